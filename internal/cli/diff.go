@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"github.com/mark3labs/swagger2mcp/internal/specdiff"
+	"github.com/spf13/cobra"
+)
+
+// DiffConfig captures the options for the diff command.
+type DiffConfig struct {
+	Old      string
+	New      string
+	Format   string
+	ExitZero bool
+	Verbose  bool
+}
+
+var diffRunner = runDiff
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two OpenAPI/Swagger documents at the service model level",
+		Long: "Build the service model for both documents and report added, removed, and changed " +
+			"endpoints, parameters, responses, and schema properties. Exits non-zero when breaking " +
+			"changes are found unless --exit-zero is set.",
+		Example: strings.TrimSpace(`  swagger2mcp diff --old v1.yaml --new v2.yaml
+  swagger2mcp diff --old v1.yaml --new https://example.com/openapi.json --format json --exit-zero`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := resolveDiffConfig(cmd)
+			if err != nil {
+				return err
+			}
+			return diffRunner(cmd.Context(), cfg, cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String("old", "", "Path or URL to the previous Swagger/OpenAPI document")
+	flags.String("new", "", "Path or URL to the updated Swagger/OpenAPI document")
+	flags.String("format", "text", "Output format (text|json)")
+	flags.Bool("exit-zero", false, "Exit with status 0 even when breaking changes are found")
+
+	return cmd
+}
+
+func resolveDiffConfig(cmd *cobra.Command) (*DiffConfig, error) {
+	flags := cmd.Flags()
+	cfg := &DiffConfig{}
+	var err error
+	if cfg.Old, err = flags.GetString("old"); err != nil {
+		return nil, err
+	}
+	if cfg.New, err = flags.GetString("new"); err != nil {
+		return nil, err
+	}
+	if cfg.Format, err = flags.GetString("format"); err != nil {
+		return nil, err
+	}
+	if cfg.ExitZero, err = flags.GetBool("exit-zero"); err != nil {
+		return nil, err
+	}
+	if cfg.Verbose, err = flags.GetBool("verbose"); err != nil {
+		return nil, err
+	}
+	cfg.Old = strings.TrimSpace(cfg.Old)
+	cfg.New = strings.TrimSpace(cfg.New)
+	cfg.Format = strings.ToLower(strings.TrimSpace(cfg.Format))
+	if cfg.Old == "" || cfg.New == "" {
+		return nil, newUsageError("diff: --old and --new are required")
+	}
+	switch cfg.Format {
+	case "", "text":
+		cfg.Format = "text"
+	case "json":
+	default:
+		return nil, newUsageError(fmt.Sprintf("diff: unsupported --format %q (allowed: text, json)", cfg.Format))
+	}
+	return cfg, nil
+}
+
+func runDiff(ctx context.Context, cfg *DiffConfig, out io.Writer) error {
+	oldSM, err := loadDiffModel(ctx, cfg.Old, cfg.Verbose)
+	if err != nil {
+		return err
+	}
+	newSM, err := loadDiffModel(ctx, cfg.New, cfg.Verbose)
+	if err != nil {
+		return err
+	}
+
+	report := specdiff.Compare(oldSM, newSM)
+	if cfg.Format == "json" {
+		err = report.WriteJSON(out)
+	} else {
+		err = report.WriteText(out)
+	}
+	if err != nil {
+		return fmt.Errorf("write diff: %w", err)
+	}
+	if report.HasBreaking() && !cfg.ExitZero {
+		return fmt.Errorf("diff: %d breaking change(s) detected", report.BreakingCount())
+	}
+	return nil
+}
+
+func loadDiffModel(ctx context.Context, input string, verbose bool) (*genspec.ServiceModel, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	doc, err := loadSpec(ctx, input, verbose)
+	if err != nil {
+		return nil, err
+	}
+	sm, err := genspec.BuildServiceModel(ctx, doc, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build model for %s: %w", input, err)
+	}
+	return sm, nil
+}
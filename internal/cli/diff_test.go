@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.yaml")
+	newPath := filepath.Join(dir, "new.yaml")
+	added := minimalSpecYAML +
+		"  /bye:\n" +
+		"    get:\n" +
+		"      summary: Bye\n" +
+		"      responses:\n" +
+		"        '200':\n" +
+		"          description: ok\n"
+	if err := os.WriteFile(oldPath, []byte(minimalSpecYAML), 0o600); err != nil {
+		t.Fatalf("write old: %v", err)
+	}
+	if err := os.WriteFile(newPath, []byte(added), 0o600); err != nil {
+		t.Fatalf("write new: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCmd()
+		root.SetOut(&out)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"diff"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	// Additions only: not breaking.
+	out, err := run("--old", oldPath, "--new", newPath)
+	if err != nil || !strings.Contains(out, "+ endpoint GET /bye added") {
+		t.Fatalf("expected addition, got err=%v out=%q", err, out)
+	}
+
+	// Reversed: the removal is breaking and fails unless --exit-zero.
+	out, err = run("--old", newPath, "--new", oldPath, "--format", "json")
+	if err == nil || !strings.Contains(err.Error(), "1 breaking") {
+		t.Fatalf("expected breaking error, got %v", err)
+	}
+	var report struct {
+		Changes []struct {
+			Kind     string
+			Breaking bool
+		}
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil || len(report.Changes) != 1 || !report.Changes[0].Breaking {
+		t.Fatalf("unexpected json output (err=%v): %s", err, out)
+	}
+	if _, err := run("--old", newPath, "--new", oldPath, "--exit-zero"); err != nil {
+		t.Fatalf("expected --exit-zero to succeed, got %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"--old", oldPath},
+		{"--old", oldPath, "--new", newPath, "--format", "yaml"},
+	} {
+		if _, err := run(args...); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: expected usage error, got %v", args, err)
+		}
+	}
+}
@@ -2,15 +2,38 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/mail"
+	"net/url"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/archive"
+	brunoemitter "github.com/mark3labs/swagger2mcp/internal/emitter/brunoemitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/fileexclude"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
 	goemitter "github.com/mark3labs/swagger2mcp/internal/emitter/goemitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/license"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	markdownemitter "github.com/mark3labs/swagger2mcp/internal/emitter/markdownemitter"
+	modelemitter "github.com/mark3labs/swagger2mcp/internal/emitter/modelemitter"
 	npmemitter "github.com/mark3labs/swagger2mcp/internal/emitter/npmemitter"
+	postmanemitter "github.com/mark3labs/swagger2mcp/internal/emitter/postmanemitter"
 	pyemitter "github.com/mark3labs/swagger2mcp/internal/emitter/pyemitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/tmploverride"
+	"github.com/mark3labs/swagger2mcp/internal/overrides"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -18,23 +41,107 @@ import (
 )
 
 // GenerateConfig captures all inputs that influence the generate command after
-// merging defaults, config file values, and CLI overrides.
+// merging defaults, config file values, SWAGGER2MCP_* environment variables,
+// and CLI overrides.
 type GenerateConfig struct {
-	Input       string
+	Input string
+	// ExtraInputs are further specs (--input repeated) whose models are
+	// merged into Input's; see genspec.MergeServiceModels.
+	ExtraInputs []string
 	Lang        string
 	Out         string
 	IncludeTags []string
 	ExcludeTags []string
-	ToolName    string
-	PackageName string
-	ConfigPath  string
-	DryRun      bool
-	Force       bool
-	Verbose     bool
+	// IncludeSchemas/ExcludeSchemas are globs matched against schema names.
+	IncludeSchemas []string
+	ExcludeSchemas []string
+	// DropExtensions removes operations whose x-* extension matches a value.
+	DropExtensions []ExtensionPredicate
+	// KeepAllServers keeps servers in spec order; otherwise public servers are
+	// listed before development (localhost, private-network) ones, which
+	// DropDevServers removes entirely.
+	KeepAllServers bool
+	DropDevServers bool
+	Overrides      string // YAML file of per-endpoint overrides applied to the built model
+	MaxSpecSize    int64  // largest spec read from a file or URL, in bytes; 0 uses genspec.DefaultMaxSpecBytes
+	ToolName       string
+	PackageName    string
+	GoVersion      string // go directive of the generated go.mod (lang go)
+	MCPLibVersion  string // mcp-go version required by the generated go.mod (lang go)
+	ESM            bool   // emit an ES module package instead of CommonJS (lang npm); on by default
+	TestRunner     string // vitest (default) or jest, what the generated tests run under (lang npm)
+	Compact        bool   // write model.json on one line instead of indented (lang model)
+	Archive        string // tar.gz or zip: pack the output into one archive at Out instead of writing a directory; none when empty
+	Layout         string // server (default) or library, which emits only the spec package (lang go, npm, python)
+	PyBuildSystem  string // setuptools (default), uv or poetry (lang python)
+	PythonVersion  string // target Python version of the generated project's tooling (lang python); also the requires-python lower bound when PythonRequires is empty
+	PythonRequires string // requires-python specifier of the generated project (lang python)
+	PyMCPVersion   string // mcp SDK version or specifier added as a dependency (lang python); none when empty
+	License        string // SPDX identifier of the LICENSE file to write (lang go, npm, python); none when empty
+	Author         string // project author in package metadata and the LICENSE copyright line (lang go, npm, python)
+	AuthorEmail    string // author's e-mail address in package metadata (lang go, npm, python)
+	ProjectVersion string // semantic version of the generated package (lang go, npm, python); the spec's version or 0.1.0 when empty
+	ConfigPath     string
+	DryRun         bool
+	Force          bool
+	// OverwriteModified lets --force replace generated files edited since the
+	// last run (per its manifest) and existing files it did not generate.
+	// Prune deletes files the last run generated that are no longer produced.
+	OverwriteModified bool
+	Prune             bool
+	PinDependencies   bool // write exact dependency versions (lang go, npm, python); go also gets its full module graph and a go.sum
+	EnableInvoke      bool // add a callEndpoint tool that calls the upstream API (lang go, npm, python)
+	EmitDocs          bool // add docs/API.md, a Markdown reference of the endpoints and schemas (lang go, npm, python)
+	EmitDockerfile    bool // add a Dockerfile, .dockerignore and Makefile docker targets (lang go, npm, python)
+	GenerateCI        bool // add a GitHub Actions workflow, .github/workflows/ci.yml (lang go, npm, python)
+	WithOTel          bool // trace tool calls with OpenTelemetry (lang go, npm, python)
+	ShardModelByTag   bool // split the embedded model into per-tag shards plus an index (lang go, npm, python)
+	Verbose           bool
+	Hooks             GenerateHooks
+	// TemplateDir holds files that replace the generated file at the same
+	// relative path, and named overrides of the built-in templates under
+	// <lang>/<name>.tmpl (lang go, npm, python); see the emitters'
+	// TemplateNames.
+	TemplateDir string
+	// ExcludeFiles are globs of generated files to leave out, matched
+	// against the relative path, or the file name when a pattern has no "/"
+	// (lang go, npm, python).
+	ExcludeFiles []string
+	// Skip names categories of generated files to leave out: tests, lint,
+	// makefile, readme, editor (lang go, npm, python).
+	Skip []string
+	// Output selects how results are reported on stdout: text (default) or
+	// json, a single machine-readable document.
+	Output string
+	// Watch regenerates whenever the input changes; remote inputs are polled
+	// every WatchInterval.
+	Watch         bool
+	WatchInterval time.Duration
+	// Verify renders in memory and compares the result with Out instead of
+	// writing it, failing when any file differs.
+	Verify bool
+	// DenyWarnings lists the IDs of warnings that fail the run ("all" denies
+	// every registered warning); AllowWarnings exempts IDs from it. Names
+	// are accepted and resolved to IDs by validate.
+	DenyWarnings  []string
+	AllowWarnings []string
+}
+
+// ExtensionPredicate is a parsed "x-key=value" pair from --drop-extension.
+type ExtensionPredicate struct {
+	Key   string
+	Value string
+}
+
+// GenerateHooks lists shell commands run around the file-writing step. They
+// are only configurable via the config file.
+type GenerateHooks struct {
+	PreGenerate  []string
+	PostGenerate []string
 }
 
 func defaultGenerateConfig() GenerateConfig {
-	return GenerateConfig{Lang: "go"}
+	return GenerateConfig{Lang: "go", ESM: true}
 }
 
 var generateRunner = runGenerate
@@ -52,20 +159,63 @@ func newGenerateCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if cfg.Watch {
+				return watchGenerate(cmd.Context(), cfg)
+			}
 			return generateRunner(cmd.Context(), cfg)
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.String("input", "", "Path or URL to the Swagger/OpenAPI document")
-	flags.String("lang", "", "Target language to emit (go|npm|python); defaults to go")
-	flags.String("out", "", "Output directory (derived from spec when omitted)")
+	flags.StringArray("input", nil, "Path or URL to the Swagger/OpenAPI document (repeatable; the specs are merged into one server)")
+	flags.String("lang", "", "Target language to emit (go|npm|python|postman|bruno|markdown|model); defaults to go")
+	flags.String("out", "", "Output directory (derived from spec when omitted); with --archive the archive file; with --lang model, - prints model.json to stdout")
 	flags.StringSlice("include-tags", nil, "Only include operations with these tags")
 	flags.StringSlice("exclude-tags", nil, "Exclude operations with these tags")
+	flags.StringSlice("include-schemas", nil, "Only embed schemas whose name matches one of these globs")
+	flags.StringSlice("exclude-schemas", nil, "Drop schemas whose name matches one of these globs")
+	flags.StringArray("drop-extension", nil, "Drop operations whose x-* extension equals a value, e.g. x-internal=true (repeatable)")
+	flags.Bool("keep-all-servers", false, "Keep servers in spec order instead of listing public servers before localhost/private ones")
+	flags.Bool("drop-dev-servers", false, "Drop localhost, private-network, .local and x-internal servers from the output")
+	flags.String("overrides", "", "YAML file mapping endpoint IDs or operationIds to summary/description/hidden/featured overrides")
+	flags.String("max-spec-size", "", "Largest spec accepted from a file or URL, e.g. 64MiB (default 32MiB)")
 	flags.String("tool-name", "", "Override the generated MCP tool name")
 	flags.String("package-name", "", "Override the generated package/module name")
+	flags.String("go-version", "", "Go version for the generated go.mod (lang go), e.g. 1.24; defaults to "+goemitter.DefaultGoVersion)
+	flags.String("mcp-lib-version", "", "github.com/mark3labs/mcp-go version for the generated go.mod (lang go); defaults to "+goemitter.DefaultMCPLibVersion)
+	flags.String("layout", "", "Project layout for go/npm/python: server (default) or library, which emits only the spec package (model + loader)")
+	flags.String("license", "", "Write a LICENSE file with this SPDX license (go/npm/python): "+strings.Join(license.IDs(), ", "))
+	flags.String("author", "", "Author for the generated README/package metadata and LICENSE (go/npm/python)")
+	flags.String("author-email", "", "Author e-mail for the generated package metadata (go/npm/python)")
+	flags.String("project-version", "", "Semantic version of the generated package (go/npm/python); defaults to the spec's info.version when it is one, else "+genspec.DefaultProjectVersion)
+	flags.String("template-dir", "", "Directory of template overrides (go/npm/python): files that replace the generated file at the same relative path, e.g. README.md, and named overrides <lang>/<name>.tmpl such as go/README.md.tmpl or python/Makefile.tmpl; unknown names are an error that lists the valid ones")
+	flags.StringSlice("skip", nil, "Leave out categories of generated files: "+strings.Join(emitter.SkipCategories, ", ")+" (go/npm/python); the remaining files drop their targets, scripts and sections")
+	flags.StringArray("exclude-file", nil, "Leave out generated files matching a glob, e.g. .pylintrc or .vscode/* (repeatable; go/npm/python); files the project needs to build cannot be excluded")
+	flags.Bool("enable-invoke", false, "Add a callEndpoint tool that sends requests to the upstream API (go/npm/python); the base URL comes from the spec's servers or API_BASE_URL")
+	flags.Bool("emit-docs", false, "Add docs/API.md, a Markdown reference with a section per tag, each endpoint's parameters and responses and a schemas appendix (go/npm/python); the same text for every language")
+	flags.Bool("emit-dockerfile", false, "Add a multi-stage Dockerfile, a .dockerignore and Makefile docker-build/docker-run targets (go/npm/python); the image serves MCP over stdio, and npm also gets a docker-compose.yml serving HTTP on port 3000")
+	flags.Bool("ci", false, "Add a GitHub Actions workflow, .github/workflows/ci.yml, that builds, lints and tests the project on every push and pull request (go/npm/python); server layout only")
+	flags.Bool("with-otel", false, "Trace each tool call with OpenTelemetry (go/npm/python): go exports over OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is set, npm and python use the OpenTelemetry API and are no-ops until an SDK is set up")
+	flags.Bool("shard-model-by-tag", false, "Split the embedded model into an index and one shard per tag under model/ (go/npm/python); the generated loader reads shards on demand")
+	flags.Bool("pin-dependencies", false, "Write exact dependency versions (go/npm/python): pinned package.json and Python requirements, a complete go.mod plus go.sum")
+	flags.String("py-build-system", "", "Packaging for lang python: setuptools (default; setup.py + requirements), uv or poetry (pyproject.toml + lock file)")
+	flags.String("python-version", "", "Target Python version (3.x) for lang python: mypy, black, ruff, pyupgrade and vermin, and requires-python when --python-requires is unset; defaults to "+pyemitter.DefaultPythonVersion)
+	flags.String("python-requires", "", "requires-python for lang python, e.g. \">=3.10\" (a bare 3.10 means >=3.10); defaults to "+pyemitter.DefaultPythonRequires)
+	flags.String("py-mcp-version", "", "Add an mcp SDK dependency to lang python projects: a version to pin (1.9.4) or a specifier (\">=1.9,<2\")")
+	flags.Bool("esm", true, "Emit an ES module package (lang npm); --esm=false emits CommonJS")
+	flags.String("test-runner", "", "Test runner of the generated tests for lang npm: vitest (default) or jest, which adds a jest.config.js for ts-jest")
+	flags.Bool("compact", false, "Write model.json on one line instead of indented (lang model)")
+	flags.String("archive", "", "Pack the output into one archive at --out instead of a directory: tar.gz or zip (any lang); entries are sorted and time-stamped 1980-01-01 so reruns are byte-identical")
 	flags.Bool("dry-run", false, "Preview planned outputs without writing files")
 	flags.Bool("force", false, "Overwrite existing output when set")
+	flags.Bool("overwrite-modified", false, "With --force, also replace generated files edited since the last run")
+	flags.Bool("prune", false, "Delete files the last run generated that are no longer produced")
+	flags.String("output", "", "Result format on stdout (text|json); json prints a single document for tooling")
+	flags.Bool("watch", false, "Regenerate (with --force) whenever the input changes")
+	flags.Duration("watch-interval", defaultGenerateWatchInterval, "Polling interval used by --watch for remote inputs")
+	flags.Bool("verify", false, "Check that --out matches what this spec and these options generate, without writing; exits non-zero on any difference")
+	flags.StringSlice("deny-warning", nil, "Fail the run on these warnings: IDs such as SW2MCP-W001 or names such as unresolved-ref, or all (repeatable)")
+	flags.StringSlice("allow-warning", nil, "Exempt these warning IDs or names from --deny-warning (repeatable)")
 
 	return cmd
 }
@@ -78,6 +228,19 @@ func resolveGenerateConfig(cmd *cobra.Command) (*GenerateConfig, error) {
 		return nil, err
 	}
 	configPath = strings.TrimSpace(configPath)
+	discovered := false
+	if configPath == "" {
+		noConfig, err := cmd.Flags().GetBool("no-config")
+		if err != nil {
+			return nil, err
+		}
+		if !noConfig {
+			if wd, err := os.Getwd(); err == nil {
+				configPath = discoverConfigFile(wd)
+				discovered = configPath != ""
+			}
+		}
+	}
 	if configPath != "" {
 		cfg.ConfigPath = configPath
 		if err := applyGenerateConfigFromFile(&cfg, configPath); err != nil {
@@ -85,9 +248,15 @@ func resolveGenerateConfig(cmd *cobra.Command) (*GenerateConfig, error) {
 		}
 	}
 
+	if err := applyGenerateConfigFromEnv(&cfg, os.LookupEnv); err != nil {
+		return nil, err
+	}
 	if err := applyGenerateFlagOverrides(cmd.Flags(), &cfg); err != nil {
 		return nil, err
 	}
+	if discovered && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "[INFO] using config file %s\n", configPath)
+	}
 
 	cfg.normalize()
 	if err := cfg.validate(); err != nil {
@@ -98,13 +267,6 @@ func resolveGenerateConfig(cmd *cobra.Command) (*GenerateConfig, error) {
 }
 
 func applyGenerateFlagOverrides(flags *pflag.FlagSet, cfg *GenerateConfig) error {
-	if flags.Changed("input") {
-		value, err := flags.GetString("input")
-		if err != nil {
-			return err
-		}
-		cfg.Input = strings.TrimSpace(value)
-	}
 	if flags.Changed("lang") {
 		value, err := flags.GetString("lang")
 		if err != nil {
@@ -119,33 +281,197 @@ func applyGenerateFlagOverrides(flags *pflag.FlagSet, cfg *GenerateConfig) error
 		}
 		cfg.Out = strings.TrimSpace(value)
 	}
-	if flags.Changed("include-tags") {
-		value, err := flags.GetStringSlice("include-tags")
+	if err := applyModelFlagOverrides(flags, cfg); err != nil {
+		return err
+	}
+	if flags.Changed("tool-name") {
+		value, err := flags.GetString("tool-name")
 		if err != nil {
 			return err
 		}
-		cfg.IncludeTags = sanitizeTags(value)
+		cfg.ToolName = strings.TrimSpace(value)
 	}
-	if flags.Changed("exclude-tags") {
-		value, err := flags.GetStringSlice("exclude-tags")
+	if flags.Changed("package-name") {
+		value, err := flags.GetString("package-name")
 		if err != nil {
 			return err
 		}
-		cfg.ExcludeTags = sanitizeTags(value)
+		cfg.PackageName = strings.TrimSpace(value)
 	}
-	if flags.Changed("tool-name") {
-		value, err := flags.GetString("tool-name")
+	if flags.Changed("go-version") {
+		value, err := flags.GetString("go-version")
 		if err != nil {
 			return err
 		}
-		cfg.ToolName = strings.TrimSpace(value)
+		cfg.GoVersion = strings.TrimSpace(value)
 	}
-	if flags.Changed("package-name") {
-		value, err := flags.GetString("package-name")
+	if flags.Changed("mcp-lib-version") {
+		value, err := flags.GetString("mcp-lib-version")
 		if err != nil {
 			return err
 		}
-		cfg.PackageName = strings.TrimSpace(value)
+		cfg.MCPLibVersion = strings.TrimSpace(value)
+	}
+	if flags.Changed("layout") {
+		value, err := flags.GetString("layout")
+		if err != nil {
+			return err
+		}
+		cfg.Layout = strings.TrimSpace(value)
+	}
+	if flags.Changed("license") {
+		value, err := flags.GetString("license")
+		if err != nil {
+			return err
+		}
+		cfg.License = strings.TrimSpace(value)
+	}
+	if flags.Changed("author") {
+		value, err := flags.GetString("author")
+		if err != nil {
+			return err
+		}
+		cfg.Author = strings.TrimSpace(value)
+	}
+	if flags.Changed("author-email") {
+		value, err := flags.GetString("author-email")
+		if err != nil {
+			return err
+		}
+		cfg.AuthorEmail = strings.TrimSpace(value)
+	}
+	if flags.Changed("project-version") {
+		value, err := flags.GetString("project-version")
+		if err != nil {
+			return err
+		}
+		cfg.ProjectVersion = strings.TrimSpace(value)
+	}
+	if flags.Changed("template-dir") {
+		value, err := flags.GetString("template-dir")
+		if err != nil {
+			return err
+		}
+		cfg.TemplateDir = strings.TrimSpace(value)
+	}
+	if flags.Changed("exclude-file") {
+		value, err := flags.GetStringArray("exclude-file")
+		if err != nil {
+			return err
+		}
+		cfg.ExcludeFiles = sanitizeTags(value)
+	}
+	if flags.Changed("skip") {
+		value, err := flags.GetStringSlice("skip")
+		if err != nil {
+			return err
+		}
+		cfg.Skip = sanitizeTags(value)
+	}
+	if flags.Changed("enable-invoke") {
+		value, err := flags.GetBool("enable-invoke")
+		if err != nil {
+			return err
+		}
+		cfg.EnableInvoke = value
+	}
+	if flags.Changed("emit-docs") {
+		value, err := flags.GetBool("emit-docs")
+		if err != nil {
+			return err
+		}
+		cfg.EmitDocs = value
+	}
+	if flags.Changed("emit-dockerfile") {
+		value, err := flags.GetBool("emit-dockerfile")
+		if err != nil {
+			return err
+		}
+		cfg.EmitDockerfile = value
+	}
+	if flags.Changed("ci") {
+		value, err := flags.GetBool("ci")
+		if err != nil {
+			return err
+		}
+		cfg.GenerateCI = value
+	}
+	if flags.Changed("with-otel") {
+		value, err := flags.GetBool("with-otel")
+		if err != nil {
+			return err
+		}
+		cfg.WithOTel = value
+	}
+	if flags.Changed("shard-model-by-tag") {
+		value, err := flags.GetBool("shard-model-by-tag")
+		if err != nil {
+			return err
+		}
+		cfg.ShardModelByTag = value
+	}
+	if flags.Changed("pin-dependencies") {
+		value, err := flags.GetBool("pin-dependencies")
+		if err != nil {
+			return err
+		}
+		cfg.PinDependencies = value
+	}
+	if flags.Changed("py-build-system") {
+		value, err := flags.GetString("py-build-system")
+		if err != nil {
+			return err
+		}
+		cfg.PyBuildSystem = strings.TrimSpace(value)
+	}
+	if flags.Changed("python-version") {
+		value, err := flags.GetString("python-version")
+		if err != nil {
+			return err
+		}
+		cfg.PythonVersion = strings.TrimSpace(value)
+	}
+	if flags.Changed("python-requires") {
+		value, err := flags.GetString("python-requires")
+		if err != nil {
+			return err
+		}
+		cfg.PythonRequires = strings.TrimSpace(value)
+	}
+	if flags.Changed("py-mcp-version") {
+		value, err := flags.GetString("py-mcp-version")
+		if err != nil {
+			return err
+		}
+		cfg.PyMCPVersion = strings.TrimSpace(value)
+	}
+	if flags.Changed("esm") {
+		value, err := flags.GetBool("esm")
+		if err != nil {
+			return err
+		}
+		cfg.ESM = value
+	}
+	if flags.Changed("test-runner") {
+		value, err := flags.GetString("test-runner")
+		if err != nil {
+			return err
+		}
+		cfg.TestRunner = strings.TrimSpace(value)
+	}
+	if flags.Changed("compact") {
+		value, err := flags.GetBool("compact")
+		if err != nil {
+			return err
+		}
+		cfg.Compact = value
+	}
+	if flags.Changed("archive") {
+		value, err := flags.GetString("archive")
+		if err != nil {
+			return err
+		}
+		cfg.Archive = strings.TrimSpace(value)
 	}
 	if flags.Changed("dry-run") {
 		value, err := flags.GetBool("dry-run")
@@ -161,6 +487,41 @@ func applyGenerateFlagOverrides(flags *pflag.FlagSet, cfg *GenerateConfig) error
 		}
 		cfg.Force = value
 	}
+	if flags.Changed("overwrite-modified") {
+		value, err := flags.GetBool("overwrite-modified")
+		if err != nil {
+			return err
+		}
+		cfg.OverwriteModified = value
+	}
+	if flags.Changed("prune") {
+		value, err := flags.GetBool("prune")
+		if err != nil {
+			return err
+		}
+		cfg.Prune = value
+	}
+	if flags.Changed("output") {
+		value, err := flags.GetString("output")
+		if err != nil {
+			return err
+		}
+		cfg.Output = strings.TrimSpace(value)
+	}
+	if flags.Changed("deny-warning") {
+		value, err := flags.GetStringSlice("deny-warning")
+		if err != nil {
+			return err
+		}
+		cfg.DenyWarnings = sanitizeTags(value)
+	}
+	if flags.Changed("allow-warning") {
+		value, err := flags.GetStringSlice("allow-warning")
+		if err != nil {
+			return err
+		}
+		cfg.AllowWarnings = sanitizeTags(value)
+	}
 	if flags.Changed("verbose") {
 		value, err := flags.GetBool("verbose")
 		if err != nil {
@@ -168,175 +529,1249 @@ func applyGenerateFlagOverrides(flags *pflag.FlagSet, cfg *GenerateConfig) error
 		}
 		cfg.Verbose = value
 	}
+	watch, err := flags.GetBool("watch")
+	if err != nil {
+		return err
+	}
+	cfg.Watch = watch
+	interval, err := flags.GetDuration("watch-interval")
+	if err != nil {
+		return err
+	}
+	cfg.WatchInterval = interval
+	verify, err := flags.GetBool("verify")
+	if err != nil {
+		return err
+	}
+	cfg.Verify = verify
 
 	return nil
 }
 
+// applyModelFlagOverrides applies the flags that choose the input and how the
+// model is built from it; refresh-model shares them with generate.
+func applyModelFlagOverrides(flags *pflag.FlagSet, cfg *GenerateConfig) error {
+	if flags.Changed("input") {
+		values, err := flags.GetStringArray("input")
+		if err != nil {
+			return err
+		}
+		cfg.Input, cfg.ExtraInputs = "", nil
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			if cfg.Input == "" {
+				cfg.Input = v
+			} else {
+				cfg.ExtraInputs = append(cfg.ExtraInputs, v)
+			}
+		}
+	}
+	if flags.Changed("include-tags") {
+		value, err := flags.GetStringSlice("include-tags")
+		if err != nil {
+			return err
+		}
+		cfg.IncludeTags = sanitizeTags(value)
+	}
+	if flags.Changed("exclude-tags") {
+		value, err := flags.GetStringSlice("exclude-tags")
+		if err != nil {
+			return err
+		}
+		cfg.ExcludeTags = sanitizeTags(value)
+	}
+	if flags.Changed("include-schemas") {
+		value, err := flags.GetStringSlice("include-schemas")
+		if err != nil {
+			return err
+		}
+		cfg.IncludeSchemas = sanitizeTags(value)
+	}
+	if flags.Changed("exclude-schemas") {
+		value, err := flags.GetStringSlice("exclude-schemas")
+		if err != nil {
+			return err
+		}
+		cfg.ExcludeSchemas = sanitizeTags(value)
+	}
+	if flags.Changed("drop-extension") {
+		value, err := flags.GetStringArray("drop-extension")
+		if err != nil {
+			return err
+		}
+		preds, err := parseExtensionPredicates(value)
+		if err != nil {
+			return err
+		}
+		cfg.DropExtensions = preds
+	}
+	if flags.Changed("keep-all-servers") {
+		value, err := flags.GetBool("keep-all-servers")
+		if err != nil {
+			return err
+		}
+		cfg.KeepAllServers = value
+	}
+	if flags.Changed("drop-dev-servers") {
+		value, err := flags.GetBool("drop-dev-servers")
+		if err != nil {
+			return err
+		}
+		cfg.DropDevServers = value
+	}
+	if flags.Changed("overrides") {
+		value, err := flags.GetString("overrides")
+		if err != nil {
+			return err
+		}
+		cfg.Overrides = strings.TrimSpace(value)
+	}
+	if flags.Changed("max-spec-size") {
+		value, err := flags.GetString("max-spec-size")
+		if err != nil {
+			return err
+		}
+		size, err := parseByteSize(value)
+		if err != nil {
+			return newUsageError(fmt.Sprintf("generate: invalid --max-spec-size: %v", err))
+		}
+		cfg.MaxSpecSize = size
+	}
+	return nil
+}
+
 func (c *GenerateConfig) normalize() {
 	c.Input = strings.TrimSpace(c.Input)
+	c.ExtraInputs = sanitizeTags(c.ExtraInputs)
 	c.Lang = strings.ToLower(strings.TrimSpace(c.Lang))
 	c.Out = strings.TrimSpace(c.Out)
+	c.Overrides = strings.TrimSpace(c.Overrides)
 	c.ToolName = strings.TrimSpace(c.ToolName)
 	c.PackageName = strings.TrimSpace(c.PackageName)
+	c.GoVersion = strings.TrimSpace(c.GoVersion)
+	c.MCPLibVersion = strings.TrimSpace(c.MCPLibVersion)
+	c.Output = strings.ToLower(strings.TrimSpace(c.Output))
+	c.Layout = strings.ToLower(strings.TrimSpace(c.Layout))
+	c.PyBuildSystem = strings.ToLower(strings.TrimSpace(c.PyBuildSystem))
+	c.TestRunner = strings.ToLower(strings.TrimSpace(c.TestRunner))
+	c.Archive = strings.ToLower(strings.TrimSpace(c.Archive))
+	c.PythonVersion = strings.TrimSpace(c.PythonVersion)
+	c.PythonRequires = strings.TrimSpace(c.PythonRequires)
+	c.PyMCPVersion = strings.TrimSpace(c.PyMCPVersion)
+	c.License = strings.TrimSpace(c.License)
+	c.Author = strings.TrimSpace(c.Author)
+	c.AuthorEmail = strings.TrimSpace(c.AuthorEmail)
+	c.ProjectVersion = strings.TrimSpace(c.ProjectVersion)
 	c.IncludeTags = sanitizeTags(c.IncludeTags)
 	c.ExcludeTags = sanitizeTags(c.ExcludeTags)
+	c.IncludeSchemas = sanitizeTags(c.IncludeSchemas)
+	c.ExcludeSchemas = sanitizeTags(c.ExcludeSchemas)
+}
+
+// inputs returns Input followed by ExtraInputs.
+func (c *GenerateConfig) inputs() []string {
+	return append([]string{c.Input}, c.ExtraInputs...)
 }
 
 func (c *GenerateConfig) validate() error {
 	if c.Input == "" {
-		return newUsageError("generate: --input is required (set via flag or config file)")
+		return newUsageError("generate: --input is required (set via flag, SWAGGER2MCP_INPUT or config file)")
 	}
 
 	switch c.Lang {
-	case "", "go", "npm", "python":
+	case "", "go", "npm", "python", "postman", "bruno", "markdown", "model":
 		if c.Lang == "" {
 			c.Lang = "go"
 		}
 	default:
-		return newUsageError(fmt.Sprintf("generate: unsupported --lang %q (allowed: go, npm, python)", c.Lang))
+		return newUsageError(fmt.Sprintf("generate: unsupported --lang %q (allowed: go, npm, python, postman, bruno, markdown, model)", c.Lang))
+	}
+
+	switch c.Output {
+	case "", "text", "json":
+		if c.Output == "" {
+			c.Output = "text"
+		}
+	default:
+		return newUsageError(fmt.Sprintf("generate: unsupported --output %q (allowed: text, json)", c.Output))
+	}
+
+	switch c.Layout {
+	case "", "server", "library":
+		if c.Layout == "" {
+			c.Layout = "server"
+		}
+	default:
+		return newUsageError(fmt.Sprintf("generate: unsupported --layout %q (allowed: server, library)", c.Layout))
+	}
+
+	switch c.PyBuildSystem {
+	case "", pyemitter.BuildToolSetuptools, pyemitter.BuildToolUV, pyemitter.BuildToolPoetry:
+		if c.PyBuildSystem == "" {
+			c.PyBuildSystem = pyemitter.BuildToolSetuptools
+		}
+	default:
+		return newUsageError(fmt.Sprintf("generate: unsupported --py-build-system %q (allowed: setuptools, uv, poetry)", c.PyBuildSystem))
+	}
+
+	switch c.TestRunner {
+	case "", npmemitter.TestRunnerVitest, npmemitter.TestRunnerJest:
+		if c.TestRunner == "" {
+			c.TestRunner = npmemitter.TestRunnerVitest
+		}
+	default:
+		return newUsageError(fmt.Sprintf("generate: unsupported --test-runner %q (allowed: vitest, jest)", c.TestRunner))
+	}
+
+	switch c.Archive {
+	case "", archive.TarGz, archive.Zip:
+	default:
+		return newUsageError(fmt.Sprintf("generate: unsupported --archive %q (allowed: %s)", c.Archive, strings.Join(archive.Formats, ", ")))
+	}
+
+	if c.License != "" {
+		id, err := license.Normalize(c.License)
+		if err != nil {
+			return newUsageError(fmt.Sprintf("generate: --license: %v", err))
+		}
+		c.License = id
+	}
+
+	// Author values are written verbatim into quoted TOML, Python and JSON
+	// strings, so characters that would need escaping are rejected.
+	if strings.ContainsAny(c.Author, "\"\\<>\n\r") {
+		return newUsageError(fmt.Sprintf("generate: --author %q must not contain quotes, backslashes, angle brackets or line breaks", c.Author))
+	}
+	if c.AuthorEmail != "" {
+		if addr, err := mail.ParseAddress(c.AuthorEmail); err != nil || addr.Address != c.AuthorEmail {
+			return newUsageError(fmt.Sprintf("generate: invalid --author-email %q (expected e.g. jane@example.com)", c.AuthorEmail))
+		}
+	}
+
+	if c.ProjectVersion != "" && !genspec.IsSemver(c.ProjectVersion) {
+		return newUsageError(fmt.Sprintf("generate: invalid --project-version %q (expected a semantic version, e.g. 1.2.3)", c.ProjectVersion))
 	}
 
 	overlap := intersect(c.IncludeTags, c.ExcludeTags)
 	if len(overlap) > 0 {
 		return newUsageError(fmt.Sprintf("generate: include/exclude tags overlap: %s", strings.Join(overlap, ", ")))
 	}
+	// An archive is written anew each run, so there is no output directory
+	// to compare, watch or print to.
+	if c.Archive != "" {
+		switch {
+		case c.Verify:
+			return newUsageError("generate: --archive and --verify are mutually exclusive")
+		case c.Watch:
+			return newUsageError("generate: --archive and --watch are mutually exclusive")
+		case c.Out == "-":
+			return newUsageError("generate: --archive needs a file for --out, not -")
+		}
+	}
+	if c.Watch && len(c.ExtraInputs) > 0 {
+		for _, input := range c.inputs() {
+			if isRemoteInput(input) {
+				return newUsageError(fmt.Sprintf("generate: --watch with several --input specs needs local files, not %s", input))
+			}
+		}
+	}
+	if c.Verify {
+		if c.Watch {
+			return newUsageError("generate: --verify and --watch are mutually exclusive")
+		}
+		// Verification only renders; nothing is written and no hooks run.
+		c.DryRun = true
+	}
+	if c.KeepAllServers && c.DropDevServers {
+		return newUsageError("generate: --keep-all-servers and --drop-dev-servers are mutually exclusive")
+	}
+	// --out - prints model.json and writes no files, so nothing can be
+	// planned, verified, watched or reported next to it on stdout.
+	if c.Out == "-" {
+		switch {
+		case c.Lang != "model":
+			return newUsageError(fmt.Sprintf("generate: --out - is only supported with --lang model, not %s", c.Lang))
+		case c.DryRun:
+			return newUsageError("generate: --out - and --dry-run/--verify are mutually exclusive")
+		case c.Watch:
+			return newUsageError("generate: --out - and --watch are mutually exclusive")
+		case c.Output == "json":
+			return newUsageError("generate: --out - and --output json are mutually exclusive")
+		}
+	}
+	if c.Lang == "go" {
+		_, mcpLibVersion, err := goemitter.ResolveVersions(c.GoVersion, c.MCPLibVersion)
+		if err != nil {
+			return newUsageError("generate: " + strings.TrimPrefix(err.Error(), "goemitter: "))
+		}
+		if c.PinDependencies && c.Layout != "library" && mcpLibVersion != goemitter.DefaultMCPLibVersion {
+			return newUsageError(fmt.Sprintf("generate: --pin-dependencies pins mcp-go %s; drop --mcp-lib-version %s or --pin-dependencies", goemitter.DefaultMCPLibVersion, mcpLibVersion))
+		}
+		if c.PinDependencies && c.Layout != "library" && c.WithOTel {
+			return newUsageError("generate: --pin-dependencies does not cover the OpenTelemetry modules of --with-otel; drop one of them")
+		}
+	}
+	if c.Lang == "python" {
+		if _, _, err := pyemitter.ResolvePythonVersion(c.PythonVersion, c.PythonRequires); err != nil {
+			return newUsageError("generate: " + strings.TrimPrefix(err.Error(), "pyemitter: "))
+		}
+		if _, _, err := pyemitter.ResolveVersions(c.PythonRequires, c.PyMCPVersion); err != nil {
+			return newUsageError("generate: " + strings.TrimPrefix(err.Error(), "pyemitter: "))
+		}
+	}
+	for _, pattern := range append(append([]string(nil), c.IncludeSchemas...), c.ExcludeSchemas...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return newUsageError(fmt.Sprintf("generate: invalid schema pattern %q: %v", pattern, err))
+		}
+	}
+	if err := fileexclude.Validate(c.ExcludeFiles); err != nil {
+		return newUsageError("generate: " + err.Error())
+	}
+	if _, err := emitter.ParseSkip(c.Skip); err != nil {
+		return newUsageError("generate: --skip: " + err.Error())
+	}
+	if c.TemplateDir != "" {
+		if err := checkTemplateDir(c.TemplateDir); err != nil {
+			return newUsageError("generate: --template-dir: " + err.Error())
+		}
+	}
+	var err error
+	if c.DenyWarnings, err = resolveWarningIDs("--deny-warning", c.DenyWarnings); err != nil {
+		return err
+	}
+	if c.AllowWarnings, err = resolveWarningIDs("--allow-warning", c.AllowWarnings); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// templateNames lists the named templates of each language --template-dir
+// can override.
+var templateNames = map[string]func() []string{
+	"go":     goemitter.TemplateNames,
+	"npm":    npmemitter.TemplateNames,
+	"python": pyemitter.TemplateNames,
+}
+
+// checkTemplateDir reports an error unless the go/, npm/ and python/
+// directories of dir hold only <name>.tmpl files with registered names, so a
+// typo fails before anything is generated rather than being silently ignored,
+// whichever language this run generates. Other files are same-path overrides
+// and are not checked.
+func checkTemplateDir(dir string) error {
+	for _, lang := range []string{"go", "npm", "python"} {
+		if err := tmploverride.CheckNamed(dir, lang, templateNames[lang]()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveWarningIDs maps warning IDs and names to registered IDs, expanding
+// "all" to every registered warning.
+func resolveWarningIDs(flag string, values []string) ([]string, error) {
+	var ids []string
+	for _, v := range values {
+		if strings.EqualFold(v, "all") {
+			for _, k := range genspec.WarningKinds() {
+				ids = append(ids, string(k.ID))
+			}
+			continue
+		}
+		k, ok := genspec.LookupWarning(v)
+		if !ok {
+			return nil, newUsageError(fmt.Sprintf("generate: unknown warning %q in %s (expected an ID such as %s, a warning name, or all)", v, flag, genspec.WarnUnresolvedRef))
+		}
+		ids = append(ids, string(k.ID))
+	}
+	return sanitizeTags(ids), nil
+}
+
+// generateModels memoizes built models across runGenerate calls in this
+// process, so a watch iteration whose spec content and filters are unchanged
+// does not rebuild the model. Each call gets its own copy to modify; only
+// the most recently used models are kept, so memory stays bounded however
+// long --watch runs.
+var generateModels = genspec.NewModelCache()
+
+// buildGenerateModel loads cfg's inputs and builds the service model with
+// cfg's filters and overrides applied. With several inputs each spec's model
+// is built on its own and the models are merged; the returned LoadResult then
+// holds the first document and every spec's files and warnings.
+func buildGenerateModel(ctx context.Context, cfg *GenerateConfig) (*genspec.LoadResult, *genspec.ServiceModel, error) {
+	var endpointOverrides overrides.File
+	if cfg.Overrides != "" {
+		f, err := overrides.Load(cfg.Overrides)
+		if err != nil {
+			return nil, nil, newUsageError(fmt.Sprintf("generate: %v", err))
+		}
+		endpointOverrides = f
+	}
+
+	// 1) Load the spec (file or http/https URL) with validation and conversion
+	var loadOpts []genspec.Option
+	if cfg.MaxSpecSize > 0 {
+		loadOpts = append(loadOpts, genspec.WithMaxSpecBytes(cfg.MaxSpecSize))
+	}
+	var results []*genspec.LoadResult
+	for _, input := range cfg.inputs() {
+		loaded, err := genspec.LoadDetailed(ctx, input, loadOpts...)
+		if err != nil {
+			err = mapSpecError(err)
+			if len(cfg.ExtraInputs) > 0 {
+				err = fmt.Errorf("%s: %w", input, err)
+			}
+			return nil, nil, err
+		}
+		results = append(results, loaded)
+	}
+
+	// 2) Build the internal model (IM) with tag filters
+	buildOpts := []genspec.BuildOption{
+		genspec.WithIncludeTags(cfg.IncludeTags),
+		genspec.WithExcludeTags(cfg.ExcludeTags),
+		genspec.WithIncludeSchemaPatterns(cfg.IncludeSchemas),
+		genspec.WithExcludeSchemaPatterns(cfg.ExcludeSchemas),
+		genspec.WithKeepAllServers(cfg.KeepAllServers),
+		genspec.WithDropDevServers(cfg.DropDevServers),
+	}
+	for _, p := range cfg.DropExtensions {
+		buildOpts = append(buildOpts, genspec.WithExtensionFilter(p.Key, p.Value))
+	}
+	var models []*genspec.ServiceModel
+	for _, loaded := range results {
+		sm, err := generateModels.Build(
+			ctx,
+			loaded.Doc,
+			nil, // v2Raw - we'll add this later when we detect v2 conversion
+			buildOpts...,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("build model: %w", err)
+		}
+		models = append(models, sm)
+	}
+	loaded, sm := results[0], models[0]
+	if len(models) > 1 {
+		merged, err := genspec.MergeServiceModels(models...)
+		if err != nil {
+			return nil, nil, newUsageError(fmt.Sprintf("generate: %v", err))
+		}
+		sm = merged
+		loaded = &genspec.LoadResult{Doc: results[0].Doc}
+		for _, r := range results {
+			loaded.Warnings = append(loaded.Warnings, r.Warnings...)
+			loaded.Files = append(loaded.Files, r.Files...)
+		}
+	}
+	overrides.Apply(sm, endpointOverrides)
+	return loaded, sm, nil
+}
+
+func runGenerate(ctx context.Context, cfg *GenerateConfig) error {
+	loaded, sm, err := buildGenerateModel(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	// In JSON mode warnings go into the report and stdout carries nothing
+	// else, so hook output is sent to stderr.
+	jsonOutput := cfg.Output == "json"
+	report := &generateReport{collect: jsonOutput, deny: deniedWarnings(cfg)}
+	hookStdout := io.Writer(os.Stdout)
+	if jsonOutput {
+		hookStdout = os.Stderr
+	}
+	// Loader warnings are only listed with --verbose, but a denied one fails
+	// the run either way.
+	for _, w := range loaded.Warnings {
+		if cfg.Verbose {
+			report.warn(w)
+		} else if report.deny[w.ID] {
+			report.denied = append(report.denied, w)
+		}
+	}
+	for _, w := range sm.Warnings {
+		report.warn(w)
+	}
+
+	// 3) Derive sensible defaults for names and out dir when omitted
+	outDir := strings.TrimSpace(cfg.Out)
+	resolvedToolName := strings.TrimSpace(cfg.ToolName)
+	if resolvedToolName == "" {
+		resolvedToolName = deriveToolName(sm.Title)
+		if resolvedToolName == "" {
+			resolvedToolName = "mcp-tool"
+		}
+	} else {
+		resolvedToolName = sanitizeToolName(resolvedToolName)
+		if resolvedToolName == "" { // after sanitization
+			resolvedToolName = "mcp-tool"
+		}
+	}
+	if outDir == "" {
+		outDir = resolvedToolName
+		if cfg.Archive != "" {
+			outDir += archive.Ext(cfg.Archive)
+		}
+	}
+
+	// Ensure outDir is absolute only for display; emitters handle actual creation/writes
+	absOut := outDir
+	if ap, err := filepath.Abs(outDir); err == nil {
+		absOut = ap
+	}
+
+	// Output generated from the same model before (per its manifest) is left
+	// alone, hooks included, unless --force is given.
+	report.Config = generateReportConfig{
+		Input:             cfg.Input,
+		ExtraInputs:       cfg.ExtraInputs,
+		Lang:              cfg.Lang,
+		Out:               absOut,
+		IncludeTags:       cfg.IncludeTags,
+		ExcludeTags:       cfg.ExcludeTags,
+		IncludeSchemas:    cfg.IncludeSchemas,
+		ExcludeSchemas:    cfg.ExcludeSchemas,
+		Overrides:         cfg.Overrides,
+		MaxSpecSize:       cfg.MaxSpecSize,
+		DryRun:            cfg.DryRun,
+		Force:             cfg.Force,
+		OverwriteModified: cfg.OverwriteModified,
+		Prune:             cfg.Prune,
+	}
+	report.ToolName = resolvedToolName
+	// A denied spec or model warning fails the run before anything is written.
+	if err := report.deniedError(); err != nil {
+		if jsonOutput {
+			if werr := report.writeJSON(os.Stdout); werr != nil {
+				return werr
+			}
+		}
+		return err
+	}
+	// model.json alone goes to stdout, without a manifest or hooks.
+	if cfg.Out == "-" {
+		data, err := modelemitter.Marshal(sm, cfg.Compact)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stdout.Write(data); err != nil {
+			return err
+		}
+		return report.deniedError()
+	}
+	// With --archive the emitter and the hooks work in a fresh staging
+	// directory, which is packed into the archive at the end.
+	archivePath := ""
+	if cfg.Archive != "" {
+		archivePath = absOut
+		if err := checkArchivePath(archivePath, cfg.Force || cfg.DryRun); err != nil {
+			return wrapOutputError(err, archivePath)
+		}
+		staging, err := os.MkdirTemp("", "swagger2mcp-archive-")
+		if err != nil {
+			return wrapOutputError(err, archivePath)
+		}
+		defer os.RemoveAll(staging)
+		outDir, absOut = staging, staging
+	}
+	optionsHash := generateOptionsHash(cfg)
+	key := manifest.Key{ToolName: resolvedToolName, Lang: cfg.Lang, SpecHash: manifest.SpecHash(sm), OptionsHash: optionsHash, Generator: Version}
+	if !cfg.DryRun && !cfg.Force && manifest.UpToDate(absOut, key) {
+		fmt.Fprintf(os.Stderr, "[INFO] %s is up to date with the spec and options; skipping (use --force to regenerate)\n", absOut)
+		if jsonOutput {
+			report.Skipped = true
+			return report.writeJSON(os.Stdout)
+		}
+		return nil
+	}
+
+	// Pre-generate hooks run before any file is written; a failure aborts.
+	hookEnv := []string{
+		"SWAGGER2MCP_OUT_DIR=" + absOut,
+		"SWAGGER2MCP_TOOL_NAME=" + resolvedToolName,
+		"SWAGGER2MCP_LANG=" + cfg.Lang,
+	}
+	force := cfg.Force
+	if !cfg.DryRun && len(cfg.Hooks.PreGenerate) > 0 {
+		// The emptiness check happens here, before the hooks write anything,
+		// so files they create don't trip the emitters' own check.
+		if !cfg.Force {
+			if err := ensureEmptyOutputDir(absOut); err != nil {
+				return wrapOutputError(err, absOut)
+			}
+		}
+		_, statErr := os.Stat(absOut)
+		created := os.IsNotExist(statErr)
+		if err := os.MkdirAll(absOut, 0o755); err != nil {
+			return wrapOutputError(fmt.Errorf("mkdir: %w", err), absOut)
+		}
+		if err := applyHooks(ctx, "preGenerate", cfg.Hooks.PreGenerate, absOut, hookEnv, hookStdout); err != nil {
+			if created {
+				_ = os.RemoveAll(absOut)
+			}
+			return newUsageError(fmt.Sprintf("generate: %v", err))
+		}
+		force = true
+	}
+
+	// 4) Emit for the chosen language
+	switch cfg.Lang {
+	case "go":
+		res, err := goemitter.Emit(ctx, sm, goemitter.Options{
+			OutDir:              outDir,
+			ToolName:            resolvedToolName,
+			ModuleName:          strings.TrimSpace(cfg.PackageName),
+			GoVersion:           cfg.GoVersion,
+			MCPLibVersion:       cfg.MCPLibVersion,
+			GenerateLintConfig:  true,
+			License:             cfg.License,
+			Author:              cfg.Author,
+			AuthorEmail:         cfg.AuthorEmail,
+			ProjectVersion:      cfg.ProjectVersion,
+			PinDependencies:     cfg.PinDependencies,
+			EnableInvoke:        cfg.EnableInvoke,
+			EmitDocs:            cfg.EmitDocs,
+			EmitDockerfile:      cfg.EmitDockerfile,
+			GenerateCI:          cfg.GenerateCI,
+			WithOTel:            cfg.WithOTel,
+			ShardModelByTag:     cfg.ShardModelByTag,
+			TemplateOverrideDir: cfg.TemplateDir,
+			ExcludeFiles:        cfg.ExcludeFiles,
+			Skip:                cfg.Skip,
+			Provenance:          generateProvenance(cfg),
+			Library:             cfg.Layout == "library",
+			Force:               force,
+			OverwriteModified:   cfg.OverwriteModified,
+			Prune:               cfg.Prune,
+			DryRun:              cfg.DryRun,
+			Verbose:             cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName, report.PackageName = res.ToolName, res.ModuleName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	case "npm":
+		res, err := npmemitter.Emit(ctx, sm, npmemitter.Options{
+			OutDir:              outDir,
+			ToolName:            resolvedToolName,
+			PackageName:         strings.TrimSpace(cfg.PackageName),
+			ESM:                 cfg.ESM,
+			TestRunner:          cfg.TestRunner,
+			License:             cfg.License,
+			Author:              cfg.Author,
+			AuthorEmail:         cfg.AuthorEmail,
+			ProjectVersion:      cfg.ProjectVersion,
+			PinDependencies:     cfg.PinDependencies,
+			EnableInvoke:        cfg.EnableInvoke,
+			EmitDocs:            cfg.EmitDocs,
+			EmitDockerfile:      cfg.EmitDockerfile,
+			GenerateCI:          cfg.GenerateCI,
+			WithOTel:            cfg.WithOTel,
+			ShardModelByTag:     cfg.ShardModelByTag,
+			TemplateOverrideDir: cfg.TemplateDir,
+			ExcludeFiles:        cfg.ExcludeFiles,
+			Skip:                cfg.Skip,
+			Provenance:          generateProvenance(cfg),
+			Library:             cfg.Layout == "library",
+			Force:               force,
+			OverwriteModified:   cfg.OverwriteModified,
+			Prune:               cfg.Prune,
+			DryRun:              cfg.DryRun,
+			Verbose:             cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName, report.PackageName = res.ToolName, res.PackageName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	case "python":
+		res, err := pyemitter.Emit(ctx, sm, pyemitter.Options{
+			OutDir:              outDir,
+			ToolName:            resolvedToolName,
+			PackageName:         strings.TrimSpace(cfg.PackageName),
+			Library:             cfg.Layout == "library",
+			BuildTool:           cfg.PyBuildSystem,
+			PythonVersion:       cfg.PythonVersion,
+			PythonRequires:      cfg.PythonRequires,
+			MCPSDKVersion:       cfg.PyMCPVersion,
+			License:             cfg.License,
+			Author:              cfg.Author,
+			AuthorEmail:         cfg.AuthorEmail,
+			ProjectVersion:      cfg.ProjectVersion,
+			PinDependencies:     cfg.PinDependencies,
+			EnableInvoke:        cfg.EnableInvoke,
+			EmitDocs:            cfg.EmitDocs,
+			EmitDockerfile:      cfg.EmitDockerfile,
+			GenerateCI:          cfg.GenerateCI,
+			WithOTel:            cfg.WithOTel,
+			ShardModelByTag:     cfg.ShardModelByTag,
+			TemplateOverrideDir: cfg.TemplateDir,
+			ExcludeFiles:        cfg.ExcludeFiles,
+			Skip:                cfg.Skip,
+			Provenance:          generateProvenance(cfg),
+			Force:               force,
+			OverwriteModified:   cfg.OverwriteModified,
+			Prune:               cfg.Prune,
+			DryRun:              cfg.DryRun,
+			Verbose:             cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName, report.PackageName = res.ToolName, res.PackageName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	case "postman":
+		res, err := postmanemitter.Emit(ctx, sm, postmanemitter.Options{
+			OutDir:            outDir,
+			ToolName:          resolvedToolName,
+			Force:             force,
+			OverwriteModified: cfg.OverwriteModified,
+			Prune:             cfg.Prune,
+			DryRun:            cfg.DryRun,
+			Verbose:           cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName = res.ToolName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	case "bruno":
+		res, err := brunoemitter.Emit(ctx, sm, brunoemitter.Options{
+			OutDir:            outDir,
+			ToolName:          resolvedToolName,
+			Force:             force,
+			OverwriteModified: cfg.OverwriteModified,
+			Prune:             cfg.Prune,
+			DryRun:            cfg.DryRun,
+			Verbose:           cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName = res.ToolName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	case "markdown":
+		res, err := markdownemitter.Emit(ctx, sm, markdownemitter.Options{
+			OutDir:            outDir,
+			ToolName:          resolvedToolName,
+			Force:             force,
+			OverwriteModified: cfg.OverwriteModified,
+			Prune:             cfg.Prune,
+			DryRun:            cfg.DryRun,
+			Verbose:           cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName = res.ToolName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	case "model":
+		res, err := modelemitter.Emit(ctx, sm, modelemitter.Options{
+			OutDir:            outDir,
+			ToolName:          resolvedToolName,
+			Compact:           cfg.Compact,
+			Force:             force,
+			OverwriteModified: cfg.OverwriteModified,
+			Prune:             cfg.Prune,
+			DryRun:            cfg.DryRun,
+			Verbose:           cfg.Verbose,
+		})
+		if err != nil {
+			return wrapOutputError(err, absOut)
+		}
+		report.ToolName = res.ToolName
+		for _, p := range res.Planned {
+			report.Plan = append(report.Plan, plannedFileReport(p.RelPath, p.Size, p.Mode, p.SHA256, p.Change))
+		}
+		report.recordOutcome(res.Outcome)
+	default:
+		// Should not happen due to earlier validation, but keep defensive.
+		return newUsageError(fmt.Sprintf("generate: unsupported --lang %q (allowed: go, npm, python, postman, bruno, markdown, model)", cfg.Lang))
+	}
+	// The manifest records how the model was built, for refresh-model.
+	if !cfg.DryRun {
+		if err := manifest.Stamp(absOut, generateProvenance(cfg), optionsHash); err != nil {
+			return wrapOutputError(err, absOut)
+		}
+	}
+
+	if cfg.Verify {
+		if err := verifyOutput(absOut, report, jsonOutput); err != nil {
+			return err
+		}
+		return report.deniedError()
+	}
+
+	// Post-generate hooks only warn on failure; written files are kept.
+	if !cfg.DryRun && len(cfg.Hooks.PostGenerate) > 0 {
+		if err := applyHooks(ctx, "postGenerate", cfg.Hooks.PostGenerate, absOut, hookEnv, hookStdout); err != nil {
+			report.warn(genspec.NewWarning(genspec.WarnHookFailed, "%v", err))
+		}
+	}
+
+	if archivePath != "" {
+		report.Archive = &archiveReport{Path: archivePath, Format: cfg.Archive, Entries: len(report.Plan)}
+		if !cfg.DryRun {
+			n, err := writeArchive(archivePath, cfg.Archive, absOut)
+			if err != nil {
+				return wrapOutputError(err, archivePath)
+			}
+			report.Archive.Entries = n
+		}
+	}
+
+	if jsonOutput {
+		if err := report.writeJSON(os.Stdout); err != nil {
+			return err
+		}
+		return report.deniedError()
+	}
+	if cfg.DryRun && report.Archive != nil {
+		printArchivePlan(report.Archive, report.Plan)
+	} else if cfg.DryRun {
+		printPlan(absOut, report.Plan)
+	}
+	return report.deniedError()
+}
+
+// checkArchivePath reports an error when path is a directory, or an existing
+// file and force is not set.
+func checkArchivePath(path string, force bool) error {
+	st, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	case st.IsDir():
+		return fmt.Errorf("archive path %q is a directory", path)
+	case !force:
+		return fmt.Errorf("archive %q already exists (use --force to overwrite)", path)
+	}
+	return nil
+}
+
+// writeArchive packs the files under dir, except the manifest, into a format
+// archive at path, through a temporary file renamed into place. It returns
+// the number of entries.
+func writeArchive(path, format, dir string) (int, error) {
+	files, err := archive.ReadDir(dir, func(rel string) bool { return rel == manifest.FileName })
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("mkdir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".swagger2mcp-archive-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := archive.Write(tmp, format, files); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("rename: %w", err)
+	}
+	return len(files), nil
+}
+
+// generateProvenance describes the input and model filters of cfg. File paths
+// are made absolute so the record stays usable from another directory.
+func generateProvenance(cfg *GenerateConfig) *manifest.Provenance {
+	p := &manifest.Provenance{
+		Generator:      Version,
+		Input:          absoluteInput(cfg.Input),
+		IncludeTags:    cfg.IncludeTags,
+		ExcludeTags:    cfg.ExcludeTags,
+		IncludeSchemas: cfg.IncludeSchemas,
+		ExcludeSchemas: cfg.ExcludeSchemas,
+		KeepAllServers: cfg.KeepAllServers,
+		DropDevServers: cfg.DropDevServers,
+	}
+	for _, input := range cfg.ExtraInputs {
+		p.ExtraInputs = append(p.ExtraInputs, absoluteInput(input))
+	}
+	for _, pred := range cfg.DropExtensions {
+		p.DropExtensions = append(p.DropExtensions, pred.Key+"="+pred.Value)
+	}
+	if cfg.Overrides != "" {
+		p.Overrides = absoluteInput(cfg.Overrides)
+	}
+	return p
+}
+
+// generateOptionsHash hashes the options of cfg that shape the generated
+// files, with the contents of the template override directory, for
+// manifest.Key. Options that only steer the run (where it writes, --force,
+// --dry-run, --output, hooks, watching, warnings) are left out, so changing
+// them alone keeps the output up to date. It returns "" when the template
+// directory cannot be read, which is never up to date.
+func generateOptionsHash(cfg *GenerateConfig) string {
+	c := *cfg
+	c.Out, c.ConfigPath, c.Archive, c.Output = "", "", "", ""
+	c.MaxSpecSize = 0
+	c.DryRun, c.Force, c.OverwriteModified, c.Prune, c.Verbose = false, false, false, false, false
+	c.Hooks = GenerateHooks{}
+	c.Watch, c.WatchInterval, c.Verify = false, 0, false
+	c.DenyWarnings, c.AllowWarnings = nil, nil
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	if c.TemplateDir != "" {
+		sum, err := tmploverride.Hash(c.TemplateDir)
+		if err != nil {
+			return ""
+		}
+		data = append(append(data, 0), sum...)
+	}
+	return manifest.HashBytes(data)
+}
+
+// absoluteInput returns input as an absolute path, or unchanged when it is a
+// URL or cannot be resolved.
+func absoluteInput(input string) string {
+	if u, err := url.Parse(input); err == nil && u.Scheme != "" && u.Host != "" {
+		return input
+	}
+	if abs, err := filepath.Abs(input); err == nil {
+		return abs
+	}
+	return input
+}
+
+// verifyOutput compares the rendered plan with outDir for --verify, reports
+// the result, and fails when any file differs.
+func verifyOutput(outDir string, report *generateReport, jsonOutput bool) error {
+	rendered := make(map[string]string, len(report.Plan))
+	for _, p := range report.Plan {
+		rendered[p.Path] = p.SHA256
+	}
+	v, err := manifest.Verify(outDir, rendered)
+	if err != nil {
+		return newUsageError(fmt.Sprintf("generate: --verify: %v", err))
+	}
+	report.Verify = v
+	if jsonOutput {
+		if err := report.writeJSON(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		printVerification(outDir, v)
+	}
+	if !v.OK() {
+		return fmt.Errorf("generate: %s does not match the spec (%d modified, %d missing, %d extra)", outDir, len(v.Modified), len(v.Missing), len(v.Extra))
+	}
+	return nil
+}
+
+func printVerification(outDir string, v *manifest.Verification) {
+	fmt.Fprintf(os.Stdout, "Verified %s: %d matched, %d modified, %d missing, %d extra\n", outDir, len(v.Matched), len(v.Modified), len(v.Missing), len(v.Extra))
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{{"modified", v.Modified}, {"missing", v.Missing}, {"extra", v.Extra}} {
+		for _, p := range group.paths {
+			fmt.Fprintf(os.Stdout, "- %s %s\n", group.label, p)
+		}
+	}
+	if len(v.UserOwned) > 0 {
+		fmt.Fprintf(os.Stdout, "(%d user-owned files not compared)\n", len(v.UserOwned))
+	}
+}
+
+// ensureEmptyOutputDir reports an error when dir exists and has entries.
+func ensureEmptyOutputDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read output directory %q: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("output directory %q is not empty (use --force to overwrite)", dir)
+	}
+	return nil
+}
+
+// applyHooks runs each command through the platform shell with dir as the
+// working directory and env appended to the current environment, sending the
+// commands' stdout to stdout. It stops at the first failing command.
+func applyHooks(ctx context.Context, stage string, commands []string, dir string, env []string, stdout io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for idx, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			continue
+		}
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdout = stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %d (%q) failed: %v", stage, idx+1, command, err)
+		}
+	}
+	return nil
+}
+
+// loadSpec loads input and, when verbose, reports loader warnings on stderr
+// so stdout stays reserved for command output.
+func loadSpec(ctx context.Context, input string, verbose bool, opts ...genspec.Option) (*openapi3.T, error) {
+	res, err := genspec.LoadDetailed(ctx, input, opts...)
+	if err != nil {
+		return nil, mapSpecError(err)
+	}
+	if verbose {
+		printLoadWarnings(res.Warnings)
+	}
+	return res.Doc, nil
+}
+
+func printLoadWarnings(warnings []genspec.Warning) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "[WARN] %s\n", w.Message)
+	}
+}
+
+// mapSpecError turns structured spec errors into friendly usage messages.
+func mapSpecError(err error) error {
+	var se *genspec.SpecError
+	if errors.As(err, &se) {
+		msg := fmt.Sprintf("spec: %s", se.Message)
+		if se.Location != "" {
+			msg = fmt.Sprintf("%s\nLocation: %s", msg, se.Location)
+		}
+		if se.JSONPointer != "" {
+			msg = fmt.Sprintf("%s\nPointer: %s", msg, se.JSONPointer)
+		}
+		return newUsageError(msg)
+	}
+	return err
+}
+
+// printArchivePlan lists the entries a --dry-run would pack into the archive.
+func printArchivePlan(a *archiveReport, plan []plannedFile) {
+	fmt.Fprintf(os.Stdout, "Planned archive %s (%s, %d entries):\n", a.Path, a.Format, a.Entries)
+	for _, p := range plan {
+		fmt.Fprintf(os.Stdout, "- %s\n", p.Path)
+	}
+}
+
+// printPlan lists the planned files with how each would change the output
+// directory, or reports "no changes" when every file is already up to date.
+func printPlan(outDir string, plan []plannedFile) {
+	changed := 0
+	for _, p := range plan {
+		if p.Change != string(filewriter.Unchanged) {
+			changed++
+		}
+	}
+	if len(plan) > 0 && changed == 0 {
+		fmt.Fprintf(os.Stdout, "Planned writes to %s: no changes (%d files up to date)\n", outDir, len(plan))
+		return
+	}
+	fmt.Fprintf(os.Stdout, "Planned writes to %s (%d files, %d changed):\n", outDir, len(plan), changed)
+	for _, p := range plan {
+		fmt.Fprintf(os.Stdout, "- %s (%s)\n", p.Path, p.Change)
+	}
+}
+
+// generateReport is the document printed by generate --output json.
+type generateReport struct {
+	Config      generateReportConfig   `json:"config"`
+	ToolName    string                 `json:"toolName"`
+	PackageName string                 `json:"packageName,omitempty"`
+	Skipped     bool                   `json:"skipped"` // output was up to date; nothing was emitted
+	Plan        []plannedFile          `json:"plan"`
+	Pruned      []string               `json:"pruned,omitempty"`  // stale files removed by --prune
+	Verify      *manifest.Verification `json:"verify,omitempty"`  // --verify result
+	Archive     *archiveReport         `json:"archive,omitempty"` // --archive output
+	Warnings    []string               `json:"warnings"`
+	// Diagnostics repeats Warnings with each warning's registered ID,
+	// category and position.
+	Diagnostics []reportWarning `json:"diagnostics"`
+
+	collect bool                       // collect warnings instead of printing them (JSON mode)
+	deny    map[genspec.WarningID]bool // IDs that fail the run (--deny-warning minus --allow-warning)
+	denied  []genspec.Warning          // warnings seen whose ID is in deny
+}
+
+// archiveReport describes the archive of --archive. With --dry-run Entries
+// counts the planned files; otherwise the files packed, hook output included.
+type archiveReport struct {
+	Path    string `json:"path"` // absolute
+	Format  string `json:"format"`
+	Entries int    `json:"entries"`
+}
+
+// reportWarning is a warning as listed in a report's diagnostics.
+type reportWarning struct {
+	ID       genspec.WarningID `json:"id"`
+	Name     string            `json:"name"`
+	Category string            `json:"category"`
+	Message  string            `json:"message"`
+	Location string            `json:"location,omitempty"`
+	Pointer  string            `json:"pointer,omitempty"`
+	Denied   bool              `json:"denied,omitempty"` // fails the run (--deny-warning)
+}
+
+// generateReportConfig is the resolved configuration echoed in a report.
+type generateReportConfig struct {
+	Input             string   `json:"input"`
+	ExtraInputs       []string `json:"extraInputs,omitempty"`
+	Lang              string   `json:"lang"`
+	Out               string   `json:"out"` // absolute
+	IncludeTags       []string `json:"includeTags,omitempty"`
+	ExcludeTags       []string `json:"excludeTags,omitempty"`
+	IncludeSchemas    []string `json:"includeSchemas,omitempty"`
+	ExcludeSchemas    []string `json:"excludeSchemas,omitempty"`
+	Overrides         string   `json:"overrides,omitempty"`
+	MaxSpecSize       int64    `json:"maxSpecSize,omitempty"`
+	DryRun            bool     `json:"dryRun"`
+	Force             bool     `json:"force"`
+	OverwriteModified bool     `json:"overwriteModified,omitempty"`
+	Prune             bool     `json:"prune,omitempty"`
+}
+
+// plannedFile is one emitted (or, with --dry-run, planned) file.
+type plannedFile struct {
+	Path   string `json:"path"` // slash-separated, relative to config.out
+	Size   int    `json:"size"`
+	Mode   string `json:"mode"`   // octal permission bits, e.g. "0644"
+	SHA256 string `json:"sha256"` // hex digest of the content
+	Change string `json:"change"` // create, update, or unchanged relative to config.out
+}
 
-	return nil
+func plannedFileReport(rel string, size int, mode os.FileMode, sha string, change filewriter.Change) plannedFile {
+	return plannedFile{
+		Path:   filepath.ToSlash(rel),
+		Size:   size,
+		Mode:   fmt.Sprintf("%04o", mode.Perm()),
+		SHA256: sha,
+		Change: string(change),
+	}
 }
 
-func runGenerate(ctx context.Context, cfg *GenerateConfig) error {
-	// 1) Load the spec (file or http/https URL) with validation and conversion
-	doc, err := genspec.Load(ctx, cfg.Input)
-	if err != nil {
-		// Map structured spec errors into friendly messages
-		var se *genspec.SpecError
-		if errors.As(err, &se) {
-			msg := fmt.Sprintf("spec: %s", se.Message)
-			if se.Location != "" {
-				msg = fmt.Sprintf("%s\nLocation: %s", msg, se.Location)
-			}
-			if se.JSONPointer != "" {
-				msg = fmt.Sprintf("%s\nPointer: %s", msg, se.JSONPointer)
-			}
-			return newUsageError(msg)
+// recordOutcome reports files a forced run kept or pruned because of the last
+// run's manifest.
+func (r *generateReport) recordOutcome(o manifest.Outcome) {
+	for _, p := range o.Modified {
+		r.warn(genspec.NewWarning(genspec.WarnModifiedFileKept, "kept %s: edited since the last run (use --overwrite-modified to replace it)", p).At(p, ""))
+	}
+	for _, p := range o.Unmanaged {
+		r.warn(genspec.NewWarning(genspec.WarnUnmanagedFileKept, "kept %s: not generated by swagger2mcp (use --overwrite-modified to replace it)", p).At(p, ""))
+	}
+	for _, p := range o.Stale {
+		r.warn(genspec.NewWarning(genspec.WarnStaleFile, "%s is no longer generated (use --prune to remove it)", p).At(p, ""))
+	}
+	for _, p := range o.Pruned {
+		r.Pruned = append(r.Pruned, p)
+		if !r.collect {
+			fmt.Fprintf(os.Stderr, "[INFO] pruned %s\n", p)
 		}
-		return err
 	}
+}
 
-	// 2) Build the internal model (IM) with tag filters
-	sm, err := genspec.BuildServiceModel(
-		ctx,
-		doc,
-		nil, // v2Raw - we'll add this later when we detect v2 conversion
-		genspec.WithIncludeTags(cfg.IncludeTags),
-		genspec.WithExcludeTags(cfg.ExcludeTags),
-	)
-	if err != nil {
-		return fmt.Errorf("build model: %w", err)
+// warn records a warning, printing it to stderr right away in text mode.
+func (r *generateReport) warn(w genspec.Warning) {
+	denied := r.deny[w.ID]
+	if denied {
+		r.denied = append(r.denied, w)
 	}
+	kind, _ := genspec.LookupWarning(string(w.ID))
+	r.Warnings = append(r.Warnings, w.Message)
+	r.Diagnostics = append(r.Diagnostics, reportWarning{
+		ID:       w.ID,
+		Name:     kind.Name,
+		Category: w.Category,
+		Message:  w.Message,
+		Location: w.Location,
+		Pointer:  w.Pointer,
+		Denied:   denied,
+	})
+	if !r.collect {
+		fmt.Fprintf(os.Stderr, "[WARN] %s\n", w)
+	}
+}
 
-	// 3) Derive sensible defaults for names and out dir when omitted
-	outDir := strings.TrimSpace(cfg.Out)
-	resolvedToolName := strings.TrimSpace(cfg.ToolName)
-	if resolvedToolName == "" {
-		resolvedToolName = deriveToolName(sm.Title)
-		if resolvedToolName == "" {
-			resolvedToolName = "mcp-tool"
-		}
-	} else {
-		resolvedToolName = sanitizeToolName(resolvedToolName)
-		if resolvedToolName == "" { // after sanitization
-			resolvedToolName = "mcp-tool"
-		}
+// deniedError reports the denied warnings seen so far, if any.
+func (r *generateReport) deniedError() error {
+	if len(r.denied) == 0 {
+		return nil
 	}
-	if outDir == "" {
-		outDir = resolvedToolName
+	ids := make([]string, 0, len(r.denied))
+	for _, w := range r.denied {
+		ids = append(ids, string(w.ID))
 	}
+	ids = sanitizeTags(ids)
+	return fmt.Errorf("generate: %d denied warning(s) (%s); first: %s", len(r.denied), strings.Join(ids, ", "), r.denied[0].Message)
+}
 
-	// Ensure outDir is absolute only for display; emitters handle actual creation/writes
-	absOut := outDir
-	if ap, err := filepath.Abs(outDir); err == nil {
-		absOut = ap
+// deniedWarnings is the set of warning IDs that fail a run configured by cfg.
+func deniedWarnings(cfg *GenerateConfig) map[genspec.WarningID]bool {
+	deny := map[genspec.WarningID]bool{}
+	for _, id := range cfg.DenyWarnings {
+		deny[genspec.WarningID(id)] = true
 	}
-
-	// 4) Emit for the chosen language
-	switch cfg.Lang {
-	case "go":
-		res, err := goemitter.Emit(ctx, sm, goemitter.Options{
-			OutDir:     outDir,
-			ToolName:   resolvedToolName,
-			ModuleName: strings.TrimSpace(cfg.PackageName),
-			Force:      cfg.Force,
-			DryRun:     cfg.DryRun,
-			Verbose:    cfg.Verbose,
-		})
-		if err != nil {
-			return wrapOutputError(err, absOut)
-		}
-		if cfg.DryRun {
-			printPlan(absOut, len(res.Planned), func() []string {
-				paths := make([]string, 0, len(res.Planned))
-				for _, p := range res.Planned {
-					paths = append(paths, p.RelPath)
-				}
-				return paths
-			}())
-		}
-	case "npm":
-		res, err := npmemitter.Emit(ctx, sm, npmemitter.Options{
-			OutDir:      outDir,
-			ToolName:    resolvedToolName,
-			PackageName: strings.TrimSpace(cfg.PackageName),
-			Force:       cfg.Force,
-			DryRun:      cfg.DryRun,
-			Verbose:     cfg.Verbose,
-		})
-		if err != nil {
-			return wrapOutputError(err, absOut)
-		}
-		if cfg.DryRun {
-			printPlan(absOut, len(res.Planned), func() []string {
-				paths := make([]string, 0, len(res.Planned))
-				for _, p := range res.Planned {
-					paths = append(paths, p.RelPath)
-				}
-				return paths
-			}())
-		}
-	case "python":
-		res, err := pyemitter.Emit(ctx, sm, pyemitter.Options{
-			OutDir:      outDir,
-			ToolName:    resolvedToolName,
-			PackageName: strings.TrimSpace(cfg.PackageName),
-			Force:       cfg.Force,
-			DryRun:      cfg.DryRun,
-			Verbose:     cfg.Verbose,
-		})
-		if err != nil {
-			return wrapOutputError(err, absOut)
-		}
-		if cfg.DryRun {
-			printPlan(absOut, len(res.Planned), func() []string {
-				paths := make([]string, 0, len(res.Planned))
-				for _, p := range res.Planned {
-					paths = append(paths, p.RelPath)
-				}
-				return paths
-			}())
-		}
-	default:
-		// Should not happen due to earlier validation, but keep defensive.
-		return newUsageError(fmt.Sprintf("generate: unsupported --lang %q (allowed: go, npm, python)", cfg.Lang))
+	for _, id := range cfg.AllowWarnings {
+		delete(deny, genspec.WarningID(id))
 	}
-
-	return nil
+	return deny
 }
 
-func printPlan(outDir string, count int, relPaths []string) {
-	fmt.Fprintf(os.Stdout, "Planned writes to %s (%d files):\n", outDir, count)
-	for _, p := range relPaths {
-		fmt.Fprintf(os.Stdout, "- %s\n", p)
+func (r *generateReport) writeJSON(w io.Writer) error {
+	if r.Plan == nil {
+		r.Plan = []plannedFile{}
+	}
+	if r.Warnings == nil {
+		r.Warnings = []string{}
 	}
+	if r.Diagnostics == nil {
+		r.Diagnostics = []reportWarning{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
 }
 
 func wrapOutputError(err error, outDir string) error {
@@ -422,6 +1857,54 @@ func intersect(a, b []string) []string {
 	return result
 }
 
+// parseExtensionPredicates parses "x-key=value" pairs for --drop-extension.
+func parseExtensionPredicates(values []string) ([]ExtensionPredicate, error) {
+	var preds []ExtensionPredicate
+	for _, raw := range values {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(raw, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !strings.HasPrefix(strings.ToLower(key), "x-") {
+			return nil, newUsageError(fmt.Sprintf("generate: invalid --drop-extension %q (expected x-key=value)", raw))
+		}
+		preds = append(preds, ExtensionPredicate{Key: key, Value: strings.TrimSpace(value)})
+	}
+	return preds, nil
+}
+
+// configFileNames lists the config file names discovered when --config is absent,
+// in order of preference.
+var configFileNames = []string{"swagger2mcp.yaml", "swagger2mcp.yml", ".swagger2mcp.yaml"}
+
+// discoverConfigFile looks for a config file in dir and its parents, stopping at
+// the repository root (a directory containing .git) or the filesystem root.
+// It returns an empty string when no config file is found.
+func discoverConfigFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		for _, name := range configFileNames {
+			candidate := filepath.Join(dir, name)
+			if st, err := os.Stat(candidate); err == nil && !st.IsDir() {
+				return candidate
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
 func applyGenerateConfigFromFile(cfg *GenerateConfig, path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -434,73 +1917,409 @@ func applyGenerateConfigFromFile(cfg *GenerateConfig, path string) error {
 	}
 
 	for key, value := range raw {
-		normalized := normalizeKey(key)
-		switch normalized {
-		case "input":
-			str, err := valueAsString(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
-			}
-			cfg.Input = str
-		case "lang":
-			str, err := valueAsString(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
-			}
-			cfg.Lang = str
-		case "out":
-			str, err := valueAsString(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
-			}
-			cfg.Out = str
-		case "includetags":
-			list, err := valueAsStringSlice(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
+		if normalizeKey(key) == "hooks" {
+			if err := applyHooksConfig(&cfg.Hooks, path, key, value); err != nil {
+				return err
 			}
-			cfg.IncludeTags = sanitizeTags(list)
-		case "excludetags":
+			continue
+		}
+		known, err := applyGenerateConfigValue(cfg, key, fmt.Sprintf("config field %q", key), value)
+		if err != nil {
+			return err
+		}
+		if !known {
+			return newUsageError(fmt.Sprintf("config file %q: unknown field %q", path, key))
+		}
+	}
+
+	return nil
+}
+
+// applyGenerateConfigValue sets the setting named key (matched with
+// normalizeKey) from a config-file or environment value. label names the
+// source in error messages. It reports false for keys it does not know.
+func applyGenerateConfigValue(cfg *GenerateConfig, key, label string, value any) (bool, error) {
+	switch normalizeKey(key) {
+	case "input":
+		// A list names several specs to merge; a string is a single path, so
+		// it is not split on commas.
+		if _, isList := value.([]any); isList {
 			list, err := valueAsStringSlice(value)
 			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
-			}
-			cfg.ExcludeTags = sanitizeTags(list)
-		case "toolname":
-			str, err := valueAsString(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
-			}
-			cfg.ToolName = str
-		case "packagename":
-			str, err := valueAsString(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
-			}
-			cfg.PackageName = str
-		case "dryrun":
-			val, err := valueAsBool(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
+				return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
 			}
-			cfg.DryRun = val
-		case "force":
-			val, err := valueAsBool(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
+			cfg.Input, cfg.ExtraInputs = "", nil
+			if len(list) > 0 {
+				cfg.Input, cfg.ExtraInputs = list[0], list[1:]
 			}
-			cfg.Force = val
-		case "verbose":
-			val, err := valueAsBool(value)
-			if err != nil {
-				return newUsageError(fmt.Sprintf("config field %q: %v", key, err))
+			break
+		}
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Input, cfg.ExtraInputs = str, nil
+	case "lang":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Lang = str
+	case "out":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Out = str
+	case "includetags":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.IncludeTags = sanitizeTags(list)
+	case "excludetags":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ExcludeTags = sanitizeTags(list)
+	case "includeschemas":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.IncludeSchemas = sanitizeTags(list)
+	case "excludeschemas":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ExcludeSchemas = sanitizeTags(list)
+	case "dropextensions":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		preds, err := parseExtensionPredicates(list)
+		if err != nil {
+			return true, err
+		}
+		cfg.DropExtensions = preds
+	case "keepallservers":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.KeepAllServers = val
+	case "dropdevservers":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.DropDevServers = val
+	case "overrides":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Overrides = str
+	case "maxspecsize":
+		size, err := valueAsByteSize(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.MaxSpecSize = size
+	case "toolname":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ToolName = str
+	case "packagename":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.PackageName = str
+	case "goversion":
+		if _, isNumber := value.(float64); isNumber {
+			// YAML reads 1.20 as the number 1.2.
+			return true, newUsageError(fmt.Sprintf("%s: quote the Go version, e.g. \"1.24\"", label))
+		}
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.GoVersion = str
+	case "mcplibversion":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.MCPLibVersion = str
+	case "layout":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Layout = str
+	case "pybuildsystem":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.PyBuildSystem = str
+	case "pythonversion":
+		if _, isNumber := value.(float64); isNumber {
+			return true, newUsageError(fmt.Sprintf("%s: quote the Python version, e.g. \"3.11\"", label))
+		}
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.PythonVersion = str
+	case "pythonrequires":
+		if _, isNumber := value.(float64); isNumber {
+			// YAML reads 3.10 as the number 3.1.
+			return true, newUsageError(fmt.Sprintf("%s: quote the Python requirement, e.g. \">=3.10\"", label))
+		}
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.PythonRequires = str
+	case "pymcpversion":
+		if _, isNumber := value.(float64); isNumber {
+			return true, newUsageError(fmt.Sprintf("%s: quote the mcp SDK version, e.g. \"1.9.4\"", label))
+		}
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.PyMCPVersion = str
+	case "license":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.License = str
+	case "author":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Author = str
+	case "authoremail":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.AuthorEmail = str
+	case "projectversion":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ProjectVersion = str
+	case "templatedir":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.TemplateDir = str
+	case "excludefiles":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ExcludeFiles = sanitizeTags(list)
+	case "skip":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Skip = sanitizeTags(list)
+	case "enableinvoke":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.EnableInvoke = val
+	case "emitdocs":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.EmitDocs = val
+	case "emitdockerfile":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.EmitDockerfile = val
+	case "ci":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.GenerateCI = val
+	case "withotel":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.WithOTel = val
+	case "shardmodelbytag":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ShardModelByTag = val
+	case "pindependencies":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.PinDependencies = val
+	case "esm":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.ESM = val
+	case "testrunner":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.TestRunner = str
+	case "compact":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Compact = val
+	case "archive":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Archive = str
+	case "dryrun":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.DryRun = val
+	case "force":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Force = val
+	case "overwritemodified":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.OverwriteModified = val
+	case "prune":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Prune = val
+	case "verbose":
+		val, err := valueAsBool(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Verbose = val
+	case "output":
+		str, err := valueAsString(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.Output = str
+	case "denywarnings":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.DenyWarnings = sanitizeTags(list)
+	case "allowwarnings":
+		list, err := valueAsStringSlice(value)
+		if err != nil {
+			return true, newUsageError(fmt.Sprintf("%s: %v", label, err))
+		}
+		cfg.AllowWarnings = sanitizeTags(list)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// generateEnvPrefix prefixes environment variables that override config file
+// values (but not explicit flags), e.g. SWAGGER2MCP_INPUT.
+const generateEnvPrefix = "SWAGGER2MCP_"
+
+// generateEnvKeys lists the settings read from SWAGGER2MCP_<KEY>. List values
+// are comma-separated. Hooks are only configurable via the config file.
+var generateEnvKeys = []string{
+	"INPUT", "LANG", "OUT",
+	"INCLUDE_TAGS", "EXCLUDE_TAGS", "INCLUDE_SCHEMAS", "EXCLUDE_SCHEMAS", "DROP_EXTENSIONS",
+	"KEEP_ALL_SERVERS", "DROP_DEV_SERVERS", "OVERRIDES", "MAX_SPEC_SIZE",
+	"TOOL_NAME", "PACKAGE_NAME", "GO_VERSION", "MCP_LIB_VERSION", "LAYOUT", "PY_BUILD_SYSTEM", "PYTHON_VERSION", "PYTHON_REQUIRES", "PY_MCP_VERSION", "LICENSE", "AUTHOR", "AUTHOR_EMAIL", "PROJECT_VERSION", "PIN_DEPENDENCIES", "TEMPLATE_DIR", "EXCLUDE_FILES", "SKIP", "ENABLE_INVOKE", "EMIT_DOCS", "EMIT_DOCKERFILE", "CI", "WITH_OTEL", "SHARD_MODEL_BY_TAG", "ESM", "TEST_RUNNER", "COMPACT", "ARCHIVE",
+	"DRY_RUN", "FORCE", "OVERWRITE_MODIFIED", "PRUNE", "VERBOSE", "OUTPUT", "DENY_WARNINGS", "ALLOW_WARNINGS",
+}
+
+// applyGenerateConfigFromEnv applies SWAGGER2MCP_* variables found via lookup.
+// Empty values are treated as unset.
+func applyGenerateConfigFromEnv(cfg *GenerateConfig, lookup func(string) (string, bool)) error {
+	for _, key := range generateEnvKeys {
+		name := generateEnvPrefix + key
+		value, ok := lookup(name)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		if _, err := applyGenerateConfigValue(cfg, key, "environment variable "+name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyHooksConfig(hooks *GenerateHooks, path, key string, value any) error {
+	if value == nil {
+		return nil
+	}
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return newUsageError(fmt.Sprintf("config field %q: expected mapping, got %T", key, value))
+	}
+	for sub, subValue := range raw {
+		// A single command string must not be split on commas like tag lists.
+		var list []string
+		var err error
+		if str, isStr := subValue.(string); isStr {
+			if str = strings.TrimSpace(str); str != "" {
+				list = []string{str}
 			}
-			cfg.Verbose = val
+		} else {
+			list, err = valueAsStringSlice(subValue)
+		}
+		if err != nil {
+			return newUsageError(fmt.Sprintf("config field %q: %v", key+"."+sub, err))
+		}
+		switch normalizeKey(sub) {
+		case "pregenerate":
+			hooks.PreGenerate = list
+		case "postgenerate":
+			hooks.PostGenerate = list
 		default:
-			return newUsageError(fmt.Sprintf("config file %q: unknown field %q", path, key))
+			return newUsageError(fmt.Sprintf("config file %q: unknown field %q", path, key+"."+sub))
 		}
 	}
-
 	return nil
 }
 
@@ -571,6 +2390,53 @@ func valueAsBool(v any) (bool, error) {
 	}
 }
 
+func valueAsByteSize(v any) (int64, error) {
+	switch val := v.(type) {
+	case int:
+		if val < 0 {
+			return 0, fmt.Errorf("size must not be negative, got %d", val)
+		}
+		return int64(val), nil
+	case string:
+		return parseByteSize(val)
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("expected size, got %T", v)
+	}
+}
+
+// byteSizeUnits maps size suffixes to multipliers; K/M/G and their KB/MB/GB
+// spellings are binary like KiB/MiB/GiB.
+var byteSizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+}
+
+// parseByteSize parses sizes such as "1048576", "512KiB" or "64MB". An empty
+// string yields 0.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if i < 0 {
+		i = len(s)
+	}
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if i == 0 || !ok {
+		return 0, fmt.Errorf("invalid size %q (expected bytes or a KiB/MiB/GiB suffix, e.g. 64MiB)", s)
+	}
+	n, err := strconv.ParseInt(s[:i], 10, 64)
+	if err != nil || n > math.MaxInt64/unit {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * unit, nil
+}
+
 func splitAndTrim(csv string) []string {
 	parts := strings.Split(csv, ",")
 	cleaned := make([]string, 0, len(parts))
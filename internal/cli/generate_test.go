@@ -6,8 +6,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
 func TestGenerateConfigFromFlags(t *testing.T) {
@@ -89,6 +93,8 @@ out: from-config
 includeTags:
   - cfgFoo
 excludeTags: cfgBar
+excludeSchemas: [Audit*]
+includeSchemas: CfgOnly
 toolName: cfg-tool
 packageName: cfgpkg
 dryRun: true
@@ -116,6 +122,7 @@ verbose: true
 		"generate",
 		"--input", "flag-spec.yaml",
 		"--include-tags", "flagTag",
+		"--include-schemas", "Pet*,Owner",
 		"--dry-run=false",
 		"--force",
 	})
@@ -143,6 +150,12 @@ verbose: true
 	if want := []string{"cfgBar"}; !equalStringSlices(captured.ExcludeTags, want) {
 		t.Errorf("exclude tags: want %v got %v", want, captured.ExcludeTags)
 	}
+	if want := []string{"Pet*", "Owner"}; !equalStringSlices(captured.IncludeSchemas, want) {
+		t.Errorf("include schemas: want %v got %v", want, captured.IncludeSchemas)
+	}
+	if want := []string{"Audit*"}; !equalStringSlices(captured.ExcludeSchemas, want) {
+		t.Errorf("exclude schemas: want %v got %v", want, captured.ExcludeSchemas)
+	}
 	if captured.ToolName != "cfg-tool" {
 		t.Errorf("tool name mismatch: got %q", captured.ToolName)
 	}
@@ -163,6 +176,79 @@ verbose: true
 	}
 }
 
+func TestGenerateConfigEnvOverrides(t *testing.T) {
+	// Not parallel: t.Setenv mutates the process environment.
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configContent := "input: config-spec.yaml\nlang: python\nout: from-config\nincludeTags: [cfgTag]\ntoolName: cfg-tool\nforce: false\n"
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	t.Setenv("SWAGGER2MCP_LANG", "npm")
+	t.Setenv("SWAGGER2MCP_OUT", "from-env")
+	t.Setenv("SWAGGER2MCP_INCLUDE_TAGS", "envA, envB")
+	t.Setenv("SWAGGER2MCP_FORCE", "true")
+	t.Setenv("SWAGGER2MCP_TOOL_NAME", "env-tool")
+	t.Setenv("SWAGGER2MCP_PACKAGE_NAME", "")
+
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"--config", configPath, "generate", "--tool-name", "flag-tool"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured == nil {
+		t.Fatalf("expected config to be captured")
+	}
+	// default < config
+	if captured.Input != "config-spec.yaml" {
+		t.Errorf("input: want config-spec.yaml got %q", captured.Input)
+	}
+	if captured.DryRun {
+		t.Errorf("dry-run: expected default false")
+	}
+	// config < env
+	if captured.Lang != "npm" {
+		t.Errorf("lang: want npm got %q", captured.Lang)
+	}
+	if captured.Out != "from-env" {
+		t.Errorf("out: want from-env got %q", captured.Out)
+	}
+	if want := []string{"envA", "envB"}; !equalStringSlices(captured.IncludeTags, want) {
+		t.Errorf("include tags: want %v got %v", want, captured.IncludeTags)
+	}
+	if !captured.Force {
+		t.Errorf("force: expected true from env")
+	}
+	// Empty variables count as unset.
+	if captured.PackageName != "" {
+		t.Errorf("package name: want empty got %q", captured.PackageName)
+	}
+	// env < flag
+	if captured.ToolName != "flag-tool" {
+		t.Errorf("tool name: want flag-tool got %q", captured.ToolName)
+	}
+
+	t.Setenv("SWAGGER2MCP_FORCE", "sometimes")
+	root = NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"--no-config", "generate", "--input", "spec.yaml"})
+	err := root.Execute()
+	if err == nil || !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "SWAGGER2MCP_FORCE") {
+		t.Fatalf("expected usage error naming SWAGGER2MCP_FORCE, got %v", err)
+	}
+}
+
 func TestGenerateConfigUnknownKey(t *testing.T) {
 	t.Parallel()
 
@@ -194,6 +280,985 @@ func TestGenerateConfigUnknownKey(t *testing.T) {
 	}
 }
 
+func TestGenerateConfigInvalidSchemaPattern(t *testing.T) {
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"generate", "--input", "spec.yaml", "--exclude-schemas", "Audit["})
+
+	err := root.Execute()
+	if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "invalid schema pattern") {
+		t.Fatalf("expected invalid schema pattern usage error, got %v", err)
+	}
+}
+
+func TestGenerateConfigDropExtension(t *testing.T) {
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+
+	root.SetArgs([]string{"--no-config", "generate", "--input", "spec.yaml",
+		"--drop-extension", "x-internal=true", "--drop-extension", "x-stage = beta,rc"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	want := []ExtensionPredicate{{Key: "x-internal", Value: "true"}, {Key: "x-stage", Value: "beta,rc"}}
+	if len(captured.DropExtensions) != len(want) {
+		t.Fatalf("drop extensions: got %+v", captured.DropExtensions)
+	}
+	for i := range want {
+		if captured.DropExtensions[i] != want[i] {
+			t.Fatalf("drop extensions[%d]: want %+v got %+v", i, want[i], captured.DropExtensions[i])
+		}
+	}
+
+	for _, bad := range []string{"internal=true", "x-internal"} {
+		root = NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs([]string{"--no-config", "generate", "--input", "spec.yaml", "--drop-extension", bad})
+		if err := root.Execute(); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "invalid --drop-extension") {
+			t.Fatalf("%q: expected usage error, got %v", bad, err)
+		}
+	}
+}
+
+func TestGenerateConfigServerFlags(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--drop-dev-servers"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !captured.DropDevServers || captured.KeepAllServers {
+		t.Fatalf("expected drop-dev-servers only, got %+v", captured)
+	}
+	if err := run("--keep-all-servers"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !captured.KeepAllServers || captured.DropDevServers {
+		t.Fatalf("expected keep-all-servers only, got %+v", captured)
+	}
+	err := run("--keep-all-servers", "--drop-dev-servers")
+	if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected mutually exclusive usage error, got %v", err)
+	}
+}
+
+func TestGenerateConfigMaxSpecSize(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	for value, want := range map[string]int64{"1048576": 1 << 20, "512KiB": 512 << 10, "64MB": 64 << 20, "1 GiB": 1 << 30} {
+		if err := run("--max-spec-size", value); err != nil {
+			t.Fatalf("%q: execute: %v", value, err)
+		}
+		if captured.MaxSpecSize != want {
+			t.Fatalf("%q: max spec size = %d, want %d", value, captured.MaxSpecSize, want)
+		}
+	}
+	for _, bad := range []string{"big", "-1", "12TB", "MiB"} {
+		if err := run("--max-spec-size", bad); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "invalid --max-spec-size") {
+			t.Fatalf("%q: expected usage error, got %v", bad, err)
+		}
+	}
+
+	cfg := defaultGenerateConfig()
+	if err := applyGenerateConfigFromEnv(&cfg, func(key string) (string, bool) {
+		return "2MiB", key == "SWAGGER2MCP_MAX_SPEC_SIZE"
+	}); err != nil || cfg.MaxSpecSize != 2<<20 {
+		t.Fatalf("env: size=%d err=%v", cfg.MaxSpecSize, err)
+	}
+}
+
+func TestGenerateConfigGoVersions(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--go-version", "1.24", "--mcp-lib-version", "v0.41.1"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured.GoVersion != "1.24" || captured.MCPLibVersion != "v0.41.1" {
+		t.Fatalf("versions not captured: %+v", captured)
+	}
+	if err := run("--go-version", "latest"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "generate: invalid Go version") {
+		t.Fatalf("expected usage error for an invalid Go version, got %v", err)
+	}
+	// Only the Go emitter reads them.
+	if err := run("--lang", "markdown", "--go-version", "latest"); err != nil {
+		t.Fatalf("markdown: %v", err)
+	}
+
+	cfg := defaultGenerateConfig()
+	if _, err := applyGenerateConfigValue(&cfg, "goVersion", "config field \"goVersion\"", 1.2); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "quote the Go version") {
+		t.Fatalf("expected quoting hint for a numeric goVersion, got %v", err)
+	}
+}
+
+func TestGenerateConfigESM(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml", "--lang", "npm"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || !captured.ESM {
+		t.Fatalf("ESM should be on by default: err=%v cfg=%+v", err, captured)
+	}
+	if err := run("--esm=false"); err != nil || captured.ESM {
+		t.Fatalf("--esm=false should select CommonJS: err=%v cfg=%+v", err, captured)
+	}
+
+	cfg := defaultGenerateConfig()
+	if err := applyGenerateConfigFromEnv(&cfg, func(name string) (string, bool) {
+		return "false", name == "SWAGGER2MCP_ESM"
+	}); err != nil || cfg.ESM {
+		t.Fatalf("env: esm=%v err=%v", cfg.ESM, err)
+	}
+}
+
+func TestGenerateConfigTestRunner(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml", "--lang", "npm"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.TestRunner != "vitest" {
+		t.Fatalf("vitest should be the default: err=%v runner=%q", err, captured.TestRunner)
+	}
+	if err := run("--test-runner", "Jest"); err != nil || captured.TestRunner != "jest" {
+		t.Fatalf("--test-runner Jest: err=%v runner=%q", err, captured.TestRunner)
+	}
+	if err := run("--test-runner", "mocha"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "allowed: vitest, jest") {
+		t.Fatalf("expected usage error for an unknown runner, got %v", err)
+	}
+
+	cfg := defaultGenerateConfig()
+	if err := applyGenerateConfigFromEnv(&cfg, func(name string) (string, bool) {
+		return "jest", name == "SWAGGER2MCP_TEST_RUNNER"
+	}); err != nil || cfg.TestRunner != "jest" {
+		t.Fatalf("env: runner=%q err=%v", cfg.TestRunner, err)
+	}
+	cfg = defaultGenerateConfig()
+	if _, err := applyGenerateConfigValue(&cfg, "testRunner", "testRunner", "jest"); err != nil || cfg.TestRunner != "jest" {
+		t.Fatalf("config testRunner: err=%v runner=%q", err, cfg.TestRunner)
+	}
+}
+
+func TestGenerateConfigLayout(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.Layout != "server" {
+		t.Fatalf("default layout: err=%v layout=%q", err, captured.Layout)
+	}
+	if err := run("--layout", " Library "); err != nil || captured.Layout != "library" {
+		t.Fatalf("--layout library: err=%v layout=%q", err, captured.Layout)
+	}
+	if err := run("--layout", "monorepo"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "unsupported --layout") {
+		t.Fatalf("expected usage error for an unknown layout, got %v", err)
+	}
+}
+
+func TestGenerateConfigPyBuildSystem(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml", "--lang", "python"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.PyBuildSystem != "setuptools" {
+		t.Fatalf("default build system: err=%v got=%q", err, captured.PyBuildSystem)
+	}
+	if err := run("--py-build-system", " Poetry "); err != nil || captured.PyBuildSystem != "poetry" {
+		t.Fatalf("--py-build-system poetry: err=%v got=%q", err, captured.PyBuildSystem)
+	}
+	if err := run("--py-build-system", "uv"); err != nil || captured.PyBuildSystem != "uv" {
+		t.Fatalf("--py-build-system uv: err=%v got=%q", err, captured.PyBuildSystem)
+	}
+	if err := run("--py-build-system", "hatch"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "unsupported --py-build-system") {
+		t.Fatalf("expected usage error for an unknown build system, got %v", err)
+	}
+}
+
+func TestGenerateConfigPythonVersions(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml", "--lang", "python"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--python-requires", " >=3.10 ", "--py-mcp-version", "1.9.4"); err != nil || captured.PythonRequires != ">=3.10" || captured.PyMCPVersion != "1.9.4" {
+		t.Fatalf("python versions: err=%v got=%q %q", err, captured.PythonRequires, captured.PyMCPVersion)
+	}
+	if err := run("--python-requires", "3.6"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "3.8 or newer") {
+		t.Fatalf("expected usage error for Python 3.6, got %v", err)
+	}
+	if err := run("--py-mcp-version", "latest"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "invalid mcp SDK version") {
+		t.Fatalf("expected usage error for an invalid mcp version, got %v", err)
+	}
+
+	if err := run("--python-version", " 3.11 "); err != nil || captured.PythonVersion != "3.11" {
+		t.Fatalf("python version: err=%v got=%q", err, captured.PythonVersion)
+	}
+	if err := run("--python-version", "3.11.2"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "invalid Python version") {
+		t.Fatalf("expected usage error for a patch-level Python version, got %v", err)
+	}
+	if err := run("--python-version", "3.11", "--python-requires", ">=3.10"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected usage error for a version and requirement that disagree, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "pythonRequires", "config", 3.1); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error for an unquoted Python version, got %v", err)
+	}
+	if _, err := applyGenerateConfigValue(cfg, "pythonVersion", "config", 3.11); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error for an unquoted pythonVersion, got %v", err)
+	}
+}
+
+func TestGenerateConfigLicense(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.License != "" {
+		t.Fatalf("default license: err=%v got=%q", err, captured.License)
+	}
+	if err := run("--license", "apache-2.0"); err != nil || captured.License != "Apache-2.0" {
+		t.Fatalf("--license apache-2.0: err=%v got=%q", err, captured.License)
+	}
+	if err := run("--license", "WTFPL"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--license") {
+		t.Fatalf("expected usage error for an unknown license, got %v", err)
+	}
+}
+
+func TestGenerateConfigAuthor(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--author", " Jane Doe ", "--author-email", "jane@example.com"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if captured.Author != "Jane Doe" || captured.AuthorEmail != "jane@example.com" {
+		t.Fatalf("author = %q <%q>", captured.Author, captured.AuthorEmail)
+	}
+	if err := run("--author", `Jane "JD" Doe`); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--author") {
+		t.Fatalf("expected usage error for a quoted author, got %v", err)
+	}
+	for _, email := range []string{"jane", "Jane <jane@example.com>"} {
+		if err := run("--author-email", email); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--author-email") {
+			t.Fatalf("expected usage error for e-mail %q, got %v", email, err)
+		}
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "authorEmail", "authorEmail", "ops@example.com"); err != nil || cfg.AuthorEmail != "ops@example.com" {
+		t.Fatalf("config authorEmail: err=%v got=%q", err, cfg.AuthorEmail)
+	}
+}
+
+func TestGenerateConfigProjectVersion(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.ProjectVersion != "" {
+		t.Fatalf("default: err=%v got=%q", err, captured.ProjectVersion)
+	}
+	if err := run("--project-version", "2.1.0-rc.1"); err != nil || captured.ProjectVersion != "2.1.0-rc.1" {
+		t.Fatalf("--project-version: err=%v got=%q", err, captured.ProjectVersion)
+	}
+	for _, bad := range []string{"2.1", "v2.1.0"} {
+		if err := run("--project-version", bad); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--project-version") {
+			t.Fatalf("expected usage error for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestGenerateConfigPinDependencies(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.PinDependencies {
+		t.Fatalf("default: err=%v pinned=%v", err, captured.PinDependencies)
+	}
+	if err := run("--pin-dependencies", "--lang", "npm"); err != nil || !captured.PinDependencies {
+		t.Fatalf("--pin-dependencies: err=%v pinned=%v", err, captured.PinDependencies)
+	}
+	// Only the default mcp-go release has a pinned module graph.
+	if err := run("--pin-dependencies", "--mcp-lib-version", "v0.41.1"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--pin-dependencies") {
+		t.Fatalf("expected usage error for a non-default mcp-go version, got %v", err)
+	}
+	if err := run("--pin-dependencies", "--mcp-lib-version", "v0.41.1", "--layout", "library"); err != nil {
+		t.Fatalf("library layout has no dependencies to pin: %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "pinDependencies", "pinDependencies", true); err != nil || !cfg.PinDependencies {
+		t.Fatalf("config pinDependencies: err=%v pinned=%v", err, cfg.PinDependencies)
+	}
+}
+
+func TestGenerateConfigEnableInvoke(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.EnableInvoke {
+		t.Fatalf("default: err=%v invoke=%v", err, captured.EnableInvoke)
+	}
+	if err := run("--enable-invoke", "--lang", "npm"); err != nil || !captured.EnableInvoke {
+		t.Fatalf("--enable-invoke: err=%v invoke=%v", err, captured.EnableInvoke)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "enableInvoke", "enableInvoke", true); err != nil || !cfg.EnableInvoke {
+		t.Fatalf("config enableInvoke: err=%v invoke=%v", err, cfg.EnableInvoke)
+	}
+}
+
+func TestGenerateConfigEmitDocs(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.EmitDocs {
+		t.Fatalf("default: err=%v docs=%v", err, captured.EmitDocs)
+	}
+	if err := run("--emit-docs", "--lang", "npm"); err != nil || !captured.EmitDocs {
+		t.Fatalf("--emit-docs: err=%v docs=%v", err, captured.EmitDocs)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "emitDocs", "emitDocs", true); err != nil || !cfg.EmitDocs {
+		t.Fatalf("config emitDocs: err=%v docs=%v", err, cfg.EmitDocs)
+	}
+}
+
+func TestGenerateConfigEmitDockerfile(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.EmitDockerfile {
+		t.Fatalf("default: err=%v docker=%v", err, captured.EmitDockerfile)
+	}
+	if err := run("--emit-dockerfile", "--lang", "python"); err != nil || !captured.EmitDockerfile {
+		t.Fatalf("--emit-dockerfile: err=%v docker=%v", err, captured.EmitDockerfile)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "emitDockerfile", "emitDockerfile", true); err != nil || !cfg.EmitDockerfile {
+		t.Fatalf("config emitDockerfile: err=%v docker=%v", err, cfg.EmitDockerfile)
+	}
+}
+
+func TestGenerateConfigCI(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.GenerateCI {
+		t.Fatalf("default: err=%v ci=%v", err, captured.GenerateCI)
+	}
+	if err := run("--ci", "--lang", "npm"); err != nil || !captured.GenerateCI {
+		t.Fatalf("--ci: err=%v ci=%v", err, captured.GenerateCI)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "ci", "ci", true); err != nil || !cfg.GenerateCI {
+		t.Fatalf("config ci: err=%v ci=%v", err, cfg.GenerateCI)
+	}
+}
+
+func TestGenerateConfigCompact(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--lang", "model"); err != nil || captured.Compact {
+		t.Fatalf("default: err=%v compact=%v", err, captured.Compact)
+	}
+	if err := run("--lang", "model", "--compact", "--out", "-"); err != nil || !captured.Compact || captured.Out != "-" {
+		t.Fatalf("--compact: err=%v cfg=%+v", err, captured)
+	}
+	if err := run("--lang", "go", "--out", "-"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "only supported with --lang model") {
+		t.Fatalf("expected usage error for --out - with lang go, got %v", err)
+	}
+	if err := run("--lang", "model", "--out", "-", "--watch"); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error for --out - with --watch, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "compact", "compact", true); err != nil || !cfg.Compact {
+		t.Fatalf("config compact: err=%v compact=%v", err, cfg.Compact)
+	}
+}
+
+func TestGenerateConfigArchive(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.Archive != "" {
+		t.Fatalf("default: err=%v archive=%q", err, captured.Archive)
+	}
+	if err := run("--archive", " ZIP ", "--out", "dist/tool.zip"); err != nil || captured.Archive != "zip" {
+		t.Fatalf("--archive: err=%v archive=%q", err, captured.Archive)
+	}
+	if err := run("--archive", "7z"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "allowed: tar.gz, zip") {
+		t.Fatalf("expected usage error for --archive 7z, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "archive", "archive", "tar.gz"); err != nil || cfg.Archive != "tar.gz" {
+		t.Fatalf("config archive: err=%v archive=%q", err, cfg.Archive)
+	}
+}
+
+func TestGenerateConfigInputs(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--input", "a,b.yaml"); err != nil || captured.Input != "a,b.yaml" || captured.ExtraInputs != nil {
+		t.Fatalf("one input: err=%v input=%q extra=%q", err, captured.Input, captured.ExtraInputs)
+	}
+	if err := run("--input", "a.yaml", "--input", " b.yaml ", "--input", "c.yaml"); err != nil ||
+		captured.Input != "a.yaml" || !reflect.DeepEqual(captured.ExtraInputs, []string{"b.yaml", "c.yaml"}) {
+		t.Fatalf("repeated input: err=%v input=%q extra=%q", err, captured.Input, captured.ExtraInputs)
+	}
+	err := run("--input", "a.yaml", "--input", "https://example.com/b.yaml", "--watch")
+	if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "needs local files") {
+		t.Fatalf("expected usage error for --watch with a remote spec among several, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "input", "input", []any{"a.yaml", "b.yaml"}); err != nil ||
+		cfg.Input != "a.yaml" || !reflect.DeepEqual(cfg.ExtraInputs, []string{"b.yaml"}) {
+		t.Fatalf("config input list: err=%v input=%q extra=%q", err, cfg.Input, cfg.ExtraInputs)
+	}
+	if _, err := applyGenerateConfigValue(cfg, "input", "input", "c.yaml"); err != nil || cfg.Input != "c.yaml" || cfg.ExtraInputs != nil {
+		t.Fatalf("config input: err=%v input=%q extra=%q", err, cfg.Input, cfg.ExtraInputs)
+	}
+}
+
+func TestGenerateConfigWithOTel(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.WithOTel {
+		t.Fatalf("default: err=%v otel=%v", err, captured.WithOTel)
+	}
+	if err := run("--with-otel", "--lang", "npm"); err != nil || !captured.WithOTel {
+		t.Fatalf("--with-otel: err=%v otel=%v", err, captured.WithOTel)
+	}
+	err := run("--with-otel", "--pin-dependencies", "--lang", "go")
+	if err == nil || !strings.Contains(err.Error(), "--with-otel") {
+		t.Fatalf("expected --pin-dependencies to reject --with-otel for go, got %v", err)
+	}
+	if err := run("--with-otel", "--pin-dependencies", "--lang", "python"); err != nil {
+		t.Fatalf("python pins opentelemetry-api: %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "withOTel", "withOTel", true); err != nil || !cfg.WithOTel {
+		t.Fatalf("config withOTel: err=%v otel=%v", err, cfg.WithOTel)
+	}
+}
+
+func TestGenerateConfigShardModelByTag(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run(); err != nil || captured.ShardModelByTag {
+		t.Fatalf("default: err=%v shard=%v", err, captured.ShardModelByTag)
+	}
+	if err := run("--shard-model-by-tag", "--lang", "python"); err != nil || !captured.ShardModelByTag {
+		t.Fatalf("--shard-model-by-tag: err=%v shard=%v", err, captured.ShardModelByTag)
+	}
+	t.Setenv("SWAGGER2MCP_SHARD_MODEL_BY_TAG", "true")
+	if err := run(); err != nil || !captured.ShardModelByTag {
+		t.Fatalf("SWAGGER2MCP_SHARD_MODEL_BY_TAG: err=%v shard=%v", err, captured.ShardModelByTag)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "shardModelByTag", "shardModelByTag", true); err != nil || !cfg.ShardModelByTag {
+		t.Fatalf("config shardModelByTag: err=%v shard=%v", err, cfg.ShardModelByTag)
+	}
+}
+
+func TestGenerateConfigTemplateDir(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	dir := t.TempDir()
+	for rel, text := range map[string]string{
+		"README.md":            "# same-path override\n",
+		"go/README.md.tmpl":    "# {{.ToolName}}\n",
+		"python/Makefile.tmpl": "all:\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := run("--template-dir", " "+dir+" "); err != nil || captured.TemplateDir != dir {
+		t.Fatalf("--template-dir: err=%v dir=%q", err, captured.TemplateDir)
+	}
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "templateDir", "templateDir", "tmpl"); err != nil || cfg.TemplateDir != "tmpl" {
+		t.Fatalf("config templateDir: err=%v dir=%q", err, cfg.TemplateDir)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "python", "Makefle.tmpl"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := run("--template-dir", dir)
+	if err == nil || !strings.Contains(err.Error(), "unknown template override python/Makefle.tmpl") || !strings.Contains(err.Error(), "Makefile.tmpl") {
+		t.Fatalf("expected an unknown-template usage error, got %v", err)
+	}
+	if err := run("--template-dir", filepath.Join(dir, "missing")); err == nil || !strings.Contains(err.Error(), "template override dir") {
+		t.Fatalf("expected a missing-directory usage error, got %v", err)
+	}
+}
+
+func TestGenerateConfigExcludeFiles(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+	if err := run("--exclude-file", ".pylintrc", "--exclude-file", " .vscode/* ", "--exclude-file", ".pylintrc"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := strings.Join(captured.ExcludeFiles, ","); got != ".pylintrc,.vscode/*" {
+		t.Fatalf("ExcludeFiles = %s", got)
+	}
+	if err := run("--exclude-file", "[a-"); err == nil || !strings.Contains(err.Error(), "invalid exclude pattern") {
+		t.Fatalf("expected an invalid pattern error, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "excludeFiles", "excludeFiles", []any{"mypy.ini", "*.cfg"}); err != nil || strings.Join(cfg.ExcludeFiles, ",") != "mypy.ini,*.cfg" {
+		t.Fatalf("config excludeFiles: err=%v files=%v", err, cfg.ExcludeFiles)
+	}
+}
+
+func TestGenerateConfigSkip(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+	if err := run("--skip", "tests, lint", "--skip", "Editor"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	skip, err := emitter.ParseSkip(captured.Skip)
+	if err != nil || skip != (emitter.Skip{Tests: true, Lint: true, Editor: true}) {
+		t.Fatalf("Skip = %v (%+v, %v)", captured.Skip, skip, err)
+	}
+	if err := run("--skip", "docs"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), `--skip: unknown skip category "docs" (valid: tests, lint, makefile, readme, editor)`) {
+		t.Fatalf("expected a usage error for an unknown category, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "skip", "skip", []any{"makefile", "readme"}); err != nil || strings.Join(cfg.Skip, ",") != "makefile,readme" {
+		t.Fatalf("config skip: err=%v skip=%v", err, cfg.Skip)
+	}
+}
+
+func TestGenerateConfigWarningPolicy(t *testing.T) {
+	var captured *GenerateConfig
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { generateRunner = runGenerate })
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config", "generate", "--input", "spec.yaml"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("--deny-warning", "unresolved-ref,sw2mcp-w030", "--allow-warning", "SW2MCP-W030"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := strings.Join(captured.DenyWarnings, ","); got != "SW2MCP-W001,SW2MCP-W030" {
+		t.Errorf("DenyWarnings = %s", got)
+	}
+	if deny := deniedWarnings(captured); len(deny) != 1 || !deny[genspec.WarnUnresolvedRef] {
+		t.Errorf("denied = %v, want only %s", deny, genspec.WarnUnresolvedRef)
+	}
+	if err := run("--deny-warning", "all"); err != nil || len(captured.DenyWarnings) != len(genspec.WarningKinds()) {
+		t.Fatalf("--deny-warning all: err=%v got=%v", err, captured.DenyWarnings)
+	}
+	if err := run("--deny-warning", "SW2MCP-W999"); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), `unknown warning "SW2MCP-W999" in --deny-warning`) {
+		t.Fatalf("expected usage error for an unknown ID, got %v", err)
+	}
+
+	cfg := &GenerateConfig{}
+	if _, err := applyGenerateConfigValue(cfg, "denyWarnings", "denyWarnings", []any{"stale-file", "hook-failed"}); err != nil || len(cfg.DenyWarnings) != 2 {
+		t.Fatalf("config denyWarnings: err=%v got=%v", err, cfg.DenyWarnings)
+	}
+}
+
+func TestDiscoverConfigFile(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	nested := filepath.Join(repo, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir nested: %v", err)
+	}
+	if got := discoverConfigFile(nested); got != "" {
+		t.Fatalf("expected no config, got %q", got)
+	}
+
+	hidden := filepath.Join(repo, ".swagger2mcp.yaml")
+	if err := os.WriteFile(hidden, []byte("lang: npm\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if got := discoverConfigFile(nested); got != hidden {
+		t.Fatalf("expected %q from repo root, got %q", hidden, got)
+	}
+
+	// A closer file wins, and swagger2mcp.yaml is preferred over .yml.
+	yml := filepath.Join(repo, "a", "swagger2mcp.yml")
+	yaml := filepath.Join(repo, "a", "swagger2mcp.yaml")
+	for _, p := range []string{yml, yaml} {
+		if err := os.WriteFile(p, []byte("lang: npm\n"), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+	if got := discoverConfigFile(nested); got != yaml {
+		t.Fatalf("expected %q, got %q", yaml, got)
+	}
+}
+
+func TestGenerateConfigDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	discovered := filepath.Join(dir, "swagger2mcp.yaml")
+	if err := os.WriteFile(discovered, []byte("input: discovered.yaml\nlang: npm\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	explicit := filepath.Join(dir, "explicit.yaml")
+	if err := os.WriteFile(explicit, []byte("input: explicit.yaml\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	run := func(args ...string) *GenerateConfig {
+		t.Helper()
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		var captured *GenerateConfig
+		generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+			captured = cfg
+			return nil
+		}
+		t.Cleanup(func() { generateRunner = runGenerate })
+		root.SetArgs(args)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("execute %v: %v", args, err)
+		}
+		return captured
+	}
+
+	cfg := run("generate")
+	if cfg.Input != "discovered.yaml" || cfg.Lang != "npm" {
+		t.Fatalf("expected discovered config to apply, got %+v", cfg)
+	}
+	if got, _ := filepath.EvalSymlinks(cfg.ConfigPath); got != mustEvalSymlinks(t, discovered) {
+		t.Fatalf("config path: want %q got %q", discovered, cfg.ConfigPath)
+	}
+
+	cfg = run("--config", explicit, "generate")
+	if cfg.Input != "explicit.yaml" || cfg.Lang != "go" || cfg.ConfigPath != explicit {
+		t.Fatalf("expected explicit --config to take precedence, got %+v", cfg)
+	}
+
+	cfg = run("--no-config", "generate", "--input", "flag.yaml")
+	if cfg.Input != "flag.yaml" || cfg.Lang != "go" || cfg.ConfigPath != "" {
+		t.Fatalf("expected --no-config to skip discovery, got %+v", cfg)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, p string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatalf("eval symlinks: %v", err)
+	}
+	return resolved
+}
+
 func equalStringSlices(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateHooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	cases := []struct {
+		name    string
+		config  string
+		args    []string
+		setup   func(t *testing.T, outDir string)
+		wantErr string
+		check   func(t *testing.T, outDir string)
+	}{
+		{
+			name: "hooks receive env",
+			config: "hooks:\n" +
+				"  preGenerate:\n" +
+				"    - echo \"$SWAGGER2MCP_TOOL_NAME,$SWAGGER2MCP_LANG\" > pre.txt\n" +
+				"  postGenerate: echo \"$SWAGGER2MCP_OUT_DIR\" > post.txt\n",
+			args: []string{"--lang", "npm", "--tool-name", "hooked"},
+			check: func(t *testing.T, outDir string) {
+				pre, err := os.ReadFile(filepath.Join(outDir, "pre.txt"))
+				if err != nil {
+					t.Fatalf("pre hook output missing: %v", err)
+				}
+				if got := strings.TrimSpace(string(pre)); got != "hooked,npm" {
+					t.Fatalf("pre hook env: got %q", got)
+				}
+				post, err := os.ReadFile(filepath.Join(outDir, "post.txt"))
+				if err != nil {
+					t.Fatalf("post hook output missing: %v", err)
+				}
+				absOut, _ := filepath.Abs(outDir)
+				if got := strings.TrimSpace(string(post)); got != absOut {
+					t.Fatalf("post hook out dir: want %q got %q", absOut, got)
+				}
+			},
+		},
+		{
+			name:   "pre hook writes into out without force",
+			config: "hooks:\n  preGenerate: [\"touch pre.txt\"]\n",
+			check: func(t *testing.T, outDir string) {
+				for _, name := range []string{"pre.txt", "go.mod"} {
+					if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+						t.Fatalf("expected %s: %v", name, err)
+					}
+				}
+			},
+		},
+		{
+			name:   "non-empty out without force fails before hooks",
+			config: "hooks:\n  preGenerate: [\"touch pre.txt\"]\n",
+			setup: func(t *testing.T, outDir string) {
+				if err := os.MkdirAll(outDir, 0o755); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				if err := os.WriteFile(filepath.Join(outDir, "keep.txt"), nil, 0o600); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			},
+			wantErr: "not empty",
+			check: func(t *testing.T, outDir string) {
+				if _, err := os.Stat(filepath.Join(outDir, "pre.txt")); err == nil {
+					t.Fatalf("expected pre hook not to run")
+				}
+			},
+		},
+		{
+			name:    "pre hook failure aborts and removes created out dir",
+			config:  "hooks:\n  preGenerate: [\"touch partial.txt\", \"exit 3\"]\n",
+			wantErr: "preGenerate",
+			check: func(t *testing.T, outDir string) {
+				if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+					t.Fatalf("expected output directory to be removed, stat err=%v", err)
+				}
+			},
+		},
+		{
+			name:   "post hook failure keeps output",
+			config: "hooks:\n  postGenerate: [\"exit 1\"]\n",
+			check: func(t *testing.T, outDir string) {
+				if _, err := os.Stat(filepath.Join(outDir, "go.mod")); err != nil {
+					t.Fatalf("expected generated files to be kept: %v", err)
+				}
+			},
+		},
+		{
+			name:   "hooks skipped on dry-run",
+			config: "hooks:\n  preGenerate: [\"touch pre.txt\"]\n",
+			args:   []string{"--dry-run"},
+			check: func(t *testing.T, outDir string) {
+				if _, err := os.Stat(outDir); err == nil {
+					t.Fatalf("expected hooks to be skipped on dry-run")
+				}
+			},
+		},
+		{
+			name:    "unknown hook key",
+			config:  "hooks:\n  midGenerate: [\"true\"]\n",
+			wantErr: "unknown field",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specPath := filepath.Join(dir, "spec.yaml")
+			if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+				t.Fatalf("write spec: %v", err)
+			}
+			configPath := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tc.config), 0o600); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			outDir := filepath.Join(dir, "out")
+			if tc.setup != nil {
+				tc.setup(t, outDir)
+			}
+
+			root := NewRootCmd()
+			root.SetOut(io.Discard)
+			root.SetErr(io.Discard)
+			root.SetArgs(append([]string{"--config", configPath, "generate", "--input", specPath, "--out", outDir}, tc.args...))
+
+			var err error
+			captureStdout(func() { err = root.Execute() })
+			if tc.wantErr != "" {
+				if err == nil || !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected usage error containing %q, got %v", tc.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if tc.check != nil {
+				tc.check(t, outDir)
+			}
+		})
+	}
+}
@@ -91,12 +91,14 @@ func runInit(ctx context.Context, cfg *InitConfig) error {
 
 // sampleConfigYAML is a commented example config documenting available options.
 const sampleConfigYAML = `# swagger2mcp configuration (YAML)
-# All fields are optional. Command-line flags override config values.
+# All fields are optional. SWAGGER2MCP_<FIELD> environment variables (e.g.
+# SWAGGER2MCP_INCLUDE_TAGS=a,b) override config values; command-line flags override both.
 
 # Path or URL to the Swagger/OpenAPI document (http/https or local file).
+# A list merges several specs into one server.
 # input: ./openapi.yaml
 
-# Target language to emit (go|npm). Defaults to go when omitted.
+# Target language to emit (go|npm|python|postman|bruno|markdown|model). Defaults to go when omitted.
 # lang: go
 
 # Output directory. When omitted, derived from toolName or spec title.
@@ -108,18 +110,154 @@ const sampleConfigYAML = `# swagger2mcp configuration (YAML)
 # Exclude operations with these tags (comma-separated or list).
 # excludeTags: [internal]
 
+# Only embed schemas whose name matches one of these globs.
+# includeSchemas: [Pet*]
+
+# Drop schemas whose name matches any of these globs. Schemas that are still
+# referenced are kept as stubs marked excluded, with a warning.
+# excludeSchemas: [Audit*]
+
+# Drop operations whose x-* extension equals a value (booleans, strings, numbers).
+# dropExtensions: ["x-internal=true"]
+
+# Servers are listed public-first; localhost/private-network/.local and
+# x-internal servers are marked as development. keepAllServers keeps spec
+# order; dropDevServers removes development servers.
+# keepAllServers: false
+# dropDevServers: false
+
+# Per-endpoint summary/description/hidden/featured edits, keyed by endpoint ID
+# ("get /pets/{id}") or operationId, applied without touching the spec.
+# overrides: overrides.yaml
+
+# Reject specs larger than this (bytes, or with a KiB/MiB/GiB suffix).
+# maxSpecSize: 32MiB
+
 # Override tool binary/package name. Sanitized to lowercase/dash.
 # toolName: api-docs
 
 # Go: module name (e.g., example.com/mytool). npm: package name.
 # packageName: example.com/mytool
 
+# Go: go directive and mcp-go version of the generated go.mod (quote the Go
+# version so YAML keeps it a string).
+# goVersion: "1.24"
+# mcpLibVersion: v0.40.0
+
+# go/npm/python: server (default) emits a full MCP server project; library
+# emits only the spec package (typed model, loader and model.json).
+# layout: server
+
+# python: setuptools (default) emits setup.py and requirements*.txt; uv and
+# poetry declare everything in pyproject.toml and add a lock file placeholder.
+# pyBuildSystem: setuptools
+
+# python: the Python version mypy, black, ruff, pyupgrade and vermin target,
+# requires-python of the generated project (">=" + pythonVersion by default;
+# quote both so YAML keeps them strings), and an optional mcp SDK dependency:
+# a version to pin or a specifier such as ">=1.9,<2".
+# pythonVersion: "3.9"
+# pythonRequires: ">=3.9"
+# pyMcpVersion: "1.9.4"
+
+# go/npm/python: write a LICENSE file with this SPDX license (MIT,
+# Apache-2.0, BSD-2-Clause, BSD-3-Clause, ISC or MPL-2.0).
+# license: MIT
+
+# go/npm/python: author for the README and package metadata, also the
+# LICENSE copyright holder (default "Generated by swagger2mcp").
+# author: Jane Doe
+# authorEmail: jane@example.com
+
+# go/npm/python: semantic version of the generated package (default: the
+# spec's info.version when it is one, else 0.1.0).
+# projectVersion: 1.0.0
+
+# go/npm/python: write exact dependency versions: pinned package.json and
+# Python requirements, a go.mod listing mcp-go's full module graph plus go.sum.
+# pinDependencies: false
+
+# go/npm/python: add a callEndpoint tool that sends requests to the upstream
+# API (base URL from the spec's servers or API_BASE_URL); server layout only.
+# enableInvoke: false
+
+# go/npm/python: add docs/API.md, a Markdown reference of the endpoints and
+# schemas, with the same text for every language.
+# emitDocs: false
+
+# go/npm/python: add a multi-stage Dockerfile, a .dockerignore and Makefile
+# docker-build/docker-run targets; the image serves MCP over stdio.
+# emitDockerfile: false
+
+# go/npm/python: add a GitHub Actions workflow, .github/workflows/ci.yml, that
+# builds, lints and tests the project on every push and pull request.
+# ci: false
+
+# go/npm/python: trace each tool call with OpenTelemetry; go exports over OTLP
+# when OTEL_EXPORTER_OTLP_ENDPOINT is set, npm and python stay no-ops until an
+# SDK is registered.
+# withOTel: false
+
+# go/npm/python: split the embedded model into model/_index.json and one shard
+# per tag, read by the generated loader on demand.
+# shardModelByTag: false
+
+# go/npm/python: directory of files that replace the generated file at the
+# same relative path (e.g. README.md, cmd/<tool>/main.go), and of named
+# overrides of the built-in templates under <lang>/<name>.tmpl such as
+# go/README.md.tmpl or python/Makefile.tmpl; unknown names are an error.
+# templateDir: ./templates
+
+# go/npm/python: globs of generated files to leave out; files the project
+# needs to build (go.mod, package.json, ...) cannot be excluded.
+# excludeFiles: [.pylintrc, mypy.ini]
+
+# go/npm/python: categories of generated files to leave out (tests, lint,
+# makefile, readme, editor); the remaining files drop their references to them.
+# skip: [lint, editor]
+
+# npm: emit an ES module package (ES2022, output in dist/esm); false emits
+# CommonJS for runtimes that cannot load ES modules.
+# esm: true
+
+# npm: run the generated tests under vitest (default) or jest, which adds a
+# jest.config.js for ts-jest.
+# testRunner: vitest
+
+# model: write model.json on one line instead of indented.
+# compact: false
+
+# Pack the output into one archive at out instead of a directory: tar.gz or
+# zip. Entries are sorted and time-stamped 1980-01-01, so reruns match.
+# archive: tar.gz
+
 # Preview planned outputs without writing files.
 # dryRun: false
 
-# Overwrite non-empty output directory.
+# Overwrite non-empty output directory. Files edited since the last run and
+# files swagger2mcp did not generate are kept unless overwriteModified is set.
 # force: false
+# overwriteModified: false
+
+# Delete files the last run generated that are no longer produced.
+# prune: false
 
 # Enable verbose logging.
 # verbose: false
+
+# Result format on stdout (text|json). json prints one document for tooling.
+# output: text
+
+# Warnings that fail the run, by ID (SW2MCP-W030) or name
+# (authorization-header); "all" denies every warning. allowWarnings exempts
+# some of them.
+# denyWarnings: []
+# allowWarnings: []
+
+# Shell commands run in the output directory before/after files are written.
+# Each hook receives SWAGGER2MCP_OUT_DIR, SWAGGER2MCP_TOOL_NAME and SWAGGER2MCP_LANG.
+# A failing preGenerate hook aborts; postGenerate failures only warn. Skipped on dry-run.
+# hooks:
+#   preGenerate: ["echo generating $SWAGGER2MCP_TOOL_NAME"]
+#   postGenerate: ["go mod tidy"]
 `
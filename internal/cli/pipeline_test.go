@@ -1,12 +1,21 @@
 package cli
 
 import (
+    "archive/tar"
+    "archive/zip"
     "bytes"
+    "compress/gzip"
+    "encoding/json"
+    "errors"
+    "fmt"
     "io"
     "os"
     "path/filepath"
+    "reflect"
     "strings"
     "testing"
+
+    "github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
 )
 
 const minimalSpecYAML = "" +
@@ -86,3 +95,893 @@ func TestGeneratePipeline_DryRun_Npm(t *testing.T) {
         t.Fatalf("expected no writes on dry-run")
     }
 }
+
+func TestGeneratePipeline_Postman(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-postman")
+
+    root := NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"generate", "--input", specPath, "--lang", "postman", "--out", outDir})
+
+    captureStdout(func() {
+        if err := root.Execute(); err != nil {
+            t.Fatalf("execute: %v", err)
+        }
+    })
+    data, err := os.ReadFile(filepath.Join(outDir, "collection.json"))
+    if err != nil {
+        t.Fatalf("expected collection.json: %v", err)
+    }
+    if !strings.Contains(string(data), "\"name\": \"Hello\"") {
+        t.Fatalf("expected Hello item in collection, got: %s", data)
+    }
+}
+
+func TestGeneratePipeline_Bruno(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-bruno")
+
+    root := NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"generate", "--input", specPath, "--lang", "bruno", "--out", outDir})
+
+    captureStdout(func() {
+        if err := root.Execute(); err != nil {
+            t.Fatalf("execute: %v", err)
+        }
+    })
+    data, err := os.ReadFile(filepath.Join(outDir, "requests", "get-hello.bru"))
+    if err != nil {
+        t.Fatalf("expected requests/get-hello.bru: %v", err)
+    }
+    if !strings.Contains(string(data), "get {\n  url: {{baseUrl}}/hello\n") {
+        t.Fatalf("unexpected request file: %s", data)
+    }
+}
+
+func TestGeneratePipeline_Markdown(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-docs")
+
+    root := NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"generate", "--input", specPath, "--lang", "markdown", "--out", outDir})
+
+    captureStdout(func() {
+        if err := root.Execute(); err != nil {
+            t.Fatalf("execute: %v", err)
+        }
+    })
+    data, err := os.ReadFile(filepath.Join(outDir, "docs", "index.md"))
+    if err != nil {
+        t.Fatalf("expected docs/index.md: %v", err)
+    }
+    if !strings.Contains(string(data), "## Contents") {
+        t.Fatalf("unexpected index.md: %s", data)
+    }
+}
+
+func TestGeneratePipeline_Model(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-model")
+    run := func(extra ...string) (string, error) {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--lang", "model"}, extra...))
+        var err error
+        out := captureStdout(func() { err = root.Execute() })
+        return out, err
+    }
+
+    if _, err := run("--out", outDir); err != nil {
+        t.Fatalf("generate: %v", err)
+    }
+    entries, err := os.ReadDir(outDir)
+    if err != nil {
+        t.Fatalf("read out dir: %v", err)
+    }
+    for _, e := range entries {
+        if name := e.Name(); name != "model.json" && !strings.HasPrefix(name, ".swagger2mcp") {
+            t.Fatalf("unexpected file %s", name)
+        }
+    }
+    written, err := os.ReadFile(filepath.Join(outDir, "model.json"))
+    if err != nil {
+        t.Fatalf("read model.json: %v", err)
+    }
+    if !strings.Contains(string(written), "\n  \"Title\": \"Test API\"") {
+        t.Fatalf("model.json is not indented:\n%s", written)
+    }
+
+    printed, err := run("--out", "-", "--compact")
+    if err != nil {
+        t.Fatalf("generate --out -: %v", err)
+    }
+    var compacted bytes.Buffer
+    if err := json.Compact(&compacted, written); err != nil {
+        t.Fatalf("compact: %v", err)
+    }
+    if printed != compacted.String()+"\n" {
+        t.Fatalf("stdout:\n%s\nwant the compacted model.json:\n%s", printed, compacted.String())
+    }
+
+    for _, extra := range [][]string{
+        {"--out", "-", "--dry-run"},
+        {"--out", "-", "--output", "json"},
+    } {
+        if _, err := run(extra...); !errors.Is(err, ErrUsage) {
+            t.Fatalf("%v: expected usage error, got %v", extra, err)
+        }
+    }
+}
+
+func TestGeneratePipeline_MergedInputs(t *testing.T) {
+    dir := t.TempDir()
+    write := func(name, content string) string {
+        p := filepath.Join(dir, name)
+        if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+            t.Fatalf("write %s: %v", name, err)
+        }
+        return p
+    }
+    first := write("spec.yaml", minimalSpecYAML)
+    second := write("bye.yaml", strings.NewReplacer("Test API", "Bye API", "/hello", "/bye", "Hello", "Bye").Replace(minimalSpecYAML))
+    run := func(args ...string) (string, error) {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--lang", "model"}, args...))
+        var err error
+        out := captureStdout(func() { err = root.Execute() })
+        return out, err
+    }
+
+    printed, err := run("--input", first, "--input", second, "--out", "-")
+    if err != nil {
+        t.Fatalf("generate: %v", err)
+    }
+    var model struct {
+        Title     string
+        Endpoints []struct{ ID string }
+    }
+    if err := json.Unmarshal([]byte(printed), &model); err != nil {
+        t.Fatalf("decode model.json: %v\n%s", err, printed)
+    }
+    if model.Title != "Test API" || len(model.Endpoints) != 2 || model.Endpoints[0].ID != "get /bye" || model.Endpoints[1].ID != "get /hello" {
+        t.Fatalf("merged model: %+v", model)
+    }
+
+    outDir := filepath.Join(dir, "out")
+    if _, err := run("--input", first, "--input", second, "--out", outDir); err != nil {
+        t.Fatalf("generate: %v", err)
+    }
+    m, err := manifest.ReadManifest(outDir)
+    if err != nil {
+        t.Fatalf("read manifest: %v", err)
+    }
+    if m.Provenance == nil || m.Provenance.Input != first || !reflect.DeepEqual(m.Provenance.ExtraInputs, []string{second}) {
+        t.Fatalf("provenance: %+v", m.Provenance)
+    }
+
+    _, err = run("--input", first, "--input", second, "--input", write("again.yaml", minimalSpecYAML), "--out", "-")
+    if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "endpoint get /hello is defined by specs 1 and 3") {
+        t.Fatalf("expected a merge conflict, got %v", err)
+    }
+}
+
+// extractArchive returns the entries of a tar.gz or zip archive by path, as
+// "<mode> <content>".
+func extractArchive(t *testing.T, path string) map[string]string {
+    t.Helper()
+    out := map[string]string{}
+    if strings.HasSuffix(path, ".zip") {
+        zr, err := zip.OpenReader(path)
+        if err != nil {
+            t.Fatalf("open %s: %v", path, err)
+        }
+        defer zr.Close()
+        for _, f := range zr.File {
+            rc, err := f.Open()
+            if err != nil {
+                t.Fatalf("open %s: %v", f.Name, err)
+            }
+            b, _ := io.ReadAll(rc)
+            rc.Close()
+            out[f.Name] = fmt.Sprintf("%04o %s", f.Mode().Perm(), b)
+        }
+        return out
+    }
+    f, err := os.Open(path)
+    if err != nil {
+        t.Fatalf("open %s: %v", path, err)
+    }
+    defer f.Close()
+    gz, err := gzip.NewReader(f)
+    if err != nil {
+        t.Fatalf("gzip %s: %v", path, err)
+    }
+    tr := tar.NewReader(gz)
+    for {
+        hdr, err := tr.Next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            t.Fatalf("tar %s: %v", path, err)
+        }
+        b, _ := io.ReadAll(tr)
+        out[hdr.Name] = fmt.Sprintf("%04o %s", os.FileMode(hdr.Mode).Perm(), b)
+    }
+    return out
+}
+
+func TestGeneratePipeline_Archive(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    run := func(args ...string) (string, error) {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath}, args...))
+        var err error
+        out := captureStdout(func() { err = root.Execute() })
+        return out, err
+    }
+
+    for _, c := range []struct{ lang, format string }{{"python", "tar.gz"}, {"npm", "zip"}, {"go", "tar.gz"}} {
+        direct := filepath.Join(dir, c.lang+"-dir")
+        if _, err := run("--lang", c.lang, "--out", direct); err != nil {
+            t.Fatalf("%s: generate: %v", c.lang, err)
+        }
+        want := map[string]string{}
+        err := filepath.WalkDir(direct, func(p string, d os.DirEntry, err error) error {
+            if err != nil || d.IsDir() || d.Name() == ".swagger2mcp-manifest.json" {
+                return err
+            }
+            info, err := d.Info()
+            if err != nil {
+                return err
+            }
+            b, err := os.ReadFile(p)
+            if err != nil {
+                return err
+            }
+            rel, _ := filepath.Rel(direct, p)
+            want[filepath.ToSlash(rel)] = fmt.Sprintf("%04o %s", info.Mode().Perm(), b)
+            return nil
+        })
+        if err != nil {
+            t.Fatalf("%s: walk: %v", c.lang, err)
+        }
+
+        path := filepath.Join(dir, "dist", c.lang+"."+c.format)
+        out, err := run("--lang", c.lang, "--out", path, "--archive", c.format, "--dry-run")
+        if err != nil {
+            t.Fatalf("%s: dry-run: %v", c.lang, err)
+        }
+        if head := fmt.Sprintf("Planned archive %s (%s, %d entries):\n", path, c.format, len(want)); !strings.HasPrefix(out, head) {
+            t.Fatalf("%s: dry-run output:\n%s\nwant it to start with %q", c.lang, out, head)
+        }
+        if _, err := os.Stat(path); !os.IsNotExist(err) {
+            t.Fatalf("%s: dry-run wrote the archive: %v", c.lang, err)
+        }
+
+        if _, err := run("--lang", c.lang, "--out", path, "--archive", c.format); err != nil {
+            t.Fatalf("%s: archive: %v", c.lang, err)
+        }
+        got := extractArchive(t, path)
+        if len(got) != len(want) {
+            t.Errorf("%s: archive has %d entries, the directory %d files", c.lang, len(got), len(want))
+        }
+        for rel, w := range want {
+            if got[rel] != w {
+                t.Errorf("%s: %s differs from the directory emit:\n got %.80q\nwant %.80q", c.lang, rel, got[rel], w)
+            }
+        }
+
+        first, _ := os.ReadFile(path)
+        if _, err := run("--lang", c.lang, "--out", path, "--archive", c.format); err == nil || !strings.Contains(err.Error(), "already exists") {
+            t.Fatalf("%s: expected an existing-archive error, got %v", c.lang, err)
+        }
+        if _, err := run("--lang", c.lang, "--out", path, "--archive", c.format, "--force"); err != nil {
+            t.Fatalf("%s: archive --force: %v", c.lang, err)
+        }
+        if second, _ := os.ReadFile(path); !bytes.Equal(first, second) {
+            t.Errorf("%s: rerun wrote a different archive", c.lang)
+        }
+    }
+
+    for _, extra := range [][]string{
+        {"--archive", "rar"},
+        {"--archive", "zip", "--verify"},
+        {"--archive", "zip", "--watch"},
+        {"--archive", "zip", "--lang", "model", "--out", "-"},
+    } {
+        if _, err := run(extra...); !errors.Is(err, ErrUsage) {
+            t.Fatalf("%v: expected usage error, got %v", extra, err)
+        }
+    }
+}
+
+func TestGeneratePipeline_SkipsUnchangedSpec(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-docs")
+    run := func(extra ...string) error {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--lang", "markdown", "--out", outDir}, extra...))
+        var err error
+        captureStdout(func() { err = root.Execute() })
+        return err
+    }
+    if err := run(); err != nil {
+        t.Fatalf("first run: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(outDir, ".swagger2mcp-manifest.json")); err != nil {
+        t.Fatalf("expected manifest: %v", err)
+    }
+    // Without the manifest this would fail the non-empty output check.
+    if err := run(); err != nil {
+        t.Fatalf("second run should skip unchanged output: %v", err)
+    }
+    if err := run("--lang", "postman"); err == nil || !strings.Contains(err.Error(), "not empty") {
+        t.Fatalf("different lang should not be skipped, got %v", err)
+    }
+}
+
+func TestGeneratePipeline_DryRunReportsChanges(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-docs")
+    run := func(extra ...string) string {
+        t.Helper()
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--lang", "markdown", "--out", outDir}, extra...))
+        return captureStdout(func() {
+            if err := root.Execute(); err != nil {
+                t.Fatalf("execute: %v", err)
+            }
+        })
+    }
+    if out := run("--dry-run"); !strings.Contains(out, "- docs/index.md (create)\n") {
+        t.Fatalf("expected create entries, got: %s", out)
+    }
+    run()
+    if out := run("--dry-run"); !strings.Contains(out, "no changes") {
+        t.Fatalf("expected no changes for up-to-date output, got: %s", out)
+    }
+    if err := os.WriteFile(filepath.Join(outDir, "docs", "index.md"), []byte("edited\n"), 0o644); err != nil {
+        t.Fatalf("edit: %v", err)
+    }
+    out := run("--dry-run")
+    if !strings.Contains(out, "- docs/index.md (update)\n") || !strings.Contains(out, "(unchanged)") || !strings.Contains(out, "1 changed") {
+        t.Fatalf("expected one update among unchanged files, got: %s", out)
+    }
+}
+
+type jsonReport struct {
+    Config struct {
+        Input  string `json:"input"`
+        Lang   string `json:"lang"`
+        Out    string `json:"out"`
+        DryRun bool   `json:"dryRun"`
+    } `json:"config"`
+    ToolName    string `json:"toolName"`
+    PackageName string `json:"packageName"`
+    Skipped     bool   `json:"skipped"`
+    Plan        []struct {
+        Path string `json:"path"`
+        Size   int    `json:"size"`
+        Mode   string `json:"mode"`
+        SHA256 string `json:"sha256"`
+        Change string `json:"change"`
+    } `json:"plan"`
+    Warnings []string `json:"warnings"`
+    Pruned   []string `json:"pruned"`
+}
+
+func TestGeneratePipeline_OutputJSON(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-go")
+    run := func(extra ...string) jsonReport {
+        t.Helper()
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--out", outDir, "--package-name", "example.com/hello", "--output", "json"}, extra...))
+        out := captureStdout(func() {
+            if err := root.Execute(); err != nil {
+                t.Fatalf("execute: %v", err)
+            }
+        })
+        // stdout must hold exactly one JSON document.
+        var rep jsonReport
+        dec := json.NewDecoder(strings.NewReader(out))
+        if err := dec.Decode(&rep); err != nil {
+            t.Fatalf("decode report: %v\n%s", err, out)
+        }
+        if dec.More() {
+            t.Fatalf("unexpected output after the report:\n%s", out)
+        }
+        return rep
+    }
+
+    rep := run("--dry-run")
+    if rep.Config.Input != specPath || rep.Config.Lang != "go" || rep.Config.Out != outDir || !rep.Config.DryRun {
+        t.Errorf("unexpected config: %+v", rep.Config)
+    }
+    if rep.ToolName != "test-api" || rep.PackageName != "example.com/hello" {
+        t.Errorf("unexpected names: tool=%q package=%q", rep.ToolName, rep.PackageName)
+    }
+    found := false
+    for _, p := range rep.Plan {
+        if p.Path == "go.mod" {
+            found = p.Size > 0 && p.Mode == "0644" && p.Change == "create" && len(p.SHA256) == 64
+        }
+    }
+    if !found {
+        t.Errorf("expected go.mod in plan: %+v", rep.Plan)
+    }
+    if rep.Warnings == nil {
+        t.Errorf("expected warnings to be an empty array, not null")
+    }
+    if _, err := os.Stat(outDir); err == nil {
+        t.Fatalf("expected no writes on dry-run")
+    }
+
+    if rep := run(); rep.Skipped || len(rep.Plan) == 0 {
+        t.Errorf("expected written files in report: %+v", rep)
+    }
+    if rep := run(); !rep.Skipped || len(rep.Plan) != 0 {
+        t.Errorf("expected unchanged spec to be reported as skipped: %+v", rep)
+    }
+}
+
+func TestGeneratePipeline_SkipsOnlyUnchangedOutput(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    tplDir := filepath.Join(dir, "templates")
+    if err := os.MkdirAll(tplDir, 0o755); err != nil {
+        t.Fatal(err)
+    }
+    readmeTpl := filepath.Join(tplDir, "README.md")
+    if err := os.WriteFile(readmeTpl, []byte("# v1\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    outDir := filepath.Join(dir, "out-go")
+    run := func(extra ...string) (jsonReport, error) {
+        t.Helper()
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--out", outDir, "--package-name", "example.com/hello", "--template-dir", tplDir, "--output", "json"}, extra...))
+        var err error
+        out := captureStdout(func() { err = root.Execute() })
+        var rep jsonReport
+        if err == nil {
+            if derr := json.NewDecoder(strings.NewReader(out)).Decode(&rep); derr != nil {
+                t.Fatalf("decode report: %v\n%s", derr, out)
+            }
+        }
+        return rep, err
+    }
+    // notSkipped asserts the run was not skipped: it went on to write over
+    // the existing output and stopped at the non-empty directory check.
+    notSkipped := func(why string, extra ...string) {
+        t.Helper()
+        if rep, err := run(extra...); err == nil || !strings.Contains(err.Error(), "not empty") {
+            t.Fatalf("%s: expected a regenerate that hits the non-empty check, got skipped=%v err=%v", why, rep.Skipped, err)
+        }
+    }
+
+    if rep, err := run(); err != nil || rep.Skipped {
+        t.Fatalf("first run: skipped=%v err=%v", rep.Skipped, err)
+    }
+    if rep, err := run(); err != nil || !rep.Skipped {
+        t.Fatalf("unchanged rerun should skip: skipped=%v err=%v", rep.Skipped, err)
+    }
+    // run-control flags such as --verbose do not shape the output.
+    if rep, err := run("--verbose"); err != nil || !rep.Skipped {
+        t.Fatalf("--verbose rerun should skip: skipped=%v err=%v", rep.Skipped, err)
+    }
+
+    notSkipped("--license", "--license", "MIT")
+    if rep, err := run("--license", "MIT", "--force"); err != nil || rep.Skipped {
+        t.Fatalf("forced --license run: skipped=%v err=%v", rep.Skipped, err)
+    }
+    if rep, err := run("--license", "MIT"); err != nil || !rep.Skipped {
+        t.Fatalf("--license rerun should skip: skipped=%v err=%v", rep.Skipped, err)
+    }
+
+    if err := os.Remove(filepath.Join(outDir, "go.mod")); err != nil {
+        t.Fatal(err)
+    }
+    notSkipped("deleted go.mod", "--license", "MIT")
+    if _, err := run("--license", "MIT", "--force"); err != nil {
+        t.Fatalf("forced run after deleting go.mod: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(outDir, "go.mod")); err != nil {
+        t.Fatalf("--force should restore go.mod: %v", err)
+    }
+
+    if err := os.WriteFile(readmeTpl, []byte("# v2\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    notSkipped("edited override template", "--license", "MIT")
+    if _, err := run("--license", "MIT", "--force"); err != nil {
+        t.Fatalf("forced run after editing the template: %v", err)
+    }
+    if b, _ := os.ReadFile(filepath.Join(outDir, "README.md")); string(b) != "# v2\n" {
+        t.Fatalf("README.md should come from the edited template, got %q", b)
+    }
+}
+
+func TestGeneratePipeline_OutputInvalid(t *testing.T) {
+    root := NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"--no-config", "generate", "--input", "spec.yaml", "--output", "yaml"})
+    err := root.Execute()
+    if err == nil || !strings.Contains(err.Error(), "unsupported --output") {
+        t.Fatalf("expected unsupported --output error, got %v", err)
+    }
+}
+
+func TestGeneratePipeline_ForceProtectsEditsAndPrunes(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    withSchema := minimalSpecYAML +
+        "components:\n" +
+        "  schemas:\n" +
+        "    Greeting:\n" +
+        "      type: string\n"
+    if err := os.WriteFile(specPath, []byte(withSchema), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out-md")
+    run := func(extra ...string) jsonReport {
+        t.Helper()
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--lang", "markdown", "--out", outDir, "--output", "json"}, extra...))
+        var rep jsonReport
+        out := captureStdout(func() {
+            if err := root.Execute(); err != nil {
+                t.Fatalf("execute: %v", err)
+            }
+        })
+        if err := json.Unmarshal([]byte(out), &rep); err != nil {
+            t.Fatalf("decode report: %v\n%s", err, out)
+        }
+        return rep
+    }
+    run()
+
+    index := filepath.Join(outDir, "docs", "index.md")
+    schemas := filepath.Join(outDir, "docs", "schemas.md")
+    if err := os.WriteFile(index, []byte("edited"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("rewrite spec: %v", err)
+    }
+
+    rep := run("--force")
+    joined := strings.Join(rep.Warnings, "\n")
+    if !strings.Contains(joined, "kept docs/index.md") || !strings.Contains(joined, "docs/schemas.md is no longer generated") {
+        t.Fatalf("expected kept and stale warnings, got %q", rep.Warnings)
+    }
+    if b, _ := os.ReadFile(index); string(b) != "edited" {
+        t.Fatalf("--force must keep the edited index.md")
+    }
+    if _, err := os.Stat(schemas); err != nil {
+        t.Fatalf("stale schemas.md should stay without --prune: %v", err)
+    }
+
+    rep = run("--force", "--overwrite-modified", "--prune")
+    if len(rep.Pruned) != 1 || rep.Pruned[0] != "docs/schemas.md" {
+        t.Fatalf("expected schemas.md to be pruned, got %+v", rep)
+    }
+    if _, err := os.Stat(schemas); !os.IsNotExist(err) {
+        t.Fatalf("schemas.md should be removed, stat err=%v", err)
+    }
+    if b, _ := os.ReadFile(index); string(b) == "edited" {
+        t.Fatalf("--overwrite-modified should rewrite index.md")
+    }
+}
+
+func TestGeneratePipeline_Verify(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out")
+    run := func(args ...string) (string, error) {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--out", outDir, "--tool-name", "hello"}, args...))
+        var err error
+        out := captureStdout(func() { err = root.Execute() })
+        return out, err
+    }
+    if _, err := run(); err != nil {
+        t.Fatalf("generate: %v", err)
+    }
+
+    out, err := run("--verify")
+    if err != nil {
+        t.Fatalf("fresh output should verify: %v\n%s", err, out)
+    }
+    if !strings.Contains(out, "0 modified, 0 missing, 0 extra") {
+        t.Fatalf("unexpected verify output:\n%s", out)
+    }
+
+    readme := filepath.Join(outDir, "README.md")
+    if err := os.WriteFile(readme, []byte("tampered\n"), 0o644); err != nil {
+        t.Fatalf("tamper: %v", err)
+    }
+    out, err = run("--verify")
+    if err == nil || !strings.Contains(err.Error(), "does not match the spec (1 modified") {
+        t.Fatalf("expected a verify failure, got %v\n%s", err, out)
+    }
+    if !strings.Contains(out, "- modified README.md\n") {
+        t.Fatalf("tampered file not reported by name:\n%s", out)
+    }
+    if b, _ := os.ReadFile(readme); string(b) != "tampered\n" {
+        t.Fatalf("--verify must not write: README.md = %q", b)
+    }
+
+    out, _ = run("--verify", "--output", "json")
+    var rep struct {
+        Verify struct {
+            Modified []string `json:"modified"`
+            Matched  []string `json:"matched"`
+        } `json:"verify"`
+    }
+    if err := json.Unmarshal([]byte(out), &rep); err != nil {
+        t.Fatalf("decode report: %v\n%s", err, out)
+    }
+    if len(rep.Verify.Modified) != 1 || rep.Verify.Modified[0] != "README.md" || len(rep.Verify.Matched) == 0 {
+        t.Fatalf("json verify = %+v", rep.Verify)
+    }
+}
+
+func TestGeneratePipeline_MaxSpecSize(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    outDir := filepath.Join(dir, "out")
+
+    root := NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"--no-config", "generate", "--input", specPath, "--out", outDir, "--max-spec-size", "64"})
+    err := root.Execute()
+    if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "exceeds the maximum size of 64 bytes") {
+        t.Fatalf("expected a usage error for an oversized spec, got %v", err)
+    }
+    if _, statErr := os.Stat(outDir); !os.IsNotExist(statErr) {
+        t.Fatalf("nothing should be written for a rejected spec: %v", statErr)
+    }
+}
+
+func TestGeneratePipeline_Overrides(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    spec := minimalSpecYAML +
+        "  /internal:\n" +
+        "    get:\n" +
+        "      operationId: internalStatus\n" +
+        "      responses:\n" +
+        "        '200':\n" +
+        "          description: ok\n"
+    if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    overridesPath := filepath.Join(dir, "overrides.yaml")
+    overrides := "" +
+        "get /hello:\n" +
+        "  summary: Greets the caller\n" +
+        "  featured: true\n" +
+        "internalStatus:\n" +
+        "  hidden: true\n" +
+        "get /missing: {summary: nobody}\n"
+    if err := os.WriteFile(overridesPath, []byte(overrides), 0o600); err != nil {
+        t.Fatalf("write overrides: %v", err)
+    }
+    outDir := filepath.Join(dir, "out")
+
+    root := NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"--no-config", "generate", "--input", specPath, "--lang", "markdown", "--out", outDir, "--overrides", overridesPath, "--output", "json"})
+    out := captureStdout(func() {
+        if err := root.Execute(); err != nil {
+            t.Fatalf("execute: %v", err)
+        }
+    })
+    var rep jsonReport
+    if err := json.Unmarshal([]byte(out), &rep); err != nil {
+        t.Fatalf("decode report: %v\n%s", err, out)
+    }
+    if len(rep.Warnings) != 1 || rep.Warnings[0] != `override "get /missing" matches no endpoint` {
+        t.Errorf("warnings = %q", rep.Warnings)
+    }
+    var doc strings.Builder
+    err := filepath.WalkDir(outDir, func(path string, d os.DirEntry, err error) error {
+        if err != nil || d.IsDir() || !strings.HasSuffix(path, ".md") {
+            return err
+        }
+        b, err := os.ReadFile(path)
+        doc.Write(b)
+        return err
+    })
+    if err != nil {
+        t.Fatalf("read markdown: %v", err)
+    }
+    if !strings.Contains(doc.String(), "Greets the caller") || strings.Contains(doc.String(), "/internal") {
+        t.Fatalf("overrides not applied:\n%s", doc.String())
+    }
+
+    // A missing overrides file is a usage error.
+    root = NewRootCmd()
+    root.SetOut(io.Discard)
+    root.SetErr(io.Discard)
+    root.SetArgs([]string{"--no-config", "generate", "--input", specPath, "--out", outDir, "--overrides", filepath.Join(dir, "nope.yaml"), "--dry-run"})
+    if err := root.Execute(); !errors.Is(err, ErrUsage) {
+        t.Fatalf("expected usage error for a missing overrides file, got %v", err)
+    }
+}
+
+// A spec without components runs through every emitter and the analysis
+// commands, and each model.json writes "Schemas": {} rather than null.
+func TestGeneratePipeline_NoSchemas(t *testing.T) {
+    specPath := filepath.Join("testdata", "noschemas.yaml")
+    run := func(args ...string) error {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config"}, args...))
+        var err error
+        captureStdout(func() { err = root.Execute() })
+        return err
+    }
+
+    modelJSON := map[string]string{
+        "go":     filepath.Join("internal", "spec", "model.json"),
+        "npm":    filepath.Join("src", "spec", "model.json"),
+        "python": filepath.Join("src", "inline_only", "spec", "model.json"),
+    }
+    for _, lang := range []string{"go", "npm", "python", "postman", "bruno", "markdown"} {
+        outDir := filepath.Join(t.TempDir(), lang)
+        if err := run("generate", "--input", specPath, "--lang", lang, "--out", outDir, "--tool-name", "inline-only"); err != nil {
+            t.Fatalf("%s: generate: %v", lang, err)
+        }
+        rel, ok := modelJSON[lang]
+        if !ok {
+            continue
+        }
+        data, err := os.ReadFile(filepath.Join(outDir, rel))
+        if err != nil {
+            t.Fatalf("%s: read model.json: %v", lang, err)
+        }
+        var model struct{ Schemas json.RawMessage }
+        if err := json.Unmarshal(data, &model); err != nil {
+            t.Fatalf("%s: model.json: %v", lang, err)
+        }
+        if string(model.Schemas) != "{}" {
+            t.Fatalf("%s: model.json Schemas = %s, want {}", lang, model.Schemas)
+        }
+    }
+
+    for _, args := range [][]string{
+        {"validate", "--input", specPath},
+        {"stats", "--input", specPath, "--json"},
+        {"diff", "--old", specPath, "--new", specPath},
+    } {
+        if err := run(args...); err != nil {
+            t.Fatalf("%v: %v", args, err)
+        }
+    }
+}
+
+func TestGeneratePipeline_DenyWarning(t *testing.T) {
+    dir := t.TempDir()
+    specPath := filepath.Join(dir, "spec.yaml")
+    spec := "openapi: 3.0.0\ninfo:\n  title: Auth\n  version: '1'\npaths:\n  /me:\n    get:\n      parameters:\n        - { in: header, name: Authorization, schema: { type: string } }\n      responses: { '200': { description: ok } }\n"
+    if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    run := func(outDir string, extra ...string) (string, error) {
+        root := NewRootCmd()
+        root.SetOut(io.Discard)
+        root.SetErr(io.Discard)
+        root.SetArgs(append([]string{"--no-config", "generate", "--input", specPath, "--lang", "markdown", "--out", outDir, "--output", "json"}, extra...))
+        var err error
+        out := captureStdout(func() { err = root.Execute() })
+        return out, err
+    }
+
+    denied := filepath.Join(dir, "denied")
+    out, err := run(denied, "--deny-warning", "all")
+    if err == nil || !strings.Contains(err.Error(), "SW2MCP-W030") {
+        t.Fatalf("expected the Authorization header warning to fail the run, got %v", err)
+    }
+    var rep struct {
+        Warnings    []string `json:"warnings"`
+        Diagnostics []struct {
+            ID       string `json:"id"`
+            Name     string `json:"name"`
+            Category string `json:"category"`
+            Pointer  string `json:"pointer"`
+            Denied   bool   `json:"denied"`
+        } `json:"diagnostics"`
+    }
+    if err := json.Unmarshal([]byte(out), &rep); err != nil {
+        t.Fatalf("decode report: %v\n%s", err, out)
+    }
+    if len(rep.Diagnostics) != 1 || len(rep.Warnings) != 1 {
+        t.Fatalf("expected one warning, got %s", out)
+    }
+    if d := rep.Diagnostics[0]; d.ID != "SW2MCP-W030" || d.Name != "authorization-header" || d.Category != "parameter" || d.Pointer != "#/paths/~1me/get" || !d.Denied {
+        t.Errorf("unexpected diagnostic: %+v", d)
+    }
+    if _, err := os.Stat(denied); err == nil {
+        t.Errorf("a denied warning must fail the run before anything is written")
+    }
+
+    // Allowed warnings are still reported, but do not fail the run.
+    allowed := filepath.Join(dir, "allowed")
+    if _, err := run(allowed, "--deny-warning", "all", "--allow-warning", "authorization-header"); err != nil {
+        t.Fatalf("allowed warning failed the run: %v", err)
+    }
+    if _, err := os.Stat(allowed); err != nil {
+        t.Errorf("expected output: %v", err)
+    }
+}
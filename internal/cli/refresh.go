@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/modelshard"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+// RefreshConfig captures the options for the refresh-model command. Model is
+// built from the recorded provenance with the flags the user set on top.
+type RefreshConfig struct {
+	Dir     string
+	Force   bool
+	Verbose bool
+	Model   GenerateConfig
+}
+
+var refreshRunner = runRefreshModel
+
+func newRefreshModelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refresh-model",
+		Short: "Rebuild the embedded model.json of a generated project from an updated spec",
+		Long: "Rebuild the service model with the input and filters recorded in the project's manifest " +
+			"and rewrite only its model.json, leaving the generated code alone. Flags override the " +
+			"recorded values. Projects written by a different major version of swagger2mcp are refused " +
+			"unless --force is set.",
+		Example: strings.TrimSpace(`  swagger2mcp refresh-model --dir ./petstore-mcp
+  swagger2mcp refresh-model --dir ./petstore-mcp --input petstore-v2.yaml --exclude-tags internal`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := resolveRefreshConfig(cmd)
+			if err != nil {
+				return err
+			}
+			return refreshRunner(cmd.Context(), cfg, cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String("dir", "", "Directory of the generated project (holds "+manifest.FileName+")")
+	flags.StringArray("input", nil, "Path or URL to the Swagger/OpenAPI document (repeatable; default: the recorded inputs)")
+	flags.StringSlice("include-tags", nil, "Only include operations with these tags")
+	flags.StringSlice("exclude-tags", nil, "Exclude operations with these tags")
+	flags.StringSlice("include-schemas", nil, "Only embed schemas whose name matches one of these globs")
+	flags.StringSlice("exclude-schemas", nil, "Drop schemas whose name matches one of these globs")
+	flags.StringArray("drop-extension", nil, "Drop operations whose x-* extension equals a value, e.g. x-internal=true (repeatable)")
+	flags.Bool("keep-all-servers", false, "Keep servers in spec order instead of listing public servers before localhost/private ones")
+	flags.Bool("drop-dev-servers", false, "Drop localhost, private-network, .local and x-internal servers from the output")
+	flags.String("overrides", "", "YAML file mapping endpoint IDs or operationIds to summary/description/hidden/featured overrides")
+	flags.String("max-spec-size", "", "Largest spec accepted from a file or URL, e.g. 64MiB (default 32MiB)")
+	flags.Bool("force", false, "Refresh despite a generator major version mismatch, a missing provenance record or an edited model.json")
+
+	return cmd
+}
+
+func resolveRefreshConfig(cmd *cobra.Command) (*RefreshConfig, error) {
+	flags := cmd.Flags()
+	cfg := &RefreshConfig{}
+	var err error
+	if cfg.Dir, err = flags.GetString("dir"); err != nil {
+		return nil, err
+	}
+	if cfg.Force, err = flags.GetBool("force"); err != nil {
+		return nil, err
+	}
+	if cfg.Verbose, err = flags.GetBool("verbose"); err != nil {
+		return nil, err
+	}
+	cfg.Dir = strings.TrimSpace(cfg.Dir)
+	if cfg.Dir == "" {
+		return nil, newUsageError("refresh-model: --dir is required")
+	}
+
+	m, err := manifest.ReadManifest(cfg.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, newUsageError(fmt.Sprintf("refresh-model: %s has no %s; generate the project first", cfg.Dir, manifest.FileName))
+	}
+	if err != nil {
+		return nil, newUsageError(fmt.Sprintf("refresh-model: %v", err))
+	}
+	prov := m.Provenance
+	switch {
+	case prov == nil && !cfg.Force:
+		return nil, newUsageError(fmt.Sprintf("refresh-model: %s does not record how its model was built (written by an older swagger2mcp); regenerate it, or pass --force to refresh with the given flags only", cfg.Dir))
+	case prov == nil:
+		prov = &manifest.Provenance{}
+	case majorVersion(prov.Generator) != majorVersion(Version) && !cfg.Force:
+		return nil, newUsageError(fmt.Sprintf("refresh-model: %s was generated by swagger2mcp %s, this is %s; regenerate it, or pass --force to refresh anyway", cfg.Dir, prov.Generator, Version))
+	}
+	if cfg.Model, err = provenanceConfig(prov); err != nil {
+		return nil, err
+	}
+	if err := applyModelFlagOverrides(flags, &cfg.Model); err != nil {
+		return nil, err
+	}
+	if cfg.Model.Input == "" {
+		return nil, newUsageError("refresh-model: --input is required (the manifest records no input)")
+	}
+	if overlap := intersect(cfg.Model.IncludeTags, cfg.Model.ExcludeTags); len(overlap) > 0 {
+		return nil, newUsageError(fmt.Sprintf("refresh-model: include/exclude tags overlap: %s", strings.Join(overlap, ", ")))
+	}
+	if cfg.Model.KeepAllServers && cfg.Model.DropDevServers {
+		return nil, newUsageError("refresh-model: --keep-all-servers and --drop-dev-servers are mutually exclusive")
+	}
+	cfg.Model.Lang = m.Lang
+	cfg.Model.Verbose = cfg.Verbose
+	return cfg, nil
+}
+
+// provenanceConfig turns a recorded provenance back into generate's model
+// settings.
+func provenanceConfig(p *manifest.Provenance) (GenerateConfig, error) {
+	preds, err := parseExtensionPredicates(p.DropExtensions)
+	if err != nil {
+		return GenerateConfig{}, err
+	}
+	return GenerateConfig{
+		Input:          p.Input,
+		ExtraInputs:    p.ExtraInputs,
+		IncludeTags:    p.IncludeTags,
+		ExcludeTags:    p.ExcludeTags,
+		IncludeSchemas: p.IncludeSchemas,
+		ExcludeSchemas: p.ExcludeSchemas,
+		DropExtensions: preds,
+		KeepAllServers: p.KeepAllServers,
+		DropDevServers: p.DropDevServers,
+		Overrides:      p.Overrides,
+	}, nil
+}
+
+func runRefreshModel(ctx context.Context, cfg *RefreshConfig, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	m, err := manifest.ReadManifest(cfg.Dir)
+	if err != nil {
+		return newUsageError(fmt.Sprintf("refresh-model: %v", err))
+	}
+	// The data files are found through the manifest, which covers every
+	// language and layout (and Python's package-named spec directory): a
+	// model.json, or with --shard-model-by-tag a model/_index.json and the
+	// shards next to it.
+	var data []int
+	var shardDirs []string
+	for i, f := range m.Files {
+		switch {
+		case path.Base(f.Path) == "model.json":
+			data = append(data, i)
+		case path.Base(f.Path) == modelshard.IndexFile && path.Base(path.Dir(f.Path)) == modelshard.Dir:
+			shardDirs = append(shardDirs, path.Dir(f.Path))
+		}
+	}
+	for _, dir := range shardDirs {
+		for i, f := range m.Files {
+			if path.Dir(f.Path) == dir && path.Ext(f.Path) == ".json" {
+				data = append(data, i)
+			}
+		}
+	}
+	if len(data) == 0 {
+		return newUsageError(fmt.Sprintf("refresh-model: %s (lang %s) has no embedded model.json or model/%s", cfg.Dir, m.Lang, modelshard.IndexFile))
+	}
+	if !cfg.Force {
+		for _, i := range data {
+			f := m.Files[i]
+			content, err := os.ReadFile(filepath.Join(cfg.Dir, filepath.FromSlash(f.Path)))
+			if err == nil && manifest.HashBytes(content) != f.SHA256 {
+				return newUsageError(fmt.Sprintf("refresh-model: %s was edited since it was generated; pass --force to replace it", f.Path))
+			}
+		}
+	}
+
+	loaded, sm, err := buildGenerateModel(ctx, &cfg.Model)
+	if err != nil {
+		return err
+	}
+	if cfg.Verbose {
+		for _, w := range loaded.Warnings {
+			fmt.Fprintf(os.Stderr, "[WARN] %s\n", w)
+		}
+	}
+	for _, w := range sm.Warnings {
+		fmt.Fprintf(os.Stderr, "[WARN] %s\n", w)
+	}
+	written, err := refreshedData(m, data, shardDirs, sm)
+	if err != nil {
+		return err
+	}
+
+	isData := map[string]bool{}
+	for _, i := range data {
+		rel := m.Files[i].Path
+		isData[rel] = true
+		if written[rel] == nil {
+			// A shard of a tag the model no longer has.
+			if err := os.Remove(filepath.Join(cfg.Dir, filepath.FromSlash(rel))); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return wrapOutputError(fmt.Errorf("remove %s: %w", rel, err), cfg.Dir)
+			}
+			fmt.Fprintf(out, "[INFO] removed %s\n", rel)
+		}
+	}
+	var files []manifest.File
+	for _, f := range m.Files {
+		if !isData[f.Path] {
+			files = append(files, f)
+		}
+	}
+	m.Files = files
+	rels := make([]string, 0, len(written))
+	for rel := range written {
+		rels = append(rels, rel)
+	}
+	genspec.SortNames(rels)
+	for _, rel := range rels {
+		if err := filewriter.WriteFileAtomic(cfg.Dir, rel, written[rel], 0o644); err != nil {
+			return wrapOutputError(fmt.Errorf("write %s: %w", rel, err), cfg.Dir)
+		}
+		m.Files = append(m.Files, manifest.File{Path: rel, SHA256: manifest.HashBytes(written[rel])})
+		fmt.Fprintf(out, "[INFO] refreshed %s\n", rel)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	m.SpecHash = manifest.SpecHash(sm)
+	m.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	m.Provenance = generateProvenance(&cfg.Model)
+	if err := manifest.WriteManifest(cfg.Dir, m); err != nil {
+		return wrapOutputError(err, cfg.Dir)
+	}
+	return nil
+}
+
+// refreshedData returns the new content of the model data files of m, by
+// manifest path: sm as JSON for each model.json among data, and the index and
+// shards of sm in each of shardDirs.
+func refreshedData(m *manifest.Manifest, data []int, shardDirs []string, sm *genspec.ServiceModel) (map[string][]byte, error) {
+	written := map[string][]byte{}
+	for _, i := range data {
+		if rel := m.Files[i].Path; path.Base(rel) == "model.json" {
+			modelJSON, err := json.MarshalIndent(sm, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("marshal model.json: %w", err)
+			}
+			written[rel] = append(modelJSON, '\n')
+		}
+	}
+	for _, dir := range shardDirs {
+		shards, err := modelshard.Files(sm)
+		if err != nil {
+			return nil, err
+		}
+		for rel, b := range shards {
+			// rel starts with modelshard.Dir, the last element of dir.
+			written[path.Join(path.Dir(dir), rel)] = b
+		}
+	}
+	return written, nil
+}
+
+// majorVersion returns the major component of a version such as v1.2.3.
+func majorVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	major, _, _ := strings.Cut(v, ".")
+	return major
+}
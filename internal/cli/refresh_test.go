@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+)
+
+func TestRefreshModel(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	tagged := minimalSpecYAML +
+		"  /admin:\n" +
+		"    get:\n" +
+		"      tags: [admin]\n" +
+		"      summary: Admin\n" +
+		"      responses:\n" +
+		"        '200':\n" +
+		"          description: ok\n"
+	if err := os.WriteFile(specPath, []byte(tagged), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCmd()
+		root.SetOut(&out)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	for _, tc := range []struct{ lang, model string }{
+		{"go", "internal/spec/model.json"},
+		{"npm", "src/spec/model.json"},
+		{"python", "src/refresh_tool/spec/model.json"},
+	} {
+		t.Run(tc.lang, func(t *testing.T) {
+			out := filepath.Join(dir, "out-"+tc.lang)
+			if _, err := run("generate", "--input", specPath, "--lang", tc.lang, "--out", out, "--tool-name", "refresh-tool", "--exclude-tags", "admin"); err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			before := hashTree(t, out)
+			m, err := manifest.ReadManifest(out)
+			if err != nil || m.Provenance == nil || m.Provenance.Input != specPath || m.Provenance.Generator != Version {
+				t.Fatalf("provenance not recorded: %+v, %v", m, err)
+			}
+
+			changed := strings.Replace(tagged, "summary: Hello", "summary: Hello again", 1)
+			if err := os.WriteFile(specPath, []byte(changed), 0o600); err != nil {
+				t.Fatalf("write spec: %v", err)
+			}
+			t.Cleanup(func() { _ = os.WriteFile(specPath, []byte(tagged), 0o600) })
+			stdout, err := run("refresh-model", "--dir", out)
+			if err != nil {
+				t.Fatalf("refresh-model: %v", err)
+			}
+			if !strings.Contains(stdout, "refreshed "+tc.model) {
+				t.Fatalf("unexpected output: %q", stdout)
+			}
+
+			after := hashTree(t, out)
+			for rel, sum := range before {
+				want := rel == tc.model || rel == manifest.FileName
+				if (after[rel] != sum) != want {
+					t.Errorf("%s: changed=%v, want changed=%v", rel, after[rel] != sum, want)
+				}
+			}
+			if len(after) != len(before) {
+				t.Errorf("file set changed: %d -> %d files", len(before), len(after))
+			}
+			model, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(tc.model)))
+			if err != nil {
+				t.Fatalf("read model: %v", err)
+			}
+			// The recorded --exclude-tags still applies.
+			if !strings.Contains(string(model), "Hello again") || strings.Contains(string(model), "/admin") {
+				t.Fatalf("refreshed model.json does not reflect the spec and filters:\n%s", model)
+			}
+			// The manifest tracks the new content, so a second refresh does not
+			// mistake model.json for an edited file.
+			if _, err := run("refresh-model", "--dir", out); err != nil {
+				t.Fatalf("second refresh-model: %v", err)
+			}
+		})
+	}
+}
+
+func TestRefreshModel_ShardedModel(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	tagged := minimalSpecYAML +
+		"  /admin:\n" +
+		"    get:\n" +
+		"      tags: [admin]\n" +
+		"      summary: Admin\n" +
+		"      responses:\n" +
+		"        '200':\n" +
+		"          description: ok\n"
+	if err := os.WriteFile(specPath, []byte(tagged), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCmd()
+		root.SetOut(&out)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	for _, tc := range []struct{ lang, model string }{
+		{"go", "internal/spec/model"},
+		{"npm", "src/spec/model"},
+		{"python", "src/refresh_tool/spec/model"},
+	} {
+		t.Run(tc.lang, func(t *testing.T) {
+			out := filepath.Join(dir, "out-"+tc.lang)
+			if _, err := run("generate", "--input", specPath, "--lang", tc.lang, "--out", out, "--tool-name", "refresh-tool", "--shard-model-by-tag"); err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			before := hashTree(t, out)
+			if before[tc.model+"/admin.json"] == "" {
+				t.Fatalf("no admin shard in %v", before)
+			}
+
+			// The admin endpoint moves to a new tag: its shard goes away.
+			changed := strings.Replace(tagged, "tags: [admin]", "tags: [ops]", 1)
+			if err := os.WriteFile(specPath, []byte(changed), 0o600); err != nil {
+				t.Fatalf("write spec: %v", err)
+			}
+			t.Cleanup(func() { _ = os.WriteFile(specPath, []byte(tagged), 0o600) })
+			stdout, err := run("refresh-model", "--dir", out)
+			if err != nil {
+				t.Fatalf("refresh-model: %v", err)
+			}
+			for _, want := range []string{
+				"refreshed " + tc.model + "/_index.json",
+				"refreshed " + tc.model + "/ops.json",
+				"removed " + tc.model + "/admin.json",
+			} {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("output %q lacks %q", stdout, want)
+				}
+			}
+
+			after := hashTree(t, out)
+			for rel, sum := range before {
+				want := strings.HasPrefix(rel, tc.model+"/") && rel != tc.model+"/_untagged.json" || rel == manifest.FileName
+				if (after[rel] != sum) != want {
+					t.Errorf("%s: changed=%v, want changed=%v", rel, after[rel] != sum, want)
+				}
+			}
+			m, err := manifest.ReadManifest(out)
+			if err != nil {
+				t.Fatalf("read manifest: %v", err)
+			}
+			listed := map[string]bool{}
+			for _, f := range m.Files {
+				listed[f.Path] = true
+				if after[f.Path] != f.SHA256 {
+					t.Errorf("manifest hash of %s does not match the file", f.Path)
+				}
+			}
+			for rel := range after {
+				if rel != manifest.FileName && !listed[rel] {
+					t.Errorf("%s is not in the manifest", rel)
+				}
+			}
+			if _, err := run("refresh-model", "--dir", out); err != nil {
+				t.Fatalf("second refresh-model: %v", err)
+			}
+		})
+	}
+}
+
+func TestRefreshModel_Refusals(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	out := filepath.Join(dir, "out")
+	run := func(args ...string) error {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"--no-config"}, args...))
+		return root.Execute()
+	}
+
+	if err := run("refresh-model", "--dir", out); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "generate the project first") {
+		t.Fatalf("expected usage error without a manifest, got %v", err)
+	}
+	if err := run("generate", "--input", specPath, "--lang", "go", "--out", out, "--tool-name", "refresh-tool"); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	m, err := manifest.ReadManifest(out)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	m.Provenance.Generator = "v99.0.0"
+	if err := manifest.WriteManifest(out, m); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := run("refresh-model", "--dir", out); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "v99.0.0") {
+		t.Fatalf("expected usage error for a major version mismatch, got %v", err)
+	}
+	if err := run("refresh-model", "--dir", out, "--force"); err != nil {
+		t.Fatalf("refresh-model --force: %v", err)
+	}
+
+	model := filepath.Join(out, "internal", "spec", "model.json")
+	if err := os.WriteFile(model, []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("edit model: %v", err)
+	}
+	if err := run("refresh-model", "--dir", out); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "was edited") {
+		t.Fatalf("expected usage error for an edited model.json, got %v", err)
+	}
+
+	if err := run("generate", "--input", specPath, "--lang", "markdown", "--out", filepath.Join(dir, "docs"), "--tool-name", "refresh-tool"); err != nil {
+		t.Fatalf("generate markdown: %v", err)
+	}
+	if err := run("refresh-model", "--dir", filepath.Join(dir, "docs")); !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "no embedded model.json or model/_index.json") {
+		t.Fatalf("expected usage error for output without model.json, got %v", err)
+	}
+}
+
+// hashTree returns the SHA-256 of every file under dir, keyed by slash path.
+func hashTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	sums := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		sums[filepath.ToSlash(rel)] = manifest.HashBytes(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk %s: %v", dir, err)
+	}
+	return sums
+}
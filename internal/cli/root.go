@@ -6,6 +6,11 @@ import (
     "github.com/spf13/cobra"
 )
 
+// Version is the swagger2mcp release, recorded in the manifest of every
+// generated project. Release builds set it with
+// -ldflags "-X github.com/mark3labs/swagger2mcp/internal/cli.Version=1.2.3".
+var Version = "0.1.0"
+
 // Execute runs the swagger2mcp CLI.
 func Execute() error {
 	return NewRootCmd().Execute()
@@ -31,6 +36,7 @@ func NewRootCmd() *cobra.Command {
     })
 
     cmd.PersistentFlags().StringP("config", "c", "", "Config file path (YAML or JSON)")
+    cmd.PersistentFlags().Bool("no-config", false, "Do not auto-discover swagger2mcp.yaml when --config is absent")
     cmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose logging output")
 
     g := newGenerateCmd()
@@ -45,5 +51,35 @@ func NewRootCmd() *cobra.Command {
     })
     cmd.AddCommand(i)
 
+    sv := newServeCmd()
+    sv.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+        return newUsageError(fmt.Sprintf("%v\n\n%s", err, c.UsageString()))
+    })
+    cmd.AddCommand(sv)
+
+    v := newValidateCmd()
+    v.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+        return newUsageError(fmt.Sprintf("%v\n\n%s", err, c.UsageString()))
+    })
+    cmd.AddCommand(v)
+
+    d := newDiffCmd()
+    d.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+        return newUsageError(fmt.Sprintf("%v\n\n%s", err, c.UsageString()))
+    })
+    cmd.AddCommand(d)
+
+    rm := newRefreshModelCmd()
+    rm.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+        return newUsageError(fmt.Sprintf("%v\n\n%s", err, c.UsageString()))
+    })
+    cmd.AddCommand(rm)
+
+    st := newStatsCmd()
+    st.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+        return newUsageError(fmt.Sprintf("%v\n\n%s", err, c.UsageString()))
+    })
+    cmd.AddCommand(st)
+
     return cmd
 }
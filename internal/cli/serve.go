@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/swagger2mcp/internal/mcpserve"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+// ServeConfig captures the options for the serve command.
+type ServeConfig struct {
+	Input         string
+	IncludeTags   []string
+	ExcludeTags   []string
+	Methods       []genspec.HttpMethod
+	PathPatterns  []string
+	Watch         bool
+	WatchInterval time.Duration
+	Verbose       bool
+}
+
+var serveRunner = runServe
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve MCP documentation tools directly from an OpenAPI/Swagger document",
+		Long: "Load a spec, build the service model in memory, and serve the listEndpoints, " +
+			"searchEndpoints, getEndpointDetails, listSchemas, and getSchemaDetails tools over stdio " +
+			"without generating a project.",
+		Example: strings.TrimSpace(`  swagger2mcp serve --input spec.yaml
+  swagger2mcp serve --input spec.yaml --include-tags pets --methods get --watch`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := resolveServeConfig(cmd)
+			if err != nil {
+				return err
+			}
+			return serveRunner(cmd.Context(), cfg)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String("input", "", "Path or URL to the Swagger/OpenAPI document")
+	flags.StringSlice("include-tags", nil, "Only include operations with these tags")
+	flags.StringSlice("exclude-tags", nil, "Exclude operations with these tags")
+	flags.StringSlice("methods", nil, "Only include operations using these HTTP methods")
+	flags.StringSlice("paths", nil, "Only include operations whose path matches one of these regular expressions")
+	flags.Bool("watch", false, "Reload the model when the spec file changes (local files only)")
+	flags.Duration("watch-interval", mcpserve.DefaultWatchInterval, "Polling interval used by --watch")
+
+	return cmd
+}
+
+func resolveServeConfig(cmd *cobra.Command) (*ServeConfig, error) {
+	flags := cmd.Flags()
+	cfg := &ServeConfig{}
+	var err error
+	if cfg.Input, err = flags.GetString("input"); err != nil {
+		return nil, err
+	}
+	cfg.Input = strings.TrimSpace(cfg.Input)
+	if cfg.Input == "" {
+		return nil, newUsageError("serve: --input is required")
+	}
+	include, err := flags.GetStringSlice("include-tags")
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := flags.GetStringSlice("exclude-tags")
+	if err != nil {
+		return nil, err
+	}
+	cfg.IncludeTags = sanitizeTags(include)
+	cfg.ExcludeTags = sanitizeTags(exclude)
+	if overlap := intersect(cfg.IncludeTags, cfg.ExcludeTags); len(overlap) > 0 {
+		return nil, newUsageError(fmt.Sprintf("serve: include/exclude tags overlap: %s", strings.Join(overlap, ", ")))
+	}
+	methods, err := flags.GetStringSlice("methods")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Methods, err = parseHTTPMethods(methods); err != nil {
+		return nil, newUsageError(fmt.Sprintf("serve: %v", err))
+	}
+	paths, err := flags.GetStringSlice("paths")
+	if err != nil {
+		return nil, err
+	}
+	cfg.PathPatterns = sanitizeTags(paths)
+	if cfg.Watch, err = flags.GetBool("watch"); err != nil {
+		return nil, err
+	}
+	if cfg.WatchInterval, err = flags.GetDuration("watch-interval"); err != nil {
+		return nil, err
+	}
+	if cfg.Verbose, err = flags.GetBool("verbose"); err != nil {
+		return nil, err
+	}
+	if cfg.Watch && isRemoteInput(cfg.Input) {
+		return nil, newUsageError("serve: --watch only supports local spec files")
+	}
+	return cfg, nil
+}
+
+func parseHTTPMethods(values []string) ([]genspec.HttpMethod, error) {
+	var out []genspec.HttpMethod
+	for _, v := range sanitizeTags(values) {
+		m := genspec.HttpMethod(strings.ToLower(v))
+		switch m {
+		case genspec.GET, genspec.POST, genspec.PUT, genspec.DELETE, genspec.PATCH, genspec.HEAD, genspec.OPTIONS, genspec.TRACE:
+			out = append(out, m)
+		default:
+			return nil, fmt.Errorf("unsupported HTTP method %q", v)
+		}
+	}
+	return out, nil
+}
+
+func isRemoteInput(input string) bool {
+	u, err := url.Parse(input)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func runServe(ctx context.Context, cfg *ServeConfig) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sm, err := loadServeModel(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	srv := mcpserve.New(sm)
+
+	if cfg.Watch {
+		path, err := filepath.Abs(cfg.Input)
+		if err != nil {
+			return newUsageError(fmt.Sprintf("serve: resolve input path: %v", err))
+		}
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			_ = mcpserve.Watch(watchCtx, path, cfg.WatchInterval, func() {
+				if err := reloadServeModel(watchCtx, cfg, srv); err != nil {
+					fmt.Fprintf(os.Stderr, "[WARN] reload %s: %v\n", path, err)
+					return
+				}
+				if cfg.Verbose {
+					fmt.Fprintf(os.Stderr, "[INFO] reloaded %s (%d endpoints)\n", path, len(srv.Model().Endpoints))
+				}
+			})
+		}()
+	}
+
+	return srv.ServeStdio(ctx, os.Stdin, os.Stdout)
+}
+
+// reloadServeModel rebuilds the model from cfg and swaps it into srv. On
+// error srv keeps serving the previous model until the spec is fixed.
+func reloadServeModel(ctx context.Context, cfg *ServeConfig, srv *mcpserve.Server) error {
+	next, err := loadServeModel(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	srv.SetModel(next)
+	return nil
+}
+
+func loadServeModel(ctx context.Context, cfg *ServeConfig) (*genspec.ServiceModel, error) {
+	doc, err := loadSpec(ctx, cfg.Input, cfg.Verbose)
+	if err != nil {
+		return nil, err
+	}
+	sm, err := genspec.BuildServiceModel(
+		ctx,
+		doc,
+		nil,
+		genspec.WithIncludeTags(cfg.IncludeTags),
+		genspec.WithExcludeTags(cfg.ExcludeTags),
+		genspec.WithMethods(cfg.Methods),
+		genspec.WithPathPatterns(cfg.PathPatterns),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build model: %w", err)
+	}
+	return sm, nil
+}
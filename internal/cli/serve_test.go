@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/mark3labs/swagger2mcp/internal/mcpserve"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func TestServeConfigFromFlags(t *testing.T) {
+	var captured *ServeConfig
+	serveRunner = func(ctx context.Context, cfg *ServeConfig) error {
+		captured = cfg
+		return nil
+	}
+	t.Cleanup(func() { serveRunner = runServe })
+
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{
+		"serve",
+		"--input", "spec.yaml",
+		"--include-tags", "pets",
+		"--methods", "GET,post",
+		"--paths", "^/pets",
+		"--watch",
+	})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured == nil {
+		t.Fatalf("expected config to be captured")
+	}
+	if captured.Input != "spec.yaml" || !captured.Watch {
+		t.Fatalf("unexpected config: %+v", captured)
+	}
+	if want := []string{"pets"}; !equalStringSlices(captured.IncludeTags, want) {
+		t.Errorf("include tags: got %v", captured.IncludeTags)
+	}
+	if len(captured.Methods) != 2 || captured.Methods[0] != genspec.GET || captured.Methods[1] != genspec.POST {
+		t.Errorf("methods: got %v", captured.Methods)
+	}
+	if want := []string{"^/pets"}; !equalStringSlices(captured.PathPatterns, want) {
+		t.Errorf("paths: got %v", captured.PathPatterns)
+	}
+}
+
+func TestServeConfig_Errors(t *testing.T) {
+	cases := [][]string{
+		{"serve"},
+		{"serve", "--input", "spec.yaml", "--methods", "fetch"},
+		{"serve", "--input", "https://example.com/spec.yaml", "--watch"},
+	}
+	for _, args := range cases {
+		root := NewRootCmd()
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(args)
+		if err := root.Execute(); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestReloadServeModel(t *testing.T) {
+	ctx := context.Background()
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	writeSpec := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(specPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("write spec: %v", err)
+		}
+	}
+	writeSpec(minimalSpecYAML)
+	cfg := &ServeConfig{Input: specPath}
+	sm, err := loadServeModel(ctx, cfg)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	srv := mcpserve.New(sm)
+
+	c, err := client.NewInProcessClient(srv.MCPServer())
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	overview := func() string {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "listEndpoints"
+		res, err := c.CallTool(ctx, req)
+		if err != nil {
+			t.Fatalf("call listEndpoints: %v", err)
+		}
+		if len(res.Content) == 0 {
+			t.Fatalf("listEndpoints returned no content")
+		}
+		text, _ := res.Content[0].(mcp.TextContent)
+		return text.Text
+	}
+
+	writeSpec(minimalSpecYAML +
+		"  /bye:\n" +
+		"    get:\n" +
+		"      summary: Bye\n" +
+		"      responses:\n" +
+		"        '200':\n" +
+		"          description: ok\n")
+	if err := reloadServeModel(ctx, cfg, srv); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if text := overview(); !strings.Contains(text, "GET /bye - Bye") || !strings.Contains(text, "GET /hello - Hello") {
+		t.Fatalf("expected reloaded model, got %q", text)
+	}
+
+	writeSpec("openapi: 3.0.0\npaths: [broken\n")
+	if err := reloadServeModel(ctx, cfg, srv); err == nil {
+		t.Fatalf("expected reload of broken spec to fail")
+	}
+	if text := overview(); !strings.Contains(text, "GET /bye - Bye") {
+		t.Fatalf("expected previous model to be kept, got %q", text)
+	}
+}
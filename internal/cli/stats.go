@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/swagger2mcp/internal/overrides"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"github.com/mark3labs/swagger2mcp/internal/specstats"
+	"github.com/spf13/cobra"
+)
+
+// StatsConfig captures the options for the stats command. The filters match
+// generate's, so metrics can be computed for the subset an agent would see.
+type StatsConfig struct {
+	Input          string
+	IncludeTags    []string
+	ExcludeTags    []string
+	IncludeSchemas []string
+	ExcludeSchemas []string
+	DropExtensions []ExtensionPredicate
+	// Overrides is an endpoint overrides file, applied after the filters;
+	// hidden endpoints are not counted.
+	Overrides string
+	JSON      bool
+	Verbose   bool
+}
+
+var statsRunner = runStats
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report complexity metrics for an OpenAPI/Swagger document",
+		Long: "Build the service model and report endpoint counts by method and tag, schema count, " +
+			"parameters per endpoint, deprecated share, and response and description coverage. " +
+			"--json prints one flat object whose keys are stable across runs.",
+		Example: strings.TrimSpace(`  swagger2mcp stats --input spec.yaml
+  swagger2mcp stats --input spec.yaml --include-tags pets --json >> metrics.jsonl`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := resolveStatsConfig(cmd)
+			if err != nil {
+				return err
+			}
+			return statsRunner(cmd.Context(), cfg, cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String("input", "", "Path or URL to the Swagger/OpenAPI document")
+	flags.StringSlice("include-tags", nil, "Only include operations with these tags")
+	flags.StringSlice("exclude-tags", nil, "Exclude operations with these tags")
+	flags.StringSlice("include-schemas", nil, "Only embed schemas whose name matches one of these globs")
+	flags.StringSlice("exclude-schemas", nil, "Drop schemas whose name matches one of these globs")
+	flags.StringArray("drop-extension", nil, "Drop operations whose x-* extension equals a value, e.g. x-internal=true (repeatable)")
+	flags.String("overrides", "", "YAML file mapping endpoint IDs or operationIds to summary/description/hidden/featured overrides")
+	flags.Bool("json", false, "Print a single flat JSON object instead of a table")
+
+	return cmd
+}
+
+func resolveStatsConfig(cmd *cobra.Command) (*StatsConfig, error) {
+	flags := cmd.Flags()
+	cfg := &StatsConfig{}
+	var err error
+	if cfg.Input, err = flags.GetString("input"); err != nil {
+		return nil, err
+	}
+	cfg.Input = strings.TrimSpace(cfg.Input)
+	if cfg.Input == "" {
+		return nil, newUsageError("stats: --input is required")
+	}
+	for name, dst := range map[string]*[]string{
+		"include-tags":    &cfg.IncludeTags,
+		"exclude-tags":    &cfg.ExcludeTags,
+		"include-schemas": &cfg.IncludeSchemas,
+		"exclude-schemas": &cfg.ExcludeSchemas,
+	} {
+		value, err := flags.GetStringSlice(name)
+		if err != nil {
+			return nil, err
+		}
+		*dst = sanitizeTags(value)
+	}
+	if overlap := intersect(cfg.IncludeTags, cfg.ExcludeTags); len(overlap) > 0 {
+		return nil, newUsageError(fmt.Sprintf("stats: include/exclude tags overlap: %s", strings.Join(overlap, ", ")))
+	}
+	for _, pattern := range append(append([]string(nil), cfg.IncludeSchemas...), cfg.ExcludeSchemas...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, newUsageError(fmt.Sprintf("stats: invalid schema pattern %q: %v", pattern, err))
+		}
+	}
+	drops, err := flags.GetStringArray("drop-extension")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DropExtensions, err = parseExtensionPredicates(drops); err != nil {
+		return nil, err
+	}
+	if cfg.Overrides, err = flags.GetString("overrides"); err != nil {
+		return nil, err
+	}
+	cfg.Overrides = strings.TrimSpace(cfg.Overrides)
+	if cfg.JSON, err = flags.GetBool("json"); err != nil {
+		return nil, err
+	}
+	if cfg.Verbose, err = flags.GetBool("verbose"); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func runStats(ctx context.Context, cfg *StatsConfig, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var endpointOverrides overrides.File
+	if cfg.Overrides != "" {
+		f, err := overrides.Load(cfg.Overrides)
+		if err != nil {
+			return newUsageError(fmt.Sprintf("stats: %v", err))
+		}
+		endpointOverrides = f
+	}
+	doc, err := loadSpec(ctx, cfg.Input, cfg.Verbose)
+	if err != nil {
+		return err
+	}
+	buildOpts := []genspec.BuildOption{
+		genspec.WithIncludeTags(cfg.IncludeTags),
+		genspec.WithExcludeTags(cfg.ExcludeTags),
+		genspec.WithIncludeSchemaPatterns(cfg.IncludeSchemas),
+		genspec.WithExcludeSchemaPatterns(cfg.ExcludeSchemas),
+	}
+	for _, p := range cfg.DropExtensions {
+		buildOpts = append(buildOpts, genspec.WithExtensionFilter(p.Key, p.Value))
+	}
+	sm, err := genspec.BuildServiceModel(ctx, doc, nil, buildOpts...)
+	if err != nil {
+		return fmt.Errorf("build model: %w", err)
+	}
+	overrides.Apply(sm, endpointOverrides)
+	for _, w := range sm.Warnings {
+		fmt.Fprintf(os.Stderr, "[WARN] %s\n", w)
+	}
+
+	stats := specstats.Analyze(sm)
+	if cfg.JSON {
+		err = stats.WriteJSON(out)
+	} else {
+		err = stats.WriteText(out)
+	}
+	if err != nil {
+		return fmt.Errorf("write stats: %w", err)
+	}
+	return nil
+}
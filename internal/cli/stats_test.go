@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStatsCommand(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	tagged := minimalSpecYAML +
+		"  /pets:\n" +
+		"    get:\n" +
+		"      tags: [pets]\n" +
+		"      deprecated: true\n" +
+		"      responses:\n" +
+		"        '200':\n" +
+		"          description: ok\n"
+	if err := os.WriteFile(specPath, []byte(tagged), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCmd()
+		root.SetOut(&out)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"stats", "--input", specPath}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run()
+	if err != nil || !strings.HasPrefix(out, "METRIC") || !strings.Contains(out, "endpoints_by_tag.pets") {
+		t.Fatalf("expected table, got err=%v out=%q", err, out)
+	}
+
+	// Filters apply before counting, as in generate.
+	out, err = run("--json", "--include-tags", "pets")
+	if err != nil {
+		t.Fatalf("stats --json: %v", err)
+	}
+	var flat map[string]any
+	if err := json.Unmarshal([]byte(out), &flat); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, out)
+	}
+	if flat["endpoints"] != float64(1) || flat["deprecated_pct"] != float64(100) {
+		t.Fatalf("unexpected filtered stats: %v", flat)
+	}
+	for k, v := range flat {
+		switch v.(type) {
+		case string, float64:
+		default:
+			t.Fatalf("key %s is not flat: %T", k, v)
+		}
+	}
+
+	// Hidden overrides remove endpoints from the counts, as in generate.
+	overridesPath := filepath.Join(filepath.Dir(specPath), "overrides.yaml")
+	if err := os.WriteFile(overridesPath, []byte("get /pets:\n  hidden: true\n"), 0o600); err != nil {
+		t.Fatalf("write overrides: %v", err)
+	}
+	out, err = run("--json", "--overrides", overridesPath)
+	if err != nil {
+		t.Fatalf("stats --overrides: %v", err)
+	}
+	flat = nil
+	if err := json.Unmarshal([]byte(out), &flat); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, out)
+	}
+	if flat["endpoints"] != float64(1) || flat["deprecated"] != float64(0) {
+		t.Fatalf("hidden endpoint should not be counted: %v", flat)
+	}
+
+	for _, args := range [][]string{
+		{"--include-tags", "pets", "--exclude-tags", "pets"},
+		{"--overrides", filepath.Join(filepath.Dir(specPath), "missing.yaml")},
+		{"--exclude-schemas", "["},
+		{"--drop-extension", "internal"},
+	} {
+		if _, err := run(args...); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: expected usage error, got %v", args, err)
+		}
+	}
+}
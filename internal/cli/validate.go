@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate an OpenAPI/Swagger document and report every problem found",
+		Example: strings.TrimSpace(`  swagger2mcp validate --input spec.yaml
+  swagger2mcp validate --input https://example.com/openapi.json`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := cmd.Flags().GetString("input")
+			if err != nil {
+				return err
+			}
+			input = strings.TrimSpace(input)
+			if input == "" {
+				return newUsageError("validate: --input is required")
+			}
+			verbose, err := cmd.Flags().GetBool("verbose")
+			if err != nil {
+				return err
+			}
+			return runValidate(cmd.Context(), input, verbose, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().String("input", "", "Path or URL to the Swagger/OpenAPI document")
+	return cmd
+}
+
+// runValidate loads input and prints each problem on its own line. Unlike
+// generate, which only shows the first problem, it lists all of them. A valid
+// document is followed by any warnings from building its service model.
+func runValidate(ctx context.Context, input string, verbose bool, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	res, err := genspec.LoadDetailed(ctx, input)
+	if err == nil {
+		if verbose {
+			printLoadWarnings(res.Warnings)
+		}
+		doc := res.Doc
+		title, version := "", ""
+		if doc.Info != nil {
+			title, version = doc.Info.Title, doc.Info.Version
+		}
+		fmt.Fprintf(out, "OK: %s %s is valid\n", title, version)
+		// Model warnings (such as an Authorization header parameter) do not
+		// make the document invalid, so they are listed without failing.
+		if sm, err := genspec.BuildServiceModel(ctx, doc, nil); err == nil {
+			for _, w := range sm.Warnings {
+				fmt.Fprintf(out, "- [WARN] %s\n", w)
+			}
+		}
+		return nil
+	}
+	var se *genspec.SpecError
+	if !errors.As(err, &se) {
+		return err
+	}
+	details := se.Errors
+	if len(details) == 0 {
+		details = []genspec.SpecErrorDetail{{Code: se.Code, Message: se.Message, JSONPointer: se.JSONPointer}}
+	}
+	fmt.Fprintf(out, "%s: %d problem(s)\n", se.Location, len(details))
+	for _, d := range details {
+		line := fmt.Sprintf("- [%s] %s", d.Code, d.Message)
+		if d.JSONPointer != "" {
+			line += " (" + d.JSONPointer + ")"
+		}
+		fmt.Fprintln(out, line)
+	}
+	return newUsageError(fmt.Sprintf("validate: %s", se.Message))
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	if err := os.WriteFile(good, []byte(minimalSpecYAML), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("openapi: 3.0.0\ninfo:\n  title: Bad\n  version: '1'\npaths:\n  /pet:\n    get:\n      responses: {}\n"), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCmd()
+		root.SetOut(&out)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"validate"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--input", good)
+	if err != nil || !strings.Contains(out, "OK: Test API 1.0.0") {
+		t.Fatalf("expected valid spec, got err=%v out=%q", err, out)
+	}
+
+	out, err = run("--input", bad)
+	if !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if !strings.Contains(out, "problem(s)") || !strings.Contains(out, "- [") {
+		t.Fatalf("expected problem list, got %q", out)
+	}
+
+	authHeader := filepath.Join(dir, "auth.yaml")
+	if err := os.WriteFile(authHeader, []byte("openapi: 3.0.0\ninfo:\n  title: Auth\n  version: '1'\npaths:\n  /me:\n    get:\n      parameters:\n        - { in: header, name: Authorization, schema: { type: string } }\n      responses: { '200': { description: ok } }\n"), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	out, err = run("--input", authHeader)
+	if err != nil || !strings.Contains(out, "OK: Auth 1") || !strings.Contains(out, `- [WARN] get /me: header parameter "Authorization" should be a security scheme`) {
+		t.Fatalf("expected a valid spec with an Authorization warning, got err=%v out=%q", err, out)
+	}
+
+	if _, err := run(); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error without --input, got %v", err)
+	}
+}
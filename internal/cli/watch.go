@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// defaultGenerateWatchInterval is how often generate --watch polls a remote spec.
+const defaultGenerateWatchInterval = 5 * time.Second
+
+// watchDebounce coalesces bursts of file events; editors often save a file in
+// several steps.
+var watchDebounce = 200 * time.Millisecond
+
+// watchGenerate runs an initial generation and then regenerates, with --force
+// implied, every time the input changes. Local inputs are watched together
+// with the files their $refs pull in and the overrides file; --dry-run plans
+// every cycle instead of writing. It returns nil once ctx is cancelled or the
+// process receives SIGINT/SIGTERM. Only the initial generation's error is
+// returned; later failures are reported and watching continues.
+func watchGenerate(ctx context.Context, cfg *GenerateConfig) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := generateRunner(ctx, cfg); err != nil {
+		return err
+	}
+
+	next := *cfg
+	next.Force = true
+	regenerate := func(changed string) {
+		fmt.Fprintf(os.Stderr, "[INFO] %s changed; regenerating\n", changed)
+		start := time.Now()
+		if err := generateRunner(ctx, &next); err != nil {
+			fmt.Fprintf(os.Stderr, "[WARN] regenerate: %v\n", err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "[INFO] regenerated in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+
+	fmt.Fprintf(os.Stderr, "[INFO] watching %s for changes (Ctrl+C to stop)\n", strings.Join(cfg.inputs(), ", "))
+	var err error
+	if isRemoteInput(cfg.Input) {
+		maxBytes := cfg.MaxSpecSize
+		if maxBytes <= 0 {
+			maxBytes = genspec.DefaultMaxSpecBytes
+		}
+		err = pollRemoteSpec(ctx, cfg.Input, cfg.WatchInterval, maxBytes, func() { regenerate(cfg.Input) })
+	} else {
+		err = watchLocalSpec(ctx, func() []string { return watchedFiles(ctx, cfg) }, regenerate)
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// watchedFiles returns the local files a generation from cfg reads: the
+// specs, the files their $refs pull in and the overrides file. Refs are only
+// found when a spec loads; until then that spec alone is watched.
+func watchedFiles(ctx context.Context, cfg *GenerateConfig) []string {
+	var files []string
+	var loadOpts []genspec.Option
+	if cfg.MaxSpecSize > 0 {
+		loadOpts = append(loadOpts, genspec.WithMaxSpecBytes(cfg.MaxSpecSize))
+	}
+	for _, input := range cfg.inputs() {
+		if loaded, err := genspec.LoadDetailed(ctx, input, loadOpts...); err == nil {
+			files = append(files, loaded.Files...)
+		} else if path, err := filepath.Abs(input); err == nil {
+			files = append(files, path)
+		}
+	}
+	if cfg.Overrides != "" {
+		if path, err := filepath.Abs(cfg.Overrides); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// watchLocalSpec calls onChange with the changed file after one of files()
+// is written or replaced, until ctx is done. The files are listed again after
+// each change, as edits may add or drop refs. Parent directories are watched
+// rather than the files themselves because editors that save via rename would
+// otherwise drop the watch.
+func watchLocalSpec(ctx context.Context, files func() []string, onChange func(changed string)) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+	defer w.Close()
+	watched, dirs := map[string]bool{}, map[string]bool{}
+	watch := func() error {
+		watched = map[string]bool{}
+		for _, f := range files() {
+			watched[filepath.Clean(f)] = true
+			if dir := filepath.Dir(f); !dirs[dir] {
+				if err := w.Add(dir); err != nil {
+					return fmt.Errorf("watch %s: %w", f, err)
+				}
+				dirs[dir] = true
+			}
+		}
+		return nil
+	}
+	if err := watch(); err != nil {
+		return err
+	}
+
+	var fire <-chan time.Time
+	changed := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if name := filepath.Clean(ev.Name); watched[name] && ev.Has(fsnotify.Write|fsnotify.Create) {
+				changed = name
+				fire = time.After(watchDebounce)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "[WARN] watch: %v\n", err)
+		case <-fire:
+			fire = nil
+			onChange(changed)
+			if err := watch(); err != nil {
+				fmt.Fprintf(os.Stderr, "[WARN] %v\n", err)
+			}
+		}
+	}
+}
+
+// pollRemoteSpec checks rawURL every interval and calls onChange when its
+// content changes, until ctx is done. Poll errors are reported and retried.
+// Bodies over maxBytes are rejected as the loader rejects them.
+func pollRemoteSpec(ctx context.Context, rawURL string, interval time.Duration, maxBytes int64, onChange func()) error {
+	if interval <= 0 {
+		interval = defaultGenerateWatchInterval
+	}
+	p := &remoteSpecPoller{url: rawURL, client: &http.Client{Timeout: 30 * time.Second}, maxBytes: maxBytes}
+	if _, err := p.changed(ctx); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "[WARN] poll %s: %v\n", rawURL, err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		changed, err := p.changed(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "[WARN] poll %s: %v\n", rawURL, err)
+			}
+			continue
+		}
+		if changed {
+			onChange()
+		}
+	}
+}
+
+// remoteSpecPoller detects changes to a remote document. It sends conditional
+// requests using the last ETag/Last-Modified so unchanged specs cost a 304,
+// and compares a content hash for servers that send neither.
+type remoteSpecPoller struct {
+	url          string
+	client       *http.Client
+	maxBytes     int64 // like genspec.Settings.MaxSpecBytes; <= 0 reads everything
+	etag         string
+	lastModified string
+	hash         string
+}
+
+// changed fetches the document and reports whether it differs from the
+// previous fetch. The first successful fetch only records a baseline.
+func (p *remoteSpecPoller) changed(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return false, err
+	}
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.lastModified != "" {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	if resp.StatusCode >= 400 {
+		return false, fmt.Errorf("http %d", resp.StatusCode)
+	}
+	body, err := genspec.ReadLimited(resp.Body, p.maxBytes)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	p.etag = resp.Header.Get("ETag")
+	p.lastModified = resp.Header.Get("Last-Modified")
+	changed := p.hash != "" && hash != p.hash
+	p.hash = hash
+	return changed, nil
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func TestGenerateWatchRegeneratesOnChange(t *testing.T) {
+	// Not parallel: swaps generateRunner and watchDebounce.
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(minimalSpecYAML), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	oldDebounce := watchDebounce
+	watchDebounce = 10 * time.Millisecond
+	runs := make(chan *GenerateConfig, 16)
+	var mu sync.Mutex
+	calls := 0
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		runs <- cfg
+		if n == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	t.Cleanup(func() {
+		generateRunner = runGenerate
+		watchDebounce = oldDebounce
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"--no-config", "generate", "--input", specPath, "--watch"})
+	done := make(chan error, 1)
+	go func() { done <- root.ExecuteContext(ctx) }()
+
+	first := waitRun(t, runs, nil)
+	if first.Force {
+		t.Fatalf("initial generation should not force")
+	}
+	// The watcher starts after the first run; keep touching the file until a
+	// regeneration is observed. Run 2 fails, and watching must continue to run 3.
+	touch := func() {
+		if err := os.WriteFile(specPath, []byte(minimalSpecYAML+"# edit\n"), 0o600); err != nil {
+			t.Errorf("rewrite spec: %v", err)
+		}
+	}
+	for i := 2; i <= 3; i++ {
+		cfg := waitRun(t, runs, touch)
+		if !cfg.Force {
+			t.Fatalf("run %d: regeneration should imply --force", i)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch should exit cleanly on cancel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch did not stop after cancel")
+	}
+}
+
+func TestGenerateWatchFollowsRefs(t *testing.T) {
+	// Not parallel: swaps generateRunner and watchDebounce.
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	spec := "openapi: 3.0.0\ninfo: { title: Refs, version: '1' }\npaths:\n  /pets:\n    get:\n      responses:\n        '200':\n          description: ok\n          content:\n            application/json:\n              schema: { $ref: './schemas/pet.yaml#/Pet' }\n"
+	if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	refPath := filepath.Join(dir, "schemas", "pet.yaml")
+	if err := os.MkdirAll(filepath.Dir(refPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(refPath, []byte("Pet:\n  type: object\n"), 0o600); err != nil {
+		t.Fatalf("write ref: %v", err)
+	}
+	if got := watchedFiles(context.Background(), &GenerateConfig{Input: specPath}); len(got) != 2 || got[1] != refPath {
+		t.Fatalf("watchedFiles = %q, want the spec and %s", got, refPath)
+	}
+
+	oldDebounce := watchDebounce
+	watchDebounce = 10 * time.Millisecond
+	runs := make(chan *GenerateConfig, 16)
+	generateRunner = func(ctx context.Context, cfg *GenerateConfig) error {
+		runs <- cfg
+		return nil
+	}
+	t.Cleanup(func() {
+		generateRunner = runGenerate
+		watchDebounce = oldDebounce
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"--no-config", "generate", "--input", specPath, "--watch", "--dry-run"})
+	done := make(chan error, 1)
+	go func() { done <- root.ExecuteContext(ctx) }()
+
+	waitRun(t, runs, nil)
+	// Only the referenced file changes; --dry-run carries over to the cycle.
+	cfg := waitRun(t, runs, func() {
+		if err := os.WriteFile(refPath, []byte("Pet:\n  type: object\n  description: edited\n"), 0o600); err != nil {
+			t.Errorf("rewrite ref: %v", err)
+		}
+	})
+	if !cfg.DryRun || !cfg.Force {
+		t.Fatalf("regeneration should keep --dry-run and imply --force: %+v", cfg)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch should exit cleanly on cancel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch did not stop after cancel")
+	}
+}
+
+// waitRun waits for the next generation, calling poke periodically meanwhile.
+func waitRun(t *testing.T, runs <-chan *GenerateConfig, poke func()) *GenerateConfig {
+	t.Helper()
+	deadline := time.After(10 * time.Second)
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case cfg := <-runs:
+			return cfg
+		case <-tick.C:
+			if poke != nil {
+				poke()
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for a generation run")
+		}
+	}
+}
+
+func TestRemoteSpecPoller(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	body, etag := "v1", `"1"`
+	useETag := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if useETag {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	p := &remoteSpecPoller{url: srv.URL, client: srv.Client()}
+	ctx := context.Background()
+	check := func(want bool, step string) {
+		t.Helper()
+		got, err := p.changed(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+		if got != want {
+			t.Fatalf("%s: changed=%v, want %v", step, got, want)
+		}
+	}
+	check(false, "baseline")
+	check(false, "304 not modified")
+	mu.Lock()
+	body, etag = "v2", `"2"`
+	mu.Unlock()
+	check(true, "new etag")
+
+	// Without validators the content hash decides.
+	mu.Lock()
+	useETag = false
+	mu.Unlock()
+	check(false, "same body without etag")
+	mu.Lock()
+	body = "v3"
+	mu.Unlock()
+	check(true, "changed body without etag")
+}
+
+func TestRemoteSpecPoller_MaxBytes(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("x", 64))
+	}))
+	defer srv.Close()
+
+	p := &remoteSpecPoller{url: srv.URL, client: srv.Client(), maxBytes: 16}
+	if _, err := p.changed(context.Background()); !errors.Is(err, genspec.ErrSpecTooLarge) {
+		t.Fatalf("expected %v, got %v", genspec.ErrSpecTooLarge, err)
+	}
+	p.maxBytes = 64
+	if _, err := p.changed(context.Background()); err != nil {
+		t.Fatalf("a body at the limit should be read: %v", err)
+	}
+}
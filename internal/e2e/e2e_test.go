@@ -5,6 +5,8 @@ import (
     "context"
     "crypto/sha256"
     "encoding/hex"
+    "encoding/json"
+    "fmt"
     "io"
     "os"
     "os/exec"
@@ -16,6 +18,13 @@ import (
     "time"
 
     cli "github.com/mark3labs/swagger2mcp/internal/cli"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/brunoemitter"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/goemitter"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/markdownemitter"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/npmemitter"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/postmanemitter"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/pyemitter"
+    genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
 // minimal OpenAPI v3 spec with a single endpoint
@@ -161,6 +170,234 @@ func TestE2E_Generate_NPM_Deterministic_And_Formatting(t *testing.T) {
     }
 }
 
+// v2 spec with a file download (schema type: file) and a formData upload
+const v2FileSpec = "" +
+    "swagger: '2.0'\n" +
+    "info: {title: Files, version: '1'}\n" +
+    "host: api.example.com\n" +
+    "paths:\n" +
+    "  /files/{id}:\n" +
+    "    get:\n" +
+    "      operationId: download\n" +
+    "      summary: Get a file\n" +
+    "      produces: [application/octet-stream]\n" +
+    "      parameters:\n" +
+    "        - {name: id, in: path, required: true, type: string}\n" +
+    "      responses:\n" +
+    "        '200':\n" +
+    "          description: the file\n" +
+    "          schema: {type: file}\n" +
+    "  /upload:\n" +
+    "    post:\n" +
+    "      consumes: [multipart/form-data]\n" +
+    "      parameters:\n" +
+    "        - {name: file, in: formData, type: file, required: true}\n" +
+    "      responses:\n" +
+    "        '204': {description: ok}\n"
+
+func TestE2E_V2FileDownload_AllModelParsers(t *testing.T) {
+    t.Parallel()
+    spec := filepath.Join(t.TempDir(), "files.yaml")
+    if err := os.WriteFile(spec, []byte(v2FileSpec), 0o600); err != nil {
+        t.Fatalf("write spec: %v", err)
+    }
+    goDir, npmDir, pyDir := t.TempDir(), t.TempDir(), t.TempDir()
+    runCLI(t, "generate", "--input", spec, "--lang", "go", "--out", goDir, "--force")
+    runCLI(t, "generate", "--input", spec, "--lang", "npm", "--out", npmDir, "--force")
+    runCLI(t, "generate", "--input", spec, "--lang", "python", "--out", pyDir, "--force", "--package-name", "files_tool")
+
+    models := map[string]string{
+        "go":     filepath.Join(goDir, "internal", "spec", "model.json"),
+        "npm":    filepath.Join(npmDir, "src", "spec", "model.json"),
+        "python": filepath.Join(pyDir, "src", "files_tool", "spec", "model.json"),
+    }
+    for lang, path := range models {
+        raw, err := os.ReadFile(path)
+        if err != nil {
+            t.Fatalf("%s: read model.json: %v", lang, err)
+        }
+        if strings.Contains(string(raw), `"Type": "file"`) {
+            t.Fatalf("%s: model.json still contains the v2 file type", lang)
+        }
+        var sm genspec.ServiceModel
+        if err := json.Unmarshal(raw, &sm); err != nil {
+            t.Fatalf("%s: decode model.json: %v", lang, err)
+        }
+        var download *genspec.EndpointModel
+        for i := range sm.Endpoints {
+            if sm.Endpoints[i].ID == "get /files/{id}" {
+                download = &sm.Endpoints[i]
+            }
+        }
+        if download == nil || len(download.Responses) != 1 || len(download.Responses[0].Content) != 1 {
+            t.Fatalf("%s: unexpected download endpoint: %+v", lang, download)
+        }
+        media := download.Responses[0].Content[0]
+        if media.Mime != "application/octet-stream" || !media.IsBinary {
+            t.Fatalf("%s: download media = %+v, want binary application/octet-stream", lang, media)
+        }
+        if sc := media.Schema; sc == nil || sc.Schema == nil || sc.Schema.Type != "string" || sc.Schema.Format != "binary" {
+            t.Fatalf("%s: download schema = %+v, want string/binary", lang, media.Schema)
+        }
+    }
+
+    // The Python model parser only needs the standard library.
+    if haveCmd("python3") {
+        script := "from files_tool.spec.loader import load_service_model\n" +
+            "m = load_service_model()\n" +
+            "c = [e for e in m.endpoints if e.id == 'get /files/{id}'][0].responses[0].content[0]\n" +
+            "assert c.is_binary and c.mime == 'application/octet-stream', c\n" +
+            "assert (c.schema.schema.type, c.schema.schema.format) == ('string', 'binary'), c\n"
+        cmd := exec.Command("python3", "-c", script)
+        cmd.Env = append(os.Environ(), "PYTHONPATH="+filepath.Join(pyDir, "src"))
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("python model parser: %v\n%s", err, out)
+        }
+    }
+
+    // The Go and TypeScript parsers run through the generated test suites.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") == "1" {
+        if haveCmd("go") {
+            if err := runCmdWithTimeout(goDir, 3*time.Minute, "go", "mod", "tidy"); err != nil {
+                t.Skipf("go mod tidy skipped (likely offline): %v", err)
+            }
+            if err := runCmdWithTimeout(goDir, 3*time.Minute, "go", "test", "./..."); err != nil {
+                t.Fatalf("generated go tests failed: %v", err)
+            }
+        }
+        if haveCmd("npm") {
+            if err := runCmdWithTimeout(npmDir, 3*time.Minute, "npm", "install"); err != nil {
+                t.Skipf("npm install skipped (likely offline): %v", err)
+            }
+            if err := runCmdWithTimeout(npmDir, 1*time.Minute, "npm", "test"); err != nil {
+                t.Fatalf("npm test failed: %v", err)
+            }
+        }
+    }
+}
+
+// A model whose string fields are all empty (no title, version or
+// description) must still render well-formed artifacts from every emitter.
+func TestE2E_EmptyModelFields_AllEmitters(t *testing.T) {
+    t.Parallel()
+    empty := func() *genspec.ServiceModel {
+        return &genspec.ServiceModel{Endpoints: []genspec.EndpointModel{{ID: "get /x", Method: genspec.GET, Path: "/x"}}}
+    }
+    ctx := context.Background()
+    emit := map[string]func(dir string) error{
+        "go": func(dir string) error {
+            _, err := goemitter.Emit(ctx, empty(), goemitter.Options{OutDir: dir, EmitDocs: true})
+            return err
+        },
+        "npm": func(dir string) error {
+            _, err := npmemitter.Emit(ctx, empty(), npmemitter.Options{OutDir: dir, ESM: true})
+            return err
+        },
+        "python": func(dir string) error {
+            _, err := pyemitter.Emit(ctx, empty(), pyemitter.Options{OutDir: dir})
+            return err
+        },
+        "postman": func(dir string) error {
+            _, err := postmanemitter.Emit(ctx, empty(), postmanemitter.Options{OutDir: dir})
+            return err
+        },
+        "bruno": func(dir string) error {
+            _, err := brunoemitter.Emit(ctx, empty(), brunoemitter.Options{OutDir: dir})
+            return err
+        },
+        "markdown": func(dir string) error {
+            _, err := markdownemitter.Emit(ctx, empty(), markdownemitter.Options{OutDir: dir})
+            return err
+        },
+    }
+    for lang, fn := range emit {
+        dir := t.TempDir()
+        if err := fn(dir); err != nil {
+            t.Fatalf("%s: emit: %v", lang, err)
+        }
+        err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+            if err != nil || info.IsDir() {
+                return err
+            }
+            rel, _ := filepath.Rel(dir, path)
+            raw, err := os.ReadFile(path)
+            if err != nil {
+                return err
+            }
+            text := string(raw)
+            if strings.HasSuffix(rel, ".md") {
+                for i, line := range strings.Split(text, "\n") {
+                    trimmed := strings.TrimSpace(line)
+                    if strings.HasPrefix(trimmed, "#") && (strings.Trim(trimmed, "# ") == "" || strings.HasPrefix(strings.TrimLeft(trimmed, "#"), "  ")) {
+                        t.Errorf("%s: %s:%d: header is missing the title: %q", lang, rel, i+1, line)
+                    }
+                    if strings.HasSuffix(trimmed, " for") || strings.Contains(line, " for  ") || strings.HasPrefix(trimmed, "的") {
+                        t.Errorf("%s: %s:%d: sentence is missing the API title: %q", lang, rel, i+1, line)
+                    }
+                }
+            }
+            switch filepath.Base(rel) {
+            case "package.json", "manifest.json":
+                var doc map[string]any
+                if err := json.Unmarshal(raw, &doc); err != nil {
+                    t.Errorf("%s: %s: %v", lang, rel, err)
+                }
+                for _, key := range []string{"name", "version", "description"} {
+                    if v, ok := doc[key]; ok && strings.TrimSpace(fmt.Sprint(v)) == "" {
+                        t.Errorf("%s: %s: %s is empty", lang, rel, key)
+                    }
+                }
+            case "pyproject.toml", "setup.py":
+                for _, bad := range []string{`version = ""`, `description = ""`, `version=""`, `description=""`, `description = "的`, `description="的`} {
+                    if strings.Contains(text, bad) {
+                        t.Errorf("%s: %s: contains %s", lang, rel, bad)
+                    }
+                }
+            case "model.json":
+                var sm genspec.ServiceModel
+                if err := json.Unmarshal(raw, &sm); err != nil {
+                    t.Errorf("%s: %s: %v", lang, rel, err)
+                } else if sm.Title == "" || sm.Version == "" || sm.Description == "" {
+                    t.Errorf("%s: %s: Title/Version/Description should be filled in: %q %q %q", lang, rel, sm.Title, sm.Version, sm.Description)
+                }
+            }
+            return nil
+        })
+        if err != nil {
+            t.Fatalf("%s: walk: %v", lang, err)
+        }
+    }
+}
+
+func TestE2E_EmitDocs_SameForAllLanguages(t *testing.T) {
+    t.Parallel()
+    spec := writeTempSpec(t)
+    var want string
+    for _, lang := range []string{"go", "npm", "python"} {
+        dir1 := t.TempDir()
+        dir2 := t.TempDir()
+        runCLI(t, "generate", "--input", spec, "--lang", lang, "--out", dir1, "--force", "--emit-docs")
+        runCLI(t, "generate", "--input", spec, "--lang", lang, "--out", dir2, "--force", "--emit-docs")
+
+        b, err := os.ReadFile(filepath.Join(dir1, "docs", "API.md"))
+        if err != nil {
+            t.Fatalf("%s: read docs/API.md: %v", lang, err)
+        }
+        // The manifests' time stamps may differ, so only the reference is compared.
+        if again, err := os.ReadFile(filepath.Join(dir2, "docs", "API.md")); err != nil || !bytes.Equal(again, b) {
+            t.Fatalf("%s: docs/API.md differs between runs (err=%v)", lang, err)
+        }
+        if want == "" {
+            want = string(b)
+            if !strings.Contains(want, "| `GET` | [`/pets`](#list-pets) | List pets |") {
+                t.Fatalf("%s: docs/API.md has no endpoint table:\n%s", lang, want)
+            }
+        } else if string(b) != want {
+            t.Errorf("%s: docs/API.md differs from go's:\n%s\n---\n%s", lang, b, want)
+        }
+    }
+}
+
 func haveCmd(name string) bool {
     _, err := exec.LookPath(name)
     return err == nil
@@ -0,0 +1,139 @@
+// Package archive packs generated files into a single .tar.gz or .zip. Entries
+// are written in genspec.CompareNames order with fixed ownership and time
+// stamps, so the same files always make the same bytes.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// Archive formats.
+const (
+	TarGz = "tar.gz"
+	Zip   = "zip"
+)
+
+// Formats lists the valid formats of Write.
+var Formats = []string{TarGz, Zip}
+
+// ModTime stamps every entry: 1980-01-01, the earliest time a zip entry can
+// record.
+var ModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// File is one archive entry.
+type File struct {
+	Path    string      // slash-separated, relative to the archive root
+	Mode    os.FileMode // permission bits
+	Content []byte
+}
+
+// Ext returns the file name extension of format, e.g. ".tar.gz".
+func Ext(format string) string {
+	return "." + format
+}
+
+// Write writes files to w as a format archive. The order of files does not
+// matter; entries are sorted by path.
+func Write(w io.Writer, format string, files []File) error {
+	sorted := append([]File(nil), files...)
+	slices.SortFunc(sorted, func(a, b File) int { return genspec.CompareNames(a.Path, b.Path) })
+	switch format {
+	case TarGz:
+		return writeTarGz(w, sorted)
+	case Zip:
+		return writeZip(w, sorted)
+	}
+	return fmt.Errorf("archive: unsupported format %q (valid: %s)", format, strings.Join(Formats, ", "))
+}
+
+func writeTarGz(w io.Writer, files []File) error {
+	// A zero gzip header leaves out the name and time stamp.
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.Path,
+			Mode:     int64(f.Mode.Perm()),
+			Size:     int64(len(f.Content)),
+			ModTime:  ModTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("archive: %s: %w", f.Path, err)
+		}
+		if _, err := tw.Write(f.Content); err != nil {
+			return fmt.Errorf("archive: %s: %w", f.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	return nil
+}
+
+func writeZip(w io.Writer, files []File) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		hdr := &zip.FileHeader{Name: f.Path, Method: zip.Deflate, Modified: ModTime}
+		hdr.SetMode(f.Mode.Perm())
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("archive: %s: %w", f.Path, err)
+		}
+		if _, err := fw.Write(f.Content); err != nil {
+			return fmt.Errorf("archive: %s: %w", f.Path, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	return nil
+}
+
+// ReadDir returns the regular files under dir with their permission bits,
+// leaving out those skip reports (by slash-separated relative path).
+func ReadDir(dir string, skip func(rel string) bool) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !d.Type().IsRegular() || skip != nil && skip(rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: rel, Mode: info.Mode().Perm(), Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+	return files, nil
+}
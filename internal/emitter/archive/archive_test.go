@@ -0,0 +1,150 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleFiles() []File {
+	return []File{
+		{Path: "src/server.py", Mode: 0o644, Content: []byte("print('hi')\n")},
+		{Path: "Makefile", Mode: 0o755, Content: []byte("all:\n")},
+		{Path: "README.md", Mode: 0o644, Content: []byte("# Tool\n")},
+		{Path: "empty.txt", Mode: 0o644},
+	}
+}
+
+// readBack lists the entries of an archive as Files, in archive order, and
+// checks every entry carries ModTime.
+func readBack(t *testing.T, format string, data []byte) []File {
+	t.Helper()
+	var out []File
+	switch format {
+	case TarGz:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("gzip: %v", err)
+		}
+		if !gz.ModTime.IsZero() || gz.Name != "" {
+			t.Errorf("gzip header should be blank: %+v", gz.Header)
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("tar: %v", err)
+			}
+			if !hdr.ModTime.Equal(ModTime) || hdr.Uid != 0 || hdr.Uname != "" {
+				t.Errorf("%s: header %+v", hdr.Name, hdr)
+			}
+			content, _ := io.ReadAll(tr)
+			out = append(out, File{Path: hdr.Name, Mode: os.FileMode(hdr.Mode), Content: content})
+		}
+	case Zip:
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("zip: %v", err)
+		}
+		for _, f := range zr.File {
+			if !f.Modified.Equal(ModTime) {
+				t.Errorf("%s: modified %v", f.Name, f.Modified)
+			}
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("open %s: %v", f.Name, err)
+			}
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			out = append(out, File{Path: f.Name, Mode: f.Mode().Perm(), Content: content})
+		}
+	}
+	for i := range out {
+		if len(out[i].Content) == 0 {
+			out[i].Content = nil
+		}
+	}
+	return out
+}
+
+func TestWrite_RoundTrip(t *testing.T) {
+	want := []File{
+		{Path: "empty.txt", Mode: 0o644},
+		{Path: "Makefile", Mode: 0o755, Content: []byte("all:\n")},
+		{Path: "README.md", Mode: 0o644, Content: []byte("# Tool\n")},
+		{Path: "src/server.py", Mode: 0o644, Content: []byte("print('hi')\n")},
+	}
+	for _, format := range Formats {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, sampleFiles()); err != nil {
+			t.Fatalf("%s: write: %v", format, err)
+		}
+		if got := readBack(t, format, buf.Bytes()); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: entries\n got %+v\nwant %+v", format, got, want)
+		}
+	}
+}
+
+func TestWrite_Deterministic(t *testing.T) {
+	for _, format := range Formats {
+		var first, second bytes.Buffer
+		files := sampleFiles()
+		if err := Write(&first, format, files); err != nil {
+			t.Fatalf("%s: write: %v", format, err)
+		}
+		// the input order does not matter
+		for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+			files[i], files[j] = files[j], files[i]
+		}
+		if err := Write(&second, format, files); err != nil {
+			t.Fatalf("%s: write: %v", format, err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Errorf("%s: archives of the same files differ", format)
+		}
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	err := Write(io.Discard, "rar", sampleFiles())
+	if err == nil || !strings.Contains(err.Error(), `unsupported format "rar" (valid: tar.gz, zip)`) {
+		t.Fatalf("expected an unsupported-format error, got %v", err)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	for rel, mode := range map[string]os.FileMode{"a/b.txt": 0o644, "run.sh": 0o755, ".skip-me": 0o644} {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(p, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := ReadDir(dir, func(rel string) bool { return rel == ".skip-me" })
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := []File{
+		{Path: "a/b.txt", Mode: 0o644, Content: []byte("a/b.txt")},
+		{Path: "run.sh", Mode: 0o755, Content: []byte("run.sh")},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("ReadDir:\n got %+v\nwant %+v", files, want)
+	}
+}
@@ -0,0 +1,110 @@
+package emitter
+
+import (
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// Credential is how the generated callEndpoint tool authenticates with one
+// security scheme: the secret read from EnvVar is sent as the In parameter
+// Name. Bearer and basic credentials go in the Authorization header.
+type Credential struct {
+	Scheme string // name in ServiceModel.SecuritySchemes
+	Kind   string // apiKey, bearer or basic
+	In     string // header, query or cookie
+	Name   string // header, query or cookie parameter name
+	EnvVar string // environment variable holding the secret; user:password for basic
+}
+
+// Credentials returns the credentials of the security schemes of sm the
+// generated projects can apply, sorted by scheme name. They are read from
+// <TOOL>_API_KEY, <TOOL>_BEARER_TOKEN (http bearer, oauth2 and openIdConnect
+// access tokens) and <TOOL>_BASIC_AUTH; when several schemes share a suffix,
+// each gets <TOOL>_<SCHEME>_<SUFFIX> instead. Other http schemes and apiKeys
+// without a parameter name are left out.
+func Credentials(toolName string, sm *genspec.ServiceModel) []Credential {
+	if sm == nil || len(sm.SecuritySchemes) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(sm.SecuritySchemes))
+	for name := range sm.SecuritySchemes {
+		names = append(names, name)
+	}
+	genspec.SortNames(names)
+
+	var out []Credential
+	suffixes := map[string]int{}
+	for _, name := range names {
+		s := sm.SecuritySchemes[name]
+		c := Credential{Scheme: name, In: "header", Name: "Authorization"}
+		switch {
+		case s.Type == "apiKey" && s.ParamName != "":
+			c.Kind, c.In, c.Name = "apiKey", strings.ToLower(s.In), s.ParamName
+			if c.In != "query" && c.In != "cookie" {
+				c.In = "header"
+			}
+		case s.Type == "http" && strings.EqualFold(s.Scheme, "bearer"), s.Type == "oauth2", s.Type == "openIdConnect":
+			c.Kind = "bearer"
+		case s.Type == "http" && strings.EqualFold(s.Scheme, "basic"):
+			c.Kind = "basic"
+		default:
+			continue
+		}
+		suffixes[envSuffix(c.Kind)]++
+		out = append(out, c)
+	}
+	prefix := envName(toolName)
+	for i := range out {
+		suffix := envSuffix(out[i].Kind)
+		if suffixes[suffix] > 1 {
+			suffix = envName(out[i].Scheme) + "_" + suffix
+		}
+		out[i].EnvVar = prefix + "_" + suffix
+	}
+	return out
+}
+
+func envSuffix(kind string) string {
+	switch kind {
+	case "bearer":
+		return "BEARER_TOKEN"
+	case "basic":
+		return "BASIC_AUTH"
+	}
+	return "API_KEY"
+}
+
+// envName upper-cases s and replaces what is not a letter or digit with _.
+func envName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "API"
+	}
+	return b.String()
+}
+
+// Placement describes in README prose where c is sent.
+func (c Credential) Placement() string {
+	switch {
+	case c.Kind == "bearer":
+		return "`Authorization: Bearer <token>` header"
+	case c.Kind == "basic":
+		return "`Authorization: Basic` header, given as user:password"
+	case c.In == "query":
+		return "`" + c.Name + "` query parameter"
+	case c.In == "cookie":
+		return "`" + c.Name + "` cookie"
+	}
+	return "`" + c.Name + "` header"
+}
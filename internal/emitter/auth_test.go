@@ -0,0 +1,57 @@
+package emitter
+
+import (
+	"reflect"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func TestCredentials(t *testing.T) {
+	sm := &genspec.ServiceModel{SecuritySchemes: map[string]genspec.SecurityScheme{
+		"apiKey":    {Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+		"bearer":    {Type: "http", Scheme: "Bearer"},
+		"oauth":     {Type: "oauth2"},
+		"basic":     {Type: "http", Scheme: "basic"},
+		"digest":    {Type: "http", Scheme: "digest"},
+		"unnamed":   {Type: "apiKey", In: "query"},
+		"cookieKey": {Type: "apiKey", In: "cookie", ParamName: "session"},
+	}}
+	want := []Credential{
+		{Scheme: "apiKey", Kind: "apiKey", In: "header", Name: "X-API-Key", EnvVar: "MY_TOOL_APIKEY_API_KEY"},
+		{Scheme: "basic", Kind: "basic", In: "header", Name: "Authorization", EnvVar: "MY_TOOL_BASIC_AUTH"},
+		{Scheme: "bearer", Kind: "bearer", In: "header", Name: "Authorization", EnvVar: "MY_TOOL_BEARER_BEARER_TOKEN"},
+		{Scheme: "cookieKey", Kind: "apiKey", In: "cookie", Name: "session", EnvVar: "MY_TOOL_COOKIEKEY_API_KEY"},
+		{Scheme: "oauth", Kind: "bearer", In: "header", Name: "Authorization", EnvVar: "MY_TOOL_OAUTH_BEARER_TOKEN"},
+	}
+	if got := Credentials("my-tool", sm); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Credentials =\n%+v\nwant\n%+v", got, want)
+	}
+
+	single := &genspec.ServiceModel{SecuritySchemes: map[string]genspec.SecurityScheme{
+		"key": {Type: "apiKey", In: "query", ParamName: "api_key"},
+	}}
+	if got := Credentials("pets", single); len(got) != 1 || got[0].EnvVar != "PETS_API_KEY" || got[0].In != "query" {
+		t.Fatalf("Credentials = %+v", got)
+	}
+	if got := Credentials("pets", &genspec.ServiceModel{}); got != nil {
+		t.Fatalf("expected no credentials, got %+v", got)
+	}
+}
+
+func TestCredentialPlacement(t *testing.T) {
+	for _, tc := range []struct {
+		c    Credential
+		want string
+	}{
+		{Credential{Kind: "apiKey", In: "header", Name: "X-API-Key"}, "`X-API-Key` header"},
+		{Credential{Kind: "apiKey", In: "query", Name: "api_key"}, "`api_key` query parameter"},
+		{Credential{Kind: "apiKey", In: "cookie", Name: "session"}, "`session` cookie"},
+		{Credential{Kind: "bearer", In: "header", Name: "Authorization"}, "`Authorization: Bearer <token>` header"},
+		{Credential{Kind: "basic", In: "header", Name: "Authorization"}, "`Authorization: Basic` header, given as user:password"},
+	} {
+		if got := tc.c.Placement(); got != tc.want {
+			t.Errorf("Placement(%+v) = %q, want %q", tc.c, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package brunoemitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// RequestsDir holds the generated .bru request files.
+const RequestsDir = "requests"
+
+// maxFileStem bounds request file and folder names, so machine-generated
+// paths stay well within file name and Windows path limits.
+const maxFileStem = 64
+
+// Options controls how the Bruno emitter renders a collection.
+type Options struct {
+	OutDir            string // required; target directory to write the collection
+	ToolName          string // collection name fallback when the spec has no title
+	Force             bool   // overwrite existing files
+	OverwriteModified bool   // with Force, also replace files edited since the last run and files it did not generate
+	Prune             bool   // delete files the last run generated that are no longer produced
+	Concurrency       int    // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun            bool   // don't write, only plan
+	Verbose           bool
+}
+
+// PlannedFile describes a file the emitter intends to write.
+type PlannedFile struct {
+	RelPath string
+	Size    int
+	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
+}
+
+// Result returns the planned files and final resolved names.
+type Result struct {
+	ToolName string
+	Planned  []PlannedFile
+	Skipped  bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome  manifest.Outcome // files kept or pruned because of the last run's manifest
+}
+
+// Emit renders a Bruno collection directory from the provided ServiceModel (IM):
+// a bruno.json manifest and one .bru file per endpoint under requests/. Tagged
+// endpoints go into a folder named after their first tag.
+func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result, error) {
+	_ = ctx
+	if sm == nil {
+		return nil, fmt.Errorf("brunoemitter: nil ServiceModel")
+	}
+	if strings.TrimSpace(opts.OutDir) == "" {
+		return nil, fmt.Errorf("brunoemitter: OutDir is required")
+	}
+	toolName := strings.TrimSpace(opts.ToolName)
+	if toolName == "" {
+		toolName = "mcp-tool"
+	}
+	name := strings.TrimSpace(sm.Title)
+	if name == "" {
+		name = toolName
+	}
+
+	files := map[string][]byte{}
+	collection, err := json.MarshalIndent(map[string]any{
+		"version": "1",
+		"name":    name,
+		"type":    "collection",
+		"ignore":  []string{"node_modules", ".git"},
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal bruno.json: %w", err)
+	}
+	files["bruno.json"] = append(collection, '\n')
+
+	base := ""
+	if len(sm.Servers) > 0 {
+		base = strings.TrimRight(strings.TrimSpace(sm.Servers[0].URL), "/")
+	}
+	if base == "" {
+		// Without a server URL, requests use an environment variable instead.
+		base = "{{baseUrl}}"
+		files[filepath.Join("environments", "default.bru")] = []byte("vars {\n  baseUrl: \n}\n")
+	}
+
+	seq := map[string]int{}
+	for i := range sm.Endpoints {
+		ep := &sm.Endpoints[i]
+		dir := RequestsDir
+		if len(ep.Tags) > 0 {
+			if folder := emitter.ShortName(fileSlug(ep.Tags[0]), maxFileStem); folder != "" {
+				dir = filepath.Join(RequestsDir, folder)
+			}
+		}
+		seq[dir]++
+		rel := filepath.Join(dir, uniqueName(files, dir, fileSlug(ep.ID))+".bru")
+		files[rel] = []byte(renderRequest(sm, ep, base, seq[dir]))
+	}
+
+	rels := make([]string, 0, len(files))
+	for p := range files {
+		rels = append(rels, filepath.ToSlash(p))
+	}
+	genspec.SortNames(rels)
+	planned := make([]PlannedFile, 0, len(rels))
+	for _, rel := range rels {
+		content := files[filepath.FromSlash(rel)]
+		planned = append(planned, PlannedFile{
+			RelPath: rel,
+			Size:    len(content),
+			Mode:    0o644,
+			SHA256:  manifest.HashBytes(content),
+			Change:  filewriter.Classify(opts.OutDir, rel, content, 0o644),
+		})
+	}
+
+	res := &Result{ToolName: toolName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "bruno", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		w := &filewriter.Writer{Prefix: "brunoemitter", Concurrency: opts.Concurrency, Force: opts.Force}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	}
+	return res, nil
+}
+
+// renderRequest renders one endpoint in Bruno's plaintext .bru format.
+func renderRequest(sm *genspec.ServiceModel, ep *genspec.EndpointModel, base string, seq int) string {
+	var b strings.Builder
+	name := strings.TrimSpace(ep.Summary)
+	if name == "" {
+		name = strings.ToUpper(string(ep.Method)) + " " + ep.Path
+	}
+	writeBlock(&b, "meta", []string{"name: " + name, "type: http", "seq: " + strconv.Itoa(seq)})
+
+	var query, path, headers []string
+	for _, p := range ep.Parameters {
+		entry := p.Name + ": "
+		switch p.In {
+		case "path":
+			path = append(path, entry)
+		case "query":
+			query = append(query, disabledPrefix(p.Required)+entry)
+		case "header":
+			headers = append(headers, disabledPrefix(p.Required)+entry)
+		}
+	}
+	example, hasBody := sm.JSONRequestExample(ep.RequestBody)
+	bodyMode := "none"
+	if hasBody {
+		bodyMode = "json"
+		headers = append(headers, "Content-Type: application/json")
+	}
+
+	// Bruno marks path parameters with a leading colon.
+	url := base + strings.NewReplacer("{", ":", "}", "").Replace(ep.Path)
+	writeBlock(&b, string(ep.Method), []string{"url: " + url, "body: " + bodyMode, "auth: none"})
+	if len(query) > 0 {
+		writeBlock(&b, "params:query", query)
+	}
+	if len(path) > 0 {
+		writeBlock(&b, "params:path", path)
+	}
+	if len(headers) > 0 {
+		writeBlock(&b, "headers", headers)
+	}
+	if hasBody {
+		raw, err := json.MarshalIndent(example, "", "  ")
+		if err != nil {
+			raw = []byte("{}")
+		}
+		writeBlock(&b, "body:json", strings.Split(string(raw), "\n"))
+	}
+	if status := successStatus(ep); status != "" {
+		writeBlock(&b, "assert", []string{"res.status: eq " + status})
+	}
+	if desc := strings.TrimSpace(ep.Description); desc != "" {
+		writeBlock(&b, "docs", strings.Split(desc, "\n"))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeBlock(b *strings.Builder, name string, lines []string) {
+	b.WriteString(name + " {\n")
+	for _, l := range lines {
+		if l == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString("  " + l + "\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+func disabledPrefix(required bool) string {
+	if required {
+		return ""
+	}
+	return "~"
+}
+
+// successStatus returns the first numeric 2xx response status, if any.
+func successStatus(ep *genspec.EndpointModel) string {
+	for _, r := range ep.Responses {
+		if len(r.Status) == 3 && r.Status[0] == '2' {
+			if _, err := strconv.Atoi(r.Status); err == nil {
+				return r.Status
+			}
+		}
+	}
+	return ""
+}
+
+// fileSlug turns an endpoint ID or tag into a file-system friendly name,
+// e.g. "get /pets/{id}" -> "get-pets-id".
+func fileSlug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(s) {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+// uniqueName appends a numeric suffix when slug already exists in dir;
+// names longer than maxFileStem are shortened with emitter.ShortName.
+func uniqueName(files map[string][]byte, dir, slug string) string {
+	if slug == "" {
+		slug = "request"
+	}
+	name := emitter.ShortName(slug, maxFileStem)
+	for i := 2; ; i++ {
+		if _, exists := files[filepath.Join(dir, name+".bru")]; !exists {
+			return name
+		}
+		name = emitter.ShortName(slug+"-"+strconv.Itoa(i), maxFileStem)
+	}
+}
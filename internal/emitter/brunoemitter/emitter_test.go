@@ -0,0 +1,199 @@
+package brunoemitter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func sampleModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Title:   "Pet Store",
+		Servers: []genspec.Server{{URL: "https://api.example.com/v1/"}},
+		Endpoints: []genspec.EndpointModel{
+			{
+				ID:      "get /pets/{petId}",
+				Method:  genspec.GET,
+				Path:    "/pets/{petId}",
+				Summary: "Get pet",
+				Tags:    []string{"pets"},
+				Parameters: []genspec.ParameterModel{
+					{Name: "petId", In: "path", Required: true},
+					{Name: "verbose", In: "query"},
+					{Name: "X-Trace", In: "header", Required: true},
+				},
+				Responses: []genspec.ResponseModel{{Status: "default"}, {Status: "200"}},
+			},
+			{
+				ID:          "post /pets",
+				Method:      genspec.POST,
+				Path:        "/pets",
+				Summary:     "Create pet",
+				Description: "Creates a pet.",
+				Tags:        []string{"pets", "write"},
+				RequestBody: &genspec.RequestBodyModel{Content: []genspec.Media{{
+					Mime:    "application/json",
+					Example: map[string]any{"name": "rex"},
+				}}},
+				Responses: []genspec.ResponseModel{{Status: "201"}},
+			},
+			{ID: "get /health", Method: genspec.GET, Path: "/health"},
+		},
+	}
+}
+
+func readFile(t *testing.T, dir, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("read %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestEmit_WritesCollection(t *testing.T) {
+	dir := t.TempDir()
+	res, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, ToolName: "pets"})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+
+	want := []string{
+		"bruno.json",
+		"requests/get-health.bru",
+		"requests/pets/get-pets-petId.bru",
+		"requests/pets/post-pets.bru",
+	}
+	if len(res.Planned) != len(want) {
+		t.Fatalf("planned: want %v got %+v", want, res.Planned)
+	}
+	for i, p := range res.Planned {
+		if p.RelPath != want[i] {
+			t.Fatalf("planned[%d]: want %s got %s", i, want[i], p.RelPath)
+		}
+	}
+
+	var manifest map[string]any
+	if err := json.Unmarshal([]byte(readFile(t, dir, "bruno.json")), &manifest); err != nil {
+		t.Fatalf("bruno.json is not valid JSON: %v", err)
+	}
+	if manifest["name"] != "Pet Store" || manifest["type"] != "collection" {
+		t.Fatalf("unexpected manifest: %v", manifest)
+	}
+
+	get := readFile(t, dir, "requests/pets/get-pets-petId.bru")
+	for _, part := range []string{
+		"meta {\n  name: Get pet\n  type: http\n  seq: 1\n}\n",
+		"get {\n  url: https://api.example.com/v1/pets/:petId\n  body: none\n  auth: none\n}\n",
+		"params:query {\n  ~verbose: \n}\n",
+		"params:path {\n  petId: \n}\n",
+		"headers {\n  X-Trace: \n}\n",
+		"assert {\n  res.status: eq 200\n}\n",
+	} {
+		if !strings.Contains(get, part) {
+			t.Fatalf("GET request missing %q:\n%s", part, get)
+		}
+	}
+	if !strings.HasSuffix(get, "}\n") || strings.HasSuffix(get, "\n\n") {
+		t.Fatalf("expected file to end with a single newline:\n%q", get)
+	}
+
+	post := readFile(t, dir, "requests/pets/post-pets.bru")
+	for _, part := range []string{
+		"seq: 2",
+		"post {\n  url: https://api.example.com/v1/pets\n  body: json\n  auth: none\n}\n",
+		"headers {\n  Content-Type: application/json\n}\n",
+		"body:json {\n  {\n    \"name\": \"rex\"\n  }\n}\n",
+		"assert {\n  res.status: eq 201\n}\n",
+		"docs {\n  Creates a pet.\n}\n",
+	} {
+		if !strings.Contains(post, part) {
+			t.Fatalf("POST request missing %q:\n%s", part, post)
+		}
+	}
+
+	health := readFile(t, dir, "requests/get-health.bru")
+	if !strings.Contains(health, "name: GET /health") || !strings.Contains(health, "get {\n  url: https://api.example.com/v1/health\n") {
+		t.Fatalf("unexpected untagged request:\n%s", health)
+	}
+}
+
+func TestEmit_NoServerUsesEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	sm := &genspec.ServiceModel{Endpoints: []genspec.EndpointModel{{ID: "delete /pets/{id}", Method: genspec.DELETE, Path: "/pets/{id}"}}}
+	if _, err := Emit(context.Background(), sm, Options{OutDir: dir}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	if env := readFile(t, dir, "environments/default.bru"); !strings.Contains(env, "baseUrl:") {
+		t.Fatalf("unexpected environment:\n%s", env)
+	}
+	if req := readFile(t, dir, "requests/delete-pets-id.bru"); !strings.Contains(req, "delete {\n  url: {{baseUrl}}/pets/:id\n") {
+		t.Fatalf("unexpected request:\n%s", req)
+	}
+}
+
+func TestEmit_DryRunAndErrors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, DryRun: true}); err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("dry-run should not create output, stat err=%v", err)
+	}
+	if _, err := Emit(context.Background(), nil, Options{OutDir: dir}); err == nil {
+		t.Fatalf("expected error for nil model")
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{}); err == nil {
+		t.Fatalf("expected error for empty OutDir")
+	}
+}
+
+func TestFileSlugAndUniqueName(t *testing.T) {
+	if got := fileSlug("get /pets/{id}/tags"); got != "get-pets-id-tags" {
+		t.Fatalf("fileSlug: got %q", got)
+	}
+	files := map[string][]byte{filepath.Join("requests", "a.bru"): nil}
+	if got := uniqueName(files, "requests", "a"); got != "a-2" {
+		t.Fatalf("uniqueName: got %q", got)
+	}
+}
+
+func TestEmit_LongPaths(t *testing.T) {
+	long := "/" + strings.Repeat("resources/{resourceId}/", 15)
+	sm := &genspec.ServiceModel{Title: "Long", Endpoints: []genspec.EndpointModel{
+		{ID: "get " + long + "a", Method: genspec.GET, Path: long + "a", Tags: []string{strings.Repeat("tag", 40)}},
+		{ID: "get " + long + "b", Method: genspec.GET, Path: long + "b", Tags: []string{strings.Repeat("tag", 40)}},
+		{ID: "get " + long + "a", Method: genspec.GET, Path: long + "a", Tags: []string{strings.Repeat("tag", 40)}},
+	}}
+	plan := func() []string {
+		res, err := Emit(context.Background(), sm, Options{OutDir: t.TempDir(), DryRun: true})
+		if err != nil {
+			t.Fatalf("emit: %v", err)
+		}
+		var rels []string
+		for _, p := range res.Planned {
+			if !strings.HasSuffix(p.RelPath, ".bru") || !strings.HasPrefix(p.RelPath, RequestsDir+"/") {
+				continue
+			}
+			for _, part := range strings.Split(p.RelPath, "/") {
+				if len(strings.TrimSuffix(part, ".bru")) > maxFileStem {
+					t.Fatalf("%s: %q exceeds %d bytes", p.RelPath, part, maxFileStem)
+				}
+			}
+			rels = append(rels, p.RelPath)
+		}
+		return rels
+	}
+	first := plan()
+	if len(first) != 3 {
+		t.Fatalf("want 3 distinct request files, got %v", first)
+	}
+	if again := plan(); strings.Join(again, ",") != strings.Join(first, ",") {
+		t.Fatalf("file names differ between runs:\n%v\n%v", first, again)
+	}
+}
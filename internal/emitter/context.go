@@ -0,0 +1,131 @@
+// Package emitter holds what the language emitters share with the user: the
+// TemplateContext that template overrides (--template-dir) are rendered with.
+package emitter
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"github.com/mark3labs/swagger2mcp/internal/specstats"
+)
+
+// TemplateContextVersion is the schema version of TemplateContext. Adding a
+// field keeps the version; removing one or changing its meaning bumps it.
+const TemplateContextVersion = 1
+
+// TemplateContext is the data a template override is rendered with, as a
+// text/template, by the go, npm and python emitters alike. The fields below
+// are the documented contract: testdata/template_context.tmpl references
+// every one of them and each emitter renders it in its tests. Only the
+// extras of the emitting language (Go, NPM or Python) are set.
+type TemplateContext struct {
+	SchemaVersion   int    // TemplateContextVersion
+	Lang            string // go, npm or python
+	ToolName        string // MCP tool and binary name
+	PackageName     string // Go module path, npm package name or Python package name
+	ServiceTitle    string // the spec's info.title
+	Version         string // project version
+	Author          string // project author and LICENSE copyright holder
+	AuthorEmail     string // author's e-mail address; may be empty
+	License         string // SPDX identifier of LICENSE; empty when none is written
+	Year            int    // copyright year in LICENSE
+	Library         bool   // library layout: only the spec package is generated
+	EnableInvoke    bool   // the callEndpoint tool is generated
+	PinDependencies bool   // dependencies are written at exact versions
+	Dockerfile      bool   // a Dockerfile, .dockerignore and Makefile docker targets are generated
+	CI              bool   // a CI workflow is generated (.github/workflows/ci.yml, or .gitlab-ci.yml for python's gitlab provider)
+	OTel            bool   // tool calls are traced with OpenTelemetry
+	ShardModel      bool   // the model is embedded as per-tag shards under model/ (see modelshard)
+	Skip            Skip   // categories of files left out of the project
+
+	ServiceModel *genspec.ServiceModel // the model the project embeds
+	Stats        *specstats.Stats      // counts over ServiceModel
+	Provenance   *manifest.Provenance  // how the model was built; nil when the caller does not record it
+	Limits       Limits
+	Credentials  []Credential // the credentials callEndpoint applies, from Credentials
+
+	Go     *GoContext
+	NPM    *NPMContext
+	Python *PythonContext
+}
+
+// Limits are the bounds the generated callEndpoint tool applies.
+type Limits struct {
+	MaxResponseBytes int           // response bodies are truncated to this many bytes
+	CallTimeout      time.Duration // request timeout when CallTimeoutEnv is unset
+	CallTimeoutEnv   string        // environment variable overriding CallTimeout
+	CallRetries      int           // retries of a failed request when CallRetriesEnv is unset
+	CallRetriesEnv   string        // environment variable overriding CallRetries
+}
+
+// GoContext holds the TemplateContext extras of the go emitter.
+type GoContext struct {
+	GoVersion     string // go directive in go.mod
+	MCPLibVersion string // required github.com/mark3labs/mcp-go version
+	Interfaces    bool   // the tools are routed through a Handler interface
+	Mocks         bool   // internal/mcp/mocks holds a testify MockHandler
+	LintConfig    bool   // .golangci.yml and a Makefile lint target are generated
+	// Binaries are the cmd/ directories of the per-tag binaries of
+	// SplitByTag; empty when the project builds the single cmd/<tool>.
+	Binaries []string
+}
+
+// NPMContext holds the TemplateContext extras of the npm emitter.
+type NPMContext struct {
+	BundleName string // PackageName safe as a file name: @scope/name becomes scope-name
+	ESM        bool   // ES module package; CommonJS otherwise
+	ZodSchemas bool   // src/spec/schemas.ts holds Zod schemas
+	TestRunner string // vitest or jest; what the generated tests run under
+}
+
+// PythonContext holds the TemplateContext extras of the python emitter.
+type PythonContext struct {
+	BuildTool      string // setuptools, uv or poetry
+	Version        string // target (and lowest supported) Python version, e.g. 3.9
+	Requires       string // requires-python specifier, e.g. >=3.9
+	MCPVersionSpec string // version specifier of the mcp dependency; empty when none is declared
+	UseRuff        bool   // lint with ruff instead of pylint
+	FastAPI        bool   // a FastAPI router and api_server.py are generated
+}
+
+// NewTemplateContext returns the language-neutral part of a TemplateContext for
+// sm; the emitter fills in the project settings and its extras.
+func NewTemplateContext(lang, toolName, packageName string, sm *genspec.ServiceModel) *TemplateContext {
+	c := &TemplateContext{
+		SchemaVersion: TemplateContextVersion,
+		Lang:          lang,
+		ToolName:      strings.TrimSpace(toolName),
+		PackageName:   strings.TrimSpace(packageName),
+		ServiceModel:  sm,
+		Limits: Limits{
+			MaxResponseBytes: 64 << 10,
+			CallTimeout:      30 * time.Second,
+			CallTimeoutEnv:   "API_TIMEOUT",
+			CallRetriesEnv:   "API_RETRIES",
+		},
+	}
+	if sm != nil {
+		c.ServiceTitle = strings.TrimSpace(sm.Title)
+		c.Stats = specstats.Analyze(sm)
+		c.Credentials = Credentials(c.ToolName, sm)
+	}
+	return c
+}
+
+// Render executes text, the override of the generated file name, with c. A
+// reference to a field c does not have is an error.
+func (c *TemplateContext) Render(name, text string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, c); err != nil {
+		return "", fmt.Errorf("execute: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,72 @@
+package emitter
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func readProbe(t *testing.T) string {
+	t.Helper()
+	probe, err := os.ReadFile("testdata/template_context.tmpl")
+	if err != nil {
+		t.Fatalf("read probe: %v", err)
+	}
+	return string(probe)
+}
+
+// The probe each emitter renders must reference every field of the contract,
+// so that a removal fails the emitter tests.
+func TestTemplateContextProbeCoversFields(t *testing.T) {
+	probe := readProbe(t)
+	for _, v := range []any{TemplateContext{}, Skip{}, Limits{}, Credential{}, GoContext{}, NPMContext{}, PythonContext{}} {
+		typ := reflect.TypeOf(v)
+		for i := 0; i < typ.NumField(); i++ {
+			name := typ.Field(i).Name
+			if !regexp.MustCompile(`\.` + name + `\b`).MatchString(probe) {
+				t.Errorf("testdata/template_context.tmpl does not reference %s.%s", typ.Name(), name)
+			}
+		}
+	}
+}
+
+func TestTemplateContextRender(t *testing.T) {
+	sm := &genspec.ServiceModel{
+		Title:     " Pets ",
+		Endpoints: []genspec.EndpointModel{{ID: "get /pets", Method: genspec.GET, Path: "/pets"}},
+		SecuritySchemes: map[string]genspec.SecurityScheme{
+			"key": {Name: "key", Type: "apiKey", In: "query", ParamName: "api_key"},
+		},
+	}
+	c := NewTemplateContext("npm", "pets", "@acme/pets", sm)
+	c.Provenance = &manifest.Provenance{Generator: "1.2.3", Input: "pets.yaml"}
+	c.NPM = &NPMContext{BundleName: "pets", ESM: true, TestRunner: "vitest"}
+	out, err := c.Render("README.md", readProbe(t))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{
+		"schema 1 lang npm\n",
+		"tool pets package @acme/pets title Pets version \n",
+		"endpoints 1 stats 1\n",
+		"input pets.yaml generator 1.2.3\n",
+		"limits 65536 30s API_TIMEOUT 0 API_RETRIES\n",
+		"\n\nnpm pets esm true zod false tests vitest\n\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered probe lacks %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := c.Render("README.md", "{{.Nope}}"); err == nil || !strings.Contains(err.Error(), "Nope") {
+		t.Fatalf("expected an error for an unknown field, got %v", err)
+	}
+	if _, err := c.Render("README.md", "{{.ToolName"); err == nil || !strings.Contains(err.Error(), "parse") {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package docs renders Markdown references of a ServiceModel. The code
+// emitters' EmitDocs option adds Render's single-page reference to the
+// generated project as File, so the Go, TypeScript and Python projects carry
+// the same text; markdownemitter builds its multi-page site from the same
+// Writer.
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// File is where the emitters write the reference, relative to OutDir.
+const File = "docs/API.md"
+
+// UntaggedTitle groups endpoints without tags.
+const UntaggedTitle = "Other"
+
+// Group is a tag and the endpoints it is the first tag of.
+type Group struct {
+	Title     string
+	Endpoints []*genspec.EndpointModel
+}
+
+// GroupByTag buckets endpoints by first tag, keeping the order in which tags
+// first appear; untagged endpoints go in a trailing "Other" group.
+func GroupByTag(sm *genspec.ServiceModel) []*Group {
+	var groups []*Group
+	byTag := map[string]*Group{}
+	var untagged *Group
+	for i := range sm.Endpoints {
+		ep := &sm.Endpoints[i]
+		tag := ""
+		if len(ep.Tags) > 0 {
+			tag = strings.TrimSpace(ep.Tags[0])
+		}
+		if tag == "" {
+			if untagged == nil {
+				untagged = &Group{Title: UntaggedTitle}
+			}
+			untagged.Endpoints = append(untagged.Endpoints, ep)
+			continue
+		}
+		g, ok := byTag[tag]
+		if !ok {
+			g = &Group{Title: tag}
+			byTag[tag] = g
+			groups = append(groups, g)
+		}
+		g.Endpoints = append(g.Endpoints, ep)
+	}
+	if untagged != nil {
+		groups = append(groups, untagged)
+	}
+	return groups
+}
+
+// anchors are the heading anchors of one rendering of the reference.
+type anchors struct {
+	groups    map[*Group]string
+	endpoints map[*genspec.EndpointModel]string
+	schemas   map[string]string
+	appendix  string
+}
+
+// Render returns the API reference of sm as one Markdown document titled
+// title: the servers, a table of contents, a section per tag with a table of
+// its endpoints followed by the endpoints' parameter and response tables,
+// and a schemas appendix. Every link targets a heading of the document.
+func Render(sm *genspec.ServiceModel, title string) string {
+	groups := GroupByTag(sm)
+	// Links precede the headings they target, so the first pass only
+	// collects the anchors; headings do not depend on links, so the second
+	// pass assigns the same ones.
+	_, links := render(sm, title, groups, &anchors{})
+	out, _ := render(sm, title, groups, links)
+	return out
+}
+
+func render(sm *genspec.ServiceModel, title string, groups []*Group, links *anchors) (string, *anchors) {
+	got := &anchors{
+		groups:    map[*Group]string{},
+		endpoints: map[*genspec.EndpointModel]string{},
+		schemas:   map[string]string{},
+	}
+	w := &Writer{Level: 3, SchemaLink: func(name string) string {
+		if id, ok := links.schemas[name]; ok {
+			return "#" + id
+		}
+		return ""
+	}}
+	names := make([]string, 0, len(sm.Schemas))
+	for name := range sm.Schemas {
+		names = append(names, name)
+	}
+	genspec.SortNames(names)
+
+	w.Heading(1, title)
+	if v := strings.TrimSpace(sm.Version); v != "" {
+		fmt.Fprintf(w, "Version: `%s`\n\n", v)
+	}
+	if d := strings.TrimSpace(sm.Description); d != "" {
+		w.WriteString(d + "\n\n")
+	}
+	if len(sm.Servers) > 0 {
+		w.Heading(2, "Servers")
+		for _, s := range sm.Servers {
+			line := "- `" + s.URL + "`"
+			if s.Development {
+				line += " _(development)_"
+			}
+			if d := strings.TrimSpace(s.Description); d != "" {
+				line += " - " + d
+			}
+			w.WriteString(line + "\n")
+		}
+		w.WriteString("\n")
+	}
+	w.Heading(2, "Contents")
+	for _, g := range groups {
+		fmt.Fprintf(w, "- [%s](#%s) (%d endpoint(s))\n", g.Title, links.groups[g], len(g.Endpoints))
+	}
+	if len(names) > 0 {
+		fmt.Fprintf(w, "- [Schemas](#%s) (%d schema(s))\n", links.appendix, len(names))
+	}
+	w.WriteString("\n")
+
+	for _, g := range groups {
+		got.groups[g] = w.Heading(2, g.Title)
+		if d := strings.TrimSpace(sm.TagDescriptions[g.Title]); d != "" {
+			w.WriteString(d + "\n\n")
+		}
+		w.WriteString("| Method | Path | Summary |\n")
+		w.WriteString("| --- | --- | --- |\n")
+		for _, ep := range g.Endpoints {
+			summary := Cell(ep.Summary)
+			switch {
+			case ep.Deprecated && summary == "-":
+				summary = "_(deprecated)_"
+			case ep.Deprecated:
+				summary += " _(deprecated)_"
+			}
+			fmt.Fprintf(w, "| `%s` | [`%s`](#%s) | %s |\n",
+				strings.ToUpper(string(ep.Method)), ep.Path, links.endpoints[ep], summary)
+		}
+		w.WriteString("\n")
+		for _, ep := range g.Endpoints {
+			got.endpoints[ep] = w.Endpoint(ep)
+		}
+	}
+
+	if len(names) > 0 {
+		got.appendix = w.Heading(2, "Schemas")
+		for _, name := range names {
+			got.schemas[name] = w.Schema(name, sm.Schemas[name])
+			if !strings.HasSuffix(w.String(), "\n\n") {
+				w.WriteString("\n")
+			}
+		}
+	}
+	return strings.TrimRight(w.String(), "\n") + "\n", got
+}
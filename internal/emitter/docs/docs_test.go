@@ -0,0 +1,169 @@
+package docs
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func sampleModel() *genspec.ServiceModel {
+	petRef := &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Pet"}}
+	return &genspec.ServiceModel{
+		Title:           "Pet Store",
+		Version:         "1.2.0",
+		Servers:         []genspec.Server{{URL: "https://api.example.com/v1", Description: "production"}},
+		TagDescriptions: map[string]string{"Pet": "Everything about pets."},
+		Endpoints: []genspec.EndpointModel{
+			{
+				ID:      "get /pets/{petId}",
+				Method:  genspec.GET,
+				Path:    "/pets/{petId}",
+				Summary: "Get pet",
+				Tags:    []string{"Pet"},
+				Parameters: []genspec.ParameterModel{
+					{Name: "petId", In: "path", Required: true, Description: "Pet | identifier", Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "integer", Format: "int64"}}},
+				},
+				Responses: []genspec.ResponseModel{
+					{Status: "200", Description: "The pet", Content: []genspec.Media{{Mime: "application/json", Schema: petRef}}},
+					{Status: "404", Description: "Not found"},
+				},
+			},
+			{
+				ID:         "get /v2/pets/{petId}",
+				Method:     genspec.GET,
+				Path:       "/v2/pets/{petId}",
+				Summary:    "Get pet",
+				Tags:       []string{"Pet"},
+				Deprecated: true,
+				Parameters: []genspec.ParameterModel{{Name: "petId", In: "path", Required: true}},
+				Responses:  []genspec.ResponseModel{{Status: "200", Description: "ok"}},
+			},
+			{
+				ID:     "post /stores",
+				Method: genspec.POST,
+				Path:   "/stores",
+				Tags:   []string{"Store Admin"},
+				RequestBody: &genspec.RequestBodyModel{Required: true, Content: []genspec.Media{
+					{Mime: "application/json", Schema: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Store"}}},
+				}},
+			},
+			{ID: "get /health", Method: genspec.GET, Path: "/health", Summary: "???"},
+		},
+		Schemas: map[string]genspec.Schema{
+			"Pet": {Name: "Pet", Type: "object", Required: []string{"id"}, Properties: map[string]*genspec.SchemaOrRef{
+				"id":   {Schema: &genspec.Schema{Type: "integer", Description: "Unique id"}},
+				"tags": {Schema: &genspec.Schema{Type: "array", Items: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Tag"}}}},
+			}},
+			"Store":      {Name: "Store", Type: "object", Properties: map[string]*genspec.SchemaOrRef{"pets": {Schema: &genspec.Schema{Type: "array", Items: petRef}}}},
+			"Parameters": {Name: "Parameters", Excluded: true},
+		},
+	}
+}
+
+var (
+	headingLine = regexp.MustCompile(`(?m)^#{1,6} (.+)$`)
+	localLink   = regexp.MustCompile(`\]\(#([^)]*)\)`)
+)
+
+// headingAnchors lists the anchors GitHub gives the headings of doc, in
+// order, numbering repeats as github-slugger does.
+func headingAnchors(doc string) []string {
+	var out []string
+	seen := map[string]int{}
+	for _, m := range headingLine.FindAllStringSubmatch(doc, -1) {
+		base := Anchor(m[1])
+		id := base
+		for {
+			if _, taken := seen[id]; !taken {
+				break
+			}
+			seen[base]++
+			id = base + "-" + strconv.Itoa(seen[base])
+		}
+		seen[id] = 0
+		out = append(out, id)
+	}
+	return out
+}
+
+func TestRender_LinksResolve(t *testing.T) {
+	doc := Render(sampleModel(), "Pet Store")
+	defined := map[string]bool{}
+	for _, id := range headingAnchors(doc) {
+		defined[id] = true
+	}
+	links := localLink.FindAllStringSubmatch(doc, -1)
+	if len(links) == 0 {
+		t.Fatalf("no in-document links:\n%s", doc)
+	}
+	for _, m := range links {
+		if !defined[m[1]] {
+			t.Errorf("link #%s has no heading (anchors %v)\n%s", m[1], headingAnchors(doc), doc)
+		}
+	}
+
+	for _, want := range []string{
+		// the Pet tag takes "pet", so the Pet schema is "pet-1"
+		"- [Pet](#pet) (2 endpoint(s))\n",
+		"- [Schemas](#schemas) (3 schema(s))\n",
+		"| `GET` | [`/pets/{petId}`](#get-pet) | Get pet |\n",
+		"| `GET` | [`/v2/pets/{petId}`](#get-pet-1) | Get pet _(deprecated)_ |\n",
+		"| `POST` | [`/stores`](#post-stores) | - |\n",
+		"| `GET` | [`/health`](#get-health) | ??? |\n",
+		"| 200 | The pet | `application/json` [Pet](#pet-1) |\n",
+		"- `application/json`: [Store](#store)\n",
+		"| `pets` | array of [Pet](#pet-1) | no | - |\n",
+		// Tag is not a schema of the model, so it is not linked
+		"| `tags` | array of `Tag` | no | - |\n",
+		"| `petId` | path | `integer` (int64) | yes | Pet \\| identifier |\n",
+		"## Pet\n\nEverything about pets.\n\n| Method | Path | Summary |\n",
+		"### Parameters\n\n_Excluded by schema filter._\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("API.md missing %q:\n%s", want, doc)
+		}
+	}
+	if !strings.HasSuffix(doc, "| `pets` | array of [Pet](#pet-1) | no | - |\n") {
+		t.Errorf("API.md should end with the last schema's table and one newline:\n%q", doc[len(doc)-40:])
+	}
+}
+
+func TestRender_Deterministic(t *testing.T) {
+	first := Render(sampleModel(), "Pet Store")
+	for i := 0; i < 5; i++ {
+		if got := Render(sampleModel(), "Pet Store"); got != first {
+			t.Fatalf("render %d differs:\n%s\n---\n%s", i, got, first)
+		}
+	}
+	if !strings.HasPrefix(first, "# Pet Store\n\nVersion: `1.2.0`\n\n## Servers\n\n- `https://api.example.com/v1` - production\n\n## Contents\n") {
+		t.Errorf("unexpected header:\n%s", first)
+	}
+	// tag sections in first-appearance order, untagged last, schemas after
+	var order []int
+	for _, h := range []string{"\n## Pet\n", "\n## Store Admin\n", "\n## Other\n", "\n## Schemas\n", "\n### Parameters\n\n_Excluded", "\n### Pet\n", "\n### Store\n"} {
+		order = append(order, strings.Index(first, h))
+	}
+	for i := 1; i < len(order); i++ {
+		if order[i-1] < 0 || order[i] <= order[i-1] {
+			t.Fatalf("section order %v:\n%s", order, first)
+		}
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	for in, want := range map[string]string{
+		"Get pet":           "get-pet",
+		"GET /pets/{petId}": "get-petspetid",
+		"Store Admin":       "store-admin",
+		"snake_case-Name":   "snake_case-name",
+		"查询 用户":             "查询-用户",
+		"???":               "",
+	} {
+		if got := Anchor(in); got != want {
+			t.Errorf("Anchor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package docs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// Writer renders parts of a ServiceModel as CommonMark. It keeps the anchors
+// of the headings it wrote, so links within one document can target them.
+type Writer struct {
+	strings.Builder
+	// Level is the heading level of endpoint and schema titles; their
+	// sections are one level deeper.
+	Level int
+	// SchemaLink returns the link target of a named schema, e.g.
+	// "schemas.md#pet" or "#pet".
+	SchemaLink func(name string) string
+
+	seen map[string]int
+}
+
+// Heading writes a heading of level and returns its anchor: the GitHub slug
+// of text, with "-1", "-2"... appended to repeats within the document.
+func (w *Writer) Heading(level int, text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	fmt.Fprintf(w, "%s %s\n\n", strings.Repeat("#", level), text)
+	if w.seen == nil {
+		w.seen = map[string]int{}
+	}
+	base := Anchor(text)
+	id := base
+	for {
+		if _, taken := w.seen[id]; !taken {
+			break
+		}
+		w.seen[base]++
+		id = base + "-" + strconv.Itoa(w.seen[base])
+	}
+	w.seen[id] = 0
+	return id
+}
+
+// Endpoint writes the section of ep, titled by its summary (or method and
+// path), with tables of its parameters and responses. It returns the
+// section's anchor.
+func (w *Writer) Endpoint(ep *genspec.EndpointModel) string {
+	method := strings.ToUpper(string(ep.Method))
+	heading := strings.TrimSpace(ep.Summary)
+	if Anchor(heading) == "" {
+		heading = method + " " + ep.Path
+	}
+	id := w.Heading(w.Level, heading)
+	fmt.Fprintf(w, "`%s` `%s`\n\n", method, ep.Path)
+	if d := strings.TrimSpace(ep.Description); d != "" {
+		w.WriteString(d + "\n\n")
+	}
+
+	if len(ep.Parameters) > 0 {
+		w.Heading(w.Level+1, "Parameters")
+		w.WriteString("| Name | In | Type | Required | Description |\n")
+		w.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, p := range ep.Parameters {
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+				p.Name, p.In, w.FieldType(p.Schema), YesNo(p.Required), Cell(p.Description))
+		}
+		w.WriteString("\n")
+	}
+
+	if rb := ep.RequestBody; rb != nil && len(rb.Content) > 0 {
+		w.Heading(w.Level+1, "Request body")
+		if rb.Required {
+			w.WriteString("Required.\n\n")
+		}
+		for _, m := range rb.Content {
+			fmt.Fprintf(w, "- `%s`: %s\n", m.Mime, w.TypeLabel(m.Schema))
+		}
+		w.WriteString("\n")
+	}
+
+	if len(ep.Responses) > 0 {
+		w.Heading(w.Level+1, "Responses")
+		w.WriteString("| Status | Description | Content type |\n")
+		w.WriteString("| --- | --- | --- |\n")
+		for _, r := range ep.Responses {
+			fmt.Fprintf(w, "| %s | %s | %s |\n", r.Status, Cell(r.Description), w.contentCell(r.Content))
+		}
+		w.WriteString("\n")
+	}
+	return id
+}
+
+// Schema writes the section of the named schema: its type, description,
+// enum values and a table of its properties. It returns the section's
+// anchor.
+func (w *Writer) Schema(name string, sc genspec.Schema) string {
+	id := w.Heading(w.Level, name)
+	if sc.Excluded {
+		w.WriteString("_Excluded by schema filter._\n")
+		return id
+	}
+	fmt.Fprintf(w, "Type: %s\n\n", w.FieldType(&genspec.SchemaOrRef{Schema: &sc}))
+	if d := strings.TrimSpace(sc.Description); d != "" {
+		w.WriteString(d + "\n\n")
+	}
+	if len(sc.Enum) > 0 {
+		vals := make([]string, 0, len(sc.Enum))
+		for _, v := range sc.Enum {
+			vals = append(vals, fmt.Sprintf("`%v`", v))
+		}
+		w.WriteString("Enum: " + strings.Join(vals, ", ") + "\n\n")
+	}
+	if len(sc.Properties) > 0 {
+		required := map[string]bool{}
+		for _, r := range sc.Required {
+			required[r] = true
+		}
+		props := make([]string, 0, len(sc.Properties))
+		for p := range sc.Properties {
+			props = append(props, p)
+		}
+		genspec.SortNames(props)
+		w.WriteString("| Property | Type | Required | Description |\n")
+		w.WriteString("| --- | --- | --- | --- |\n")
+		for _, p := range props {
+			prop := sc.Properties[p]
+			desc := ""
+			if prop != nil && prop.Schema != nil {
+				desc = prop.Schema.Description
+			}
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", p, w.FieldType(prop), YesNo(required[p]), Cell(desc))
+		}
+		w.WriteString("\n")
+	}
+	return id
+}
+
+// TypeLabel renders a schema as inline Markdown: simple types inline, named
+// schemas as links to SchemaLink, or as code when it returns "".
+func (w *Writer) TypeLabel(sor *genspec.SchemaOrRef) string {
+	if sor == nil {
+		return "any"
+	}
+	if sor.Ref != nil {
+		name := sor.Ref.Ref[strings.LastIndex(sor.Ref.Ref, "/")+1:]
+		if target := w.SchemaLink(name); target != "" {
+			return fmt.Sprintf("[%s](%s)", name, target)
+		}
+		return "`" + name + "`"
+	}
+	sc := sor.Schema
+	if sc == nil {
+		return "any"
+	}
+	switch {
+	case sc.Type == "array":
+		return "array of " + w.TypeLabel(sc.Items)
+	case len(sc.OneOf) > 0:
+		return "one of " + w.joinLabels(sc.OneOf)
+	case len(sc.AnyOf) > 0:
+		return "any of " + w.joinLabels(sc.AnyOf)
+	case len(sc.AllOf) > 0:
+		return "all of " + w.joinLabels(sc.AllOf)
+	case sc.Type == "":
+		return "any"
+	}
+	if sc.Format != "" {
+		return fmt.Sprintf("`%s` (%s)", sc.Type, sc.Format)
+	}
+	return "`" + sc.Type + "`"
+}
+
+// FieldType is TypeLabel followed by any inline schema constraints, e.g.
+// "array of `string` (`minItems=2`, `uniqueItems`)".
+func (w *Writer) FieldType(sor *genspec.SchemaOrRef) string {
+	label := w.TypeLabel(sor)
+	if sor == nil || sor.Schema == nil {
+		return label
+	}
+	constraints := sor.Schema.Constraints()
+	if len(constraints) == 0 {
+		return label
+	}
+	return label + " (`" + strings.Join(constraints, "`, `") + "`)"
+}
+
+func (w *Writer) joinLabels(list []*genspec.SchemaOrRef) string {
+	labels := make([]string, 0, len(list))
+	for _, s := range list {
+		labels = append(labels, w.TypeLabel(s))
+	}
+	return strings.Join(labels, ", ")
+}
+
+func (w *Writer) contentCell(content []genspec.Media) string {
+	if len(content) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(content))
+	for _, m := range content {
+		part := "`" + m.Mime + "`"
+		if m.Schema != nil {
+			part += " " + w.TypeLabel(m.Schema)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "<br>")
+}
+
+// Cell makes free text safe inside a Markdown table cell.
+func Cell(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "-"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// YesNo renders a flag in a table cell.
+func YesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// Anchor returns the slug GitHub gives a heading: lowercase, spaces become
+// dashes, punctuation other than "-" and "_" is dropped, and letters and
+// digits of any script are kept.
+func Anchor(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
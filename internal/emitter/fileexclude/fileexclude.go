@@ -0,0 +1,74 @@
+// Package fileexclude leaves generated files out of a project, for users
+// whose own tooling replaces them (lint configs, pre-commit hooks, ...).
+package fileexclude
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Essential reports whether the project does not build without the file at
+// the slash-separated relative path rel.
+type Essential func(rel string) bool
+
+// Validate checks that every pattern is a valid path.Match glob.
+func Validate(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether the slash-separated relative path rel matches
+// pattern: a path.Match glob against the whole path or, when pattern has no
+// "/", against the file name, so ".pylintrc" and "*.ini" match at any depth.
+func Match(pattern, rel string) bool {
+	if ok, _ := path.Match(pattern, rel); ok {
+		return true
+	}
+	if strings.Contains(pattern, "/") {
+		return false
+	}
+	ok, _ := path.Match(pattern, path.Base(rel))
+	return ok
+}
+
+// Apply removes the entries of files (keyed by relative path) that match one
+// of patterns. It removes nothing and fails when a pattern is invalid or
+// matches a file essential reports the build needs.
+func Apply(patterns []string, files map[string][]byte, essential Essential) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	if err := Validate(patterns); err != nil {
+		return err
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	var drop []string
+	for _, rel := range rels {
+		slash := filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if !Match(pattern, slash) {
+				continue
+			}
+			if essential != nil && essential(slash) {
+				return fmt.Errorf("exclude pattern %q matches %s, which the project needs to build", pattern, slash)
+			}
+			drop = append(drop, rel)
+			break
+		}
+	}
+	for _, rel := range drop {
+		delete(files, rel)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package fileexclude
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{".pylintrc", ".pylintrc", true},
+		{"*.ini", "mypy.ini", true},
+		{"launch.json", ".vscode/launch.json", true},
+		{".vscode/*", ".vscode/launch.json", true},
+		{".vscode/*", "other/.vscode/launch.json", false},
+		{"tests/*.py", "tests/test_server.py", true},
+		{"tests/*.py", "src/tests/test_server.py", false},
+		{"*.ini", "setup.cfg", false},
+	} {
+		if got := Match(tc.pattern, tc.rel); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tc.pattern, tc.rel, got, tc.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	files := map[string][]byte{
+		".pylintrc":                        nil,
+		".pre-commit-config.yaml":          nil,
+		"mypy.ini":                         nil,
+		"pyproject.toml":                   nil,
+		filepath.Join(".vscode", "a.json"): nil,
+	}
+	essential := func(rel string) bool { return rel == "pyproject.toml" }
+	if err := Apply([]string{".pylintrc", "*.yaml", ".vscode/*", "nothing.txt"}, files, essential); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	var left []string
+	for rel := range files {
+		left = append(left, rel)
+	}
+	sort.Strings(left)
+	if want := []string{"mypy.ini", "pyproject.toml"}; !reflect.DeepEqual(left, want) {
+		t.Fatalf("files left = %v, want %v", left, want)
+	}
+
+	err := Apply([]string{"mypy.ini", "*.toml"}, files, essential)
+	if err == nil || !strings.Contains(err.Error(), `"*.toml" matches pyproject.toml`) {
+		t.Fatalf("expected an error for an essential file, got %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("a refused exclusion must remove nothing: %v", files)
+	}
+	if err := Apply([]string{"[a-"}, files, essential); err == nil || !strings.Contains(err.Error(), "invalid exclude pattern") {
+		t.Fatalf("expected an invalid pattern error, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Package filewriter writes emitter output to disk. Files are written
+// atomically (temp file + rename) by a pool of workers so that projects with
+// hundreds of files are not bottlenecked on one fsync at a time.
+package filewriter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Writer writes a set of files under an output directory.
+type Writer struct {
+	Prefix      string                       // error message prefix, e.g. "goemitter"
+	Concurrency int                          // number of workers; <= 0 uses runtime.NumCPU()
+	Force       bool                         // allow writing into a non-empty directory
+	Mode        func(rel string) os.FileMode // per-file permissions; nil means 0o644
+}
+
+// Check resolves outDir and verifies it can be written to: it must either not
+// exist yet, or be a directory that is empty (unless Force is set). It returns
+// the absolute output path.
+func (w *Writer) Check(outDir string) (string, error) {
+	abs, err := filepath.Abs(outDir)
+	if err != nil {
+		return "", w.errorf("resolve output directory: %w", err)
+	}
+	st, err := os.Stat(abs)
+	if os.IsNotExist(err) {
+		return abs, nil
+	}
+	if err != nil {
+		return "", w.errorf("cannot access output directory %q: %w", abs, err)
+	}
+	if !st.IsDir() {
+		return "", w.errorf("output path %q is not a directory", abs)
+	}
+	if w.Force {
+		return abs, nil
+	}
+	entries, err := os.ReadDir(abs)
+	if err != nil {
+		return "", w.errorf("cannot read output directory %q: %w", abs, err)
+	}
+	if len(entries) > 0 {
+		return "", w.errorf("output directory %q is not empty (use --force to overwrite)", abs)
+	}
+	return abs, nil
+}
+
+// Write checks outDir and writes every file in files (keyed by slash-separated
+// relative path). Failures of individual files do not stop the others; all of
+// them are reported together as a joined error, ordered by path.
+func (w *Writer) Write(outDir string, files map[string][]byte) error {
+	abs, err := w.Check(outDir)
+	if err != nil {
+		return err
+	}
+
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	// Create directories up front so workers only ever write files.
+	dirs := map[string]bool{abs: true}
+	for _, rel := range rels {
+		dir := filepath.Dir(filepath.Join(abs, filepath.FromSlash(rel)))
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return w.errorf("mkdir %s: %w", dir, err)
+		}
+	}
+
+	workers := w.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(rels) {
+		workers = len(rels)
+	}
+
+	errs := make([]error, len(rels))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				rel := rels[idx]
+				if err := WriteFileAtomic(abs, rel, files[rel], w.mode(rel)); err != nil {
+					errs[idx] = w.errorf("write %s: %w", rel, err)
+				}
+			}
+		}()
+	}
+	for idx := range rels {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (w *Writer) mode(rel string) os.FileMode {
+	if w.Mode == nil {
+		return 0o644
+	}
+	return w.Mode(rel)
+}
+
+func (w *Writer) errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if w.Prefix == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", w.Prefix, err)
+}
+
+// WriteFileAtomic writes content to baseDir/relPath by writing a temporary
+// file in the same directory, syncing it and renaming it into place, so
+// readers never observe a partially written file.
+func WriteFileAtomic(baseDir, relPath string, content []byte, mode os.FileMode) error {
+	fullPath := filepath.Join(baseDir, filepath.FromSlash(relPath))
+	dir := filepath.Dir(fullPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("ensure target directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-"+filepath.Base(fullPath)+"-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	success := false
+	defer func() {
+		if !success {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("set file permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		return fmt.Errorf("rename into place: %w", err)
+	}
+	success = true
+	return nil
+}
+
+// Change classifies a planned file against what is already on disk.
+type Change string
+
+const (
+	Create    Change = "create"    // the file does not exist yet
+	Update    Change = "update"    // the file exists with different content or permissions
+	Unchanged Change = "unchanged" // the file already has this content and mode
+)
+
+// Classify reports how writing content with mode to rel under outDir would
+// change the file on disk. Unreadable files count as updates.
+func Classify(outDir, rel string, content []byte, mode os.FileMode) Change {
+	path := filepath.Join(outDir, filepath.FromSlash(rel))
+	st, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return Create
+	}
+	if err != nil || st.IsDir() || st.Mode().Perm() != mode.Perm() || st.Size() != int64(len(content)) {
+		return Update
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(existing, content) {
+		return Update
+	}
+	return Unchanged
+}
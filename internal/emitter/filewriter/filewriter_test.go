@@ -0,0 +1,161 @@
+package filewriter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func sampleFiles(n int) map[string][]byte {
+	files := make(map[string][]byte, n)
+	for i := 0; i < n; i++ {
+		rel := fmt.Sprintf("pkg%02d/file%03d.txt", i%10, i)
+		files[rel] = []byte(strings.Repeat(fmt.Sprintf("line %d\n", i), 64))
+	}
+	return files
+}
+
+func TestWrite_WritesAllFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	files := sampleFiles(50)
+	w := &Writer{Prefix: "test", Concurrency: 4}
+	if err := w.Write(dir, files); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for rel, want := range files {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("%s: content mismatch", rel)
+		}
+	}
+	// No temp files are left behind.
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasPrefix(info.Name(), ".tmp-") {
+			t.Fatalf("leftover temp file %s", path)
+		}
+		return nil
+	})
+}
+
+func TestWrite_Modes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not meaningful on windows")
+	}
+	dir := t.TempDir()
+	w := &Writer{Mode: func(rel string) os.FileMode {
+		if rel == "run.sh" {
+			return 0o755
+		}
+		return 0o644
+	}}
+	if err := w.Write(dir, map[string][]byte{"run.sh": []byte("#!/bin/sh\n"), "a.txt": []byte("a")}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for rel, want := range map[string]os.FileMode{"run.sh": 0o755, "a.txt": 0o644} {
+		st, err := os.Stat(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("stat %s: %v", rel, err)
+		}
+		if st.Mode().Perm() != want {
+			t.Fatalf("%s: want mode %o got %o", rel, want, st.Mode().Perm())
+		}
+	}
+}
+
+func TestWrite_OutputDirChecks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "existing"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w := &Writer{Prefix: "test"}
+	err := w.Write(dir, map[string][]byte{"a.txt": []byte("a")})
+	if err == nil || !strings.Contains(err.Error(), "test: output directory") || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("expected not-empty error, got %v", err)
+	}
+	w.Force = true
+	if err := w.Write(dir, map[string][]byte{"a.txt": []byte("a")}); err != nil {
+		t.Fatalf("force write: %v", err)
+	}
+	if _, err := w.Check(filepath.Join(dir, "existing")); err == nil || !strings.Contains(err.Error(), "is not a directory") {
+		t.Fatalf("expected not-a-directory error, got %v", err)
+	}
+}
+
+func TestWrite_JoinsErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory permissions are not enforced on windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores directory permissions")
+	}
+	dir := t.TempDir()
+	for _, sub := range []string{"ro1", "ro2"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o555); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := &Writer{Force: true, Concurrency: 2}
+	err := w.Write(dir, map[string][]byte{
+		"ok.txt":    []byte("ok"),
+		"ro1/a.txt": []byte("a"),
+		"ro2/b.txt": []byte("b"),
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 2 {
+		t.Fatalf("expected two joined errors, got %v", err)
+	}
+	if _, serr := os.Stat(filepath.Join(dir, "ok.txt")); serr != nil {
+		t.Fatalf("healthy file should still be written: %v", serr)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	w := &Writer{}
+	if err := w.Write(dir, map[string][]byte{"a.txt": []byte("same"), "b.txt": []byte("old"), "run.sh": []byte("#!/bin/sh\n")}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, tc := range []struct {
+		rel     string
+		content string
+		mode    os.FileMode
+		want    Change
+	}{
+		{"a.txt", "same", 0o644, Unchanged},
+		{"b.txt", "new", 0o644, Update},
+		{"b.txt", "olx", 0o644, Update}, // same size, different bytes
+		{"run.sh", "#!/bin/sh\n", 0o755, Update},
+		{"sub/c.txt", "c", 0o644, Create},
+	} {
+		if got := Classify(dir, tc.rel, []byte(tc.content), tc.mode); got != tc.want {
+			t.Errorf("Classify(%s, %q, %o) = %s, want %s", tc.rel, tc.content, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func benchmarkWrite(b *testing.B, concurrency int) {
+	files := sampleFiles(200)
+	root := b.TempDir()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := &Writer{Concurrency: concurrency}
+		if err := w.Write(filepath.Join(root, fmt.Sprintf("run%d", i)), files); err != nil {
+			b.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func BenchmarkWrite200Files_Sequential(b *testing.B) { benchmarkWrite(b, 1) }
+
+func BenchmarkWrite200Files_Parallel(b *testing.B) { benchmarkWrite(b, runtime.NumCPU()) }
@@ -4,30 +4,110 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"go/format"
 	"os"
+	"path"
 	"path/filepath"
-	"sort"
+	"regexp"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/docs"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/fileexclude"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/license"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/modelshard"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/tmploverride"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
 // Options controls how the Go emitter renders a project.
 type Options struct {
-	OutDir     string // required; target directory to write the project
-	ToolName   string // tool binary name; used under cmd/<tool>/
-	ModuleName string // go module name; defaults to ToolName when empty
-	Force      bool   // overwrite existing files
-	DryRun     bool   // don't write, only plan
-	Verbose    bool
+	OutDir             string   // required; target directory to write the project
+	ToolName           string   // tool binary name; used under cmd/<tool>/
+	ModuleName         string   // go module name; defaults to ToolName when empty
+	Platforms          []string // GOOS/GOARCH pairs for the Makefile's build-all target; defaults to DefaultPlatforms
+	GoVersion          string   // go directive in go.mod, e.g. 1.24 or 1.24.2; defaults to DefaultGoVersion
+	MCPLibVersion      string   // github.com/mark3labs/mcp-go version, e.g. v0.41.1; defaults to DefaultMCPLibVersion
+	GenerateInterfaces bool     // emit a Handler interface in internal/mcp and route the tools through it
+	GenerateMocks      bool     // emit a testify MockHandler in internal/mcp/mocks; implies GenerateInterfaces
+	GenerateLintConfig bool     // emit .golangci.yml and a Makefile lint target; the CLI turns this on by default
+	License            string   // SPDX identifier (MIT, Apache-2.0, ...); when set, writes LICENSE and names it in the README
+	Author             string   // project author, credited in the README and as the LICENSE copyright holder; defaults to "Generated by swagger2mcp"
+	AuthorEmail        string   // author's e-mail address, shown next to Author in the README
+	ProjectVersion     string   // semantic version shown in the README; defaults to the spec's version when that is one, else 0.1.0
+	Library            bool     // emit only the spec package (model, loader, model.json) as an importable library; no server, methods or tests
+	PinDependencies    bool     // write mcp-go's full module graph to go.mod and its checksums to go.sum, so the server builds without go mod tidy
+	EnableInvoke       bool     // add a callEndpoint tool that sends requests to the upstream API (base URL from the model's servers or API_BASE_URL)
+	EmitDocs           bool     // add docs/API.md, the Markdown reference of the endpoints and schemas that all emitters render alike (see package docs)
+	EmitDockerfile     bool     // emit a multi-stage Dockerfile, a .dockerignore and Makefile docker-build/docker-run targets; server layout only
+	GenerateCI         bool     // emit .github/workflows/ci.yml, a GitHub Actions workflow that builds, vets, tests and lints the project; server layout only
+	WithOTel           bool     // trace each tool call with OpenTelemetry, exported over OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is set; server layout only
+	ShardModelByTag    bool     // embed the model as an index and per-tag shards under model/ next to loader.go, read lazily by LoadShard and LoadTag, instead of one model.json
+	SplitByTag         bool     // emit a binary per tag, cmd/<tool>-<tag>/, serving only the endpoints whose first tag it is, instead of cmd/<tool>/; server layout only
+	Force              bool     // overwrite existing files
+	OverwriteModified  bool     // with Force, also replace files edited since the last run and files it did not generate
+	Prune              bool     // delete files the last run generated that are no longer produced
+	Concurrency        int      // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun             bool     // don't write, only plan
+	Verbose            bool
+
+	// TemplateOverrideDir holds files that replace the generated file at the
+	// same relative path (e.g. README.md, cmd/<tool>/main.go). They are
+	// rendered as text/template with an emitter.TemplateContext; files
+	// without an override keep the built-in.
+	// Under go/ it holds named overrides instead: go/<name>.tmpl, where
+	// <name> is one of TemplateNames, replaces that file after the same-path
+	// overrides, and any other file under go/ is an error listing the valid
+	// names. Other languages' directories are ignored.
+	TemplateOverrideDir string
+	// Provenance records how the model was built; template overrides see it
+	// as .Provenance.
+	Provenance *manifest.Provenance
+	// ExcludeFiles are globs (path.Match syntax, against the slash-separated
+	// relative path, or the file name when a pattern has no "/") of generated
+	// files to leave out, e.g. .golangci.yml or .vscode/*. Files the project
+	// needs to build cannot be excluded.
+	ExcludeFiles []string
+	// Skip names categories of generated files to leave out (see
+	// emitter.SkipCategories): tests drops the _test.go files, tests/ and
+	// testdata/, lint .golangci.yml, makefile the Makefile, readme
+	// README.md and editor .editorconfig and .vscode/. The remaining files
+	// are adapted, e.g. the Makefile has no test target without tests.
+	Skip []string
+}
+
+// DefaultPlatforms are the cross-compile targets of the generated build-all target.
+var DefaultPlatforms = []string{
+	"linux/amd64", "linux/arm64",
+	"darwin/amd64", "darwin/arm64",
+	"windows/amd64", "windows/arm64",
 }
 
+// Defaults for Options.GoVersion and Options.MCPLibVersion.
+const (
+	DefaultGoVersion     = "1.23"
+	DefaultMCPLibVersion = "v0.40.0"
+)
+
+// minGoMinor is the oldest Go 1.x release accepted for Options.GoVersion, the
+// first with embed.
+const minGoMinor = 16
+
+var (
+	goVersionRe     = regexp.MustCompile(`^1\.[0-9]+(\.[0-9]+|(rc|beta)[0-9]+)?$`)
+	moduleVersionRe = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+)
+
 // PlannedFile describes a file the emitter intends to write.
 type PlannedFile struct {
 	RelPath string
 	Size    int
 	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
 }
 
 // Result returns the planned files and final resolved names.
@@ -35,6 +115,8 @@ type Result struct {
 	ToolName   string
 	ModuleName string
 	Planned    []PlannedFile
+	Skipped    bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome    manifest.Outcome // files kept or pruned because of the last run's manifest
 }
 
 // Emit renders a Go MCP tool project using the provided ServiceModel (IM).
@@ -46,6 +128,17 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 	if strings.TrimSpace(opts.OutDir) == "" {
 		return nil, fmt.Errorf("goemitter: OutDir is required")
 	}
+	if v := strings.TrimSpace(opts.ProjectVersion); v != "" && !genspec.IsSemver(v) {
+		return nil, fmt.Errorf("goemitter: invalid ProjectVersion %q (expected a semantic version, e.g. 1.2.3)", opts.ProjectVersion)
+	}
+	licenseID := ""
+	if opts.License != "" {
+		id, err := license.Normalize(opts.License)
+		if err != nil {
+			return nil, fmt.Errorf("goemitter: %w", err)
+		}
+		licenseID = id
+	}
 	toolName := sanitizeToolName(opts.ToolName)
 	if toolName == "" {
 		// derive from service title as a fallback
@@ -59,33 +152,280 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 		moduleName = toolName
 	}
 
-	tmplData := newTemplateData(toolName, moduleName, sm)
+	platforms, err := resolvePlatforms(opts.Platforms)
+	if err != nil {
+		return nil, err
+	}
+	goVersion, mcpLibVersion, err := ResolveVersions(opts.GoVersion, opts.MCPLibVersion)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := emitter.ParseSkip(opts.Skip)
+	if err != nil {
+		return nil, fmt.Errorf("goemitter: %w", err)
+	}
+	if opts.PinDependencies && !opts.Library {
+		if mcpLibVersion != pinnedMCPLibVersion {
+			return nil, fmt.Errorf("goemitter: PinDependencies pins mcp-go %s; no pinned module set for %s", pinnedMCPLibVersion, mcpLibVersion)
+		}
+		if opts.GenerateMocks {
+			return nil, fmt.Errorf("goemitter: PinDependencies does not cover the testify dependency of GenerateMocks")
+		}
+		if opts.WithOTel {
+			return nil, fmt.Errorf("goemitter: PinDependencies does not cover the OpenTelemetry dependencies of WithOTel")
+		}
+	}
+	if opts.SplitByTag && opts.EmitDockerfile && !opts.Library {
+		return nil, fmt.Errorf("goemitter: SplitByTag does not support EmitDockerfile, whose image runs a single binary")
+	}
+
+	// Templates render from a copy with blank Title/Version/Description
+	// filled in; the spec hash below is still computed from sm.
+	model, notes := genspec.FillDefaults(sm, toolName)
+	if opts.Verbose {
+		for _, n := range notes {
+			fmt.Fprintf(os.Stderr, "[INFO] goemitter: %s\n", n)
+		}
+	}
+
+	tmplData := newTemplateData(toolName, moduleName, model)
+	tmplData.goVersion, tmplData.mcpLibVersion = goVersion, mcpLibVersion
+	tmplData.interfaces = opts.GenerateInterfaces || opts.GenerateMocks
+	tmplData.mocks = opts.GenerateMocks
+	tmplData.lint = opts.GenerateLintConfig && !skip.Lint
+	tmplData.skip = skip
+	tmplData.pinned = opts.PinDependencies
+	tmplData.invoke = opts.EnableInvoke
+	tmplData.docker = opts.EmitDockerfile
+	tmplData.ci = opts.GenerateCI && !opts.Library
+	tmplData.otel = opts.WithOTel
+	tmplData.shardModel = opts.ShardModelByTag
+	tmplData.license = licenseID
+	tmplData.version = genspec.ProjectVersion(opts.ProjectVersion, sm.Version)
+	if author := strings.TrimSpace(opts.Author); author != "" {
+		tmplData.author = author
+	}
+	tmplData.authorEmail = strings.TrimSpace(opts.AuthorEmail)
+	if opts.SplitByTag && !opts.Library {
+		if tmplData.tagBinaries, err = tagBinaries(toolName, model); err != nil {
+			return nil, err
+		}
+	}
+
+	var files map[string][]byte
+	if opts.Library {
+		files, err = libraryFiles(tmplData, model)
+	} else {
+		files, err = projectFiles(tmplData, model, platforms)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if licenseID != "" {
+		text, err := license.Render(licenseID, tmplData.year, tmplData.author)
+		if err != nil {
+			return nil, err
+		}
+		files[license.FileName] = []byte(text)
+	}
+	if opts.EmitDocs {
+		files[filepath.FromSlash(docs.File)] = []byte(docs.Render(model, model.Title))
+	}
+
+	tmplCtx := templateContext(tmplData, model, opts)
+	render := func(rel, text string) (string, error) {
+		out, err := tmplCtx.Render(rel, text)
+		return normalize(out), err
+	}
+	if err := tmploverride.Apply(opts.TemplateOverrideDir, files, render); err != nil {
+		return nil, fmt.Errorf("goemitter: %w", err)
+	}
+	// With SplitByTag the cmd/TOOL/ overrides apply to every binary.
+	for _, bin := range tmplData.binaries() {
+		err = tmploverride.ApplyNamed(opts.TemplateOverrideDir, "go", templateNames, func(name string) string {
+			return strings.Replace(name, "cmd/TOOL/", "cmd/"+bin.name+"/", 1)
+		}, files, render)
+		if err != nil {
+			return nil, fmt.Errorf("goemitter: %w", err)
+		}
+	}
+	if err := fileexclude.Apply(skip.Patterns(skipFiles), files, essentialFile); err != nil {
+		return nil, fmt.Errorf("goemitter: %w", err)
+	}
+	if err := fileexclude.Apply(opts.ExcludeFiles, files, essentialFile); err != nil {
+		return nil, fmt.Errorf("goemitter: %w", err)
+	}
+
+	// gofmt the Go sources so consumers' gofmt checks pass; a failure here
+	// means a template produced invalid Go.
+	for rel, content := range files {
+		if !strings.HasSuffix(rel, ".go") {
+			continue
+		}
+		formatted, err := format.Source(content)
+		if err != nil {
+			return nil, fmt.Errorf("goemitter: format %s: %w", filepath.ToSlash(rel), err)
+		}
+		files[rel] = formatted
+	}
+
+	// Plan in deterministic order
+	rels := make([]string, 0, len(files))
+	for p := range files {
+		rels = append(rels, filepath.ToSlash(p))
+	}
+	genspec.SortNames(rels)
+
+	planned := make([]PlannedFile, 0, len(rels))
+	for _, rel := range rels {
+		content := files[rel]
+		planned = append(planned, PlannedFile{
+			RelPath: rel,
+			Size:    len(content),
+			Mode:    0o644,
+			SHA256:  manifest.HashBytes(content),
+			Change:  filewriter.Classify(opts.OutDir, rel, content, 0o644),
+		})
+	}
+
+	// Write if not dry-run
+	res := &Result{ToolName: toolName, ModuleName: moduleName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "go", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		w := &filewriter.Writer{Prefix: "goemitter", Concurrency: opts.Concurrency, Force: opts.Force}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	}
+
+	return res, nil
+}
+
+// skipFiles maps each Skip category to the files it leaves out.
+var skipFiles = map[string][]string{
+	emitter.SkipTests:    {"*_test.go", "tests/*", "testdata/*"},
+	emitter.SkipLint:     {".golangci.yml"},
+	emitter.SkipMakefile: {"Makefile"},
+	emitter.SkipReadme:   {"README.md"},
+	emitter.SkipEditor:   {".editorconfig", ".vscode/*"},
+}
+
+// templateNames registers the built-in templates TemplateOverrideDir can
+// override by name, keyed by the slash-separated path of the file each
+// renders; TOOL stands for the tool's directory under cmd/, or each binary's
+// with SplitByTag. The model data, docs/API.md and go.sum are not templates.
+var templateNames = []string{
+	".dockerignore",
+	".editorconfig",
+	".github/workflows/ci.yml",
+	".golangci.yml",
+	".vscode/launch.json",
+	"Dockerfile",
+	"LICENSE",
+	"Makefile",
+	"README.md",
+	"cmd/TOOL/main.go",
+	"cmd/TOOL/main_test.go",
+	"go.mod",
+	"internal/mcp/describe.go",
+	"internal/mcp/methods/call_endpoint.go",
+	"internal/mcp/methods/explain_parameter.go",
+	"internal/mcp/methods/get_endpoint_details.go",
+	"internal/mcp/methods/get_schema_details.go",
+	"internal/mcp/methods/list_endpoints.go",
+	"internal/mcp/methods/list_schemas.go",
+	"internal/mcp/methods/search_endpoints.go",
+	"internal/mcp/methods/utils.go",
+	"internal/mcp/mocks/mock_handler.go",
+	"internal/mcp/otel.go",
+	"internal/mcp/server.go",
+	"internal/spec/loader.go",
+	"internal/spec/loader_test.go",
+	"internal/spec/model.go",
+	"spec/loader.go",
+	"spec/loader_test.go",
+	"spec/model.go",
+	"testdata/sample.yaml",
+	"tests/call_endpoint_test.go",
+	"tests/describe_test.go",
+	"tests/mcp_methods_test.go",
+	"tests/otel_test.go",
+}
+
+// TemplateNames returns the names of the templates TemplateOverrideDir can
+// override by name, each given there as go/<name>.tmpl.
+func TemplateNames() []string {
+	return append([]string(nil), templateNames...)
+}
+
+// essentialFile reports whether the generated module does not build without
+// rel: go.mod, go.sum, the embedded model.json or model shards and every
+// non-test Go source.
+func essentialFile(rel string) bool {
+	switch {
+	case rel == "go.mod", rel == "go.sum", path.Base(rel) == "model.json":
+		return true
+	case path.Base(path.Dir(rel)) == modelshard.Dir && path.Ext(rel) == ".json":
+		return true
+	case strings.HasSuffix(rel, ".go"):
+		return !strings.HasSuffix(rel, "_test.go")
+	}
+	return false
+}
 
-	// Build file map
+// projectFiles renders the full MCP server project.
+func projectFiles(tmplData templateData, sm *genspec.ServiceModel, platforms []string) (map[string][]byte, error) {
 	files := map[string][]byte{}
 	// editorconfig for consistent formatting
 	files[".editorconfig"] = []byte(renderEditorConfig())
+	// golangci-lint baseline
+	if tmplData.lint {
+		files[".golangci.yml"] = []byte(renderGolangciConfig())
+	}
 	// go.mod
 	gomod := renderGoMod(tmplData)
 	files["go.mod"] = []byte(gomod)
+	if tmplData.pinned {
+		files["go.sum"] = []byte(pinnedGoSum)
+	}
+	// VS Code debug configuration
+	files[filepath.Join(".vscode", "launch.json")] = []byte(renderVSCodeLaunch(tmplData))
 	// Makefile
-	files["Makefile"] = []byte(renderMakefileGo())
+	files["Makefile"] = []byte(renderMakefileGo(tmplData, platforms))
+	if tmplData.docker {
+		files["Dockerfile"] = []byte(renderDockerfileGo(tmplData))
+		files[".dockerignore"] = []byte(renderDockerignoreGo())
+	}
+	if tmplData.ci {
+		files[filepath.Join(".github", "workflows", "ci.yml")] = []byte(renderCIWorkflowGo(tmplData))
+	}
 	// README
 	files["README.md"] = []byte(renderReadme(tmplData))
-	// main.go
-	mainPath := filepath.Join("cmd", toolName, "main.go")
-	files[mainPath] = []byte(renderMainGo(tmplData))
+	// main.go, one per tag with SplitByTag
+	for _, bin := range tmplData.binaries() {
+		files[filepath.Join("cmd", bin.name, "main.go")] = []byte(renderMainGo(tmplData, bin))
+		files[filepath.Join("cmd", bin.name, "main_test.go")] = []byte(renderMainTestGo(tmplData, bin))
+	}
 	// internal/spec model + loader + data
 	files[filepath.Join("internal", "spec", "model.go")] = []byte(renderSpecModelGo())
-	// model.json
-	modelJSON, err := json.MarshalIndent(sm, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("marshal model.json: %w", err)
+	// model.json, or its shards, and the loader
+	if err := addSpecData(files, filepath.Join("internal", "spec"), tmplData, sm); err != nil {
+		return nil, err
 	}
-	files[filepath.Join("internal", "spec", "model.json")] = append(modelJSON, '\n')
-	files[filepath.Join("internal", "spec", "loader.go")] = []byte(renderSpecLoaderGo())
 	// mcp server bootstrap wiring
 	files[filepath.Join("internal", "mcp", "server.go")] = []byte(renderMCPBootstrapGo(tmplData))
+	files[filepath.Join("internal", "mcp", "describe.go")] = []byte(tmplData.render(mcpDescribeGo))
+	if tmplData.otel {
+		files[filepath.Join("internal", "mcp", "otel.go")] = []byte(tmplData.render(mcpOTelGo))
+		files[filepath.Join("tests", "otel_test.go")] = []byte(tmplData.render(otelTestsGo))
+	}
 	// methods (inject module import path)
 	files[filepath.Join("internal", "mcp", "methods", "list_endpoints.go")] = []byte(renderListEndpointsGo(tmplData))
 	files[filepath.Join("internal", "mcp", "methods", "search_endpoints.go")] = []byte(renderSearchEndpointsGo(tmplData))
@@ -93,64 +433,140 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 	files[filepath.Join("internal", "mcp", "methods", "get_endpoint_details.go")] = []byte(renderGetEndpointDetailsGo(tmplData))
 	files[filepath.Join("internal", "mcp", "methods", "list_schemas.go")] = []byte(renderListSchemasGo(tmplData))
 	files[filepath.Join("internal", "mcp", "methods", "get_schema_details.go")] = []byte(renderGetSchemaDetailsGo(tmplData))
+	files[filepath.Join("internal", "mcp", "methods", "explain_parameter.go")] = []byte(renderExplainParameterGo(tmplData))
+	if tmplData.invoke {
+		files[filepath.Join("internal", "mcp", "methods", "call_endpoint.go")] = []byte(renderCallEndpointGo(tmplData))
+		files[filepath.Join("tests", "call_endpoint_test.go")] = []byte(renderCallEndpointTestsGo(tmplData))
+	}
+	// testify mock of the Handler interface
+	if tmplData.mocks {
+		files[filepath.Join("internal", "mcp", "mocks", "mock_handler.go")] = []byte(renderMockHandlerGo(tmplData))
+	}
 	// tests
 	files[filepath.Join("tests", "mcp_methods_test.go")] = []byte(renderGeneratedTests(tmplData))
+	files[filepath.Join("tests", "describe_test.go")] = []byte(tmplData.render(describeTestsGo))
 	// testdata sample spec (informational)
 	files[filepath.Join("testdata", "sample.yaml")] = []byte(sampleSpecYAML)
+	return files, nil
+}
 
-	// Plan in deterministic order
-	rels := make([]string, 0, len(files))
-	for p := range files {
-		rels = append(rels, filepath.ToSlash(p))
-	}
-	sort.Strings(rels)
-
-	planned := make([]PlannedFile, 0, len(rels))
-	for _, rel := range rels {
-		planned = append(planned, PlannedFile{RelPath: rel, Size: len(files[rel]), Mode: 0o644})
+// libraryFiles renders only the spec package, promoted from internal/spec to
+// spec/ so other modules can import the typed model and the embedded
+// model.json and build their own server on them.
+func libraryFiles(tmplData templateData, sm *genspec.ServiceModel) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	files[".editorconfig"] = []byte(renderEditorConfig())
+	if tmplData.lint {
+		files[".golangci.yml"] = []byte(renderGolangciConfig())
 	}
-
-	// Write if not dry-run
-	if !opts.DryRun {
-		if err := writeFiles(opts.OutDir, files, opts.Force); err != nil {
-			return nil, err
-		}
+	files["go.mod"] = []byte(renderLibraryGoMod(tmplData))
+	files["README.md"] = []byte(renderLibraryReadme(tmplData))
+	files[filepath.Join("spec", "model.go")] = []byte(renderSpecModelGo())
+	if err := addSpecData(files, "spec", tmplData, sm); err != nil {
+		return nil, err
 	}
-
-	return &Result{ToolName: toolName, ModuleName: moduleName, Planned: planned}, nil
+	return files, nil
 }
 
-func writeFiles(outDir string, files map[string][]byte, force bool) error {
-	abs, err := filepath.Abs(outDir)
-	if err != nil {
-		return fmt.Errorf("resolve out dir: %w", err)
+// tagBinaries returns the binaries of SplitByTag, one per shard of sm in the
+// order of modelshard.Split: <tool>-<shard name>, and <tool>-untagged for the
+// endpoints without tags unless a tag took that name. It is an error when no
+// endpoint has a tag.
+func tagBinaries(toolName string, sm *genspec.ServiceModel) ([]tagBinary, error) {
+	idx, _ := modelshard.Split(sm)
+	taken := map[string]bool{}
+	for _, s := range idx.Shards {
+		taken[s.Name] = true
 	}
-	// Pre-flight: if directory exists and not empty and not force, error.
-	if st, err := os.Stat(abs); err == nil && st.IsDir() && !force {
-		// check emptiness
-		entries, rerr := os.ReadDir(abs)
-		if rerr == nil && len(entries) > 0 {
-			return fmt.Errorf("goemitter: output directory %q is not empty (use --force to overwrite)", abs)
+	bins := make([]tagBinary, 0, len(idx.Shards))
+	tagged := false
+	for i := range idx.Shards {
+		s := &idx.Shards[i]
+		name := s.Name
+		if s.Tag == "" {
+			if n := strings.TrimPrefix(name, "_"); !taken[n] {
+				name = n
+			}
+		} else {
+			tagged = true
 		}
+		bins = append(bins, tagBinary{name: toolName + "-" + name, shard: s})
 	}
-	for rel, content := range files {
-		p := filepath.Join(abs, rel)
-		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
-			return fmt.Errorf("mkdir: %w", err)
+	if !tagged {
+		return nil, fmt.Errorf("goemitter: SplitByTag needs endpoints with tags")
+	}
+	return bins, nil
+}
+
+// addSpecData writes the model data of the spec package in dir and its
+// loader.go: model.json, or with ShardModelByTag the index and per-tag shards
+// under model/, and loader_test.go, which checks the binary loads it without
+// the files.
+func addSpecData(files map[string][]byte, dir string, tmplData templateData, sm *genspec.ServiceModel) error {
+	if tmplData.shardModel {
+		shards, err := modelshard.Files(sm)
+		if err != nil {
+			return err
 		}
-		// atomic write via temp file + rename
-		tmp := p + ".tmp-" + time.Now().Format("20060102150405")
-		if err := os.WriteFile(tmp, content, 0o644); err != nil {
-			return fmt.Errorf("write temp %s: %w", rel, err)
+		for rel, b := range shards {
+			files[filepath.Join(dir, filepath.FromSlash(rel))] = b
 		}
-		if err := os.Rename(tmp, p); err != nil {
-			_ = os.Remove(tmp)
-			return fmt.Errorf("rename %s: %w", rel, err)
+	} else {
+		modelJSON, err := json.MarshalIndent(sm, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal model.json: %w", err)
 		}
+		files[filepath.Join(dir, "model.json")] = append(modelJSON, '\n')
 	}
+	files[filepath.Join(dir, "loader.go")] = []byte(renderSpecLoaderGo(tmplData))
+	files[filepath.Join(dir, "loader_test.go")] = []byte(renderSpecLoaderTestGo(sm))
 	return nil
 }
 
+// resolvePlatforms validates GOOS/GOARCH pairs, falling back to DefaultPlatforms.
+func resolvePlatforms(in []string) ([]string, error) {
+	if len(in) == 0 {
+		return DefaultPlatforms, nil
+	}
+	out := make([]string, 0, len(in))
+	for _, p := range in {
+		p = strings.TrimSpace(p)
+		goos, goarch, ok := strings.Cut(p, "/")
+		if !ok || goos == "" || goarch == "" || strings.ContainsAny(p, " \t$") || strings.Contains(goarch, "/") {
+			return nil, fmt.Errorf("goemitter: invalid platform %q (expected GOOS/GOARCH, e.g. linux/amd64)", p)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// ResolveVersions validates the Go and mcp-go versions for go.mod, filling in
+// DefaultGoVersion and DefaultMCPLibVersion for empty values. A missing "v"
+// prefix on the mcp-go version is added.
+func ResolveVersions(goVersion, mcpLibVersion string) (string, string, error) {
+	goVersion = strings.TrimPrefix(strings.TrimSpace(goVersion), "go")
+	if goVersion == "" {
+		goVersion = DefaultGoVersion
+	}
+	if !goVersionRe.MatchString(goVersion) {
+		return "", "", fmt.Errorf("goemitter: invalid Go version %q (expected e.g. 1.24 or 1.24.2)", goVersion)
+	}
+	if minor, _ := strconv.Atoi(strings.FieldsFunc(goVersion, func(r rune) bool { return r < '0' || r > '9' })[1]); minor < minGoMinor {
+		return "", "", fmt.Errorf("goemitter: Go version %s is too old; the generated project embeds its model with embed.FS, which needs Go 1.%d or newer", goVersion, minGoMinor)
+	}
+	mcpLibVersion = strings.TrimSpace(mcpLibVersion)
+	if mcpLibVersion == "" {
+		mcpLibVersion = DefaultMCPLibVersion
+	}
+	if !strings.HasPrefix(mcpLibVersion, "v") {
+		mcpLibVersion = "v" + mcpLibVersion
+	}
+	if !moduleVersionRe.MatchString(mcpLibVersion) {
+		return "", "", fmt.Errorf("goemitter: invalid mcp-go version %q (expected a semantic version, e.g. v0.41.1)", mcpLibVersion)
+	}
+	return goVersion, mcpLibVersion, nil
+}
+
 func sanitizeToolName(name string) string {
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -187,3 +603,28 @@ func deriveToolName(title string) string {
 	}
 	return strings.Join(parts, "-")
 }
+
+// templateContext returns the data template overrides are rendered with.
+func templateContext(d templateData, model *genspec.ServiceModel, opts Options) *emitter.TemplateContext {
+	c := emitter.NewTemplateContext("go", d.ToolName, d.ModuleName, model)
+	c.Version, c.Author, c.AuthorEmail = d.version, d.author, d.authorEmail
+	c.License, c.Year = d.license, d.year
+	c.Library, c.EnableInvoke, c.PinDependencies = opts.Library, d.invoke, d.pinned
+	c.Dockerfile = d.docker && !opts.Library
+	c.CI = d.ci
+	c.OTel = d.otel && !opts.Library
+	c.ShardModel = d.shardModel
+	c.Skip = d.skip
+	c.Provenance = opts.Provenance
+	c.Go = &emitter.GoContext{
+		GoVersion:     d.goVersion,
+		MCPLibVersion: d.mcpLibVersion,
+		Interfaces:    d.interfaces,
+		Mocks:         d.mocks,
+		LintConfig:    d.lint,
+	}
+	for _, bin := range d.tagBinaries {
+		c.Go.Binaries = append(c.Go.Binaries, bin.name)
+	}
+	return c
+}
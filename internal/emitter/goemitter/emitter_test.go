@@ -3,12 +3,24 @@ package goemitter
 import (
     "context"
     "encoding/json"
+    "fmt"
+    "go/ast"
+    "go/format"
+    "go/parser"
+    "go/token"
     "os"
+    "os/exec"
     "path/filepath"
+    "reflect"
+    "strconv"
     "strings"
     "testing"
+    "time"
 
+    "github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+    "github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
     genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+    "gopkg.in/yaml.v3"
 )
 
 func minimalModel() *genspec.ServiceModel {
@@ -46,6 +58,7 @@ func TestEmit_DryRun_Plan(t *testing.T) {
     want := []string{
         "go.mod",
         "Makefile",
+        filepath.ToSlash(filepath.Join(".vscode", "launch.json")),
         "README.md",
         filepath.ToSlash(filepath.Join("cmd", "mytool", "main.go")),
         filepath.ToSlash(filepath.Join("internal", "mcp", "server.go")),
@@ -108,6 +121,19 @@ func TestEmit_WriteAndContents(t *testing.T) {
     if err := json.Unmarshal(j, &v); err != nil {
         t.Fatalf("model.json invalid: %v", err)
     }
+
+    // .vscode/launch.json is valid JSON with a server debug configuration
+    lj, err := os.ReadFile(filepath.Join(dir, ".vscode", "launch.json"))
+    if err != nil { t.Fatalf("read launch.json: %v", err) }
+    var launch struct {
+        Configurations []map[string]any `json:"configurations"`
+    }
+    if err := json.Unmarshal(lj, &launch); err != nil {
+        t.Fatalf("launch.json invalid: %v", err)
+    }
+    if len(launch.Configurations) != 2 || !strings.Contains(string(lj), `"program": "${workspaceFolder}/cmd/mytool"`) {
+        t.Fatalf("unexpected launch.json: %s", string(lj))
+    }
 }
 
 func TestEmit_NoForce_NonEmptyDir(t *testing.T) {
@@ -123,3 +149,1622 @@ func TestEmit_NoForce_NonEmptyDir(t *testing.T) {
         t.Fatalf("expected error on non-empty dir without force")
     }
 }
+
+func TestEmit_SkipsWhenManifestMatches(t *testing.T) {
+    t.Parallel()
+    ctx := context.Background()
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool"}
+
+    res, err := Emit(ctx, minimalModel(), opts)
+    if err != nil { t.Fatalf("first emit: %v", err) }
+    if res.Skipped { t.Fatalf("first emit should write files") }
+    m, err := manifest.ReadManifest(dir)
+    if err != nil { t.Fatalf("read manifest: %v", err) }
+    if m.Lang != "go" || m.ToolName != "mytool" || m.SpecHash != manifest.SpecHash(minimalModel()) || len(m.Files) != len(res.Planned) {
+        t.Fatalf("unexpected manifest: %+v", m)
+    }
+
+    // Same model and options, no --force: nothing is rewritten.
+    res, err = Emit(ctx, minimalModel(), opts)
+    if err != nil { t.Fatalf("second emit: %v", err) }
+    if !res.Skipped { t.Fatalf("expected unchanged output to skip writes") }
+
+    // A changed model, changed options or an edited file is a normal run
+    // again, so the non-empty check applies.
+    changed := minimalModel()
+    changed.Version = "2.0.0"
+    if _, err := Emit(ctx, changed, opts); err == nil || !strings.Contains(err.Error(), "not empty") {
+        t.Fatalf("expected not-empty error for changed spec, got %v", err)
+    }
+    licensed := opts
+    licensed.License = "MIT"
+    if _, err := Emit(ctx, minimalModel(), licensed); err == nil || !strings.Contains(err.Error(), "not empty") {
+        t.Fatalf("expected not-empty error for changed options, got %v", err)
+    }
+    readme := filepath.Join(dir, "README.md")
+    if err := os.WriteFile(readme, []byte("edited"), 0o644); err != nil { t.Fatal(err) }
+    if _, err := Emit(ctx, minimalModel(), opts); err == nil || !strings.Contains(err.Error(), "not empty") {
+        t.Fatalf("expected not-empty error for an edited file, got %v", err)
+    }
+    if b, _ := os.ReadFile(readme); string(b) != "edited" { t.Fatalf("failed emit must not touch files") }
+
+    // --force regenerates even when the hash matches, but keeps the edited README.
+    opts.Force = true
+    res, err = Emit(ctx, minimalModel(), opts)
+    if err != nil || res.Skipped { t.Fatalf("forced emit: res=%+v err=%v", res, err) }
+    if b, _ := os.ReadFile(readme); string(b) != "edited" { t.Fatalf("forced emit must keep the edited README.md") }
+    if len(res.Outcome.Modified) != 1 || res.Outcome.Modified[0] != "README.md" { t.Fatalf("expected README.md reported as modified, got %+v", res.Outcome) }
+
+    opts.OverwriteModified = true
+    if _, err := Emit(ctx, minimalModel(), opts); err != nil { t.Fatalf("overwrite emit: %v", err) }
+    if b, _ := os.ReadFile(readme); string(b) == "edited" { t.Fatalf("--overwrite-modified should rewrite README.md") }
+}
+
+func TestEmit_PlanClassifiesChanges(t *testing.T) {
+    t.Parallel()
+    ctx := context.Background()
+    dir := t.TempDir()
+    res, err := Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool", DryRun: true})
+    if err != nil { t.Fatalf("dry-run: %v", err) }
+    for _, p := range res.Planned {
+        if p.Change != filewriter.Create || len(p.SHA256) != 64 {
+            t.Fatalf("empty dir: expected create with digest, got %+v", p)
+        }
+    }
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool"}); err != nil { t.Fatalf("emit: %v", err) }
+
+    // Regenerating over the output: one edited file, everything else as written.
+    if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("edited"), 0o644); err != nil { t.Fatal(err) }
+    res, err = Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool", DryRun: true})
+    if err != nil { t.Fatalf("dry-run over output: %v", err) }
+    for _, p := range res.Planned {
+        want := filewriter.Unchanged
+        if p.RelPath == "README.md" {
+            want = filewriter.Update
+        }
+        if p.Change != want {
+            t.Errorf("%s: change=%s, want %s", p.RelPath, p.Change, want)
+        }
+        if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p.RelPath))); err == nil && want == filewriter.Unchanged && manifest.HashBytes(data) != p.SHA256 {
+            t.Errorf("%s: digest does not match the file on disk", p.RelPath)
+        }
+    }
+}
+
+func TestEmit_MakefileCrossCompile(t *testing.T) {
+    t.Parallel()
+    ctx := context.Background()
+    dir := t.TempDir()
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    b, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+    if err != nil { t.Fatalf("read Makefile: %v", err) }
+    mk := string(b)
+    for _, want := range []string{
+        "PLATFORMS ?= linux/amd64 linux/arm64 darwin/amd64 darwin/arm64 windows/amd64 windows/arm64\n",
+        "ifeq ($(OS),Windows_NT)\nEXE := .exe\n",
+        "go build -o bin/$(TOOL)$(EXE) $(PKG)\n",
+        "build-all:\n",
+        `out="dist/$(TOOL)_$${os}_$${arch}$$ext"`,
+        `if [ "$$os" = "windows" ]; then ext=".exe"; fi`,
+        "> checksums.txt\n",
+    } {
+        if !strings.Contains(mk, want) { t.Fatalf("Makefile missing %q:\n%s", want, mk) }
+    }
+    readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+    if err != nil { t.Fatalf("read README: %v", err) }
+    for _, want := range []string{`"command": "/path/to/mytool/bin/mytool"`, `"command": "C:\\path\\to\\mytool\\bin\\mytool.exe"`} {
+        if !strings.Contains(string(readme), want) { t.Fatalf("README missing %q", want) }
+    }
+
+    // Custom platform list
+    dir2 := t.TempDir()
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir2, ToolName: "mytool", Platforms: []string{"linux/amd64", "windows/arm64"}}); err != nil {
+        t.Fatalf("emit custom platforms: %v", err)
+    }
+    b, _ = os.ReadFile(filepath.Join(dir2, "Makefile"))
+    if !strings.Contains(string(b), "PLATFORMS ?= linux/amd64 windows/arm64\n") { t.Fatalf("custom platforms not applied:\n%s", b) }
+
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: t.TempDir(), Platforms: []string{"linux"}}); err == nil || !strings.Contains(err.Error(), "invalid platform") {
+        t.Fatalf("expected invalid platform error, got %v", err)
+    }
+}
+
+func TestEmit_GoModVersions(t *testing.T) {
+    t.Parallel()
+    ctx := context.Background()
+    dir := t.TempDir()
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GoVersion: "1.24.2", MCPLibVersion: "0.41.1"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+    if want := "module example.com/mytool\n\ngo 1.24.2\n\nrequire github.com/mark3labs/mcp-go v0.41.1\n"; string(gomod) != want {
+        t.Fatalf("go.mod:\n%s\nwant:\n%s", gomod, want)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "requires Go 1.24.2 or newer") || !strings.Contains(string(readme), "mcp-go v0.41.1") {
+        t.Fatalf("README missing versions:\n%s", readme)
+    }
+
+    // An older toolchain, given with or without the "go" prefix.
+    for _, goVersion := range []string{"1.21", "go1.21"} {
+        older := t.TempDir()
+        if _, err := Emit(ctx, minimalModel(), Options{OutDir: older, ToolName: "mytool", ModuleName: "example.com/mytool", GoVersion: goVersion}); err != nil {
+            t.Fatalf("emit %s: %v", goVersion, err)
+        }
+        gomod, _ := os.ReadFile(filepath.Join(older, "go.mod"))
+        if !strings.HasPrefix(string(gomod), "module example.com/mytool\n\ngo 1.21\n") {
+            t.Fatalf("%s: go.mod:\n%s", goVersion, gomod)
+        }
+    }
+
+    // Any release from 1.16, the first with embed, is accepted as given.
+    for _, goVersion := range []string{"1.22", "1.16"} {
+        dir := t.TempDir()
+        if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GoVersion: goVersion}); err != nil {
+            t.Fatalf("emit %s: %v", goVersion, err)
+        }
+        gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+        if !strings.Contains(string(gomod), "\ngo "+goVersion+"\n") {
+            t.Fatalf("%s: go.mod:\n%s", goVersion, gomod)
+        }
+    }
+
+    // Defaults when unset.
+    res, err := Emit(ctx, minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", DryRun: true})
+    if err != nil || res == nil { t.Fatalf("emit defaults: %v", err) }
+    if gv, mv, err := ResolveVersions("", ""); err != nil || gv != DefaultGoVersion || mv != DefaultMCPLibVersion {
+        t.Fatalf("defaults = %q %q %v", gv, mv, err)
+    }
+
+    for _, tc := range []struct{ goVersion, mcpLib, want string }{
+        {"1.x", "", "invalid Go version"},
+        {"2", "", "invalid Go version"},
+        {"1.24; rm -rf /", "", "invalid Go version"},
+        {"1.15", "", "too old"},
+        {"1.9.7", "", "too old"},
+        {"", "latest", "invalid mcp-go version"},
+        {"", "v0.41", "invalid mcp-go version"},
+    } {
+        out := t.TempDir()
+        _, err := Emit(ctx, minimalModel(), Options{OutDir: out, GoVersion: tc.goVersion, MCPLibVersion: tc.mcpLib})
+        if err == nil || !strings.Contains(err.Error(), tc.want) {
+            t.Fatalf("%q/%q: expected %q error, got %v", tc.goVersion, tc.mcpLib, tc.want, err)
+        }
+        if entries, _ := os.ReadDir(out); len(entries) != 0 { t.Fatalf("%q/%q: files written before validation failed", tc.goVersion, tc.mcpLib) }
+    }
+}
+
+func TestEmit_GenerateLintConfig(t *testing.T) {
+    t.Parallel()
+    ctx := context.Background()
+    dir := t.TempDir()
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir, ToolName: "mytool", GenerateLintConfig: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    b, err := os.ReadFile(filepath.Join(dir, ".golangci.yml"))
+    if err != nil { t.Fatalf("read .golangci.yml: %v", err) }
+    var cfg struct {
+        Version string `yaml:"version"`
+        Linters struct {
+            Enable  []string `yaml:"enable"`
+            Disable []string `yaml:"disable"`
+        } `yaml:"linters"`
+        Formatters struct {
+            Enable []string `yaml:"enable"`
+        } `yaml:"formatters"`
+    }
+    if err := yaml.Unmarshal(b, &cfg); err != nil { t.Fatalf("parse .golangci.yml: %v\n%s", err, b) }
+    if cfg.Version != "2" || !strings.Contains(string(b), "generated by swagger2mcp") {
+        t.Fatalf("unexpected header:\n%s", b)
+    }
+    if got := strings.Join(cfg.Linters.Enable, ","); got != "errcheck,govet,ineffassign,staticcheck" { t.Fatalf("enabled linters = %s", got) }
+    if got := strings.Join(cfg.Linters.Disable, ","); got != "funlen,gocognit" { t.Fatalf("disabled linters = %s", got) }
+    if got := strings.Join(cfg.Formatters.Enable, ","); got != "gofmt,goimports" { t.Fatalf("formatters = %s", got) }
+
+    mk, _ := os.ReadFile(filepath.Join(dir, "Makefile"))
+    if !strings.Contains(string(mk), "\nlint:\n\tgolangci-lint run ./...\n") || !strings.Contains(string(mk), ".PHONY: help build build-all test fmt lint tidy clean") {
+        t.Fatalf("Makefile missing lint target:\n%s", mk)
+    }
+
+    // Off: neither the config nor the target.
+    dir2 := t.TempDir()
+    if _, err := Emit(ctx, minimalModel(), Options{OutDir: dir2, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit without lint: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(dir2, ".golangci.yml")); !os.IsNotExist(err) { t.Fatalf(".golangci.yml should not exist: %v", err) }
+    mk, _ = os.ReadFile(filepath.Join(dir2, "Makefile"))
+    if strings.Contains(string(mk), "lint") { t.Fatalf("unexpected lint target:\n%s", mk) }
+}
+
+func TestEmit_EmbeddedLoader(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", Force: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    specDir := filepath.Join(dir, "internal", "spec")
+    loader, err := os.ReadFile(filepath.Join(specDir, "loader.go"))
+    if err != nil { t.Fatalf("read loader.go: %v", err) }
+    for _, want := range []string{"//go:embed model.json", "var modelFS embed.FS", "func LoadEmbedded() (*ServiceModel, error)", "func LoadFromFile(path string) (*ServiceModel, error)", `const ModelPathEnv = "MCP_MODEL_PATH"`} {
+        if !strings.Contains(string(loader), want) {
+            t.Fatalf("loader.go missing %q:\n%s", want, loader)
+        }
+    }
+    mainGo, err := os.ReadFile(filepath.Join(dir, "cmd", "mytool", "main.go"))
+    if err != nil { t.Fatalf("read main.go: %v", err) }
+    if !strings.Contains(string(mainGo), "spec.Load()") {
+        t.Fatalf("main.go should load through spec.Load so MCP_MODEL_PATH applies:\n%s", mainGo)
+    }
+    if !strings.Contains(string(loader), `const BaseURLEnv = "API_BASE_URL"`) {
+        t.Fatalf("loader.go missing BaseURLEnv:\n%s", loader)
+    }
+    loaderTest, err := os.ReadFile(filepath.Join(specDir, "loader_test.go"))
+    if err != nil { t.Fatalf("read loader_test.go: %v", err) }
+    if !strings.Contains(string(loaderTest), `const wantTitle, wantEndpoints = "Sample API", 1`) || !strings.Contains(string(loaderTest), "os.Chdir(t.TempDir())") {
+        t.Fatalf("loader_test.go should check the embedded model loads away from model.json:\n%s", loaderTest)
+    }
+    for _, interfaces := range []bool{false, true} {
+        out := t.TempDir()
+        if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: out, ToolName: "mytool", ModuleName: "example.com/mytool", GenerateInterfaces: interfaces}); err != nil {
+            t.Fatalf("emit (interfaces=%v): %v", interfaces, err)
+        }
+        server, _ := os.ReadFile(filepath.Join(out, "internal", "mcp", "server.go"))
+        if strings.Count(string(server), "spec.ApplyBaseURLOverride(sm)") != 1 {
+            t.Fatalf("server.go (interfaces=%v) should apply API_BASE_URL once:\n%s", interfaces, server)
+        }
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "API_BASE_URL") {
+        t.Fatalf("README should document API_BASE_URL:\n%s", readme)
+    }
+
+    // Compiling needs a Go toolchain; gate it like the other build checks.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    // The spec package only uses the standard library, so it builds as its own
+    // module without fetching the generated project's dependencies.
+    mod := t.TempDir()
+    for _, name := range []string{"model.go", "loader.go", "loader_test.go", "model.json"} {
+        data, err := os.ReadFile(filepath.Join(specDir, name))
+        if err != nil { t.Fatalf("read %s: %v", name, err) }
+        if err := os.WriteFile(filepath.Join(mod, name), data, 0o644); err != nil { t.Fatalf("write %s: %v", name, err) }
+    }
+    override := filepath.Join(t.TempDir(), "override.json")
+    if err := os.WriteFile(override, []byte(`{"Title":"Override"}`), 0o644); err != nil { t.Fatalf("write override: %v", err) }
+    files := map[string]string{
+        "go.mod": "module example.com/mytool/internal/spec\n\ngo 1.23\n",
+        "loaders_test.go": `package spec
+
+import "testing"
+
+func TestLoaders(t *testing.T) {
+    sm, err := LoadEmbedded()
+    if err != nil || sm.Title != "Sample API" {
+        t.Fatalf("LoadEmbedded: %v %+v", err, sm)
+    }
+    sm, err = LoadFromFile(` + "`" + override + "`" + `)
+    if err != nil || sm.Title != "Override" {
+        t.Fatalf("LoadFromFile: %v %+v", err, sm)
+    }
+    if sm, err = Load(); err != nil || sm.Title != "Sample API" {
+        t.Fatalf("Load without MCP_MODEL_PATH: %v %+v", err, sm)
+    }
+    t.Setenv(ModelPathEnv, ` + "`" + override + "`" + `)
+    if sm, err = Load(); err != nil || sm.Title != "Override" {
+        t.Fatalf("Load with MCP_MODEL_PATH: %v %+v", err, sm)
+    }
+}
+`,
+        "base_url_test.go": `package spec
+
+import "testing"
+
+func TestBaseURL(t *testing.T) {
+    sm := &ServiceModel{Servers: []Server{{URL: "https://prod.example.com"}, {URL: "https://staging.example.com"}}}
+    if got := BaseURL(sm); got != "https://prod.example.com" {
+        t.Fatalf("BaseURL without API_BASE_URL = %q", got)
+    }
+    if got := BaseURL(&ServiceModel{}); got != "" {
+        t.Fatalf("BaseURL without servers = %q", got)
+    }
+    t.Setenv(BaseURLEnv, " https://staging.example.com ")
+    if got := BaseURL(sm); got != "https://staging.example.com" {
+        t.Fatalf("BaseURL with API_BASE_URL = %q", got)
+    }
+    ApplyBaseURLOverride(sm)
+    if len(sm.Servers) != 2 || sm.Servers[0].URL != "https://staging.example.com" || sm.Servers[1].URL != "https://prod.example.com" {
+        t.Fatalf("ApplyBaseURLOverride = %+v", sm.Servers)
+    }
+}
+`,
+    }
+    for name, content := range files {
+        if err := os.WriteFile(filepath.Join(mod, name), []byte(content), 0o644); err != nil { t.Fatalf("write %s: %v", name, err) }
+    }
+    cmd := exec.Command("go", "test", "./...")
+    cmd.Dir = mod
+    if out, err := cmd.CombinedOutput(); err != nil {
+        t.Fatalf("go test generated spec package: %v\n%s", err, out)
+    }
+}
+
+func TestEmit_GenerateInterfaces(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GenerateInterfaces: true, Force: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    serverPath := filepath.Join(dir, "internal", "mcp", "server.go")
+    src, err := os.ReadFile(serverPath)
+    if err != nil { t.Fatalf("read server.go: %v", err) }
+    file, err := parser.ParseFile(token.NewFileSet(), serverPath, src, 0)
+    if err != nil { t.Fatalf("parse server.go: %v", err) }
+
+    // One Handler method per registered tool, and every tool goes through it.
+    var handlerMethods int
+    ast.Inspect(file, func(n ast.Node) bool {
+        if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == "Handler" {
+            if it, ok := ts.Type.(*ast.InterfaceType); ok {
+                handlerMethods = len(it.Methods.List)
+            }
+        }
+        return true
+    })
+    tools := strings.Count(string(src), "srv.AddTool(")
+    if tools == 0 || handlerMethods != tools {
+        t.Fatalf("Handler has %d methods, want one per tool (%d)", handlerMethods, tools)
+    }
+    if calls := strings.Count(string(src), " := h."); calls != tools {
+        t.Fatalf("expected %d tool handlers calling the Handler, found %d:\n%s", tools, calls, src)
+    }
+    for _, want := range []string{"func NewMCPServerWithHandler(sm *spec.ServiceModel, h Handler) *goserver.MCPServer", "func NewHandler(sm *spec.ServiceModel) Handler"} {
+        if !strings.Contains(string(src), want) { t.Fatalf("server.go missing %q", want) }
+    }
+    tests, err := os.ReadFile(filepath.Join(dir, "tests", "mcp_methods_test.go"))
+    if err != nil { t.Fatalf("read tests: %v", err) }
+    if !strings.Contains(string(tests), "var _ server.Handler = stubHandler{}") || strings.Contains(string(tests), "methods.FormatEndpointsOverview(") {
+        t.Fatalf("generated tests should use the Handler interface:\n%s", tests)
+    }
+
+    // Without the option the bootstrap has no Handler.
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool", Force: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if b, _ := os.ReadFile(filepath.Join(plain, "internal", "mcp", "server.go")); strings.Contains(string(b), "Handler interface") {
+        t.Fatalf("Handler interface emitted without GenerateInterfaces")
+    }
+
+    // Building and testing the project needs the toolchain and mcp-go.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    for _, args := range [][]string{{"mod", "tidy"}, {"test", "./..."}} {
+        cmd := exec.Command("go", args...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_GenerateMocks(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    // GenerateMocks implies the Handler interface it mocks.
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GenerateMocks: true, Force: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    interfaceMethods := map[string]bool{}
+    serverPath := filepath.Join(dir, "internal", "mcp", "server.go")
+    serverFile, err := parser.ParseFile(token.NewFileSet(), serverPath, nil, 0)
+    if err != nil { t.Fatalf("parse server.go: %v", err) }
+    ast.Inspect(serverFile, func(n ast.Node) bool {
+        if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == "Handler" {
+            if it, ok := ts.Type.(*ast.InterfaceType); ok {
+                for _, m := range it.Methods.List {
+                    interfaceMethods[m.Names[0].Name] = true
+                }
+            }
+        }
+        return true
+    })
+    if len(interfaceMethods) == 0 { t.Fatalf("server.go has no Handler interface") }
+
+    mockPath := filepath.Join(dir, "internal", "mcp", "mocks", "mock_handler.go")
+    mockFile, err := parser.ParseFile(token.NewFileSet(), mockPath, nil, 0)
+    if err != nil { t.Fatalf("parse mock_handler.go: %v", err) }
+    var testifyImport bool
+    for _, imp := range mockFile.Imports {
+        testifyImport = testifyImport || imp.Path.Value == `"github.com/stretchr/testify/mock"`
+    }
+    if !testifyImport { t.Fatalf("mock_handler.go does not import testify/mock") }
+
+    // One On-compatible method per Handler method: a *MockHandler method that
+    // records the call through m.Called.
+    mocked := map[string]bool{}
+    for _, decl := range mockFile.Decls {
+        fn, ok := decl.(*ast.FuncDecl)
+        if !ok || fn.Recv == nil { continue }
+        star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+        if !ok || star.X.(*ast.Ident).Name != "MockHandler" { continue }
+        ast.Inspect(fn.Body, func(n ast.Node) bool {
+            if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "Called" {
+                mocked[fn.Name.Name] = true
+            }
+            return true
+        })
+    }
+    if len(mocked) != len(interfaceMethods) {
+        t.Fatalf("MockHandler mocks %v, Handler declares %v", mocked, interfaceMethods)
+    }
+    for name := range interfaceMethods {
+        if !mocked[name] { t.Fatalf("MockHandler is missing %s", name) }
+    }
+
+    gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+    if err != nil { t.Fatalf("read go.mod: %v", err) }
+    if !strings.Contains(string(gomod), "github.com/stretchr/testify v") {
+        t.Fatalf("go.mod does not require testify:\n%s", gomod)
+    }
+    tests, err := os.ReadFile(filepath.Join(dir, "tests", "mcp_methods_test.go"))
+    if err != nil { t.Fatalf("read tests: %v", err) }
+    if !strings.Contains(string(tests), "func Test_ToolsWithMockHandler(t *testing.T)") || !strings.Contains(string(tests), "h := &mocks.MockHandler{}") {
+        t.Fatalf("generated tests should exercise the mock:\n%s", tests)
+    }
+
+    // Interfaces alone add neither the mock nor the dependency.
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool", GenerateInterfaces: true, Force: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(plain, "internal", "mcp", "mocks")); !os.IsNotExist(err) {
+        t.Fatalf("mocks emitted without GenerateMocks (stat err=%v)", err)
+    }
+    if b, _ := os.ReadFile(filepath.Join(plain, "go.mod")); strings.Contains(string(b), "testify") {
+        t.Fatalf("go.mod requires testify without GenerateMocks:\n%s", b)
+    }
+
+    // Building and testing the project needs the toolchain, mcp-go and testify.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    for _, args := range [][]string{{"mod", "tidy"}, {"test", "./..."}} {
+        cmd := exec.Command("go", args...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_ExplainParameter(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Endpoints = append(sm.Endpoints,
+        genspec.EndpointModel{ID: "get /pets", Method: genspec.GET, Path: "/pets", Parameters: []genspec.ParameterModel{
+            {Name: "status", In: "query", Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "string", Enum: []any{"available", "sold"}}}},
+        }},
+        genspec.EndpointModel{ID: "get /pets/{petId}", Method: genspec.GET, Path: "/pets/{petId}", Parameters: []genspec.ParameterModel{
+            {Name: "petId", In: "path", Required: true, Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "integer"}}},
+        }},
+    )
+    for _, mocks := range []bool{false, true} {
+        dir := t.TempDir()
+        if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GenerateMocks: mocks, Force: true}); err != nil {
+            t.Fatalf("emit (mocks=%v): %v", mocks, err)
+        }
+        methodsGo, err := os.ReadFile(filepath.Join(dir, "internal", "mcp", "methods", "explain_parameter.go"))
+        if err != nil { t.Fatalf("read explain_parameter.go: %v", err) }
+        for _, want := range []string{"func ExplainParameter(sm *spec.ServiceModel, endpointID, name string) (*ParameterExplanation, error)", "func FormatParameterExplanation("} {
+            if !strings.Contains(string(methodsGo), want) { t.Fatalf("explain_parameter.go missing %q", want) }
+        }
+        server, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "server.go"))
+        if !strings.Contains(string(server), `mcp.NewTool("explainParameter"`) {
+            t.Fatalf("server.go (mocks=%v) does not register explainParameter:\n%s", mocks, server)
+        }
+        tests, _ := os.ReadFile(filepath.Join(dir, "tests", "mcp_methods_test.go"))
+        if !strings.Contains(string(tests), "func Test_ExplainParameter(t *testing.T)") {
+            t.Fatalf("generated tests (mocks=%v) do not cover explainParameter:\n%s", mocks, tests)
+        }
+
+        if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+            continue
+        }
+        if _, err := exec.LookPath("go"); err != nil {
+            t.Skip("go toolchain not available")
+        }
+        for _, args := range [][]string{{"mod", "tidy"}, {"test", "-run", "ExplainParameter|ToolsWithMockHandler", "-v", "./tests"}} {
+            cmd := exec.Command("go", args...)
+            cmd.Dir = dir
+            out, err := cmd.CombinedOutput()
+            if err != nil {
+                t.Fatalf("go %s (mocks=%v): %v\n%s", strings.Join(args, " "), mocks, err, out)
+            }
+            if args[0] == "test" && strings.Contains(string(out), "SKIP") {
+                t.Fatalf("explainParameter tests skipped on the fixture:\n%s", out)
+            }
+        }
+    }
+}
+
+func TestEmit_LibraryLayout(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", Library: true, GenerateMocks: true, DryRun: true}
+    res, err := Emit(context.Background(), minimalModel(), opts)
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    var got []string
+    for _, pf := range res.Planned { got = append(got, pf.RelPath) }
+    want := []string{".editorconfig", "go.mod", "README.md", "spec/loader.go", "spec/loader_test.go", "spec/model.go", "spec/model.json"}
+    if strings.Join(got, ",") != strings.Join(want, ",") {
+        t.Fatalf("library plan = %v, want %v", got, want)
+    }
+
+    opts.DryRun = false
+    if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+    if string(gomod) != "module example.com/mytool\n\ngo "+DefaultGoVersion+"\n" {
+        t.Fatalf("library go.mod should have no requirements:\n%s", gomod)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), `import "example.com/mytool/spec"`) || !strings.Contains(string(readme), "spec.Load()") {
+        t.Fatalf("README should show library usage:\n%s", readme)
+    }
+    // Without dependencies the library builds offline.
+    cmd := exec.Command("go", "vet", "./...")
+    cmd.Dir = dir
+    cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+    if out, err := cmd.CombinedOutput(); err != nil {
+        t.Fatalf("go vet library: %v\n%s", err, out)
+    }
+}
+
+func TestEmit_GoFilesAreGofmtClean(t *testing.T) {
+    t.Parallel()
+    for _, interfaces := range []bool{false, true} {
+        dir := t.TempDir()
+        if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GenerateInterfaces: interfaces, GenerateMocks: interfaces, Force: true}); err != nil {
+            t.Fatalf("emit (interfaces=%v): %v", interfaces, err)
+        }
+        var checked int
+        err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+            if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+                return err
+            }
+            src, err := os.ReadFile(path)
+            if err != nil { return err }
+            if _, err := parser.ParseFile(token.NewFileSet(), path, src, parser.ParseComments); err != nil {
+                t.Errorf("parse %s: %v", path, err)
+                return nil
+            }
+            formatted, err := format.Source(src)
+            if err != nil || string(formatted) != string(src) {
+                t.Errorf("%s is not gofmt-clean (interfaces=%v, err=%v)", path, interfaces, err)
+            }
+            checked++
+            return nil
+        })
+        if err != nil { t.Fatalf("walk: %v", err) }
+        if checked == 0 { t.Fatalf("no Go files emitted") }
+    }
+}
+
+func TestEmit_License(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", License: "apache-2.0", DryRun: true})
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    planned := false
+    for _, pf := range res.Planned {
+        planned = planned || pf.RelPath == "LICENSE"
+    }
+    if !planned {
+        t.Fatalf("dry-run plan should list LICENSE: %+v", res.Planned)
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", License: "MIT"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    data, err := os.ReadFile(filepath.Join(dir, "LICENSE"))
+    if err != nil {
+        t.Fatalf("read LICENSE: %v", err)
+    }
+    year := strconv.Itoa(time.Now().Year())
+    if !strings.HasPrefix(string(data), "MIT License\n\nCopyright (c) "+year+" Generated by swagger2mcp\n") {
+        t.Fatalf("unexpected LICENSE:\n%s", data)
+    }
+
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), License: "WTFPL"}); err == nil || !strings.Contains(err.Error(), `unknown license "WTFPL"`) {
+        t.Fatalf("expected an unknown license error, got %v", err)
+    }
+}
+
+func TestEmit_AuthorMetadata(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", License: "mit", Author: "Jane Doe", AuthorEmail: "jane@example.com"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+    if err != nil {
+        t.Fatalf("read README.md: %v", err)
+    }
+    for _, want := range []string{"License: MIT, see LICENSE.\n", "Author: Jane Doe <jane@example.com>\n"} {
+        if !strings.Contains(string(readme), want) {
+            t.Fatalf("README.md missing %q:\n%s", want, readme)
+        }
+    }
+    licenseText, err := os.ReadFile(filepath.Join(dir, "LICENSE"))
+    if err != nil {
+        t.Fatalf("read LICENSE: %v", err)
+    }
+    if !strings.Contains(string(licenseText), strconv.Itoa(time.Now().Year())+" Jane Doe\n") {
+        t.Fatalf("LICENSE should name the author:\n%s", licenseText)
+    }
+
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ = os.ReadFile(filepath.Join(plain, "README.md"))
+    if strings.Contains(string(readme), "License:") || strings.Contains(string(readme), "Author:") {
+        t.Fatalf("README.md should not carry metadata by default:\n%s", readme)
+    }
+}
+
+func TestEmit_ProjectVersion(t *testing.T) {
+    t.Parallel()
+    undated := minimalModel()
+    undated.Version = "2024-01"
+    for _, tc := range []struct {
+        sm      *genspec.ServiceModel
+        version string
+        want    string
+    }{
+        {minimalModel(), "3.1.0-beta.2", "3.1.0-beta.2"},
+        {minimalModel(), "", "1.0.0"},
+        {undated, "", genspec.DefaultProjectVersion},
+    } {
+        dir := t.TempDir()
+        if _, err := Emit(context.Background(), tc.sm, Options{OutDir: dir, ToolName: "mytool", ProjectVersion: tc.version}); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+        if !strings.Contains(string(readme), "\nVersion: "+tc.want+"\n") {
+            t.Fatalf("README.md should show version %s:\n%s", tc.want, readme)
+        }
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ProjectVersion: "1.0"}); err == nil || !strings.Contains(err.Error(), "invalid ProjectVersion") {
+        t.Fatalf("expected an invalid ProjectVersion error, got %v", err)
+    }
+}
+
+func TestEmit_TagDescriptions(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Tags = []string{"read", "write"}
+    sm.TagDescriptions = map[string]string{"read": "Read-only\n  operations", "write": ""}
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "Tags:\n\n- read — Read-only operations\n- write\n\n") {
+        t.Fatalf("README.md should list the tags with their descriptions:\n%s", readme)
+    }
+    model, _ := os.ReadFile(filepath.Join(dir, "internal", "spec", "model.go"))
+    listGo, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "methods", "list_endpoints.go"))
+    if !strings.Contains(string(model), "TagDescriptions map[string]string") || !strings.Contains(string(listGo), "sm.TagDescriptions[tagList[i].tag]") {
+        t.Fatalf("the overview should show tag descriptions:\n%s", listGo)
+    }
+
+    untagged := minimalModel()
+    untagged.Endpoints[0].Tags = nil
+    dir = t.TempDir()
+    if _, err := Emit(context.Background(), untagged, Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ = os.ReadFile(filepath.Join(dir, "README.md"))
+    if strings.Contains(string(readme), "Tags:") {
+        t.Fatalf("README.md of an untagged model should have no Tags section:\n%s", readme)
+    }
+}
+
+func TestEmit_TemplateOverrideDir(t *testing.T) {
+    t.Parallel()
+    tmpl := t.TempDir()
+    if err := os.WriteFile(filepath.Join(tmpl, "README.md"), []byte("# {{.ToolName}} for {{.ServiceTitle}}\n\nmodule {{.PackageName}}\n"), 0o644); err != nil {
+        t.Fatalf("write override: %v", err)
+    }
+    if err := os.MkdirAll(filepath.Join(tmpl, "cmd", "mytool"), 0o755); err != nil {
+        t.Fatalf("mkdir: %v", err)
+    }
+    mainGo := "package main\n\nimport \"{{.PackageName}}/internal/mcp\"\n\n// Custom bootstrap.\nfunc main() {   _ = mcp.Run }\n"
+    if err := os.WriteFile(filepath.Join(tmpl, "cmd", "mytool", "main.go"), []byte(mainGo), 0o644); err != nil {
+        t.Fatalf("write override: %v", err)
+    }
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", TemplateOverrideDir: tmpl}
+    if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if string(readme) != "# mytool for Sample API\n\nmodule example.com/mytool\n" {
+        t.Fatalf("README.md should come from the override:\n%s", readme)
+    }
+    gotMain, _ := os.ReadFile(filepath.Join(dir, "cmd", "mytool", "main.go"))
+    if !strings.Contains(string(gotMain), "// Custom bootstrap.\nfunc main() { _ = mcp.Run }") || !strings.Contains(string(gotMain), `"example.com/mytool/internal/mcp"`) {
+        t.Fatalf("main.go should come from the override, gofmt'd:\n%s", gotMain)
+    }
+    // Files without an override keep the built-in template.
+    server, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "server.go"))
+    if !strings.Contains(string(server), "package mcp") {
+        t.Fatalf("server.go should use the built-in template:\n%s", server)
+    }
+
+    opts = Options{OutDir: t.TempDir(), ToolName: "mytool", TemplateOverrideDir: filepath.Join(tmpl, "missing")}
+    if _, err := Emit(context.Background(), minimalModel(), opts); err == nil || !strings.Contains(err.Error(), "template override") {
+        t.Fatalf("expected an error for a missing override directory, got %v", err)
+    }
+}
+
+func TestEmit_TemplateOverrideDirNamed(t *testing.T) {
+    t.Parallel()
+    // Every file a template renders is registered, and every registered name
+    // is rendered by some combination of options.
+    registered := map[string]bool{}
+    for _, name := range TemplateNames() {
+        registered[name] = false
+    }
+    for _, opts := range []Options{
+        {},
+        {GenerateMocks: true, GenerateLintConfig: true, EnableInvoke: true, EmitDockerfile: true, GenerateCI: true, WithOTel: true, License: "MIT"},
+        {Library: true},
+    } {
+        opts.OutDir, opts.ToolName, opts.DryRun = t.TempDir(), "mytool", true
+        res, err := Emit(context.Background(), minimalModel(), opts)
+        if err != nil { t.Fatalf("emit: %v", err) }
+        for _, f := range res.Planned {
+            name := strings.Replace(filepath.ToSlash(f.RelPath), "cmd/mytool/", "cmd/TOOL/", 1)
+            if strings.HasSuffix(name, "/model.json") {
+                continue
+            }
+            if _, ok := registered[name]; !ok {
+                t.Errorf("%s is not in templateNames", name)
+            }
+            registered[name] = true
+        }
+    }
+    for name, seen := range registered {
+        if !seen {
+            t.Errorf("templateNames lists %s, which no tested options generate", name)
+        }
+    }
+
+    tmpl := t.TempDir()
+    for rel, text := range map[string]string{
+        "go/README.md.tmpl":        "# {{.ToolName}} by {{.Author}}\n",
+        "go/cmd/TOOL/main.go.tmpl": "// Corporate header.\n\npackage main\n\nfunc main() {   }\n",
+        "go/Dockerfile.tmpl":       "FROM scratch\n",
+        "python/README.md.tmpl":    "# not for go\n",
+        "README.md":                "# same-path, replaced by go/README.md.tmpl\n",
+        ".editorconfig":            "root = true\n",
+    } {
+        p := filepath.Join(tmpl, filepath.FromSlash(rel))
+        if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+        if err := os.WriteFile(p, []byte(text), 0o644); err != nil { t.Fatalf("write override: %v", err) }
+    }
+    plain, dir := t.TempDir(), t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", Author: "ACME", TemplateOverrideDir: tmpl}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if readme, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(readme) != "# mytool by ACME\n" {
+        t.Fatalf("README.md should come from go/README.md.tmpl:\n%s", readme)
+    }
+    if ec, _ := os.ReadFile(filepath.Join(dir, ".editorconfig")); string(ec) != "root = true\n" {
+        t.Fatalf(".editorconfig should come from the same-path override:\n%s", ec)
+    }
+    if mainGo, _ := os.ReadFile(filepath.Join(dir, "cmd", "mytool", "main.go")); string(mainGo) != "// Corporate header.\n\npackage main\n\nfunc main() {}\n" {
+        t.Fatalf("main.go should come from the override, gofmt'd:\n%s", mainGo)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); !os.IsNotExist(err) {
+        t.Fatalf("an override must not add a file the options do not generate: %v", err)
+    }
+    for _, rel := range []string{filepath.Join("internal", "mcp", "server.go"), "Makefile"} {
+        want, _ := os.ReadFile(filepath.Join(plain, rel))
+        if got, _ := os.ReadFile(filepath.Join(dir, rel)); string(got) != string(want) {
+            t.Fatalf("%s without an override should stay built-in", rel)
+        }
+    }
+
+    if err := os.WriteFile(filepath.Join(tmpl, "go", "Makefil.tmpl"), nil, 0o644); err != nil { t.Fatalf("write: %v", err) }
+    _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", TemplateOverrideDir: tmpl, DryRun: true})
+    if err == nil || !strings.Contains(err.Error(), "unknown template override go/Makefil.tmpl") || !strings.Contains(err.Error(), "Makefile.tmpl") {
+        t.Fatalf("expected an error listing the valid templates, got %v", err)
+    }
+}
+
+func TestEmit_TemplateContextProbe(t *testing.T) {
+    t.Parallel()
+    probe, err := os.ReadFile(filepath.Join("..", "testdata", "template_context.tmpl"))
+    if err != nil {
+        t.Fatalf("read probe: %v", err)
+    }
+    tmpl := t.TempDir()
+    if err := os.WriteFile(filepath.Join(tmpl, "README.md"), probe, 0o644); err != nil {
+        t.Fatalf("write override: %v", err)
+    }
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", GenerateMocks: true, SplitByTag: true, TemplateOverrideDir: tmpl,
+        Provenance: &manifest.Provenance{Generator: "1.2.3", Input: "spec.yaml"}}
+    if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    for _, want := range []string{
+        "schema 1 lang go\n",
+        "tool mytool package example.com/mytool title Sample API version 1.0.0\n",
+        "input spec.yaml generator 1.2.3\n",
+        "go " + DefaultGoVersion + " mcp-go " + DefaultMCPLibVersion + " interfaces true mocks true lint false binaries [mytool-read]\n",
+    } {
+        if !strings.Contains(string(readme), want) {
+            t.Errorf("rendered probe lacks %q:\n%s", want, readme)
+        }
+    }
+}
+
+func TestEmit_EndpointDetailsGroupParameters(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    methodsGo, err := os.ReadFile(filepath.Join(dir, "internal", "mcp", "methods", "get_endpoint_details.go"))
+    if err != nil { t.Fatalf("read get_endpoint_details.go: %v", err) }
+    sections := []string{`{"path", "路径参数"}`, `{"query", "查询参数"}`, `{"header", "请求头参数"}`, `{"cookie", "Cookie参数"}`}
+    last := -1
+    for _, want := range sections {
+        i := strings.Index(string(methodsGo), want)
+        if i < 0 || i < last { t.Fatalf("parameter section %s missing or out of order:\n%s", want, methodsGo) }
+        last = i
+    }
+    tests, _ := os.ReadFile(filepath.Join(dir, "tests", "mcp_methods_test.go"))
+    if !strings.Contains(string(tests), "func Test_EndpointDetailsGroupsParameters(t *testing.T)") {
+        t.Fatalf("generated tests do not cover parameter grouping:\n%s", tests)
+    }
+
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    for _, args := range [][]string{{"mod", "tidy"}, {"test", "-run", "EndpointDetailsGroupsParameters", "./tests"}} {
+        cmd := exec.Command("go", args...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_Describe(t *testing.T) {
+    t.Parallel()
+    for _, opts := range []Options{{}, {GenerateInterfaces: true, EnableInvoke: true}, {ShardModelByTag: true}} {
+        dir := t.TempDir()
+        opts.OutDir, opts.ToolName, opts.ModuleName = dir, "mytool", "example.com/mytool"
+        if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+            t.Fatalf("emit %+v: %v", opts, err)
+        }
+        mainGo, _ := os.ReadFile(filepath.Join(dir, "cmd", "mytool", "main.go"))
+        if !strings.Contains(string(mainGo), `flag.Bool("describe"`) || !strings.Contains(string(mainGo), "mcp.WriteDescription(os.Stdout, srv, sm)") {
+            t.Fatalf("main.go should handle --describe:\n%s", mainGo)
+        }
+        describeGo, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "describe.go"))
+        if !strings.Contains(string(describeGo), "srv.ListTools()") || !strings.Contains(string(describeGo), "spec.ModelHash()") {
+            t.Fatalf("describe.go should list the registered tools and hash the model:\n%s", describeGo)
+        }
+        if _, err := os.Stat(filepath.Join(dir, "tests", "describe_test.go")); err != nil {
+            t.Fatalf("expected a generated describe test: %v", err)
+        }
+
+        if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+            continue
+        }
+        if _, err := exec.LookPath("go"); err != nil {
+            t.Skip("go toolchain not available")
+        }
+        for _, args := range [][]string{{"mod", "tidy"}, {"test", "-run", "Describe|LoadEmbedded", "./tests", "./internal/spec"}} {
+            cmd := exec.Command("go", args...)
+            cmd.Dir = dir
+            if out, err := cmd.CombinedOutput(); err != nil {
+                t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+            }
+        }
+        cmd := exec.Command("go", "run", "./cmd/mytool", "--describe")
+        cmd.Dir = dir
+        out, err := cmd.Output()
+        if err != nil {
+            t.Fatalf("go run --describe: %v", err)
+        }
+        var d struct {
+            Title     string                  `json:"title"`
+            Endpoints int                     `json:"endpoints"`
+            Tools     []struct{ Name string } `json:"tools"`
+        }
+        if err := json.Unmarshal(out, &d); err != nil || d.Title != "Sample API" || d.Endpoints != 1 {
+            t.Fatalf("--describe output (%v):\n%s", err, out)
+        }
+        wantTools := 6
+        if opts.EnableInvoke {
+            wantTools = 7
+        }
+        if len(d.Tools) != wantTools {
+            t.Fatalf("--describe lists %d tools, want %d: %+v", len(d.Tools), wantTools, d.Tools)
+        }
+    }
+}
+
+func TestEmit_HTTPTransport(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    cmdDir := filepath.Join(dir, "cmd", "mytool")
+    for _, name := range []string{"main.go", "main_test.go"} {
+        if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(cmdDir, name), nil, 0); err != nil {
+            t.Fatalf("parse %s: %v", name, err)
+        }
+    }
+    mainGo, _ := os.ReadFile(filepath.Join(cmdDir, "main.go"))
+    for _, want := range []string{`flag.String("transport", "stdio"`, `os.Getenv("MCP_HTTP_ADDR")`, `os.Getenv("PORT")`, "goserver.NewStreamableHTTPServer(srv).Start(addr)", "goserver.ServeStdio(srv)"} {
+        if !strings.Contains(string(mainGo), want) {
+            t.Fatalf("main.go missing %q:\n%s", want, mainGo)
+        }
+    }
+
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    for _, args := range [][]string{{"mod", "tidy"}, {"test", "./cmd/..."}} {
+        cmd := exec.Command("go", args...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_PinDependencies(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "go.sum")); !os.IsNotExist(err) {
+        t.Fatalf("go.sum should only be written with PinDependencies: %v", err)
+    }
+
+    dir = t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", PinDependencies: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+    for _, want := range append([]string{"require github.com/mark3labs/mcp-go " + DefaultMCPLibVersion + "\n"}, pinnedModules...) {
+        if !strings.Contains(string(gomod), want) {
+            t.Fatalf("go.mod missing %q:\n%s", want, gomod)
+        }
+    }
+    gosum, _ := os.ReadFile(filepath.Join(dir, "go.sum"))
+    for _, mod := range append([]string{"github.com/mark3labs/mcp-go " + DefaultMCPLibVersion}, pinnedModules...) {
+        if !strings.Contains(string(gosum), mod+" h1:") || !strings.Contains(string(gosum), mod+"/go.mod h1:") {
+            t.Fatalf("go.sum has no checksums for %s:\n%s", mod, gosum)
+        }
+    }
+
+    for _, opts := range []Options{{MCPLibVersion: "v0.41.1"}, {GenerateMocks: true}} {
+        opts.OutDir, opts.PinDependencies = t.TempDir(), true
+        if _, err := Emit(context.Background(), minimalModel(), opts); err == nil || !strings.Contains(err.Error(), "PinDependencies") {
+            t.Fatalf("%+v: expected a PinDependencies error, got %v", opts, err)
+        }
+    }
+
+    // The pinned go.mod and go.sum build as written, without go mod tidy.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    cmd := exec.Command("go", "vet", "./...")
+    cmd.Dir = dir
+    cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly")
+    if out, err := cmd.CombinedOutput(); err != nil {
+        t.Fatalf("go vet: %v\n%s", err, out)
+    }
+}
+
+func TestEmit_EnableInvoke(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Servers = []genspec.Server{{URL: "https://api.example.com/v1"}}
+    sm.Endpoints = append(sm.Endpoints, genspec.EndpointModel{ID: "get /pets/{petId}", Method: genspec.GET, Path: "/pets/{petId}", Parameters: []genspec.ParameterModel{
+        {Name: "petId", In: "path", Required: true, Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "integer"}}},
+        {Name: "fields", In: "query", Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "string"}}},
+    }})
+    variants := []struct {
+        name string
+        opts Options
+    }{{"plain", Options{}}, {"interfaces", Options{GenerateInterfaces: true}}, {"mocks", Options{GenerateMocks: true}}}
+    for _, variant := range variants {
+        dir := t.TempDir()
+        opts := variant.opts
+        opts.OutDir, opts.ToolName, opts.ModuleName, opts.EnableInvoke, opts.Force = dir, "mytool", "example.com/mytool", true, true
+        if _, err := Emit(context.Background(), sm, opts); err != nil {
+            t.Fatalf("emit (%s): %v", variant.name, err)
+        }
+        for rel, wants := range map[string][]string{
+            filepath.Join("internal", "mcp", "methods", "call_endpoint.go"): {"func CallEndpoint(ctx context.Context, sm *spec.ServiceModel, args CallArgs, opts CallOptions) (*CallResult, error)", "func BuildRequest(", `const CallTimeoutEnv = "API_TIMEOUT"`, `const CallRetriesEnv = "API_RETRIES"`, "context.WithTimeout(ctx, timeout)"},
+            filepath.Join("internal", "mcp", "server.go"):                   {`mcp.NewTool("callEndpoint"`},
+            filepath.Join("tests", "call_endpoint_test.go"):                 {"func Test_CallEndpoint(t *testing.T)", "httptest.NewServer"},
+            "README.md": {"callEndpoint", "API_TIMEOUT", "API_RETRIES"},
+        } {
+            data, err := os.ReadFile(filepath.Join(dir, rel))
+            if err != nil { t.Fatalf("read %s: %v", rel, err) }
+            for _, want := range wants {
+                if !strings.Contains(string(data), want) {
+                    t.Fatalf("%s (%s) missing %q:\n%s", rel, variant.name, want, data)
+                }
+            }
+        }
+
+        if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+            continue
+        }
+        if _, err := exec.LookPath("go"); err != nil {
+            t.Skip("go toolchain not available")
+        }
+        for _, args := range [][]string{{"mod", "tidy"}, {"vet", "./..."}, {"test", "-run", "CallEndpoint|ToolsWithMockHandler", "-v", "./tests"}} {
+            cmd := exec.Command("go", args...)
+            cmd.Dir = dir
+            out, err := cmd.CombinedOutput()
+            if err != nil {
+                t.Fatalf("go %s (%s): %v\n%s", strings.Join(args, " "), variant.name, err, out)
+            }
+            if args[0] == "test" && strings.Contains(string(out), "SKIP") {
+                t.Fatalf("callEndpoint tests skipped on the fixture:\n%s", out)
+            }
+        }
+    }
+
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "internal", "mcp", "methods", "call_endpoint.go")); !os.IsNotExist(err) {
+        t.Fatalf("call_endpoint.go written without EnableInvoke (err=%v)", err)
+    }
+    server, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "server.go"))
+    if strings.Contains(string(server), "callEndpoint") {
+        t.Fatalf("server.go registers callEndpoint without EnableInvoke")
+    }
+}
+
+func TestEmit_InvokeCredentials(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Servers = []genspec.Server{{URL: "https://api.example.com/v1"}}
+    sm.SecuritySchemes = map[string]genspec.SecurityScheme{
+        "key":    {Name: "key", Type: "apiKey", In: "query", ParamName: "api_key"},
+        "bearer": {Name: "bearer", Type: "http", Scheme: "bearer"},
+    }
+    sm.Endpoints[0].Security = [][]string{{"key"}, {"bearer"}}
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", EnableInvoke: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        filepath.Join("internal", "mcp", "methods", "call_endpoint.go"): {`"key":    {Kind: "apiKey", In: "query", Name: "api_key", EnvVar: "MYTOOL_API_KEY"},`, `"bearer": {Kind: "bearer", In: "header", Name: "Authorization", EnvVar: "MYTOOL_BEARER_TOKEN"},`, "applyCredentials(ep, query, header, &cookies)"},
+        filepath.Join("internal", "spec", "model.go"):                   {"Security    [][]string"},
+        filepath.Join("tests", "call_endpoint_test.go"):                 {`t.Run("credentials"`, "missing credentials"},
+        "README.md": {"- `MYTOOL_API_KEY`: scheme `key`, sent in the `api_key` query parameter", "- `MYTOOL_BEARER_TOKEN`: scheme `bearer`, sent in the `Authorization: Bearer <token>` header"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+    model, _ := os.ReadFile(filepath.Join(dir, "internal", "spec", "model.json"))
+    if !strings.Contains(string(model), `"Security": [`) {
+        t.Fatalf("model.json lacks the endpoint's security requirements:\n%s", model)
+    }
+}
+
+func TestEmit_ExcludeFiles(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", DryRun: true, GenerateLintConfig: true, ExcludeFiles: []string{".golangci.yml", ".vscode/*"}})
+    if err != nil { t.Fatalf("emit: %v", err) }
+    for _, p := range res.Planned {
+        if p.RelPath == ".golangci.yml" || strings.HasPrefix(p.RelPath, ".vscode/") {
+            t.Fatalf("excluded file %s still planned", p.RelPath)
+        }
+    }
+    if len(res.Planned) == 0 { t.Fatalf("nothing planned") }
+
+    for _, pattern := range []string{"go.mod", "*.go", "model.json"} {
+        _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", ExcludeFiles: []string{pattern}})
+        if err == nil || !strings.Contains(err.Error(), "which the project needs to build") {
+            t.Fatalf("exclude %q: expected an essential-file error, got %v", pattern, err)
+        }
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", ExcludeFiles: []string{"*_test.go"}}); err != nil {
+        t.Fatalf("excluding tests: %v", err)
+    }
+}
+
+func TestEmit_Skip(t *testing.T) {
+    t.Parallel()
+    planned := func(skip ...string) map[string]bool {
+        t.Helper()
+        res, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", GenerateLintConfig: true, EmitDockerfile: true, DryRun: true, Skip: skip})
+        if err != nil { t.Fatalf("emit %v: %v", skip, err) }
+        out := map[string]bool{}
+        for _, p := range res.Planned { out[p.RelPath] = true }
+        return out
+    }
+    all := planned()
+    for category, want := range map[string][]string{
+        "tests":    {"cmd/mytool/main_test.go", "internal/spec/loader_test.go", "tests/describe_test.go", "tests/mcp_methods_test.go", "testdata/sample.yaml"},
+        "lint":     {".golangci.yml"},
+        "makefile": {"Makefile"},
+        "readme":   {"README.md"},
+        "editor":   {".editorconfig", ".vscode/launch.json"},
+    } {
+        got := planned(category)
+        for _, rel := range want {
+            if !all[rel] || got[rel] { t.Fatalf("skip %s: %s planned %v without the category, %v with it", category, rel, all[rel], got[rel]) }
+        }
+        if len(got) != len(all)-len(want) { t.Fatalf("skip %s: planned %d files, want %d: %v", category, len(got), len(all)-len(want), got) }
+    }
+
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", GenerateLintConfig: true, EmitDockerfile: true, Skip: []string{"tests", "makefile"}}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    for _, unwanted := range []string{"make ", "Debug tests", "set IMAGE"} {
+        if strings.Contains(string(readme), unwanted) { t.Fatalf("README refers to a skipped file (%q):\n%s", unwanted, readme) }
+    }
+    for _, want := range []string{"go build -o bin/mytool ./cmd/mytool", "docker build -t mytool .", "`golangci-lint run ./...` lints"} {
+        if !strings.Contains(string(readme), want) { t.Fatalf("README lacks %q:\n%s", want, readme) }
+    }
+    launch, _ := os.ReadFile(filepath.Join(dir, ".vscode", "launch.json"))
+    if strings.Contains(string(launch), "Debug tests") || !strings.Contains(string(launch), "Debug MCP server") { t.Fatalf("launch.json:\n%s", launch) }
+
+    dir = t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", GenerateLintConfig: true, Skip: []string{"tests", "editor"}}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    mk, _ := os.ReadFile(filepath.Join(dir, "Makefile"))
+    if strings.Contains(string(mk), "test") || !strings.Contains(string(mk), "Targets: build build-all fmt lint tidy clean") { t.Fatalf("Makefile without tests:\n%s", mk) }
+    readme, _ = os.ReadFile(filepath.Join(dir, "README.md"))
+    if strings.Contains(string(readme), ".vscode") { t.Fatalf("README refers to .vscode:\n%s", readme) }
+
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", Skip: []string{"docs"}}); err == nil || !strings.Contains(err.Error(), "unknown skip category") {
+        t.Fatalf("expected an unknown-category error, got %v", err)
+    }
+}
+
+func TestEmit_Dockerfile(t *testing.T) {
+    t.Parallel()
+    planned := func(opts Options) map[string]bool {
+        t.Helper()
+        opts.OutDir, opts.ToolName, opts.DryRun = t.TempDir(), "mytool", true
+        res, err := Emit(context.Background(), minimalModel(), opts)
+        if err != nil { t.Fatalf("emit: %v", err) }
+        out := map[string]bool{}
+        for _, p := range res.Planned { out[p.RelPath] = true }
+        return out
+    }
+    if got := planned(Options{}); got["Dockerfile"] || got[".dockerignore"] {
+        t.Fatalf("Docker files planned without EmitDockerfile: %v", got)
+    }
+    if got := planned(Options{EmitDockerfile: true}); !got["Dockerfile"] || !got[".dockerignore"] {
+        t.Fatalf("Docker files not planned with EmitDockerfile: %v", got)
+    }
+    if got := planned(Options{EmitDockerfile: true, Library: true}); got["Dockerfile"] {
+        t.Fatalf("library layout should have no Dockerfile: %v", got)
+    }
+
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", GoVersion: "1.24", EmitDockerfile: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        "Dockerfile": {"FROM golang:1.24 AS build", "go mod tidy && CGO_ENABLED=0 go build", "-o /out/mytool ./cmd/mytool", `ENTRYPOINT ["/usr/local/bin/mytool"]`},
+        "Makefile":   {"IMAGE ?= $(TOOL)", "docker-build:\n\tdocker build -t $(IMAGE) .", "docker-run:\n\tdocker run -i --rm $(IMAGE)", ".PHONY: help build build-all test fmt tidy clean docker-build docker-run"},
+        "README.md":  {"make docker-run"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+
+    pinned := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: pinned, ToolName: "mytool", PinDependencies: true, EmitDockerfile: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if data, _ := os.ReadFile(filepath.Join(pinned, "Dockerfile")); strings.Contains(string(data), "go mod tidy") {
+        t.Fatalf("pinned Dockerfile should build from the written go.sum:\n%s", data)
+    }
+}
+
+func TestEmit_WithOTel(t *testing.T) {
+    t.Parallel()
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(plain, "internal", "mcp", "otel.go")); !os.IsNotExist(err) {
+        t.Fatalf("otel.go written without WithOTel: %v", err)
+    }
+    for _, rel := range []string{"go.mod", filepath.Join("internal", "mcp", "server.go"), filepath.Join("cmd", "mytool", "main.go")} {
+        if data, _ := os.ReadFile(filepath.Join(plain, rel)); strings.Contains(string(data), "opentelemetry") || strings.Contains(string(data), "Tracing") {
+            t.Fatalf("%s mentions tracing without WithOTel:\n%s", rel, data)
+        }
+    }
+
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", WithOTel: true, GenerateMocks: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        "go.mod":                                      {"github.com/stretchr/testify v1.9.0", "go.opentelemetry.io/otel " + otelVersion, "go.opentelemetry.io/otel/sdk " + otelVersion},
+        filepath.Join("internal", "mcp", "server.go"): {"goserver.WithToolHandlerMiddleware(traceTool),"},
+        filepath.Join("internal", "mcp", "otel.go"):   {"func SetupTracing(ctx context.Context)", "OTEL_EXPORTER_OTLP_ENDPOINT", `"tools/call "+name`},
+        filepath.Join("cmd", "mytool", "main.go"):     {"shutdown, err := mcp.SetupTracing(context.Background())"},
+        filepath.Join("tests", "otel_test.go"):        {"func Test_ToolsWithoutExporter("},
+        "README.md":                                   {"Tracing:"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", WithOTel: true, PinDependencies: true, DryRun: true}); err == nil {
+        t.Fatalf("expected PinDependencies to reject WithOTel")
+    }
+
+    // Building and testing the project needs the toolchain and the OpenTelemetry modules.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    for _, args := range [][]string{{"mod", "tidy"}, {"test", "./..."}} {
+        cmd := exec.Command("go", args...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_ShardModelByTag(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Endpoints = append(sm.Endpoints,
+        genspec.EndpointModel{ID: "get /health", Method: genspec.GET, Path: "/health"},
+        genspec.EndpointModel{ID: "post /hello", Method: genspec.POST, Path: "/hello", Tags: []string{"read"}},
+    )
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", ShardModelByTag: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    specDir := filepath.Join(dir, "internal", "spec")
+    if _, err := os.Stat(filepath.Join(specDir, "model.json")); !os.IsNotExist(err) {
+        t.Fatalf("model.json written with ShardModelByTag: %v", err)
+    }
+    for _, name := range []string{"_index.json", "read.json", "_untagged.json"} {
+        if _, err := os.Stat(filepath.Join(specDir, "model", name)); err != nil {
+            t.Fatalf("missing shard %s: %v", name, err)
+        }
+    }
+    loader, _ := os.ReadFile(filepath.Join(specDir, "loader.go"))
+    for _, want := range []string{"//go:embed model/*.json", "func LoadShard(name string) ([]EndpointModel, error)", "func LoadTag(tag string) ([]EndpointModel, error)", "func LoadFromFile(path string)"} {
+        if !strings.Contains(string(loader), want) {
+            t.Fatalf("loader.go missing %q:\n%s", want, loader)
+        }
+    }
+    if readme, _ := os.ReadFile(filepath.Join(dir, "README.md")); !strings.Contains(string(readme), "Model shards:") {
+        t.Fatalf("README should document the shards:\n%s", readme)
+    }
+    if _, err := Emit(context.Background(), sm, Options{OutDir: t.TempDir(), ToolName: "mytool", ShardModelByTag: true, ExcludeFiles: []string{"_untagged.json"}}); err == nil {
+        t.Fatalf("expected ExcludeFiles to refuse a model shard")
+    }
+    lib := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: lib, ToolName: "mytool", Library: true, ShardModelByTag: true}); err != nil {
+        t.Fatalf("emit library: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(lib, "spec", "model", "_index.json")); err != nil {
+        t.Fatalf("library misses its shard index: %v", err)
+    }
+
+    // Compiling needs a Go toolchain; gate it like the other build checks.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    mod := filepath.Join(t.TempDir(), "spec")
+    if err := os.CopyFS(mod, os.DirFS(specDir)); err != nil {
+        t.Fatalf("copy spec package: %v", err)
+    }
+    files := map[string]string{
+        "go.mod": "module example.com/mytool/internal/spec\n\ngo 1.23\n",
+        "shard_test.go": `package spec
+
+import "testing"
+
+func TestShards(t *testing.T) {
+    sm, err := LoadEmbedded()
+    if err != nil || sm.Title != "Sample API" || len(sm.Endpoints) != 3 || sm.Endpoints[1].ID != "get /health" {
+        t.Fatalf("LoadEmbedded: %v %+v", err, sm)
+    }
+    idx, err := LoadIndex()
+    if err != nil || idx.Title != "Sample API" || len(idx.Endpoints) != 0 {
+        t.Fatalf("LoadIndex: %v %+v", err, idx)
+    }
+    shards, err := Shards()
+    if err != nil || len(shards) != 2 || shards[0] != (Shard{Name: "read", Tag: "read", Endpoints: 2}) {
+        t.Fatalf("Shards: %v %+v", err, shards)
+    }
+    eps, err := LoadTag("read")
+    if err != nil || len(eps) != 2 || eps[1].ID != "post /hello" {
+        t.Fatalf("LoadTag: %v %+v", err, eps)
+    }
+    if eps, err = LoadTag(""); err != nil || len(eps) != 1 {
+        t.Fatalf("LoadTag untagged: %v %+v", err, eps)
+    }
+    if _, err := LoadShard("missing"); err == nil {
+        t.Fatal("expected an error for an unknown shard")
+    }
+}
+`,
+    }
+    for name, content := range files {
+        if err := os.WriteFile(filepath.Join(mod, name), []byte(content), 0o644); err != nil { t.Fatalf("write %s: %v", name, err) }
+    }
+    cmd := exec.Command("go", "test", "./...")
+    cmd.Dir = mod
+    if out, err := cmd.CombinedOutput(); err != nil {
+        t.Fatalf("go test: %v\n%s", err, out)
+    }
+}
+
+func TestEmit_SplitByTag(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Tags = []string{"read", "write"}
+    sm.Endpoints = append(sm.Endpoints,
+        genspec.EndpointModel{ID: "post /notes", Method: genspec.POST, Path: "/notes", Tags: []string{"write", "read"}},
+        genspec.EndpointModel{ID: "post /hello", Method: genspec.POST, Path: "/hello", Tags: []string{"read"}},
+    )
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ModuleName: "example.com/mytool", SplitByTag: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    entries, err := os.ReadDir(filepath.Join(dir, "cmd"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var cmds []string
+    for _, e := range entries {
+        cmds = append(cmds, e.Name())
+    }
+    if want := []string{"mytool-read", "mytool-write"}; !reflect.DeepEqual(cmds, want) {
+        t.Fatalf("cmd/ = %v, want %v", cmds, want)
+    }
+    for tag, want := range map[string]int{"read": 2, "write": 1} {
+        cmdDir := filepath.Join(dir, "cmd", "mytool-"+tag)
+        for _, name := range []string{"main.go", "main_test.go"} {
+            if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(cmdDir, name), nil, 0); err != nil {
+                t.Fatalf("parse %s: %v", name, err)
+            }
+        }
+        mainGo, _ := os.ReadFile(filepath.Join(cmdDir, "main.go"))
+        if !strings.Contains(string(mainGo), fmt.Sprintf("sm.Endpoints = endpointsWithTag(sm.Endpoints, %q)", tag)) {
+            t.Fatalf("cmd/mytool-%s/main.go should keep only the endpoints tagged %s:\n%s", tag, tag, mainGo)
+        }
+        mainTest, _ := os.ReadFile(filepath.Join(cmdDir, "main_test.go"))
+        if !strings.Contains(string(mainTest), fmt.Sprintf("const tag, want = %q, %d", tag, want)) {
+            t.Fatalf("cmd/mytool-%s/main_test.go should expect %d endpoints:\n%s", tag, want, mainTest)
+        }
+    }
+    makefile, _ := os.ReadFile(filepath.Join(dir, "Makefile"))
+    for _, want := range []string{
+        "BINARIES := mytool-read mytool-write\n",
+        "build: $(BINARIES:%=build-%)\n",
+        "build-mytool-read:\n\tgo build -o bin/mytool-read$(EXE) ./cmd/mytool-read\n",
+        "build-mytool-write:\n\tgo build -o bin/mytool-write$(EXE) ./cmd/mytool-write\n",
+        "go build -o \"$$out\" ./cmd/$$bin",
+        ".PHONY: help build build-mytool-read build-mytool-write build-all",
+    } {
+        if !strings.Contains(string(makefile), want) {
+            t.Fatalf("Makefile missing %q:\n%s", want, makefile)
+        }
+    }
+    launch, _ := os.ReadFile(filepath.Join(dir, ".vscode", "launch.json"))
+    if !strings.Contains(string(launch), `"program": "${workspaceFolder}/cmd/mytool-read"`) || !strings.Contains(string(launch), `"name": "Debug mytool-write (stdio)"`) {
+        t.Fatalf("launch.json should debug every binary:\n%s", launch)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "- cmd/mytool-read — tag read (2 endpoints)\n- cmd/mytool-write — tag write (1 endpoint)\n") || strings.Contains(string(readme), "cmd/mytool ") {
+        t.Fatalf("README.md should list the binaries:\n%s", readme)
+    }
+
+    untagged := minimalModel()
+    untagged.Endpoints = append(untagged.Endpoints, genspec.EndpointModel{ID: "get /health", Method: genspec.GET, Path: "/health"})
+    dir2 := t.TempDir()
+    if _, err := Emit(context.Background(), untagged, Options{OutDir: dir2, ToolName: "mytool", SplitByTag: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    mainGo, _ := os.ReadFile(filepath.Join(dir2, "cmd", "mytool-untagged", "main.go"))
+    if !strings.Contains(string(mainGo), `endpointsWithTag(sm.Endpoints, "")`) {
+        t.Fatalf("the untagged endpoints should get a binary:\n%s", mainGo)
+    }
+
+    untagged.Endpoints = untagged.Endpoints[1:]
+    if _, err := Emit(context.Background(), untagged, Options{OutDir: t.TempDir(), SplitByTag: true}); err == nil || !strings.Contains(err.Error(), "needs endpoints with tags") {
+        t.Fatalf("expected an error for a model without tags, got %v", err)
+    }
+    if _, err := Emit(context.Background(), sm, Options{OutDir: t.TempDir(), SplitByTag: true, EmitDockerfile: true}); err == nil || !strings.Contains(err.Error(), "EmitDockerfile") {
+        t.Fatalf("expected an EmitDockerfile error, got %v", err)
+    }
+
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go toolchain not available")
+    }
+    for _, args := range [][]string{{"mod", "tidy"}, {"test", "./cmd/..."}} {
+        cmd := exec.Command("go", args...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+    for tag, want := range map[string]int{"read": 2, "write": 1} {
+        cmd := exec.Command("go", "run", "./cmd/mytool-"+tag, "--describe")
+        cmd.Dir = dir
+        out, err := cmd.Output()
+        if err != nil {
+            t.Fatalf("go run ./cmd/mytool-%s --describe: %v", tag, err)
+        }
+        var d struct {
+            Endpoints int `json:"endpoints"`
+        }
+        if err := json.Unmarshal(out, &d); err != nil || d.Endpoints != want {
+            t.Fatalf("cmd/mytool-%s serves %d endpoints, want %d (%v):\n%s", tag, d.Endpoints, want, err, out)
+        }
+    }
+}
+
+func TestEmit_GenerateCI(t *testing.T) {
+    t.Parallel()
+    workflow := func(opts Options) (map[string][]string, string) {
+        t.Helper()
+        dir := t.TempDir()
+        opts.OutDir, opts.ToolName, opts.GenerateCI = dir, "mytool", true
+        if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        data, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+        if err != nil {
+            t.Fatalf("read ci.yml: %v", err)
+        }
+        var wf struct {
+            On   map[string]any `yaml:"on"`
+            Jobs map[string]struct {
+                RunsOn string `yaml:"runs-on"`
+                Steps  []struct {
+                    Uses string `yaml:"uses"`
+                    Run  string `yaml:"run"`
+                } `yaml:"steps"`
+            } `yaml:"jobs"`
+        }
+        if err := yaml.Unmarshal(data, &wf); err != nil {
+            t.Fatalf("ci.yml is not valid YAML: %v\n%s", err, data)
+        }
+        if _, ok := wf.On["push"]; !ok {
+            t.Errorf("ci.yml should run on push:\n%s", data)
+        }
+        if _, ok := wf.On["pull_request"]; !ok {
+            t.Errorf("ci.yml should run on pull_request:\n%s", data)
+        }
+        jobs := map[string][]string{}
+        for name, job := range wf.Jobs {
+            if job.RunsOn != "ubuntu-latest" {
+                t.Errorf("job %s runs on %q", name, job.RunsOn)
+            }
+            for _, step := range job.Steps {
+                jobs[name] = append(jobs[name], step.Uses+step.Run)
+            }
+        }
+        readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+        return jobs, string(readme)
+    }
+
+    setup := []string{"actions/checkout@v4", "actions/setup-go@v5", "go mod tidy"}
+    jobs, readme := workflow(Options{GenerateLintConfig: true})
+    want := map[string][]string{
+        "build": append(append([]string(nil), setup...), "go build ./...", "go vet ./...", "go test ./..."),
+        "lint":  append(append([]string(nil), setup...), "golangci/golangci-lint-action@v8"),
+    }
+    if !reflect.DeepEqual(jobs, want) {
+        t.Fatalf("jobs = %q, want %q", jobs, want)
+    }
+    if !strings.Contains(readme, "it builds and vets the module, runs the tests and lints it with golangci-lint.") {
+        t.Errorf("README does not describe the CI workflow:\n%s", readme)
+    }
+
+    jobs, readme = workflow(Options{PinDependencies: true, Skip: []string{"tests"}})
+    want = map[string][]string{"build": {"actions/checkout@v4", "actions/setup-go@v5", "go build ./...", "go vet ./..."}}
+    if !reflect.DeepEqual(jobs, want) {
+        t.Fatalf("pinned, without tests: jobs = %q, want %q", jobs, want)
+    }
+    if !strings.Contains(readme, "it builds and vets the module.") {
+        t.Errorf("README does not describe the CI workflow:\n%s", readme)
+    }
+
+    dir := t.TempDir()
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", GenerateCI: true, Library: true, DryRun: true})
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for _, p := range res.Planned {
+        if strings.HasPrefix(p.RelPath, ".github/") {
+            t.Fatalf("library layout should have no CI workflow: %s", p.RelPath)
+        }
+    }
+}
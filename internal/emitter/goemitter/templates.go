@@ -1,17 +1,52 @@
 package goemitter
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/modelshard"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
+// defaultAuthor is credited as the generated project's author.
+const defaultAuthor = "Generated by swagger2mcp"
+
 type templateData struct {
 	ToolName    string
 	ModuleName  string
 	serviceName string
 	service     *genspec.ServiceModel
+	interfaces  bool   // emit the Handler interface and route tools through it
+	mocks       bool   // emit internal/mcp/mocks with a testify MockHandler
+	lint        bool   // emit .golangci.yml and the Makefile lint target
+	pinned      bool   // list pinnedModules in go.mod and write go.sum
+	invoke      bool   // emit the callEndpoint tool, which calls the upstream API
+	docker      bool   // emit a Dockerfile, .dockerignore and the Makefile docker targets
+	ci          bool   // emit the GitHub Actions workflow .github/workflows/ci.yml
+	otel        bool   // trace tool calls with OpenTelemetry (internal/mcp/otel.go)
+	shardModel  bool   // embed the model as per-tag shards under model/ (modelshard)
+	author      string // project author and copyright holder in LICENSE
+	authorEmail string // author's e-mail address; optional
+	license     string // SPDX identifier of LICENSE; empty when none is written
+	version     string // project version shown in the README
+	year        int    // copyright year in LICENSE
+
+	skip emitter.Skip // categories of files left out; the rest refer only to what is generated
+
+	tagBinaries []tagBinary // with SplitByTag, the per-tag binaries replacing cmd/<tool>
+
+	goVersion     string // go directive in go.mod
+	mcpLibVersion string // required github.com/mark3labs/mcp-go version
+}
+
+// tagBinary is a server binary under cmd/: the tool's, serving every endpoint
+// when shard is nil, or with SplitByTag that of the endpoints of shard.
+type tagBinary struct {
+	name  string
+	shard *modelshard.Shard
 }
 
 func newTemplateData(toolName, moduleName string, sm *genspec.ServiceModel) templateData {
@@ -24,13 +59,45 @@ func newTemplateData(toolName, moduleName string, sm *genspec.ServiceModel) temp
 		ModuleName:  strings.TrimSpace(moduleName),
 		serviceName: serviceTitle,
 		service:     sm,
+		author:      defaultAuthor,
+		version:     genspec.DefaultProjectVersion,
+		year:        time.Now().Year(),
+
+		goVersion:     DefaultGoVersion,
+		mcpLibVersion: DefaultMCPLibVersion,
+	}
+}
+
+// binaries returns the server binaries: one per tag with SplitByTag, else
+// the tool's.
+func (d templateData) binaries() []tagBinary {
+	if len(d.tagBinaries) > 0 {
+		return d.tagBinaries
 	}
+	return []tagBinary{{name: d.ToolName}}
 }
 
 func (d templateData) serviceTitle() string {
 	return d.serviceName
 }
 
+// metadataLines returns the README lines naming the project version, the
+// license and, when one was given, the author.
+func (d templateData) metadataLines() []string {
+	lines := []string{"Version: " + d.version}
+	if d.license != "" {
+		lines = append(lines, fmt.Sprintf("License: %s, see LICENSE.", d.license))
+	}
+	if d.author != defaultAuthor || d.authorEmail != "" {
+		author := d.author
+		if d.authorEmail != "" {
+			author += " <" + d.authorEmail + ">"
+		}
+		lines = append(lines, "Author: "+author)
+	}
+	return append(lines, "")
+}
+
 func (d templateData) apply(content string) string {
 	replacer := strings.NewReplacer(
 		"{{MODULE}}", d.ModuleName,
@@ -55,7 +122,100 @@ func normalize(content string) string {
 // Templates and content renderers
 
 func renderGoMod(data templateData) string {
-	return normalize(fmt.Sprintf("module %s\n\ngo 1.23\n\nrequire github.com/mark3labs/mcp-go v0.40.0\n\n", data.ModuleName))
+	if data.mocks || data.otel {
+		requires := []string{"github.com/mark3labs/mcp-go " + data.mcpLibVersion}
+		if data.mocks {
+			requires = append(requires, "github.com/stretchr/testify v1.9.0")
+		}
+		if data.otel {
+			requires = append(requires, otelModules...)
+		}
+		return normalize(fmt.Sprintf("module %s\n\ngo %s\n\nrequire (\n\t%s\n)\n\n", data.ModuleName, data.goVersion, strings.Join(requires, "\n\t")))
+	}
+	gomod := fmt.Sprintf("module %s\n\ngo %s\n\nrequire github.com/mark3labs/mcp-go %s\n\n", data.ModuleName, data.goVersion, data.mcpLibVersion)
+	if data.pinned {
+		gomod += "require (\n\t" + strings.Join(pinnedModules, " // indirect\n\t") + " // indirect\n)\n"
+	}
+	return normalize(gomod)
+}
+
+// renderLibraryGoMod returns the go.mod of the library layout; the spec
+// package only uses the standard library.
+func renderLibraryGoMod(data templateData) string {
+	return normalize(fmt.Sprintf("module %s\n\ngo %s\n", data.ModuleName, data.goVersion))
+}
+
+func renderLibraryReadme(data templateData) string {
+	lines := []string{
+		fmt.Sprintf("# %s", data.ToolName),
+		"",
+		fmt.Sprintf("Typed service model for %s", data.serviceTitle()),
+		"",
+		"This library was generated by swagger2mcp. It contains only the spec package: the Go types",
+		"of the API model and the model itself (" + data.modelData("spec") + "), embedded at build time. Use it to",
+		"build your own MCP server or tooling on the same model the generated servers use.",
+		"",
+		fmt.Sprintf("Requires Go %s or newer and no other dependencies.", data.goVersion),
+		"",
+		"```go",
+		fmt.Sprintf("import \"%s/spec\"", data.ModuleName),
+		"",
+		"sm, err := spec.Load() // embedded model, or the file named by MCP_MODEL_PATH",
+		"if err != nil {",
+		"    log.Fatal(err)",
+		"}",
+		"spec.ApplyBaseURLOverride(sm) // honor API_BASE_URL",
+		"for _, ep := range sm.Endpoints {",
+		"    fmt.Println(ep.Method, ep.Path, ep.Summary)",
+		"}",
+		"fmt.Println(\"base URL:\", spec.BaseURL(sm))",
+		"```",
+		"",
+	}
+	if data.shardModel {
+		lines = append(lines, shardedModelReadmeLines("spec")...)
+	}
+	lines = append(lines, data.metadataLines()...)
+	return normalize(strings.Join(lines, "\n"))
+}
+
+// modelData names the embedded model data of the spec package in dir for
+// the README: model.json, or the model/ directory of shards.
+func (d templateData) modelData(dir string) string {
+	if d.shardModel {
+		return dir + "/model/"
+	}
+	return dir + "/model.json"
+}
+
+// shardedModelReadmeLines documents the per-tag shards of ShardModelByTag.
+func shardedModelReadmeLines(dir string) []string {
+	return []string{
+		"Model shards:",
+		"",
+		"The model is split by tag: " + dir + "/model/_index.json holds everything but the endpoints,",
+		"and " + dir + "/model/<tag>.json the endpoints whose first tag it is (_untagged.json those",
+		"without tags). spec.Load still returns the whole model; spec.LoadIndex, spec.Shards,",
+		"spec.LoadTag and spec.LoadShard read only what they need, each shard once.",
+		"",
+	}
+}
+
+// readmeTagLines lists the model's tags with their descriptions for the
+// README, followed by a blank line; it is empty when no endpoint has a tag.
+func readmeTagLines(sm *genspec.ServiceModel) []string {
+	if sm == nil || len(sm.Tags) == 0 {
+		return nil
+	}
+	lines := []string{"Tags:", ""}
+	for _, tag := range sm.Tags {
+		line := "- " + tag
+		if desc := strings.Join(strings.Fields(sm.TagDescriptions[tag]), " "); desc != "" {
+			line += " — " + desc
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, "")
 }
 
 func renderReadme(data templateData) string {
@@ -66,24 +226,292 @@ func renderReadme(data templateData) string {
 		"",
 		"This project was generated by swagger2mcp and exposes MCP methods to query your API documentation.",
 		"",
-		"- Methods: listEndpoints, searchEndpoints, getEndpointDetails, listSchemas, getSchemaDetails",
-		"- Runtime: Go (github.com/mark3labs/mcp-go)",
+		"- Methods: listEndpoints, searchEndpoints, getEndpointDetails, listSchemas, getSchemaDetails, explainParameter",
+		fmt.Sprintf("- Runtime: Go (github.com/mark3labs/mcp-go %s)", data.mcpLibVersion),
+		"",
+	}
+	lines = append(lines, readmeTagLines(data.service)...)
+	// Examples that run one binary use the first of SplitByTag.
+	bin, install := data.ToolName, "./cmd/"+data.ToolName
+	if len(data.tagBinaries) > 0 {
+		bin, install = data.tagBinaries[0].name, "./cmd/..."
+		lines = append(lines, splitBinaryReadmeLines(data)...)
+	}
+	lines = append(lines, fmt.Sprintf("Build (requires Go %s or newer):", data.goVersion), "")
+	switch {
+	case data.skip.Makefile:
+		lines = append(lines, "```")
+		for _, b := range data.binaries() {
+			lines = append(lines, "go build -o bin/"+b.name+" ./cmd/"+b.name+"  # add .exe to the output name on Windows")
+		}
+		lines = append(lines, "```", "")
+	case len(data.tagBinaries) > 0:
+		one := "make build-" + bin
+		pad := func(cmd string) string { return cmd + strings.Repeat(" ", len(one)-len(cmd)+2) + "# " }
+		lines = append(lines,
+			"```",
+			pad("make build")+"bin/<binary> for every binary above (bin\\<binary>.exe on Windows)",
+			pad(one)+"just bin/"+bin,
+			pad("make build-all")+"dist/<binary>_<os>_<arch>[.exe] plus dist/checksums.txt",
+			"```",
+			"",
+			"Without make, run `go build -o bin/<binary> ./cmd/<binary>` (add `.exe` to the output name on Windows).",
+			"",
+		)
+	default:
+		lines = append(lines,
+			"```",
+			"make build      # bin/"+data.ToolName+" (bin\\"+data.ToolName+".exe on Windows)",
+			"make build-all  # dist/"+data.ToolName+"_<os>_<arch>[.exe] plus dist/checksums.txt",
+			"```",
+			"",
+			"Without make, run `go build -o bin/"+data.ToolName+" ./cmd/"+data.ToolName+"` (add `.exe` to the output name on Windows).",
+			"",
+		)
+	}
+	var hosts, winHosts []string
+	for i, b := range data.binaries() {
+		sep := ""
+		if i < len(data.binaries())-1 {
+			sep = ","
+		}
+		hosts = append(hosts, fmt.Sprintf("    \"%s\": { \"command\": \"/path/to/%s/bin/%s\" }%s", b.name, data.ToolName, b.name, sep))
+		winHosts = append(winHosts, fmt.Sprintf("    \"%s\": { \"command\": \"C:\\\\path\\\\to\\\\%s\\\\bin\\\\%s.exe\" }%s", b.name, data.ToolName, b.name, sep))
+	}
+	lines = append(lines, []string{
+		"The API model (" + data.modelData("internal/spec") + ") is compiled into the binary, so it runs from any",
+		"directory and `go install " + install + "` just works. Set MCP_MODEL_PATH to a model.json",
+		"file to load that model at runtime instead.",
+		"",
+		"Requests target the spec's first server URL. Set API_BASE_URL to point the same",
+		"binary at another environment (e.g. staging); it takes precedence over the spec's servers.",
+		"",
+		"Use with an MCP host (absolute path to the built binary):",
+		"",
+		"```json",
+		"{",
+		"  \"mcpServers\": {",
+	}...)
+	lines = append(lines, hosts...)
+	lines = append(lines, []string{
+		"  }",
+		"}",
+		"```",
+		"",
+		"On Windows, escape backslashes and include the extension:",
+		"",
+		"```json",
+		"{",
+		"  \"mcpServers\": {",
+	}...)
+	lines = append(lines, winHosts...)
+	lines = append(lines, []string{
+		"  }",
+		"}",
+		"```",
+		"",
+		"Remote MCP clients can reach the server over streamable HTTP instead of stdio:",
+		"",
+		"```",
+		fmt.Sprintf("bin/%s --transport http   # serves http://127.0.0.1:8080/mcp", bin),
+		"```",
+		"",
+		"Set MCP_HTTP_ADDR (e.g. `0.0.0.0:8080`) to choose the listen address, or PORT to listen",
+		"on all interfaces on that port; MCP_HTTP_ADDR wins when both are set.",
 		"",
-		"Build:",
+		"To see what a built binary contains without an MCP host, print its tools, the API",
+		"title and version, the endpoint and schema counts and the SHA-256 of its model as JSON:",
 		"",
 		"```",
-		"go build ./...",
+		fmt.Sprintf("bin/%s --describe", bin),
 		"```",
 		"",
+	}...)
+	if !data.skip.Editor && len(data.tagBinaries) > 0 {
+		lines = append(lines, "Debug (VS Code):", "", "Open the project with the Go extension installed; .vscode/launch.json provides")
+		lines = append(lines, "\"Debug <binary> (stdio)\" for each binary, which runs ./cmd/<binary> in the integrated terminal.")
+		if !data.skip.Tests {
+			lines = append(lines, "\"Debug tests\" runs ./tests under the debugger.")
+		}
+		lines = append(lines, "")
+	} else if !data.skip.Editor {
+		lines = append(lines, "Debug (VS Code):", "", "Open the project with the Go extension installed; .vscode/launch.json provides")
+		if data.skip.Tests {
+			lines = append(lines, fmt.Sprintf("\"Debug MCP server (stdio)\", which runs ./cmd/%s in the integrated terminal.", data.ToolName), "")
+		} else {
+			lines = append(lines,
+				fmt.Sprintf("\"Debug MCP server (stdio)\", which runs ./cmd/%s in the integrated terminal, and", data.ToolName),
+				"\"Debug tests\", which runs ./tests under the debugger.",
+				"",
+			)
+		}
+	}
+	if data.pinned {
+		lines = append(lines,
+			"Dependencies are pinned: go.mod lists every module the build needs and go.sum their",
+			"checksums, so the build is reproducible and works offline from a populated module cache",
+			"without `go mod tidy`.",
+			"",
+		)
+	}
+	if data.shardModel {
+		lines = append(lines, shardedModelReadmeLines("internal/spec")...)
+	}
+	if data.invoke {
+		lines = append(lines,
+			"Calling the API:",
+			"",
+			"The callEndpoint tool sends a request to an endpoint at the base URL above. Required",
+			"parameters and the request body are checked against the model before anything is sent.",
+			"Set API_TIMEOUT (e.g. `10s`; default 30s) to bound each attempt, and API_RETRIES (default 0)",
+			"to resend a request that fails, times out or gets a 429 or 5xx response, waiting 100ms",
+			"before the first retry and twice as long before each further one, up to 2s. The response",
+			"body is returned up to 64 KiB.",
+			"",
+		)
+		if creds := emitter.Credentials(data.ToolName, data.service); len(creds) > 0 {
+			lines = append(lines, "Credentials are read from the environment and sent as each endpoint's security requirements ask:", "")
+			for _, c := range creds {
+				lines = append(lines, fmt.Sprintf("- `%s`: scheme `%s`, sent in the %s", c.EnvVar, c.Scheme, c.Placement()))
+			}
+			lines = append(lines, "", "A call to an endpoint whose requirements these do not meet fails before anything is sent.", "")
+		}
 	}
+	if data.docker && data.skip.Makefile {
+		lines = append(lines,
+			"Docker:",
+			"",
+			"```",
+			"docker build -t "+data.ToolName+" .",
+			"docker run -i --rm "+data.ToolName,
+			"```",
+			"",
+			"The image holds only the static binary and serves MCP over stdio, so run it with `-i`.",
+			"Pass API_BASE_URL and other settings with `-e`.",
+			"",
+		)
+	} else if data.docker {
+		lines = append(lines,
+			"Docker:",
+			"",
+			"```",
+			"make docker-build  # docker build -t "+data.ToolName+" .",
+			"make docker-run    # docker run -i --rm "+data.ToolName,
+			"```",
+			"",
+			"The image holds only the static binary and serves MCP over stdio, so run it with `-i`;",
+			"set IMAGE to tag it differently. Pass API_BASE_URL and other settings with `-e`.",
+			"",
+		)
+	}
+	if data.otel {
+		lines = append(lines,
+			"Tracing:",
+			"",
+			"Each tool call is recorded as an OpenTelemetry span named `tools/call <tool>`, with the",
+			"tool name, its duration and an error status when it fails. Spans are exported over",
+			"OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) is set,",
+			"e.g. `http://localhost:4318`; the other standard OTEL_* variables configure headers and the",
+			"service name. Without an endpoint tracing is a no-op.",
+			"",
+		)
+	}
+	if data.lint {
+		run := "`make lint` runs golangci-lint"
+		if data.skip.Makefile {
+			run = "`golangci-lint run ./...` lints"
+		}
+		lines = append(lines,
+			"Lint:",
+			"",
+			run+" with the baseline in .golangci.yml (generated by swagger2mcp — customize as needed).",
+			"",
+		)
+	}
+	if data.ci {
+		lines = append(lines,
+			"CI:",
+			"",
+			"The GitHub Actions workflow in .github/workflows/ci.yml runs on every push and pull request:",
+			"it builds and vets the module"+ciTestClause(data)+".",
+			"",
+		)
+	}
+	lines = append(lines, data.metadataLines()...)
 	return normalize(strings.Join(lines, "\n"))
 }
 
-func renderMainGo(data templateData) string {
-	return normalize(fmt.Sprintf(`package main
+// splitBinaryReadmeLines lists the binaries of SplitByTag for the README.
+func splitBinaryReadmeLines(data templateData) []string {
+	lines := []string{
+		"Binaries:",
+		"",
+		"Each tag of the API has its own binary, serving only the endpoints whose first tag it is,",
+		"so each can be deployed as a separate MCP server:",
+		"",
+	}
+	for _, b := range data.tagBinaries {
+		what := "tag " + b.shard.Tag
+		if b.shard.Tag == "" {
+			what = "endpoints without tags"
+		}
+		count := fmt.Sprintf("%d endpoints", b.shard.Endpoints)
+		if b.shard.Endpoints == 1 {
+			count = "1 endpoint"
+		}
+		lines = append(lines, fmt.Sprintf("- cmd/%s — %s (%s)", b.name, what, count))
+	}
+	return append(lines, "")
+}
+
+// renderVSCodeLaunch returns a .vscode/launch.json that debugs each stdio binary
+// and, unless they are skipped, the generated tests with the Go extension
+// (delve).
+func renderVSCodeLaunch(data templateData) string {
+	var configs []map[string]any
+	for _, bin := range data.binaries() {
+		name := "Debug MCP server (stdio)"
+		if bin.shard != nil {
+			name = "Debug " + bin.name + " (stdio)"
+		}
+		configs = append(configs, map[string]any{
+			"type":    "go",
+			"request": "launch",
+			"name":    name,
+			"mode":    "auto",
+			"program": "${workspaceFolder}/cmd/" + bin.name,
+			"console": "integratedTerminal",
+		})
+	}
+	if !data.skip.Tests {
+		configs = append(configs, map[string]any{
+			"type":    "go",
+			"request": "launch",
+			"name":    "Debug tests",
+			"mode":    "test",
+			"program": "${workspaceFolder}/tests",
+		})
+	}
+	cfg := map[string]any{
+		"version":        "0.2.0",
+		"configurations": configs,
+	}
+	b, _ := json.MarshalIndent(cfg, "", "  ")
+	return string(b) + "\n"
+}
+
+// renderMainGo returns the server entry point of bin. It serves over stdio
+// unless --transport http selects mcp-go's streamable HTTP server; --describe
+// prints the server's tools and model summary instead. A binary of
+// SplitByTag drops the endpoints of other tags after loading the model.
+func renderMainGo(data templateData, bin tagBinary) string {
+	src := fmt.Sprintf(`package main
 
 import (
+    "flag"
     "log"
+    "net"
+    "os"
 
     goserver "github.com/mark3labs/mcp-go/server"
 
@@ -91,20 +519,148 @@ import (
     "%s/internal/spec"
 )
 
+// defaultHTTPAddr is where --transport http listens when neither
+// MCP_HTTP_ADDR nor PORT is set.
+const defaultHTTPAddr = "127.0.0.1:8080"
+
 func main() {
-    // Load the embedded service model
+    transport := flag.String("transport", "stdio", "MCP transport: stdio, or http (streamable HTTP at /mcp on MCP_HTTP_ADDR or :$PORT)")
+    describe := flag.Bool("describe", false, "print the tools and a summary of the model as JSON, then exit")
+    flag.Parse()
+    if *transport != "stdio" && *transport != "http" {
+        log.Fatalf("unknown transport %%q (want stdio or http)", *transport)
+    }
+
+    // Load the embedded service model (or the file named by MCP_MODEL_PATH)
     sm, err := spec.Load()
     if err != nil {
         log.Fatalf("load model: %%v", err)
     }
 
-    // Create MCP server and serve over stdio
     srv := mcp.NewMCPServer(sm)
+    if *describe {
+        if err := mcp.WriteDescription(os.Stdout, srv, sm); err != nil {
+            log.Fatalf("describe: %%v", err)
+        }
+        return
+    }
+    if *transport == "http" {
+        addr := httpAddr()
+        log.Printf("mcp http: listening on http://%%s/mcp", addr)
+        if err := goserver.NewStreamableHTTPServer(srv).Start(addr); err != nil {
+            log.Fatalf("mcp http: %%v", err)
+        }
+        return
+    }
     if err := goserver.ServeStdio(srv); err != nil {
         log.Fatalf("mcp stdio: %%v", err)
     }
 }
-`, data.ModuleName, data.ModuleName))
+
+// httpAddr returns MCP_HTTP_ADDR when set, else all interfaces on PORT (as
+// container platforms expect), else defaultHTTPAddr.
+func httpAddr() string {
+    if addr := os.Getenv("MCP_HTTP_ADDR"); addr != "" {
+        return addr
+    }
+    if port := os.Getenv("PORT"); port != "" {
+        return net.JoinHostPort("", port)
+    }
+    return defaultHTTPAddr
+}
+`, data.ModuleName, data.ModuleName)
+	if bin.shard != nil {
+		src = withTagFilter(src, bin.shard.Tag)
+	}
+	if data.otel {
+		src = withTracingSetup(src)
+	}
+	return normalize(src)
+}
+
+// withTagFilter makes main.go serve only the endpoints whose first tag is
+// tag, or those without tags when tag is "".
+func withTagFilter(src, tag string) string {
+	what := fmt.Sprintf("whose first tag is %q", tag)
+	if tag == "" {
+		what = "without tags"
+	}
+	return strings.NewReplacer(`
+        log.Fatalf("load model: %v", err)
+    }
+`, fmt.Sprintf(`
+        log.Fatalf("load model: %%v", err)
+    }
+    // Serve only the endpoints %s; each tag has its own binary
+    sm.Endpoints = endpointsWithTag(sm.Endpoints, %q)
+`, what, tag)).Replace(src) + `
+// endpointsWithTag returns the endpoints whose first tag is tag, or those
+// without tags when tag is "".
+func endpointsWithTag(eps []spec.EndpointModel, tag string) []spec.EndpointModel {
+    out := []spec.EndpointModel{}
+    for _, ep := range eps {
+        first := ""
+        if len(ep.Tags) > 0 {
+            first = ep.Tags[0]
+        }
+        if first == tag {
+            out = append(out, ep)
+        }
+    }
+    return out
+}
+`
+}
+
+// renderMainTestGo tests how the entry point of bin picks the HTTP listen
+// address and, for a binary of SplitByTag, which endpoints it keeps.
+func renderMainTestGo(data templateData, bin tagBinary) string {
+	src := `package main
+
+import "testing"
+
+func TestHTTPAddr(t *testing.T) {
+    t.Setenv("MCP_HTTP_ADDR", "")
+    t.Setenv("PORT", "")
+    if got := httpAddr(); got != defaultHTTPAddr {
+        t.Fatalf("httpAddr() = %q, want %q", got, defaultHTTPAddr)
+    }
+    t.Setenv("PORT", "9000")
+    if got := httpAddr(); got != ":9000" {
+        t.Fatalf("httpAddr() with PORT = %q, want :9000", got)
+    }
+    t.Setenv("MCP_HTTP_ADDR", "0.0.0.0:7000")
+    if got := httpAddr(); got != "0.0.0.0:7000" {
+        t.Fatalf("httpAddr() with MCP_HTTP_ADDR = %q, want 0.0.0.0:7000", got)
+    }
+}
+`
+	if bin.shard != nil {
+		src = strings.Replace(src, `import "testing"`, fmt.Sprintf(`import (
+    "testing"
+
+    "%s/internal/spec"
+)`, data.ModuleName), 1) + fmt.Sprintf(`
+func TestEndpointsWithTag(t *testing.T) {
+    t.Setenv(spec.ModelPathEnv, "")
+    sm, err := spec.Load()
+    if err != nil {
+        t.Fatalf("load model: %%v", err)
+    }
+    const tag, want = %q, %d
+    eps := endpointsWithTag(sm.Endpoints, tag)
+    if len(eps) != want {
+        t.Fatalf("endpointsWithTag(%%q) kept %%d endpoints, want %%d", tag, len(eps), want)
+    }
+    for _, ep := range eps {
+        if (len(ep.Tags) == 0 && tag != "") || (len(ep.Tags) > 0 && ep.Tags[0] != tag) {
+            t.Fatalf("endpointsWithTag(%%q) kept %%s tagged %%v", tag, ep.ID, ep.Tags)
+        }
+    }
+}
+`, bin.shard.Tag, bin.shard.Endpoints)
+	}
+	return normalize(src)
 }
 
 func renderEditorConfig() string {
@@ -120,25 +676,223 @@ trim_trailing_whitespace = true
 `)
 }
 
-func renderMakefileGo() string {
-	return normalize(`# Simple Makefile for Go MCP tool
+// renderGolangciConfig returns a golangci-lint (v2 config format) baseline.
+// funlen and gocognit stay off because the generated handlers are long by design.
+func renderGolangciConfig() string {
+	return normalize(`# golangci-lint configuration generated by swagger2mcp — customize as needed.
+version: "2"
 
-.PHONY: help build test fmt tidy
+linters:
+  default: none
+  enable:
+    - errcheck
+    - govet
+    - ineffassign
+    - staticcheck
+  disable:
+    - funlen
+    - gocognit
 
-help:
-	@echo "Targets: build test fmt tidy"
+formatters:
+  enable:
+    - gofmt
+    - goimports
+`)
+}
+
+// renderMakefileGo returns the project Makefile. build writes bin/<tool>, with
+// .exe on Windows; build-all cross-compiles every GOOS/GOARCH pair in platforms
+// to dist/<tool>_<os>_<arch>[.exe] and records SHA-256 sums in dist/checksums.txt.
+// With SplitByTag the same holds for every binary, and build-<binary> builds one.
+func renderMakefileGo(data templateData, platforms []string) string {
+	targets, test, lint := "build build-all", "\ntest:\n\tgo test ./...\n", ""
+	if data.skip.Tests {
+		test = ""
+	} else {
+		targets += " test"
+	}
+	targets += " fmt"
+	if data.lint {
+		targets += " lint"
+		lint = "\nlint:\n\tgolangci-lint run ./...\n"
+	}
+	targets += " tidy clean"
+	image, docker := "", ""
+	if data.docker {
+		targets += " docker-build docker-run"
+		image = "IMAGE ?= $(TOOL)\n"
+		docker = dockerMakeTargets
+	}
+	pkg, build := "PKG := ./cmd/$(TOOL)", "build:\n\tgo build -o bin/$(TOOL)$(EXE) $(PKG)\n"
+	buildAll := `	@set -e; for platform in $(PLATFORMS); do \
+		os=$${platform%/*}; arch=$${platform#*/}; ext=""; \
+		if [ "$$os" = "windows" ]; then ext=".exe"; fi; \
+		out="dist/$(TOOL)_$${os}_$${arch}$$ext"; \
+		echo "building $$out"; \
+		CGO_ENABLED=0 GOOS=$$os GOARCH=$$arch go build -o "$$out" $(PKG); \
+	done
+	cd dist && (sha256sum $(TOOL)_* 2>/dev/null || shasum -a 256 $(TOOL)_*) > checksums.txt`
+	if len(data.tagBinaries) > 0 {
+		var names, builds []string
+		for _, bin := range data.tagBinaries {
+			names = append(names, bin.name)
+			builds = append(builds, fmt.Sprintf("build-%s:\n\tgo build -o bin/%s$(EXE) ./cmd/%s\n", bin.name, bin.name, bin.name))
+		}
+		targets = strings.Replace(targets, "build ", "build build-"+strings.Join(names, " build-")+" ", 1)
+		pkg = "BINARIES := " + strings.Join(names, " ")
+		build = "build: $(BINARIES:%=build-%)\n\n" + strings.Join(builds, "\n")
+		buildAll = `	@set -e; for bin in $(BINARIES); do for platform in $(PLATFORMS); do \
+		os=$${platform%/*}; arch=$${platform#*/}; ext=""; \
+		if [ "$$os" = "windows" ]; then ext=".exe"; fi; \
+		out="dist/$${bin}_$${os}_$${arch}$$ext"; \
+		echo "building $$out"; \
+		CGO_ENABLED=0 GOOS=$$os GOARCH=$$arch go build -o "$$out" ./cmd/$$bin; \
+	done; done
+	cd dist && (sha256sum $(TOOL)-*_* 2>/dev/null || shasum -a 256 $(TOOL)-*_*) > checksums.txt`
+	}
+	return data.render(strings.NewReplacer("{{PLATFORMS}}", strings.Join(platforms, " "), "{{TARGETS}}", targets, "{{PKG}}", pkg, "{{BUILD}}", build, "{{BUILD_ALL}}", buildAll, "{{TEST}}", test, "{{LINT}}", lint, "{{IMAGE}}", image, "{{DOCKER}}", docker).Replace(`# Makefile for the {{TOOL_NAME}} Go MCP tool
+
+TOOL := {{TOOL_NAME}}
+{{PKG}}
+PLATFORMS ?= {{PLATFORMS}}
+{{IMAGE}}
+ifeq ($(OS),Windows_NT)
+EXE := .exe
+else
+EXE :=
+endif
 
-build:
-	go build ./...
+.PHONY: help {{TARGETS}}
 
-test:
-	go test ./...
+help:
+	@echo "Targets: {{TARGETS}}"
 
+{{BUILD}}
+# Cross-compile for $(PLATFORMS); needs a POSIX shell (on Windows, Git Bash).
+build-all:
+	@mkdir -p dist
+{{BUILD_ALL}}
+{{TEST}}
 fmt:
 	go fmt ./...
-
+{{LINT}}
 tidy:
 	go mod tidy
+
+clean:
+	rm -rf bin dist
+{{DOCKER}}`))
+}
+
+// dockerMakeTargets build the image of the Dockerfile and run it with stdin
+// attached, which the stdio transport needs.
+const dockerMakeTargets = `
+docker-build:
+	docker build -t $(IMAGE) .
+
+docker-run:
+	docker run -i --rm $(IMAGE)
+`
+
+// renderDockerfileGo returns a multi-stage Dockerfile: a static build of
+// ./cmd/<tool> copied into a distroless image whose entrypoint serves MCP
+// over stdio. Without pinned dependencies go.sum is written by go mod tidy.
+func renderDockerfileGo(data templateData) string {
+	build := "CGO_ENABLED=0 go build -trimpath -ldflags=\"-s -w\" -o /out/{{TOOL_NAME}} ./cmd/{{TOOL_NAME}}"
+	if !data.pinned {
+		build = "go mod tidy && " + build
+	}
+	return data.render(strings.NewReplacer("{{GO_VERSION}}", data.goVersion, "{{BUILD}}", build).Replace(`# Dockerfile for the {{TOOL_NAME}} Go MCP tool
+FROM golang:{{GO_VERSION}} AS build
+WORKDIR /src
+COPY go.mod go.sum* ./
+RUN go mod download
+COPY . .
+RUN {{BUILD}}
+
+FROM gcr.io/distroless/static-debian12:nonroot
+COPY --from=build /out/{{TOOL_NAME}} /usr/local/bin/{{TOOL_NAME}}
+# The server speaks MCP over stdio; keep stdin open: docker run -i --rm <image>
+ENTRYPOINT ["/usr/local/bin/{{TOOL_NAME}}"]
+`))
+}
+
+// ciTestClause completes the README's description of the CI workflow with
+// the jobs beyond build and vet.
+func ciTestClause(data templateData) string {
+	switch {
+	case !data.skip.Tests && data.lint:
+		return ", runs the tests and lints it with golangci-lint"
+	case !data.skip.Tests:
+		return " and runs the tests"
+	case data.lint:
+		return " and lints it with golangci-lint"
+	}
+	return ""
+}
+
+// renderCIWorkflowGo returns the GitHub Actions workflow of GenerateCI: a
+// build job running go build, go vet and, unless tests are skipped, go
+// test, and with the lint config a lint job running golangci-lint. Both use
+// the Go version of go.mod; without pinned dependencies go.sum is written by
+// go mod tidy first.
+func renderCIWorkflowGo(data templateData) string {
+	setup := `    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+`
+	if !data.pinned {
+		setup += `      - name: Tidy
+        run: go mod tidy
+`
+	}
+	build := `      - name: Build
+        run: go build ./...
+      - name: Vet
+        run: go vet ./...
+`
+	if !data.skip.Tests {
+		build += `      - name: Test
+        run: go test ./...
+`
+	}
+	lint := ""
+	if data.lint {
+		lint = `
+  lint:
+    runs-on: ubuntu-latest
+` + setup + `      - name: Lint (golangci-lint)
+        uses: golangci/golangci-lint-action@v8
+        with:
+          version: v2.1
+`
+	}
+	return data.render(`# CI for the {{TOOL_NAME}} Go MCP tool, generated by swagger2mcp.
+name: CI
+
+on:
+  push:
+  pull_request:
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+` + setup + build + lint)
+}
+
+// renderDockerignoreGo keeps build output, editor settings and tests out of
+// the build context.
+func renderDockerignoreGo() string {
+	return normalize(`.git
+.vscode
+bin
+dist
+tests
+testdata
+Dockerfile
+.dockerignore
 `)
 }
 
@@ -146,6 +900,8 @@ tidy:
 func renderSpecModelGo() string {
 	return normalize(`package spec
 
+import "sort"
+
 // Internal Model (IM) definitions used by the generated MCP tool.
 
 type HttpMethod string
@@ -167,17 +923,22 @@ type ServiceModel struct {
     Description string
     Servers     []Server
     Tags        []string
-    Endpoints   []EndpointModel
-    Schemas     map[string]Schema // by name/ref
+    // TagDescriptions maps each of Tags to its description ("" when none).
+    TagDescriptions map[string]string
+    Endpoints       []EndpointModel
+    Schemas         map[string]Schema // by name/ref
+    Extensions      map[string]any    // x-* vendor extensions
 }
 
 type Server struct {
     URL         string
     Description string
+    Development bool // localhost, private-network, .local, or x-internal
 }
 
 type EndpointModel struct {
     ID          string // method+path
+    OperationID string
     Method      HttpMethod
     Path        string
     Summary     string
@@ -186,13 +947,27 @@ type EndpointModel struct {
     Parameters  []ParameterModel
     RequestBody *RequestBodyModel
     Responses   []ResponseModel
+    Deprecated  bool
+    Featured    bool
+    Pagination  *PaginationHint
+    Extensions  map[string]any // x-* vendor extensions
+    Security    [][]string     // alternative security requirements, by scheme name; [] allows anonymous access
+}
+
+type PaginationHint struct {
+    Style  string   // cursor|offset|unknown
+    Params []string // pagination query parameters
 }
 
 type ParameterModel struct {
-    Name     string
-    In       string // path|query|header|cookie
-    Required bool
-    Schema   *SchemaOrRef
+    Name        string
+    In          string // path|query|header|cookie
+    Required    bool
+    Description string
+    Schema      *SchemaOrRef // a referenced schema keeps its Ref and is inlined in Schema
+    Example     any
+    Style       string // form|simple|spaceDelimited|pipeDelimited|...
+    Explode     *bool
 }
 
 type RequestBodyModel struct {
@@ -210,6 +985,7 @@ type Media struct {
     Mime   string
     Schema *SchemaOrRef
     Example any
+    IsBinary bool
 }
 
 type Schema struct {
@@ -225,6 +1001,15 @@ type Schema struct {
     Enum        []any
     Format      string
     Example     any
+    Extensions  map[string]any // x-* vendor extensions
+    // Array and numeric constraints. Exclusive* qualify Minimum/Maximum.
+    MinItems         *int
+    MaxItems         *int
+    UniqueItems      bool
+    Minimum          *float64
+    Maximum          *float64
+    ExclusiveMinimum bool
+    ExclusiveMaximum bool
 }
 
 type SchemaRef struct{ Ref string }
@@ -233,846 +1018,3393 @@ type SchemaOrRef struct {
     Schema *Schema
     Ref    *SchemaRef
 }
+
+// CompareNames orders the names the tools list: schemas, properties, tags,
+// paths and headers. Names compare case-insensitively first, folding only
+// the ASCII letters, then bytewise, so "apple" < "Pet" < "pet" < "Zebra";
+// non-ASCII characters sort by code point after ASCII. It is the order of
+// the generator's model.
+func CompareNames(a, b string) int {
+    n := len(a)
+    if len(b) < n {
+        n = len(b)
+    }
+    for i := 0; i < n; i++ {
+        if ca, cb := foldASCII(a[i]), foldASCII(b[i]); ca != cb {
+            if ca < cb {
+                return -1
+            }
+            return 1
+        }
+    }
+    switch {
+    case len(a) < len(b):
+        return -1
+    case len(a) > len(b):
+        return 1
+    case a < b:
+        return -1
+    case a > b:
+        return 1
+    }
+    return 0
+}
+
+// SortNames sorts names in place by CompareNames.
+func SortNames(names []string) {
+    sort.Slice(names, func(i, j int) bool { return CompareNames(names[i], names[j]) < 0 })
+}
+
+func foldASCII(c byte) byte {
+    if c >= 'A' && c <= 'Z' {
+        return c + ('a' - 'A')
+    }
+    return c
+}
 `)
 }
 
-func renderSpecLoaderGo() string {
-	return normalize(`package spec
+// renderSpecLoaderGo returns loader.go of the spec package, which reads the
+// embedded model.json or, with ShardModelByTag, the shards under model/.
+func renderSpecLoaderGo(data templateData) string {
+	src := specLoaderGo
+	if data.shardModel {
+		src = withShardedModelGo(src)
+	}
+	return normalize(src)
+}
+
+// renderSpecLoaderTestGo returns loader_test.go of the spec package, which
+// checks that sm loads from the binary alone, away from any model file.
+func renderSpecLoaderTestGo(sm *genspec.ServiceModel) string {
+	return normalize(fmt.Sprintf(`package spec
 
 import (
-    "embed"
-    "encoding/json"
-    "errors"
+    "os"
+    "reflect"
+    "testing"
 )
 
-//go:embed model.json
-var rawModel []byte
-// reference embed so the import is not considered unused by older toolchains
-var _ embed.FS
+// TestLoadEmbedded runs from a directory without model data and with
+// MCP_MODEL_PATH unset, so Load can only succeed from the embedded copy.
+func TestLoadEmbedded(t *testing.T) {
+    t.Setenv(ModelPathEnv, "")
+    wd, err := os.Getwd()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Chdir(t.TempDir()); err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { _ = os.Chdir(wd) })
 
-// Load returns the embedded ServiceModel.
-func Load() (*ServiceModel, error) {
-    if len(rawModel) == 0 {
-        return nil, errors.New("empty embedded model")
+    sm, err := Load()
+    if err != nil {
+        t.Fatalf("load embedded model: %%v", err)
     }
-    var sm ServiceModel
-    if err := json.Unmarshal(rawModel, &sm); err != nil {
-        return nil, err
+    const wantTitle, wantEndpoints = %q, %d
+    if sm.Title != wantTitle || len(sm.Endpoints) != wantEndpoints {
+        t.Fatalf("embedded model = %%q with %%d endpoints, want %%q with %%d", sm.Title, len(sm.Endpoints), wantTitle, wantEndpoints)
     }
-    return &sm, nil
 }
-`)
+
+// TestSortNames pins the order of the tools' listings to the generator's.
+func TestSortNames(t *testing.T) {
+    names := []string{"Zebra", "apple", "pet", "Pet", "_id", "éclair", "Eclair", "b", "B2", "a10", "a2", "Ünits", "units", "zoo", "😀", "文件", "ﬁ"}
+    SortNames(names)
+    want := []string{"_id", "a10", "a2", "apple", "b", "B2", "Eclair", "Pet", "pet", "units", "Zebra", "zoo", "Ünits", "éclair", "文件", "ﬁ", "😀"}
+    if !reflect.DeepEqual(names, want) {
+        t.Fatalf("SortNames:\n got %%q\nwant %%q", names, want)
+    }
+}
+`, sm.Title, len(sm.Endpoints)))
 }
 
-func renderMCPBootstrapGo(data templateData) string {
-	return data.render(`package mcp
+// withShardedModelGo embeds the model/ shards instead of model.json: Load
+// and LoadEmbedded still return the whole model, while LoadIndex, Shards,
+// LoadShard and LoadTag read just the shards a caller asks for.
+func withShardedModelGo(src string) string {
+	return strings.NewReplacer(
+		`    "strings"
+)`, `    "strings"
+    "sync"
+)`,
+		specLoaderEmbedGo, shardedLoaderEmbedGo,
+	).Replace(src)
+}
 
-import (
-    "context"
-    "fmt"
+const specLoaderEmbedGo = `// modelFS holds model.json, compiled into the binary so it runs without the file.
+//go:embed model.json
+var modelFS embed.FS
 
-    "github.com/mark3labs/mcp-go/mcp"
-    goserver "github.com/mark3labs/mcp-go/server"
+// LoadEmbedded returns the ServiceModel compiled into the binary.
+func LoadEmbedded() (*ServiceModel, error) {
+    raw, err := modelFS.ReadFile("model.json")
+    if err != nil {
+        return nil, fmt.Errorf("read embedded model: %w", err)
+    }
+    return decode(raw)
+}
 
-    methods "{{MODULE}}/internal/mcp/methods"
-    "{{MODULE}}/internal/spec"
-)
+// hashEmbedded writes the embedded model data to h for ModelHash.
+func hashEmbedded(h io.Writer) error {
+    raw, err := modelFS.ReadFile("model.json")
+    if err != nil {
+        return fmt.Errorf("read embedded model: %w", err)
+    }
+    _, err = h.Write(raw)
+    return err
+}
+`
 
-// NewMCPServer creates and configures an MCP server with tools backed by the ServiceModel.
-func NewMCPServer(sm *spec.ServiceModel) *goserver.MCPServer {
-    name := sm.Title
-    if name == "" { name = "mcp-tool" }
-    srv := goserver.NewMCPServer(name, sm.Version,
-        goserver.WithToolCapabilities(true),
-        goserver.WithInstructions("This server exposes tools to query your API documentation."),
-        goserver.WithRecovery(),
-    )
+const shardedLoaderEmbedGo = `// modelFS holds the model split by tag: model/_index.json has everything but
+// the endpoints, and model/<shard>.json the endpoints whose first tag names
+// the shard (model/_untagged.json those without tags).
+//go:embed model/*.json
+var modelFS embed.FS
 
-    // listEndpoints tool (no args)
-    srv.AddTool(mcp.NewTool("listEndpoints",
-        mcp.WithDescription("Show API overview and routing summary"),
-        mcp.WithInputSchema[struct{}](),
-    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-        overview := methods.FormatEndpointsOverview(sm)
-        return &mcp.CallToolResult{
-            Content: []mcp.Content{mcp.TextContent{Type: "text", Text: overview}},
-        }, nil
-    })
+// Shard describes one endpoint shard of the embedded model.
+type Shard struct {
+    Name      string // file name under model/ without ".json"
+    Tag       string // "" for the untagged endpoints
+    Endpoints int
+}
 
-    // searchEndpoints tool
-    type SearchArgs struct {
-        Keyword     string
-        Tag         string
-        Method      string
-        PathPattern string
-    }
-    srv.AddTool(mcp.NewTool("searchEndpoints",
-        mcp.WithDescription("Search endpoints by keyword, tag, method, or path regex"),
-        mcp.WithInputSchema[SearchArgs](),
-    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-        var args SearchArgs
-        _ = req.BindArguments(&args)
-        out := methods.SearchEndpoints(sm, methods.SearchQuery{Keyword: args.Keyword, Tag: args.Tag, Method: args.Method, PathPattern: args.PathPattern})
-        return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("%d matches", len(out))}}, StructuredContent: out}, nil
-    })
+// modelIndex is the content of model/_index.json. EndpointShards lists the
+// shard of every endpoint in model order.
+type modelIndex struct {
+    ServiceModel
+    Shards         []Shard
+    EndpointShards []struct{ ID, Shard string }
+}
 
-    // getEndpointDetails tool
-    type GetEPArgs struct {
-        ID     string
-        Method string
-        Path   string
+var (
+    indexOnce sync.Once
+    index     *modelIndex
+    indexErr  error
+
+    shardMu sync.Mutex
+    shards  = map[string][]EndpointModel{}
+)
+
+func readIndex() (*modelIndex, error) {
+    raw, err := modelFS.ReadFile("model/_index.json")
+    if err != nil {
+        return nil, fmt.Errorf("read embedded model index: %w", err)
     }
-    srv.AddTool(mcp.NewTool("getEndpointDetails",
-        mcp.WithDescription("Get endpoint details by id or method+path"),
-        mcp.WithInputSchema[GetEPArgs](),
+    var idx modelIndex
+    if err := json.Unmarshal(raw, &idx); err != nil {
+        return nil, fmt.Errorf("decode model index: %w", err)
+    }
+    return &idx, nil
+}
+
+func readShard(name string) ([]EndpointModel, error) {
+    raw, err := modelFS.ReadFile("model/" + name + ".json")
+    if err != nil {
+        return nil, fmt.Errorf("read model shard %s: %w", name, err)
+    }
+    var eps []EndpointModel
+    if err := json.Unmarshal(raw, &eps); err != nil {
+        return nil, fmt.Errorf("decode model shard %s: %w", name, err)
+    }
+    return eps, nil
+}
+
+func cachedIndex() (*modelIndex, error) {
+    indexOnce.Do(func() { index, indexErr = readIndex() })
+    return index, indexErr
+}
+
+// LoadIndex returns the embedded ServiceModel without its endpoints, reading
+// no shard.
+func LoadIndex() (*ServiceModel, error) {
+    idx, err := readIndex()
+    if err != nil {
+        return nil, err
+    }
+    return &idx.ServiceModel, nil
+}
+
+// Shards lists the embedded shards in the order their first endpoint appears.
+func Shards() ([]Shard, error) {
+    idx, err := cachedIndex()
+    if err != nil {
+        return nil, err
+    }
+    return append([]Shard(nil), idx.Shards...), nil
+}
+
+// LoadShard returns the endpoints of the named shard, reading its file on
+// the first call only. Callers must not modify the result.
+func LoadShard(name string) ([]EndpointModel, error) {
+    shardMu.Lock()
+    defer shardMu.Unlock()
+    if eps, ok := shards[name]; ok {
+        return eps, nil
+    }
+    eps, err := readShard(name)
+    if err != nil {
+        return nil, err
+    }
+    shards[name] = eps
+    return eps, nil
+}
+
+// LoadTag returns the endpoints whose first tag is tag, or those without
+// tags when tag is "", reading only their shard.
+func LoadTag(tag string) ([]EndpointModel, error) {
+    idx, err := cachedIndex()
+    if err != nil {
+        return nil, err
+    }
+    for _, s := range idx.Shards {
+        if s.Tag == tag {
+            return LoadShard(s.Name)
+        }
+    }
+    return nil, nil
+}
+
+// LoadEmbedded returns the ServiceModel compiled into the binary, assembled
+// from the index and every shard in model order.
+func LoadEmbedded() (*ServiceModel, error) {
+    idx, err := readIndex()
+    if err != nil {
+        return nil, err
+    }
+    byShard := map[string][]EndpointModel{}
+    for _, s := range idx.Shards {
+        eps, err := readShard(s.Name)
+        if err != nil {
+            return nil, err
+        }
+        byShard[s.Name] = eps
+    }
+    sm := idx.ServiceModel
+    sm.Endpoints = make([]EndpointModel, 0, len(idx.EndpointShards))
+    for _, e := range idx.EndpointShards {
+        eps := byShard[e.Shard]
+        if len(eps) == 0 {
+            return nil, fmt.Errorf("model shard %s is missing endpoint %s", e.Shard, e.ID)
+        }
+        sm.Endpoints = append(sm.Endpoints, eps[0])
+        byShard[e.Shard] = eps[1:]
+    }
+    return &sm, nil
+}
+
+// hashEmbedded writes the embedded model files to h for ModelHash, in name
+// order.
+func hashEmbedded(h io.Writer) error {
+    entries, err := modelFS.ReadDir("model")
+    if err != nil {
+        return fmt.Errorf("read embedded model: %w", err)
+    }
+    for _, e := range entries {
+        raw, err := modelFS.ReadFile("model/" + e.Name())
+        if err != nil {
+            return fmt.Errorf("read embedded model: %w", err)
+        }
+        if _, err := h.Write(raw); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+`
+
+const specLoaderGo = `package spec
+
+import (
+    "crypto/sha256"
+    "embed"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+// ModelPathEnv names an environment variable that, when set, makes Load read
+// the model from that file instead of the embedded copy.
+const ModelPathEnv = "MCP_MODEL_PATH"
+
+` + specLoaderEmbedGo + `
+// LoadFromFile reads a ServiceModel from path, overriding the embedded one at runtime.
+func LoadFromFile(path string) (*ServiceModel, error) {
+    raw, err := os.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("read model: %w", err)
+    }
+    return decode(raw)
+}
+
+// Load returns the ServiceModel from the file named by MCP_MODEL_PATH when it
+// is set, and the embedded one otherwise.
+func Load() (*ServiceModel, error) {
+    if path := os.Getenv(ModelPathEnv); path != "" {
+        return LoadFromFile(path)
+    }
+    return LoadEmbedded()
+}
+
+// ModelHash returns the hex SHA-256 of the model data Load reads: the file
+// named by MCP_MODEL_PATH when it is set, and the embedded data otherwise.
+func ModelHash() (string, error) {
+    h := sha256.New()
+    if path := os.Getenv(ModelPathEnv); path != "" {
+        raw, err := os.ReadFile(path)
+        if err != nil {
+            return "", fmt.Errorf("read model: %w", err)
+        }
+        h.Write(raw)
+    } else if err := hashEmbedded(h); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// BaseURLEnv names an environment variable that, when set, replaces the
+// spec's server selection so one build can target staging and production.
+const BaseURLEnv = "API_BASE_URL"
+
+// ApplyBaseURLOverride puts the URL from API_BASE_URL, when set, first in
+// sm.Servers (dropping a duplicate entry), so it wins over the spec's servers.
+func ApplyBaseURLOverride(sm *ServiceModel) {
+    url := strings.TrimSpace(os.Getenv(BaseURLEnv))
+    if sm == nil || url == "" {
+        return
+    }
+    servers := []Server{{URL: url, Description: "from " + BaseURLEnv}}
+    for _, s := range sm.Servers {
+        if s.URL != url {
+            servers = append(servers, s)
+        }
+    }
+    sm.Servers = servers
+}
+
+// BaseURL returns the URL requests should target: API_BASE_URL when set,
+// otherwise the first server's URL, or "" when the spec lists none.
+func BaseURL(sm *ServiceModel) string {
+    if url := strings.TrimSpace(os.Getenv(BaseURLEnv)); url != "" {
+        return url
+    }
+    if sm == nil || len(sm.Servers) == 0 {
+        return ""
+    }
+    return sm.Servers[0].URL
+}
+
+func decode(raw []byte) (*ServiceModel, error) {
+    if len(raw) == 0 {
+        return nil, errors.New("empty model")
+    }
+    var sm ServiceModel
+    if err := json.Unmarshal(raw, &sm); err != nil {
+        return nil, err
+    }
+    return &sm, nil
+}
+`
+
+func renderMCPBootstrapGo(data templateData) string {
+	src := mcpBootstrapGo
+	if data.invoke {
+		src = withCallEndpointTool(src)
+	}
+	if data.interfaces {
+		src = withHandlerInterface(src, data.invoke)
+	}
+	if data.otel {
+		src = withToolTracing(src)
+	}
+	return data.render(src)
+}
+
+// mcpDescribeGo renders internal/mcp/describe.go, what --describe prints:
+// the tools registered on the server and a summary of the model.
+const mcpDescribeGo = `package mcp
+
+import (
+    "encoding/json"
+    "io"
+    "sort"
+
+    goserver "github.com/mark3labs/mcp-go/server"
+
+    "{{MODULE}}/internal/spec"
+)
+
+// Tool names one registered tool.
+type Tool struct {
+    Name        string ` + "`json:\"name\"`" + `
+    Description string ` + "`json:\"description\"`" + `
+}
+
+// Description summarizes a server: the API it documents, the size of its
+// model and the tools it registers, sorted by name. ModelHash is the hex
+// SHA-256 of the model data (see spec.ModelHash).
+type Description struct {
+    Title     string ` + "`json:\"title\"`" + `
+    Version   string ` + "`json:\"version\"`" + `
+    Endpoints int    ` + "`json:\"endpoints\"`" + `
+    Schemas   int    ` + "`json:\"schemas\"`" + `
+    ModelHash string ` + "`json:\"modelHash\"`" + `
+    Tools     []Tool ` + "`json:\"tools\"`" + `
+}
+
+// Describe returns the Description of srv serving sm.
+func Describe(srv *goserver.MCPServer, sm *spec.ServiceModel) (Description, error) {
+    hash, err := spec.ModelHash()
+    if err != nil {
+        return Description{}, err
+    }
+    d := Description{Title: sm.Title, Version: sm.Version, Endpoints: len(sm.Endpoints), Schemas: len(sm.Schemas), ModelHash: hash, Tools: []Tool{}}
+    for name, t := range srv.ListTools() {
+        d.Tools = append(d.Tools, Tool{Name: name, Description: t.Tool.Description})
+    }
+    sort.Slice(d.Tools, func(i, j int) bool { return spec.CompareNames(d.Tools[i].Name, d.Tools[j].Name) < 0 })
+    return d, nil
+}
+
+// WriteDescription writes the Description of srv serving sm to w as indented
+// JSON.
+func WriteDescription(w io.Writer, srv *goserver.MCPServer, sm *spec.ServiceModel) error {
+    d, err := Describe(srv, sm)
+    if err != nil {
+        return err
+    }
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(d)
+}
+`
+
+// describeTestsGo renders tests/describe_test.go, which runs the --describe
+// path in-process and checks the JSON it prints.
+const describeTestsGo = `package tests
+
+import (
+    "bytes"
+    "encoding/json"
+    "regexp"
+    "testing"
+
+    "{{MODULE}}/internal/mcp"
+    "{{MODULE}}/internal/spec"
+)
+
+func Test_Describe(t *testing.T) {
+    t.Setenv(spec.ModelPathEnv, "")
+    sm, err := spec.Load()
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    var buf bytes.Buffer
+    if err := mcp.WriteDescription(&buf, mcp.NewMCPServer(sm), sm); err != nil {
+        t.Fatalf("describe: %v", err)
+    }
+    var got struct {
+        Title     string ` + "`json:\"title\"`" + `
+        Version   string ` + "`json:\"version\"`" + `
+        Endpoints int    ` + "`json:\"endpoints\"`" + `
+        Schemas   int    ` + "`json:\"schemas\"`" + `
+        ModelHash string ` + "`json:\"modelHash\"`" + `
+        Tools     []struct {
+            Name        string ` + "`json:\"name\"`" + `
+            Description string ` + "`json:\"description\"`" + `
+        } ` + "`json:\"tools\"`" + `
+    }
+    if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+        t.Fatalf("describe output is not JSON: %v\n%s", err, buf.String())
+    }
+    if got.Title != sm.Title || got.Version != sm.Version || got.Endpoints != len(sm.Endpoints) || got.Schemas != len(sm.Schemas) {
+        t.Fatalf("describe summary = %+v, want the loaded model's", got)
+    }
+    if !regexp.MustCompile("^[0-9a-f]{64}$").MatchString(got.ModelHash) {
+        t.Fatalf("modelHash = %q, want a hex SHA-256", got.ModelHash)
+    }
+    names := map[string]bool{}
+    for _, tool := range got.Tools {
+        if tool.Description == "" {
+            t.Errorf("tool %s has no description", tool.Name)
+        }
+        names[tool.Name] = true
+    }
+    for _, want := range []string{"listEndpoints", "searchEndpoints", "getEndpointDetails", "listSchemas", "getSchemaDetails", "explainParameter"} {
+        if !names[want] {
+            t.Errorf("describe tools lack %s: %+v", want, got.Tools)
+        }
+    }
+}
+`
+
+// withCallEndpointTool registers the callEndpoint tool after the
+// documentation tools.
+func withCallEndpointTool(src string) string {
+	return strings.NewReplacer(
+		`"This server exposes tools to query your API documentation."`, `"This server exposes tools to query your API documentation and to call the API."`,
+		`
+    return srv
+}
+`, mcpCallEndpointToolGo+`
+    return srv
+}
+`,
+	).Replace(src)
+}
+
+// mcpCallEndpointToolGo registers callEndpoint in NewMCPServer.
+const mcpCallEndpointToolGo = `
+    // callEndpoint tool: sends a request to the upstream API
+    srv.AddTool(mcp.NewTool("callEndpoint",
+        mcp.WithDescription("Call an API endpoint: pass its id, parameters by name (path, query, header or cookie) and an optional JSON body; returns the status, headers and body (truncated)"),
+        mcp.WithInputSchema[methods.CallArgs](),
     ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-        var a GetEPArgs
-        _ = req.BindArguments(&a)
-        var ep *spec.EndpointModel
+        var a methods.CallArgs
+        if err := req.BindArguments(&a); err != nil { return nil, err }
+        res, err := methods.CallEndpoint(ctx, sm, a, methods.CallOptions{})
+        if err != nil { return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}}}, nil }
+        return &mcp.CallToolResult{StructuredContent: res, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: methods.FormatCallResult(res)}}}, nil
+    })
+`
+
+// withHandlerInterface rewrites the bootstrap so each tool calls a Handler
+// method instead of the methods package, and appends the Handler interface
+// with its default implementation. invoke adds the CallEndpoint method.
+func withHandlerInterface(src string, invoke bool) string {
+	handler := mcpHandlerGo
+	if invoke {
+		handler = strings.Replace(handler, `
+    ExplainParameter(endpointID, name string) (*methods.ParameterExplanation, string, error)
+`, `
+    ExplainParameter(endpointID, name string) (*methods.ParameterExplanation, string, error)
+    // CallEndpoint sends the request described by args to the upstream API.
+    CallEndpoint(ctx context.Context, args methods.CallArgs) (*methods.CallResult, error)
+`, 1) + `
+func (m modelHandler) CallEndpoint(ctx context.Context, args methods.CallArgs) (*methods.CallResult, error) {
+    return methods.CallEndpoint(ctx, m.sm, args, methods.CallOptions{})
+}
+`
+	}
+	return strings.NewReplacer(
+		`// NewMCPServer creates and configures an MCP server with tools backed by the ServiceModel.
+func NewMCPServer(sm *spec.ServiceModel) *goserver.MCPServer {
+`, `// NewMCPServer creates and configures an MCP server with tools backed by the ServiceModel.
+func NewMCPServer(sm *spec.ServiceModel) *goserver.MCPServer {
+    return NewMCPServerWithHandler(sm, NewHandler(sm))
+}
+
+// NewMCPServerWithHandler is NewMCPServer with every tool answered by h, so
+// tests can substitute a stub or an alternative implementation.
+func NewMCPServerWithHandler(sm *spec.ServiceModel, h Handler) *goserver.MCPServer {
+`,
+		`overview := methods.FormatEndpointsOverview(sm)`, `overview := h.ListEndpoints()`,
+		`out := methods.SearchEndpoints(sm, methods.SearchQuery{`, `out := h.SearchEndpoints(methods.SearchQuery{`,
+		`        var ep *spec.EndpointModel
         var ok bool
         if a.ID != "" {
             ep, ok = methods.GetEndpointDetails(sm, a.ID)
         } else {
             ep, ok = methods.GetEndpointDetails(sm, a.Method, a.Path)
         }
-        if !ok || ep == nil {
-            return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "endpoint not found"}}}, nil
-        }
-        // Format detailed text output
+`, `        ep, text, ok := h.GetEndpointDetails(a.ID, a.Method, a.Path)
+`,
+		`        // Format detailed text output
         text := methods.FormatEndpointDetails(ep, sm)
-        return &mcp.CallToolResult{StructuredContent: ep, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}}}, nil
-    })
+`, ``,
+		`out := methods.ListSchemas(sm)`, `out := h.ListSchemas()`,
+		`sc, ok := methods.GetSchemaDetails(sm, a.Name)`, `sc, text, ok := h.GetSchemaDetails(a.Name)`,
+		`        // Format detailed schema output
+        text := methods.FormatSchemaDetails(sc, sm)
+`, ``,
+		`px, err := methods.ExplainParameter(sm, a.EndpointID, a.ParameterName)`, `px, text, err := h.ExplainParameter(a.EndpointID, a.ParameterName)`,
+		`        // Format the explanation
+        text := methods.FormatParameterExplanation(px, sm)
+`, ``,
+		`res, err := methods.CallEndpoint(ctx, sm, a, methods.CallOptions{})`, `res, err := h.CallEndpoint(ctx, a)`,
+	).Replace(src) + handler
+}
+
+// mcpHandlerGo is appended to server.go when interfaces are generated.
+const mcpHandlerGo = `
+// Handler answers the MCP tools, one method per tool.
+type Handler interface {
+    // ListEndpoints returns the API overview shown by listEndpoints.
+    ListEndpoints() string
+    // SearchEndpoints returns the endpoints matching q.
+    SearchEndpoints(q methods.SearchQuery) []methods.EndpointSearchResult
+    // GetEndpointDetails looks an endpoint up by id, or by method and path
+    // when id is empty, and returns it with its formatted details.
+    GetEndpointDetails(id, method, path string) (*spec.EndpointModel, string, bool)
+    // ListSchemas returns the schema summaries.
+    ListSchemas() []methods.SchemaSummary
+    // GetSchemaDetails returns the named schema with its formatted details.
+    GetSchemaDetails(name string) (*spec.Schema, string, bool)
+    // ExplainParameter explains one parameter of an endpoint, with its
+    // formatted text; the error names the closest matches when either is unknown.
+    ExplainParameter(endpointID, name string) (*methods.ParameterExplanation, string, error)
+}
+
+// NewHandler returns the Handler backed by the generated methods package.
+func NewHandler(sm *spec.ServiceModel) Handler {
+    return modelHandler{sm: sm}
+}
+
+type modelHandler struct{ sm *spec.ServiceModel }
+
+func (m modelHandler) ListEndpoints() string {
+    return methods.FormatEndpointsOverview(m.sm)
+}
+
+func (m modelHandler) SearchEndpoints(q methods.SearchQuery) []methods.EndpointSearchResult {
+    return methods.SearchEndpoints(m.sm, q)
+}
+
+func (m modelHandler) GetEndpointDetails(id, method, path string) (*spec.EndpointModel, string, bool) {
+    var ep *spec.EndpointModel
+    var ok bool
+    if id != "" {
+        ep, ok = methods.GetEndpointDetails(m.sm, id)
+    } else {
+        ep, ok = methods.GetEndpointDetails(m.sm, method, path)
+    }
+    if !ok || ep == nil {
+        return nil, "", false
+    }
+    return ep, methods.FormatEndpointDetails(ep, m.sm), true
+}
+
+func (m modelHandler) ListSchemas() []methods.SchemaSummary {
+    return methods.ListSchemas(m.sm)
+}
+
+func (m modelHandler) GetSchemaDetails(name string) (*spec.Schema, string, bool) {
+    sc, ok := methods.GetSchemaDetails(m.sm, name)
+    if !ok {
+        return nil, "", false
+    }
+    return sc, methods.FormatSchemaDetails(sc, m.sm), true
+}
+
+func (m modelHandler) ExplainParameter(endpointID, name string) (*methods.ParameterExplanation, string, error) {
+    px, err := methods.ExplainParameter(m.sm, endpointID, name)
+    if err != nil {
+        return nil, "", err
+    }
+    return px, methods.FormatParameterExplanation(px, m.sm), nil
+}
+`
+
+func renderMockHandlerGo(data templateData) string {
+	if data.invoke {
+		return data.render(strings.Replace(mockHandlerGo, `import (
+`, `import (
+    "context"
+
+`, 1) + mockCallEndpointGo)
+	}
+	return data.render(mockHandlerGo)
+}
+
+// mockCallEndpointGo is the MockHandler method for callEndpoint.
+const mockCallEndpointGo = `
+func (m *MockHandler) CallEndpoint(ctx context.Context, args methods.CallArgs) (*methods.CallResult, error) {
+    ret := m.Called(ctx, args)
+    res, _ := ret.Get(0).(*methods.CallResult)
+    return res, ret.Error(1)
+}
+`
+
+// mockHandlerGo is a testify mock of the Handler interface. Each method
+// records its call with m.Called, so tests program results with
+// m.On("<Method>", args...).Return(...).
+const mockHandlerGo = `package mocks
+
+import (
+    "github.com/stretchr/testify/mock"
+
+    server "{{MODULE}}/internal/mcp"
+    methods "{{MODULE}}/internal/mcp/methods"
+    "{{MODULE}}/internal/spec"
+)
+
+// MockHandler is a testify mock implementing server.Handler.
+type MockHandler struct {
+    mock.Mock
+}
+
+var _ server.Handler = (*MockHandler)(nil)
+
+func (m *MockHandler) ListEndpoints() string {
+    args := m.Called()
+    return args.String(0)
+}
+
+func (m *MockHandler) SearchEndpoints(q methods.SearchQuery) []methods.EndpointSearchResult {
+    args := m.Called(q)
+    out, _ := args.Get(0).([]methods.EndpointSearchResult)
+    return out
+}
+
+func (m *MockHandler) GetEndpointDetails(id, method, path string) (*spec.EndpointModel, string, bool) {
+    args := m.Called(id, method, path)
+    ep, _ := args.Get(0).(*spec.EndpointModel)
+    return ep, args.String(1), args.Bool(2)
+}
+
+func (m *MockHandler) ListSchemas() []methods.SchemaSummary {
+    args := m.Called()
+    out, _ := args.Get(0).([]methods.SchemaSummary)
+    return out
+}
+
+func (m *MockHandler) GetSchemaDetails(name string) (*spec.Schema, string, bool) {
+    args := m.Called(name)
+    sc, _ := args.Get(0).(*spec.Schema)
+    return sc, args.String(1), args.Bool(2)
+}
+
+func (m *MockHandler) ExplainParameter(endpointID, name string) (*methods.ParameterExplanation, string, error) {
+    args := m.Called(endpointID, name)
+    px, _ := args.Get(0).(*methods.ParameterExplanation)
+    return px, args.String(1), args.Error(2)
+}
+`
+
+const mcpBootstrapGo = `package mcp
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/mark3labs/mcp-go/mcp"
+    goserver "github.com/mark3labs/mcp-go/server"
+
+    methods "{{MODULE}}/internal/mcp/methods"
+    "{{MODULE}}/internal/spec"
+)
+
+// NewMCPServer creates and configures an MCP server with tools backed by the ServiceModel.
+func NewMCPServer(sm *spec.ServiceModel) *goserver.MCPServer {
+    spec.ApplyBaseURLOverride(sm)
+    name := sm.Title
+    if name == "" { name = "mcp-tool" }
+    srv := goserver.NewMCPServer(name, sm.Version,
+        goserver.WithToolCapabilities(true),
+        goserver.WithInstructions("This server exposes tools to query your API documentation."),
+        goserver.WithRecovery(),
+    )
+
+    // listEndpoints tool (no args)
+    srv.AddTool(mcp.NewTool("listEndpoints",
+        mcp.WithDescription("Show API overview and routing summary"),
+        mcp.WithInputSchema[struct{}](),
+    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        overview := methods.FormatEndpointsOverview(sm)
+        return &mcp.CallToolResult{
+            Content: []mcp.Content{mcp.TextContent{Type: "text", Text: overview}},
+        }, nil
+    })
+
+    // searchEndpoints tool
+    type SearchArgs struct {
+        Keyword     string
+        Tag         string
+        Method      string
+        PathPattern string
+    }
+    srv.AddTool(mcp.NewTool("searchEndpoints",
+        mcp.WithDescription("Search endpoints by keyword, tag, method, or path regex"),
+        mcp.WithInputSchema[SearchArgs](),
+    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        var args SearchArgs
+        _ = req.BindArguments(&args)
+        out := methods.SearchEndpoints(sm, methods.SearchQuery{Keyword: args.Keyword, Tag: args.Tag, Method: args.Method, PathPattern: args.PathPattern})
+        return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("%d matches", len(out))}}, StructuredContent: out}, nil
+    })
+
+    // getEndpointDetails tool
+    type GetEPArgs struct {
+        ID     string
+        Method string
+        Path   string
+    }
+    srv.AddTool(mcp.NewTool("getEndpointDetails",
+        mcp.WithDescription("Get endpoint details by id or method+path"),
+        mcp.WithInputSchema[GetEPArgs](),
+    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        var a GetEPArgs
+        _ = req.BindArguments(&a)
+        var ep *spec.EndpointModel
+        var ok bool
+        if a.ID != "" {
+            ep, ok = methods.GetEndpointDetails(sm, a.ID)
+        } else {
+            ep, ok = methods.GetEndpointDetails(sm, a.Method, a.Path)
+        }
+        if !ok || ep == nil {
+            return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "endpoint not found"}}}, nil
+        }
+        // Format detailed text output
+        text := methods.FormatEndpointDetails(ep, sm)
+        return &mcp.CallToolResult{StructuredContent: ep, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}}}, nil
+    })
+
+    // listSchemas tool
+    srv.AddTool(mcp.NewTool("listSchemas",
+        mcp.WithDescription("List schema names"),
+        mcp.WithInputSchema[struct{}](),
+    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        out := methods.ListSchemas(sm)
+        return &mcp.CallToolResult{StructuredContent: out, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("%d schemas", len(out))}}}, nil
+    })
+
+    // getSchemaDetails tool
+    type GetSchemaArgs struct { Name string }
+    srv.AddTool(mcp.NewTool("getSchemaDetails",
+        mcp.WithDescription("Get details for a schema by name"),
+        mcp.WithInputSchema[GetSchemaArgs](),
+    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        var a GetSchemaArgs
+        if err := req.BindArguments(&a); err != nil { return nil, err }
+        sc, ok := methods.GetSchemaDetails(sm, a.Name)
+        if !ok { return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "schema not found"}}}, nil }
+        // Format detailed schema output
+        text := methods.FormatSchemaDetails(sc, sm)
+        return &mcp.CallToolResult{StructuredContent: sc, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}}}, nil
+    })
+
+    // explainParameter tool
+    type ExplainArgs struct {
+        EndpointID    string ` + "`json:\"endpointId\"`" + `
+        ParameterName string ` + "`json:\"parameterName\"`" + `
+    }
+    srv.AddTool(mcp.NewTool("explainParameter",
+        mcp.WithDescription("Explain one endpoint parameter: location, schema, serialization, enum values, and how it appears in a request"),
+        mcp.WithInputSchema[ExplainArgs](),
+    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        var a ExplainArgs
+        if err := req.BindArguments(&a); err != nil { return nil, err }
+        px, err := methods.ExplainParameter(sm, a.EndpointID, a.ParameterName)
+        if err != nil { return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}}}, nil }
+        // Format the explanation
+        text := methods.FormatParameterExplanation(px, sm)
+        return &mcp.CallToolResult{StructuredContent: px, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}}}, nil
+    })
+
+    return srv
+}
+`
+
+func renderListEndpointsGo(data templateData) string {
+	return data.render(`package methods
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "` + "{{MODULE}}" + `/internal/spec"
+)
+
+type EndpointSummary struct {
+    ID          string   ` + "`json:\"id\"`" + `
+    Method      string   ` + "`json:\"method\"`" + `
+    Path        string   ` + "`json:\"path\"`" + `
+    Summary     string   ` + "`json:\"summary\"`" + `
+    Description string   ` + "`json:\"description\"`" + `
+    Tags        []string ` + "`json:\"tags\"`" + `
+    Parameters  []ParameterSummary ` + "`json:\"parameters\"`" + `
+    Responses   []ResponseSummary  ` + "`json:\"responses\"`" + `
+}
+
+type ParameterSummary struct {
+    Name     string ` + "`json:\"name\"`" + `
+    In       string ` + "`json:\"in\"`" + `
+    Required bool   ` + "`json:\"required\"`" + `
+    Type     string ` + "`json:\"type\"`" + `
+    Enum     []any  ` + "`json:\"enum,omitempty\"`" + `
+}
+
+type ResponseSummary struct {
+    Status      string ` + "`json:\"status\"`" + `
+    Description string ` + "`json:\"description\"`" + `
+}
+
+func ListEndpoints(sm *spec.ServiceModel) []EndpointSummary {
+    out := make([]EndpointSummary, 0, len(sm.Endpoints))
+    for _, ep := range sm.Endpoints {
+        // Build parameters
+        params := make([]ParameterSummary, 0, len(ep.Parameters))
+        for _, p := range ep.Parameters {
+            paramType := "unknown"
+            var enumValues []any
+            if p.Schema != nil && p.Schema.Schema != nil {
+                if p.Schema.Schema.Type != "" {
+                    paramType = p.Schema.Schema.Type
+                }
+                enumValues = p.Schema.Schema.Enum
+            }
+            params = append(params, ParameterSummary{
+                Name:     p.Name,
+                In:       p.In,
+                Required: p.Required,
+                Type:     paramType,
+                Enum:     enumValues,
+            })
+        }
+        
+        // Build responses
+        responses := make([]ResponseSummary, 0, len(ep.Responses))
+        for _, r := range ep.Responses {
+            responses = append(responses, ResponseSummary{
+                Status:      r.Status,
+                Description: r.Description,
+            })
+        }
+        
+        out = append(out, EndpointSummary{
+            ID:          ep.ID, 
+            Method:      string(ep.Method), 
+            Path:        ep.Path, 
+            Summary:     ep.Summary, 
+            Description: ep.Description,
+            Tags:        append([]string(nil), ep.Tags...),
+            Parameters:  params,
+            Responses:   responses,
+        })
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].Path == out[j].Path { return out[i].Method < out[j].Method }
+        return spec.CompareNames(out[i].Path, out[j].Path) < 0
+    })
+    return out
+}
+
+// FormatEndpointsOverview formats a comprehensive overview of all endpoints
+func FormatEndpointsOverview(sm *spec.ServiceModel) string {
+    endpoints := ListEndpoints(sm)
+    if len(endpoints) == 0 {
+        return "无可用接口"
+    }
+
+    var lines []string
+    lines = append(lines, fmt.Sprintf("API 接口概览 (%d 个接口)", len(endpoints)))
+    if base := spec.BaseURL(sm); base != "" {
+        lines = append(lines, "基础 URL: "+base)
+    }
+    lines = append(lines, "")
+
+    // 按标签分组统计
+    tagStats := make(map[string]int)
+    methodStats := make(map[string]int)
+    
+    for _, ep := range endpoints {
+        methodStats[ep.Method]++
+        for _, tag := range ep.Tags {
+            tagStats[tag]++
+        }
+    }
+    
+    // 显示方法统计
+    lines = append(lines, "HTTP 方法分布:")
+    methods := make([]string, 0, len(methodStats))
+    for method := range methodStats {
+        methods = append(methods, method)
+    }
+    sort.Strings(methods)
+    for _, method := range methods {
+        lines = append(lines, fmt.Sprintf("  %s: %d 个接口", strings.ToUpper(method), methodStats[method]))
+    }
+    lines = append(lines, "")
+    
+    // 显示标签统计 (取前10个)
+    if len(tagStats) > 0 {
+        lines = append(lines, "服务模块分布:")
+        // 按数量排序
+        type tagCount struct {
+            tag   string
+            count int
+        }
+        var tagList []tagCount
+        for tag, count := range tagStats {
+            tagList = append(tagList, tagCount{tag, count})
+        }
+        sort.Slice(tagList, func(i, j int) bool {
+            if tagList[i].count == tagList[j].count {
+                return spec.CompareNames(tagList[i].tag, tagList[j].tag) < 0
+            }
+            return tagList[i].count > tagList[j].count
+        })
+        
+        // 显示前10个最多的标签
+        maxShow := 10
+        if len(tagList) < maxShow {
+            maxShow = len(tagList)
+        }
+        for i := 0; i < maxShow; i++ {
+            line := fmt.Sprintf("  %s: %d 个接口", tagList[i].tag, tagList[i].count)
+            if desc := sm.TagDescriptions[tagList[i].tag]; desc != "" {
+                line += " — " + desc
+            }
+            lines = append(lines, line)
+        }
+        if len(tagList) > 10 {
+            lines = append(lines, fmt.Sprintf("  ... 还有 %d 个其他服务模块", len(tagList)-10))
+        }
+        lines = append(lines, "")
+    }
+    
+    // 显示主要路由路径
+    lines = append(lines, "主要路由路径:")
+    pathPrefixes := make(map[string]int)
+    for _, ep := range endpoints {
+        // 提取路径前缀 (前两级路径)
+        parts := strings.Split(strings.Trim(ep.Path, "/"), "/")
+        if len(parts) >= 2 {
+            prefix := "/" + parts[0] + "/" + parts[1]
+            pathPrefixes[prefix]++
+        } else if len(parts) == 1 && parts[0] != "" {
+            prefix := "/" + parts[0]
+            pathPrefixes[prefix]++
+        }
+    }
+    
+    // 按数量排序路径前缀
+    type pathCount struct {
+        path  string
+        count int
+    }
+    var pathList []pathCount
+    for path, count := range pathPrefixes {
+        pathList = append(pathList, pathCount{path, count})
+    }
+    sort.Slice(pathList, func(i, j int) bool {
+        if pathList[i].count == pathList[j].count {
+            return spec.CompareNames(pathList[i].path, pathList[j].path) < 0
+        }
+        return pathList[i].count > pathList[j].count
+    })
+    
+    // 显示前10个最常用的路径前缀
+    maxPaths := 10
+    if len(pathList) < maxPaths {
+        maxPaths = len(pathList)
+    }
+    for i := 0; i < maxPaths; i++ {
+        lines = append(lines, fmt.Sprintf("  %s: %d 个接口", pathList[i].path, pathList[i].count))
+    }
+    if len(pathList) > 10 {
+        lines = append(lines, fmt.Sprintf("  ... 还有 %d 个其他路径", len(pathList)-10))
+    }
+    lines = append(lines, "")
+    
+    // 显示所有接口端点列表 (功能总结和路由)
+    lines = append(lines, "所有接口端点:")
+    for _, ep := range endpoints {
+        summary := ep.Summary
+        if summary == "" {
+            summary = "无描述"
+        }
+        lines = append(lines, fmt.Sprintf("  %s %s - %s", strings.ToUpper(ep.Method), ep.Path, summary))
+    }
+    
+    return strings.Join(lines, "\n")
+}
+`)
+}
+
+func renderSearchEndpointsGo(data templateData) string {
+	return data.render(`package methods
+
+import (
+    "regexp"
+    "sort"
+    "strings"
+
+    "` + "{{MODULE}}" + `/internal/spec"
+)
+
+type SearchQuery struct {
+    Keyword     string
+    Tag         string
+    Method      string // lowercased http verb
+    PathPattern string // regex
+}
+
+type EndpointSearchResult struct {
+    ID      string   ` + "`json:\"id\"`" + `
+    Method  string   ` + "`json:\"method\"`" + `
+    Path    string   ` + "`json:\"path\"`" + `
+    Summary string   ` + "`json:\"summary\"`" + `
+    Tags    []string ` + "`json:\"tags\"`" + `
+}
+
+func SearchEndpoints(sm *spec.ServiceModel, q SearchQuery) []EndpointSearchResult {
+    var re *regexp.Regexp
+    if strings.TrimSpace(q.PathPattern) != "" {
+        var err error
+        re, err = regexp.Compile(q.PathPattern)
+        if err != nil {
+            // invalid pattern -> no matches
+            return nil
+        }
+    }
+    kw := strings.ToLower(strings.TrimSpace(q.Keyword))
+    tag := strings.TrimSpace(q.Tag)
+    method := strings.ToLower(strings.TrimSpace(q.Method))
+
+    var out []EndpointSearchResult
+    for _, ep := range sm.Endpoints {
+        if method != "" && string(ep.Method) != method { continue }
+        if tag != "" {
+            found := false
+            for _, t := range ep.Tags { if t == tag { found = true; break } }
+            if !found { continue }
+        }
+        if re != nil && !re.MatchString(ep.Path) { continue }
+        if kw != "" {
+            text := strings.ToLower(ep.Summary + "\n" + ep.Description + "\n" + ep.Path)
+            if !strings.Contains(text, kw) { continue }
+        }
+        out = append(out, EndpointSearchResult{ID: ep.ID, Method: string(ep.Method), Path: ep.Path, Summary: ep.Summary, Tags: append([]string(nil), ep.Tags...)})
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].Path == out[j].Path { return out[i].Method < out[j].Method }
+        return spec.CompareNames(out[i].Path, out[j].Path) < 0
+    })
+    return out
+}
+`)
+}
+
+func renderUtilsGo(data templateData) string {
+	return data.render(`package methods
+
+import (
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+
+    "{{MODULE}}/internal/spec"
+)
+
+// formatSchemaWithRefs recursively formats schema with reference resolution
+func formatSchemaWithRefs(schema *spec.Schema, sm *spec.ServiceModel, indent string) []string {
+    var lines []string
+    
+    if schema == nil {
+        return lines
+    }
+    
+    if schema.Type != "" {
+        lines = append(lines, fmt.Sprintf("%s类型: %s", indent, schema.Type))
+    }
+    
+    if len(schema.Properties) > 0 {
+        lines = append(lines, fmt.Sprintf("%s属性:", indent))
+        propNames := make([]string, 0, len(schema.Properties))
+        for propName := range schema.Properties {
+            propNames = append(propNames, propName)
+        }
+        spec.SortNames(propNames)
+        for _, propName := range propNames {
+            propSchema := schema.Properties[propName]
+            isRequired := "[可选]"
+            for _, req := range schema.Required {
+                if req == propName {
+                    isRequired = "[必需]"
+                    break
+                }
+            }
+            
+            if propSchema.Schema != nil {
+                // Direct schema
+                propType := getStringOrDefault(propSchema.Schema.Type, "unknown")
+                if propType == "array" {
+                    // Handle array property with items
+                    if propSchema.Schema.Items != nil && propSchema.Schema.Items.Ref != nil {
+                        refName := strings.Replace(propSchema.Schema.Items.Ref.Ref, "#/components/schemas/", "", 1)
+                        refName = strings.Replace(refName, "#/definitions/", "", 1)
+                        lines = append(lines, fmt.Sprintf("%s  • %s: %s<%s> %s", indent, propName, propType, refName, isRequired))
+                        
+                        // Try to resolve the reference
+                        if sm.Schemas != nil {
+                            if refSchema, exists := sm.Schemas[refName]; exists {
+                                lines = append(lines, fmt.Sprintf("%s    └─ %s 详情:", indent, refName))
+                                refLines := formatSchemaWithRefs(&refSchema, sm, indent+"      ")
+                                lines = append(lines, refLines...)
+                            }
+                        }
+                    } else if propSchema.Schema.Items != nil && propSchema.Schema.Items.Schema != nil {
+                        itemType := getStringOrDefault(propSchema.Schema.Items.Schema.Type, "unknown")
+                        lines = append(lines, fmt.Sprintf("%s  • %s: %s<%s> %s", indent, propName, propType, itemType, isRequired))
+                    } else {
+                        lines = append(lines, fmt.Sprintf("%s  • %s: %s %s", indent, propName, propType, isRequired))
+                    }
+                } else {
+                    lines = append(lines, fmt.Sprintf("%s  • %s: %s %s", indent, propName, propType, isRequired))
+                }
+                
+                if len(propSchema.Schema.Enum) > 0 {
+                    enumBytes, _ := json.Marshal(propSchema.Schema.Enum)
+                    lines = append(lines, fmt.Sprintf("%s    允许值: %s", indent, string(enumBytes)))
+                }
+                if c := schemaConstraints(propSchema.Schema); len(c) > 0 {
+                    lines = append(lines, fmt.Sprintf("%s    约束: %s", indent, strings.Join(c, ", ")))
+                }
+            } else if propSchema.Ref != nil {
+                // Reference to another schema
+                refName := strings.Replace(propSchema.Ref.Ref, "#/components/schemas/", "", 1)
+                refName = strings.Replace(refName, "#/definitions/", "", 1)
+                lines = append(lines, fmt.Sprintf("%s  • %s: %s (引用) %s", indent, propName, refName, isRequired))
+                
+                // Try to resolve the reference
+                if sm.Schemas != nil {
+                    if refSchema, exists := sm.Schemas[refName]; exists {
+                        lines = append(lines, fmt.Sprintf("%s    └─ %s 详情:", indent, refName))
+                        refLines := formatSchemaWithRefs(&refSchema, sm, indent+"      ")
+                        lines = append(lines, refLines...)
+                    }
+                }
+            } else {
+                lines = append(lines, fmt.Sprintf("%s  • %s: unknown %s", indent, propName, isRequired))
+            }
+        }
+    }
+    
+    if schema.Items != nil {
+        if schema.Items.Schema != nil {
+            itemType := getStringOrDefault(schema.Items.Schema.Type, "unknown")
+            lines = append(lines, fmt.Sprintf("%s数组元素类型: %s", indent, itemType))
+            itemLines := formatSchemaWithRefs(schema.Items.Schema, sm, indent+"  ")
+            lines = append(lines, itemLines...)
+        } else if schema.Items.Ref != nil {
+            refName := strings.Replace(schema.Items.Ref.Ref, "#/components/schemas/", "", 1)
+            refName = strings.Replace(refName, "#/definitions/", "", 1)
+            lines = append(lines, fmt.Sprintf("%s数组元素类型: %s (引用)", indent, refName))
+            
+            // Try to resolve the reference
+            if sm.Schemas != nil {
+                if refSchema, exists := sm.Schemas[refName]; exists {
+                    lines = append(lines, fmt.Sprintf("%s└─ %s 详情:", indent, refName))
+                    refLines := formatSchemaWithRefs(&refSchema, sm, indent+"  ")
+                    lines = append(lines, refLines...)
+                }
+            }
+        }
+    }
+    
+    if len(schema.AllOf) > 0 {
+        lines = append(lines, fmt.Sprintf("%s组合类型 (AllOf):", indent))
+        for i, subSchema := range schema.AllOf {
+            lines = append(lines, fmt.Sprintf("%s  %d. ", indent, i+1))
+            if subSchema.Schema != nil {
+                subLines := formatSchemaWithRefs(subSchema.Schema, sm, indent+"    ")
+                lines = append(lines, subLines...)
+            } else if subSchema.Ref != nil {
+                refName := strings.Replace(subSchema.Ref.Ref, "#/components/schemas/", "", 1)
+                refName = strings.Replace(refName, "#/definitions/", "", 1)
+                lines = append(lines, fmt.Sprintf("%s    引用: %s", indent, refName))
+                if sm.Schemas != nil {
+                    if refSchema, exists := sm.Schemas[refName]; exists {
+                        refLines := formatSchemaWithRefs(&refSchema, sm, indent+"      ")
+                        lines = append(lines, refLines...)
+                    }
+                }
+            }
+        }
+    }
+    
+    if len(schema.Enum) > 0 {
+        enumBytes, _ := json.Marshal(schema.Enum)
+        lines = append(lines, fmt.Sprintf("%s允许值: %s", indent, string(enumBytes)))
+    }
+    
+    if c := schemaConstraints(schema); len(c) > 0 {
+        lines = append(lines, fmt.Sprintf("%s约束: %s", indent, strings.Join(c, ", ")))
+    }
+    
+    return lines
+}
+
+// schemaConstraints lists array and numeric constraints, e.g. "minItems=2"
+func schemaConstraints(s *spec.Schema) []string {
+    var out []string
+    if s.MinItems != nil {
+        out = append(out, fmt.Sprintf("minItems=%d", *s.MinItems))
+    }
+    if s.MaxItems != nil {
+        out = append(out, fmt.Sprintf("maxItems=%d", *s.MaxItems))
+    }
+    if s.UniqueItems {
+        out = append(out, "uniqueItems")
+    }
+    if s.Minimum != nil {
+        op := ">="
+        if s.ExclusiveMinimum {
+            op = ">"
+        }
+        out = append(out, "minimum"+op+strconv.FormatFloat(*s.Minimum, 'g', -1, 64))
+    }
+    if s.Maximum != nil {
+        op := "<="
+        if s.ExclusiveMaximum {
+            op = "<"
+        }
+        out = append(out, "maximum"+op+strconv.FormatFloat(*s.Maximum, 'g', -1, 64))
+    }
+    return out
+}
+
+func getStringOrDefault(s, def string) string {
+    if strings.TrimSpace(s) == "" {
+        return def
+    }
+    return s
+}
+
+func getTagsOrDefault(tags []string) string {
+    if len(tags) == 0 {
+        return "无"
+    }
+    return strings.Join(tags, ", ")
+}
+`)
+}
+
+func renderGetEndpointDetailsGo(data templateData) string {
+	return data.render(`package methods
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+
+    "` + "{{MODULE}}" + `/internal/spec"
+)
+
+func GetEndpointDetails(sm *spec.ServiceModel, idOrMethod string, pathOptional ...string) (*spec.EndpointModel, bool) {
+    method := strings.ToLower(strings.TrimSpace(idOrMethod))
+    if len(pathOptional) == 0 {
+        // id is expected in form "<method> <path>"
+        for _, ep := range sm.Endpoints {
+            if ep.ID == idOrMethod { return &ep, true }
+        }
+        return nil, false
+    }
+    path := pathOptional[0]
+    for _, ep := range sm.Endpoints {
+        if string(ep.Method) == method && ep.Path == path { return &ep, true }
+    }
+    return nil, false
+}
+
+// parameterLocations orders the parameter sections of FormatEndpointDetails.
+var parameterLocations = []struct{ in, title string }{
+    {"path", "路径参数"},
+    {"query", "查询参数"},
+    {"header", "请求头参数"},
+    {"cookie", "Cookie参数"},
+}
+
+// FormatEndpointDetails formats endpoint details with schema reference resolution
+func FormatEndpointDetails(ep *spec.EndpointModel, sm *spec.ServiceModel) string {
+    var lines []string
+    
+    // Basic info
+    lines = append(lines, fmt.Sprintf("%s %s", strings.ToUpper(string(ep.Method)), ep.Path))
+    lines = append(lines, fmt.Sprintf("摘要: %s", getStringOrDefault(ep.Summary, "无")))
+    lines = append(lines, fmt.Sprintf("描述: %s", getStringOrDefault(ep.Description, "无")))
+    lines = append(lines, fmt.Sprintf("标签: %s", getTagsOrDefault(ep.Tags)))
+    if len(ep.Extensions) > 0 {
+        extBytes, _ := json.Marshal(ep.Extensions)
+        lines = append(lines, fmt.Sprintf("扩展: %s", string(extBytes)))
+    }
+    
+    // Parameters, grouped by location
+    for _, loc := range parameterLocations {
+        var located []spec.ParameterModel
+        for _, param := range ep.Parameters {
+            if param.In == loc.in {
+                located = append(located, param)
+            }
+        }
+        if len(located) == 0 {
+            continue
+        }
+        lines = append(lines, "", loc.title+":")
+        for _, param := range located {
+            required := "[可选]"
+            if param.Required {
+                required = "[必需]"
+            }
+            paramType := "unknown"
+            enumValues := ""
+            if param.Schema != nil && param.Schema.Schema != nil {
+                paramType = getStringOrDefault(param.Schema.Schema.Type, "unknown")
+                if param.Schema.Schema.Format != "" {
+                    paramType += " (" + param.Schema.Schema.Format + ")"
+                }
+                if len(param.Schema.Schema.Enum) > 0 {
+                    enumBytes, _ := json.Marshal(param.Schema.Schema.Enum)
+                    enumValues = fmt.Sprintf(" (允许值: %s)", string(enumBytes))
+                }
+            }
+            lines = append(lines, fmt.Sprintf("  • %s - %s%s %s", param.Name, paramType, enumValues, required))
+            if desc := strings.Join(strings.Fields(param.Description), " "); desc != "" {
+                lines = append(lines, "    说明: "+desc)
+            }
+            if param.Example != nil {
+                exBytes, _ := json.Marshal(param.Example)
+                lines = append(lines, "    示例: "+string(exBytes))
+            }
+        }
+    }
+    
+    // Request body
+    if ep.RequestBody != nil {
+        lines = append(lines, "", "请求体:")
+        required := "[可选]"
+        if ep.RequestBody.Required {
+            required = "[必需]"
+        }
+        
+        contentTypes := make([]string, 0)
+        for _, content := range ep.RequestBody.Content {
+            contentTypes = append(contentTypes, content.Mime)
+        }
+        contentTypeStr := "unknown"
+        if len(contentTypes) > 0 {
+            contentTypeStr = strings.Join(contentTypes, ", ")
+        }
+        lines = append(lines, fmt.Sprintf("  Content-Type: %s %s", contentTypeStr, required))
+        
+        // Add request schema information
+        for _, content := range ep.RequestBody.Content {
+            if content.Schema != nil {
+                lines = append(lines, fmt.Sprintf("  Schema (%s):", content.Mime))
+                
+                // Handle direct schema
+                if content.Schema.Schema != nil {
+                    schemaLines := formatSchemaWithRefs(content.Schema.Schema, sm, "    ")
+                    lines = append(lines, schemaLines...)
+                }
+                
+                // Handle schema reference
+                if content.Schema.Ref != nil {
+                    refName := strings.Replace(content.Schema.Ref.Ref, "#/components/schemas/", "", 1)
+                    lines = append(lines, fmt.Sprintf("    引用: %s", refName))
+                    
+                    // Try to resolve the reference and show details
+                    if sm.Schemas != nil {
+                        if refSchema, exists := sm.Schemas[refName]; exists {
+                            lines = append(lines, fmt.Sprintf("    └─ %s 详情:", refName))
+                            refLines := formatSchemaWithRefs(&refSchema, sm, "      ")
+                            lines = append(lines, refLines...)
+                        }
+                    }
+                }
+            }
+            
+            if content.Example != nil {
+                exampleBytes, _ := json.Marshal(content.Example)
+                lines = append(lines, fmt.Sprintf("  示例: %s", string(exampleBytes)))
+            }
+        }
+    }
+    
+    // Responses
+    if len(ep.Responses) > 0 {
+        lines = append(lines, "", "响应:")
+        for _, resp := range ep.Responses {
+            lines = append(lines, fmt.Sprintf("  • %s: %s", resp.Status, resp.Description))
+            
+            // Add response schema information
+            for _, content := range resp.Content {
+                if content.Schema != nil {
+                    lines = append(lines, fmt.Sprintf("    Schema (%s):", content.Mime))
+                    
+                    // Handle direct schema
+                    if content.Schema.Schema != nil {
+                        schemaLines := formatSchemaWithRefs(content.Schema.Schema, sm, "      ")
+                        lines = append(lines, schemaLines...)
+                    }
+                    
+                    // Handle schema reference
+                    if content.Schema.Ref != nil {
+                        refName := strings.Replace(content.Schema.Ref.Ref, "#/components/schemas/", "", 1)
+                        lines = append(lines, fmt.Sprintf("      引用: %s", refName))
+                        
+                        // Try to resolve the reference and show details
+                        if sm.Schemas != nil {
+                            if refSchema, exists := sm.Schemas[refName]; exists {
+                                lines = append(lines, fmt.Sprintf("      └─ %s 详情:", refName))
+                                refLines := formatSchemaWithRefs(&refSchema, sm, "        ")
+                                lines = append(lines, refLines...)
+                            }
+                        }
+                    }
+                }
+                
+                if content.Example != nil {
+                    exampleBytes, _ := json.Marshal(content.Example)
+                    lines = append(lines, fmt.Sprintf("    示例: %s", string(exampleBytes)))
+                }
+            }
+        }
+    }
+    
+    return strings.Join(lines, "\n")
+}
+`)
+}
+
+func renderListSchemasGo(data templateData) string {
+	return data.render(`package methods
+
+import (
+    "` + "{{MODULE}}" + `/internal/spec"
+)
+
+type SchemaSummary struct {
+    Name        string ` + "`json:\"name\"`" + `
+    Description string ` + "`json:\"description\"`" + `
+}
+
+func ListSchemas(sm *spec.ServiceModel) []SchemaSummary {
+    if len(sm.Schemas) == 0 { return nil }
+    names := make([]string, 0, len(sm.Schemas))
+    for name := range sm.Schemas { names = append(names, name) }
+    spec.SortNames(names)
+    out := make([]SchemaSummary, 0, len(names))
+    for _, name := range names {
+        s := sm.Schemas[name]
+        out = append(out, SchemaSummary{Name: name, Description: s.Description})
+    }
+    return out
+}
+`)
+}
 
-    // listSchemas tool
-    srv.AddTool(mcp.NewTool("listSchemas",
-        mcp.WithDescription("List schema names"),
-        mcp.WithInputSchema[struct{}](),
-    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-        out := methods.ListSchemas(sm)
-        return &mcp.CallToolResult{StructuredContent: out, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("%d schemas", len(out))}}}, nil
-    })
+func renderGetSchemaDetailsGo(data templateData) string {
+	return data.render(`package methods
 
-    // getSchemaDetails tool
-    type GetSchemaArgs struct { Name string }
-    srv.AddTool(mcp.NewTool("getSchemaDetails",
-        mcp.WithDescription("Get details for a schema by name"),
-        mcp.WithInputSchema[GetSchemaArgs](),
-    ), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-        var a GetSchemaArgs
-        if err := req.BindArguments(&a); err != nil { return nil, err }
-        sc, ok := methods.GetSchemaDetails(sm, a.Name)
-        if !ok { return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "schema not found"}}}, nil }
-        // Format detailed schema output
-        text := methods.FormatSchemaDetails(sc, sm)
-        return &mcp.CallToolResult{StructuredContent: sc, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}}}, nil
-    })
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+    
+    "` + "{{MODULE}}" + `/internal/spec"
+)
 
-    return srv
+func GetSchemaDetails(sm *spec.ServiceModel, name string) (*spec.Schema, bool) {
+    s, ok := sm.Schemas[name]
+    if !ok { return nil, false }
+    // copy to avoid external mutation
+    sc := s
+    return &sc, true
+}
+
+// FormatSchemaDetails formats schema details with reference resolution
+func FormatSchemaDetails(schema *spec.Schema, sm *spec.ServiceModel) string {
+    var lines []string
+    
+    lines = append(lines, fmt.Sprintf("Schema: %s", schema.Name))
+    if len(schema.Extensions) > 0 {
+        extBytes, _ := json.Marshal(schema.Extensions)
+        lines = append(lines, fmt.Sprintf("扩展: %s", string(extBytes)))
+    }
+    
+    if schema != nil {
+        schemaLines := formatSchemaWithRefs(schema, sm, "")
+        lines = append(lines, schemaLines...)
+    }
+    
+    return strings.Join(lines, "\n")
 }
 `)
 }
 
-func renderListEndpointsGo(data templateData) string {
+func renderExplainParameterGo(data templateData) string {
 	return data.render(`package methods
 
 import (
+    "encoding/json"
     "fmt"
+    "net/url"
     "sort"
     "strings"
 
     "` + "{{MODULE}}" + `/internal/spec"
 )
 
-type EndpointSummary struct {
-    ID          string   ` + "`json:\"id\"`" + `
-    Method      string   ` + "`json:\"method\"`" + `
-    Path        string   ` + "`json:\"path\"`" + `
-    Summary     string   ` + "`json:\"summary\"`" + `
-    Description string   ` + "`json:\"description\"`" + `
-    Tags        []string ` + "`json:\"tags\"`" + `
-    Parameters  []ParameterSummary ` + "`json:\"parameters\"`" + `
-    Responses   []ResponseSummary  ` + "`json:\"responses\"`" + `
+// ParameterExplanation is everything the model knows about one parameter of
+// an endpoint, with its schema reference resolved.
+type ParameterExplanation struct {
+    EndpointID  string       ` + "`json:\"endpointId\"`" + `
+    Name        string       ` + "`json:\"name\"`" + `
+    In          string       ` + "`json:\"in\"`" + ` // path|query|header|cookie, or body for a request body property
+    Required    bool         ` + "`json:\"required\"`" + `
+    Style       string       ` + "`json:\"style,omitempty\"`" + `
+    Explode     bool         ` + "`json:\"explode\"`" + `
+    ContentType string       ` + "`json:\"contentType,omitempty\"`" + ` // body properties only
+    Ref         string       ` + "`json:\"ref,omitempty\"`" + `
+    Schema      *spec.Schema ` + "`json:\"schema,omitempty\"`" + `
+    Enum        []any        ` + "`json:\"enum,omitempty\"`" + `
+    Example     any          ` + "`json:\"example,omitempty\"`" + `
+    Constraints []string     ` + "`json:\"constraints,omitempty\"`" + `
+    Usage       string       ` + "`json:\"usage\"`" + ` // how the parameter appears in a request
 }
 
-type ParameterSummary struct {
-    Name     string ` + "`json:\"name\"`" + `
-    In       string ` + "`json:\"in\"`" + `
-    Required bool   ` + "`json:\"required\"`" + `
-    Type     string ` + "`json:\"type\"`" + `
-    Enum     []any  ` + "`json:\"enum,omitempty\"`" + `
+// ExplainParameter explains parameter name of the endpoint endpointID, given
+// as its "<method> <path>" ID or operationId. Top-level request body
+// properties count as parameters in "body". An unknown endpoint or parameter
+// yields an error listing the closest names.
+func ExplainParameter(sm *spec.ServiceModel, endpointID, name string) (*ParameterExplanation, error) {
+    ep := findEndpoint(sm, endpointID)
+    if ep == nil {
+        ids := make([]string, 0, len(sm.Endpoints))
+        for _, e := range sm.Endpoints {
+            ids = append(ids, e.ID)
+        }
+        return nil, notFound(fmt.Sprintf("endpoint %q not found", endpointID), endpointID, ids, false)
+    }
+    name = strings.TrimSpace(name)
+    var names []string
+    for _, p := range ep.Parameters {
+        if p.Name == name {
+            return explainModelParameter(sm, ep, p), nil
+        }
+        names = append(names, p.Name)
+    }
+    body, mime := requestBodySchema(sm, ep)
+    if body != nil {
+        for _, prop := range sortedProperties(body) {
+            if prop == name {
+                return explainBodyProperty(sm, ep, body, prop, mime), nil
+            }
+            names = append(names, prop)
+        }
+    }
+    return nil, notFound(fmt.Sprintf("parameter %q not found on %s", name, ep.ID), name, names, true)
 }
 
-type ResponseSummary struct {
-    Status      string ` + "`json:\"status\"`" + `
-    Description string ` + "`json:\"description\"`" + `
+// FormatParameterExplanation formats an explanation for the explainParameter tool.
+func FormatParameterExplanation(px *ParameterExplanation, sm *spec.ServiceModel) string {
+    required := "[可选]"
+    if px.Required {
+        required = "[必需]"
+    }
+    lines := []string{fmt.Sprintf("参数: %s (%s) %s", px.Name, px.In, required)}
+    lines = append(lines, fmt.Sprintf("端点: %s", px.EndpointID))
+    if px.In == "body" {
+        lines = append(lines, fmt.Sprintf("Content-Type: %s", getStringOrDefault(px.ContentType, "unknown")))
+    } else {
+        lines = append(lines, fmt.Sprintf("序列化: style=%s, explode=%t", px.Style, px.Explode))
+    }
+    lines = append(lines, "", "Schema:")
+    if px.Ref != "" {
+        lines = append(lines, fmt.Sprintf("  引用: %s", px.Ref))
+    }
+    if px.Schema != nil {
+        if px.Schema.Format != "" {
+            lines = append(lines, fmt.Sprintf("  格式: %s", px.Schema.Format))
+        }
+        lines = append(lines, formatSchemaWithRefs(px.Schema, sm, "  ")...)
+    } else {
+        lines = append(lines, "  类型: unknown")
+    }
+    if px.Example != nil {
+        exampleBytes, _ := json.Marshal(px.Example)
+        lines = append(lines, "", fmt.Sprintf("示例: %s", string(exampleBytes)))
+    }
+    lines = append(lines, "", "用法:")
+    for _, line := range strings.Split(px.Usage, "\n") {
+        if line != "" {
+            line = "  " + line
+        }
+        lines = append(lines, line)
+    }
+    return strings.Join(lines, "\n")
 }
 
-func ListEndpoints(sm *spec.ServiceModel) []EndpointSummary {
-    out := make([]EndpointSummary, 0, len(sm.Endpoints))
-    for _, ep := range sm.Endpoints {
-        // Build parameters
-        params := make([]ParameterSummary, 0, len(ep.Parameters))
-        for _, p := range ep.Parameters {
-            paramType := "unknown"
-            var enumValues []any
-            if p.Schema != nil && p.Schema.Schema != nil {
-                if p.Schema.Schema.Type != "" {
-                    paramType = p.Schema.Schema.Type
-                }
-                enumValues = p.Schema.Schema.Enum
+// findEndpoint resolves an endpoint ID (method case-insensitive) or operationId.
+func findEndpoint(sm *spec.ServiceModel, key string) *spec.EndpointModel {
+    key = strings.TrimSpace(key)
+    if method, path, ok := strings.Cut(key, " "); ok {
+        id := strings.ToLower(method) + " " + strings.TrimSpace(path)
+        for i := range sm.Endpoints {
+            if sm.Endpoints[i].ID == id {
+                return &sm.Endpoints[i]
             }
-            params = append(params, ParameterSummary{
-                Name:     p.Name,
-                In:       p.In,
-                Required: p.Required,
-                Type:     paramType,
-                Enum:     enumValues,
-            })
         }
-        
-        // Build responses
-        responses := make([]ResponseSummary, 0, len(ep.Responses))
-        for _, r := range ep.Responses {
-            responses = append(responses, ResponseSummary{
-                Status:      r.Status,
-                Description: r.Description,
-            })
+    }
+    for i := range sm.Endpoints {
+        if sm.Endpoints[i].OperationID != "" && sm.Endpoints[i].OperationID == key {
+            return &sm.Endpoints[i]
         }
-        
-        out = append(out, EndpointSummary{
-            ID:          ep.ID, 
-            Method:      string(ep.Method), 
-            Path:        ep.Path, 
-            Summary:     ep.Summary, 
-            Description: ep.Description,
-            Tags:        append([]string(nil), ep.Tags...),
-            Parameters:  params,
-            Responses:   responses,
-        })
     }
-    sort.Slice(out, func(i, j int) bool {
-        if out[i].Path == out[j].Path { return out[i].Method < out[j].Method }
-        return out[i].Path < out[j].Path
-    })
+    return nil
+}
+
+func explainModelParameter(sm *spec.ServiceModel, ep *spec.EndpointModel, p spec.ParameterModel) *ParameterExplanation {
+    style := p.Style
+    if style == "" {
+        style = "simple"
+        if p.In == "query" || p.In == "cookie" {
+            style = "form"
+        }
+    }
+    explode := style == "form"
+    if p.Explode != nil {
+        explode = *p.Explode
+    }
+    px := &ParameterExplanation{EndpointID: ep.ID, Name: p.Name, In: p.In, Required: p.Required || p.In == "path", Style: style, Explode: explode}
+    px.Ref, px.Schema = resolveSchema(sm, p.Schema)
+    describeSchema(px, sm)
+    px.Usage = parameterUsage(ep, px, sampleValue(sm, px.Schema, 0))
+    return px
+}
+
+func explainBodyProperty(sm *spec.ServiceModel, ep *spec.EndpointModel, body *spec.Schema, name, mime string) *ParameterExplanation {
+    px := &ParameterExplanation{EndpointID: ep.ID, Name: name, In: "body", ContentType: mime}
+    for _, req := range body.Required {
+        px.Required = px.Required || req == name
+    }
+    px.Ref, px.Schema = resolveSchema(sm, body.Properties[name])
+    describeSchema(px, sm)
+    value := sampleValue(sm, px.Schema, 0)
+    var payload string
+    if strings.Contains(mime, "json") || mime == "" {
+        b, _ := json.MarshalIndent(map[string]any{name: value}, "", "  ")
+        payload = string(b)
+    } else {
+        payload = url.QueryEscape(name) + "=" + url.QueryEscape(scalarString(value))
+    }
+    px.Usage = fmt.Sprintf("%s %s\nContent-Type: %s\n\n%s", strings.ToUpper(string(ep.Method)), ep.Path, getStringOrDefault(mime, "application/json"), payload)
+    return px
+}
+
+// describeSchema copies enum values, example and constraints from the schema,
+// looking at the items of an array.
+func describeSchema(px *ParameterExplanation, sm *spec.ServiceModel) {
+    s := px.Schema
+    if s == nil {
+        return
+    }
+    px.Enum, px.Example, px.Constraints = s.Enum, s.Example, schemaConstraints(s)
+    if s.Type == "array" && s.Items != nil {
+        if _, items := resolveSchema(sm, s.Items); items != nil && len(px.Enum) == 0 {
+            px.Enum = items.Enum
+        }
+    }
+}
+
+// requestBodySchema returns the resolved object schema of the first request
+// body media type that has properties.
+func requestBodySchema(sm *spec.ServiceModel, ep *spec.EndpointModel) (*spec.Schema, string) {
+    if ep.RequestBody == nil {
+        return nil, ""
+    }
+    for _, media := range ep.RequestBody.Content {
+        if _, s := resolveSchema(sm, media.Schema); s != nil && len(s.Properties) > 0 {
+            return s, media.Mime
+        }
+    }
+    return nil, ""
+}
+
+// resolveSchema returns the referenced schema name, if any, and the schema.
+// A reference wins over the schema inlined next to it (parameters carry both).
+func resolveSchema(sm *spec.ServiceModel, sr *spec.SchemaOrRef) (string, *spec.Schema) {
+    if sr == nil {
+        return "", nil
+    }
+    if sr.Ref == nil {
+        return "", sr.Schema
+    }
+    name := strings.Replace(sr.Ref.Ref, "#/components/schemas/", "", 1)
+    name = strings.Replace(name, "#/definitions/", "", 1)
+    if s, ok := sm.Schemas[name]; ok {
+        return name, &s
+    }
+    return name, sr.Schema
+}
+
+func sortedProperties(s *spec.Schema) []string {
+    names := make([]string, 0, len(s.Properties))
+    for name := range s.Properties {
+        names = append(names, name)
+    }
+    spec.SortNames(names)
+    return names
+}
+
+// sampleValue picks a value to show in the usage snippet: the schema's
+// example, its first enum value, or a placeholder of the right type.
+func sampleValue(sm *spec.ServiceModel, s *spec.Schema, depth int) any {
+    if s == nil {
+        return "value"
+    }
+    if s.Example != nil {
+        return s.Example
+    }
+    if len(s.Enum) > 0 {
+        return s.Enum[0]
+    }
+    switch s.Type {
+    case "integer":
+        if s.Minimum != nil {
+            return int64(*s.Minimum)
+        }
+        return 1
+    case "number":
+        if s.Minimum != nil {
+            return *s.Minimum
+        }
+        return 1.5
+    case "boolean":
+        return true
+    case "array":
+        _, items := resolveSchema(sm, s.Items)
+        if items != nil && len(items.Enum) > 1 {
+            return []any{items.Enum[0], items.Enum[1]}
+        }
+        if depth > 2 {
+            return []any{}
+        }
+        return []any{sampleValue(sm, items, depth+1)}
+    case "object":
+        obj := map[string]any{}
+        if depth > 2 {
+            return obj
+        }
+        for _, name := range sortedProperties(s) {
+            _, prop := resolveSchema(sm, s.Properties[name])
+            obj[name] = sampleValue(sm, prop, depth+1)
+            if len(obj) == 2 {
+                break
+            }
+        }
+        if len(obj) == 0 {
+            obj["key"] = "value"
+        }
+        return obj
+    }
+    switch s.Format {
+    case "date":
+        return "2024-01-01"
+    case "date-time":
+        return "2024-01-01T00:00:00Z"
+    case "uuid":
+        return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+    }
+    return "value"
+}
+
+// parameterUsage shows value serialized per the parameter's style and explode
+// settings, in the request line or as a header.
+func parameterUsage(ep *spec.EndpointModel, px *ParameterExplanation, value any) string {
+    method := strings.ToUpper(string(ep.Method))
+    switch px.In {
+    case "path":
+        var v string
+        switch px.Style {
+        case "label":
+            v = "." + joinValue(value, px.Explode, ".", url.PathEscape)
+        case "matrix":
+            v = matrixValue(px.Name, value, px.Explode)
+        default:
+            v = joinValue(value, px.Explode, ",", url.PathEscape)
+        }
+        return method + " " + strings.ReplaceAll(ep.Path, "{"+px.Name+"}", v)
+    case "header":
+        return fmt.Sprintf("%s %s\n%s: %s", method, ep.Path, px.Name, joinValue(value, px.Explode, ",", identity))
+    case "cookie":
+        return fmt.Sprintf("%s %s\nCookie: %s", method, ep.Path, queryValue(px, value))
+    }
+    return method + " " + ep.Path + "?" + queryValue(px, value)
+}
+
+// queryValue serializes a form, spaceDelimited, pipeDelimited or deepObject
+// parameter as it appears in a query string.
+func queryValue(px *ParameterExplanation, value any) string {
+    name := url.QueryEscape(px.Name)
+    switch v := value.(type) {
+    case []any:
+        parts := make([]string, 0, len(v))
+        for _, item := range v {
+            parts = append(parts, url.QueryEscape(scalarString(item)))
+        }
+        switch {
+        case px.Style == "spaceDelimited":
+            return name + "=" + strings.Join(parts, "%20")
+        case px.Style == "pipeDelimited":
+            return name + "=" + strings.Join(parts, "|")
+        case px.Explode:
+            return name + "=" + strings.Join(parts, "&"+name+"=")
+        }
+        return name + "=" + strings.Join(parts, ",")
+    case map[string]any:
+        keys := make([]string, 0, len(v))
+        for k := range v {
+            keys = append(keys, k)
+        }
+        sort.Strings(keys)
+        parts := make([]string, 0, len(keys))
+        for _, k := range keys {
+            val := url.QueryEscape(scalarString(v[k]))
+            switch {
+            case px.Style == "deepObject":
+                parts = append(parts, name+"["+url.QueryEscape(k)+"]="+val)
+            case px.Explode:
+                parts = append(parts, url.QueryEscape(k)+"="+val)
+            default:
+                parts = append(parts, url.QueryEscape(k)+","+val)
+            }
+        }
+        if px.Style == "deepObject" || px.Explode {
+            return strings.Join(parts, "&")
+        }
+        return name + "=" + strings.Join(parts, ",")
+    }
+    return name + "=" + url.QueryEscape(scalarString(value))
+}
+
+// joinValue serializes a simple or label style value, joining array items
+// and object members with sep.
+func joinValue(value any, explode bool, sep string, escape func(string) string) string {
+    switch v := value.(type) {
+    case []any:
+        parts := make([]string, 0, len(v))
+        for _, item := range v {
+            parts = append(parts, escape(scalarString(item)))
+        }
+        return strings.Join(parts, sep)
+    case map[string]any:
+        keys := make([]string, 0, len(v))
+        for k := range v {
+            keys = append(keys, k)
+        }
+        sort.Strings(keys)
+        parts := make([]string, 0, len(keys))
+        for _, k := range keys {
+            if explode {
+                parts = append(parts, escape(k)+"="+escape(scalarString(v[k])))
+            } else {
+                parts = append(parts, escape(k), escape(scalarString(v[k])))
+            }
+        }
+        return strings.Join(parts, sep)
+    }
+    return escape(scalarString(value))
+}
+
+func matrixValue(name string, value any, explode bool) string {
+    if items, ok := value.([]any); ok && explode {
+        parts := make([]string, 0, len(items))
+        for _, item := range items {
+            parts = append(parts, ";"+name+"="+url.PathEscape(scalarString(item)))
+        }
+        return strings.Join(parts, "")
+    }
+    return ";" + name + "=" + joinValue(value, explode, ",", url.PathEscape)
+}
+
+func identity(s string) string { return s }
+
+func scalarString(v any) string {
+    if s, ok := v.(string); ok {
+        return s
+    }
+    b, err := json.Marshal(v)
+    if err != nil {
+        return fmt.Sprint(v)
+    }
+    return string(b)
+}
+
+// notFound builds the error for an unknown name, suggesting the closest
+// candidates, or all of them when listAll is set and none is close.
+func notFound(msg, name string, candidates []string, listAll bool) error {
+    suggestions := closestNames(name, candidates)
+    if len(suggestions) == 0 && listAll {
+        suggestions = candidates
+    }
+    if len(suggestions) == 0 {
+        return fmt.Errorf("%s; use searchEndpoints to find it", msg)
+    }
+    return fmt.Errorf("%s; did you mean: %s", msg, strings.Join(suggestions, ", "))
+}
+
+// closestNames returns up to five candidates that contain name, are contained
+// in it, or are within a small edit distance, closest first.
+func closestNames(name string, candidates []string) []string {
+    type scored struct {
+        name string
+        dist int
+    }
+    want := strings.ToLower(strings.TrimSpace(name))
+    if want == "" {
+        return nil
+    }
+    limit := len(want) / 3
+    if limit < 2 {
+        limit = 2
+    }
+    var matches []scored
+    for _, c := range candidates {
+        lc := strings.ToLower(c)
+        d := editDistance(want, lc)
+        if d <= limit || strings.Contains(lc, want) || strings.Contains(want, lc) {
+            matches = append(matches, scored{c, d})
+        }
+    }
+    sort.SliceStable(matches, func(i, j int) bool { return matches[i].dist < matches[j].dist })
+    out := make([]string, 0, 5)
+    for _, s := range matches {
+        if len(out) == 5 {
+            break
+        }
+        out = append(out, s.name)
+    }
     return out
 }
 
-// FormatEndpointsOverview formats a comprehensive overview of all endpoints
-func FormatEndpointsOverview(sm *spec.ServiceModel) string {
-    endpoints := ListEndpoints(sm)
-    if len(endpoints) == 0 {
-        return "无可用接口"
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+    ra, rb := []rune(a), []rune(b)
+    prev := make([]int, len(rb)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+    for i := 1; i <= len(ra); i++ {
+        cur := make([]int, len(rb)+1)
+        cur[0] = i
+        for j := 1; j <= len(rb); j++ {
+            cost := 1
+            if ra[i-1] == rb[j-1] {
+                cost = 0
+            }
+            cur[j] = prev[j-1] + cost
+            if prev[j]+1 < cur[j] {
+                cur[j] = prev[j] + 1
+            }
+            if cur[j-1]+1 < cur[j] {
+                cur[j] = cur[j-1] + 1
+            }
+        }
+        prev = cur
     }
+    return prev[len(rb)]
+}
+`)
+}
+
+func renderGeneratedTests(data templateData) string {
+	handlerTests := generatedHandlerTestsGo
+	if data.invoke {
+		handlerTests = strings.Replace(handlerTests, "\nfunc (stubHandler) ExplainParameter(", `
+func (stubHandler) CallEndpoint(ctx context.Context, args methods.CallArgs) (*methods.CallResult, error) {
+    return &methods.CallResult{Status: 204}, nil
+}
+
+func (stubHandler) ExplainParameter(`, 1)
+	}
+	if data.mocks {
+		return data.render(strings.Replace(handlerTests,
+			`    server "{{MODULE}}/internal/mcp"
+`, `    server "{{MODULE}}/internal/mcp"
+    mocks "{{MODULE}}/internal/mcp/mocks"
+`, 1) + generatedMockTestsGo + generatedExplainTestsGo + generatedDetailsTestsGo)
+	}
+	if data.interfaces {
+		return data.render(handlerTests + generatedExplainTestsGo + generatedDetailsTestsGo)
+	}
+	return data.render(`package tests
+
+import (
+    "strings"
+    "testing"
+
+    methods "` + "{{MODULE}}" + `/internal/mcp/methods"
+    "` + "{{MODULE}}" + `/internal/spec"
+)
+
+func Test_ListAndSearch(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
 
-    var lines []string
-    lines = append(lines, fmt.Sprintf("API 接口概览 (%d 个接口)", len(endpoints)))
-    lines = append(lines, "")
+    overview := methods.FormatEndpointsOverview(sm)
+    if overview == "" { t.Fatalf("expected overview text, got empty") }
 
-    // 按标签分组统计
-    tagStats := make(map[string]int)
-    methodStats := make(map[string]int)
+    // Use a more flexible search test - try a common keyword first
+    searchKeywords := []string{"get", "list", "api", "user", "data"}
+    var res []methods.EndpointSearchResult
     
-    for _, ep := range endpoints {
-        methodStats[ep.Method]++
-        for _, tag := range ep.Tags {
-            tagStats[tag]++
+    for _, keyword := range searchKeywords {
+        res = methods.SearchEndpoints(sm, methods.SearchQuery{Keyword: keyword})
+        if res != nil && len(res) > 0 {
+            break
         }
     }
     
-    // 显示方法统计
-    lines = append(lines, "HTTP 方法分布:")
-    for method, count := range methodStats {
-        lines = append(lines, fmt.Sprintf("  %s: %d 个接口", strings.ToUpper(method), count))
-    }
-    lines = append(lines, "")
-    
-    // 显示标签统计 (取前10个)
-    if len(tagStats) > 0 {
-        lines = append(lines, "服务模块分布:")
-        // 按数量排序
-        type tagCount struct {
-            tag   string
-            count int
+    if res == nil { t.Fatalf("expected a result list, got nil") }
+}
+
+func Test_SchemaDetails(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+    schemas := methods.ListSchemas(sm)
+    if len(schemas) > 0 {
+        if _, ok := methods.GetSchemaDetails(sm, schemas[0].Name); !ok {
+            t.Fatalf("schema details not found")
         }
-        var tagList []tagCount
-        for tag, count := range tagStats {
-            tagList = append(tagList, tagCount{tag, count})
+    } else {
+        if _, ok := methods.GetSchemaDetails(sm, "__nonexistent__"); ok {
+            t.Fatalf("unexpected schema found")
         }
-        sort.Slice(tagList, func(i, j int) bool {
-            return tagList[i].count > tagList[j].count
+    }
+}
+` + generatedExplainTestsGo + generatedDetailsTestsGo)
+}
+
+// generatedExplainTestsGo checks explainParameter on the first query
+// parameter with an enum and the first path parameter of the embedded model.
+const generatedExplainTestsGo = `
+func Test_ExplainParameter(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+    find := func(match func(p spec.ParameterModel) bool) (string, string) {
+        for _, ep := range sm.Endpoints {
+            for _, p := range ep.Parameters {
+                if match(p) { return ep.ID, p.Name }
+            }
+        }
+        return "", ""
+    }
+
+    t.Run("query enum", func(t *testing.T) {
+        id, name := find(func(p spec.ParameterModel) bool {
+            return p.In == "query" && p.Schema != nil && p.Schema.Schema != nil && len(p.Schema.Schema.Enum) > 0
         })
-        
-        // 显示前10个最多的标签
-        maxShow := 10
-        if len(tagList) < maxShow {
-            maxShow = len(tagList)
+        if id == "" { t.Skip("no query parameter with an enum") }
+        px, err := methods.ExplainParameter(sm, id, name)
+        if err != nil { t.Fatalf("explain %s %s: %v", id, name, err) }
+        if px.In != "query" || len(px.Enum) == 0 || !strings.Contains(px.Usage, "?"+name+"=") {
+            t.Fatalf("unexpected explanation: %+v", px)
         }
-        for i := 0; i < maxShow; i++ {
-            lines = append(lines, fmt.Sprintf("  %s: %d 个接口", tagList[i].tag, tagList[i].count))
+        if text := methods.FormatParameterExplanation(px, sm); !strings.Contains(text, "允许值") {
+            t.Fatalf("enum values missing:\n%s", text)
         }
-        if len(tagList) > 10 {
-            lines = append(lines, fmt.Sprintf("  ... 还有 %d 个其他服务模块", len(tagList)-10))
+    })
+
+    t.Run("path", func(t *testing.T) {
+        id, name := find(func(p spec.ParameterModel) bool { return p.In == "path" })
+        if id == "" { t.Skip("no path parameter") }
+        px, err := methods.ExplainParameter(sm, id, name)
+        if err != nil { t.Fatalf("explain %s %s: %v", id, name, err) }
+        if px.In != "path" || !px.Required || strings.Contains(px.Usage, "{"+name+"}") {
+            t.Fatalf("unexpected explanation: %+v", px)
         }
-        lines = append(lines, "")
-    }
-    
-    // 显示主要路由路径
-    lines = append(lines, "主要路由路径:")
-    pathPrefixes := make(map[string]int)
-    for _, ep := range endpoints {
-        // 提取路径前缀 (前两级路径)
-        parts := strings.Split(strings.Trim(ep.Path, "/"), "/")
-        if len(parts) >= 2 {
-            prefix := "/" + parts[0] + "/" + parts[1]
-            pathPrefixes[prefix]++
-        } else if len(parts) == 1 && parts[0] != "" {
-            prefix := "/" + parts[0]
-            pathPrefixes[prefix]++
+        if _, err := methods.ExplainParameter(sm, id, name+"x"); err == nil || !strings.Contains(err.Error(), name) {
+            t.Fatalf("expected a suggestion for %q, got %v", name, err)
         }
-    }
-    
-    // 按数量排序路径前缀
-    type pathCount struct {
-        path  string
-        count int
-    }
-    var pathList []pathCount
-    for path, count := range pathPrefixes {
-        pathList = append(pathList, pathCount{path, count})
-    }
-    sort.Slice(pathList, func(i, j int) bool {
-        return pathList[i].count > pathList[j].count
     })
-    
-    // 显示前10个最常用的路径前缀
-    maxPaths := 10
-    if len(pathList) < maxPaths {
-        maxPaths = len(pathList)
+
+    if _, err := methods.ExplainParameter(sm, "get /__nonexistent__", "id"); err == nil {
+        t.Fatalf("unexpected endpoint found")
     }
-    for i := 0; i < maxPaths; i++ {
-        lines = append(lines, fmt.Sprintf("  %s: %d 个接口", pathList[i].path, pathList[i].count))
+}
+`
+
+// generatedDetailsTestsGo checks that endpoint details list parameters in
+// path, query, header, cookie order whatever order the spec declares them in.
+const generatedDetailsTestsGo = `
+func Test_EndpointDetailsGroupsParameters(t *testing.T) {
+    ep := &spec.EndpointModel{ID: "get /items/{id}", Method: "get", Path: "/items/{id}", Parameters: []spec.ParameterModel{
+        {Name: "session", In: "cookie"},
+        {Name: "X-Trace", In: "header"},
+        {Name: "limit", In: "query"},
+        {Name: "id", In: "path", Required: true},
+    }}
+    text := methods.FormatEndpointDetails(ep, &spec.ServiceModel{Endpoints: []spec.EndpointModel{*ep}})
+    last := -1
+    for _, section := range []string{"路径参数:\n  • id", "查询参数:\n  • limit", "请求头参数:\n  • X-Trace", "Cookie参数:\n  • session"} {
+        i := strings.Index(text, section)
+        if i < 0 || i < last { t.Fatalf("section %q missing or out of order:\n%s", section, text) }
+        last = i
     }
-    if len(pathList) > 10 {
-        lines = append(lines, fmt.Sprintf("  ... 还有 %d 个其他路径", len(pathList)-10))
+}
+`
+
+// generatedHandlerTestsGo exercises the tools through the Handler interface:
+// the default handler against the embedded model, and a stub wired into the
+// server in its place.
+const generatedHandlerTestsGo = `package tests
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    mcpgo "github.com/mark3labs/mcp-go/mcp"
+
+    server "{{MODULE}}/internal/mcp"
+    methods "{{MODULE}}/internal/mcp/methods"
+    "{{MODULE}}/internal/spec"
+)
+
+type stubHandler struct{}
+
+var _ server.Handler = stubHandler{}
+
+func (stubHandler) ListEndpoints() string { return "stub overview" }
+
+func (stubHandler) SearchEndpoints(q methods.SearchQuery) []methods.EndpointSearchResult { return nil }
+
+func (stubHandler) GetEndpointDetails(id, method, path string) (*spec.EndpointModel, string, bool) {
+    return &spec.EndpointModel{ID: "stub"}, "stub endpoint", true
+}
+
+func (stubHandler) ListSchemas() []methods.SchemaSummary { return nil }
+
+func (stubHandler) GetSchemaDetails(name string) (*spec.Schema, string, bool) { return nil, "", false }
+
+func (stubHandler) ExplainParameter(endpointID, name string) (*methods.ParameterExplanation, string, error) {
+    return &methods.ParameterExplanation{Name: name}, "stub parameter", nil
+}
+
+func Test_DefaultHandler(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+    var h server.Handler = server.NewHandler(sm)
+
+    if h.ListEndpoints() == "" { t.Fatalf("expected overview text, got empty") }
+    if len(sm.Endpoints) > 0 {
+        ep := sm.Endpoints[0]
+        if _, text, ok := h.GetEndpointDetails(ep.ID, "", ""); !ok || text == "" {
+            t.Fatalf("endpoint %s not found", ep.ID)
+        }
     }
-    lines = append(lines, "")
-    
-    // 显示所有接口端点列表 (功能总结和路由)
-    lines = append(lines, "所有接口端点:")
-    for _, ep := range endpoints {
-        summary := ep.Summary
-        if summary == "" {
-            summary = "无描述"
+    if schemas := h.ListSchemas(); len(schemas) > 0 {
+        if _, _, ok := h.GetSchemaDetails(schemas[0].Name); !ok {
+            t.Fatalf("schema details not found")
         }
-        lines = append(lines, fmt.Sprintf("  %s %s - %s", strings.ToUpper(ep.Method), ep.Path, summary))
+    } else if _, _, ok := h.GetSchemaDetails("__nonexistent__"); ok {
+        t.Fatalf("unexpected schema found")
     }
-    
-    return strings.Join(lines, "\n")
 }
-`)
+
+func Test_ServerUsesHandler(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+    srv := server.NewMCPServerWithHandler(sm, stubHandler{})
+
+    tool := srv.GetTool("listEndpoints")
+    if tool == nil { t.Fatalf("listEndpoints not registered") }
+    res, err := tool.Handler(context.Background(), mcpgo.CallToolRequest{})
+    if err != nil { t.Fatalf("call listEndpoints: %v", err) }
+    if text, ok := res.Content[0].(mcpgo.TextContent); !ok || text.Text != "stub overview" {
+        t.Fatalf("expected stub overview, got %+v", res.Content)
+    }
+
+    tool = srv.GetTool("getSchemaDetails")
+    if tool == nil { t.Fatalf("getSchemaDetails not registered") }
+    res, err = tool.Handler(context.Background(), mcpgo.CallToolRequest{})
+    if err != nil { t.Fatalf("call getSchemaDetails: %v", err) }
+    if !res.IsError { t.Fatalf("expected the stub's not-found result") }
 }
+`
 
-func renderSearchEndpointsGo(data templateData) string {
+// generatedMockTestsGo is appended to the handler tests when mocks are
+// generated: a table of tool calls answered by a programmed MockHandler.
+const generatedMockTestsGo = `
+func Test_ToolsWithMockHandler(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+
+    cases := []struct {
+        name    string
+        tool    string
+        args    map[string]any
+        setup   func(m *mocks.MockHandler)
+        want    string
+        isError bool
+    }{
+        {
+            name:  "overview",
+            tool:  "listEndpoints",
+            setup: func(m *mocks.MockHandler) { m.On("ListEndpoints").Return("mock overview") },
+            want:  "mock overview",
+        },
+        {
+            name: "search",
+            tool: "searchEndpoints",
+            args: map[string]any{"Keyword": "pets"},
+            setup: func(m *mocks.MockHandler) {
+                m.On("SearchEndpoints", methods.SearchQuery{Keyword: "pets"}).Return([]methods.EndpointSearchResult{{ID: "get /pets"}})
+            },
+            want: "1 matches",
+        },
+        {
+            name: "endpoint by id",
+            tool: "getEndpointDetails",
+            args: map[string]any{"ID": "get /pets"},
+            setup: func(m *mocks.MockHandler) {
+                m.On("GetEndpointDetails", "get /pets", "", "").Return(&spec.EndpointModel{ID: "get /pets"}, "mock endpoint", true)
+            },
+            want: "mock endpoint",
+        },
+        {
+            name: "endpoint not found",
+            tool: "getEndpointDetails",
+            args: map[string]any{"Method": "get", "Path": "/missing"},
+            setup: func(m *mocks.MockHandler) {
+                m.On("GetEndpointDetails", "", "get", "/missing").Return(nil, "", false)
+            },
+            want:    "endpoint not found",
+            isError: true,
+        },
+        {
+            name:  "schemas",
+            tool:  "listSchemas",
+            setup: func(m *mocks.MockHandler) { m.On("ListSchemas").Return([]methods.SchemaSummary{{Name: "Pet"}}) },
+            want:  "1 schemas",
+        },
+        {
+            name: "schema not found",
+            tool: "getSchemaDetails",
+            args: map[string]any{"Name": "Missing"},
+            setup: func(m *mocks.MockHandler) {
+                m.On("GetSchemaDetails", "Missing").Return(nil, "", false)
+            },
+            want:    "schema not found",
+            isError: true,
+        },
+        {
+            name: "explain parameter",
+            tool: "explainParameter",
+            args: map[string]any{"endpointId": "get /pets", "parameterName": "status"},
+            setup: func(m *mocks.MockHandler) {
+                m.On("ExplainParameter", "get /pets", "status").Return(&methods.ParameterExplanation{Name: "status"}, "mock parameter", nil)
+            },
+            want: "mock parameter",
+        },
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            h := &mocks.MockHandler{}
+            tc.setup(h)
+            tool := server.NewMCPServerWithHandler(sm, h).GetTool(tc.tool)
+            if tool == nil { t.Fatalf("%s not registered", tc.tool) }
+
+            req := mcpgo.CallToolRequest{}
+            req.Params.Arguments = tc.args
+            res, err := tool.Handler(context.Background(), req)
+            if err != nil { t.Fatalf("call %s: %v", tc.tool, err) }
+            text, _ := res.Content[0].(mcpgo.TextContent)
+            if res.IsError != tc.isError || text.Text != tc.want {
+                t.Fatalf("got %q (isError=%v), want %q (isError=%v)", text.Text, res.IsError, tc.want, tc.isError)
+            }
+            h.AssertExpectations(t)
+        })
+    }
+}
+`
+
+// sampleSpecYAML is a small sample used for testdata in the generated project.
+const sampleSpecYAML = "" +
+	"openapi: 3.0.0\n" +
+	"info:\n" +
+	"  title: Sample\n" +
+	"  version: '1.0.0'\n" +
+	"paths:\n" +
+	"  /pets:\n" +
+	"    get:\n" +
+	"      summary: List pets\n" +
+	"      tags: [read]\n" +
+	"      responses:\n" +
+	"        '200':\n" +
+	"          description: ok\n" +
+	"          content:\n" +
+	"            application/json:\n" +
+	"              schema:\n" +
+	"                type: array\n" +
+	"                items:\n" +
+	"                  type: string\n"
+
+// renderCallEndpointGo renders the callEndpoint method, which sends a request
+// described by the model to the upstream API (EnableInvoke).
+func renderCallEndpointGo(data templateData) string {
 	return data.render(`package methods
 
 import (
-    "regexp"
+    "bytes"
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "os"
     "sort"
+    "strconv"
     "strings"
+    "time"
 
     "` + "{{MODULE}}" + `/internal/spec"
 )
 
-type SearchQuery struct {
-    Keyword     string
-    Tag         string
-    Method      string // lowercased http verb
-    PathPattern string // regex
+// CallTimeoutEnv names an environment variable holding the timeout of one
+// callEndpoint request: a duration such as 10s or 500ms, or a number of
+// seconds.
+const CallTimeoutEnv = "API_TIMEOUT"
+
+// DefaultCallTimeout bounds a callEndpoint request when API_TIMEOUT is unset
+// or invalid.
+const DefaultCallTimeout = 30 * time.Second
+
+// CallRetriesEnv names an environment variable holding how many times
+// callEndpoint resends a request that failed, timed out or got a 429 or 5xx
+// response.
+const CallRetriesEnv = "API_RETRIES"
+
+// DefaultCallRetries is the retry count when API_RETRIES is unset or
+// invalid.
+const DefaultCallRetries = 0
+
+// CallRetryDelay is the wait before the first retry; it doubles for each
+// further retry, up to MaxCallRetryDelay.
+const (
+    CallRetryDelay    = 100 * time.Millisecond
+    MaxCallRetryDelay = 2 * time.Second
+)
+
+// MaxResponseBytes is how much of a response body callEndpoint returns; the
+// rest is dropped and the result marked truncated.
+const MaxResponseBytes = 64 << 10
+
+// Credential is how callEndpoint authenticates with one security scheme: the
+// secret read from EnvVar is sent as the In parameter Name.
+type Credential struct {
+    Kind   string // apiKey, bearer or basic (EnvVar holds user:password)
+    In     string // header, query or cookie
+    Name   string
+    EnvVar string
 }
 
-type EndpointSearchResult struct {
-    ID      string   ` + "`json:\"id\"`" + `
-    Method  string   ` + "`json:\"method\"`" + `
-    Path    string   ` + "`json:\"path\"`" + `
-    Summary string   ` + "`json:\"summary\"`" + `
-    Tags    []string ` + "`json:\"tags\"`" + `
+// Credentials holds the security schemes of the model callEndpoint can
+// apply, by name.
+` + goCredentials(data) + `
+
+// CallArgs are the arguments of the callEndpoint tool. Params holds path,
+// query, header and cookie parameters by name; each is sent where the
+// endpoint declares it.
+type CallArgs struct {
+    EndpointID string         ` + "`json:\"endpointId\"`" + `
+    Params     map[string]any ` + "`json:\"params,omitempty\"`" + `
+    Body       any            ` + "`json:\"body,omitempty\"`" + `
 }
 
-func SearchEndpoints(sm *spec.ServiceModel, q SearchQuery) []EndpointSearchResult {
-    var re *regexp.Regexp
-    if strings.TrimSpace(q.PathPattern) != "" {
-        var err error
-        re, err = regexp.Compile(q.PathPattern)
-        if err != nil {
-            // invalid pattern -> no matches
-            return nil
-        }
+// CallResult is the upstream response to a callEndpoint request.
+type CallResult struct {
+    Method    string            ` + "`json:\"method\"`" + `
+    URL       string            ` + "`json:\"url\"`" + `
+    Status    int               ` + "`json:\"status\"`" + `
+    Headers   map[string]string ` + "`json:\"headers\"`" + `
+    Body      string            ` + "`json:\"body\"`" + `
+    Truncated bool              ` + "`json:\"truncated\"`" + ` // Body holds only the first MaxResponseBytes
+}
+
+// CallOptions configure CallEndpoint. The zero value targets spec.BaseURL
+// with http.DefaultClient; every attempt is bounded by CallTimeout.
+type CallOptions struct {
+    BaseURL string
+    Client  *http.Client
+}
+
+// CallTimeout returns the request timeout from API_TIMEOUT, or
+// DefaultCallTimeout.
+func CallTimeout() time.Duration {
+    v := strings.TrimSpace(os.Getenv(CallTimeoutEnv))
+    if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+        return time.Duration(secs * float64(time.Second))
     }
-    kw := strings.ToLower(strings.TrimSpace(q.Keyword))
-    tag := strings.TrimSpace(q.Tag)
-    method := strings.ToLower(strings.TrimSpace(q.Method))
+    if d, err := time.ParseDuration(v); err == nil && d > 0 {
+        return d
+    }
+    return DefaultCallTimeout
+}
 
-    var out []EndpointSearchResult
-    for _, ep := range sm.Endpoints {
-        if method != "" && string(ep.Method) != method { continue }
-        if tag != "" {
-            found := false
-            for _, t := range ep.Tags { if t == tag { found = true; break } }
-            if !found { continue }
+// CallRetries returns the retry count from API_RETRIES, or
+// DefaultCallRetries.
+func CallRetries() int {
+    if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(CallRetriesEnv))); err == nil && n >= 0 {
+        return n
+    }
+    return DefaultCallRetries
+}
+
+// CallEndpoint validates args against the endpoint's parameters and request
+// body, then sends the request and returns the response. Nothing is sent
+// when validation fails. Each attempt is bounded by CallTimeout; a request
+// that fails, times out or gets a 429 or 5xx response is resent up to
+// CallRetries times, and the last outcome is returned. Non-2xx responses are
+// results, not errors.
+func CallEndpoint(ctx context.Context, sm *spec.ServiceModel, args CallArgs, opts CallOptions) (*CallResult, error) {
+    req, err := BuildRequest(ctx, sm, args, opts.BaseURL)
+    if err != nil {
+        return nil, err
+    }
+    client := opts.Client
+    if client == nil {
+        client = http.DefaultClient
+    }
+    timeout, retries := CallTimeout(), CallRetries()
+    delay := CallRetryDelay
+    for attempt := 0; ; attempt++ {
+        res, err := send(ctx, client, req, timeout)
+        retry := err != nil || res.Status == http.StatusTooManyRequests || res.Status >= 500
+        if !retry || attempt >= retries || ctx.Err() != nil {
+            return res, err
         }
-        if re != nil && !re.MatchString(ep.Path) { continue }
-        if kw != "" {
-            text := strings.ToLower(ep.Summary + "\n" + ep.Description + "\n" + ep.Path)
-            if !strings.Contains(text, kw) { continue }
+        select {
+        case <-ctx.Done():
+            return res, err
+        case <-time.After(delay):
         }
-        out = append(out, EndpointSearchResult{ID: ep.ID, Method: string(ep.Method), Path: ep.Path, Summary: ep.Summary, Tags: append([]string(nil), ep.Tags...)})
+        if delay *= 2; delay > MaxCallRetryDelay {
+            delay = MaxCallRetryDelay
+        }
+    }
+}
+
+// send makes one attempt at req, bounded by timeout.
+func send(ctx context.Context, client *http.Client, req *http.Request, timeout time.Duration) (*CallResult, error) {
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+    attempt := req.Clone(ctx)
+    if req.GetBody != nil {
+        body, err := req.GetBody()
+        if err != nil {
+            return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+        }
+        attempt.Body = body
+    }
+    resp, err := client.Do(attempt)
+    if err != nil {
+        return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+    }
+    defer resp.Body.Close()
+    body, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBytes+1))
+    if err != nil {
+        return nil, fmt.Errorf("%s %s: read response: %w", req.Method, req.URL, err)
+    }
+    res := &CallResult{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode, Headers: map[string]string{}}
+    for name, values := range resp.Header {
+        res.Headers[name] = strings.Join(values, ", ")
+    }
+    if len(body) > MaxResponseBytes {
+        body, res.Truncated = body[:MaxResponseBytes], true
     }
-    sort.Slice(out, func(i, j int) bool {
-        if out[i].Path == out[j].Path { return out[i].Method < out[j].Method }
-        return out[i].Path < out[j].Path
-    })
-    return out
+    res.Body = string(body)
+    return res, nil
 }
-`)
-}
-
-func renderUtilsGo(data templateData) string {
-	return data.render(`package methods
 
-import (
-    "encoding/json"
-    "fmt"
-    "strings"
+// BuildRequest builds the HTTP request for args against baseURL, or
+// spec.BaseURL(sm) when baseURL is empty. It fails on an unknown endpoint or
+// parameter, a missing required parameter, body or credential, and a base
+// URL that is not absolute.
+func BuildRequest(ctx context.Context, sm *spec.ServiceModel, args CallArgs, baseURL string) (*http.Request, error) {
+    ep := findEndpoint(sm, args.EndpointID)
+    if ep == nil {
+        ids := make([]string, 0, len(sm.Endpoints))
+        for _, e := range sm.Endpoints {
+            ids = append(ids, e.ID)
+        }
+        return nil, notFound(fmt.Sprintf("endpoint %q not found", args.EndpointID), args.EndpointID, ids, false)
+    }
 
-    "{{MODULE}}/internal/spec"
-)
+    known := map[string]spec.ParameterModel{}
+    var names, missing []string
+    for _, p := range ep.Parameters {
+        if _, dup := known[p.Name]; dup {
+            continue
+        }
+        known[p.Name] = p
+        names = append(names, p.Name)
+        if _, ok := args.Params[p.Name]; !ok && (p.Required || p.In == "path") {
+            missing = append(missing, fmt.Sprintf("%s (%s)", p.Name, p.In))
+        }
+    }
+    given := make([]string, 0, len(args.Params))
+    for name := range args.Params {
+        given = append(given, name)
+    }
+    sort.Strings(given)
+    for _, name := range given {
+        if _, ok := known[name]; !ok {
+            return nil, notFound(fmt.Sprintf("parameter %q not found on %s", name, ep.ID), name, names, true)
+        }
+    }
+    if len(missing) > 0 {
+        return nil, fmt.Errorf("%s: missing required parameters: %s", ep.ID, strings.Join(missing, ", "))
+    }
+    if ep.RequestBody != nil && ep.RequestBody.Required && args.Body == nil {
+        return nil, fmt.Errorf("%s: a request body is required", ep.ID)
+    }
 
-// formatSchemaWithRefs recursively formats schema with reference resolution
-func formatSchemaWithRefs(schema *spec.Schema, sm *spec.ServiceModel, indent string) []string {
-    var lines []string
-    
-    if schema == nil {
-        return lines
+    if baseURL == "" {
+        baseURL = spec.BaseURL(sm)
     }
-    
-    if schema.Type != "" {
-        lines = append(lines, fmt.Sprintf("%s类型: %s", indent, schema.Type))
+    base, err := url.Parse(strings.TrimSpace(baseURL))
+    if err != nil || base.Scheme == "" || base.Host == "" {
+        return nil, fmt.Errorf("base URL %q is not an absolute URL; set %s", baseURL, spec.BaseURLEnv)
     }
-    
-    if len(schema.Properties) > 0 {
-        lines = append(lines, fmt.Sprintf("%s属性:", indent))
-        for propName, propSchema := range schema.Properties {
-            isRequired := "[可选]"
-            for _, req := range schema.Required {
-                if req == propName {
-                    isRequired = "[必需]"
-                    break
-                }
+
+    path := ep.Path
+    query := url.Values{}
+    header := http.Header{}
+    var cookies []string
+    for _, name := range given {
+        p := known[name]
+        values := paramValues(args.Params[name])
+        switch p.In {
+        case "path":
+            escaped := make([]string, len(values))
+            for i, v := range values {
+                escaped[i] = url.PathEscape(v)
             }
-            
-            if propSchema.Schema != nil {
-                // Direct schema
-                propType := getStringOrDefault(propSchema.Schema.Type, "unknown")
-                if propType == "array" {
-                    // Handle array property with items
-                    if propSchema.Schema.Items != nil && propSchema.Schema.Items.Ref != nil {
-                        refName := strings.Replace(propSchema.Schema.Items.Ref.Ref, "#/components/schemas/", "", 1)
-                        refName = strings.Replace(refName, "#/definitions/", "", 1)
-                        lines = append(lines, fmt.Sprintf("%s  • %s: %s<%s> %s", indent, propName, propType, refName, isRequired))
-                        
-                        // Try to resolve the reference
-                        if sm.Schemas != nil {
-                            if refSchema, exists := sm.Schemas[refName]; exists {
-                                lines = append(lines, fmt.Sprintf("%s    └─ %s 详情:", indent, refName))
-                                refLines := formatSchemaWithRefs(&refSchema, sm, indent+"      ")
-                                lines = append(lines, refLines...)
-                            }
-                        }
-                    } else if propSchema.Schema.Items != nil && propSchema.Schema.Items.Schema != nil {
-                        itemType := getStringOrDefault(propSchema.Schema.Items.Schema.Type, "unknown")
-                        lines = append(lines, fmt.Sprintf("%s  • %s: %s<%s> %s", indent, propName, propType, itemType, isRequired))
-                    } else {
-                        lines = append(lines, fmt.Sprintf("%s  • %s: %s %s", indent, propName, propType, isRequired))
-                    }
-                } else {
-                    lines = append(lines, fmt.Sprintf("%s  • %s: %s %s", indent, propName, propType, isRequired))
-                }
-                
-                if len(propSchema.Schema.Enum) > 0 {
-                    enumBytes, _ := json.Marshal(propSchema.Schema.Enum)
-                    lines = append(lines, fmt.Sprintf("%s    允许值: %s", indent, string(enumBytes)))
-                }
-            } else if propSchema.Ref != nil {
-                // Reference to another schema
-                refName := strings.Replace(propSchema.Ref.Ref, "#/components/schemas/", "", 1)
-                refName = strings.Replace(refName, "#/definitions/", "", 1)
-                lines = append(lines, fmt.Sprintf("%s  • %s: %s (引用) %s", indent, propName, refName, isRequired))
-                
-                // Try to resolve the reference
-                if sm.Schemas != nil {
-                    if refSchema, exists := sm.Schemas[refName]; exists {
-                        lines = append(lines, fmt.Sprintf("%s    └─ %s 详情:", indent, refName))
-                        refLines := formatSchemaWithRefs(&refSchema, sm, indent+"      ")
-                        lines = append(lines, refLines...)
-                    }
-                }
+            path = strings.ReplaceAll(path, "{"+name+"}", strings.Join(escaped, ","))
+        case "query":
+            explode := p.Style == "" || p.Style == "form"
+            if p.Explode != nil {
+                explode = *p.Explode
+            }
+            if explode {
+                query[name] = append(query[name], values...)
             } else {
-                lines = append(lines, fmt.Sprintf("%s  • %s: unknown %s", indent, propName, isRequired))
+                query.Set(name, strings.Join(values, queryDelimiter(p.Style)))
             }
+        case "header":
+            header.Set(name, strings.Join(values, ","))
+        case "cookie":
+            cookies = append(cookies, name+"="+url.QueryEscape(strings.Join(values, ",")))
         }
     }
-    
-    if schema.Items != nil {
-        if schema.Items.Schema != nil {
-            itemType := getStringOrDefault(schema.Items.Schema.Type, "unknown")
-            lines = append(lines, fmt.Sprintf("%s数组元素类型: %s", indent, itemType))
-            itemLines := formatSchemaWithRefs(schema.Items.Schema, sm, indent+"  ")
-            lines = append(lines, itemLines...)
-        } else if schema.Items.Ref != nil {
-            refName := strings.Replace(schema.Items.Ref.Ref, "#/components/schemas/", "", 1)
-            refName = strings.Replace(refName, "#/definitions/", "", 1)
-            lines = append(lines, fmt.Sprintf("%s数组元素类型: %s (引用)", indent, refName))
-            
-            // Try to resolve the reference
-            if sm.Schemas != nil {
-                if refSchema, exists := sm.Schemas[refName]; exists {
-                    lines = append(lines, fmt.Sprintf("%s└─ %s 详情:", indent, refName))
-                    refLines := formatSchemaWithRefs(&refSchema, sm, indent+"  ")
-                    lines = append(lines, refLines...)
-                }
-            }
+    if err := applyCredentials(ep, query, header, &cookies); err != nil {
+        return nil, err
+    }
+    target := strings.TrimSuffix(base.String(), "/") + path
+    if len(query) > 0 {
+        target += "?" + query.Encode()
+    }
+
+    var body io.Reader
+    if args.Body != nil {
+        raw, err := json.Marshal(args.Body)
+        if err != nil {
+            return nil, fmt.Errorf("%s: encode body: %w", ep.ID, err)
+        }
+        body = bytes.NewReader(raw)
+        if header.Get("Content-Type") == "" {
+            header.Set("Content-Type", "application/json")
         }
     }
-    
-    if len(schema.AllOf) > 0 {
-        lines = append(lines, fmt.Sprintf("%s组合类型 (AllOf):", indent))
-        for i, subSchema := range schema.AllOf {
-            lines = append(lines, fmt.Sprintf("%s  %d. ", indent, i+1))
-            if subSchema.Schema != nil {
-                subLines := formatSchemaWithRefs(subSchema.Schema, sm, indent+"    ")
-                lines = append(lines, subLines...)
-            } else if subSchema.Ref != nil {
-                refName := strings.Replace(subSchema.Ref.Ref, "#/components/schemas/", "", 1)
-                refName = strings.Replace(refName, "#/definitions/", "", 1)
-                lines = append(lines, fmt.Sprintf("%s    引用: %s", indent, refName))
-                if sm.Schemas != nil {
-                    if refSchema, exists := sm.Schemas[refName]; exists {
-                        refLines := formatSchemaWithRefs(&refSchema, sm, indent+"      ")
-                        lines = append(lines, refLines...)
-                    }
-                }
+    req, err := http.NewRequestWithContext(ctx, strings.ToUpper(string(ep.Method)), target, body)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", ep.ID, err)
+    }
+    for name, values := range header {
+        req.Header[name] = values
+    }
+    if len(cookies) > 0 {
+        req.Header.Set("Cookie", strings.Join(cookies, "; "))
+    }
+    if req.Header.Get("Accept") == "" {
+        req.Header.Set("Accept", "application/json, */*;q=0.8")
+    }
+    return req, nil
+}
+
+// FormatCallResult formats a response for the callEndpoint tool.
+func FormatCallResult(res *CallResult) string {
+    lines := []string{fmt.Sprintf("%s %s", res.Method, res.URL), fmt.Sprintf("HTTP %d %s", res.Status, http.StatusText(res.Status))}
+    names := make([]string, 0, len(res.Headers))
+    for name := range res.Headers {
+        names = append(names, name)
+    }
+    spec.SortNames(names)
+    for _, name := range names {
+        lines = append(lines, fmt.Sprintf("%s: %s", name, res.Headers[name]))
+    }
+    lines = append(lines, "", res.Body)
+    if res.Truncated {
+        lines = append(lines, fmt.Sprintf("... (truncated to %d bytes)", MaxResponseBytes))
+    }
+    return strings.Join(lines, "\n")
+}
+
+// applyCredentials adds the credentials of the first security alternative of
+// ep whose environment variables are all set. Parameters given explicitly
+// keep their values. It fails when ep requires credentials and no
+// alternative is met.
+func applyCredentials(ep *spec.EndpointModel, query url.Values, header http.Header, cookies *[]string) error {
+    anonymous := len(ep.Security) == 0
+    var options []string
+    for _, alt := range ep.Security {
+        if len(alt) == 0 {
+            anonymous = true
+            continue
+        }
+        var unset []string
+        supported := true
+        for _, scheme := range alt {
+            c, ok := Credentials[scheme]
+            if !ok {
+                supported = false
+                break
             }
+            if os.Getenv(c.EnvVar) == "" {
+                unset = append(unset, c.EnvVar)
+            }
+        }
+        if !supported {
+            continue
+        }
+        if len(unset) > 0 {
+            options = append(options, strings.Join(unset, " and "))
+            continue
+        }
+        for _, scheme := range alt {
+            applyCredential(Credentials[scheme], query, header, cookies)
         }
+        return nil
     }
-    
-    if len(schema.Enum) > 0 {
-        enumBytes, _ := json.Marshal(schema.Enum)
-        lines = append(lines, fmt.Sprintf("%s允许值: %s", indent, string(enumBytes)))
+    if anonymous {
+        return nil
     }
-    
-    return lines
+    if len(options) == 0 {
+        return fmt.Errorf("%s: missing credentials: no supported security scheme in %v", ep.ID, ep.Security)
+    }
+    return fmt.Errorf("%s: missing credentials: set %s", ep.ID, strings.Join(options, " or "))
 }
 
-func getStringOrDefault(s, def string) string {
-    if strings.TrimSpace(s) == "" {
-        return def
+func applyCredential(c Credential, query url.Values, header http.Header, cookies *[]string) {
+    value := os.Getenv(c.EnvVar)
+    switch c.Kind {
+    case "bearer":
+        value = "Bearer " + value
+    case "basic":
+        value = "Basic " + base64.StdEncoding.EncodeToString([]byte(value))
+    }
+    switch c.In {
+    case "query":
+        if _, ok := query[c.Name]; !ok {
+            query.Set(c.Name, value)
+        }
+    case "cookie":
+        for _, cookie := range *cookies {
+            if strings.HasPrefix(cookie, c.Name+"=") {
+                return
+            }
+        }
+        *cookies = append(*cookies, c.Name+"="+url.QueryEscape(value))
+    default:
+        if header.Get(c.Name) == "" {
+            header.Set(c.Name, value)
+        }
     }
-    return s
 }
 
-func getTagsOrDefault(tags []string) string {
-    if len(tags) == 0 {
-        return "无"
+// paramValues flattens a parameter value into its string forms: one per
+// array element, objects as JSON.
+func paramValues(v any) []string {
+    switch t := v.(type) {
+    case nil:
+        return []string{""}
+    case []any:
+        out := make([]string, 0, len(t))
+        for _, e := range t {
+            out = append(out, paramValues(e)...)
+        }
+        return out
+    case string:
+        return []string{t}
+    case float64:
+        return []string{strconv.FormatFloat(t, 'f', -1, 64)}
+    case bool, int, int64, json.Number:
+        return []string{fmt.Sprint(t)}
+    default:
+        raw, _ := json.Marshal(t)
+        return []string{string(raw)}
     }
-    return strings.Join(tags, ", ")
+}
+
+// queryDelimiter joins the values of a non-exploded query parameter.
+func queryDelimiter(style string) string {
+    switch style {
+    case "spaceDelimited":
+        return " "
+    case "pipeDelimited":
+        return "|"
+    }
+    return ","
 }
 `)
 }
 
-func renderGetEndpointDetailsGo(data templateData) string {
-	return data.render(`package methods
+// goCredentials declares the Credentials table of call_endpoint.go.
+func goCredentials(data templateData) string {
+	creds := emitter.Credentials(data.ToolName, data.service)
+	if len(creds) == 0 {
+		return "var Credentials = map[string]Credential{}"
+	}
+	lines := []string{"var Credentials = map[string]Credential{"}
+	for _, c := range creds {
+		lines = append(lines, fmt.Sprintf("    %q: {Kind: %q, In: %q, Name: %q, EnvVar: %q},", c.Scheme, c.Kind, c.In, c.Name, c.EnvVar))
+	}
+	return strings.Join(append(lines, "}"), "\n")
+}
+
+// renderCallEndpointTestsGo renders tests/call_endpoint_test.go, which calls
+// every endpoint of the embedded model against a local stub server.
+func renderCallEndpointTestsGo(data templateData) string {
+	return data.render(`package tests
 
 import (
-    "encoding/json"
-    "fmt"
+    "context"
+    "encoding/base64"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
     "strings"
+    "sync"
+    "testing"
+    "time"
 
-    "` + "{{MODULE}}" + `/internal/spec"
+    mcpgo "github.com/mark3labs/mcp-go/mcp"
+
+    server "{{MODULE}}/internal/mcp"
+    methods "{{MODULE}}/internal/mcp/methods"
+    "{{MODULE}}/internal/spec"
 )
 
-func GetEndpointDetails(sm *spec.ServiceModel, idOrMethod string, pathOptional ...string) (*spec.EndpointModel, bool) {
-    method := strings.ToLower(strings.TrimSpace(idOrMethod))
-    if len(pathOptional) == 0 {
-        // id is expected in form "<method> <path>"
-        for _, ep := range sm.Endpoints {
-            if ep.ID == idOrMethod { return &ep, true }
+// stubRequest is a request received by the stub API server.
+type stubRequest struct {
+    method, path, query, body string
+    header                    http.Header
+}
+
+// stubAPI answers every request with 418 and a small JSON body, recording
+// what it received.
+type stubAPI struct {
+    mu       sync.Mutex
+    requests []stubRequest
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    body, _ := io.ReadAll(r.Body)
+    s.mu.Lock()
+    s.requests = append(s.requests, stubRequest{r.Method, r.URL.EscapedPath(), r.URL.RawQuery, string(body), r.Header})
+    s.mu.Unlock()
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusTeapot)
+    _, _ = io.WriteString(w, "{\"ok\":true}")
+}
+
+func (s *stubAPI) take() []stubRequest {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    out := s.requests
+    s.requests = nil
+    return out
+}
+
+// counter counts the requests a test server received.
+type counter struct {
+    mu sync.Mutex
+    n  int
+}
+
+func (c *counter) add() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.n++
+    return c.n
+}
+
+func (c *counter) get() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return c.n
+}
+
+// sampleArgs gives every parameter of ep a placeholder value and adds a body
+// when ep takes one.
+func sampleArgs(ep spec.EndpointModel) methods.CallArgs {
+    args := methods.CallArgs{EndpointID: ep.ID, Params: map[string]any{}}
+    for _, p := range ep.Parameters {
+        args.Params[p.Name] = "v-" + p.Name
+    }
+    if ep.RequestBody != nil {
+        args.Body = map[string]any{"sample": true}
+    }
+    return args
+}
+
+// appliedAlternative returns the security alternative of ep callEndpoint
+// applies when every credential is set: the first whose schemes are all known.
+func appliedAlternative(ep spec.EndpointModel) []string {
+    for _, alt := range ep.Security {
+        known := len(alt) > 0
+        for _, scheme := range alt {
+            _, ok := methods.Credentials[scheme]
+            known = known && ok
         }
-        return nil, false
+        if known { return alt }
     }
-    path := pathOptional[0]
-    for _, ep := range sm.Endpoints {
-        if string(ep.Method) == method && ep.Path == path { return &ep, true }
+    return nil
+}
+
+// requiresCredentials reports whether ep cannot be called anonymously.
+func requiresCredentials(ep spec.EndpointModel) bool {
+    for _, alt := range ep.Security {
+        if len(alt) == 0 { return false }
     }
-    return nil, false
+    return len(ep.Security) > 0
 }
 
-// FormatEndpointDetails formats endpoint details with schema reference resolution
-func FormatEndpointDetails(ep *spec.EndpointModel, sm *spec.ServiceModel) string {
-    var lines []string
-    
-    // Basic info
-    lines = append(lines, fmt.Sprintf("%s %s", strings.ToUpper(string(ep.Method)), ep.Path))
-    lines = append(lines, fmt.Sprintf("摘要: %s", getStringOrDefault(ep.Summary, "无")))
-    lines = append(lines, fmt.Sprintf("描述: %s", getStringOrDefault(ep.Description, "无")))
-    lines = append(lines, fmt.Sprintf("标签: %s", getTagsOrDefault(ep.Tags)))
-    
-    // Parameters
-    if len(ep.Parameters) > 0 {
-        lines = append(lines, "", "参数:")
-        for _, param := range ep.Parameters {
-            required := "[可选]"
-            if param.Required {
-                required = "[必需]"
+// declares reports whether ep has a parameter that overrides a credential.
+func declares(ep spec.EndpointModel, in, name string) bool {
+    for _, p := range ep.Parameters {
+        if p.In == in && strings.EqualFold(p.Name, name) { return true }
+    }
+    return false
+}
+
+func Test_CallEndpoint(t *testing.T) {
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+    if len(sm.Endpoints) == 0 { t.Skip("no endpoints") }
+    api := &stubAPI{}
+    stub := httptest.NewServer(api)
+    defer stub.Close()
+    ctx := context.Background()
+    opts := methods.CallOptions{BaseURL: stub.URL + "/base"}
+    for _, c := range methods.Credentials {
+        t.Setenv(c.EnvVar, "secret-"+c.EnvVar)
+    }
+
+    t.Run("parameter substitution", func(t *testing.T) {
+        for _, ep := range sm.Endpoints {
+            res, err := methods.CallEndpoint(ctx, sm, sampleArgs(ep), opts)
+            if err != nil { t.Fatalf("%s: %v", ep.ID, err) }
+            if res.Status != http.StatusTeapot { t.Fatalf("%s: status %d, want 418", ep.ID, res.Status) }
+            got := api.take()
+            if len(got) != 1 { t.Fatalf("%s: expected one request, got %d", ep.ID, len(got)) }
+            r := got[0]
+            if r.method != strings.ToUpper(string(ep.Method)) || !strings.HasPrefix(r.path, "/base") {
+                t.Fatalf("%s: got %s %s", ep.ID, r.method, r.path)
             }
-            paramType := "unknown"
-            enumValues := ""
-            if param.Schema != nil && param.Schema.Schema != nil {
-                paramType = getStringOrDefault(param.Schema.Schema.Type, "unknown")
-                if len(param.Schema.Schema.Enum) > 0 {
-                    enumBytes, _ := json.Marshal(param.Schema.Schema.Enum)
-                    enumValues = fmt.Sprintf(" (允许值: %s)", string(enumBytes))
+            for _, p := range ep.Parameters {
+                want := "v-" + p.Name
+                switch p.In {
+                case "path":
+                    if strings.Contains(r.path, "{"+p.Name+"}") || !strings.Contains(r.path, url.PathEscape(want)) {
+                        t.Errorf("%s: path parameter %s not substituted in %s", ep.ID, p.Name, r.path)
+                    }
+                case "query":
+                    if q, _ := url.ParseQuery(r.query); q.Get(p.Name) != want {
+                        t.Errorf("%s: query %q lacks %s=%s", ep.ID, r.query, p.Name, want)
+                    }
+                case "header":
+                    if !strings.EqualFold(p.Name, "Host") && r.header.Get(p.Name) != want {
+                        t.Errorf("%s: header %s = %q, want %q", ep.ID, p.Name, r.header.Get(p.Name), want)
+                    }
                 }
             }
-            lines = append(lines, fmt.Sprintf("  • %s (%s) - %s%s %s", param.Name, param.In, paramType, enumValues, required))
+            if ep.RequestBody != nil && !strings.Contains(r.body, "\"sample\":true") {
+                t.Errorf("%s: body %q not sent", ep.ID, r.body)
+            }
         }
-    }
-    
-    // Request body
-    if ep.RequestBody != nil {
-        lines = append(lines, "", "请求体:")
-        required := "[可选]"
-        if ep.RequestBody.Required {
-            required = "[必需]"
+    })
+
+    t.Run("validation before sending", func(t *testing.T) {
+        if _, err := methods.CallEndpoint(ctx, sm, methods.CallArgs{EndpointID: "get /__missing__"}, opts); err == nil {
+            t.Errorf("expected an error for an unknown endpoint")
         }
-        
-        contentTypes := make([]string, 0)
-        for _, content := range ep.RequestBody.Content {
-            contentTypes = append(contentTypes, content.Mime)
+        for _, ep := range sm.Endpoints {
+            for _, p := range ep.Parameters {
+                if !p.Required && p.In != "path" { continue }
+                args := sampleArgs(ep)
+                delete(args.Params, p.Name)
+                if _, err := methods.CallEndpoint(ctx, sm, args, opts); err == nil || !strings.Contains(err.Error(), p.Name) {
+                    t.Errorf("%s: expected a missing %s error, got %v", ep.ID, p.Name, err)
+                }
+            }
+            args := sampleArgs(ep)
+            args.Params["__unknown__"] = "x"
+            if _, err := methods.CallEndpoint(ctx, sm, args, opts); err == nil {
+                t.Errorf("%s: expected an unknown parameter error", ep.ID)
+            }
+            if ep.RequestBody != nil && ep.RequestBody.Required {
+                args := sampleArgs(ep)
+                args.Body = nil
+                if _, err := methods.CallEndpoint(ctx, sm, args, opts); err == nil {
+                    t.Errorf("%s: expected a missing body error", ep.ID)
+                }
+            }
         }
-        contentTypeStr := "unknown"
-        if len(contentTypes) > 0 {
-            contentTypeStr = strings.Join(contentTypes, ", ")
+        if _, err := methods.CallEndpoint(ctx, sm, sampleArgs(sm.Endpoints[0]), methods.CallOptions{BaseURL: "/relative"}); err == nil || !strings.Contains(err.Error(), spec.BaseURLEnv) {
+            t.Errorf("expected a base URL error, got %v", err)
         }
-        lines = append(lines, fmt.Sprintf("  Content-Type: %s %s", contentTypeStr, required))
-        
-        // Add request schema information
-        for _, content := range ep.RequestBody.Content {
-            if content.Schema != nil {
-                lines = append(lines, fmt.Sprintf("  Schema (%s):", content.Mime))
-                
-                // Handle direct schema
-                if content.Schema.Schema != nil {
-                    schemaLines := formatSchemaWithRefs(content.Schema.Schema, sm, "    ")
-                    lines = append(lines, schemaLines...)
+        if got := api.take(); len(got) != 0 {
+            t.Fatalf("invalid calls reached the server: %+v", got)
+        }
+    })
+
+    t.Run("credentials", func(t *testing.T) {
+        for _, ep := range sm.Endpoints {
+            alt := appliedAlternative(ep)
+            if len(alt) == 0 { continue }
+            if _, err := methods.CallEndpoint(ctx, sm, sampleArgs(ep), opts); err != nil { t.Fatalf("%s: %v", ep.ID, err) }
+            r := api.take()[0]
+            for _, scheme := range alt {
+                c := methods.Credentials[scheme]
+                if declares(ep, c.In, c.Name) { continue }
+                want := "secret-" + c.EnvVar
+                switch c.Kind {
+                case "bearer":
+                    want = "Bearer " + want
+                case "basic":
+                    want = "Basic " + base64.StdEncoding.EncodeToString([]byte(want))
                 }
-                
-                // Handle schema reference
-                if content.Schema.Ref != nil {
-                    refName := strings.Replace(content.Schema.Ref.Ref, "#/components/schemas/", "", 1)
-                    lines = append(lines, fmt.Sprintf("    引用: %s", refName))
-                    
-                    // Try to resolve the reference and show details
-                    if sm.Schemas != nil {
-                        if refSchema, exists := sm.Schemas[refName]; exists {
-                            lines = append(lines, fmt.Sprintf("    └─ %s 详情:", refName))
-                            refLines := formatSchemaWithRefs(&refSchema, sm, "      ")
-                            lines = append(lines, refLines...)
-                        }
+                var got string
+                switch c.In {
+                case "query":
+                    q, _ := url.ParseQuery(r.query)
+                    got = q.Get(c.Name)
+                case "cookie":
+                    if cookie, err := (&http.Request{Header: r.header}).Cookie(c.Name); err == nil {
+                        got, _ = url.QueryUnescape(cookie.Value)
                     }
+                default:
+                    got = r.header.Get(c.Name)
+                }
+                if got != want {
+                    t.Errorf("%s: %s %s = %q, want %q", ep.ID, c.In, c.Name, got, want)
                 }
             }
-            
-            if content.Example != nil {
-                exampleBytes, _ := json.Marshal(content.Example)
-                lines = append(lines, fmt.Sprintf("  示例: %s", string(exampleBytes)))
+        }
+
+        for _, c := range methods.Credentials {
+            t.Setenv(c.EnvVar, "")
+        }
+        for _, ep := range sm.Endpoints {
+            if !requiresCredentials(ep) { continue }
+            if _, err := methods.CallEndpoint(ctx, sm, sampleArgs(ep), opts); err == nil || !strings.Contains(err.Error(), "missing credentials") {
+                t.Errorf("%s: expected a missing credentials error, got %v", ep.ID, err)
             }
         }
-    }
-    
-    // Responses
-    if len(ep.Responses) > 0 {
-        lines = append(lines, "", "响应:")
-        for _, resp := range ep.Responses {
-            lines = append(lines, fmt.Sprintf("  • %s: %s", resp.Status, resp.Description))
-            
-            // Add response schema information
-            for _, content := range resp.Content {
-                if content.Schema != nil {
-                    lines = append(lines, fmt.Sprintf("    Schema (%s):", content.Mime))
-                    
-                    // Handle direct schema
-                    if content.Schema.Schema != nil {
-                        schemaLines := formatSchemaWithRefs(content.Schema.Schema, sm, "      ")
-                        lines = append(lines, schemaLines...)
-                    }
-                    
-                    // Handle schema reference
-                    if content.Schema.Ref != nil {
-                        refName := strings.Replace(content.Schema.Ref.Ref, "#/components/schemas/", "", 1)
-                        lines = append(lines, fmt.Sprintf("      引用: %s", refName))
-                        
-                        // Try to resolve the reference and show details
-                        if sm.Schemas != nil {
-                            if refSchema, exists := sm.Schemas[refName]; exists {
-                                lines = append(lines, fmt.Sprintf("      └─ %s 详情:", refName))
-                                refLines := formatSchemaWithRefs(&refSchema, sm, "        ")
-                                lines = append(lines, refLines...)
-                            }
-                        }
-                    }
-                }
-                
-                if content.Example != nil {
-                    exampleBytes, _ := json.Marshal(content.Example)
-                    lines = append(lines, fmt.Sprintf("    示例: %s", string(exampleBytes)))
-                }
+        if got := api.take(); len(got) != 0 {
+            t.Fatalf("calls without credentials reached the server: %+v", got)
+        }
+    })
+
+    t.Run("truncated body", func(t *testing.T) {
+        big := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            _, _ = io.WriteString(w, strings.Repeat("x", methods.MaxResponseBytes+100))
+        }))
+        defer big.Close()
+        for _, ep := range sm.Endpoints {
+            if ep.Method == spec.HEAD { continue }
+            res, err := methods.CallEndpoint(ctx, sm, sampleArgs(ep), methods.CallOptions{BaseURL: big.URL})
+            if err != nil { t.Fatalf("%s: %v", ep.ID, err) }
+            if !res.Truncated || len(res.Body) != methods.MaxResponseBytes {
+                t.Fatalf("%s: truncated=%v len=%d", ep.ID, res.Truncated, len(res.Body))
             }
+            return
         }
-    }
-    
-    return strings.Join(lines, "\n")
+        t.Skip("only HEAD endpoints")
+    })
+
+    t.Run("timeout", func(t *testing.T) {
+        attempts := &counter{}
+        slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            attempts.add()
+            select {
+            case <-r.Context().Done():
+            case <-time.After(5 * time.Second):
+            }
+        }))
+        defer slow.Close()
+        t.Setenv(methods.CallTimeoutEnv, "50ms")
+        t.Setenv(methods.CallRetriesEnv, "1")
+        start := time.Now()
+        _, err := methods.CallEndpoint(ctx, sm, sampleArgs(sm.Endpoints[0]), methods.CallOptions{BaseURL: slow.URL})
+        if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+            t.Fatalf("expected a timeout error, got %v", err)
+        }
+        if elapsed := time.Since(start); elapsed > 2*time.Second {
+            t.Fatalf("the timeout did not bound the call: %v", elapsed)
+        }
+        if n := attempts.get(); n != 2 {
+            t.Fatalf("API_RETRIES=1 should send the request twice, got %d", n)
+        }
+    })
+
+    t.Run("retries", func(t *testing.T) {
+        attempts := &counter{}
+        flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            if attempts.add()%3 != 0 {
+                w.WriteHeader(http.StatusServiceUnavailable)
+                return
+            }
+            w.WriteHeader(http.StatusTeapot)
+        }))
+        defer flaky.Close()
+        opts := methods.CallOptions{BaseURL: flaky.URL}
+        args := sampleArgs(sm.Endpoints[0])
+        res, err := methods.CallEndpoint(ctx, sm, args, opts)
+        if err != nil { t.Fatalf("call: %v", err) }
+        if res.Status != http.StatusServiceUnavailable || attempts.get() != 1 {
+            t.Fatalf("without API_RETRIES: status %d after %d attempts, want 503 after 1", res.Status, attempts.get())
+        }
+        t.Setenv(methods.CallRetriesEnv, "2")
+        res, err = methods.CallEndpoint(ctx, sm, args, opts)
+        if err != nil { t.Fatalf("call: %v", err) }
+        if res.Status != http.StatusTeapot || attempts.get() != 3 {
+            t.Fatalf("API_RETRIES=2: status %d after %d attempts, want 418 after 3", res.Status, attempts.get())
+        }
+    })
+
+    t.Run("tool", func(t *testing.T) {
+        t.Setenv(spec.BaseURLEnv, stub.URL)
+        tool := server.NewMCPServer(sm).GetTool("callEndpoint")
+        if tool == nil { t.Fatalf("callEndpoint not registered") }
+        args := sampleArgs(sm.Endpoints[0])
+        req := mcpgo.CallToolRequest{}
+        req.Params.Arguments = map[string]any{"endpointId": args.EndpointID, "params": args.Params, "body": args.Body}
+        res, err := tool.Handler(ctx, req)
+        if err != nil { t.Fatalf("call: %v", err) }
+        text, _ := res.Content[0].(mcpgo.TextContent)
+        if res.IsError || !strings.Contains(text.Text, "HTTP 418") {
+            t.Fatalf("got %q (isError=%v)", text.Text, res.IsError)
+        }
+        api.take()
+    })
 }
 `)
 }
 
-func renderListSchemasGo(data templateData) string {
-	return data.render(`package methods
-
-import (
-    "sort"
+// otelVersion is the OpenTelemetry Go release WithOTel requires.
+const otelVersion = "v1.38.0"
 
-    "` + "{{MODULE}}" + `/internal/spec"
-)
+// otelModules are the go.mod requirements of internal/mcp/otel.go and
+// tests/otel_test.go.
+var otelModules = []string{
+	"go.opentelemetry.io/otel " + otelVersion,
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp " + otelVersion,
+	"go.opentelemetry.io/otel/sdk " + otelVersion,
+	"go.opentelemetry.io/otel/trace " + otelVersion,
+}
 
-type SchemaSummary struct {
-    Name        string ` + "`json:\"name\"`" + `
-    Description string ` + "`json:\"description\"`" + `
+// withToolTracing installs traceTool as tool handler middleware, so every
+// registered tool is traced without touching its handler.
+func withToolTracing(src string) string {
+	return strings.Replace(src, `
+        goserver.WithRecovery(),
+`, `
+        goserver.WithRecovery(),
+        goserver.WithToolHandlerMiddleware(traceTool),
+`, 1)
 }
 
-func ListSchemas(sm *spec.ServiceModel) []SchemaSummary {
-    if len(sm.Schemas) == 0 { return nil }
-    names := make([]string, 0, len(sm.Schemas))
-    for name := range sm.Schemas { names = append(names, name) }
-    sort.Strings(names)
-    out := make([]SchemaSummary, 0, len(names))
-    for _, name := range names {
-        s := sm.Schemas[name]
-        out = append(out, SchemaSummary{Name: name, Description: s.Description})
+// withTracingSetup makes main install the OTLP exporter before serving and
+// flush it on the way out.
+func withTracingSetup(src string) string {
+	return strings.NewReplacer(`
+import (
+    "flag"
+`, `
+import (
+    "context"
+    "flag"
+`, `
+    srv := mcp.NewMCPServer(sm)
+`, `
+    // Export tool-call spans when OTEL_EXPORTER_OTLP_ENDPOINT is set
+    shutdown, err := mcp.SetupTracing(context.Background())
+    if err != nil {
+        log.Fatalf("tracing: %v", err)
     }
-    return out
-}
-`)
+    defer func() { _ = shutdown(context.Background()) }()
+
+    srv := mcp.NewMCPServer(sm)
+`).Replace(src)
 }
 
-func renderGetSchemaDetailsGo(data templateData) string {
-	return data.render(`package methods
+// mcpOTelGo renders internal/mcp/otel.go: the exporter setup and the
+// middleware that records a span per tool call.
+const mcpOTelGo = `package mcp
 
 import (
-    "fmt"
-    "strings"
-    
-    "` + "{{MODULE}}" + `/internal/spec"
+    "context"
+    "os"
+    "time"
+
+    "github.com/mark3labs/mcp-go/mcp"
+    goserver "github.com/mark3labs/mcp-go/server"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
 )
 
-func GetSchemaDetails(sm *spec.ServiceModel, name string) (*spec.Schema, bool) {
-    s, ok := sm.Schemas[name]
-    if !ok { return nil, false }
-    // copy to avoid external mutation
-    sc := s
-    return &sc, true
-}
+// tracerName identifies the spans this package records.
+const tracerName = "{{MODULE}}/internal/mcp"
 
-// FormatSchemaDetails formats schema details with reference resolution
-func FormatSchemaDetails(schema *spec.Schema, sm *spec.ServiceModel) string {
-    var lines []string
-    
-    lines = append(lines, fmt.Sprintf("Schema: %s", schema.Name))
-    
-    if schema != nil {
-        schemaLines := formatSchemaWithRefs(schema, sm, "")
-        lines = append(lines, schemaLines...)
+// SetupTracing exports spans over OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT
+// or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set; the exporter takes headers,
+// timeouts and TLS settings from the other standard OTEL_EXPORTER_OTLP_*
+// variables, and OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES override the
+// service name. Without an endpoint the global tracer provider stays a no-op.
+// The returned function flushes pending spans and stops the exporter.
+func SetupTracing(ctx context.Context) (func(context.Context) error, error) {
+    noop := func(context.Context) error { return nil }
+    if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+        return noop, nil
     }
-    
-    return strings.Join(lines, "\n")
+    exporter, err := otlptracehttp.New(ctx)
+    if err != nil { return noop, err }
+    res, err := resource.New(ctx,
+        resource.WithAttributes(attribute.String("service.name", "{{TOOL_NAME}}")),
+        resource.WithFromEnv(),
+    )
+    if err != nil { return noop, err }
+    tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+    otel.SetTracerProvider(tp)
+    return tp.Shutdown, nil
 }
-`)
+
+// traceTool records a "tools/call <name>" span around each tool call, with
+// the tool name and duration as attributes and an error status when the
+// handler fails or returns an error result.
+func traceTool(next goserver.ToolHandlerFunc) goserver.ToolHandlerFunc {
+    return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        name := req.Params.Name
+        ctx, span := otel.Tracer(tracerName).Start(ctx, "tools/call "+name,
+            trace.WithSpanKind(trace.SpanKindServer),
+            trace.WithAttributes(attribute.String("mcp.tool.name", name)),
+        )
+        defer span.End()
+        start := time.Now()
+        res, err := next(ctx, req)
+        span.SetAttributes(attribute.Float64("mcp.tool.duration_ms", float64(time.Since(start).Microseconds())/1000))
+        switch {
+        case err != nil:
+            span.RecordError(err)
+            span.SetStatus(codes.Error, err.Error())
+        case res != nil && res.IsError:
+            span.SetStatus(codes.Error, "tool returned an error result")
+        }
+        return res, err
+    }
 }
+`
 
-func renderGeneratedTests(data templateData) string {
-	return data.render(`package tests
+// otelTestsGo renders tests/otel_test.go: the tools answer as before with
+// tracing wired in but no exporter, and each call leaves one span.
+const otelTestsGo = `package tests
 
 import (
+    "context"
+    "encoding/json"
     "testing"
 
-    methods "` + "{{MODULE}}" + `/internal/mcp/methods"
-    "` + "{{MODULE}}" + `/internal/spec"
+    mcpgo "github.com/mark3labs/mcp-go/mcp"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/codes"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+    server "{{MODULE}}/internal/mcp"
+    methods "{{MODULE}}/internal/mcp/methods"
+    "{{MODULE}}/internal/spec"
 )
 
-func Test_ListAndSearch(t *testing.T) {
-    sm, err := spec.Load()
-    if err != nil { t.Fatalf("load: %v", err) }
+// callTool sends a tools/call request through the server, so the tool
+// handler middleware runs as it does for a client.
+func callTool(t *testing.T, sm *spec.ServiceModel, name string, args map[string]any) mcpgo.CallToolResult {
+    t.Helper()
+    msg, err := json.Marshal(map[string]any{
+        "jsonrpc": "2.0", "id": 1, "method": "tools/call",
+        "params": map[string]any{"name": name, "arguments": args},
+    })
+    if err != nil { t.Fatalf("marshal: %v", err) }
+    resp, ok := server.NewMCPServer(sm).HandleMessage(context.Background(), msg).(mcpgo.JSONRPCResponse)
+    if !ok { t.Fatalf("%s: expected a result response", name) }
+    res, ok := resp.Result.(mcpgo.CallToolResult)
+    if !ok { t.Fatalf("%s: unexpected result %T", name, resp.Result) }
+    return res
+}
 
-    overview := methods.FormatEndpointsOverview(sm)
-    if overview == "" { t.Fatalf("expected overview text, got empty") }
+func Test_ToolsWithoutExporter(t *testing.T) {
+    t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+    t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+    shutdown, err := server.SetupTracing(context.Background())
+    if err != nil { t.Fatalf("setup tracing: %v", err) }
+    defer func() { _ = shutdown(context.Background()) }()
 
-    // Use a more flexible search test - try a common keyword first
-    searchKeywords := []string{"get", "list", "api", "user", "data"}
-    var res []methods.EndpointSearchResult
-    
-    for _, keyword := range searchKeywords {
-        res = methods.SearchEndpoints(sm, methods.SearchQuery{Keyword: keyword})
-        if res != nil && len(res) > 0 {
-            break
-        }
+    sm, err := spec.LoadEmbedded()
+    if err != nil { t.Fatalf("load: %v", err) }
+    res := callTool(t, sm, "listEndpoints", map[string]any{})
+    text, _ := res.Content[0].(mcpgo.TextContent)
+    if res.IsError || text.Text != methods.FormatEndpointsOverview(sm) {
+        t.Fatalf("listEndpoints returned %q (isError=%v)", text.Text, res.IsError)
+    }
+    if res := callTool(t, sm, "getSchemaDetails", map[string]any{"name": "__missing__"}); !res.IsError {
+        t.Fatalf("expected an error result for an unknown schema")
     }
-    
-    if res == nil { t.Fatalf("expected a result list, got nil") }
 }
 
-func Test_SchemaDetails(t *testing.T) {
-    sm, err := spec.Load()
+func Test_ToolSpans(t *testing.T) {
+    recorder := tracetest.NewSpanRecorder()
+    prev := otel.GetTracerProvider()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+    defer otel.SetTracerProvider(prev)
+
+    sm, err := spec.LoadEmbedded()
     if err != nil { t.Fatalf("load: %v", err) }
-    schemas := methods.ListSchemas(sm)
-    if len(schemas) > 0 {
-        if _, ok := methods.GetSchemaDetails(sm, schemas[0].Name); !ok {
-            t.Fatalf("schema details not found")
-        }
-    } else {
-        if _, ok := methods.GetSchemaDetails(sm, "__nonexistent__"); ok {
-            t.Fatalf("unexpected schema found")
-        }
+    callTool(t, sm, "listSchemas", map[string]any{})
+    callTool(t, sm, "getSchemaDetails", map[string]any{"name": "__missing__"})
+
+    spans := recorder.Ended()
+    if len(spans) != 2 { t.Fatalf("expected 2 spans, got %d", len(spans)) }
+    if spans[0].Name() != "tools/call listSchemas" || spans[0].Status().Code == codes.Error {
+        t.Fatalf("listSchemas span: %q %v", spans[0].Name(), spans[0].Status())
     }
+    if spans[1].Name() != "tools/call getSchemaDetails" || spans[1].Status().Code != codes.Error {
+        t.Fatalf("getSchemaDetails span: %q %v", spans[1].Name(), spans[1].Status())
+    }
+    var tool string
+    for _, kv := range spans[1].Attributes() {
+        if kv.Key == "mcp.tool.name" { tool = kv.Value.AsString() }
+    }
+    if tool != "getSchemaDetails" { t.Fatalf("mcp.tool.name = %q", tool) }
 }
-`)
-}
-
-// sampleSpecYAML is a small sample used for testdata in the generated project.
-const sampleSpecYAML = "" +
-	"openapi: 3.0.0\n" +
-	"info:\n" +
-	"  title: Sample\n" +
-	"  version: '1.0.0'\n" +
-	"paths:\n" +
-	"  /pets:\n" +
-	"    get:\n" +
-	"      summary: List pets\n" +
-	"      tags: [read]\n" +
-	"      responses:\n" +
-	"        '200':\n" +
-	"          description: ok\n" +
-	"          content:\n" +
-	"            application/json:\n" +
-	"              schema:\n" +
-	"                type: array\n" +
-	"                items:\n" +
-	"                  type: string\n"
+`
@@ -0,0 +1,81 @@
+// Package license renders the LICENSE file emitters add to a generated
+// project. Texts are the standard SPDX ones; those with a copyright line get
+// the year and holder filled in.
+package license
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileName is the license file's name in a generated project.
+const FileName = "LICENSE"
+
+//go:embed texts/*.txt
+var texts embed.FS
+
+// IDs returns the supported SPDX identifiers, sorted.
+func IDs() []string {
+	entries, err := texts.ReadDir("texts")
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Normalize returns the canonical spelling of a supported identifier, matched
+// case-insensitively (mit -> MIT), or an error listing the supported ones.
+func Normalize(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	for _, known := range IDs() {
+		if strings.EqualFold(id, known) {
+			return known, nil
+		}
+	}
+	return "", fmt.Errorf("unknown license %q (supported: %s)", id, strings.Join(IDs(), ", "))
+}
+
+// Render returns the text of license id with year and holder filled in.
+func Render(id string, year int, holder string) (string, error) {
+	canonical, err := Normalize(id)
+	if err != nil {
+		return "", err
+	}
+	data, err := texts.ReadFile("texts/" + canonical + ".txt")
+	if err != nil {
+		return "", err
+	}
+	replacer := strings.NewReplacer(
+		"{{YEAR}}", strconv.Itoa(year),
+		"{{HOLDER}}", strings.TrimSpace(holder),
+	)
+	return replacer.Replace(string(data)), nil
+}
+
+// classifiers maps identifiers to their PyPI trove classifier.
+var classifiers = map[string]string{
+	"Apache-2.0":   "License :: OSI Approved :: Apache Software License",
+	"BSD-2-Clause": "License :: OSI Approved :: BSD License",
+	"BSD-3-Clause": "License :: OSI Approved :: BSD License",
+	"ISC":          "License :: OSI Approved :: ISC License (ISCL)",
+	"MIT":          "License :: OSI Approved :: MIT License",
+	"MPL-2.0":      "License :: OSI Approved :: Mozilla Public License 2.0 (MPL 2.0)",
+}
+
+// Classifier returns the PyPI trove classifier of a supported identifier,
+// or "" for an unknown one.
+func Classifier(id string) string {
+	canonical, err := Normalize(id)
+	if err != nil {
+		return ""
+	}
+	return classifiers[canonical]
+}
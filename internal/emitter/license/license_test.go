@@ -0,0 +1,56 @@
+package license
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{"MIT": "MIT", " mit ": "MIT", "apache-2.0": "Apache-2.0", "bsd-3-clause": "BSD-3-Clause"} {
+		got, err := Normalize(in)
+		if err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	_, err := Normalize("GPL-3.0")
+	if err == nil || !strings.Contains(err.Error(), `unknown license "GPL-3.0"`) || !strings.Contains(err.Error(), "Apache-2.0, BSD-2-Clause") {
+		t.Fatalf("expected an error listing the supported licenses, got %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, id := range IDs() {
+		text, err := Render(id, 2031, " Example Corp ")
+		if err != nil {
+			t.Fatalf("Render(%s): %v", id, err)
+		}
+		if strings.Contains(text, "{{") {
+			t.Errorf("%s: unfilled placeholder:\n%s", id, text)
+		}
+		// MPL-2.0 has no copyright line to fill in.
+		if id != "MPL-2.0" && !strings.Contains(text, "2031") {
+			t.Errorf("%s: missing year", id)
+		}
+		if id != "MPL-2.0" && !strings.Contains(text, "2031 Example Corp") && !strings.Contains(text, "2031, Example Corp") {
+			t.Errorf("%s: missing copyright holder", id)
+		}
+	}
+	mit, _ := Render("mit", 2031, "Example Corp")
+	if !strings.HasPrefix(mit, "MIT License\n\nCopyright (c) 2031 Example Corp\n") {
+		t.Fatalf("unexpected MIT header:\n%s", mit)
+	}
+}
+
+func TestClassifier(t *testing.T) {
+	for _, id := range IDs() {
+		if !strings.HasPrefix(Classifier(id), "License :: OSI Approved :: ") {
+			t.Errorf("Classifier(%s) = %q", id, Classifier(id))
+		}
+	}
+	if got := Classifier("apache-2.0"); got != "License :: OSI Approved :: Apache Software License" {
+		t.Errorf("Classifier(apache-2.0) = %q", got)
+	}
+	if got := Classifier("WTFPL"); got != "" {
+		t.Errorf("Classifier(WTFPL) = %q, want empty", got)
+	}
+}
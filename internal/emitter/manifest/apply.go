@@ -0,0 +1,133 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+)
+
+// Policy controls how Apply treats files left by an earlier run.
+type Policy struct {
+	OverwriteModified bool // replace generated files edited since the last run, and files it did not generate
+	Prune             bool // delete files the previous run generated that this run no longer does
+}
+
+// Outcome lists the files Apply did not simply write.
+type Outcome struct {
+	Modified  []string `json:"modified"`  // generated earlier, edited since; left in place
+	Unmanaged []string `json:"unmanaged"` // already on disk but not generated earlier; left in place
+	Stale     []string `json:"stale"`     // generated earlier, no longer produced; left in place
+	Pruned    []string `json:"pruned"`    // generated earlier, no longer produced; removed
+}
+
+// Apply writes files (keyed by slash-separated relative path) to dir with w
+// and records m, built for the same files, as the new manifest.
+//
+// When dir holds a manifest from an earlier run, only the files it lists are
+// overwritten, and only while their content still matches the recorded hash.
+// Edited files (Modified) and files swagger2mcp never wrote (Unmanaged) are
+// kept unless p.OverwriteModified is set; an edited file keeps its old entry
+// in m, so it stays protected on later runs. Files the earlier run generated
+// but this one does not (Stale) are kept and stay in m, unless p.Prune removes
+// them; edited stale files also need p.OverwriteModified. Other files in dir
+// are never touched. Without an earlier manifest every file is written.
+func Apply(w *filewriter.Writer, dir string, m *Manifest, files map[string][]byte, p Policy) (*Outcome, error) {
+	if _, err := w.Check(dir); err != nil {
+		return nil, err
+	}
+	out := &Outcome{}
+	prev, err := ReadManifest(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := w.Write(dir, files); err != nil {
+			return nil, err
+		}
+		return out, prefixed(w, WriteManifest(dir, m))
+	}
+	if err != nil {
+		return nil, prefixed(w, err)
+	}
+
+	recorded := make(map[string]string, len(prev.Files))
+	for _, f := range prev.Files {
+		recorded[f.Path] = f.SHA256
+	}
+	entries := make(map[string]File, len(m.Files))
+	for _, f := range m.Files {
+		entries[f.Path] = f
+	}
+
+	write := make(map[string][]byte, len(files))
+	for rel, content := range files {
+		slash := filepath.ToSlash(rel)
+		onDisk, exists := diskHash(dir, slash)
+		want, managed := recorded[slash]
+		switch {
+		case !exists, p.OverwriteModified, managed && onDisk == want:
+			write[rel] = content
+		case managed:
+			out.Modified = append(out.Modified, slash)
+			entries[slash] = File{Path: slash, SHA256: want}
+		default:
+			out.Unmanaged = append(out.Unmanaged, slash)
+			delete(entries, slash)
+		}
+	}
+	for path, want := range recorded {
+		if _, generated := entries[path]; generated {
+			continue
+		}
+		onDisk, exists := diskHash(dir, path)
+		if !exists {
+			continue
+		}
+		if p.Prune && (onDisk == want || p.OverwriteModified) {
+			if err := os.Remove(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+				return nil, prefixed(w, fmt.Errorf("prune %s: %w", path, err))
+			}
+			out.Pruned = append(out.Pruned, path)
+			continue
+		}
+		out.Stale = append(out.Stale, path)
+		entries[path] = File{Path: path, SHA256: want}
+	}
+
+	if err := w.Write(dir, write); err != nil {
+		return nil, err
+	}
+	m.Files = m.Files[:0]
+	for _, f := range entries {
+		m.Files = append(m.Files, f)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	for _, list := range [][]string{out.Modified, out.Unmanaged, out.Stale, out.Pruned} {
+		sort.Strings(list)
+	}
+	return out, prefixed(w, WriteManifest(dir, m))
+}
+
+// prefixed adds the writer's prefix to err, matching filewriter's own errors.
+func prefixed(w *filewriter.Writer, err error) error {
+	if err == nil || w.Prefix == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", w.Prefix, err)
+}
+
+// diskHash returns the hash of dir/rel and whether it exists as a regular file.
+// Unreadable files hash to "" and so never match a recorded hash.
+func diskHash(dir, rel string) (string, bool) {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	st, err := os.Stat(path)
+	if err != nil || st.IsDir() {
+		return "", err == nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", true
+	}
+	return HashBytes(data), true
+}
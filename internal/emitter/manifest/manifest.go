@@ -0,0 +1,181 @@
+// Package manifest reads and writes .swagger2mcp-manifest.json, the record an
+// emitter leaves in its output directory describing what it generated. A later
+// run compares its spec hash, options and files against it to skip
+// regenerating unchanged output.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// FileName is the manifest's name inside an output directory.
+const FileName = ".swagger2mcp-manifest.json"
+
+// Manifest describes one generate run.
+type Manifest struct {
+	ToolName    string      `json:"tool_name"`
+	Lang        string      `json:"lang"`
+	GeneratedAt string      `json:"generated_at"` // RFC3339, UTC
+	SpecHash    string      `json:"spec_hash"`
+	OptionsHash string      `json:"options_hash,omitempty"` // Key.OptionsHash of the run, set by Stamp
+	Files       []File      `json:"files"`
+	Provenance  *Provenance `json:"provenance,omitempty"`
+}
+
+// Key is what a generate run's output depends on besides the files on disk.
+type Key struct {
+	ToolName    string
+	Lang        string
+	SpecHash    string
+	OptionsHash string // hash of the resolved options that shape the output, template overrides included
+	Generator   string // swagger2mcp version
+}
+
+// Provenance records where a run's model came from and how it was filtered, so
+// the model can be rebuilt from an updated spec without regenerating the code.
+type Provenance struct {
+	Generator      string   `json:"generator"`              // swagger2mcp version that wrote the output
+	Input          string   `json:"input"`                  // spec path (absolute) or URL
+	ExtraInputs    []string `json:"extra_inputs,omitempty"` // further specs merged with Input, likewise
+	IncludeTags    []string `json:"include_tags,omitempty"`
+	ExcludeTags    []string `json:"exclude_tags,omitempty"`
+	IncludeSchemas []string `json:"include_schemas,omitempty"`
+	ExcludeSchemas []string `json:"exclude_schemas,omitempty"`
+	DropExtensions []string `json:"drop_extensions,omitempty"` // x-key=value
+	KeepAllServers bool     `json:"keep_all_servers,omitempty"`
+	DropDevServers bool     `json:"drop_dev_servers,omitempty"`
+	Overrides      string   `json:"overrides,omitempty"` // overrides file path (absolute)
+}
+
+// File is a generated file and the SHA-256 of its content.
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// New builds a manifest for files (keyed by relative path), sorted by path and
+// stamped with the current time.
+func New(toolName, lang, specHash string, files map[string][]byte) *Manifest {
+	m := &Manifest{
+		ToolName:    toolName,
+		Lang:        lang,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		SpecHash:    specHash,
+		Files:       make([]File, 0, len(files)),
+	}
+	for rel, content := range files {
+		m.Files = append(m.Files, File{Path: filepath.ToSlash(rel), SHA256: HashBytes(content)})
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	return m
+}
+
+// HashBytes returns the hex SHA-256 of data.
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SpecHash hashes the normalized service model. Hashing the model rather than
+// the raw document means filter changes (tags, schemas, ...) also count as a
+// spec change. It returns "" if the model cannot be encoded.
+func SpecHash(sm *genspec.ServiceModel) string {
+	data, err := json.Marshal(sm)
+	if err != nil {
+		return ""
+	}
+	return HashBytes(data)
+}
+
+// ReadManifest loads the manifest from dir. A missing manifest yields an error
+// matching os.ErrNotExist.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("manifest: parse %s: %w", FileName, err)
+	}
+	return &m, nil
+}
+
+// WriteManifest atomically writes m to dir.
+func WriteManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("manifest: encode: %w", err)
+	}
+	if err := filewriter.WriteFileAtomic(dir, FileName, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("manifest: write %s: %w", FileName, err)
+	}
+	return nil
+}
+
+// Stamp records p as the provenance of the manifest in dir and optionsHash as
+// its Key.OptionsHash.
+func Stamp(dir string, p *Provenance, optionsHash string) error {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	m.Provenance = p
+	m.OptionsHash = optionsHash
+	return WriteManifest(dir, m)
+}
+
+// UpToDate reports whether dir holds the output of a run with key: its
+// manifest records the same tool, language, spec hash, options hash and
+// generator, and every file it lists is on disk with its recorded hash. A
+// key without a spec or options hash is never up to date, and neither is a
+// manifest from a version that did not record them.
+func UpToDate(dir string, key Key) bool {
+	if key.SpecHash == "" || key.OptionsHash == "" {
+		return false
+	}
+	m, err := ReadManifest(dir)
+	if err != nil || m.Provenance == nil {
+		return false
+	}
+	return m.ToolName == key.ToolName && m.Lang == key.Lang && m.SpecHash == key.SpecHash &&
+		m.OptionsHash == key.OptionsHash && m.Provenance.Generator == key.Generator && m.intact(dir)
+}
+
+// Unchanged reports whether writing m, a manifest built by New for a run's
+// files, would leave dir as it is: dir's manifest records the same tool,
+// language, spec hash and files as m, and every file is on disk with its
+// recorded hash.
+func Unchanged(dir string, m *Manifest) bool {
+	if m.SpecHash == "" {
+		return false
+	}
+	prev, err := ReadManifest(dir)
+	if err != nil {
+		return false
+	}
+	return prev.ToolName == m.ToolName && prev.Lang == m.Lang && prev.SpecHash == m.SpecHash &&
+		slices.Equal(prev.Files, m.Files) && prev.intact(dir)
+}
+
+// intact reports whether every file m lists is in dir with its recorded hash.
+func (m *Manifest) intact(dir string) bool {
+	for _, f := range m.Files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+		if err != nil || HashBytes(data) != f.SHA256 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,300 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{"b.txt": []byte("b"), "a/x.go": []byte("package a\n")}
+	m := New("tool", "go", "abc123", files)
+	if _, err := time.Parse(time.RFC3339, m.GeneratedAt); err != nil {
+		t.Fatalf("generated_at not RFC3339: %q", m.GeneratedAt)
+	}
+	if len(m.Files) != 2 || m.Files[0].Path != "a/x.go" || m.Files[1].SHA256 != HashBytes([]byte("b")) {
+		t.Fatalf("unexpected files: %+v", m.Files)
+	}
+	if err := WriteManifest(dir, m); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Fatalf("round trip mismatch:\nwant %+v\ngot  %+v", m, got)
+	}
+}
+
+func TestUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	w := &filewriter.Writer{Prefix: "test", Force: true}
+	files := map[string][]byte{"a.txt": []byte("a"), "sub/b.txt": []byte("b")}
+	if _, err := Apply(w, dir, New("tool", "go", "abc123", files), files, Policy{}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	key := Key{ToolName: "tool", Lang: "go", SpecHash: "abc123", OptionsHash: "opts", Generator: "1.2.3"}
+	if UpToDate(dir, key) {
+		t.Fatalf("a manifest that was never stamped should not be up to date")
+	}
+	if err := Stamp(dir, &Provenance{Generator: "1.2.3"}, "opts"); err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	if !UpToDate(dir, key) {
+		t.Fatalf("expected matching manifest to be up to date")
+	}
+	for _, change := range []func(*Key){
+		func(k *Key) { k.ToolName = "other" },
+		func(k *Key) { k.Lang = "npm" },
+		func(k *Key) { k.SpecHash = "def" },
+		func(k *Key) { k.SpecHash = "" },
+		func(k *Key) { k.OptionsHash = "license" },
+		func(k *Key) { k.OptionsHash = "" },
+		func(k *Key) { k.Generator = "1.2.4" },
+	} {
+		k := key
+		change(&k)
+		if UpToDate(dir, k) {
+			t.Fatalf("UpToDate(%+v) should be false", k)
+		}
+	}
+
+	// an edited or deleted file means the output is no longer what was generated
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if UpToDate(dir, key) {
+		t.Fatalf("an edited file should not be up to date")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "sub", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if UpToDate(dir, key) {
+		t.Fatalf("a deleted file should not be up to date")
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	w := &filewriter.Writer{Prefix: "test", Force: true}
+	files := map[string][]byte{"a.txt": []byte("a"), "b.txt": []byte("b")}
+	if Unchanged(dir, New("tool", "go", "h", files)) {
+		t.Fatalf("a directory without a manifest should not be unchanged")
+	}
+	if _, err := Apply(w, dir, New("tool", "go", "h", files), files, Policy{}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !Unchanged(dir, New("tool", "go", "h", files)) {
+		t.Fatalf("rendering the same files should leave dir unchanged")
+	}
+	if Unchanged(dir, New("tool", "go", "h", map[string][]byte{"a.txt": []byte("a"), "b.txt": []byte("MIT")})) {
+		t.Fatalf("different file contents should not be unchanged")
+	}
+	if Unchanged(dir, New("tool", "go", "h", map[string][]byte{"a.txt": []byte("a")})) {
+		t.Fatalf("a different file set should not be unchanged")
+	}
+	if Unchanged(dir, New("tool", "go", "other", files)) {
+		t.Fatalf("a different spec hash should not be unchanged")
+	}
+	if err := os.Remove(filepath.Join(dir, "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if Unchanged(dir, New("tool", "go", "h", files)) {
+		t.Fatalf("a deleted file should not be unchanged")
+	}
+}
+
+func TestReadManifest_Missing(t *testing.T) {
+	if _, err := ReadManifest(t.TempDir()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestStamp(t *testing.T) {
+	dir := t.TempDir()
+	m := New("tool", "go", "abc123", map[string][]byte{"a.txt": []byte("a")})
+	if err := WriteManifest(dir, m); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	p := &Provenance{Generator: "1.2.3", Input: "/specs/api.yaml", IncludeTags: []string{"pets"}, DropDevServers: true}
+	if err := Stamp(dir, p, "opts"); err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	got, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !reflect.DeepEqual(got.Provenance, p) || got.OptionsHash != "opts" || !reflect.DeepEqual(got.Files, m.Files) {
+		t.Fatalf("stamped manifest = %+v", got)
+	}
+	if err := Stamp(t.TempDir(), p, "opts"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist without a manifest, got %v", err)
+	}
+}
+
+func TestSpecHash_Deterministic(t *testing.T) {
+	a := &genspec.ServiceModel{Title: "A", Schemas: map[string]genspec.Schema{"X": {Name: "X"}, "Y": {Name: "Y"}}}
+	b := &genspec.ServiceModel{Title: "A", Schemas: map[string]genspec.Schema{"Y": {Name: "Y"}, "X": {Name: "X"}}}
+	if SpecHash(a) == "" || SpecHash(a) != SpecHash(b) {
+		t.Fatalf("expected equal non-empty hashes")
+	}
+	b.Title = "B"
+	if SpecHash(a) == SpecHash(b) {
+		t.Fatalf("expected different hashes for different models")
+	}
+}
+
+func TestApply_ConflictCases(t *testing.T) {
+	dir := t.TempDir()
+	w := &filewriter.Writer{Prefix: "test", Force: true}
+	read := func(rel string) string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "<missing>"
+		}
+		return string(b)
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apply := func(files map[string][]byte, p Policy) *Outcome {
+		t.Helper()
+		out, err := Apply(w, dir, New("tool", "go", "h", files), files, p)
+		if err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+		return out
+	}
+	recorded := func() map[string]string {
+		t.Helper()
+		m, err := ReadManifest(dir)
+		if err != nil {
+			t.Fatalf("read manifest: %v", err)
+		}
+		got := map[string]string{}
+		for _, f := range m.Files {
+			got[f.Path] = f.SHA256
+		}
+		return got
+	}
+
+	// Without an earlier manifest everything is written, even over existing files.
+	write("a.txt", "old")
+	first := map[string][]byte{"a.txt": []byte("a1"), "b.txt": []byte("b1"), "gone.txt": []byte("g1")}
+	if out := apply(first, Policy{}); !reflect.DeepEqual(out, &Outcome{}) || read("a.txt") != "a1" {
+		t.Fatalf("first run: %+v a=%q", out, read("a.txt"))
+	}
+
+	// Edited, unmanaged, stale, hand-added and deleted files on the next run.
+	write("b.txt", "user edit")
+	write("c.txt", "user file")
+	write("notes.txt", "hand added")
+	if err := os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	second := map[string][]byte{"a.txt": []byte("a2"), "b.txt": []byte("b2"), "c.txt": []byte("c2")}
+	out := apply(second, Policy{})
+	want := &Outcome{Modified: []string{"b.txt"}, Unmanaged: []string{"c.txt"}, Stale: []string{"gone.txt"}}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("second run outcome:\nwant %+v\ngot  %+v", want, out)
+	}
+	if read("a.txt") != "a2" || read("b.txt") != "user edit" || read("c.txt") != "user file" || read("notes.txt") != "hand added" || read("gone.txt") != "g1" {
+		t.Fatalf("unexpected files after second run")
+	}
+	got := recorded()
+	if got["b.txt"] != HashBytes([]byte("b1")) || got["gone.txt"] != HashBytes([]byte("g1")) {
+		t.Fatalf("modified and stale files should keep their old entries: %v", got)
+	}
+	if _, ok := got["c.txt"]; ok {
+		t.Fatalf("unmanaged file must not be recorded: %v", got)
+	}
+	if _, ok := got["notes.txt"]; ok {
+		t.Fatalf("hand-added file must not be recorded: %v", got)
+	}
+
+	// The edit stays protected, and an edited stale file is not pruned.
+	write("gone.txt", "edited stale")
+	out = apply(second, Policy{Prune: true})
+	if !reflect.DeepEqual(out.Modified, []string{"b.txt"}) || !reflect.DeepEqual(out.Stale, []string{"gone.txt"}) || len(out.Pruned) != 0 {
+		t.Fatalf("third run outcome: %+v", out)
+	}
+
+	// --overwrite-modified with --prune replaces everything it owns or collides with.
+	out = apply(second, Policy{OverwriteModified: true, Prune: true})
+	if !reflect.DeepEqual(out, &Outcome{Pruned: []string{"gone.txt"}}) {
+		t.Fatalf("overwrite run outcome: %+v", out)
+	}
+	if read("b.txt") != "b2" || read("c.txt") != "c2" || read("gone.txt") != "<missing>" || read("notes.txt") != "hand added" {
+		t.Fatalf("unexpected files after overwrite run")
+	}
+	if got := recorded(); len(got) != 3 || got["c.txt"] != HashBytes([]byte("c2")) {
+		t.Fatalf("unexpected manifest after overwrite run: %v", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	w := &filewriter.Writer{Prefix: "test", Force: true}
+	files := map[string][]byte{"a.txt": []byte("a"), "sub/b.txt": []byte("b"), "gone.txt": []byte("g")}
+	if _, err := Apply(w, dir, New("tool", "go", "h", files), files, Policy{}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	hashes := func(files map[string][]byte) map[string]string {
+		out := map[string]string{}
+		for rel, content := range files {
+			out[rel] = HashBytes(content)
+		}
+		return out
+	}
+
+	v, err := Verify(dir, hashes(files))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !v.OK() || !reflect.DeepEqual(v.Matched, []string{"a.txt", "gone.txt", "sub/b.txt"}) {
+		t.Fatalf("fresh output should verify: %+v", v)
+	}
+
+	// Tamper with one file, delete another, and render a set that drops
+	// gone.txt and adds a path the user already owns.
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "sub", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mine.txt"), []byte("user"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	next := map[string][]byte{"a.txt": []byte("a"), "sub/b.txt": []byte("b"), "mine.txt": []byte("rendered")}
+	v, err = Verify(dir, hashes(next))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	want := &Verification{Matched: []string{}, Modified: []string{"a.txt"}, Missing: []string{"sub/b.txt"}, Extra: []string{"gone.txt"}, UserOwned: []string{"mine.txt"}}
+	if v.OK() || !reflect.DeepEqual(v, want) {
+		t.Fatalf("tampered output:\nwant %+v\ngot  %+v", want, v)
+	}
+	if read, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(read) != "tampered" {
+		t.Fatalf("verify must not write: a.txt = %q", read)
+	}
+
+	if _, err := Verify(filepath.Join(dir, "nope"), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist for a missing dir, got %v", err)
+	}
+}
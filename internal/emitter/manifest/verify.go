@@ -0,0 +1,87 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Verification is the result of comparing a rendered file set with an output
+// directory. Paths are slash-separated and sorted.
+type Verification struct {
+	Matched   []string `json:"matched"`
+	Modified  []string `json:"modified"`            // on disk, but the content differs from the render
+	Missing   []string `json:"missing"`             // rendered, but not on disk
+	Extra     []string `json:"extra"`               // recorded by the manifest and on disk, but no longer rendered
+	UserOwned []string `json:"userOwned,omitempty"` // rendered paths the manifest leaves to the user; not compared
+}
+
+// OK reports whether the directory matches the render exactly.
+func (v *Verification) OK() bool {
+	return len(v.Modified) == 0 && len(v.Missing) == 0 && len(v.Extra) == 0
+}
+
+// Verify compares dir with files, the SHA-256 of each rendered file keyed by
+// relative path, without writing anything. Rendering is deterministic, so a
+// directory generated from the same spec and options matches byte for byte;
+// the manifest itself, which carries a timestamp, is not compared.
+//
+// When dir holds a manifest, it decides ownership the way Apply does: a
+// rendered path that exists on disk but is not recorded there was left to the
+// user (Apply kept it as Unmanaged) and is reported as UserOwned, and recorded
+// files that are no longer rendered but still exist are Extra. Without a
+// manifest every rendered path is compared and nothing counts as Extra.
+func Verify(dir string, files map[string]string) (*Verification, error) {
+	st, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: verify: %w", err)
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("manifest: verify: %s is not a directory", dir)
+	}
+	var recorded map[string]bool
+	prev, err := ReadManifest(dir)
+	switch {
+	case err == nil:
+		recorded = make(map[string]bool, len(prev.Files))
+		for _, f := range prev.Files {
+			recorded[f.Path] = true
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	v := &Verification{Matched: []string{}, Modified: []string{}, Missing: []string{}, Extra: []string{}}
+	for rel, want := range files {
+		slash := filepath.ToSlash(rel)
+		onDisk, exists := diskHash(dir, slash)
+		switch {
+		case !exists:
+			v.Missing = append(v.Missing, slash)
+		case recorded != nil && !recorded[slash]:
+			v.UserOwned = append(v.UserOwned, slash)
+		case onDisk == want:
+			v.Matched = append(v.Matched, slash)
+		default:
+			v.Modified = append(v.Modified, slash)
+		}
+	}
+	rendered := make(map[string]bool, len(files))
+	for rel := range files {
+		rendered[filepath.ToSlash(rel)] = true
+	}
+	for path := range recorded {
+		if rendered[path] {
+			continue
+		}
+		if _, exists := diskHash(dir, path); exists {
+			v.Extra = append(v.Extra, path)
+		}
+	}
+	for _, list := range [][]string{v.Matched, v.Modified, v.Missing, v.Extra, v.UserOwned} {
+		sort.Strings(list)
+	}
+	return v, nil
+}
@@ -0,0 +1,252 @@
+package markdownemitter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/docs"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// DocsDir holds the generated Markdown pages.
+const DocsDir = "docs"
+
+// SchemasPage is the page listing schema definitions that endpoint pages link to.
+const SchemasPage = "schemas.md"
+
+// maxFileStem bounds the file names of tag pages, so long tags stay within
+// file name and Windows path limits.
+const maxFileStem = 64
+
+// Options controls how the Markdown emitter renders documentation.
+type Options struct {
+	OutDir            string // required; target directory to write docs/ into
+	ToolName          string // documentation title fallback when the spec has no title
+	Force             bool   // overwrite existing files
+	OverwriteModified bool   // with Force, also replace files edited since the last run and files it did not generate
+	Prune             bool   // delete files the last run generated that are no longer produced
+	Concurrency       int    // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun            bool   // don't write, only plan
+	Verbose           bool
+}
+
+// PlannedFile describes a file the emitter intends to write.
+type PlannedFile struct {
+	RelPath string
+	Size    int
+	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
+}
+
+// Result returns the planned files and final resolved names.
+type Result struct {
+	ToolName string
+	Planned  []PlannedFile
+	Skipped  bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome  manifest.Outcome // files kept or pruned because of the last run's manifest
+}
+
+// page is one tag page and the endpoints grouped on it.
+type page struct {
+	Title     string
+	File      string
+	Endpoints []*genspec.EndpointModel
+}
+
+// Emit renders a docs/ directory from the provided ServiceModel (IM): an
+// index.md table of contents, one page per tag (endpoints grouped by their
+// first tag) and schemas.md. Pages use plain CommonMark tables and relative
+// links so the directory can be served by mkdocs or docusaurus as-is.
+func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result, error) {
+	_ = ctx
+	if sm == nil {
+		return nil, fmt.Errorf("markdownemitter: nil ServiceModel")
+	}
+	if strings.TrimSpace(opts.OutDir) == "" {
+		return nil, fmt.Errorf("markdownemitter: OutDir is required")
+	}
+	toolName := strings.TrimSpace(opts.ToolName)
+	if toolName == "" {
+		toolName = "mcp-tool"
+	}
+	title := strings.TrimSpace(sm.Title)
+	if title == "" {
+		title = toolName
+	}
+
+	pages := groupPages(sm)
+	files := map[string][]byte{}
+	files[filepath.Join(DocsDir, "index.md")] = []byte(renderIndex(sm, title, pages))
+	for _, pg := range pages {
+		files[filepath.Join(DocsDir, pg.File)] = []byte(renderTagPage(pg))
+	}
+	if len(sm.Schemas) > 0 {
+		files[filepath.Join(DocsDir, SchemasPage)] = []byte(renderSchemas(sm))
+	}
+
+	rels := make([]string, 0, len(files))
+	for p := range files {
+		rels = append(rels, filepath.ToSlash(p))
+	}
+	genspec.SortNames(rels)
+	planned := make([]PlannedFile, 0, len(rels))
+	for _, rel := range rels {
+		content := files[filepath.FromSlash(rel)]
+		planned = append(planned, PlannedFile{
+			RelPath: rel,
+			Size:    len(content),
+			Mode:    0o644,
+			SHA256:  manifest.HashBytes(content),
+			Change:  filewriter.Classify(opts.OutDir, rel, content, 0o644),
+		})
+	}
+
+	res := &Result{ToolName: toolName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "markdown", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		w := &filewriter.Writer{Prefix: "markdownemitter", Concurrency: opts.Concurrency, Force: opts.Force}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	}
+	return res, nil
+}
+
+// groupPages gives each of docs.GroupByTag's groups a page file.
+func groupPages(sm *genspec.ServiceModel) []*page {
+	var pages []*page
+	used := map[string]bool{"index.md": true, SchemasPage: true}
+	for _, g := range docs.GroupByTag(sm) {
+		pages = append(pages, &page{Title: g.Title, File: uniqueFile(used, slug(g.Title)), Endpoints: g.Endpoints})
+	}
+	return pages
+}
+
+func renderIndex(sm *genspec.ServiceModel, title string, pages []*page) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if v := strings.TrimSpace(sm.Version); v != "" {
+		fmt.Fprintf(&b, "Version: `%s`\n\n", v)
+	}
+	if d := strings.TrimSpace(sm.Description); d != "" {
+		b.WriteString(d + "\n\n")
+	}
+	if len(sm.Servers) > 0 {
+		b.WriteString("## Servers\n\n")
+		for _, s := range sm.Servers {
+			line := "- `" + s.URL + "`"
+			if s.Development {
+				line += " _(development)_"
+			}
+			if d := strings.TrimSpace(s.Description); d != "" {
+				line += " - " + d
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("## Contents\n\n")
+	for _, pg := range pages {
+		fmt.Fprintf(&b, "- [%s](%s) (%d endpoint(s))\n", pg.Title, pg.File, len(pg.Endpoints))
+	}
+	if len(sm.Schemas) > 0 {
+		fmt.Fprintf(&b, "- [Schemas](%s) (%d schema(s))\n", SchemasPage, len(sm.Schemas))
+	}
+	return b.String()
+}
+
+func renderTagPage(pg *page) string {
+	w := newWriter()
+	fmt.Fprintf(w, "# %s\n", pg.Title)
+	for _, ep := range pg.Endpoints {
+		w.WriteString("\n")
+		w.Endpoint(ep)
+	}
+	return w.String()
+}
+
+func renderSchemas(sm *genspec.ServiceModel) string {
+	names := make([]string, 0, len(sm.Schemas))
+	for name := range sm.Schemas {
+		names = append(names, name)
+	}
+	genspec.SortNames(names)
+
+	w := newWriter()
+	w.WriteString("# Schemas\n")
+	for _, name := range names {
+		w.WriteString("\n")
+		w.Schema(name, sm.Schemas[name])
+	}
+	return strings.TrimSuffix(w.String(), "\n") + "\n"
+}
+
+// newWriter returns a docs.Writer for the pages: endpoint and schema titles
+// are second-level headings and named schemas link into schemas.md.
+func newWriter() *docs.Writer {
+	return &docs.Writer{Level: 2, SchemaLink: func(name string) string {
+		return SchemasPage + "#" + anchor(name)
+	}}
+}
+
+// anchor mirrors the heading slugs generated by mkdocs and docusaurus:
+// lowercase, spaces become dashes, other punctuation is dropped.
+func anchor(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// slug turns a tag into a file name stem, e.g. "Pet Store" -> "pet-store".
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+// uniqueFile returns stem+".md", adding a numeric suffix when already used;
+// stems longer than maxFileStem are shortened with emitter.ShortName.
+func uniqueFile(used map[string]bool, stem string) string {
+	if stem == "" {
+		stem = "tag"
+	}
+	name := emitter.ShortName(stem, maxFileStem) + ".md"
+	for i := 2; used[name]; i++ {
+		name = emitter.ShortName(stem+"-"+strconv.Itoa(i), maxFileStem) + ".md"
+	}
+	used[name] = true
+	return name
+}
@@ -0,0 +1,197 @@
+package markdownemitter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func sampleModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Title:   "Pet Store",
+		Version: "1.2.0",
+		Servers: []genspec.Server{
+			{URL: "https://api.example.com/v1", Description: "production"},
+			{URL: "http://localhost:8080", Description: "local", Development: true},
+		},
+		Endpoints: []genspec.EndpointModel{
+			{
+				ID:          "get /pets/{petId}",
+				Method:      genspec.GET,
+				Path:        "/pets/{petId}",
+				Summary:     "Get pet",
+				Description: "Returns a single pet.",
+				Tags:        []string{"Pets"},
+				Parameters: []genspec.ParameterModel{
+					{Name: "petId", In: "path", Required: true, Description: "Pet | identifier", Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "integer", Format: "int64"}}},
+					{Name: "fields", In: "query", Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "array", Items: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "string"}}}}},
+				},
+				Responses: []genspec.ResponseModel{
+					{Status: "200", Description: "The pet", Content: []genspec.Media{{Mime: "application/json", Schema: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Pet"}}}}},
+					{Status: "404", Description: "Not found"},
+				},
+			},
+			{
+				ID:      "post /stores",
+				Method:  genspec.POST,
+				Path:    "/stores",
+				Tags:    []string{"Store Admin", "Pets"},
+				Summary: "Create store",
+				RequestBody: &genspec.RequestBodyModel{Required: true, Content: []genspec.Media{
+					{Mime: "application/json", Schema: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Store"}}},
+					{Mime: "text/plain", Schema: &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "string"}}},
+				}},
+			},
+			{ID: "get /health", Method: genspec.GET, Path: "/health"},
+		},
+		Schemas: map[string]genspec.Schema{
+			"Pet": {Name: "Pet", Type: "object", Required: []string{"id"}, Properties: map[string]*genspec.SchemaOrRef{
+				"id":   {Schema: &genspec.Schema{Type: "integer", Description: "Unique id"}},
+				"tags": {Schema: &genspec.Schema{Type: "array", Items: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Tag"}}}},
+				"age":  {Schema: &genspec.Schema{Type: "integer", Minimum: ptr(0.0), Maximum: ptr(30.0), ExclusiveMaximum: true}},
+			}},
+			"Store": {Name: "Store", Type: "object"},
+		},
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func readFile(t *testing.T, dir, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("read %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestEmit_WritesDocs(t *testing.T) {
+	dir := t.TempDir()
+	res, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	want := []string{"docs/index.md", "docs/other.md", "docs/pets.md", "docs/schemas.md", "docs/store-admin.md"}
+	if len(res.Planned) != len(want) {
+		t.Fatalf("planned: want %v got %+v", want, res.Planned)
+	}
+	for i, p := range res.Planned {
+		if p.RelPath != want[i] {
+			t.Fatalf("planned[%d]: want %s got %s", i, want[i], p.RelPath)
+		}
+	}
+
+	index := readFile(t, dir, "docs/index.md")
+	for _, part := range []string{
+		"# Pet Store\n",
+		"Version: `1.2.0`",
+		"- `https://api.example.com/v1` - production\n",
+		"- `http://localhost:8080` _(development)_ - local\n",
+		"- [Pets](pets.md) (1 endpoint(s))\n",
+		"- [Store Admin](store-admin.md) (1 endpoint(s))\n",
+		"- [Other](other.md) (1 endpoint(s))\n",
+		"- [Schemas](schemas.md) (2 schema(s))\n",
+	} {
+		if !strings.Contains(index, part) {
+			t.Fatalf("index.md missing %q:\n%s", part, index)
+		}
+	}
+}
+
+func TestEmit_ParametersTable(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	pets := readFile(t, dir, "docs/pets.md")
+	want := "## Get pet\n\n" +
+		"`GET` `/pets/{petId}`\n\n" +
+		"Returns a single pet.\n\n" +
+		"### Parameters\n\n" +
+		"| Name | In | Type | Required | Description |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `petId` | path | `integer` (int64) | yes | Pet \\| identifier |\n" +
+		"| `fields` | query | array of `string` | no | - |\n\n" +
+		"### Responses\n\n" +
+		"| Status | Description | Content type |\n" +
+		"| --- | --- | --- |\n" +
+		"| 200 | The pet | `application/json` [Pet](schemas.md#pet) |\n" +
+		"| 404 | Not found | - |\n"
+	if !strings.Contains(pets, want) {
+		t.Fatalf("pets.md:\n%s\nwant section:\n%s", pets, want)
+	}
+
+	store := readFile(t, dir, "docs/store-admin.md")
+	for _, part := range []string{
+		"### Request body\n\nRequired.\n\n",
+		"- `application/json`: [Store](schemas.md#store)\n",
+		"- `text/plain`: `string`\n",
+	} {
+		if !strings.Contains(store, part) {
+			t.Fatalf("store-admin.md missing %q:\n%s", part, store)
+		}
+	}
+	if other := readFile(t, dir, "docs/other.md"); !strings.Contains(other, "## GET /health\n") {
+		t.Fatalf("untagged endpoint missing from other.md:\n%s", other)
+	}
+
+	schemas := readFile(t, dir, "docs/schemas.md")
+	for _, part := range []string{
+		"## Pet\n\nType: `object`\n",
+		"| `id` | `integer` | yes | Unique id |\n",
+		"| `tags` | array of [Tag](schemas.md#tag) | no | - |\n",
+		"| `age` | `integer` (`minimum>=0`, `maximum<30`) | no | - |\n",
+	} {
+		if !strings.Contains(schemas, part) {
+			t.Fatalf("schemas.md missing %q:\n%s", part, schemas)
+		}
+	}
+}
+
+func TestEmit_DryRunAndErrors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, DryRun: true}); err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("dry-run should not create output, stat err=%v", err)
+	}
+	if _, err := Emit(context.Background(), nil, Options{OutDir: dir}); err == nil {
+		t.Fatalf("expected error for nil model")
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{}); err == nil {
+		t.Fatalf("expected error for empty OutDir")
+	}
+}
+
+func TestSlugAndAnchor(t *testing.T) {
+	used := map[string]bool{"index.md": true}
+	if got := uniqueFile(used, slug("Index")); got != "index-2.md" {
+		t.Fatalf("uniqueFile: got %q", got)
+	}
+	if got := anchor("Pet Owner.v2"); got != "pet-ownerv2" {
+		t.Fatalf("anchor: got %q", got)
+	}
+}
+
+func TestUniqueFileLongTags(t *testing.T) {
+	used := map[string]bool{}
+	long := slug(strings.Repeat("Very Long Tag ", 30))
+	first, second := uniqueFile(used, long), uniqueFile(used, long)
+	for _, name := range []string{first, second} {
+		if len(strings.TrimSuffix(name, ".md")) > maxFileStem {
+			t.Fatalf("%q exceeds %d bytes", name, maxFileStem)
+		}
+	}
+	if first == second {
+		t.Fatalf("both tags got %q", first)
+	}
+	if again := uniqueFile(map[string]bool{}, long); again != first {
+		t.Fatalf("file name differs between runs: %q, then %q", first, again)
+	}
+}
@@ -0,0 +1,109 @@
+package modelemitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// ModelFile is the only file written by the emitter.
+const ModelFile = "model.json"
+
+// Options controls how the model emitter writes the ServiceModel.
+type Options struct {
+	OutDir            string // required; target directory to write model.json
+	ToolName          string // recorded in the manifest
+	Compact           bool   // write the JSON on one line instead of indented
+	Force             bool   // overwrite existing files
+	OverwriteModified bool   // with Force, also replace files edited since the last run and files it did not generate
+	Prune             bool   // delete files the last run generated that are no longer produced
+	Concurrency       int    // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun            bool   // don't write, only plan
+	Verbose           bool
+}
+
+// PlannedFile describes a file the emitter intends to write.
+type PlannedFile struct {
+	RelPath string
+	Size    int
+	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
+}
+
+// Result returns the planned files and final resolved names.
+type Result struct {
+	ToolName string
+	Planned  []PlannedFile
+	Skipped  bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome  manifest.Outcome // files kept or pruned because of the last run's manifest
+}
+
+// Emit writes the provided ServiceModel (IM) as model.json, the same document
+// the generated servers embed, for tools that consume the model directly.
+func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result, error) {
+	_ = ctx
+	if sm == nil {
+		return nil, fmt.Errorf("modelemitter: nil ServiceModel")
+	}
+	if strings.TrimSpace(opts.OutDir) == "" {
+		return nil, fmt.Errorf("modelemitter: OutDir is required")
+	}
+	toolName := strings.TrimSpace(opts.ToolName)
+	if toolName == "" {
+		toolName = "mcp-tool"
+	}
+
+	data, err := Marshal(sm, opts.Compact)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{ModelFile: data}
+
+	planned := []PlannedFile{{
+		RelPath: ModelFile,
+		Size:    len(data),
+		Mode:    0o644,
+		SHA256:  manifest.HashBytes(data),
+		Change:  filewriter.Classify(opts.OutDir, ModelFile, data, 0o644),
+	}}
+	res := &Result{ToolName: toolName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "model", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		w := &filewriter.Writer{Prefix: "modelemitter", Concurrency: opts.Concurrency, Force: opts.Force}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	}
+	return res, nil
+}
+
+// Marshal returns sm as model.json content: indented with two spaces like the
+// model.json of the generated projects, or on one line when compact, and
+// ending in a newline either way.
+func Marshal(sm *genspec.ServiceModel, compact bool) ([]byte, error) {
+	var data []byte
+	var err error
+	if compact {
+		data, err = json.Marshal(sm)
+	} else {
+		data, err = json.MarshalIndent(sm, "", "  ")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("modelemitter: marshal model: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,131 @@
+package modelemitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func sampleModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Title:   "Pet Store",
+		Version: "1.0.0",
+		Servers: []genspec.Server{{URL: "https://api.example.com/v1"}},
+		Tags:    []string{"pets"},
+		Endpoints: []genspec.EndpointModel{
+			{
+				ID:      "get /pets/{petId}",
+				Method:  genspec.GET,
+				Path:    "/pets/{petId}",
+				Summary: "Get pet",
+				Tags:    []string{"pets"},
+				Parameters: []genspec.ParameterModel{
+					{Name: "petId", In: "path", Required: true},
+				},
+			},
+		},
+		Schemas: map[string]genspec.Schema{
+			"Pet": {Name: "Pet", Type: "object"},
+		},
+	}
+}
+
+func TestEmit_WritesModel(t *testing.T) {
+	dir := t.TempDir()
+	sm := sampleModel()
+	res, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "pets"})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	if len(res.Planned) != 1 || res.Planned[0].RelPath != ModelFile {
+		t.Fatalf("planned: %+v", res.Planned)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ModelFile))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("{\n  \"")) || !bytes.HasSuffix(data, []byte("}\n")) {
+		t.Fatalf("model.json is not indented:\n%s", data)
+	}
+	var got genspec.ServiceModel
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(&got, sm) {
+		t.Fatalf("round trip:\n got %+v\nwant %+v", got, *sm)
+	}
+
+	// A second run with the same model is up to date and writes nothing.
+	res, err = Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "pets"})
+	if err != nil {
+		t.Fatalf("second emit: %v", err)
+	}
+	if !res.Skipped {
+		t.Fatalf("second emit should be skipped")
+	}
+}
+
+func TestEmit_Compact(t *testing.T) {
+	dir := t.TempDir()
+	res, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, Compact: true})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ModelFile))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Count(string(data), "\n") != 1 || !strings.HasSuffix(string(data), "}\n") {
+		t.Fatalf("compact model.json should be one line:\n%s", data)
+	}
+	if res.Planned[0].Size != len(data) {
+		t.Fatalf("planned size %d, wrote %d bytes", res.Planned[0].Size, len(data))
+	}
+}
+
+func TestEmit_ExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ModelFile), []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir}); err == nil {
+		t.Fatalf("expected error for an existing model.json without Force")
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, Force: true, OverwriteModified: true}); err != nil {
+		t.Fatalf("force: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ModelFile))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(data), `"Title": "Pet Store"`) {
+		t.Fatalf("model.json was not replaced:\n%s", data)
+	}
+}
+
+func TestEmit_DryRunAndErrors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	res, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if len(res.Planned) != 1 || res.Planned[0].SHA256 == "" {
+		t.Fatalf("dry-run plan: %+v", res.Planned)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("dry-run should not create output, stat err=%v", err)
+	}
+	if _, err := Emit(context.Background(), nil, Options{OutDir: dir}); err == nil {
+		t.Fatalf("expected error for nil model")
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{}); err == nil {
+		t.Fatalf("expected error for empty OutDir")
+	}
+}
@@ -0,0 +1,140 @@
+// Package modelshard splits the service model of a generated project into
+// per-tag shards, so servers for large APIs read only the endpoints they use.
+package modelshard
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+const (
+	// Dir is the directory, next to the loader, that holds the shards.
+	Dir = "model"
+	// IndexFile is the shard index in Dir. The leading "_" keeps it apart
+	// from the tag shards, whose names never start with one.
+	IndexFile = "_index.json"
+	// Untagged names the shard of the endpoints without a tag.
+	Untagged = "_untagged"
+)
+
+// maxNameLen bounds the shard names derived from tags.
+const maxNameLen = 64
+
+// Shard describes one shard file of the index.
+type Shard struct {
+	Name      string // file name in Dir without ".json"
+	Tag       string // "" for Untagged
+	Endpoints int
+}
+
+// EndpointShard records the shard of one endpoint.
+type EndpointShard struct {
+	ID    string
+	Shard string
+}
+
+// Index is the content of IndexFile: the model with an empty Endpoints list,
+// its shards in the order their first endpoint appears, and the shard of
+// every endpoint in model order, from which loaders rebuild the full model.
+type Index struct {
+	genspec.ServiceModel
+	Shards         []Shard
+	EndpointShards []EndpointShard
+}
+
+// Files returns the index and the shards of sm, keyed by their
+// slash-separated path below the loader's directory (model/_index.json,
+// model/<tag>.json, ...). An endpoint goes into the shard of its first tag,
+// an endpoint without tags into model/_untagged.json.
+func Files(sm *genspec.ServiceModel) (map[string][]byte, error) {
+	idx, shards := Split(sm)
+	files := make(map[string][]byte, len(shards)+1)
+	b, err := marshal(idx)
+	if err != nil {
+		return nil, fmt.Errorf("encode model index: %w", err)
+	}
+	files[path.Join(Dir, IndexFile)] = b
+	for _, s := range idx.Shards {
+		b, err := marshal(shards[s.Name])
+		if err != nil {
+			return nil, fmt.Errorf("encode model shard %s: %w", s.Name, err)
+		}
+		files[path.Join(Dir, s.Name+".json")] = b
+	}
+	return files, nil
+}
+
+// Split returns the index of sm and its endpoints by shard name.
+func Split(sm *genspec.ServiceModel) (*Index, map[string][]genspec.EndpointModel) {
+	idx := &Index{ServiceModel: *sm}
+	idx.Endpoints = []genspec.EndpointModel{}
+	idx.EndpointShards = make([]EndpointShard, 0, len(sm.Endpoints))
+	shards := map[string][]genspec.EndpointModel{}
+	byTag := map[string]int{}
+	used := map[string]bool{}
+	for _, ep := range sm.Endpoints {
+		tag := ""
+		if len(ep.Tags) > 0 {
+			tag = ep.Tags[0]
+		}
+		i, ok := byTag[tag]
+		if !ok {
+			name := Untagged
+			if tag != "" {
+				name = uniqueName(tag, used)
+			}
+			used[name] = true
+			i = len(idx.Shards)
+			byTag[tag] = i
+			idx.Shards = append(idx.Shards, Shard{Name: name, Tag: tag})
+		}
+		s := &idx.Shards[i]
+		s.Endpoints++
+		shards[s.Name] = append(shards[s.Name], ep)
+		idx.EndpointShards = append(idx.EndpointShards, EndpointShard{ID: ep.ID, Shard: s.Name})
+	}
+	return idx, shards
+}
+
+// uniqueName returns the shard name of tag: its lower-case slug of letters,
+// digits and "-", bounded by emitter.ShortName and suffixed with -2, -3, ...
+// when another tag already took it.
+func uniqueName(tag string, used map[string]bool) string {
+	base := slug(tag)
+	name := emitter.ShortName(base, maxNameLen)
+	for n := 2; used[name]; n++ {
+		name = emitter.ShortName(fmt.Sprintf("%s-%d", base, n), maxNameLen)
+	}
+	return name
+}
+
+func slug(tag string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(tag) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if s := strings.TrimRight(b.String(), "-"); s != "" {
+		return s
+	}
+	return "tag"
+}
+
+func marshal(v any) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
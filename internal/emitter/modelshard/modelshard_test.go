@@ -0,0 +1,83 @@
+package modelshard
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func TestFiles(t *testing.T) {
+	sm := &genspec.ServiceModel{
+		Title: "Pets",
+		Tags:  []string{"Pets", "pets!", "Store"},
+		Endpoints: []genspec.EndpointModel{
+			{ID: "get /pets", Tags: []string{"Pets", "Store"}},
+			{ID: "get /health"},
+			{ID: "get /orders", Tags: []string{"Store"}},
+			{ID: "post /pets", Tags: []string{"Pets"}},
+			{ID: "get /other", Tags: []string{"pets!"}},
+			{ID: "get /odd", Tags: []string{"???"}},
+		},
+	}
+	files, err := Files(sm)
+	if err != nil {
+		t.Fatalf("files: %v", err)
+	}
+	var names []string
+	for rel := range files {
+		names = append(names, rel)
+	}
+	sort.Strings(names)
+	want := []string{"model/_index.json", "model/_untagged.json", "model/pets-2.json", "model/pets.json", "model/store.json", "model/tag.json"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+
+	var idx Index
+	if err := json.Unmarshal(files["model/_index.json"], &idx); err != nil {
+		t.Fatalf("decode index: %v", err)
+	}
+	if idx.Title != "Pets" || idx.Endpoints == nil || len(idx.Endpoints) != 0 {
+		t.Fatalf("index must keep the model without endpoints: %+v", idx.ServiceModel)
+	}
+	wantShards := []Shard{
+		{Name: "pets", Tag: "Pets", Endpoints: 2},
+		{Name: "_untagged", Endpoints: 1},
+		{Name: "store", Tag: "Store", Endpoints: 1},
+		{Name: "pets-2", Tag: "pets!", Endpoints: 1},
+		{Name: "tag", Tag: "???", Endpoints: 1},
+	}
+	if !reflect.DeepEqual(idx.Shards, wantShards) {
+		t.Fatalf("shards = %+v, want %+v", idx.Shards, wantShards)
+	}
+	if len(idx.EndpointShards) != 6 || idx.EndpointShards[1] != (EndpointShard{ID: "get /health", Shard: "_untagged"}) {
+		t.Fatalf("endpoint shards = %+v", idx.EndpointShards)
+	}
+
+	var pets []genspec.EndpointModel
+	if err := json.Unmarshal(files["model/pets.json"], &pets); err != nil {
+		t.Fatalf("decode shard: %v", err)
+	}
+	if len(pets) != 2 || pets[0].ID != "get /pets" || pets[1].ID != "post /pets" {
+		t.Fatalf("pets shard = %+v", pets)
+	}
+	if len(sm.Endpoints) != 6 {
+		t.Fatal("Files must not modify the model")
+	}
+}
+
+func TestShardNameBound(t *testing.T) {
+	long := strings.Repeat("very long tag ", 10)
+	idx, _ := Split(&genspec.ServiceModel{Endpoints: []genspec.EndpointModel{
+		{ID: "a", Tags: []string{long + "one"}},
+		{ID: "b", Tags: []string{long + "two"}},
+	}})
+	a, b := idx.Shards[0].Name, idx.Shards[1].Name
+	if len(a) > maxNameLen || len(b) > maxNameLen || a == b {
+		t.Fatalf("shard names must stay bounded and distinct: %q, %q", a, b)
+	}
+}
@@ -0,0 +1,40 @@
+package npmemitter
+
+// dependency is an npm package a generated package.json can depend on.
+type dependency struct {
+	versionRange string // written by default
+	pinned       string // exact release written with Options.PinDependencies
+}
+
+// dependencies is the version table behind the dependencies and
+// devDependencies of every generated package.json. Pinned releases satisfy
+// their range.
+var dependencies = map[string]dependency{
+	"@jest/globals":                    {"^29.7.0", "29.7.0"},
+	"@opentelemetry/api":               {"^1.9.0", "1.9.0"},
+	"@types/node":                      {"^20.11.0", "20.11.30"},
+	"@typescript-eslint/eslint-plugin": {"^7.0.0", "7.18.0"},
+	"@typescript-eslint/parser":        {"^7.0.0", "7.18.0"},
+	"eslint":                           {"^8.57.0", "8.57.1"},
+	"eslint-config-prettier":           {"^9.1.0", "9.1.0"},
+	"jest":                             {"^29.7.0", "29.7.0"},
+	"prettier":                         {"^3.2.5", "3.2.5"},
+	"ts-jest":                          {"^29.1.2", "29.1.2"},
+	"typescript":                       {"^5.4.0", "5.4.5"},
+	"vitest":                           {"^1.5.0", "1.6.0"},
+	"zod":                              {"^3.23.8", "3.23.8"},
+}
+
+// dependencyVersions returns the package.json entries of names: each
+// package's range, or its exact release when pinned is set.
+func dependencyVersions(pinned bool, names ...string) map[string]string {
+	out := make(map[string]string, len(names))
+	for _, name := range names {
+		if pinned {
+			out[name] = dependencies[name].pinned
+		} else {
+			out[name] = dependencies[name].versionRange
+		}
+	}
+	return out
+}
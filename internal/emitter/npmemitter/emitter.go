@@ -6,21 +6,76 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
-	"time"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/docs"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/fileexclude"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/license"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/modelshard"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/tmploverride"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
+// Test runners the generated test suite can run under.
+const (
+	TestRunnerVitest = "vitest" // vitest run; the tests import describe, it and expect from vitest
+	TestRunnerJest   = "jest"   // jest with ts-jest and a jest.config.js; the tests import them from @jest/globals
+)
+
 // Options controls how the npm/TypeScript emitter renders a project.
 type Options struct {
-	OutDir      string // required; target directory to write the project
-	ToolName    string // CLI/tool name; used in README and semantics
-	PackageName string // npm package name; defaults to derived tool name when empty
-	Force       bool   // overwrite existing files
-	DryRun      bool   // don't write, only plan
-	Verbose     bool
+	OutDir             string // required; target directory to write the project
+	ToolName           string // CLI/tool name; used in README and semantics
+	PackageName        string // npm package name; defaults to derived tool name when empty
+	ESM                bool   // emit an ES module package ("type": "module", output in dist/esm); CommonJS otherwise
+	License            string // SPDX identifier (MIT, Apache-2.0, ...); when set, writes LICENSE and sets "license" in package.json
+	Author             string // package author in package.json and the MCPB manifest, and the LICENSE copyright holder; defaults to "Generated by swagger2mcp"
+	AuthorEmail        string // author's e-mail address, added next to Author
+	ProjectVersion     string // semantic version of package.json and the MCPB manifest; defaults to the spec's version when that is one, else 0.1.0
+	Library            bool   // emit only src/spec (model, loader, model.json) as a publishable package; no server, manifest or tests
+	GenerateZodSchemas bool   // emit src/spec/schemas.ts with a Zod schema per ServiceModel.Schemas entry; adds zod as a dependency
+	PinDependencies    bool   // write exact dependency versions to package.json instead of ^ ranges
+	EnableInvoke       bool   // add the callEndpoint tool (src/mcp/methods/callEndpoint.ts), which sends requests to the upstream API
+	EmitDocs           bool   // add docs/API.md, the Markdown reference of the endpoints and schemas that all emitters render alike (see package docs)
+	EmitDockerfile     bool   // emit a multi-stage Dockerfile, a .dockerignore, a docker-compose.yml serving HTTP on port 3000, Makefile docker-build/docker-run targets and docker:build/docker:run scripts; server layout only
+	GenerateCI         bool   // emit .github/workflows/ci.yml, a GitHub Actions workflow that installs, builds, lints and tests the project; server layout only
+	WithOTel           bool   // trace each tool call with @opentelemetry/api, a no-op until the host registers an SDK; server layout only
+	ShardModelByTag    bool   // write the model as an index and per-tag shards under src/spec/model/, read lazily by loadShard and loadTag, instead of one model.json
+	TestRunner         string // TestRunnerVitest (default when empty) or TestRunnerJest; server layout only
+	Force              bool   // overwrite existing files
+	OverwriteModified  bool   // with Force, also replace files edited since the last run and files it did not generate
+	Prune              bool   // delete files the last run generated that are no longer produced
+	Concurrency        int    // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun             bool   // don't write, only plan
+	Verbose            bool
+
+	// TemplateOverrideDir holds files that replace the generated file at the
+	// same relative path (e.g. README.md, src/index.ts). They are rendered as
+	// text/template with an emitter.TemplateContext; files without an
+	// override keep the built-in.
+	// Under npm/ it holds named overrides instead: npm/<name>.tmpl, where
+	// <name> is one of TemplateNames, replaces that file after the same-path
+	// overrides, and any other file under npm/ is an error listing the valid
+	// names. Other languages' directories are ignored.
+	TemplateOverrideDir string
+	// Provenance records how the model was built; template overrides see it
+	// as .Provenance.
+	Provenance *manifest.Provenance
+	// ExcludeFiles are globs (path.Match syntax, against the slash-separated
+	// relative path, or the file name when a pattern has no "/") of generated
+	// files to leave out, e.g. .eslintrc.json. Files the project needs to build
+	// cannot be excluded.
+	ExcludeFiles []string
+	// Skip names categories of generated files to leave out (see
+	// emitter.SkipCategories): tests drops __tests__/ and testdata/, lint
+	// .eslintrc.json, makefile the Makefile, readme README.md and editor
+	// .editorconfig and .vscode/. package.json, the Makefile, launch.json
+	// and the README drop the scripts, targets, dependencies and sections of
+	// skipped files.
+	Skip []string
 }
 
 // PlannedFile describes a file the emitter intends to write.
@@ -28,6 +83,8 @@ type PlannedFile struct {
 	RelPath string
 	Size    int
 	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
 }
 
 // Result returns the planned files and final resolved names.
@@ -35,6 +92,8 @@ type Result struct {
 	ToolName    string
 	PackageName string
 	Planned     []PlannedFile
+	Skipped     bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome     manifest.Outcome // files kept or pruned because of the last run's manifest
 }
 
 // Emit renders a Node/TypeScript MCP tool project using the provided ServiceModel (IM).
@@ -46,6 +105,17 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 	if strings.TrimSpace(opts.OutDir) == "" {
 		return nil, fmt.Errorf("npmemitter: OutDir is required")
 	}
+	if v := strings.TrimSpace(opts.ProjectVersion); v != "" && !genspec.IsSemver(v) {
+		return nil, fmt.Errorf("npmemitter: invalid ProjectVersion %q (expected a semantic version, e.g. 1.2.3)", opts.ProjectVersion)
+	}
+	licenseID := ""
+	if opts.License != "" {
+		id, err := license.Normalize(opts.License)
+		if err != nil {
+			return nil, fmt.Errorf("npmemitter: %w", err)
+		}
+		licenseID = id
+	}
 	toolName := sanitizeToolName(opts.ToolName)
 	if toolName == "" {
 		toolName = deriveToolName(sm.Title)
@@ -58,9 +128,194 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 		pkgName = toolName
 	}
 
-	tmplData := newTemplateData(toolName, pkgName, sm)
+	// Templates render from a copy with blank Title/Version/Description
+	// filled in; the spec hash below is still computed from sm.
+	model, notes := genspec.FillDefaults(sm, toolName)
+	if opts.Verbose {
+		for _, n := range notes {
+			fmt.Fprintf(os.Stderr, "[INFO] npmemitter: %s\n", n)
+		}
+	}
+
+	skip, err := emitter.ParseSkip(opts.Skip)
+	if err != nil {
+		return nil, fmt.Errorf("npmemitter: %w", err)
+	}
+	testRunner := strings.TrimSpace(opts.TestRunner)
+	switch testRunner {
+	case "":
+		testRunner = TestRunnerVitest
+	case TestRunnerVitest, TestRunnerJest:
+	default:
+		return nil, fmt.Errorf("npmemitter: unsupported TestRunner %q (allowed: %s, %s)", opts.TestRunner, TestRunnerVitest, TestRunnerJest)
+	}
+
+	tmplData := newTemplateData(toolName, pkgName, model)
+	tmplData.skip = skip
+	tmplData.esm = opts.ESM
+	tmplData.zod = opts.GenerateZodSchemas
+	tmplData.pinned = opts.PinDependencies
+	tmplData.invoke = opts.EnableInvoke
+	tmplData.docker = opts.EmitDockerfile
+	tmplData.ci = opts.GenerateCI && !opts.Library
+	tmplData.otel = opts.WithOTel
+	tmplData.shardModel = opts.ShardModelByTag
+	tmplData.testRunner = testRunner
+	tmplData.license = licenseID
+	tmplData.version = genspec.ProjectVersion(opts.ProjectVersion, sm.Version)
+	if author := strings.TrimSpace(opts.Author); author != "" {
+		tmplData.author = author
+	}
+	tmplData.authorEmail = strings.TrimSpace(opts.AuthorEmail)
+
+	var files map[string][]byte
+	if opts.Library {
+		files, err = libraryFiles(tmplData, model)
+	} else {
+		files, err = projectFiles(tmplData, model)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if licenseID != "" {
+		text, err := license.Render(licenseID, tmplData.year, tmplData.author)
+		if err != nil {
+			return nil, err
+		}
+		files[license.FileName] = []byte(text)
+	}
+	if opts.EmitDocs {
+		files[filepath.FromSlash(docs.File)] = []byte(docs.Render(model, model.Title))
+	}
+	tmplCtx := templateContext(tmplData, model, opts)
+	render := func(rel, text string) (string, error) {
+		out, err := tmplCtx.Render(rel, text)
+		return normalize(out), err
+	}
+	if err := tmploverride.Apply(opts.TemplateOverrideDir, files, render); err != nil {
+		return nil, fmt.Errorf("npmemitter: %w", err)
+	}
+	identity := func(name string) string { return name }
+	if err := tmploverride.ApplyNamed(opts.TemplateOverrideDir, "npm", templateNames, identity, files, render); err != nil {
+		return nil, fmt.Errorf("npmemitter: %w", err)
+	}
+	if err := fileexclude.Apply(skip.Patterns(skipFiles), files, essentialFile); err != nil {
+		return nil, fmt.Errorf("npmemitter: %w", err)
+	}
+	if err := fileexclude.Apply(opts.ExcludeFiles, files, essentialFile); err != nil {
+		return nil, fmt.Errorf("npmemitter: %w", err)
+	}
+
+	// Plan in deterministic order
+	rels := make([]string, 0, len(files))
+	for p := range files {
+		rels = append(rels, filepath.ToSlash(p))
+	}
+	genspec.SortNames(rels)
+
+	planned := make([]PlannedFile, 0, len(rels))
+	for _, rel := range rels {
+		content := files[rel]
+		planned = append(planned, PlannedFile{
+			RelPath: rel,
+			Size:    len(content),
+			Mode:    0o644,
+			SHA256:  manifest.HashBytes(content),
+			Change:  filewriter.Classify(opts.OutDir, rel, content, 0o644),
+		})
+	}
+
+	// Write if not dry-run
+	res := &Result{ToolName: toolName, PackageName: pkgName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "npm", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		w := &filewriter.Writer{Prefix: "npmemitter", Concurrency: opts.Concurrency, Force: opts.Force}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	}
+
+	return res, nil
+}
+
+// skipFiles maps each Skip category to the files it leaves out.
+var skipFiles = map[string][]string{
+	emitter.SkipTests:    {"__tests__/*", "testdata/*", "jest.config.js"},
+	emitter.SkipLint:     {".eslintrc.json"},
+	emitter.SkipMakefile: {"Makefile"},
+	emitter.SkipReadme:   {"README.md"},
+	emitter.SkipEditor:   {".editorconfig", ".vscode/*"},
+}
+
+// templateNames registers the built-in templates TemplateOverrideDir can
+// override by name, keyed by the slash-separated path of the file each
+// renders. The model data and docs/API.md are not templates.
+var templateNames = []string{
+	".dockerignore",
+	".editorconfig",
+	".eslintrc.json",
+	".github/workflows/ci.yml",
+	".mcpbignore",
+	".prettierrc.json",
+	".vscode/launch.json",
+	"Dockerfile",
+	"LICENSE",
+	"Makefile",
+	"README.md",
+	"__tests__/callEndpoint.test.ts",
+	"__tests__/describe.test.ts",
+	"__tests__/mcp-methods.test.ts",
+	"__tests__/telemetry.test.ts",
+	"__tests__/transport.test.ts",
+	"docker-compose.yml",
+	"jest.config.js",
+	"manifest.json",
+	"package.json",
+	"src/describe.ts",
+	"src/index.ts",
+	"src/mcp/methods/callEndpoint.ts",
+	"src/mcp/methods/explainParameter.ts",
+	"src/mcp/methods/formatSchema.ts",
+	"src/mcp/methods/getEndpointDetails.ts",
+	"src/mcp/methods/getSchemaDetails.ts",
+	"src/mcp/methods/index.ts",
+	"src/mcp/methods/listEndpoints.ts",
+	"src/mcp/methods/listSchemas.ts",
+	"src/mcp/methods/searchEndpoints.ts",
+	"src/spec/index.ts",
+	"src/spec/loader.ts",
+	"src/spec/model.ts",
+	"src/spec/schemas.ts",
+	"src/telemetry.ts",
+	"src/transport.ts",
+	"testdata/sample.yaml",
+	"tsconfig.json",
+}
+
+// TemplateNames returns the names of the templates TemplateOverrideDir can
+// override by name, each given there as npm/<name>.tmpl.
+func TemplateNames() []string {
+	return append([]string(nil), templateNames...)
+}
+
+// essentialFile reports whether the generated package does not build without
+// rel: package.json, the tsconfig files and everything under src/.
+func essentialFile(rel string) bool {
+	if rel == "package.json" || strings.HasPrefix(rel, "src/") {
+		return true
+	}
+	return strings.HasPrefix(rel, "tsconfig") && strings.HasSuffix(rel, ".json")
+}
 
-	// Build file map
+// projectFiles renders the full MCP server project.
+func projectFiles(tmplData templateData, sm *genspec.ServiceModel) (map[string][]byte, error) {
 	files := map[string][]byte{}
 	// editorconfig + formatting configs
 	files[".editorconfig"] = []byte(renderEditorConfig())
@@ -69,87 +324,109 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 	// package.json
 	files["package.json"] = []byte(renderPackageJSON(tmplData))
 	// .mcpbignore to reduce bundle size
-	files[".mcpbignore"] = []byte(renderMCPBIgnore())
+	files[".mcpbignore"] = []byte(renderMCPBIgnore(tmplData))
 	// tsconfig.json
-	files["tsconfig.json"] = []byte(renderTSConfig())
+	files["tsconfig.json"] = []byte(renderTSConfig(tmplData))
+	// VS Code debug configurations
+	files[filepath.Join(".vscode", "launch.json")] = []byte(renderVSCodeLaunch(tmplData))
 	// Makefile
-	files["Makefile"] = []byte(renderMakefileNpm())
+	files["Makefile"] = []byte(renderMakefileNpm(tmplData))
+	if tmplData.docker {
+		files["Dockerfile"] = []byte(renderDockerfile(tmplData))
+		files[".dockerignore"] = []byte(renderDockerignore())
+		files["docker-compose.yml"] = []byte(renderDockerCompose(tmplData))
+	}
+	if tmplData.ci {
+		files[filepath.Join(".github", "workflows", "ci.yml")] = []byte(renderCIWorkflow(tmplData))
+	}
 	// README
 	files["README.md"] = []byte(renderReadme(tmplData))
-	// src/index.ts bootstrap (minimal stdio MCP server)
-	files[filepath.Join("src", "index.ts")] = []byte(renderIndexTs())
+	// src/index.ts bootstrap (minimal MCP server over stdio or HTTP)
+	files[filepath.Join("src", "index.ts")] = []byte(renderIndexTs(tmplData))
+	files[filepath.Join("src", "transport.ts")] = []byte(renderTransportTs())
+	files[filepath.Join("src", "describe.ts")] = []byte(renderDescribeTs())
+	if tmplData.otel {
+		files[filepath.Join("src", "telemetry.ts")] = []byte(renderTelemetryTs(tmplData))
+	}
 	// spec model + loader + data
 	files[filepath.Join("src", "spec", "model.ts")] = []byte(renderSpecModelTs())
-	modelJSON, err := json.MarshalIndent(sm, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("marshal model.json: %w", err)
+	if err := addSpecData(files, tmplData, sm); err != nil {
+		return nil, err
+	}
+	if tmplData.zod {
+		files[filepath.Join("src", "spec", "schemas.ts")] = []byte(renderZodSchemasTs(sm))
 	}
-	files[filepath.Join("src", "spec", "model.json")] = append(modelJSON, '\n')
-	files[filepath.Join("src", "spec", "loader.ts")] = []byte(renderSpecLoaderTs())
 	// methods
 	files[filepath.Join("src", "mcp", "methods", "listEndpoints.ts")] = []byte(renderListEndpointsTs())
 	files[filepath.Join("src", "mcp", "methods", "searchEndpoints.ts")] = []byte(renderSearchEndpointsTs())
 	files[filepath.Join("src", "mcp", "methods", "getEndpointDetails.ts")] = []byte(renderGetEndpointDetailsTs())
 	files[filepath.Join("src", "mcp", "methods", "listSchemas.ts")] = []byte(renderListSchemasTs())
 	files[filepath.Join("src", "mcp", "methods", "getSchemaDetails.ts")] = []byte(renderGetSchemaDetailsTs())
-	files[filepath.Join("src", "mcp", "methods", "index.ts")] = []byte(renderMethodsIndexTs())
+	files[filepath.Join("src", "mcp", "methods", "explainParameter.ts")] = []byte(renderExplainParameterTs())
+	if tmplData.invoke {
+		files[filepath.Join("src", "mcp", "methods", "callEndpoint.ts")] = []byte(renderCallEndpointTs(tmplData))
+	}
+	files[filepath.Join("src", "mcp", "methods", "formatSchema.ts")] = []byte(renderFormatSchemaTs())
+	files[filepath.Join("src", "mcp", "methods", "index.ts")] = []byte(renderMethodsIndexTs(tmplData))
 	// mcpb manifest
 	files["manifest.json"] = []byte(renderMCPBManifest(tmplData))
 	// tests
-	files[filepath.Join("__tests__", "mcp-methods.test.ts")] = []byte(renderGeneratedTestsTs())
+	files[filepath.Join("__tests__", "mcp-methods.test.ts")] = []byte(tmplData.testSource(renderGeneratedTestsTs()))
+	files[filepath.Join("__tests__", "describe.test.ts")] = []byte(tmplData.testSource(renderDescribeTestsTs()))
+	files[filepath.Join("__tests__", "transport.test.ts")] = []byte(tmplData.testSource(renderTransportTestsTs()))
+	if tmplData.invoke {
+		files[filepath.Join("__tests__", "callEndpoint.test.ts")] = []byte(tmplData.testSource(renderCallEndpointTestsTs()))
+	}
+	if tmplData.otel {
+		files[filepath.Join("__tests__", "telemetry.test.ts")] = []byte(tmplData.testSource(renderTelemetryTestsTs()))
+	}
+	if tmplData.testRunner == TestRunnerJest {
+		files["jest.config.js"] = []byte(renderJestConfig(tmplData))
+	}
 	// testdata sample spec (informational)
 	files[filepath.Join("testdata", "sample.yaml")] = []byte(sampleSpecYAML)
+	return files, nil
+}
 
-	// Plan in deterministic order
-	rels := make([]string, 0, len(files))
-	for p := range files {
-		rels = append(rels, filepath.ToSlash(p))
-	}
-	sort.Strings(rels)
-
-	planned := make([]PlannedFile, 0, len(rels))
-	for _, rel := range rels {
-		planned = append(planned, PlannedFile{RelPath: rel, Size: len(files[rel]), Mode: 0o644})
+// libraryFiles renders only src/spec as a package whose exports are the
+// typed model, the loader and model.json, for building a custom server.
+func libraryFiles(tmplData templateData, sm *genspec.ServiceModel) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	files[".editorconfig"] = []byte(renderEditorConfig())
+	files["package.json"] = []byte(renderLibraryPackageJSON(tmplData))
+	files["tsconfig.json"] = []byte(renderTSConfig(tmplData))
+	files["README.md"] = []byte(renderLibraryReadme(tmplData))
+	files[filepath.Join("src", "spec", "index.ts")] = []byte(renderSpecIndexTs(tmplData))
+	files[filepath.Join("src", "spec", "model.ts")] = []byte(renderSpecModelTs())
+	if err := addSpecData(files, tmplData, sm); err != nil {
+		return nil, err
 	}
-
-	// Write if not dry-run
-	if !opts.DryRun {
-		if err := writeFiles(opts.OutDir, files, opts.Force); err != nil {
-			return nil, err
-		}
+	if tmplData.zod {
+		files[filepath.Join("src", "spec", "schemas.ts")] = []byte(renderZodSchemasTs(sm))
 	}
-
-	return &Result{ToolName: toolName, PackageName: pkgName, Planned: planned}, nil
+	return files, nil
 }
 
-func writeFiles(outDir string, files map[string][]byte, force bool) error {
-	abs, err := filepath.Abs(outDir)
-	if err != nil {
-		return fmt.Errorf("resolve out dir: %w", err)
-	}
-	// Pre-flight: if directory exists and not empty and not force, error.
-	if st, err := os.Stat(abs); err == nil && st.IsDir() && !force {
-		// check emptiness
-		entries, rerr := os.ReadDir(abs)
-		if rerr == nil && len(entries) > 0 {
-			return fmt.Errorf("npmemitter: output directory %q is not empty (use --force to overwrite)", abs)
-		}
-	}
-	for rel, content := range files {
-		p := filepath.Join(abs, rel)
-		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
-			return fmt.Errorf("mkdir: %w", err)
+// addSpecData writes the model data of src/spec and its loader.ts:
+// model.json, or with ShardModelByTag the index and per-tag shards under
+// src/spec/model/.
+func addSpecData(files map[string][]byte, tmplData templateData, sm *genspec.ServiceModel) error {
+	if tmplData.shardModel {
+		shards, err := modelshard.Files(sm)
+		if err != nil {
+			return err
 		}
-		// atomic write via temp file + rename
-		tmp := p + ".tmp-" + time.Now().Format("20060102150405")
-		if err := os.WriteFile(tmp, content, 0o644); err != nil {
-			return fmt.Errorf("write temp %s: %w", rel, err)
+		for rel, b := range shards {
+			files[filepath.Join("src", "spec", filepath.FromSlash(rel))] = b
 		}
-		if err := os.Rename(tmp, p); err != nil {
-			_ = os.Remove(tmp)
-			return fmt.Errorf("rename %s: %w", rel, err)
+	} else {
+		modelJSON, err := json.MarshalIndent(sm, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal model.json: %w", err)
 		}
+		files[filepath.Join("src", "spec", "model.json")] = append(modelJSON, '\n')
 	}
+	files[filepath.Join("src", "spec", "loader.ts")] = []byte(renderSpecLoaderTs(tmplData))
 	return nil
 }
 
@@ -173,25 +450,85 @@ func sanitizeToolName(name string) string {
 	return out
 }
 
+// maxPackageNameLen is npm's limit on the full package name, scope included.
+const maxPackageNameLen = 214
+
+// reservedPackageNames cannot be published as unscoped names.
+var reservedPackageNames = map[string]bool{"node_modules": true, "favicon.ico": true}
+
+// sanitizePackageName returns a valid npm package name, or "" when nothing
+// usable is left. A scoped name ("@scope/name") keeps its scope, with each
+// part lowercased and cleaned on its own: the scope keeps [a-z0-9-], the name
+// [a-z0-9-._] and may not start with a dot or underscore. When either part is
+// empty after cleaning, the whole input is sanitized as an unscoped name.
+// Names longer than npm's 214 characters are truncated, and the reserved
+// unscoped names are rejected.
 func sanitizePackageName(name string) string {
-	// Simplified npm name sanitizer (no scope handling here); keep lowercase, dot, dash
 	name = strings.ToLower(strings.TrimSpace(name))
 	if name == "" {
 		return ""
 	}
+	if scope, rest, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+		scope = keepRunes(strings.ReplaceAll(scope[1:], " ", "-"), "-")
+		scope = strings.Trim(scope, "-")
+		rest = sanitizeUnscopedName(rest)
+		if scope != "" && rest != "" {
+			prefix := "@" + scope + "/"
+			if len(prefix)+1 > maxPackageNameLen {
+				return ""
+			}
+			return prefix + truncateName(rest, maxPackageNameLen-len(prefix))
+		}
+	}
+	out := truncateName(sanitizeUnscopedName(name), maxPackageNameLen)
+	if reservedPackageNames[out] {
+		return ""
+	}
+	return out
+}
+
+// sanitizeUnscopedName keeps lowercase letters, digits, dash, underscore and
+// dot, turning spaces and slashes into dashes. Leading dots and underscores
+// are dropped.
+func sanitizeUnscopedName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
 	// replace spaces and slashes
 	name = strings.ReplaceAll(name, " ", "-")
 	name = strings.ReplaceAll(name, "/", "-")
-	// remove invalid chars
+	out := keepRunes(name, "-_.")
+	out = strings.Trim(strings.TrimLeft(out, "-._"), "-.")
+	return out
+}
+
+// truncateName cuts name to at most n bytes (it only holds ASCII) without
+// leaving a trailing dash or dot.
+func truncateName(name string, n int) string {
+	if len(name) <= n {
+		return name
+	}
+	return strings.TrimRight(name[:n], "-.")
+}
+
+// unscopedName turns a package name into one that is safe as a file name:
+// "@scope/name" becomes "scope-name", the same form npm pack uses for its
+// tarballs. Unscoped names are returned as is.
+func unscopedName(pkg string) string {
+	if scope, rest, ok := strings.Cut(pkg, "/"); ok && strings.HasPrefix(scope, "@") {
+		return scope[1:] + "-" + rest
+	}
+	return pkg
+}
+
+// keepRunes drops every rune of s that is not a lowercase letter, a digit, or
+// one of extra.
+func keepRunes(s, extra string) string {
 	b := strings.Builder{}
-	for _, r := range name {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || strings.ContainsRune(extra, r) {
 			b.WriteRune(r)
 		}
 	}
-	out := b.String()
-	out = strings.Trim(out, "-.")
-	return out
+	return b.String()
 }
 
 func deriveToolName(title string) string {
@@ -208,3 +545,19 @@ func deriveToolName(title string) string {
 	}
 	return strings.Join(parts, "-")
 }
+
+// templateContext returns the data template overrides are rendered with.
+func templateContext(d templateData, model *genspec.ServiceModel, opts Options) *emitter.TemplateContext {
+	c := emitter.NewTemplateContext("npm", d.ToolName, d.PackageName, model)
+	c.Version, c.Author, c.AuthorEmail = d.version, d.author, d.authorEmail
+	c.License, c.Year = d.license, d.year
+	c.Library, c.EnableInvoke, c.PinDependencies = opts.Library, d.invoke, d.pinned
+	c.Dockerfile = d.docker && !opts.Library
+	c.CI = d.ci
+	c.OTel = d.otel && !opts.Library
+	c.ShardModel = d.shardModel
+	c.Skip = d.skip
+	c.Provenance = opts.Provenance
+	c.NPM = &emitter.NPMContext{BundleName: d.BundleName, ESM: d.esm, ZodSchemas: d.zod, TestRunner: d.testRunner}
+	return c
+}
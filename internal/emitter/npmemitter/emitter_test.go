@@ -4,11 +4,17 @@ import (
     "context"
     "encoding/json"
     "os"
+    "os/exec"
     "path/filepath"
+    "reflect"
+    "strconv"
     "strings"
     "testing"
+    "time"
 
+    "github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
     genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+    "gopkg.in/yaml.v3"
 )
 
 func minimalModel() *genspec.ServiceModel {
@@ -46,6 +52,7 @@ func TestEmit_DryRun_Plan(t *testing.T) {
     want := []string{
         "manifest.json",
         "Makefile",
+        filepath.ToSlash(filepath.Join(".vscode", "launch.json")),
         "package.json",
         "tsconfig.json",
         filepath.ToSlash(filepath.Join("src", "index.ts")),
@@ -100,6 +107,14 @@ func TestEmit_WriteAndContents(t *testing.T) {
     if _, err := os.Stat(listPath); err != nil {
         t.Fatalf("missing methods file: %v", err)
     }
+    // endpoints on one path are ordered by compareNames, not by locale
+    for _, name := range []string{"listEndpoints.ts", "searchEndpoints.ts"} {
+        src, err := os.ReadFile(filepath.Join(dir, "src", "mcp", "methods", name))
+        if err != nil { t.Fatalf("read %s: %v", name, err) }
+        if !strings.Contains(string(src), "compareNames(a.method, b.method)") || strings.Contains(string(src), "localeCompare") {
+            t.Fatalf("%s should sort methods with compareNames:\n%s", name, src)
+        }
+    }
 
     // model.json is valid JSON
     modelJSONPath := filepath.Join(dir, "src", "spec", "model.json")
@@ -109,6 +124,26 @@ func TestEmit_WriteAndContents(t *testing.T) {
     if err := json.Unmarshal(j, &v); err != nil {
         t.Fatalf("model.json invalid: %v", err)
     }
+
+    // tsconfig.json enables source maps for debugging
+    tsc, err := os.ReadFile(filepath.Join(dir, "tsconfig.json"))
+    if err != nil { t.Fatalf("read tsconfig.json: %v", err) }
+    if !strings.Contains(string(tsc), "\"sourceMap\": true") || !strings.Contains(string(tsc), "\"declarationMap\": true") {
+        t.Fatalf("tsconfig.json missing source maps: %s", string(tsc))
+    }
+
+    // .vscode/launch.json is valid JSON with a server debug configuration
+    lj, err := os.ReadFile(filepath.Join(dir, ".vscode", "launch.json"))
+    if err != nil { t.Fatalf("read launch.json: %v", err) }
+    var launch struct {
+        Configurations []map[string]any `json:"configurations"`
+    }
+    if err := json.Unmarshal(lj, &launch); err != nil {
+        t.Fatalf("launch.json invalid: %v", err)
+    }
+    if len(launch.Configurations) != 2 || !strings.Contains(string(lj), `"program": "${workspaceFolder}/dist/index.js"`) {
+        t.Fatalf("unexpected launch.json: %s", string(lj))
+    }
 }
 
 func TestEmit_NoForce_NonEmptyDir(t *testing.T) {
@@ -124,3 +159,1228 @@ func TestEmit_NoForce_NonEmptyDir(t *testing.T) {
         t.Fatalf("expected error on non-empty dir without force")
     }
 }
+
+func TestEmit_ForceKeepsUserFiles(t *testing.T) {
+    t.Parallel()
+    ctx := context.Background()
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "tool", PackageName: "pkg"}
+    if _, err := Emit(ctx, minimalModel(), opts); err != nil {
+        t.Fatalf("first emit: %v", err)
+    }
+    readme := filepath.Join(dir, "README.md")
+    notes := filepath.Join(dir, "NOTES.md")
+    if err := os.WriteFile(readme, []byte("edited"), 0o644); err != nil { t.Fatal(err) }
+    if err := os.WriteFile(notes, []byte("mine"), 0o644); err != nil { t.Fatal(err) }
+
+    changed := minimalModel()
+    changed.Version = "2.0.0"
+    opts.Force = true
+    res, err := Emit(ctx, changed, opts)
+    if err != nil { t.Fatalf("forced emit: %v", err) }
+    if b, _ := os.ReadFile(readme); string(b) != "edited" { t.Fatalf("edited README.md was overwritten") }
+    if b, _ := os.ReadFile(notes); string(b) != "mine" { t.Fatalf("hand-added file was touched") }
+    if len(res.Outcome.Modified) != 1 || res.Outcome.Modified[0] != "README.md" {
+        t.Fatalf("expected README.md reported as modified, got %+v", res.Outcome)
+    }
+}
+
+func TestSanitizePackageName_Scoped(t *testing.T) {
+    t.Parallel()
+    cases := []struct{ in, want string }{
+        // valid scoped names
+        {"@myorg/my-mcp-tool", "@myorg/my-mcp-tool"},
+        {"  @MyOrg/My-MCP-Tool ", "@myorg/my-mcp-tool"},
+        {"@my-org/tool.v2_beta", "@my-org/tool.v2_beta"},
+        // scoped names with invalid characters, cleaned per part
+        {"@my_org!/my tool", "@myorg/my-tool"},
+        {"@my.org/tool$", "@myorg/tool"},
+        {"@org/a/b", "@org/a-b"},
+        // malformed scope: fall back to an unscoped name
+        {"@/tool", "tool"},
+        {"@!!/tool", "tool"},
+        {"@org/", "org"},
+        {"@org/_tool", "@org/tool"},
+        {"@org/node_modules", "@org/node_modules"},
+        {"myorg/tool", "myorg-tool"},
+        // unscoped names are unchanged
+        {"Example MyTool", "example-mytool"},
+        {"_private.tool", "private.tool"},
+        {"node_modules", ""},
+        {"favicon.ico", ""},
+        {"", ""},
+    }
+    for _, tc := range cases {
+        if got := sanitizePackageName(tc.in); got != tc.want {
+            t.Errorf("sanitizePackageName(%q) = %q, want %q", tc.in, got, tc.want)
+        }
+    }
+    long := sanitizePackageName("@org/" + strings.Repeat("a", 300))
+    if len(long) != 214 || !strings.HasPrefix(long, "@org/aaa") {
+        t.Errorf("long scoped name not capped at 214 characters: %d", len(long))
+    }
+    for in, want := range map[string]string{"@myorg/my-mcp-tool": "myorg-my-mcp-tool", "my-mcp-tool": "my-mcp-tool"} {
+        if got := unscopedName(in); got != want {
+            t.Errorf("unscopedName(%q) = %q, want %q", in, got, want)
+        }
+    }
+
+    dir := t.TempDir()
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", PackageName: "@MyOrg/my-mcp-tool"})
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if res.PackageName != "@myorg/my-mcp-tool" {
+        t.Fatalf("result package name = %q", res.PackageName)
+    }
+    // package.json keeps the scope; the bundle manifest and file names use
+    // the scope-free form so mcpb never sees a slash.
+    for name, want := range map[string]string{"package.json": "@myorg/my-mcp-tool", "manifest.json": "myorg-my-mcp-tool"} {
+        data, err := os.ReadFile(filepath.Join(dir, name))
+        if err != nil { t.Fatalf("read %s: %v", name, err) }
+        var doc struct {
+            Name    string            `json:"name"`
+            Scripts map[string]string `json:"scripts"`
+        }
+        if err := json.Unmarshal(data, &doc); err != nil { t.Fatalf("%s invalid: %v", name, err) }
+        if doc.Name != want {
+            t.Fatalf("%s name = %q, want %q", name, doc.Name, want)
+        }
+        if name == "package.json" && !strings.Contains(doc.Scripts["bundle"], "dist/myorg-my-mcp-tool-$npm_package_version.mcpb") {
+            t.Fatalf("bundle script should use the scope-free name: %q", doc.Scripts["bundle"])
+        }
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "dist/myorg-my-mcp-tool-<version>.mcpb") {
+        t.Fatalf("README bundle command should use the scope-free name:\n%s", readme)
+    }
+}
+
+func TestEmit_BaseURLOverride(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        filepath.Join("src", "spec", "loader.ts"):                   {"export const BASE_URL_ENV = 'API_BASE_URL'", "export function applyBaseUrlOverride(sm: ServiceModel): ServiceModel", "export function baseUrl(sm: ServiceModel): string"},
+        filepath.Join("src", "index.ts"):                            {"sm = applyBaseUrlOverride(loadServiceModel())"},
+        filepath.Join("src", "mcp", "methods", "listEndpoints.ts"): {"import { baseUrl } from '../../spec/loader.js'", "'基础 URL: ' + base"},
+        "README.md": {"API_BASE_URL"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+}
+
+func TestEmit_EndpointDetailsGroupParameters(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    index, err := os.ReadFile(filepath.Join(dir, "src", "index.ts"))
+    if err != nil { t.Fatalf("read index.ts: %v", err) }
+    want := "const parameterLocations: [string, string][] = [['path', '路径参数'], ['query', '查询参数'], ['header', '请求头参数'], ['cookie', 'Cookie参数']]"
+    if !strings.Contains(string(index), want) {
+        t.Fatalf("index.ts should list parameters by location in path, query, header, cookie order:\n%s", index)
+    }
+    if strings.Contains(string(index), "'参数:'") {
+        t.Fatalf("index.ts still lists parameters in a single section")
+    }
+}
+
+func TestEmit_ExplainParameter(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        filepath.Join("src", "mcp", "methods", "explainParameter.ts"): {"export function explainParameter(sm: ServiceModel, endpointId: string, name: string)", "export function formatParameterExplanation(", "import { formatSchemaWithRefs, schemaConstraints } from './formatSchema.js'"},
+        filepath.Join("src", "mcp", "methods", "formatSchema.ts"):     {"export function formatSchemaWithRefs(", "export function schemaConstraints("},
+        filepath.Join("src", "mcp", "methods", "index.ts"):            {"export { explainParameter, formatParameterExplanation } from './explainParameter.js'"},
+        filepath.Join("src", "index.ts"):                              {"name: 'explainParameter'", "if (name === 'explainParameter') {", "import { formatSchemaWithRefs } from './mcp/methods/formatSchema.js'"},
+        filepath.Join("__tests__", "mcp-methods.test.ts"):             {"explains a query enum parameter", "explains a path parameter"},
+        "manifest.json": {`"name": "explainParameter"`},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+    index, _ := os.ReadFile(filepath.Join(dir, "src", "index.ts"))
+    if strings.Contains(string(index), "function formatSchemaWithRefs(") {
+        t.Fatalf("index.ts should import formatSchemaWithRefs, not define it")
+    }
+}
+
+func TestEmit_ModuleFormat(t *testing.T) {
+    t.Parallel()
+    type pkgJSON struct {
+        Type    string            `json:"type"`
+        Scripts map[string]string `json:"scripts"`
+    }
+    type tsconfig struct {
+        CompilerOptions struct {
+            Module           string `json:"module"`
+            ModuleResolution string `json:"moduleResolution"`
+            OutDir           string `json:"outDir"`
+        } `json:"compilerOptions"`
+    }
+    cases := []struct {
+        esm                        bool
+        typ, module, resolution, dist string
+    }{
+        {true, "module", "ES2022", "Bundler", "dist/esm"},
+        {false, "", "Node16", "Node16", "dist"},
+    }
+    for _, tc := range cases {
+        dir := t.TempDir()
+        if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ESM: tc.esm}); err != nil {
+            t.Fatalf("emit (esm=%v): %v", tc.esm, err)
+        }
+        read := func(rel string) []byte {
+            data, err := os.ReadFile(filepath.Join(dir, rel))
+            if err != nil { t.Fatalf("read %s: %v", rel, err) }
+            return data
+        }
+        var pkg pkgJSON
+        if err := json.Unmarshal(read("package.json"), &pkg); err != nil { t.Fatalf("package.json invalid: %v", err) }
+        if pkg.Type != tc.typ {
+            t.Errorf("esm=%v: package.json type = %q, want %q", tc.esm, pkg.Type, tc.typ)
+        }
+        if _, ok := pkg.Scripts["build:esm"]; ok != tc.esm {
+            t.Errorf("esm=%v: build:esm script present = %v", tc.esm, ok)
+        }
+        if !strings.Contains(pkg.Scripts["start"], "node "+tc.dist+"/index.js") {
+            t.Errorf("esm=%v: start script = %q", tc.esm, pkg.Scripts["start"])
+        }
+        var ts tsconfig
+        if err := json.Unmarshal(read("tsconfig.json"), &ts); err != nil { t.Fatalf("tsconfig.json invalid: %v", err) }
+        if co := ts.CompilerOptions; co.Module != tc.module || co.ModuleResolution != tc.resolution || co.OutDir != tc.dist {
+            t.Errorf("esm=%v: compilerOptions = %+v", tc.esm, co)
+        }
+        if !strings.Contains(string(read("manifest.json")), `"entry_point": "`+tc.dist+`/index.js"`) {
+            t.Errorf("esm=%v: manifest entry point should be under %s", tc.esm, tc.dist)
+        }
+        if !strings.Contains(string(read(".vscode/launch.json")), "${workspaceFolder}/"+tc.dist+"/index.js") {
+            t.Errorf("esm=%v: launch.json should run %s/index.js", tc.esm, tc.dist)
+        }
+        loader := string(read("src/spec/loader.ts"))
+        if strings.Contains(loader, "import.meta") != tc.esm {
+            t.Errorf("esm=%v: loader.ts import.meta usage is wrong:\n%s", tc.esm, loader)
+        }
+        if strings.Contains(loader, "require(") {
+            t.Errorf("esm=%v: loader.ts should not use require():\n%s", tc.esm, loader)
+        }
+    }
+}
+
+func TestEmit_LibraryLayout(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool", PackageName: "@myorg/pets-model", ESM: true, Library: true, DryRun: true}
+    res, err := Emit(context.Background(), minimalModel(), opts)
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    var got []string
+    for _, pf := range res.Planned { got = append(got, pf.RelPath) }
+    want := []string{".editorconfig", "package.json", "README.md", "src/spec/index.ts", "src/spec/loader.ts", "src/spec/model.json", "src/spec/model.ts", "tsconfig.json"}
+    if strings.Join(got, ",") != strings.Join(want, ",") {
+        t.Fatalf("library plan = %v, want %v", got, want)
+    }
+
+    opts.DryRun = false
+    if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    data, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+    var pkg struct {
+        Name    string            `json:"name"`
+        Private bool              `json:"private"`
+        Type    string            `json:"type"`
+        Exports map[string]string `json:"exports"`
+        Files   []string          `json:"files"`
+    }
+    if err := json.Unmarshal(data, &pkg); err != nil { t.Fatalf("package.json invalid: %v", err) }
+    if pkg.Name != "@myorg/pets-model" || pkg.Private || pkg.Type != "module" {
+        t.Fatalf("package.json should be a publishable ES module: %s", data)
+    }
+    if pkg.Exports["."] != "./dist/esm/spec/index.js" || pkg.Exports["./model.json"] != "./dist/esm/spec/model.json" {
+        t.Fatalf("exports = %v", pkg.Exports)
+    }
+    if len(pkg.Files) != 1 || pkg.Files[0] != "dist/esm" {
+        t.Fatalf("files = %v", pkg.Files)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "from '@myorg/pets-model'") {
+        t.Fatalf("README should show library usage:\n%s", readme)
+    }
+}
+
+func TestEmit_ZodSchemas(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Schemas = map[string]genspec.Schema{
+        "Pet": {Name: "Pet", Type: "object", Required: []string{"id", "name"}, Properties: map[string]*genspec.SchemaOrRef{
+            "id":     {Schema: &genspec.Schema{Type: "integer"}},
+            "name":   {Schema: &genspec.Schema{Type: "string"}},
+            "status": {Schema: &genspec.Schema{Type: "string", Enum: []any{"available", "sold"}}},
+            "tags":   {Schema: &genspec.Schema{Type: "array", Items: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Tag"}}}},
+            "parent": {Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Pet"}},
+        }},
+        "Tag": {Name: "Tag", Type: "object", Properties: map[string]*genspec.SchemaOrRef{
+            "label": {Schema: &genspec.Schema{Type: "string"}},
+        }},
+    }
+
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ESM: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "src", "spec", "schemas.ts")); !os.IsNotExist(err) {
+        t.Fatalf("schemas.ts should only be generated with GenerateZodSchemas: %v", err)
+    }
+    if data, _ := os.ReadFile(filepath.Join(dir, "package.json")); strings.Contains(string(data), "zod") {
+        t.Fatalf("package.json should not depend on zod by default:\n%s", data)
+    }
+
+    dir = t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ESM: true, GenerateZodSchemas: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    data, err := os.ReadFile(filepath.Join(dir, "src", "spec", "schemas.ts"))
+    if err != nil { t.Fatalf("read schemas.ts: %v", err) }
+    ts := string(data)
+    for _, want := range []string{
+        "import { z } from 'zod'",
+        "export const PetSchema: z.ZodTypeAny = z.object({",
+        "  id: z.number(),\n",
+        "  name: z.string(),\n",
+        "  status: z.enum(['available', 'sold']).optional(),\n",
+        "  tags: z.array(z.lazy(() => TagSchema)).optional(),\n",
+        "  parent: z.lazy(() => PetSchema).optional(),\n",
+        "export const TagSchema = z.object({\n  label: z.string().optional(),\n})",
+    } {
+        if !strings.Contains(ts, want) {
+            t.Errorf("schemas.ts missing %q:\n%s", want, ts)
+        }
+    }
+    var pkg struct {
+        Dependencies map[string]string `json:"dependencies"`
+    }
+    raw, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+    if err := json.Unmarshal(raw, &pkg); err != nil { t.Fatalf("package.json invalid: %v", err) }
+    if pkg.Dependencies["zod"] == "" {
+        t.Fatalf("package.json should depend on zod:\n%s", raw)
+    }
+
+    // Type-checking needs typescript and zod from the registry.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("npm"); err != nil {
+        t.Skip("npm not available")
+    }
+    for _, args := range [][]string{{"npm", "install", "--no-audit", "--no-fund"}, {"npx", "tsc", "--noEmit"}} {
+        cmd := exec.Command(args[0], args[1:]...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_AuthorMetadata(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", License: "isc", Author: "Jane Doe", AuthorEmail: "jane@example.com"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    var pkg struct {
+        License string `json:"license"`
+        Author  string `json:"author"`
+    }
+    data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+    if err != nil {
+        t.Fatalf("read package.json: %v", err)
+    }
+    if err := json.Unmarshal(data, &pkg); err != nil {
+        t.Fatalf("package.json: %v", err)
+    }
+    if pkg.License != "ISC" || pkg.Author != "Jane Doe <jane@example.com>" {
+        t.Fatalf("package.json license/author = %q/%q", pkg.License, pkg.Author)
+    }
+    var manifest struct {
+        License string `json:"license"`
+        Author  struct {
+            Name  string `json:"name"`
+            Email string `json:"email"`
+        } `json:"author"`
+    }
+    data, err = os.ReadFile(filepath.Join(dir, "manifest.json"))
+    if err != nil {
+        t.Fatalf("read manifest.json: %v", err)
+    }
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        t.Fatalf("manifest.json: %v", err)
+    }
+    if manifest.License != "ISC" || manifest.Author.Name != "Jane Doe" || manifest.Author.Email != "jane@example.com" {
+        t.Fatalf("manifest.json license/author = %+v", manifest)
+    }
+
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    data, _ = os.ReadFile(filepath.Join(plain, "package.json"))
+    if strings.Contains(string(data), `"license"`) || strings.Contains(string(data), `"author"`) {
+        t.Fatalf("package.json should not carry metadata by default:\n%s", data)
+    }
+}
+
+func TestEmit_ProjectVersion(t *testing.T) {
+    t.Parallel()
+    undated := minimalModel()
+    undated.Version = "v2"
+    for _, tc := range []struct {
+        sm      *genspec.ServiceModel
+        version string
+        want    string
+    }{
+        {minimalModel(), "3.1.0-beta.2", "3.1.0-beta.2"},
+        {minimalModel(), "", "1.0.0"},
+        {undated, "", genspec.DefaultProjectVersion},
+    } {
+        dir := t.TempDir()
+        if _, err := Emit(context.Background(), tc.sm, Options{OutDir: dir, ToolName: "mytool", ProjectVersion: tc.version}); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        for _, name := range []string{"package.json", "manifest.json"} {
+            var doc struct{ Version string `json:"version"` }
+            data, _ := os.ReadFile(filepath.Join(dir, name))
+            if err := json.Unmarshal(data, &doc); err != nil || doc.Version != tc.want {
+                t.Fatalf("%s version = %q (err %v), want %q", name, doc.Version, err, tc.want)
+            }
+        }
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ProjectVersion: "latest"}); err == nil || !strings.Contains(err.Error(), "invalid ProjectVersion") {
+        t.Fatalf("expected an invalid ProjectVersion error, got %v", err)
+    }
+}
+
+func TestEmit_TagDescriptions(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Tags = []string{"read", "write"}
+    sm.TagDescriptions = map[string]string{"read": "Read-only\n  operations", "write": ""}
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if !strings.Contains(string(readme), "## Tags\n\n- read — Read-only operations\n- write\n\n## Quick Start") {
+        t.Fatalf("README.md should list the tags with their descriptions:\n%s", readme)
+    }
+    model, _ := os.ReadFile(filepath.Join(dir, "src", "spec", "model.ts"))
+    listTs, _ := os.ReadFile(filepath.Join(dir, "src", "mcp", "methods", "listEndpoints.ts"))
+    if !strings.Contains(string(model), "TagDescriptions?: Record<string, string>") || !strings.Contains(string(listTs), "sm.TagDescriptions?.[tag]") {
+        t.Fatalf("the overview should show tag descriptions:\n%s", listTs)
+    }
+
+    untagged := minimalModel()
+    untagged.Endpoints[0].Tags = nil
+    dir = t.TempDir()
+    if _, err := Emit(context.Background(), untagged, Options{OutDir: dir, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ = os.ReadFile(filepath.Join(dir, "README.md"))
+    if strings.Contains(string(readme), "## Tags") {
+        t.Fatalf("README.md of an untagged model should have no Tags section:\n%s", readme)
+    }
+}
+
+func TestEmit_TemplateOverrideDir(t *testing.T) {
+    t.Parallel()
+    tmpl := t.TempDir()
+    if err := os.WriteFile(filepath.Join(tmpl, "README.md"), []byte("# {{.ToolName}} ({{.PackageName}}) for {{.ServiceTitle}}\r\n"), 0o644); err != nil {
+        t.Fatalf("write override: %v", err)
+    }
+    if err := os.MkdirAll(filepath.Join(tmpl, "src"), 0o755); err != nil {
+        t.Fatalf("mkdir: %v", err)
+    }
+    if err := os.WriteFile(filepath.Join(tmpl, "src", "index.ts"), []byte("// custom bootstrap for {{.ToolName}}\n"), 0o644); err != nil {
+        t.Fatalf("write override: %v", err)
+    }
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool", PackageName: "@acme/mytool", TemplateOverrideDir: tmpl}
+    if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    if string(readme) != "# mytool (@acme/mytool) for Sample API\n" {
+        t.Fatalf("README.md should come from the override:\n%q", readme)
+    }
+    index, _ := os.ReadFile(filepath.Join(dir, "src", "index.ts"))
+    if string(index) != "// custom bootstrap for mytool\n" {
+        t.Fatalf("src/index.ts should come from the override:\n%s", index)
+    }
+    // Files without an override keep the built-in template.
+    transport, _ := os.ReadFile(filepath.Join(dir, "src", "transport.ts"))
+    if len(transport) == 0 {
+        t.Fatalf("src/transport.ts should use the built-in template")
+    }
+
+    opts = Options{OutDir: t.TempDir(), ToolName: "mytool", TemplateOverrideDir: filepath.Join(tmpl, "missing")}
+    if _, err := Emit(context.Background(), minimalModel(), opts); err == nil || !strings.Contains(err.Error(), "template override") {
+        t.Fatalf("expected an error for a missing override directory, got %v", err)
+    }
+}
+
+func TestEmit_TemplateContextProbe(t *testing.T) {
+    t.Parallel()
+    probe, err := os.ReadFile(filepath.Join("..", "testdata", "template_context.tmpl"))
+    if err != nil {
+        t.Fatalf("read probe: %v", err)
+    }
+    tmpl := t.TempDir()
+    if err := os.WriteFile(filepath.Join(tmpl, "README.md"), probe, 0o644); err != nil {
+        t.Fatalf("write override: %v", err)
+    }
+    dir := t.TempDir()
+    opts := Options{OutDir: dir, ToolName: "mytool", PackageName: "@acme/mytool", ESM: true, TemplateOverrideDir: tmpl,
+        Provenance: &manifest.Provenance{Generator: "1.2.3", Input: "spec.yaml"}}
+    if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    for _, want := range []string{
+        "schema 1 lang npm\n",
+        "tool mytool package @acme/mytool title Sample API version 1.0.0\n",
+        "input spec.yaml generator 1.2.3\n",
+        "npm acme-mytool esm true zod false tests vitest\n",
+    } {
+        if !strings.Contains(string(readme), want) {
+            t.Errorf("rendered probe lacks %q:\n%s", want, readme)
+        }
+    }
+}
+
+func TestEmit_License(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", License: "apache-2.0", DryRun: true})
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    planned := false
+    for _, pf := range res.Planned {
+        planned = planned || pf.RelPath == "LICENSE"
+    }
+    if !planned {
+        t.Fatalf("dry-run plan should list LICENSE: %+v", res.Planned)
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", License: "MIT"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    data, err := os.ReadFile(filepath.Join(dir, "LICENSE"))
+    if err != nil {
+        t.Fatalf("read LICENSE: %v", err)
+    }
+    year := strconv.Itoa(time.Now().Year())
+    if !strings.HasPrefix(string(data), "MIT License\n\nCopyright (c) "+year+" Generated by swagger2mcp\n") {
+        t.Fatalf("unexpected LICENSE:\n%s", data)
+    }
+
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), License: "WTFPL"}); err == nil || !strings.Contains(err.Error(), `unknown license "WTFPL"`) {
+        t.Fatalf("expected an unknown license error, got %v", err)
+    }
+}
+
+func TestEmit_HTTPTransport(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ESM: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    transport, err := os.ReadFile(filepath.Join(dir, "src", "transport.ts"))
+    if err != nil { t.Fatalf("read transport.ts: %v", err) }
+    for _, want := range []string{"export function parseTransport(argv: string[]): Transport", "env.MCP_HTTP_ADDR", "env.PORT", "export function createHttpServer("} {
+        if !strings.Contains(string(transport), want) {
+            t.Fatalf("transport.ts missing %q:\n%s", want, transport)
+        }
+    }
+    index, _ := os.ReadFile(filepath.Join(dir, "src", "index.ts"))
+    for _, want := range []string{"parseTransport(process.argv.slice(2))", "createHttpServer(handleRequest).listen("} {
+        if !strings.Contains(string(index), want) {
+            t.Fatalf("index.ts missing %q:\n%s", want, index)
+        }
+    }
+    if _, err := os.Stat(filepath.Join(dir, "__tests__", "transport.test.ts")); err != nil {
+        t.Fatalf("transport tests not generated: %v", err)
+    }
+
+    var pkg struct {
+        Scripts map[string]string `json:"scripts"`
+    }
+    raw, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+    if err := json.Unmarshal(raw, &pkg); err != nil { t.Fatalf("package.json invalid: %v", err) }
+    if !strings.Contains(pkg.Scripts["start:http"], "--transport http") {
+        t.Fatalf("package.json should have a start:http script:\n%s", raw)
+    }
+    var manifest struct {
+        Server struct {
+            MCPConfig struct{ Args []string `json:"args"` } `json:"mcp_config"`
+        } `json:"server"`
+        UserConfig map[string]struct{ Default string `json:"default"` } `json:"user_config"`
+    }
+    raw, _ = os.ReadFile(filepath.Join(dir, "manifest.json"))
+    if err := json.Unmarshal(raw, &manifest); err != nil { t.Fatalf("manifest.json invalid: %v", err) }
+    args := strings.Join(manifest.Server.MCPConfig.Args, " ")
+    if !strings.Contains(args, "--transport ${user_config.transport}") || manifest.UserConfig["transport"].Default != "stdio" {
+        t.Fatalf("manifest.json should pass a transport user_config defaulting to stdio:\n%s", raw)
+    }
+
+    // Type-checking needs typescript from the registry.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("npm"); err != nil {
+        t.Skip("npm not available")
+    }
+    for _, args := range [][]string{{"npm", "install", "--no-audit", "--no-fund"}, {"npx", "tsc", "--noEmit"}} {
+        cmd := exec.Command(args[0], args[1:]...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_Describe(t *testing.T) {
+    t.Parallel()
+    for _, opts := range []Options{{ESM: true}, {ESM: false}, {ESM: true, ShardModelByTag: true}} {
+        dir := t.TempDir()
+        opts.OutDir, opts.ToolName = dir, "mytool"
+        if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+            t.Fatalf("emit %+v: %v", opts, err)
+        }
+        index, _ := os.ReadFile(filepath.Join(dir, "src", "index.ts"))
+        if !strings.Contains(string(index), "process.argv.slice(2).includes('--describe')") || !strings.Contains(string(index), "describeServer(sm, tools)") {
+            t.Fatalf("index.ts should print describeServer for --describe:\n%s", index)
+        }
+        loader, _ := os.ReadFile(filepath.Join(dir, "src", "spec", "loader.ts"))
+        if !strings.Contains(string(loader), "export function modelHash(): string") {
+            t.Fatalf("loader.ts should export modelHash:\n%s", loader)
+        }
+        if _, err := os.Stat(filepath.Join(dir, "__tests__", "describe.test.ts")); err != nil {
+            t.Fatalf("describe tests not generated: %v", err)
+        }
+        var pkg struct {
+            Scripts map[string]string `json:"scripts"`
+        }
+        raw, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+        if err := json.Unmarshal(raw, &pkg); err != nil { t.Fatalf("package.json invalid: %v", err) }
+        if want := "node " + map[bool]string{true: "dist/esm", false: "dist"}[opts.ESM] + "/index.js --describe"; !strings.HasSuffix(pkg.Scripts["describe"], want) {
+            t.Fatalf("describe script = %q, want it to run %q", pkg.Scripts["describe"], want)
+        }
+    }
+}
+
+func TestEmit_PinDependencies(t *testing.T) {
+    t.Parallel()
+    for _, library := range []bool{false, true} {
+        for _, pinned := range []bool{false, true} {
+            dir := t.TempDir()
+            if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ESM: true, Library: library, GenerateZodSchemas: true, PinDependencies: pinned}); err != nil {
+                t.Fatalf("emit: %v", err)
+            }
+            var pkg struct {
+                Dependencies    map[string]string `json:"dependencies"`
+                DevDependencies map[string]string `json:"devDependencies"`
+            }
+            raw, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+            if err := json.Unmarshal(raw, &pkg); err != nil {
+                t.Fatalf("package.json invalid: %v", err)
+            }
+            if pkg.Dependencies["zod"] == "" || pkg.DevDependencies["typescript"] == "" {
+                t.Fatalf("library=%v pinned=%v: missing dependencies:\n%s", library, pinned, raw)
+            }
+            for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+                for name, version := range deps {
+                    want := dependencies[name].versionRange
+                    if pinned {
+                        want = dependencies[name].pinned
+                    }
+                    if version != want || (pinned && strings.HasPrefix(version, "^")) {
+                        t.Errorf("library=%v pinned=%v: %s = %q, want %q", library, pinned, name, version, want)
+                    }
+                }
+            }
+        }
+    }
+    // Pinned releases sit inside the default ranges.
+    for name, dep := range dependencies {
+        if !strings.HasPrefix(dep.versionRange, "^") || strings.Split(dep.versionRange[1:], ".")[0] != strings.Split(dep.pinned, ".")[0] {
+            t.Errorf("%s: pinned %s is outside %s", name, dep.pinned, dep.versionRange)
+        }
+    }
+}
+
+func TestEmit_EnableInvoke(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", EnableInvoke: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        filepath.Join("src", "mcp", "methods", "callEndpoint.ts"): {"export async function callEndpoint(sm: ServiceModel, args: CallArgs, base?: string): Promise<CallResult>", "export function buildRequest(", "signal: controller.signal", "export function callRetries(", "import { findEndpoint, notFound } from './explainParameter.js'"},
+        filepath.Join("src", "mcp", "methods", "index.ts"):        {"export { callEndpoint, buildRequest, formatCallResult, CREDENTIALS, CALL_TIMEOUT_ENV, CALL_RETRIES_ENV, type CallArgs, type CallResult, type Credential } from './callEndpoint.js'"},
+        filepath.Join("src", "index.ts"):                          {"name: 'callEndpoint'", "if (name === 'callEndpoint') {", "Promise.all(inflight)"},
+        filepath.Join("__tests__", "callEndpoint.test.ts"):        {"substitutes parameters", "validates before sending", "createServer("},
+        "manifest.json": {`"name": "callEndpoint"`},
+        "README.md":     {"## Calling the API", "API_TIMEOUT", "API_RETRIES"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(plain, "src", "mcp", "methods", "callEndpoint.ts")); !os.IsNotExist(err) {
+        t.Fatalf("callEndpoint.ts written without EnableInvoke (err=%v)", err)
+    }
+    for _, rel := range []string{filepath.Join("src", "index.ts"), "manifest.json"} {
+        data, _ := os.ReadFile(filepath.Join(plain, rel))
+        if strings.Contains(string(data), "callEndpoint") {
+            t.Fatalf("%s mentions callEndpoint without EnableInvoke", rel)
+        }
+    }
+
+    // Type-checking and the vitest suite need packages from the registry.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("npm"); err != nil {
+        t.Skip("npm not available")
+    }
+    for _, args := range [][]string{{"npm", "install", "--no-audit", "--no-fund"}, {"npx", "tsc", "--noEmit"}, {"npx", "vitest", "run", "__tests__/callEndpoint.test.ts"}} {
+        cmd := exec.Command(args[0], args[1:]...)
+        cmd.Dir = dir
+        if out, err := cmd.CombinedOutput(); err != nil {
+            t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, out)
+        }
+    }
+}
+
+func TestEmit_InvokeCredentials(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.SecuritySchemes = map[string]genspec.SecurityScheme{
+        "key":    {Name: "key", Type: "apiKey", In: "query", ParamName: "api_key"},
+        "bearer": {Name: "bearer", Type: "http", Scheme: "bearer"},
+    }
+    sm.Endpoints[0].Security = [][]string{{"key"}, {"bearer"}}
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", EnableInvoke: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, wants := range map[string][]string{
+        filepath.Join("src", "mcp", "methods", "callEndpoint.ts"): {"  key: { kind: 'apiKey', in: 'query', name: 'api_key', envVar: 'MYTOOL_API_KEY' },", "  bearer: { kind: 'bearer', in: 'header', name: 'Authorization', envVar: 'MYTOOL_BEARER_TOKEN' },", "applyCredentials(ep, query, headers, cookies)"},
+        filepath.Join("src", "spec", "model.ts"):                  {"Security?: string[][]"},
+        filepath.Join("__tests__", "callEndpoint.test.ts"):        {"applies credentials", "missing credentials"},
+        "README.md": {"- `MYTOOL_API_KEY`: scheme `key`, sent in the `api_key` query parameter", "- `MYTOOL_BEARER_TOKEN`: scheme `bearer`, sent in the `Authorization: Bearer <token>` header"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+}
+
+func TestEmit_ExcludeFiles(t *testing.T) {
+    t.Parallel()
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", DryRun: true, ExcludeFiles: []string{".eslintrc.json", ".vscode/*", "__tests__/*"}})
+    if err != nil { t.Fatalf("emit: %v", err) }
+    for _, p := range res.Planned {
+        if p.RelPath == ".eslintrc.json" || strings.HasPrefix(p.RelPath, ".vscode/") || strings.HasPrefix(p.RelPath, "__tests__/") {
+            t.Fatalf("excluded file %s still planned", p.RelPath)
+        }
+    }
+    if len(res.Planned) == 0 { t.Fatalf("nothing planned") }
+
+    for _, pattern := range []string{"package.json", "tsconfig.json", "src/index.ts", "*.ts"} {
+        _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", ExcludeFiles: []string{pattern}})
+        if err == nil || !strings.Contains(err.Error(), "which the project needs to build") {
+            t.Fatalf("exclude %q: expected an essential-file error, got %v", pattern, err)
+        }
+    }
+}
+
+func TestEmit_Skip(t *testing.T) {
+    t.Parallel()
+    planned := func(skip ...string) map[string]bool {
+        t.Helper()
+        res, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", ESM: true, EnableInvoke: true, DryRun: true, Skip: skip})
+        if err != nil { t.Fatalf("emit %v: %v", skip, err) }
+        out := map[string]bool{}
+        for _, p := range res.Planned { out[p.RelPath] = true }
+        return out
+    }
+    all := planned()
+    for category, want := range map[string][]string{
+        "tests":    {"__tests__/mcp-methods.test.ts", "__tests__/transport.test.ts", "__tests__/callEndpoint.test.ts", "__tests__/describe.test.ts", "testdata/sample.yaml"},
+        "lint":     {".eslintrc.json"},
+        "makefile": {"Makefile"},
+        "readme":   {"README.md"},
+        "editor":   {".editorconfig", ".vscode/launch.json"},
+    } {
+        got := planned(category)
+        for _, rel := range want {
+            if !all[rel] || got[rel] { t.Fatalf("skip %s: %s planned %v without the category, %v with it", category, rel, all[rel], got[rel]) }
+        }
+        if len(got) != len(all)-len(want) { t.Fatalf("skip %s: planned %d files, want %d: %v", category, len(got), len(all)-len(want), got) }
+    }
+
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ESM: true, EmitDockerfile: true, Skip: []string{"tests", "lint", "makefile"}}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    var pkg struct {
+        Scripts         map[string]string
+        DevDependencies map[string]string
+    }
+    b, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+    if err := json.Unmarshal(b, &pkg); err != nil { t.Fatalf("package.json: %v", err) }
+    for _, script := range []string{"test", "lint"} {
+        if _, ok := pkg.Scripts[script]; ok { t.Fatalf("package.json keeps the %s script: %s", script, b) }
+    }
+    for _, dep := range []string{"vitest", "eslint", "@typescript-eslint/parser"} {
+        if _, ok := pkg.DevDependencies[dep]; ok { t.Fatalf("package.json keeps %s: %s", dep, b) }
+    }
+    if pkg.Scripts["build"] == "" || pkg.DevDependencies["typescript"] == "" { t.Fatalf("package.json lost the build: %s", b) }
+    readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+    for _, unwanted := range []string{"make ", "Debug tests", "set IMAGE"} {
+        if strings.Contains(string(readme), unwanted) { t.Fatalf("README refers to a skipped file (%q):\n%s", unwanted, readme) }
+    }
+    if !strings.Contains(string(readme), "docker build -t mytool .") || !strings.Contains(string(readme), "provides a configuration:") { t.Fatalf("README:\n%s", readme) }
+    launch, _ := os.ReadFile(filepath.Join(dir, ".vscode", "launch.json"))
+    if strings.Contains(string(launch), "vitest") { t.Fatalf("launch.json:\n%s", launch) }
+
+    dir = t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ESM: true, Skip: []string{"tests", "lint", "editor"}}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    mk, _ := os.ReadFile(filepath.Join(dir, "Makefile"))
+    if strings.Contains(string(mk), "test") || strings.Contains(string(mk), "lint") || !strings.Contains(string(mk), "Targets: install build format bundle") { t.Fatalf("Makefile:\n%s", mk) }
+    readme, _ = os.ReadFile(filepath.Join(dir, "README.md"))
+    if strings.Contains(string(readme), ".vscode") { t.Fatalf("README refers to .vscode:\n%s", readme) }
+
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", Skip: []string{"docs"}}); err == nil || !strings.Contains(err.Error(), "unknown skip category") {
+        t.Fatalf("expected an unknown-category error, got %v", err)
+    }
+}
+
+func TestEmit_Dockerfile(t *testing.T) {
+    t.Parallel()
+    planned := func(opts Options) map[string]bool {
+        t.Helper()
+        opts.OutDir, opts.ToolName, opts.DryRun = t.TempDir(), "mytool", true
+        res, err := Emit(context.Background(), minimalModel(), opts)
+        if err != nil { t.Fatalf("emit: %v", err) }
+        out := map[string]bool{}
+        for _, p := range res.Planned { out[p.RelPath] = true }
+        return out
+    }
+    dockerFiles := []string{"Dockerfile", ".dockerignore", "docker-compose.yml"}
+    for _, rel := range dockerFiles {
+        if got := planned(Options{}); got[rel] {
+            t.Fatalf("%s planned without EmitDockerfile: %v", rel, got)
+        }
+        if got := planned(Options{EmitDockerfile: true}); !got[rel] {
+            t.Fatalf("%s not planned with EmitDockerfile: %v", rel, got)
+        }
+    }
+    if got := planned(Options{EmitDockerfile: true, Library: true}); got["Dockerfile"] || got["docker-compose.yml"] {
+        t.Fatalf("library layout should have no Dockerfile: %v", got)
+    }
+
+    for _, esm := range []bool{false, true} {
+        dir := t.TempDir()
+        if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", ESM: esm, EmitDockerfile: true}); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        entry := `ENTRYPOINT ["node", "dist/index.js"]`
+        if esm {
+            entry = `ENTRYPOINT ["node", "dist/esm/index.js"]`
+        }
+        for rel, wants := range map[string][]string{
+            "Dockerfile":    {"FROM node:lts AS build", "npm ci", "npm run build", "FROM node:lts-slim", entry},
+            ".dockerignore": {"node_modules\n", "__tests__\n", "*.md\n"},
+            "docker-compose.yml": {"image: mytool", `command: ["--transport", "http"]`, `PORT: "3000"`, `- "3000:3000"`},
+            "Makefile":      {"IMAGE ?= mytool", "docker-build:\n\tdocker build -t $(IMAGE) .", "docker-run:\n\tdocker run -i --rm $(IMAGE)"},
+            "package.json":  {`"docker:build": "docker build -t mytool ."`, `"docker:run": "docker run -i --rm mytool"`},
+            ".mcpbignore":   {"Dockerfile\n.dockerignore\ndocker-compose.yml\n"},
+            "README.md":     {"## Docker", "make docker-run", "docker compose up"},
+        } {
+            data, err := os.ReadFile(filepath.Join(dir, rel))
+            if err != nil { t.Fatalf("read %s: %v", rel, err) }
+            for _, want := range wants {
+                if !strings.Contains(string(data), want) {
+                    t.Fatalf("%s missing %q:\n%s", rel, want, data)
+                }
+            }
+        }
+    }
+}
+
+func TestEmit_WithOTel(t *testing.T) {
+    t.Parallel()
+    plain := t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool", EnableInvoke: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for _, rel := range []string{"package.json", filepath.Join("src", "index.ts")} {
+        if data, _ := os.ReadFile(filepath.Join(plain, rel)); strings.Contains(string(data), "opentelemetry") || strings.Contains(string(data), "traceToolCall") {
+            t.Fatalf("%s mentions tracing without WithOTel:\n%s", rel, data)
+        }
+    }
+    if _, err := os.Stat(filepath.Join(plain, "src", "telemetry.ts")); !os.IsNotExist(err) {
+        t.Fatalf("telemetry.ts written without WithOTel: %v", err)
+    }
+
+    var dirs []string
+    for _, invoke := range []bool{false, true} {
+        dir := t.TempDir()
+        dirs = append(dirs, dir)
+        if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", EnableInvoke: invoke, WithOTel: true}); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        var pkg struct{ Dependencies map[string]string }
+        data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+        if err != nil { t.Fatalf("read package.json: %v", err) }
+        if err := json.Unmarshal(data, &pkg); err != nil { t.Fatalf("parse package.json: %v", err) }
+        if pkg.Dependencies["@opentelemetry/api"] != dependencies["@opentelemetry/api"].versionRange {
+            t.Fatalf("dependencies = %v", pkg.Dependencies)
+        }
+        index, err := os.ReadFile(filepath.Join(dir, "src", "index.ts"))
+        if err != nil { t.Fatalf("read index.ts: %v", err) }
+        for _, want := range []string{
+            "import { traceToolCall } from './telemetry.js'",
+            "function handleRequest(req: JSONRPCRequest): ReturnType<typeof dispatchRequest> {",
+            "return traceToolCall(String(req.params?.name ?? ''), () => dispatchRequest(req))",
+        } {
+            if !strings.Contains(string(index), want) {
+                t.Fatalf("index.ts missing %q (invoke=%v)", want, invoke)
+            }
+        }
+        if strings.Count(string(index), "function handleRequest(") != 1 || strings.Count(string(index), "function dispatchRequest(") != 1 {
+            t.Fatalf("expected one handleRequest and one dispatchRequest (invoke=%v)", invoke)
+        }
+        for _, rel := range []string{filepath.Join("src", "telemetry.ts"), filepath.Join("__tests__", "telemetry.test.ts")} {
+            if _, err := os.Stat(filepath.Join(dir, rel)); err != nil { t.Fatalf("%s not written: %v", rel, err) }
+        }
+    }
+    // Type-checking the projects needs npm and the registry.
+    if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+        return
+    }
+    if _, err := exec.LookPath("npm"); err != nil {
+        t.Skip("npm not available")
+    }
+    for _, dir := range dirs {
+        for _, args := range [][]string{{"npm", "install", "--no-audit", "--no-fund"}, {"npx", "tsc", "--noEmit"}, {"npx", "vitest", "run"}} {
+            cmd := exec.Command(args[0], args[1:]...)
+            cmd.Dir = dir
+            if out, err := cmd.CombinedOutput(); err != nil {
+                t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, out)
+            }
+        }
+    }
+}
+
+func TestEmit_ShardModelByTag(t *testing.T) {
+    t.Parallel()
+    sm := minimalModel()
+    sm.Endpoints = append(sm.Endpoints, genspec.EndpointModel{ID: "get /health", Method: genspec.GET, Path: "/health"})
+    dir := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "mytool", ESM: true, ShardModelByTag: true}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "src", "spec", "model.json")); !os.IsNotExist(err) {
+        t.Fatalf("model.json written with ShardModelByTag: %v", err)
+    }
+    for _, name := range []string{"_index.json", "read.json", "_untagged.json"} {
+        if _, err := os.Stat(filepath.Join(dir, "src", "spec", "model", name)); err != nil {
+            t.Fatalf("missing shard %s: %v", name, err)
+        }
+    }
+    for rel, wants := range map[string][]string{
+        filepath.Join("src", "spec", "loader.ts"): {"export function loadShard(name: string): EndpointModel[]", "export function loadTag(tag: string): EndpointModel[]", "const __dirname = dirname(__filename)", "join(__dirname, 'model', name + '.json')"},
+        "package.json": {"cp -r src/spec/model dist/esm/spec/"},
+        "README.md":    {"## Model shards"},
+    } {
+        data, err := os.ReadFile(filepath.Join(dir, rel))
+        if err != nil { t.Fatalf("read %s: %v", rel, err) }
+        for _, want := range wants {
+            if !strings.Contains(string(data), want) {
+                t.Fatalf("%s missing %q:\n%s", rel, want, data)
+            }
+        }
+    }
+
+    lib := t.TempDir()
+    if _, err := Emit(context.Background(), sm, Options{OutDir: lib, ToolName: "mytool", Library: true, ShardModelByTag: true}); err != nil {
+        t.Fatalf("emit library: %v", err)
+    }
+    var pkg struct{ Exports map[string]string }
+    data, err := os.ReadFile(filepath.Join(lib, "package.json"))
+    if err != nil { t.Fatalf("read package.json: %v", err) }
+    if err := json.Unmarshal(data, &pkg); err != nil { t.Fatalf("parse package.json: %v", err) }
+    if pkg.Exports["./model/*"] != "./dist/spec/model/*" || pkg.Exports["./model.json"] != "" {
+        t.Fatalf("exports = %v", pkg.Exports)
+    }
+}
+
+func TestEmit_TemplateOverrideDirNamed(t *testing.T) {
+    t.Parallel()
+    // Every file a template renders is registered, and every registered name
+    // is rendered by some combination of options.
+    registered := map[string]bool{}
+    for _, name := range TemplateNames() {
+        registered[name] = false
+    }
+    for _, opts := range []Options{
+        {},
+        {GenerateZodSchemas: true, EnableInvoke: true, EmitDockerfile: true, GenerateCI: true, WithOTel: true, TestRunner: TestRunnerJest, License: "MIT"},
+        {Library: true},
+    } {
+        opts.OutDir, opts.ToolName, opts.DryRun = t.TempDir(), "mytool", true
+        res, err := Emit(context.Background(), minimalModel(), opts)
+        if err != nil { t.Fatalf("emit: %v", err) }
+        for _, f := range res.Planned {
+            name := filepath.ToSlash(f.RelPath)
+            if name == "src/spec/model.json" {
+                continue
+            }
+            if _, ok := registered[name]; !ok {
+                t.Errorf("%s is not in templateNames", name)
+            }
+            registered[name] = true
+        }
+    }
+    for name, seen := range registered {
+        if !seen {
+            t.Errorf("templateNames lists %s, which no tested options generate", name)
+        }
+    }
+
+    tmpl := t.TempDir()
+    for rel, text := range map[string]string{
+        "npm/README.md.tmpl": "# {{.PackageName}}\n",
+        "npm/Makefile.tmpl":  "# Corporate header\ninclude common.mk\n",
+        "go/README.md.tmpl":  "# not for npm\n",
+    } {
+        p := filepath.Join(tmpl, filepath.FromSlash(rel))
+        if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+        if err := os.WriteFile(p, []byte(text), 0o644); err != nil { t.Fatalf("write override: %v", err) }
+    }
+    plain, dir := t.TempDir(), t.TempDir()
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: plain, ToolName: "mytool"}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: dir, ToolName: "mytool", TemplateOverrideDir: tmpl}); err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for rel, want := range map[string]string{"README.md": "# mytool\n", "Makefile": "# Corporate header\ninclude common.mk\n"} {
+        if got, _ := os.ReadFile(filepath.Join(dir, rel)); string(got) != want {
+            t.Fatalf("%s should come from the override:\n%s", rel, got)
+        }
+    }
+    for _, rel := range []string{"package.json", filepath.Join("src", "index.ts")} {
+        want, _ := os.ReadFile(filepath.Join(plain, rel))
+        if got, _ := os.ReadFile(filepath.Join(dir, rel)); string(got) != string(want) {
+            t.Fatalf("%s without an override should stay built-in", rel)
+        }
+    }
+
+    if err := os.WriteFile(filepath.Join(tmpl, "npm", "index.ts.tmpl"), nil, 0o644); err != nil { t.Fatalf("write: %v", err) }
+    _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", TemplateOverrideDir: tmpl, DryRun: true})
+    if err == nil || !strings.Contains(err.Error(), "unknown template override npm/index.ts.tmpl") || !strings.Contains(err.Error(), "src/index.ts.tmpl") {
+        t.Fatalf("expected an error listing the valid templates, got %v", err)
+    }
+}
+
+func TestEmit_GenerateCI(t *testing.T) {
+    t.Parallel()
+    steps := func(opts Options) ([]string, string) {
+        t.Helper()
+        dir := t.TempDir()
+        opts.OutDir, opts.ToolName, opts.GenerateCI = dir, "mytool", true
+        if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        data, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+        if err != nil {
+            t.Fatalf("read ci.yml: %v", err)
+        }
+        var wf struct {
+            On   map[string]any `yaml:"on"`
+            Jobs map[string]struct {
+                RunsOn string `yaml:"runs-on"`
+                Steps  []struct {
+                    Uses string `yaml:"uses"`
+                    Run  string `yaml:"run"`
+                } `yaml:"steps"`
+            } `yaml:"jobs"`
+        }
+        if err := yaml.Unmarshal(data, &wf); err != nil {
+            t.Fatalf("ci.yml is not valid YAML: %v\n%s", err, data)
+        }
+        _, push := wf.On["push"]
+        _, pr := wf.On["pull_request"]
+        if !push || !pr || len(wf.Jobs) != 1 || wf.Jobs["build"].RunsOn != "ubuntu-latest" {
+            t.Fatalf("ci.yml should run one build job on push and pull_request:\n%s", data)
+        }
+        var out []string
+        for _, step := range wf.Jobs["build"].Steps {
+            out = append(out, step.Uses+step.Run)
+        }
+        readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+        return out, string(readme)
+    }
+
+    install := []string{"actions/checkout@v4", "actions/setup-node@v4", "if [ -f package-lock.json ]; then npm ci; else npm install; fi"}
+    got, readme := steps(Options{})
+    if want := append(append([]string(nil), install...), "npm run build", "npm run lint", "npm test"); !reflect.DeepEqual(got, want) {
+        t.Fatalf("steps = %q, want %q", got, want)
+    }
+    if !strings.Contains(readme, "then runs `npm run build`, `npm run lint`, `npm test`.") {
+        t.Errorf("README does not describe the CI workflow:\n%s", readme)
+    }
+    got, _ = steps(Options{Skip: []string{"tests", "lint"}})
+    if want := append(append([]string(nil), install...), "npm run build"); !reflect.DeepEqual(got, want) {
+        t.Fatalf("without tests and lint: steps = %q, want %q", got, want)
+    }
+
+    res, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), ToolName: "mytool", GenerateCI: true, Library: true, DryRun: true})
+    if err != nil {
+        t.Fatalf("emit: %v", err)
+    }
+    for _, p := range res.Planned {
+        if strings.HasPrefix(p.RelPath, ".github/") {
+            t.Fatalf("library layout should have no CI workflow: %s", p.RelPath)
+        }
+    }
+}
+
+func TestEmit_TestRunner(t *testing.T) {
+    t.Parallel()
+    emit := func(opts Options) string {
+        t.Helper()
+        dir := t.TempDir()
+        opts.OutDir, opts.ToolName, opts.EnableInvoke, opts.WithOTel = dir, "mytool", true, true
+        if _, err := Emit(context.Background(), minimalModel(), opts); err != nil {
+            t.Fatalf("emit: %v", err)
+        }
+        return dir
+    }
+    pkg := func(dir string) (scripts, devDeps map[string]string) {
+        t.Helper()
+        var p struct {
+            Scripts         map[string]string `json:"scripts"`
+            DevDependencies map[string]string `json:"devDependencies"`
+        }
+        data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+        if err != nil { t.Fatalf("read package.json: %v", err) }
+        if err := json.Unmarshal(data, &p); err != nil { t.Fatalf("package.json: %v", err) }
+        return p.Scripts, p.DevDependencies
+    }
+
+    dir := emit(Options{ESM: true})
+    if _, err := os.Stat(filepath.Join(dir, "jest.config.js")); !os.IsNotExist(err) {
+        t.Fatalf("vitest projects should have no jest.config.js, stat err=%v", err)
+    }
+    if scripts, deps := pkg(dir); scripts["test"] != "vitest run" || deps["vitest"] == "" || deps["jest"] != "" {
+        t.Fatalf("vitest package.json: scripts=%v devDependencies=%v", scripts, deps)
+    }
+
+    for _, esm := range []bool{true, false} {
+        dir := emit(Options{ESM: esm, TestRunner: TestRunnerJest})
+        config, err := os.ReadFile(filepath.Join(dir, "jest.config.js"))
+        if err != nil { t.Fatalf("esm=%v: read jest.config.js: %v", esm, err) }
+        wantExport, wantScript := "module.exports = {", "jest"
+        if esm {
+            wantExport, wantScript = "export default {", "node --experimental-vm-modules node_modules/jest/bin/jest.js"
+        }
+        if !strings.Contains(string(config), wantExport) || !strings.Contains(string(config), "'ts-jest'") {
+            t.Fatalf("esm=%v: jest.config.js:\n%s", esm, config)
+        }
+        scripts, deps := pkg(dir)
+        if scripts["test"] != wantScript || deps["vitest"] != "" {
+            t.Fatalf("esm=%v: scripts=%v devDependencies=%v", esm, scripts, deps)
+        }
+        for _, dep := range []string{"jest", "ts-jest", "@jest/globals"} {
+            if deps[dep] == "" { t.Fatalf("esm=%v: devDependencies lack %s: %v", esm, dep, deps) }
+        }
+        tests, err := filepath.Glob(filepath.Join(dir, "__tests__", "*.test.ts"))
+        if err != nil || len(tests) != 5 { t.Fatalf("esm=%v: tests %v, err=%v", esm, tests, err) }
+        for _, f := range append(tests, filepath.Join(dir, ".vscode", "launch.json"), filepath.Join(dir, "README.md")) {
+            data, _ := os.ReadFile(f)
+            if strings.Contains(string(data), "vitest") || strings.Contains(string(data), "skipIf") || strings.Contains(string(data), ", ep.ID)") {
+                t.Fatalf("esm=%v: %s still refers to vitest:\n%s", esm, filepath.Base(f), data)
+            }
+        }
+    }
+
+    dir = emit(Options{TestRunner: TestRunnerJest, Skip: []string{"tests"}})
+    if _, err := os.Stat(filepath.Join(dir, "jest.config.js")); !os.IsNotExist(err) {
+        t.Fatalf("--skip tests should drop jest.config.js, stat err=%v", err)
+    }
+    if _, err := Emit(context.Background(), minimalModel(), Options{OutDir: t.TempDir(), TestRunner: "mocha", DryRun: true}); err == nil || !strings.Contains(err.Error(), "unsupported TestRunner") {
+        t.Fatalf("expected error for an unknown test runner, got %v", err)
+    }
+}
+
+func TestJestTestSource(t *testing.T) {
+    t.Parallel()
+    in := "import { describe, it, expect, vi } from 'vitest'\n" +
+        "const spy = vi.spyOn(process, 'exit')\n" +
+        "it.skipIf(!found)('runs', async () => {\n" +
+        "  expect(f(a, [b, ')']), ep.ID).toBe(1)\n" +
+        "  await expect(call(sm, { ...args(ep), body: undefined }, url), 'why, ' + ep.ID).rejects.toThrow('body')\n" +
+        "  expect(() => g(1, 2)).toThrow('x, y')\n" +
+        "})\n" +
+        "describe.skipIf(list.length === 0)('all', () => {})\n"
+    want := "import { describe, it, expect, jest } from '@jest/globals'\n" +
+        "const spy = jest.spyOn(process, 'exit')\n" +
+        ";(!found ? it.skip : it)('runs', async () => {\n" +
+        "  expect(f(a, [b, ')'])).toBe(1)\n" +
+        "  await expect(call(sm, { ...args(ep), body: undefined }, url)).rejects.toThrow('body')\n" +
+        "  expect(() => g(1, 2)).toThrow('x, y')\n" +
+        "})\n" +
+        ";(list.length === 0 ? describe.skip : describe)('all', () => {})\n"
+    if got := jestTestSource(in); got != want {
+        t.Fatalf("jestTestSource:\n%s\nwant:\n%s", got, want)
+    }
+}
@@ -3,16 +3,39 @@ package npmemitter
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
+// defaultAuthor is credited as the generated package's author.
+const defaultAuthor = "Generated by swagger2mcp"
+
 type templateData struct {
 	ToolName     string
 	PackageName  string
+	BundleName   string // PackageName without the scope; used for file names
 	serviceTitle string
 	service      *genspec.ServiceModel
+	esm          bool   // ES module package; CommonJS otherwise
+	zod          bool   // src/spec/schemas.ts holds Zod schemas; zod is a dependency
+	pinned       bool   // package.json pins exact dependency versions
+	invoke       bool   // add the callEndpoint tool, which sends requests to the upstream API
+	docker       bool   // emit a Dockerfile, .dockerignore, docker-compose.yml and the docker targets and scripts
+	ci           bool   // emit the GitHub Actions workflow .github/workflows/ci.yml
+	otel         bool   // trace tool calls through src/telemetry.ts; @opentelemetry/api is a dependency
+	shardModel   bool   // write the model as per-tag shards under src/spec/model/ (modelshard)
+	testRunner   string // TestRunnerVitest or TestRunnerJest
+	author       string // package author and copyright holder in LICENSE
+	authorEmail  string // author's e-mail address; optional
+	license      string // SPDX identifier of LICENSE; empty when none is written
+	version      string // package version in package.json and the MCPB manifest
+	year         int    // copyright year in LICENSE
+
+	skip emitter.Skip // categories of files left out; the rest refer only to what is generated
 }
 
 func newTemplateData(toolName, packageName string, sm *genspec.ServiceModel) templateData {
@@ -23,8 +46,12 @@ func newTemplateData(toolName, packageName string, sm *genspec.ServiceModel) tem
 	return templateData{
 		ToolName:     strings.TrimSpace(toolName),
 		PackageName:  strings.TrimSpace(packageName),
+		BundleName:   unscopedName(strings.TrimSpace(packageName)),
 		serviceTitle: title,
 		service:      sm,
+		author:       defaultAuthor,
+		version:      genspec.DefaultProjectVersion,
+		year:         time.Now().Year(),
 	}
 }
 
@@ -36,6 +63,50 @@ func (d templateData) title() string {
 	return d.serviceTitle
 }
 
+// addMetadata sets package.json's "license" when a LICENSE is written and
+// "author" (as "Name <email>") when one was given.
+func (d templateData) addMetadata(pkg map[string]any) {
+	if d.license != "" {
+		pkg["license"] = d.license
+	}
+	if d.author != defaultAuthor || d.authorEmail != "" {
+		author := d.author
+		if d.authorEmail != "" {
+			author += " <" + d.authorEmail + ">"
+		}
+		pkg["author"] = author
+	}
+}
+
+// distDir is the tsc output directory: dist/esm for ES modules, dist otherwise.
+func (d templateData) distDir() string {
+	if d.esm {
+		return "dist/esm"
+	}
+	return "dist"
+}
+
+// testCommand is the "test" script of package.json. Jest needs Node's VM
+// modules flag to load ES modules.
+func (d templateData) testCommand() string {
+	switch {
+	case d.testRunner != TestRunnerJest:
+		return "vitest run"
+	case d.esm:
+		return "node --experimental-vm-modules node_modules/jest/bin/jest.js"
+	default:
+		return "jest"
+	}
+}
+
+// testSource returns a test file, written for vitest, for the test runner.
+func (d templateData) testSource(content string) string {
+	if d.testRunner == TestRunnerJest {
+		return jestTestSource(content)
+	}
+	return content
+}
+
 func normalize(content string) string {
 	trimmed := strings.TrimSpace(content)
 	if trimmed == "" {
@@ -48,59 +119,287 @@ func normalize(content string) string {
 
 func renderPackageJSON(data templateData) string {
 	// Keep minimal but useful scripts and dev deps
+	dist := data.distDir()
+	scripts := map[string]string{
+		"build":      "tsc -p . && " + data.copyModel(),
+		"start":      "npm run build && node " + dist + "/index.js",
+		"start:http": "npm run build && node " + dist + "/index.js --transport http",
+		"describe":   "npm run -s build && node " + dist + "/index.js --describe",
+		"bundle":     "npm run build && mcpb pack . dist/" + data.BundleName + "-$npm_package_version.mcpb",
+		"format":     "prettier -w .",
+	}
+	devDeps := []string{"@types/node", "prettier", "typescript"}
+	if !data.skip.Tests {
+		scripts["test"] = data.testCommand()
+		if data.testRunner == TestRunnerJest {
+			devDeps = append(devDeps, "@jest/globals", "jest", "ts-jest")
+		} else {
+			devDeps = append(devDeps, "vitest")
+		}
+	}
+	if data.docker {
+		scripts["docker:build"] = "docker build -t " + data.ToolName + " ."
+		scripts["docker:run"] = "docker run -i --rm " + data.ToolName
+	}
+	if !data.skip.Lint {
+		scripts["lint"] = "eslint . --ext .ts --max-warnings=0"
+		devDeps = append(devDeps,
+			"@typescript-eslint/eslint-plugin",
+			"@typescript-eslint/parser",
+			"eslint",
+			"eslint-config-prettier",
+		)
+	}
 	pkg := map[string]any{
-		"name":    data.PackageName,
-		"version": "0.1.0",
-		"private": true,
-		"type":    "module",
-		"scripts": map[string]string{
-			"build":  "tsc -p . && cp src/spec/model.json dist/spec/",
-			"start":  "npm run build && node dist/index.js",
-			"bundle": "npm run build && mcpb pack . dist/$npm_package_name-$npm_package_version.mcpb",
-			"test":   "vitest run",
-			"format": "prettier -w .",
-			"lint":   "eslint . --ext .ts --max-warnings=0",
-		},
-		"devDependencies": map[string]string{
-			"@typescript-eslint/eslint-plugin": "^7.0.0",
-			"@typescript-eslint/parser":        "^7.0.0",
-			"eslint":                           "^8.57.0",
-			"eslint-config-prettier":           "^9.1.0",
-			"@types/node":                      "^20.11.0",
-			"prettier":                         "^3.2.5",
-			"typescript":                       "^5.4.0",
-			"vitest":                           "^1.5.0",
+		"name":            data.PackageName,
+		"version":         data.version,
+		"private":         true,
+		"scripts":         scripts,
+		"devDependencies": dependencyVersions(data.pinned, devDeps...),
+	}
+	if data.esm {
+		pkg["type"] = "module"
+		scripts["build:esm"] = scripts["build"]
+		scripts["build"] = "npm run build:esm"
+	}
+	var deps []string
+	if data.zod {
+		deps = append(deps, "zod")
+	}
+	if data.otel {
+		deps = append(deps, "@opentelemetry/api")
+	}
+	if len(deps) > 0 {
+		pkg["dependencies"] = dependencyVersions(data.pinned, deps...)
+	}
+	data.addMetadata(pkg)
+	b, _ := json.MarshalIndent(pkg, "", "  ")
+	return string(b) + "\n"
+}
+
+// renderLibraryPackageJSON returns the package.json of the library layout:
+// a publishable package whose entry point re-exports src/spec.
+func renderLibraryPackageJSON(data templateData) string {
+	dist := data.distDir()
+	scripts := map[string]string{
+		"build":          "tsc -p . && " + data.copyModel(),
+		"prepublishOnly": "npm run build",
+	}
+	pkg := map[string]any{
+		"name":        data.PackageName,
+		"version":     data.version,
+		"description": fmt.Sprintf("Typed service model for %s", data.title()),
+		"main":        "./" + dist + "/spec/index.js",
+		"types":       "./" + dist + "/spec/index.d.ts",
+		// Declarations sit next to index.js, so TypeScript finds them through
+		// the exports map without a "types" condition.
+		"exports": map[string]string{
+			".":              "./" + dist + "/spec/index.js",
+			data.modelData(): "./" + dist + "/spec/" + strings.TrimPrefix(data.modelData(), "./"),
 		},
+		"files":   []string{dist},
+		"scripts": scripts,
+		"devDependencies": dependencyVersions(data.pinned,
+			"@types/node",
+			"typescript",
+		),
+	}
+	if data.esm {
+		pkg["type"] = "module"
+		scripts["build:esm"] = scripts["build"]
+		scripts["build"] = "npm run build:esm"
+	}
+	if data.zod {
+		pkg["dependencies"] = dependencyVersions(data.pinned, "zod")
 	}
+	data.addMetadata(pkg)
 	b, _ := json.MarshalIndent(pkg, "", "  ")
 	return string(b) + "\n"
 }
 
-func renderTSConfig() string {
+func renderTSConfig(data templateData) string {
+	// Node16 compiles to CommonJS in a package without "type": "module" and,
+	// unlike the older "Node" resolution, follows the SDK's exports map.
+	module, resolution := "Node16", "Node16"
+	if data.esm {
+		module, resolution = "ES2022", "Bundler"
+	}
 	cfg := map[string]any{
 		"compilerOptions": map[string]any{
 			"target":            "ES2019",
-			"module":            "ESNext",
-			"moduleResolution":  "Node",
+			"module":            module,
+			"moduleResolution":  resolution,
 			"strict":            true,
 			"declaration":       true,
+			"declarationMap":    true,
+			"sourceMap":         true,
 			"esModuleInterop":   true,
 			"resolveJsonModule": true,
 			"skipLibCheck":      true,
-			"outDir":            "dist",
+			"outDir":            data.distDir(),
 			"rootDir":           "src",
 			"types":             []string{"node"},
 		},
-		// Build should only include source files; tests are run by vitest
-		// and should not be compiled by tsc build.
+		// Build should only include source files; tests are run by the
+		// test runner and should not be compiled by tsc build. "types" keeps
+		// out the global declarations of @types/jest and the like, as the
+		// tests import their API.
 		"include": []string{"src"},
 	}
 	b, _ := json.MarshalIndent(cfg, "", "  ")
 	return string(b) + "\n"
 }
 
+// renderVSCodeLaunch returns a .vscode/launch.json with debug configurations for
+// the stdio server and, unless tests are skipped, the test suite. Source maps
+// let breakpoints in src/*.ts bind.
+func renderVSCodeLaunch(data templateData) string {
+	configs := []map[string]any{
+		{
+			"type":          "node",
+			"request":       "launch",
+			"name":          "Debug MCP server (stdio)",
+			"preLaunchTask": "npm: build",
+			"program":       "${workspaceFolder}/" + data.distDir() + "/index.js",
+			"runtimeArgs":   []string{"--enable-source-maps"},
+			"outFiles":      []string{"${workspaceFolder}/" + data.distDir() + "/**/*.js"},
+			"sourceMaps":    true,
+			"console":       "integratedTerminal",
+			"skipFiles":     []string{"<node_internals>/**"},
+		},
+	}
+	if !data.skip.Tests && data.testRunner == TestRunnerJest {
+		tests := map[string]any{
+			"type":      "node",
+			"request":   "launch",
+			"name":      "Debug tests (jest)",
+			"program":   "${workspaceFolder}/node_modules/jest/bin/jest.js",
+			"args":      []string{"--runInBand"},
+			"smartStep": true,
+			"console":   "integratedTerminal",
+			"skipFiles": []string{"<node_internals>/**"},
+		}
+		if data.esm {
+			tests["runtimeArgs"] = []string{"--experimental-vm-modules"}
+		}
+		configs = append(configs, tests)
+	} else if !data.skip.Tests {
+		configs = append(configs, map[string]any{
+			"type":                     "node",
+			"request":                  "launch",
+			"name":                     "Debug tests (vitest)",
+			"program":                  "${workspaceFolder}/node_modules/vitest/vitest.mjs",
+			"args":                     []string{"run"},
+			"autoAttachChildProcesses": true,
+			"smartStep":                true,
+			"console":                  "integratedTerminal",
+			"skipFiles":                []string{"<node_internals>/**"},
+		})
+	}
+	cfg := map[string]any{
+		"version":        "0.2.0",
+		"configurations": configs,
+	}
+	b, _ := json.MarshalIndent(cfg, "", "  ")
+	return string(b) + "\n"
+}
+
+func renderLibraryReadme(data templateData) string {
+	moduleFormat := "CommonJS"
+	if data.esm {
+		moduleFormat = "ESM"
+	}
+	lines := []string{
+		fmt.Sprintf("# %s", data.PackageName),
+		"",
+		fmt.Sprintf("Typed service model for %s", data.title()),
+		"",
+		"This package was generated by swagger2mcp. It contains only the spec module: the TypeScript",
+		"types of the API model, a loader, and the model itself (" + strings.TrimPrefix(data.modelData(), "./") + "). Use it to build your own",
+		"MCP server or tooling on the same model the generated servers use.",
+		"",
+		fmt.Sprintf("- Runtime: Node.js (TypeScript, %s)", moduleFormat),
+		"",
+		"## Build and publish",
+		"",
+		"```sh",
+		"npm install",
+		"npm run build",
+		"npm publish",
+		"```",
+		"",
+		"## Usage",
+		"",
+		"```ts",
+		fmt.Sprintf("import { applyBaseUrlOverride, baseUrl, loadServiceModel } from '%s'", data.PackageName),
+		"",
+		"const sm = applyBaseUrlOverride(loadServiceModel()) // honor API_BASE_URL",
+		"for (const ep of sm.Endpoints) {",
+		"  console.log(ep.Method, ep.Path, ep.Summary)",
+		"}",
+		"console.log('base URL:', baseUrl(sm))",
+		"```",
+		"",
+		fmt.Sprintf("The raw model is also exported as `%s/%s`.", data.PackageName, strings.TrimPrefix(data.modelData(), "./")),
+	}
+	if data.shardModel {
+		lines = append(lines, "", shardedModelReadme("src/spec"))
+	}
+	return normalize(strings.Join(lines, "\n"))
+}
+
+// copyModel returns the build step that copies the model data next to the
+// compiled loader.
+func (d templateData) copyModel() string {
+	if d.shardModel {
+		return "cp -r src/spec/model " + d.distDir() + "/spec/"
+	}
+	return "cp src/spec/model.json " + d.distDir() + "/spec/"
+}
+
+// modelData returns the package export of the raw model: ./model.json, or
+// the ./model/* shards.
+func (d templateData) modelData() string {
+	if d.shardModel {
+		return "./model/*"
+	}
+	return "./model.json"
+}
+
+// shardedModelReadme explains the per-tag shards of ShardModelByTag and the
+// loader functions in loader that read them.
+func shardedModelReadme(loader string) string {
+	return strings.Join([]string{
+		"The model is split by tag: `model/_index.json` holds everything but the endpoints, and",
+		"`model/<tag>.json` the endpoints whose first tag it is (`_untagged.json` those without",
+		"tags). `loadServiceModel()` still returns the whole model; `loadIndex()`, `shards()`,",
+		"`loadTag()` and `loadShard()` from " + loader + " read only what they need, each shard once.",
+	}, "\n")
+}
+
+// readmeTagLines renders the README's Tags section, listing the model's tags
+// with their descriptions; it is empty when no endpoint has a tag.
+func readmeTagLines(sm *genspec.ServiceModel) []string {
+	if sm == nil || len(sm.Tags) == 0 {
+		return nil
+	}
+	lines := []string{"## Tags", ""}
+	for _, tag := range sm.Tags {
+		line := "- " + tag
+		if desc := strings.Join(strings.Fields(sm.TagDescriptions[tag]), " "); desc != "" {
+			line += " — " + desc
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, "")
+}
+
 func renderReadme(data templateData) string {
 	title := data.title()
+	moduleFormat := "CommonJS"
+	if data.esm {
+		moduleFormat = "ESM"
+	}
 	lines := []string{
 		fmt.Sprintf("# %s", data.ToolName),
 		"",
@@ -108,10 +407,13 @@ func renderReadme(data templateData) string {
 		"",
 		"This project was generated by swagger2mcp and exposes MCP methods to query your API documentation.",
 		"",
-		"- Methods: listEndpoints, searchEndpoints, getEndpointDetails, listSchemas, getSchemaDetails",
-		"- Runtime: Node.js (TypeScript, ESM)",
+		"- Methods: listEndpoints, searchEndpoints, getEndpointDetails, listSchemas, getSchemaDetails, explainParameter",
+		fmt.Sprintf("- Runtime: Node.js (TypeScript, %s)", moduleFormat),
 		"- Packaging: MCP Bundles (.mcpb)",
 		"",
+	}
+	lines = append(lines, readmeTagLines(data.service)...)
+	lines = append(lines, []string{
 		"## Quick Start",
 		"",
 		"```sh",
@@ -122,12 +424,147 @@ func renderReadme(data templateData) string {
 		"The server reads JSON-RPC (newline-delimited) from stdin and writes responses to stdout.",
 		"Logs and diagnostics go to stderr.",
 		"",
+		"## HTTP transport",
+		"",
+		"Remote MCP clients can reach the server over streamable HTTP instead of stdio:",
+		"",
+		"```sh",
+		"npm run start:http   # serves http://127.0.0.1:8080/mcp",
+		fmt.Sprintf("node %s/index.js --transport http", data.distDir()),
+		"```",
+		"",
+		"Set MCP_HTTP_ADDR (e.g. `0.0.0.0:8080`) to choose the listen address, or PORT to listen",
+		"on all interfaces on that port; MCP_HTTP_ADDR wins when both are set. Each POST to /mcp",
+		"carries one JSON-RPC message and gets its response as JSON (202 for notifications).",
+		"",
+		"## Describe",
+		"",
+		"To see what a build contains without an MCP host, print its tools, the API title and",
+		"version, the endpoint and schema counts and the SHA-256 of its model as JSON:",
+		"",
+		"```sh",
+		"npm run -s describe",
+		fmt.Sprintf("node %s/index.js --describe", data.distDir()),
+		"```",
+		"",
+		"Requests target the spec's first server URL. Set API_BASE_URL to point the same build",
+		"at another environment (e.g. staging); it takes precedence over the spec's servers.",
+		"",
+	}...)
+	if data.invoke {
+		lines = append(lines,
+			"## Calling the API",
+			"",
+			"The callEndpoint tool sends a request to an endpoint at the base URL above. Required",
+			"parameters and the request body are checked against the model before anything is sent.",
+			"Set API_TIMEOUT (e.g. `10s`; default 30s) to bound each attempt, and API_RETRIES (default 0)",
+			"to resend a request that fails, times out or gets a 429 or 5xx response, waiting 100ms",
+			"before the first retry and twice as long before each further one, up to 2s. The response",
+			"body is returned up to 64 KiB.",
+			"",
+		)
+		if creds := emitter.Credentials(data.ToolName, data.service); len(creds) > 0 {
+			lines = append(lines, "Credentials are read from the environment and sent as each endpoint's security requirements ask:", "")
+			for _, c := range creds {
+				lines = append(lines, fmt.Sprintf("- `%s`: scheme `%s`, sent in the %s", c.EnvVar, c.Scheme, c.Placement()))
+			}
+			lines = append(lines, "", "A call to an endpoint whose requirements these do not meet fails before anything is sent.", "")
+		}
+	}
+	lines = append(lines,
 		"## Build",
 		"",
 		"```sh",
 		"npm run build",
 		"```",
 		"",
+	)
+	if data.shardModel {
+		lines = append(lines, "## Model shards", "", shardedModelReadme("src/spec/loader.ts"), "")
+	}
+	if data.docker && data.skip.Makefile {
+		lines = append(lines,
+			"## Docker",
+			"",
+			"```sh",
+			"docker build -t "+data.ToolName+" .",
+			"docker run -i --rm "+data.ToolName,
+			"```",
+			"",
+			"The image runs the compiled server over stdio, so run it with `-i` (`npm run docker:build`",
+			"and `npm run docker:run` do the same). Pass API_BASE_URL and other settings with `-e`.",
+			"`docker compose up` instead serves it over HTTP at http://localhost:3000/mcp, see",
+			"docker-compose.yml. `npm ci` in the build needs the package-lock.json `npm install`",
+			"writes; without one the build falls back to `npm install`.",
+			"",
+		)
+	} else if data.docker {
+		lines = append(lines,
+			"## Docker",
+			"",
+			"```sh",
+			"make docker-build   # docker build -t "+data.ToolName+" .",
+			"make docker-run     # docker run -i --rm "+data.ToolName,
+			"```",
+			"",
+			"The image runs the compiled server over stdio, so run it with `-i`; set IMAGE to tag it",
+			"differently (`npm run docker:build` and `npm run docker:run` use the tool name). Pass",
+			"API_BASE_URL and other settings with `-e`. `docker compose up` instead serves it over HTTP",
+			"at http://localhost:3000/mcp, see docker-compose.yml. `npm ci` in the build needs the",
+			"package-lock.json `npm install` writes; without one the build falls back to `npm install`.",
+			"",
+		)
+	}
+	if data.otel {
+		lines = append(lines,
+			"## Tracing",
+			"",
+			"Each tool call is recorded as an OpenTelemetry span named `tools/call <tool>`, with the tool",
+			"name, its duration and an error status when it fails. The server only depends on",
+			"`@opentelemetry/api`, so spans are no-ops until an SDK is registered, for example:",
+			"",
+			"```sh",
+			"npm install @opentelemetry/auto-instrumentations-node",
+			"OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318 node --import @opentelemetry/auto-instrumentations-node/register "+data.distDir()+"/index.js",
+			"```",
+			"",
+		)
+	}
+	if data.ci {
+		lines = append(lines,
+			"## CI",
+			"",
+			"The GitHub Actions workflow in .github/workflows/ci.yml runs on every push and pull request:",
+			"it installs the dependencies with `npm ci`, or `npm install` until a package-lock.json is",
+			"committed, then runs "+strings.Join(ciScripts(data), ", ")+".",
+			"",
+		)
+	}
+	if !data.skip.Editor {
+		debugServer := fmt.Sprintf("- Debug MCP server (stdio): builds the project and runs %s/index.js in the integrated terminal, so you can paste JSON-RPC requests on stdin.", data.distDir())
+		if data.skip.Tests {
+			lines = append(lines,
+				"## Debugging (VS Code)",
+				"",
+				"The generated tsconfig.json emits source maps, and .vscode/launch.json provides a configuration:",
+				"",
+				debugServer,
+				"",
+			)
+		} else {
+			lines = append(lines,
+				"## Debugging (VS Code)",
+				"",
+				"The generated tsconfig.json emits source maps, and .vscode/launch.json provides two configurations:",
+				"",
+				debugServer,
+				"- Debug tests ("+data.testRunner+"): runs the test suite under the debugger.",
+				"",
+			)
+		}
+		lines = append(lines, "Set breakpoints in src/*.ts and press F5.", "")
+	}
+	lines = append(lines,
 		"## Bundle (MCPB)",
 		"",
 		"Requires the MCPB CLI:",
@@ -139,18 +576,19 @@ func renderReadme(data templateData) string {
 		"Then:",
 		"",
 		"```sh",
-		"npm run bundle   # outputs dist/<name>-<version>.mcpb",
+		fmt.Sprintf("npm run bundle   # outputs dist/%s-<version>.mcpb", data.BundleName),
 		"```",
 		"",
 		"The manifest.json declares:",
 		"- server.entry_point: server/index.js",
-		"- mcp_config: \"/usr/bin/env\" with args [\"node\", \"${__dirname}/server/index.js\"]",
+		"- mcp_config: \"/usr/bin/env\" with args [\"node\", \"${__dirname}/server/index.js\", \"--transport\", \"${user_config.transport}\"]",
+		"- user_config.transport: stdio (default) or http, passed to the server as --transport",
 		"",
 		"## Manual Bundle (if needed)",
 		"",
 		"```sh",
 		"npm run build",
-		"mkdir -p server && cp -a dist/* server/",
+		fmt.Sprintf("mkdir -p server && cp -a %s/* server/", data.distDir()),
 		fmt.Sprintf("zip -r dist/%s.mcpb manifest.json server", data.ToolName),
 		"```",
 		"",
@@ -165,14 +603,18 @@ func renderReadme(data templateData) string {
 		"- If Node path issues occur in GUI environments: replace \"/usr/bin/env\"/\"node\" with your absolute Node path in manifest.json and re-bundle.",
 		"- notifications/initialized are ignored (no response). logging/setLevel returns success.",
 		"- prompts/list, resources/list, resources/templates/list return empty arrays by default.",
-	}
+	)
 	return normalize(strings.Join(lines, "\n"))
 }
 
-func renderIndexTs() string {
-	// Minimal JSON-RPC (newline-delimited) stdio MCP server for Node.
-	return normalize(`import { loadServiceModel } from './spec/loader.js'
+func renderIndexTs(data templateData) string {
+	// Minimal JSON-RPC MCP server for Node: newline-delimited over stdio, or
+	// streamable HTTP with --transport http (see renderTransportTs).
+	src := normalize(`import { applyBaseUrlOverride, loadServiceModel } from './spec/loader.js'
 import * as Methods from './mcp/methods/index.js'
+import { formatSchemaWithRefs } from './mcp/methods/formatSchema.js'
+import { describeServer } from './describe.js'
+import { MCP_PATH, createHttpServer, httpAddress, parseTransport, type Transport } from './transport.js'
 
 type JSONRPCId = string | number | null
 interface JSONRPCRequest { jsonrpc: '2.0'; id?: JSONRPCId; method: string; params?: any }
@@ -181,9 +623,19 @@ interface JSONRPCResponse { jsonrpc: '2.0'; id: JSONRPCId; result?: any; error?:
 let sm: any
 let serverName: string
 let serverVersion: string
+let transport: Transport
+let listen: { host: string; port: number } | undefined
 
 try {
-  sm = loadServiceModel()
+  transport = parseTransport(process.argv.slice(2))
+  if (transport === 'http') listen = httpAddress(process.env)
+} catch (error: any) {
+  console.error('[mcp-server] ' + (error?.message || String(error)))
+  process.exit(1)
+}
+
+try {
+  sm = applyBaseUrlOverride(loadServiceModel())
   serverName = (sm.Title && sm.Title.trim()) || 'mcp-tool'
   serverVersion = (sm.Version && sm.Version.trim()) || '0.1.0'
 } catch (error) {
@@ -198,6 +650,7 @@ const tools = [
   { name: 'getEndpointDetails', description: 'Get endpoint details by id or method+path', inputSchema: { type: 'object', properties: { id: { type: 'string' }, method: { type: 'string' }, path: { type: 'string' } } } },
   { name: 'listSchemas', description: 'List schemas', inputSchema: { type: 'object', properties: {} } },
   { name: 'getSchemaDetails', description: 'Get schema by name', inputSchema: { type: 'object', properties: { name: { type: 'string' } }, required: ['name'] } },
+  { name: 'explainParameter', description: 'Explain one endpoint parameter: location, schema, serialization, enum values, and how it appears in a request', inputSchema: { type: 'object', properties: { endpointId: { type: 'string' }, parameterName: { type: 'string' } }, required: ['endpointId', 'parameterName'] } },
 ]
 
 function writeResponse(resp: JSONRPCResponse) {
@@ -205,124 +658,19 @@ function writeResponse(resp: JSONRPCResponse) {
   process.stdout.write(JSON.stringify(resp) + '\n')
 }
 
-// Helper function to resolve schema references and format schema content
-function formatSchemaWithRefs(schema: any, sm: any, indent: string = ''): string[] {
-  const lines: string[] = []
-  
-  if (!schema) return lines
-  
-  if (schema.Type) {
-    lines.push(`+"`"+`${indent}类型: ${schema.Type}`+"`"+`)
-  }
-  
-  if (schema.Properties && Object.keys(schema.Properties).length > 0) {
-    lines.push(`+"`"+`${indent}属性:`+"`"+`)
-    Object.entries(schema.Properties).forEach(([propName, propSchema]: [string, any]) => {
-      const isRequired = schema.Required?.includes(propName) ? '[必需]' : '[可选]'
-      
-      if (propSchema.Schema) {
-        // Direct schema
-        const propType = propSchema.Schema.Type || 'unknown'
-        if (propType === 'array') {
-          // Handle array property with items
-          if (propSchema.Schema.Items?.Ref?.Ref) {
-            const refName = propSchema.Schema.Items.Ref.Ref.replace('#/components/schemas/', '')
-            lines.push(`+"`"+`${indent}  • ${propName}: ${propType}<${refName}> ${isRequired}`+"`"+`)
-            
-            // Try to resolve the reference
-            if (sm.Schemas && sm.Schemas[refName]) {
-              const refSchema = sm.Schemas[refName]
-              lines.push(`+"`"+`${indent}    └─ ${refName} 详情:`+"`"+`)
-              const refLines = formatSchemaWithRefs(refSchema, sm, indent + '      ')
-              lines.push(...refLines)
-            }
-          } else if (propSchema.Schema.Items?.Schema) {
-            const itemType = propSchema.Schema.Items.Schema.Type || 'unknown'
-            lines.push(`+"`"+`${indent}  • ${propName}: ${propType}<${itemType}> ${isRequired}`+"`"+`)
-          } else {
-            lines.push(`+"`"+`${indent}  • ${propName}: ${propType} ${isRequired}`+"`"+`)
-          }
-        } else {
-          lines.push(`+"`"+`${indent}  • ${propName}: ${propType} ${isRequired}`+"`"+`)
-        }
-        if (propSchema.Schema.Enum) {
-          lines.push(`+"`"+`${indent}    允许值: ${JSON.stringify(propSchema.Schema.Enum)}`+"`"+`)
-        }
-      } else if (propSchema.Ref?.Ref) {
-        // Reference to another schema
-        const refName = propSchema.Ref.Ref.replace('#/components/schemas/', '')
-        lines.push(`+"`"+`${indent}  • ${propName}: ${refName} (引用) ${isRequired}`+"`"+`)
-        
-        // Try to resolve the reference
-        if (sm.Schemas && sm.Schemas[refName]) {
-          const refSchema = sm.Schemas[refName]
-          lines.push(`+"`"+`${indent}    └─ ${refName} 详情:`+"`"+`)
-          const refLines = formatSchemaWithRefs(refSchema, sm, indent + '      ')
-          lines.push(...refLines)
-        }
-      } else {
-        lines.push(`+"`"+`${indent}  • ${propName}: unknown ${isRequired}`+"`"+`)
-      }
-    })
-  }
-  
-  if (schema.Items) {
-    if (schema.Items.Schema) {
-      lines.push(`+"`"+`${indent}数组元素类型: ${schema.Items.Schema.Type || 'unknown'}`+"`"+`)
-      const itemLines = formatSchemaWithRefs(schema.Items.Schema, sm, indent + '  ')
-      lines.push(...itemLines)
-    } else if (schema.Items.Ref?.Ref) {
-      const refName = schema.Items.Ref.Ref.replace('#/components/schemas/', '')
-      lines.push(`+"`"+`${indent}数组元素类型: ${refName} (引用)`+"`"+`)
-      
-      // Try to resolve the reference
-      if (sm.Schemas && sm.Schemas[refName]) {
-        const refSchema = sm.Schemas[refName]
-        lines.push(`+"`"+`${indent}└─ ${refName} 详情:`+"`"+`)
-        const refLines = formatSchemaWithRefs(refSchema, sm, indent + '  ')
-        lines.push(...refLines)
-      }
-    }
-  }
-  
-  if (schema.AllOf && schema.AllOf.length > 0) {
-    lines.push(`+"`"+`${indent}组合类型 (AllOf):`+"`"+`)
-    schema.AllOf.forEach((subSchema: any, index: number) => {
-      lines.push(`+"`"+`${indent}  ${index + 1}. `+"`"+`)
-      if (subSchema.Schema) {
-        const subLines = formatSchemaWithRefs(subSchema.Schema, sm, indent + '    ')
-        lines.push(...subLines)
-      } else if (subSchema.Ref?.Ref) {
-        const refName = subSchema.Ref.Ref.replace('#/components/schemas/', '')
-        lines.push(`+"`"+`${indent}    引用: ${refName}`+"`"+`)
-        if (sm.Schemas && sm.Schemas[refName]) {
-          const refSchema = sm.Schemas[refName]
-          const refLines = formatSchemaWithRefs(refSchema, sm, indent + '      ')
-          lines.push(...refLines)
-        }
-      }
-    })
-  }
-  
-  if (schema.Enum) {
-    lines.push(`+"`"+`${indent}允许值: ${JSON.stringify(schema.Enum)}`+"`"+`)
-  }
-  
-  return lines
-}
-
-function handleRequest(req: JSONRPCRequest) {
+// handleRequest returns the response to a request, or undefined for a
+// notification; the transport delivers it.
+function handleRequest(req: JSONRPCRequest): JSONRPCResponse | undefined {
   const isNotification = (req.id === undefined)
   const id: JSONRPCId = isNotification ? null : (req.id as JSONRPCId)
-  const ok = (result: any) => { if (!isNotification) writeResponse({ jsonrpc: '2.0', id, result }) }
-  const err = (code: number, message: string, data?: any) => { 
-    if (!isNotification) {
-      const errorResponse: any = { jsonrpc: '2.0', id, error: { code, message } }
-      if (data !== undefined) {
-        errorResponse.error.data = data
-      }
-      writeResponse(errorResponse)
+  const ok = (result: any): JSONRPCResponse | undefined => isNotification ? undefined : { jsonrpc: '2.0', id, result }
+  const err = (code: number, message: string, data?: any): JSONRPCResponse | undefined => {
+    if (isNotification) return undefined
+    const errorResponse: JSONRPCResponse = { jsonrpc: '2.0', id, error: { code, message } }
+    if (data !== undefined) {
+      errorResponse.error!.data = data
     }
+    return errorResponse
   }
 
   try {
@@ -419,14 +767,21 @@ function handleRequest(req: JSONRPCRequest) {
             `+"`"+`描述: ${(ep as any)?.Description || '无'}`+"`"+`,
             `+"`"+`标签: ${((ep as any)?.Tags || []).join(', ') || '无'}`+"`"+`
           ]
+          if ((ep as any)?.Extensions && Object.keys((ep as any).Extensions).length > 0) {
+            textLines.push('扩展: ' + JSON.stringify((ep as any).Extensions))
+          }
           
-          if ((ep as any)?.Parameters?.length > 0) {
-            textLines.push('', '参数:')
-            ;(ep as any).Parameters.forEach((param: any) => {
+          // Parameters, grouped by location
+          const parameterLocations: [string, string][] = [['path', '路径参数'], ['query', '查询参数'], ['header', '请求头参数'], ['cookie', 'Cookie参数']]
+          for (const [location, title] of parameterLocations) {
+            const located = ((ep as any)?.Parameters || []).filter((param: any) => param.In === location)
+            if (located.length === 0) continue
+            textLines.push('', title + ':')
+            located.forEach((param: any) => {
               const required = param.Required ? '[必需]' : '[可选]'
               const type = param.Schema?.Schema?.Type || 'unknown'
               const enumValues = param.Schema?.Schema?.Enum ? `+"`"+` (允许值: ${JSON.stringify(param.Schema.Schema.Enum)})`+"`"+` : ''
-              textLines.push(`+"`"+`  • ${param.Name} (${param.In}) - ${type}${enumValues} ${required}`+"`"+`)
+              textLines.push(`+"`"+`  • ${param.Name} - ${type}${enumValues} ${required}`+"`"+`)
             })
           }
           
@@ -525,6 +880,9 @@ function handleRequest(req: JSONRPCRequest) {
           const textLines = [
             `+"`"+`Schema: ${sc.Name}`+"`"+`
           ]
+          if (sc?.Extensions && Object.keys(sc.Extensions).length > 0) {
+            textLines.push('扩展: ' + JSON.stringify(sc.Extensions))
+          }
           if (sc) {
             const schemaLines = formatSchemaWithRefs(sc, sm, '')
             textLines.push(...schemaLines)
@@ -532,6 +890,11 @@ function handleRequest(req: JSONRPCRequest) {
           
           return ok({ structuredContent: sc, content: [{ type: 'text', text: textLines.join('\\n') }] })
         }
+        if (name === 'explainParameter') {
+          const [px, okFlag, message] = Methods.explainParameter(sm, String(args.endpointId || ''), String(args.parameterName || ''))
+          if (!okFlag) return ok({ isError: true, content: [{ type: 'text', text: message }] })
+          return ok({ structuredContent: px, content: [{ type: 'text', text: Methods.formatParameterExplanation(px!, sm) }] })
+        }
         return err(-32601, 'unknown tool: ' + String(name))
       }
       default:
@@ -547,35 +910,50 @@ function handleRequest(req: JSONRPCRequest) {
 }
 
 // NL-delimited JSON-RPC over stdio
-let buf = ''
-process.stdin.setEncoding('utf8')
-process.stdin.on('data', (chunk) => {
-  buf += chunk
-  let idx
-  while ((idx = buf.indexOf('\n')) >= 0) {
-    const line = buf.slice(0, idx).trim()
-    buf = buf.slice(idx + 1)
-    if (!line) continue
-    try {
-      const msg = JSON.parse(line) as JSONRPCRequest
-      handleRequest(msg)
-    } catch (e: any) {
-      // Do not emit a JSON-RPC response with id=null; MCP clients expect id to be string/number.
-      // Log to stderr for diagnostics and ignore this line.
-      console.error('[mcp-server] parse error:', String(e))
+function serveStdio() {
+  let buf = ''
+  process.stdin.setEncoding('utf8')
+  process.stdin.on('data', (chunk) => {
+    buf += chunk
+    let idx
+    while ((idx = buf.indexOf('\n')) >= 0) {
+      const line = buf.slice(0, idx).trim()
+      buf = buf.slice(idx + 1)
+      if (!line) continue
+      try {
+        const msg = JSON.parse(line) as JSONRPCRequest
+        const resp = handleRequest(msg)
+        if (resp) writeResponse(resp)
+      } catch (e: any) {
+        // Do not emit a JSON-RPC response with id=null; MCP clients expect id to be string/number.
+        // Log to stderr for diagnostics and ignore this line.
+        console.error('[mcp-server] parse error:', String(e))
+      }
     }
-  }
-})
+  })
 
-process.stdin.on('end', () => {
-  console.error('[mcp-server] stdin closed, exiting...')
-  process.exit(0)
-})
+  process.stdin.on('end', () => {
+    console.error('[mcp-server] stdin closed, exiting...')
+    process.exit(0)
+  })
 
-process.stdin.on('error', (error) => {
-  console.error('[mcp-server] stdin error:', error)
-  process.exit(1)
-})
+  process.stdin.on('error', (error) => {
+    console.error('[mcp-server] stdin error:', error)
+    process.exit(1)
+  })
+}
+
+if (process.argv.slice(2).includes('--describe')) {
+  // Print the tools and a summary of the model instead of serving
+  process.stdout.write(JSON.stringify(describeServer(sm, tools), null, 2) + '\n')
+} else if (transport === 'http' && listen) {
+  const { host, port } = listen
+  createHttpServer(handleRequest).listen(port, host, () => {
+    console.error('[mcp-server] listening on http://' + host + ':' + port + MCP_PATH)
+  })
+} else {
+  serveStdio()
+}
 
 // Error handling for uncaught exceptions and unhandled rejections
 process.on('uncaughtException', (error) => {
@@ -601,43 +979,424 @@ process.on('SIGTERM', () => {
 
 // Do not exit automatically; let the host manage lifecycle
 `) + "\n"
+	if data.invoke {
+		src = withCallEndpointTool(src)
+	}
+	if data.otel {
+		src = withToolTracing(src)
+	}
+	return src
 }
 
-func renderSpecModelTs() string {
-	return normalize(`// Internal Model (IM) definitions used by the generated MCP tool.
-
-export type HttpMethod =
-  | 'get' | 'post' | 'put' | 'delete' | 'patch' | 'head' | 'options' | 'trace'
-
-export interface ServiceModel {
-  Title: string
-  Version: string
-  Description: string
-  Servers: Server[]
-  Tags: string[]
-  Endpoints: EndpointModel[]
-  Schemas: Record<string, Schema>
+// withToolTracing routes tools/call requests through traceToolCall, so every
+// tool in the dispatch is traced by one wrapper. It runs after
+// withCallEndpointTool, which may have widened the handler's return type.
+func withToolTracing(src string) string {
+	const doc = `// handleRequest returns the response to a request, or undefined for a
+// notification; the transport delivers it.
+`
+	pairs := []string{`
+import { MCP_PATH, createHttpServer, httpAddress, parseTransport, type Transport } from './transport.js'
+`, `
+import { MCP_PATH, createHttpServer, httpAddress, parseTransport, type Transport } from './transport.js'
+import { traceToolCall } from './telemetry.js'
+`}
+	for _, ret := range []string{"JSONRPCResponse | undefined", "JSONRPCResponse | undefined | Promise<JSONRPCResponse | undefined>"} {
+		pairs = append(pairs,
+			doc+"function handleRequest(req: JSONRPCRequest): "+ret+" {",
+			tracedHandleRequestTs+"\n// dispatchRequest returns the response to a request, or undefined for a\n// notification.\nfunction dispatchRequest(req: JSONRPCRequest): "+ret+" {",
+		)
+	}
+	return strings.NewReplacer(pairs...).Replace(src)
 }
 
-export interface Server { URL: string; Description: string }
-
-export interface EndpointModel {
-  ID: string // method+path
-  Method: HttpMethod
-  Path: string
-  Summary: string
-  Description: string
-  Tags: string[]
-  Parameters: ParameterModel[]
-  RequestBody?: RequestBodyModel
-  Responses: ResponseModel[]
+// tracedHandleRequestTs is the handleRequest withToolTracing puts in front of
+// the dispatch.
+const tracedHandleRequestTs = `// handleRequest answers a request through dispatchRequest, wrapping each
+// tools/call in a span named after the tool; the transport delivers it.
+function handleRequest(req: JSONRPCRequest): ReturnType<typeof dispatchRequest> {
+  if (req.method !== 'tools/call') return dispatchRequest(req)
+  return traceToolCall(String(req.params?.name ?? ''), () => dispatchRequest(req))
+}
+`
+
+// renderTelemetryTs renders src/telemetry.ts, the wrapper handleRequest puts
+// around each tool call. It only depends on @opentelemetry/api, which hands
+// out no-op spans until the host registers an SDK.
+func renderTelemetryTs(data templateData) string {
+	return normalize(strings.NewReplacer("{{PACKAGE}}", tsString(data.PackageName), "{{VERSION}}", tsString(data.version)).Replace(`import { SpanKind, SpanStatusCode, trace, type Span } from '@opentelemetry/api'
+
+// Spans go to the tracer provider the host registers, for example the
+// OpenTelemetry Node SDK exporting over OTLP as the OTEL_* environment
+// variables configure it; without one every span is a no-op.
+const tracer = trace.getTracer({{PACKAGE}}, {{VERSION}})
+
+// finish records how a tool call ended and ends its span: an error status for
+// a thrown error, a JSON-RPC error or a result marked isError.
+function finish(span: Span, start: number, resp: any, error?: unknown) {
+  span.setAttribute('mcp.tool.duration_ms', Date.now() - start)
+  if (error !== undefined) {
+    span.recordException(error instanceof Error ? error : String(error))
+    span.setStatus({ code: SpanStatusCode.ERROR, message: error instanceof Error ? error.message : String(error) })
+  } else if (resp?.error) {
+    span.setStatus({ code: SpanStatusCode.ERROR, message: String(resp.error.message) })
+  } else if (resp?.result?.isError) {
+    span.setStatus({ code: SpanStatusCode.ERROR, message: 'tool returned an error result' })
+  }
+  span.end()
 }
 
-export interface ParameterModel {
-  Name: string
-  In: 'path'|'query'|'header'|'cookie'|string
+// traceToolCall runs call, which answers a tools/call request for the tool
+// name, in a "tools/call <name>" span carrying the tool name and duration. A
+// promise is traced until it settles; the response is returned unchanged.
+export function traceToolCall<T>(name: string, call: () => T): T {
+  const span = tracer.startSpan('tools/call ' + name, { kind: SpanKind.SERVER, attributes: { 'mcp.tool.name': name } })
+  const start = Date.now()
+  let resp: T
+  try {
+    resp = call()
+  } catch (error) {
+    finish(span, start, undefined, error)
+    throw error
+  }
+  if (resp instanceof Promise) {
+    return resp.then(
+      (settled) => { finish(span, start, settled); return settled },
+      (error) => { finish(span, start, undefined, error); throw error },
+    ) as T
+  }
+  finish(span, start, resp)
+  return resp
+}
+`)) + "\n"
+}
+
+// withCallEndpointTool adds the callEndpoint tool to index.ts. Its handler
+// answers with a promise, which both transports wait for.
+func withCallEndpointTool(src string) string {
+	return strings.NewReplacer(
+		`'This server exposes tools to query your API documentation.'`, `'This server exposes tools to query your API documentation and to call the API.'`,
+		`function handleRequest(req: JSONRPCRequest): JSONRPCResponse | undefined {`, `function handleRequest(req: JSONRPCRequest): JSONRPCResponse | undefined | Promise<JSONRPCResponse | undefined> {`,
+		`
+]
+`, `
+  { name: 'callEndpoint', description: 'Call an API endpoint: pass its id, parameters by name (path, query, header or cookie) and an optional JSON body; returns the status, headers and body (truncated)', inputSchema: { type: 'object', properties: { endpointId: { type: 'string' }, params: { type: 'object' }, body: {} }, required: ['endpointId'] } },
+]
+`,
+		`
+        return err(-32601, 'unknown tool: ' + String(name))
+`, `
+        if (name === 'callEndpoint') {
+          const call = { endpointId: String(args.endpointId || ''), params: args.params || {}, body: args.body }
+          return Methods.callEndpoint(sm, call).then(
+            (result) => ok({ structuredContent: result, content: [{ type: 'text', text: Methods.formatCallResult(result) }] }),
+            (e: any) => ok({ isError: true, content: [{ type: 'text', text: e?.message || String(e) }] }),
+          )
+        }
+        return err(-32601, 'unknown tool: ' + String(name))
+`,
+		`
+  let buf = ''
+`, `
+  let buf = ''
+  const inflight = new Set<Promise<void>>()
+`,
+		`
+        const resp = handleRequest(msg)
+        if (resp) writeResponse(resp)
+`, `
+        const done = Promise.resolve(handleRequest(msg)).then((resp) => { if (resp) writeResponse(resp) })
+        inflight.add(done)
+        done.finally(() => inflight.delete(done))
+`,
+		`
+  process.stdin.on('end', () => {
+    console.error('[mcp-server] stdin closed, exiting...')
+    process.exit(0)
+  })
+`, `
+  process.stdin.on('end', () => {
+    // Answer the calls still in flight before exiting
+    Promise.all(inflight).then(() => {
+      console.error('[mcp-server] stdin closed, exiting...')
+      process.exit(0)
+    })
+  })
+`,
+	).Replace(src)
+}
+
+// renderTransportTs holds the --transport flag parsing, the HTTP listen
+// address and a streamable HTTP endpoint for the JSON-RPC handler in index.ts.
+// Every POST gets its response as JSON once the handler settles, so the
+// optional server-to-client SSE stream is not offered.
+func renderTransportTs() string {
+	return normalize(`import { createServer, IncomingMessage, Server, ServerResponse } from 'node:http'
+
+export type Transport = 'stdio' | 'http'
+
+// Where --transport http listens when neither MCP_HTTP_ADDR nor PORT is set.
+export const DEFAULT_HTTP_ADDR = '127.0.0.1:8080'
+
+// Path of the streamable HTTP endpoint.
+export const MCP_PATH = '/mcp'
+
+// parseTransport reads --transport stdio|http (or --transport=http) from the
+// command-line arguments; stdio when absent.
+export function parseTransport(argv: string[]): Transport {
+  let value = 'stdio'
+  argv.forEach((arg, i) => {
+    if (arg === '--transport') value = argv[i + 1] ?? ''
+    else if (arg.startsWith('--transport=')) value = arg.slice('--transport='.length)
+  })
+  if (value !== 'stdio' && value !== 'http') {
+    throw new Error('unknown transport "' + value + '" (want stdio or http)')
+  }
+  return value
+}
+
+// httpAddress returns MCP_HTTP_ADDR (host:port) when set, else all interfaces
+// on PORT, as container platforms expect, else DEFAULT_HTTP_ADDR.
+export function httpAddress(env: Record<string, string | undefined>): { host: string; port: number } {
+  let addr = (env.MCP_HTTP_ADDR || '').trim()
+  if (!addr && (env.PORT || '').trim()) addr = ':' + (env.PORT || '').trim()
+  if (!addr) addr = DEFAULT_HTTP_ADDR
+  const sep = addr.lastIndexOf(':')
+  const port = Number(addr.slice(sep + 1))
+  if (sep < 0 || addr.slice(sep + 1) === '' || !Number.isInteger(port) || port < 0 || port > 65535) {
+    throw new Error('invalid listen address "' + addr + '" (want host:port)')
+  }
+  const host = addr.slice(0, sep).replace(/^\[(.*)\]$/, '$1') || '0.0.0.0'
+  return { host, port }
+}
+
+// createHttpServer serves MCP over streamable HTTP at MCP_PATH: each POST
+// carries one JSON-RPC message and gets the handler's response as JSON, or
+// 202 Accepted for a notification.
+export function createHttpServer(handle: (message: any) => unknown): Server {
+  return createServer((req: IncomingMessage, res: ServerResponse) => {
+    const path = (req.url || '/').split('?')[0]
+    if (path !== MCP_PATH) {
+      send(res, 404, { error: 'not found: ' + path })
+      return
+    }
+    if (req.method !== 'POST') {
+      // No server-initiated messages, so there is no SSE stream to GET.
+      res.writeHead(405, { Allow: 'POST' })
+      res.end()
+      return
+    }
+    let body = ''
+    req.setEncoding('utf8')
+    req.on('data', (chunk: string) => { body += chunk })
+    req.on('end', () => {
+      let message: any
+      try {
+        message = JSON.parse(body)
+      } catch {
+        send(res, 400, { jsonrpc: '2.0', id: null, error: { code: -32700, message: 'Parse error' } })
+        return
+      }
+      Promise.resolve(handle(message)).then((response) => {
+        if (response === undefined) {
+          res.writeHead(202)
+          res.end()
+          return
+        }
+        send(res, 200, response)
+      })
+    })
+  })
+}
+
+function send(res: ServerResponse, status: number, payload: unknown) {
+  res.writeHead(status, { 'Content-Type': 'application/json' })
+  res.end(JSON.stringify(payload))
+}
+`) + "\n"
+}
+
+// renderFormatSchemaTs holds the schema formatting shared by the tool
+// handlers in index.ts and the explainParameter method.
+func renderFormatSchemaTs() string {
+	return normalize(`// Helper function to resolve schema references and format schema content
+export function formatSchemaWithRefs(schema: any, sm: any, indent: string = ''): string[] {
+  const lines: string[] = []
+  
+  if (!schema) return lines
+  
+  if (schema.Type) {
+    lines.push(`+"`"+`${indent}类型: ${schema.Type}`+"`"+`)
+  }
+  
+  if (schema.Properties && Object.keys(schema.Properties).length > 0) {
+    lines.push(`+"`"+`${indent}属性:`+"`"+`)
+    Object.entries(schema.Properties).forEach(([propName, propSchema]: [string, any]) => {
+      const isRequired = schema.Required?.includes(propName) ? '[必需]' : '[可选]'
+      
+      if (propSchema.Schema) {
+        // Direct schema
+        const propType = propSchema.Schema.Type || 'unknown'
+        if (propType === 'array') {
+          // Handle array property with items
+          if (propSchema.Schema.Items?.Ref?.Ref) {
+            const refName = propSchema.Schema.Items.Ref.Ref.replace('#/components/schemas/', '')
+            lines.push(`+"`"+`${indent}  • ${propName}: ${propType}<${refName}> ${isRequired}`+"`"+`)
+            
+            // Try to resolve the reference
+            if (sm.Schemas && sm.Schemas[refName]) {
+              const refSchema = sm.Schemas[refName]
+              lines.push(`+"`"+`${indent}    └─ ${refName} 详情:`+"`"+`)
+              const refLines = formatSchemaWithRefs(refSchema, sm, indent + '      ')
+              lines.push(...refLines)
+            }
+          } else if (propSchema.Schema.Items?.Schema) {
+            const itemType = propSchema.Schema.Items.Schema.Type || 'unknown'
+            lines.push(`+"`"+`${indent}  • ${propName}: ${propType}<${itemType}> ${isRequired}`+"`"+`)
+          } else {
+            lines.push(`+"`"+`${indent}  • ${propName}: ${propType} ${isRequired}`+"`"+`)
+          }
+        } else {
+          lines.push(`+"`"+`${indent}  • ${propName}: ${propType} ${isRequired}`+"`"+`)
+        }
+        if (propSchema.Schema.Enum) {
+          lines.push(`+"`"+`${indent}    允许值: ${JSON.stringify(propSchema.Schema.Enum)}`+"`"+`)
+        }
+        const propConstraints = schemaConstraints(propSchema.Schema)
+        if (propConstraints.length > 0) {
+          lines.push(indent + '    约束: ' + propConstraints.join(', '))
+        }
+      } else if (propSchema.Ref?.Ref) {
+        // Reference to another schema
+        const refName = propSchema.Ref.Ref.replace('#/components/schemas/', '')
+        lines.push(`+"`"+`${indent}  • ${propName}: ${refName} (引用) ${isRequired}`+"`"+`)
+        
+        // Try to resolve the reference
+        if (sm.Schemas && sm.Schemas[refName]) {
+          const refSchema = sm.Schemas[refName]
+          lines.push(`+"`"+`${indent}    └─ ${refName} 详情:`+"`"+`)
+          const refLines = formatSchemaWithRefs(refSchema, sm, indent + '      ')
+          lines.push(...refLines)
+        }
+      } else {
+        lines.push(`+"`"+`${indent}  • ${propName}: unknown ${isRequired}`+"`"+`)
+      }
+    })
+  }
+  
+  if (schema.Items) {
+    if (schema.Items.Schema) {
+      lines.push(`+"`"+`${indent}数组元素类型: ${schema.Items.Schema.Type || 'unknown'}`+"`"+`)
+      const itemLines = formatSchemaWithRefs(schema.Items.Schema, sm, indent + '  ')
+      lines.push(...itemLines)
+    } else if (schema.Items.Ref?.Ref) {
+      const refName = schema.Items.Ref.Ref.replace('#/components/schemas/', '')
+      lines.push(`+"`"+`${indent}数组元素类型: ${refName} (引用)`+"`"+`)
+      
+      // Try to resolve the reference
+      if (sm.Schemas && sm.Schemas[refName]) {
+        const refSchema = sm.Schemas[refName]
+        lines.push(`+"`"+`${indent}└─ ${refName} 详情:`+"`"+`)
+        const refLines = formatSchemaWithRefs(refSchema, sm, indent + '  ')
+        lines.push(...refLines)
+      }
+    }
+  }
+  
+  if (schema.AllOf && schema.AllOf.length > 0) {
+    lines.push(`+"`"+`${indent}组合类型 (AllOf):`+"`"+`)
+    schema.AllOf.forEach((subSchema: any, index: number) => {
+      lines.push(`+"`"+`${indent}  ${index + 1}. `+"`"+`)
+      if (subSchema.Schema) {
+        const subLines = formatSchemaWithRefs(subSchema.Schema, sm, indent + '    ')
+        lines.push(...subLines)
+      } else if (subSchema.Ref?.Ref) {
+        const refName = subSchema.Ref.Ref.replace('#/components/schemas/', '')
+        lines.push(`+"`"+`${indent}    引用: ${refName}`+"`"+`)
+        if (sm.Schemas && sm.Schemas[refName]) {
+          const refSchema = sm.Schemas[refName]
+          const refLines = formatSchemaWithRefs(refSchema, sm, indent + '      ')
+          lines.push(...refLines)
+        }
+      }
+    })
+  }
+  
+  if (schema.Enum) {
+    lines.push(`+"`"+`${indent}允许值: ${JSON.stringify(schema.Enum)}`+"`"+`)
+  }
+  
+  const constraints = schemaConstraints(schema)
+  if (constraints.length > 0) {
+    lines.push(indent + '约束: ' + constraints.join(', '))
+  }
+  
+  return lines
+}
+
+// schemaConstraints lists array and numeric constraints, e.g. "minItems=2"
+export function schemaConstraints(schema: any): string[] {
+  const out: string[] = []
+  if (schema.MinItems != null) out.push('minItems=' + schema.MinItems)
+  if (schema.MaxItems != null) out.push('maxItems=' + schema.MaxItems)
+  if (schema.UniqueItems) out.push('uniqueItems')
+  if (schema.Minimum != null) out.push('minimum' + (schema.ExclusiveMinimum ? '>' : '>=') + schema.Minimum)
+  if (schema.Maximum != null) out.push('maximum' + (schema.ExclusiveMaximum ? '<' : '<=') + schema.Maximum)
+  return out
+}
+`) + "\n"
+}
+
+func renderSpecModelTs() string {
+	return normalize(`// Internal Model (IM) definitions used by the generated MCP tool.
+
+export type HttpMethod =
+  | 'get' | 'post' | 'put' | 'delete' | 'patch' | 'head' | 'options' | 'trace'
+
+export interface ServiceModel {
+  Title: string
+  Version: string
+  Description: string
+  Servers: Server[]
+  Tags: string[]
+  TagDescriptions?: Record<string, string> // tag -> description ('' when none)
+  Endpoints: EndpointModel[]
+  Schemas: Record<string, Schema>
+  Extensions?: Record<string, any> // x-* vendor extensions
+}
+
+export interface Server { URL: string; Description: string; Development?: boolean }
+
+export interface EndpointModel {
+  ID: string // method+path
+  OperationID?: string
+  Method: HttpMethod
+  Path: string
+  Summary: string
+  Description: string
+  Tags: string[]
+  Parameters: ParameterModel[]
+  RequestBody?: RequestBodyModel
+  Responses: ResponseModel[]
+  Deprecated?: boolean
+  Featured?: boolean
+  Pagination?: PaginationHint
+  Extensions?: Record<string, any> // x-* vendor extensions
+  Security?: string[][] // alternative security requirements, by scheme name; [] allows anonymous access
+}
+
+export interface PaginationHint {
+  Style: 'cursor'|'offset'|'unknown'|string
+  Params: string[] // pagination query parameters
+}
+
+export interface ParameterModel {
+  Name: string
+  In: 'path'|'query'|'header'|'cookie'|string
   Required: boolean
   Schema?: SchemaOrRef
+  Style?: string // form|simple|spaceDelimited|pipeDelimited|...
+  Explode?: boolean
 }
 
 export interface RequestBodyModel {
@@ -655,6 +1414,7 @@ export interface Media {
   Mime: string
   Schema?: SchemaOrRef
   Example?: any
+  IsBinary?: boolean
 }
 
 export interface Schema {
@@ -670,24 +1430,79 @@ export interface Schema {
   Enum?: any[]
   Format?: string
   Example?: any
+  Extensions?: Record<string, any> // x-* vendor extensions
+  // Array and numeric constraints. Exclusive* qualify Minimum/Maximum.
+  MinItems?: number
+  MaxItems?: number
+  UniqueItems?: boolean
+  Minimum?: number
+  Maximum?: number
+  ExclusiveMinimum?: boolean
+  ExclusiveMaximum?: boolean
 }
 
 export interface SchemaRef { Ref: string }
 
 export interface SchemaOrRef { Schema?: Schema; Ref?: SchemaRef }
+
+// compareNames orders the names the tools list: schemas, properties, tags,
+// paths and headers. Names compare case-insensitively first, folding only
+// the ASCII letters, then by code point, so 'apple' < 'Pet' < 'pet' < 'Zebra';
+// non-ASCII characters sort by code point after ASCII. It is the order of
+// the generator's model, which localeCompare and the UTF-16 order of
+// Array.prototype.sort are not.
+export function compareNames(a: string, b: string): number {
+  const x = codePoints(a)
+  const y = codePoints(b)
+  const n = Math.min(x.length, y.length)
+  for (let i = 0; i < n; i++) {
+    const fx = foldAscii(x[i]), fy = foldAscii(y[i])
+    if (fx !== fy) return fx < fy ? -1 : 1
+  }
+  if (x.length !== y.length) return x.length < y.length ? -1 : 1
+  for (let i = 0; i < n; i++) {
+    if (x[i] !== y[i]) return x[i] < y[i] ? -1 : 1
+  }
+  return 0
+}
+
+// sortNames sorts names in place by compareNames and returns them.
+export function sortNames(names: string[]): string[] {
+  return names.sort(compareNames)
+}
+
+function codePoints(s: string): number[] {
+  return Array.from(s, c => c.codePointAt(0) as number)
+}
+
+function foldAscii(c: number): number {
+  return c >= 0x41 && c <= 0x5a ? c + 0x20 : c
+}
 `) + "\n"
 }
 
-func renderSpecLoaderTs() string {
-	return normalize(`import { readFileSync } from 'fs'
-import { fileURLToPath } from 'url'
-import { dirname, join } from 'path'
-import type { ServiceModel } from './model.js'
+// renderSpecIndexTs is the entry point of the library layout.
+func renderSpecIndexTs(data templateData) string {
+	content := "export * from './model.js'\nexport * from './loader.js'\n"
+	if data.zod {
+		content += "export * from './schemas.js'\n"
+	}
+	return normalize(content) + "\n"
+}
 
-export function loadServiceModel(): ServiceModel {
-  try {
+func renderSpecLoaderTs(data templateData) string {
+	// CommonJS provides __dirname; ES modules derive it from import.meta.url.
+	imports := "import { join } from 'path'"
+	dirname := ""
+	if data.esm {
+		imports = "import { fileURLToPath } from 'url'\nimport { dirname, join } from 'path'"
+		dirname = `
     const __filename = fileURLToPath(import.meta.url)
-    const __dirname = dirname(__filename)
+    const __dirname = dirname(__filename)`
+	}
+	types := "ServiceModel, Server"
+	load := `export function loadServiceModel(): ServiceModel {
+  try {` + dirname + `
     const modelPath = join(__dirname, 'model.json')
     const modelData = readFileSync(modelPath, 'utf-8')
     return JSON.parse(modelData) as ServiceModel
@@ -696,11 +1511,150 @@ export function loadServiceModel(): ServiceModel {
     throw error
   }
 }
+
+// modelHash returns the hex SHA-256 of model.json.
+export function modelHash(): string {` + strings.ReplaceAll(dirname, "\n    ", "\n  ") + `
+  return createHash('sha256').update(readFileSync(join(__dirname, 'model.json'))).digest('hex')
+}
+`
+	fsImports := "readFileSync"
+	if data.shardModel {
+		types = "EndpointModel, ServiceModel, Server"
+		load = shardedLoaderTs(strings.ReplaceAll(dirname, "\n    ", "\n  "))
+		fsImports = "readFileSync, readdirSync"
+	}
+	return normalize(`import { createHash } from 'crypto'
+import { `+fsImports+` } from 'fs'
+`+imports+`
+import type { `+types+` } from './model.js'
+
+`+load+`
+// BASE_URL_ENV names an environment variable that, when set, replaces the
+// spec's server selection so one build can target staging and production.
+export const BASE_URL_ENV = 'API_BASE_URL'
+
+function baseUrlFromEnv(): string {
+  return (process.env[BASE_URL_ENV] ?? '').trim()
+}
+
+// applyBaseUrlOverride puts the URL from API_BASE_URL, when set, first in
+// sm.Servers (dropping a duplicate entry), so it wins over the spec's servers.
+export function applyBaseUrlOverride(sm: ServiceModel): ServiceModel {
+  const url = baseUrlFromEnv()
+  if (!url) return sm
+  const servers: Server[] = [{ URL: url, Description: 'from ' + BASE_URL_ENV }]
+  for (const s of sm.Servers ?? []) {
+    if (s.URL !== url) servers.push(s)
+  }
+  sm.Servers = servers
+  return sm
+}
+
+// baseUrl returns the URL requests should target: API_BASE_URL when set,
+// otherwise the first server's URL, or '' when the spec lists none.
+export function baseUrl(sm: ServiceModel): string {
+  return baseUrlFromEnv() || sm.Servers?.[0]?.URL || ''
+}
 `) + "\n"
 }
 
+// shardedLoaderTs returns the loader functions of ShardModelByTag, which read
+// model/_index.json and the per-tag shards next to loader.ts. dirname
+// defines __dirname in ES modules and is empty for CommonJS.
+func shardedLoaderTs(dirname string) string {
+	return `// Shard describes one endpoint shard of the model: model/<Name>.json holds
+// the endpoints whose first tag is Tag ('' for those without tags).
+export interface Shard {
+  Name: string
+  Tag: string
+  Endpoints: number
+}
+
+// ModelIndex is model/_index.json: the model without its endpoints, and the
+// shard of every endpoint in model order.
+interface ModelIndex extends ServiceModel {
+  Shards: Shard[]
+  EndpointShards: Array<{ ID: string; Shard: string }>
+}
+
+function readModelFile<T>(name: string): T {` + dirname + `
+  return JSON.parse(readFileSync(join(__dirname, 'model', name + '.json'), 'utf-8')) as T
+}
+
+let cachedIndex: ModelIndex | undefined
+const cachedShards = new Map<string, EndpointModel[]>()
+
+function withoutShards(idx: ModelIndex): ServiceModel {
+  const sm: Partial<ModelIndex> = { ...idx }
+  delete sm.Shards
+  delete sm.EndpointShards
+  return sm as ServiceModel
+}
+
+// loadIndex returns the model without its endpoints, reading no shard.
+export function loadIndex(): ServiceModel {
+  return withoutShards(readModelFile<ModelIndex>('_index'))
+}
+
+// shards lists the shards in the order their first endpoint appears.
+export function shards(): Shard[] {
+  if (!cachedIndex) cachedIndex = readModelFile<ModelIndex>('_index')
+  return [...cachedIndex.Shards]
+}
+
+// loadShard returns the endpoints of the named shard, reading its file on the
+// first call only.
+export function loadShard(name: string): EndpointModel[] {
+  let eps = cachedShards.get(name)
+  if (!eps) {
+    eps = readModelFile<EndpointModel[]>(name)
+    cachedShards.set(name, eps)
+  }
+  return eps
+}
+
+// loadTag returns the endpoints whose first tag is tag, or those without tags
+// for '', reading only their shard.
+export function loadTag(tag: string): EndpointModel[] {
+  const shard = shards().find(s => s.Tag === tag)
+  return shard ? loadShard(shard.Name) : []
+}
+
+// loadServiceModel returns the whole model, assembled from the index and
+// every shard in model order.
+export function loadServiceModel(): ServiceModel {
+  try {
+    const idx = readModelFile<ModelIndex>('_index')
+    const byShard = new Map(idx.Shards.map(s => [s.Name, readModelFile<EndpointModel[]>(s.Name)]))
+    const sm = withoutShards(idx)
+    sm.Endpoints = idx.EndpointShards.map(e => {
+      const ep = byShard.get(e.Shard)?.shift()
+      if (!ep) throw new Error('model shard ' + e.Shard + ' is missing endpoint ' + e.ID)
+      return ep
+    })
+    return sm
+  } catch (error) {
+    console.error('[spec-loader] failed to load the model shards:', error)
+    throw error
+  }
+}
+
+// modelHash returns the hex SHA-256 of the model files under model/, in name
+// order.
+export function modelHash(): string {` + dirname + `
+  const dir = join(__dirname, 'model')
+  const hash = createHash('sha256')
+  for (const name of readdirSync(dir).filter(n => n.endsWith('.json')).sort()) {
+    hash.update(readFileSync(join(dir, name)))
+  }
+  return hash.digest('hex')
+}
+`
+}
+
 func renderListEndpointsTs() string {
-	return normalize(`import type { ServiceModel } from '../../spec/model.js'
+	return normalize(`import { compareNames, type ServiceModel } from '../../spec/model.js'
+import { baseUrl } from '../../spec/loader.js'
 
 export interface EndpointSummary { 
   id: string; 
@@ -734,7 +1688,7 @@ export function listEndpoints(sm: ServiceModel): EndpointSummary[] {
       description: r.Description
     }))
   }))
-  out.sort((a, b) => a.path === b.path ? a.method.localeCompare(b.method) : a.path.localeCompare(b.path))
+  out.sort((a, b) => a.path === b.path ? compareNames(a.method, b.method) : compareNames(a.path, b.path))
   return out
 }
 
@@ -747,6 +1701,8 @@ export function formatEndpointsOverview(sm: ServiceModel): string {
 
   const lines: string[] = []
   lines.push('API 接口概览 (' + endpoints.length + ' 个接口)')
+  const base = baseUrl(sm)
+  if (base) lines.push('基础 URL: ' + base)
   lines.push('')
 
   // 按标签分组统计
@@ -773,13 +1729,14 @@ export function formatEndpointsOverview(sm: ServiceModel): string {
     lines.push('服务模块分布:')
     // 按数量排序
     const tagList = Array.from(tagStats.entries())
-      .sort((a, b) => b[1] - a[1])
+      .sort((a, b) => b[1] - a[1] || compareNames(a[0], b[0]))
     
     // 显示前10个最多的标签
     const maxShow = Math.min(10, tagList.length)
     for (let i = 0; i < maxShow; i++) {
       const [tag, count] = tagList[i]
-      lines.push('  ' + tag + ': ' + count + ' 个接口')
+      const desc = sm.TagDescriptions?.[tag]
+      lines.push('  ' + tag + ': ' + count + ' 个接口' + (desc ? ' — ' + desc : ''))
     }
     if (tagList.length > 10) {
       lines.push('  ... 还有 ' + (tagList.length - 10) + ' 个其他服务模块')
@@ -806,7 +1763,7 @@ export function formatEndpointsOverview(sm: ServiceModel): string {
   
   // 按数量排序路径前缀
   const pathList = Array.from(pathPrefixes.entries())
-    .sort((a, b) => b[1] - a[1])
+    .sort((a, b) => b[1] - a[1] || compareNames(a[0], b[0]))
   
   // 显示前10个最常用的路径前缀
   const maxPaths = Math.min(10, pathList.length)
@@ -832,7 +1789,7 @@ export function formatEndpointsOverview(sm: ServiceModel): string {
 }
 
 func renderSearchEndpointsTs() string {
-	return normalize(`import type { ServiceModel } from '../../spec/model.js'
+	return normalize(`import { compareNames, type ServiceModel } from '../../spec/model.js'
 
 export interface SearchQuery { keyword?: string; tag?: string; method?: string; pathPattern?: string }
 export interface EndpointSearchResult { 
@@ -870,7 +1827,7 @@ export function searchEndpoints(sm: ServiceModel, q: SearchQuery): EndpointSearc
       tags: [...(ep.Tags||[])]
     })
   }
-  out.sort((a, b) => a.path === b.path ? a.method.localeCompare(b.method) : a.path.localeCompare(b.path))
+  out.sort((a, b) => a.path === b.path ? compareNames(a.method, b.method) : compareNames(a.path, b.path))
   return out
 }
 `) + "\n"
@@ -897,13 +1854,13 @@ export function getEndpointDetails(sm: ServiceModel, idOrMethod: string, pathOpt
 }
 
 func renderListSchemasTs() string {
-	return normalize(`import type { ServiceModel } from '../../spec/model.js'
+	return normalize(`import { sortNames, type ServiceModel } from '../../spec/model.js'
 
 export interface SchemaSummary { name: string; description?: string }
 
 export function listSchemas(sm: ServiceModel): SchemaSummary[] {
   const names = Object.keys(sm.Schemas || {})
-  names.sort()
+  sortNames(names)
   return names.map(n => ({ name: n, description: sm.Schemas[n]?.Description }))
 }
 `) + "\n"
@@ -921,9 +1878,826 @@ export function getSchemaDetails(sm: ServiceModel, name: string): [Schema, true]
 `) + "\n"
 }
 
+func renderExplainParameterTs() string {
+	return normalize(`import { sortNames, type ServiceModel, type EndpointModel, type ParameterModel, type Schema, type SchemaOrRef } from '../../spec/model.js'
+import { formatSchemaWithRefs, schemaConstraints } from './formatSchema.js'
+
+// ParameterExplanation is everything the model knows about one parameter of
+// an endpoint, with its schema reference resolved.
+export interface ParameterExplanation {
+  endpointId: string
+  name: string
+  in: string // path|query|header|cookie, or body for a request body property
+  required: boolean
+  style?: string
+  explode: boolean
+  contentType?: string // body properties only
+  ref?: string
+  schema?: Schema
+  enum?: any[]
+  example?: any
+  constraints?: string[]
+  usage: string // how the parameter appears in a request
+}
+
+// explainParameter explains parameter name of the endpoint endpointId, given
+// as its "<method> <path>" ID or operationId. Top-level request body
+// properties count as parameters in "body". An unknown endpoint or parameter
+// yields a message listing the closest names.
+export function explainParameter(sm: ServiceModel, endpointId: string, name: string): [ParameterExplanation, true, ''] | [undefined, false, string] {
+  const ep = findEndpoint(sm, endpointId)
+  if (!ep) {
+    const ids = (sm.Endpoints || []).map(e => e.ID)
+    return [undefined, false, notFound('endpoint "' + endpointId + '" not found', endpointId, ids, false)]
+  }
+  name = (name || '').trim()
+  const names: string[] = []
+  for (const p of ep.Parameters || []) {
+    if (p.Name === name) return [explainModelParameter(sm, ep, p), true, '']
+    names.push(p.Name)
+  }
+  const [body, mime] = requestBodySchema(sm, ep)
+  if (body) {
+    for (const prop of sortNames(Object.keys(body.Properties || {}))) {
+      if (prop === name) return [explainBodyProperty(sm, ep, body, prop, mime), true, '']
+      names.push(prop)
+    }
+  }
+  return [undefined, false, notFound('parameter "' + name + '" not found on ' + ep.ID, name, names, true)]
+}
+
+// formatParameterExplanation formats an explanation for the explainParameter tool.
+export function formatParameterExplanation(px: ParameterExplanation, sm: ServiceModel): string {
+  const lines = ['参数: ' + px.name + ' (' + px.in + ') ' + (px.required ? '[必需]' : '[可选]')]
+  lines.push('端点: ' + px.endpointId)
+  if (px.in === 'body') {
+    lines.push('Content-Type: ' + (px.contentType || 'unknown'))
+  } else {
+    lines.push('序列化: style=' + px.style + ', explode=' + px.explode)
+  }
+  lines.push('', 'Schema:')
+  if (px.ref) lines.push('  引用: ' + px.ref)
+  if (px.schema) {
+    if (px.schema.Format) lines.push('  格式: ' + px.schema.Format)
+    lines.push(...formatSchemaWithRefs(px.schema, sm, '  '))
+  } else {
+    lines.push('  类型: unknown')
+  }
+  if (px.example !== undefined && px.example !== null) {
+    lines.push('', '示例: ' + JSON.stringify(px.example))
+  }
+  lines.push('', '用法:')
+  for (const line of px.usage.split('\n')) lines.push(line ? '  ' + line : '')
+  return lines.join('\n')
+}
+
+// findEndpoint resolves an endpoint ID (method case-insensitive) or operationId.
+export function findEndpoint(sm: ServiceModel, key: string): EndpointModel | undefined {
+  key = (key || '').trim()
+  const space = key.indexOf(' ')
+  if (space > 0) {
+    const id = key.slice(0, space).toLowerCase() + ' ' + key.slice(space + 1).trim()
+    const ep = (sm.Endpoints || []).find(e => e.ID === id)
+    if (ep) return ep
+  }
+  return (sm.Endpoints || []).find(e => !!e.OperationID && e.OperationID === key)
+}
+
+function explainModelParameter(sm: ServiceModel, ep: EndpointModel, p: ParameterModel): ParameterExplanation {
+  const style = p.Style || (p.In === 'query' || p.In === 'cookie' ? 'form' : 'simple')
+  const explode = p.Explode ?? style === 'form'
+  const [ref, schema] = resolveSchema(sm, p.Schema)
+  const px: ParameterExplanation = { endpointId: ep.ID, name: p.Name, in: p.In, required: p.Required || p.In === 'path', style, explode, ref, schema, usage: '' }
+  describeSchema(px, sm)
+  px.usage = parameterUsage(ep, px, sampleValue(sm, schema, 0))
+  return px
+}
+
+function explainBodyProperty(sm: ServiceModel, ep: EndpointModel, body: Schema, name: string, mime: string): ParameterExplanation {
+  const [ref, schema] = resolveSchema(sm, body.Properties?.[name])
+  const px: ParameterExplanation = { endpointId: ep.ID, name, in: 'body', required: (body.Required || []).includes(name), explode: false, contentType: mime, ref, schema, usage: '' }
+  describeSchema(px, sm)
+  const value = sampleValue(sm, schema, 0)
+  const payload = !mime || mime.includes('json')
+    ? JSON.stringify({ [name]: value }, null, 2)
+    : encodeURIComponent(name) + '=' + encodeURIComponent(scalarString(value))
+  px.usage = ep.Method.toUpperCase() + ' ' + ep.Path + '\nContent-Type: ' + (mime || 'application/json') + '\n\n' + payload
+  return px
+}
+
+// describeSchema copies enum values, example and constraints from the schema,
+// looking at the items of an array.
+function describeSchema(px: ParameterExplanation, sm: ServiceModel) {
+  const s = px.schema
+  if (!s) return
+  px.enum = s.Enum
+  px.example = s.Example
+  const constraints = schemaConstraints(s)
+  if (constraints.length > 0) px.constraints = constraints
+  if (s.Type === 'array' && !px.enum) {
+    px.enum = resolveSchema(sm, s.Items)[1]?.Enum
+  }
+}
+
+// requestBodySchema returns the resolved object schema of the first request
+// body media type that has properties.
+function requestBodySchema(sm: ServiceModel, ep: EndpointModel): [Schema | undefined, string] {
+  for (const media of ep.RequestBody?.Content || []) {
+    const s = resolveSchema(sm, media.Schema)[1]
+    if (s && s.Properties && Object.keys(s.Properties).length > 0) return [s, media.Mime]
+  }
+  return [undefined, '']
+}
+
+// resolveSchema returns the referenced schema name, if any, and the schema.
+function resolveSchema(sm: ServiceModel, sr?: SchemaOrRef): [string | undefined, Schema | undefined] {
+  if (!sr) return [undefined, undefined]
+  if (!sr.Ref?.Ref) return [undefined, sr.Schema]
+  const name = sr.Ref.Ref.replace('#/components/schemas/', '').replace('#/definitions/', '')
+  return [name, sm.Schemas?.[name] || sr.Schema]
+}
+
+// sampleValue picks a value to show in the usage snippet: the schema's
+// example, its first enum value, or a placeholder of the right type.
+function sampleValue(sm: ServiceModel, s: Schema | undefined, depth: number): any {
+  if (!s) return 'value'
+  if (s.Example !== undefined && s.Example !== null) return s.Example
+  if (s.Enum && s.Enum.length > 0) return s.Enum[0]
+  switch (s.Type) {
+    case 'integer':
+      return s.Minimum != null ? Math.trunc(s.Minimum) : 1
+    case 'number':
+      return s.Minimum != null ? s.Minimum : 1.5
+    case 'boolean':
+      return true
+    case 'array': {
+      const items = resolveSchema(sm, s.Items)[1]
+      if (items?.Enum && items.Enum.length > 1) return [items.Enum[0], items.Enum[1]]
+      return depth > 2 ? [] : [sampleValue(sm, items, depth + 1)]
+    }
+    case 'object': {
+      const obj: Record<string, any> = {}
+      if (depth > 2) return obj
+      for (const name of sortNames(Object.keys(s.Properties || {})).slice(0, 2)) {
+        obj[name] = sampleValue(sm, resolveSchema(sm, s.Properties![name])[1], depth + 1)
+      }
+      if (Object.keys(obj).length === 0) obj.key = 'value'
+      return obj
+    }
+  }
+  switch (s.Format) {
+    case 'date': return '2024-01-01'
+    case 'date-time': return '2024-01-01T00:00:00Z'
+    case 'uuid': return '3fa85f64-5717-4562-b3fc-2c963f66afa6'
+  }
+  return 'value'
+}
+
+// parameterUsage shows value serialized per the parameter's style and explode
+// settings, in the request line or as a header.
+function parameterUsage(ep: EndpointModel, px: ParameterExplanation, value: any): string {
+  const method = ep.Method.toUpperCase()
+  switch (px.in) {
+    case 'path': {
+      let v: string
+      if (px.style === 'label') v = '.' + joinValue(value, px.explode, '.', encodeURIComponent)
+      else if (px.style === 'matrix') v = matrixValue(px.name, value, px.explode)
+      else v = joinValue(value, px.explode, ',', encodeURIComponent)
+      return method + ' ' + ep.Path.split('{' + px.name + '}').join(v)
+    }
+    case 'header':
+      return method + ' ' + ep.Path + '\n' + px.name + ': ' + joinValue(value, px.explode, ',', (x: string) => x)
+    case 'cookie':
+      return method + ' ' + ep.Path + '\nCookie: ' + queryValue(px, value)
+  }
+  return method + ' ' + ep.Path + '?' + queryValue(px, value)
+}
+
+// queryValue serializes a form, spaceDelimited, pipeDelimited or deepObject
+// parameter as it appears in a query string.
+function queryValue(px: ParameterExplanation, value: any): string {
+  const name = encodeURIComponent(px.name)
+  if (Array.isArray(value)) {
+    const parts = value.map(item => encodeURIComponent(scalarString(item)))
+    if (px.style === 'spaceDelimited') return name + '=' + parts.join('%20')
+    if (px.style === 'pipeDelimited') return name + '=' + parts.join('|')
+    if (px.explode) return name + '=' + parts.join('&' + name + '=')
+    return name + '=' + parts.join(',')
+  }
+  if (value !== null && typeof value === 'object') {
+    const parts = Object.keys(value).sort().map(k => {
+      const v = encodeURIComponent(scalarString(value[k]))
+      if (px.style === 'deepObject') return name + '[' + encodeURIComponent(k) + ']=' + v
+      return encodeURIComponent(k) + (px.explode ? '=' : ',') + v
+    })
+    if (px.style === 'deepObject' || px.explode) return parts.join('&')
+    return name + '=' + parts.join(',')
+  }
+  return name + '=' + encodeURIComponent(scalarString(value))
+}
+
+// joinValue serializes a simple or label style value, joining array items
+// and object members with sep.
+function joinValue(value: any, explode: boolean, sep: string, escape: (s: string) => string): string {
+  if (Array.isArray(value)) return value.map(item => escape(scalarString(item))).join(sep)
+  if (value !== null && typeof value === 'object') {
+    const parts: string[] = []
+    for (const k of Object.keys(value).sort()) {
+      if (explode) parts.push(escape(k) + '=' + escape(scalarString(value[k])))
+      else parts.push(escape(k), escape(scalarString(value[k])))
+    }
+    return parts.join(sep)
+  }
+  return escape(scalarString(value))
+}
+
+function matrixValue(name: string, value: any, explode: boolean): string {
+  if (Array.isArray(value) && explode) {
+    return value.map(item => ';' + name + '=' + encodeURIComponent(scalarString(item))).join('')
+  }
+  return ';' + name + '=' + joinValue(value, explode, ',', encodeURIComponent)
+}
+
+function scalarString(v: any): string {
+  return typeof v === 'string' ? v : JSON.stringify(v)
+}
+
+// notFound builds the message for an unknown name, suggesting the closest
+// candidates, or all of them when listAll is set and none is close.
+export function notFound(msg: string, name: string, candidates: string[], listAll: boolean): string {
+  let suggestions = closestNames(name, candidates)
+  if (suggestions.length === 0 && listAll) suggestions = candidates
+  if (suggestions.length === 0) return msg + '; use searchEndpoints to find it'
+  return msg + '; did you mean: ' + suggestions.join(', ')
+}
+
+// closestNames returns up to five candidates that contain name, are contained
+// in it, or are within a small edit distance, closest first.
+function closestNames(name: string, candidates: string[]): string[] {
+  const want = (name || '').trim().toLowerCase()
+  if (!want) return []
+  const limit = Math.max(2, Math.floor(want.length / 3))
+  return candidates
+    .map(c => ({ name: c, lc: c.toLowerCase(), dist: editDistance(want, c.toLowerCase()) }))
+    .filter(c => c.dist <= limit || c.lc.includes(want) || want.includes(c.lc))
+    .sort((a, b) => a.dist - b.dist)
+    .slice(0, 5)
+    .map(c => c.name)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+function editDistance(a: string, b: string): number {
+  const ra = Array.from(a), rb = Array.from(b)
+  let prev = Array.from({ length: rb.length + 1 }, (_, j) => j)
+  for (let i = 1; i <= ra.length; i++) {
+    const cur = [i]
+    for (let j = 1; j <= rb.length; j++) {
+      cur[j] = Math.min(prev[j] + 1, cur[j - 1] + 1, prev[j - 1] + (ra[i - 1] === rb[j - 1] ? 0 : 1))
+    }
+    prev = cur
+  }
+  return prev[rb.length]
+}
+`) + "\n"
+}
+
+// renderTransportTestsTs covers the transport helpers; index.ts starts a
+// server on import, so they live in transport.ts.
+// renderCallEndpointTs renders the callEndpoint method: it checks a call
+// against the endpoint's parameters and request body, then sends it to the
+// upstream API with fetch.
+func renderCallEndpointTs(data templateData) string {
+	return normalize(`import { STATUS_CODES } from 'node:http'
+import { sortNames, type EndpointModel, type ParameterModel, type ServiceModel } from '../../spec/model.js'
+import { BASE_URL_ENV, baseUrl } from '../../spec/loader.js'
+import { findEndpoint, notFound } from './explainParameter.js'
+
+// CALL_TIMEOUT_ENV names an environment variable holding the timeout of one
+// callEndpoint request: a duration such as 10s or 500ms, or a number of
+// seconds.
+export const CALL_TIMEOUT_ENV = 'API_TIMEOUT'
+
+// DEFAULT_CALL_TIMEOUT_MS bounds a callEndpoint request when API_TIMEOUT is
+// unset or invalid.
+export const DEFAULT_CALL_TIMEOUT_MS = 30_000
+
+// CALL_RETRIES_ENV names an environment variable holding how many times
+// callEndpoint resends a request that failed, timed out or got a 429 or 5xx
+// response.
+export const CALL_RETRIES_ENV = 'API_RETRIES'
+
+// DEFAULT_CALL_RETRIES is the retry count when API_RETRIES is unset or
+// invalid.
+export const DEFAULT_CALL_RETRIES = 0
+
+// CALL_RETRY_DELAY_MS is the wait before the first retry; it doubles for
+// each further retry, up to MAX_CALL_RETRY_DELAY_MS.
+export const CALL_RETRY_DELAY_MS = 100
+export const MAX_CALL_RETRY_DELAY_MS = 2_000
+
+// MAX_RESPONSE_BYTES is how much of a response body callEndpoint returns; the
+// rest is dropped and the result marked truncated.
+export const MAX_RESPONSE_BYTES = 64 * 1024
+
+const DURATION_UNITS: Record<string, number> = { ms: 1, s: 1000, m: 60_000, h: 3_600_000 }
+
+// Credential is how callEndpoint authenticates with one security scheme: the
+// secret read from envVar is sent as the in parameter name.
+export interface Credential {
+  kind: 'apiKey' | 'bearer' | 'basic' // basic: envVar holds user:password
+  in: 'header' | 'query' | 'cookie'
+  name: string
+  envVar: string
+}
+
+// CREDENTIALS holds the security schemes of the model callEndpoint can apply,
+// by name.
+`+tsCredentials(data)+`
+
+// CallArgs are the arguments of the callEndpoint tool. params holds path,
+// query, header and cookie parameters by name; each is sent where the
+// endpoint declares it.
+export interface CallArgs {
+  endpointId: string
+  params?: Record<string, any>
+  body?: any
+}
+
+// CallResult is the upstream response to a callEndpoint request.
+export interface CallResult {
+  method: string
+  url: string
+  status: number
+  headers: Record<string, string>
+  body: string
+  truncated: boolean // body holds only the first MAX_RESPONSE_BYTES
+}
+
+// BuiltRequest is a validated request, ready for fetch.
+export interface BuiltRequest {
+  method: string
+  url: string
+  headers: Record<string, string>
+  body?: string
+}
+
+// callTimeoutMs returns the request timeout from API_TIMEOUT, or
+// DEFAULT_CALL_TIMEOUT_MS.
+export function callTimeoutMs(env: Record<string, string | undefined> = process.env): number {
+  const m = /^(\d+(?:\.\d+)?)(ms|s|m|h)?$/.exec((env[CALL_TIMEOUT_ENV] || '').trim())
+  if (!m) return DEFAULT_CALL_TIMEOUT_MS
+  const ms = Number(m[1]) * DURATION_UNITS[m[2] || 's']
+  return ms > 0 ? ms : DEFAULT_CALL_TIMEOUT_MS
+}
+
+// callRetries returns the retry count from API_RETRIES, or
+// DEFAULT_CALL_RETRIES.
+export function callRetries(env: Record<string, string | undefined> = process.env): number {
+  const v = (env[CALL_RETRIES_ENV] || '').trim()
+  return /^\d+$/.test(v) ? Number(v) : DEFAULT_CALL_RETRIES
+}
+
+// callEndpoint validates args against the endpoint's parameters and request
+// body, then sends the request and returns the response. Nothing is sent
+// when validation fails. Each attempt is bounded by callTimeoutMs; a request
+// that fails, times out or gets a 429 or 5xx response is resent up to
+// callRetries times, and the last outcome is returned. Non-2xx responses are
+// results, not errors.
+export async function callEndpoint(sm: ServiceModel, args: CallArgs, base?: string): Promise<CallResult> {
+  const req = buildRequest(sm, args, base)
+  const timeoutMs = callTimeoutMs()
+  const retries = callRetries()
+  let delay = CALL_RETRY_DELAY_MS
+  for (let attempt = 0; ; attempt++) {
+    let res: CallResult | undefined
+    let err: unknown
+    try {
+      res = await send(req, timeoutMs)
+    } catch (e) {
+      err = e
+    }
+    const retry = !res || res.status === 429 || res.status >= 500
+    if (!retry || attempt >= retries) {
+      if (!res) throw err
+      return res
+    }
+    await new Promise(resolve => setTimeout(resolve, delay))
+    delay = Math.min(delay * 2, MAX_CALL_RETRY_DELAY_MS)
+  }
+}
+
+// send makes one attempt at req; an AbortController cancels it, reading the
+// body included, after timeoutMs.
+async function send(req: BuiltRequest, timeoutMs: number): Promise<CallResult> {
+  const controller = new AbortController()
+  const timer = setTimeout(() => controller.abort(new Error('timed out after ' + timeoutMs + 'ms')), timeoutMs)
+  try {
+    const res = await fetch(req.url, { method: req.method, headers: req.headers, body: req.body, signal: controller.signal })
+    const headers: Record<string, string> = {}
+    res.headers.forEach((value, name) => { headers[name] = value })
+    const [body, truncated] = await readLimited(res, MAX_RESPONSE_BYTES)
+    return { method: req.method, url: req.url, status: res.status, headers, body, truncated }
+  } catch (e: any) {
+    throw new Error(req.method + ' ' + req.url + ': ' + (e?.message || String(e)))
+  } finally {
+    clearTimeout(timer)
+  }
+}
+
+// buildRequest builds the request for args against base, or baseUrl(sm) when
+// base is empty. It throws on an unknown endpoint or parameter, a missing
+// required parameter, body or credential, and a base URL that is not
+// absolute.
+export function buildRequest(sm: ServiceModel, args: CallArgs, base?: string): BuiltRequest {
+  const ep = findEndpoint(sm, args.endpointId)
+  if (!ep) {
+    const ids = (sm.Endpoints || []).map(e => e.ID)
+    throw new Error(notFound('endpoint "' + args.endpointId + '" not found', args.endpointId, ids, false))
+  }
+
+  const params = args.params || {}
+  const known = new Map<string, ParameterModel>()
+  const missing: string[] = []
+  for (const p of ep.Parameters || []) {
+    if (known.has(p.Name)) continue
+    known.set(p.Name, p)
+    if (!(p.Name in params) && (p.Required || p.In === 'path')) missing.push(p.Name + ' (' + p.In + ')')
+  }
+  const given = Object.keys(params).sort()
+  for (const name of given) {
+    if (!known.has(name)) throw new Error(notFound('parameter "' + name + '" not found on ' + ep.ID, name, [...known.keys()], true))
+  }
+  if (missing.length > 0) throw new Error(ep.ID + ': missing required parameters: ' + missing.join(', '))
+  if (ep.RequestBody?.Required && args.body == null) throw new Error(ep.ID + ': a request body is required')
+
+  base = (base || baseUrl(sm)).trim()
+  let root: URL | undefined
+  try {
+    root = new URL(base)
+  } catch {
+    root = undefined
+  }
+  if (!root || !root.host) throw new Error('base URL "' + base + '" is not an absolute URL; set ' + BASE_URL_ENV)
+
+  let path = ep.Path
+  const query = new URLSearchParams()
+  const headers: Record<string, string> = {}
+  const cookies: string[] = []
+  for (const name of given) {
+    const p = known.get(name)!
+    const values = paramValues(params[name])
+    switch (p.In) {
+      case 'path':
+        path = path.split('{' + name + '}').join(values.map(encodeURIComponent).join(','))
+        break
+      case 'query': {
+        const explode = p.Explode ?? (!p.Style || p.Style === 'form')
+        if (explode) values.forEach(v => query.append(name, v))
+        else query.set(name, values.join(queryDelimiter(p.Style)))
+        break
+      }
+      case 'header':
+        headers[name] = values.join(',')
+        break
+      case 'cookie':
+        cookies.push(name + '=' + encodeURIComponent(values.join(',')))
+        break
+    }
+  }
+  applyCredentials(ep, query, headers, cookies)
+  let url = root.toString().replace(/\/$/, '') + path
+  if ([...query.keys()].length > 0) url += '?' + query.toString()
+
+  let body: string | undefined
+  if (args.body != null) {
+    body = JSON.stringify(args.body)
+    if (!hasHeader(headers, 'content-type')) headers['Content-Type'] = 'application/json'
+  }
+  if (cookies.length > 0) headers['Cookie'] = cookies.join('; ')
+  if (!hasHeader(headers, 'accept')) headers['Accept'] = 'application/json, */*;q=0.8'
+  return { method: ep.Method.toUpperCase(), url, headers, body }
+}
+
+// formatCallResult formats a response for the callEndpoint tool.
+export function formatCallResult(res: CallResult): string {
+  const lines = [res.method + ' ' + res.url, ('HTTP ' + res.status + ' ' + (STATUS_CODES[res.status] || '')).trim()]
+  for (const name of sortNames(Object.keys(res.headers))) lines.push(name + ': ' + res.headers[name])
+  lines.push('', res.body)
+  if (res.truncated) lines.push('... (truncated to ' + MAX_RESPONSE_BYTES + ' bytes)')
+  return lines.join('\n')
+}
+
+// applyCredentials adds the credentials of the first security alternative of
+// ep whose environment variables are all set. Parameters given explicitly
+// keep their values. It throws when ep requires credentials and no
+// alternative is met.
+function applyCredentials(ep: EndpointModel, query: URLSearchParams, headers: Record<string, string>, cookies: string[]): void {
+  const security = ep.Security || []
+  let anonymous = security.length === 0
+  const options: string[] = []
+  for (const alt of security) {
+    if (alt.length === 0) {
+      anonymous = true
+      continue
+    }
+    if (!alt.every(scheme => Object.prototype.hasOwnProperty.call(CREDENTIALS, scheme))) continue
+    const creds = alt.map(scheme => CREDENTIALS[scheme])
+    const unset = creds.filter(c => !process.env[c.envVar]).map(c => c.envVar)
+    if (unset.length > 0) {
+      options.push(unset.join(' and '))
+      continue
+    }
+    for (const c of creds) applyCredential(c, process.env[c.envVar]!, query, headers, cookies)
+    return
+  }
+  if (anonymous) return
+  if (options.length === 0) throw new Error(ep.ID + ': missing credentials: no supported security scheme in ' + JSON.stringify(security))
+  throw new Error(ep.ID + ': missing credentials: set ' + options.join(' or '))
+}
+
+function applyCredential(c: Credential, value: string, query: URLSearchParams, headers: Record<string, string>, cookies: string[]): void {
+  if (c.kind === 'bearer') value = 'Bearer ' + value
+  else if (c.kind === 'basic') value = 'Basic ' + Buffer.from(value).toString('base64')
+  if (c.in === 'query') {
+    if (!query.has(c.name)) query.set(c.name, value)
+  } else if (c.in === 'cookie') {
+    if (!cookies.some(cookie => cookie.startsWith(c.name + '='))) cookies.push(c.name + '=' + encodeURIComponent(value))
+  } else if (!hasHeader(headers, c.name.toLowerCase())) {
+    headers[c.name] = value
+  }
+}
+
+// readLimited reads at most limit bytes of the response body and reports
+// whether more was left.
+async function readLimited(res: Response, limit: number): Promise<[string, boolean]> {
+  const chunks: Uint8Array[] = []
+  let size = 0
+  const reader = res.body?.getReader()
+  while (reader && size <= limit) {
+    const { done, value } = await reader.read()
+    if (done) break
+    chunks.push(value)
+    size += value.length
+  }
+  const truncated = size > limit
+  if (truncated) await reader?.cancel()
+  return [Buffer.concat(chunks).subarray(0, limit).toString('utf8'), truncated]
+}
+
+// paramValues flattens a parameter value into its string forms: one per
+// array element, objects as JSON.
+function paramValues(v: any): string[] {
+  if (v === null || v === undefined) return ['']
+  if (Array.isArray(v)) return v.flatMap(paramValues)
+  if (typeof v === 'object') return [JSON.stringify(v)]
+  return [String(v)]
+}
+
+// queryDelimiter joins the values of a non-exploded query parameter.
+function queryDelimiter(style?: string): string {
+  if (style === 'spaceDelimited') return ' '
+  if (style === 'pipeDelimited') return '|'
+  return ','
+}
+
+function hasHeader(headers: Record<string, string>, name: string): boolean {
+  return Object.keys(headers).some(h => h.toLowerCase() === name)
+}
+`) + "\n"
+}
+
+// renderDescribeTs renders src/describe.ts, what --describe prints about the
+// server.
+func renderDescribeTs() string {
+	return normalize(`import { modelHash } from './spec/loader.js'
+import { compareNames, type ServiceModel } from './spec/model.js'
+
+// Description summarizes a server: the API it documents, the size of its
+// model and the tools it registers, sorted by name. modelHash is the hex
+// SHA-256 of the model data (see modelHash in spec/loader.ts).
+export interface Description {
+  title: string
+  version: string
+  endpoints: number
+  schemas: number
+  modelHash: string
+  tools: Array<{ name: string; description: string }>
+}
+
+// describeServer returns the Description of the server serving sm with the
+// tools table.
+export function describeServer(sm: ServiceModel, tools: Array<{ name: string; description: string }>): Description {
+  return {
+    title: sm.Title ?? '',
+    version: sm.Version ?? '',
+    endpoints: (sm.Endpoints ?? []).length,
+    schemas: Object.keys(sm.Schemas ?? {}).length,
+    modelHash: modelHash(),
+    tools: tools
+      .map(t => ({ name: t.name, description: t.description }))
+      .sort((a, b) => compareNames(a.name, b.name)),
+  }
+}
+`) + "\n"
+}
+
+// renderJestConfig returns the jest.config.js of TestRunnerJest. ts-jest
+// compiles each file on its own, without type-checking, which tsc does for
+// src/ in the build. Imports keep their .js extensions, as Node requires, and
+// are mapped back to the .ts sources; CommonJS packages compile to CommonJS
+// modules so describe.test.ts' dynamic import() becomes a require.
+func renderJestConfig(data templateData) string {
+	if data.esm {
+		return normalize(`/** @type {import('jest').Config} */
+export default {
+  testEnvironment: 'node',
+  testMatch: ['<rootDir>/__tests__/**/*.test.ts'],
+  extensionsToTreatAsEsm: ['.ts'],
+  moduleNameMapper: { '^(\\.{1,2}/.*)\\.js$': '$1' },
+  transform: { '^.+\\.ts$': ['ts-jest', { useESM: true, isolatedModules: true }] },
+}
+`)
+	}
+	return normalize(`/** @type {import('jest').Config} */
+module.exports = {
+  testEnvironment: 'node',
+  testMatch: ['<rootDir>/__tests__/**/*.test.ts'],
+  moduleNameMapper: { '^(\\.{1,2}/.*)\\.js$': '$1' },
+  transform: {
+    '^.+\\.ts$': ['ts-jest', { isolatedModules: true, tsconfig: { module: 'commonjs', target: 'ES2019', esModuleInterop: true } }],
+  },
+}
+`)
+}
+
+var (
+	vitestImport   = regexp.MustCompile(`import \{([^}]*)\} from 'vitest'`)
+	vitestMock     = regexp.MustCompile(`\bvi\b`)
+	vitestMockCall = regexp.MustCompile(`\bvi\.`)
+	vitestSkipIf   = regexp.MustCompile(`\b(it|describe)\.skipIf\(([^()]*)\)\(`)
+)
+
+// jestTestSource rewrites a vitest test file for Jest: describe, it and
+// expect come from @jest/globals, vi becomes jest, it.skipIf(cond) picks
+// between it.skip and it (after a semicolon, as the files omit them), and
+// expect loses the message argument Jest does not take.
+func jestTestSource(content string) string {
+	content = vitestImport.ReplaceAllStringFunc(content, func(m string) string {
+		names := vitestImport.FindStringSubmatch(m)[1]
+		return "import {" + vitestMock.ReplaceAllString(names, "jest") + "} from '@jest/globals'"
+	})
+	content = vitestMockCall.ReplaceAllString(content, "jest.")
+	content = vitestSkipIf.ReplaceAllString(content, ";(${2} ? ${1}.skip : ${1})(")
+	var b strings.Builder
+	for {
+		i := strings.Index(content, "expect(")
+		if i < 0 {
+			b.WriteString(content)
+			return b.String()
+		}
+		i += len("expect(")
+		end := i + argumentEnd(content[i:])
+		b.WriteString(content[:end])
+		for end < len(content) && content[end] == ',' {
+			end += 1 + argumentEnd(content[end+1:])
+		}
+		content = content[end:]
+	}
+}
+
+// argumentEnd returns the offset in s of the comma or closing parenthesis
+// that ends its first call argument, skipping nested brackets and quoted
+// strings, or len(s) when there is none.
+func argumentEnd(s string) int {
+	depth, quote := 0, byte(0)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case depth > 0 && (c == ')' || c == ']' || c == '}'):
+			depth--
+		case depth == 0 && (c == ',' || c == ')'):
+			return i
+		}
+	}
+	return len(s)
+}
+
+// renderDescribeTestsTs renders __tests__/describe.test.ts, which imports
+// index.ts with --describe and checks the JSON it prints.
+func renderDescribeTestsTs() string {
+	return normalize(`import { describe, it, expect, vi } from 'vitest'
+import { loadServiceModel } from '../src/spec/loader.js'
+import { compareNames } from '../src/spec/model.js'
+
+describe('--describe', () => {
+  it('prints the tools and a summary of the model as JSON', async () => {
+    const argv = process.argv
+    let out = ''
+    const write = vi.spyOn(process.stdout, 'write').mockImplementation((chunk: any) => {
+      out += String(chunk)
+      return true
+    })
+    process.argv = [...argv.slice(0, 2), '--describe']
+    try {
+      await import('../src/index.js')
+    } finally {
+      write.mockRestore()
+      process.argv = argv
+    }
+
+    const d = JSON.parse(out)
+    const sm = loadServiceModel()
+    expect(d.title).toBe(sm.Title)
+    expect(d.version).toBe(sm.Version)
+    expect(d.endpoints).toBe((sm.Endpoints ?? []).length)
+    expect(d.schemas).toBe(Object.keys(sm.Schemas ?? {}).length)
+    expect(d.modelHash).toMatch(/^[0-9a-f]{64}$/)
+    const names = d.tools.map((t: any) => t.name)
+    for (const name of ['listEndpoints', 'searchEndpoints', 'getEndpointDetails', 'listSchemas', 'getSchemaDetails', 'explainParameter']) {
+      expect(names).toContain(name)
+    }
+    expect(names).toEqual([...names].sort(compareNames))
+  })
+})
+`) + "\n"
+}
+
+func renderTransportTestsTs() string {
+	return normalize(`import { describe, it, expect } from 'vitest'
+import type { AddressInfo } from 'node:net'
+import { createHttpServer, httpAddress, parseTransport } from '../src/transport.js'
+
+describe('transport', () => {
+  it('parses --transport', () => {
+    expect(parseTransport([])).toBe('stdio')
+    expect(parseTransport(['--transport', 'http'])).toBe('http')
+    expect(parseTransport(['--transport=stdio'])).toBe('stdio')
+    expect(() => parseTransport(['--transport', 'sse'])).toThrow('unknown transport')
+  })
+
+  it('resolves the HTTP listen address', () => {
+    expect(httpAddress({})).toEqual({ host: '127.0.0.1', port: 8080 })
+    expect(httpAddress({ PORT: '9000' })).toEqual({ host: '0.0.0.0', port: 9000 })
+    expect(httpAddress({ PORT: '9000', MCP_HTTP_ADDR: 'localhost:7000' })).toEqual({ host: 'localhost', port: 7000 })
+    expect(() => httpAddress({ MCP_HTTP_ADDR: 'localhost' })).toThrow('invalid listen address')
+  })
+
+  it('answers JSON-RPC over streamable HTTP', async () => {
+    const server = createHttpServer((msg: any) => msg.id === undefined ? undefined : { jsonrpc: '2.0', id: msg.id, result: { method: msg.method } })
+    await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve))
+    const url = 'http://127.0.0.1:' + (server.address() as AddressInfo).port + '/mcp'
+    try {
+      const post = (body: unknown) => fetch(url, { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify(body) })
+      const res = await post({ jsonrpc: '2.0', id: 1, method: 'ping' })
+      expect(res.status).toBe(200)
+      expect(await res.json()).toEqual({ jsonrpc: '2.0', id: 1, result: { method: 'ping' } })
+      expect((await post({ jsonrpc: '2.0', method: 'notifications/initialized' })).status).toBe(202)
+      expect((await fetch(url)).status).toBe(405)
+    } finally {
+      await new Promise(resolve => server.close(resolve))
+    }
+  })
+})
+`) + "\n"
+}
+
+// renderTelemetryTestsTs checks that traceToolCall hands back what the tools
+// answer when no tracer provider is registered.
+func renderTelemetryTestsTs() string {
+	return normalize(`import { describe, it, expect } from 'vitest'
+import { loadServiceModel } from '../src/spec/loader.js'
+import * as Methods from '../src/mcp/methods/index.js'
+import { traceToolCall } from '../src/telemetry.js'
+
+describe('telemetry', () => {
+  it('returns tool results unchanged without a tracer provider', async () => {
+    const sm = loadServiceModel()
+    const overview = { jsonrpc: '2.0', id: 1, result: { content: [{ type: 'text', text: Methods.formatEndpointsOverview(sm) }] } }
+    expect(traceToolCall('listEndpoints', () => overview)).toBe(overview)
+    expect(await traceToolCall('listEndpoints', () => Promise.resolve(overview))).toBe(overview)
+    const [, found] = Methods.getSchemaDetails(sm, '__nonexistent__')
+    const missing = { jsonrpc: '2.0', id: 2, result: { isError: !found, content: [{ type: 'text', text: 'schema not found' }] } }
+    expect(traceToolCall('getSchemaDetails', () => missing)).toBe(missing)
+    await expect(traceToolCall('callEndpoint', () => Promise.reject(new Error('upstream down')))).rejects.toThrow('upstream down')
+  })
+})
+`) + "\n"
+}
+
 func renderGeneratedTestsTs() string {
 	return normalize(`import { describe, it, expect } from 'vitest'
 import { loadServiceModel } from '../src/spec/loader.js'
+import { sortNames } from '../src/spec/model.js'
 import * as Methods from '../src/mcp/methods/index.js'
 
 describe('MCP methods', () => {
@@ -955,17 +2729,275 @@ describe('MCP methods', () => {
       expect(det).toBeUndefined()
     }
   })
+
+  // explainParameter on the first query parameter with an enum and the first
+  // path parameter of the model; skipped when the spec has neither.
+  const sm = loadServiceModel()
+  const find = (match: (p: any) => boolean): [string, string] | undefined => {
+    for (const ep of sm.Endpoints || []) {
+      for (const p of ep.Parameters || []) {
+        if (match(p)) return [ep.ID, p.Name]
+      }
+    }
+    return undefined
+  }
+  const queryEnum = find(p => p.In === 'query' && (p.Schema?.Schema?.Enum?.length ?? 0) > 0)
+  const pathParam = find(p => p.In === 'path')
+
+  it.skipIf(!queryEnum)('explains a query enum parameter', () => {
+    const [id, name] = queryEnum!
+    const [px, ok] = Methods.explainParameter(sm, id, name)
+    expect(ok).toBe(true)
+    expect(px!.in).toBe('query')
+    expect(px!.enum?.length).toBeGreaterThan(0)
+    expect(px!.usage).toContain('?' + name + '=')
+    expect(Methods.formatParameterExplanation(px!, sm)).toContain('允许值')
+  })
+
+  it.skipIf(!pathParam)('explains a path parameter', () => {
+    const [id, name] = pathParam!
+    const [px, ok] = Methods.explainParameter(sm, id, name)
+    expect(ok).toBe(true)
+    expect(px!.required).toBe(true)
+    expect(px!.usage).not.toContain('{' + name + '}')
+    const [, found, message] = Methods.explainParameter(sm, id, name + 'x')
+    expect(found).toBe(false)
+    expect(message).toContain(name)
+  })
+
+  it('sorts names in the order of the generator', () => {
+    const names = ['Zebra', 'apple', 'pet', 'Pet', '_id', 'éclair', 'Eclair', 'b', 'B2', 'a10', 'a2', 'Ünits', 'units', 'zoo', '😀', '文件', 'ﬁ']
+    expect(sortNames(names)).toEqual(['_id', 'a10', 'a2', 'apple', 'b', 'B2', 'Eclair', 'Pet', 'pet', 'units', 'Zebra', 'zoo', 'Ünits', 'éclair', '文件', 'ﬁ', '😀'])
+  })
+})
+`) + "\n"
+}
+
+// tsCredentials declares the CREDENTIALS table of callEndpoint.ts.
+func tsCredentials(data templateData) string {
+	creds := emitter.Credentials(data.ToolName, data.service)
+	if len(creds) == 0 {
+		return "export const CREDENTIALS: Record<string, Credential> = {}"
+	}
+	lines := []string{"export const CREDENTIALS: Record<string, Credential> = {"}
+	for _, c := range creds {
+		lines = append(lines, fmt.Sprintf("  %s: { kind: %s, in: %s, name: %s, envVar: %s },", tsPropertyKey(c.Scheme), tsString(c.Kind), tsString(c.In), tsString(c.Name), tsString(c.EnvVar)))
+	}
+	return strings.Join(append(lines, "}"), "\n")
+}
+
+// renderCallEndpointTestsTs renders __tests__/callEndpoint.test.ts, which
+// calls every endpoint of the model against a local stub server.
+func renderCallEndpointTestsTs() string {
+	return normalize(`import { describe, it, expect, beforeAll, afterAll } from 'vitest'
+import { createServer, type IncomingHttpHeaders } from 'node:http'
+import type { AddressInfo } from 'node:net'
+import { BASE_URL_ENV, loadServiceModel } from '../src/spec/loader.js'
+import type { EndpointModel } from '../src/spec/model.js'
+import * as Methods from '../src/mcp/methods/index.js'
+
+// StubRequest is a request received by the stub API server.
+interface StubRequest { method: string; url: string; headers: IncomingHttpHeaders; body: string }
+
+// The stub API answers every request with 418 and a small JSON body,
+// recording what it received. Under /big it answers with a body larger than
+// MAX_RESPONSE_BYTES, under /slow not for 5s, and under /flaky with 503 but
+// for every third request.
+const received: StubRequest[] = []
+let slowAttempts = 0
+let flakyAttempts = 0
+const stub = createServer((req, res) => {
+  let body = ''
+  req.setEncoding('utf8')
+  req.on('data', (chunk: string) => { body += chunk })
+  req.on('end', () => {
+    if ((req.url || '').startsWith('/big')) {
+      res.end('x'.repeat(70 * 1024))
+      return
+    }
+    if ((req.url || '').startsWith('/slow')) {
+      slowAttempts++
+      const timer = setTimeout(() => res.end(), 5_000)
+      res.on('close', () => clearTimeout(timer))
+      return
+    }
+    if ((req.url || '').startsWith('/flaky')) {
+      flakyAttempts++
+      res.writeHead(flakyAttempts % 3 === 0 ? 418 : 503)
+      res.end()
+      return
+    }
+    received.push({ method: req.method || '', url: req.url || '', headers: req.headers, body })
+    res.writeHead(418, { 'Content-Type': 'application/json' })
+    res.end('{"ok":true}')
+  })
+})
+let stubUrl = ''
+
+beforeAll(async () => {
+  await new Promise<void>(resolve => stub.listen(0, '127.0.0.1', resolve))
+  stubUrl = 'http://127.0.0.1:' + (stub.address() as AddressInfo).port
+})
+
+afterAll(() => new Promise(resolve => stub.close(resolve)))
+
+// sampleArgs gives every parameter of ep a placeholder value and adds a body
+// when ep takes one (fetch sends none with GET or HEAD).
+function sampleArgs(ep: EndpointModel): Methods.CallArgs {
+  const params: Record<string, any> = {}
+  for (const p of ep.Parameters || []) params[p.Name] = 'v-' + p.Name
+  const body = ep.RequestBody && ep.Method !== 'get' && ep.Method !== 'head' ? { sample: true } : undefined
+  return { endpointId: ep.ID, params, body }
+}
+
+const sm = loadServiceModel()
+const endpoints = sm.Endpoints || []
+const credentials = Object.values(Methods.CREDENTIALS)
+
+// setCredentials sets every credential, so each call may authenticate.
+function setCredentials(): void {
+  for (const c of credentials) process.env[c.envVar] = 'secret-' + c.envVar
+}
+setCredentials()
+
+describe.skipIf(endpoints.length === 0)('callEndpoint', () => {
+  it('substitutes parameters', async () => {
+    for (const ep of endpoints) {
+      received.length = 0
+      const args = sampleArgs(ep)
+      const res = await Methods.callEndpoint(sm, args, stubUrl + '/base')
+      expect(res.status, ep.ID).toBe(418)
+      expect(received, ep.ID).toHaveLength(1)
+      const got = received[0]
+      const url = new URL(got.url, stubUrl)
+      expect(got.method, ep.ID).toBe(ep.Method.toUpperCase())
+      expect(url.pathname.startsWith('/base'), ep.ID).toBe(true)
+      for (const p of ep.Parameters || []) {
+        const want = 'v-' + p.Name
+        if (p.In === 'path') {
+          expect(url.pathname, ep.ID).not.toContain('{' + p.Name + '}')
+          expect(url.pathname, ep.ID).toContain(encodeURIComponent(want))
+        } else if (p.In === 'query') {
+          expect(url.searchParams.get(p.Name), ep.ID).toBe(want)
+        } else if (p.In === 'header' && p.Name.toLowerCase() !== 'host') {
+          expect(got.headers[p.Name.toLowerCase()], ep.ID).toBe(want)
+        }
+      }
+      if (args.body) expect(got.body, ep.ID).toContain('"sample":true')
+    }
+  })
+
+  it('validates before sending', async () => {
+    received.length = 0
+    await expect(Methods.callEndpoint(sm, { endpointId: 'get /__missing__' }, stubUrl)).rejects.toThrow('not found')
+    for (const ep of endpoints) {
+      for (const p of ep.Parameters || []) {
+        if (!p.Required && p.In !== 'path') continue
+        const args = sampleArgs(ep)
+        delete args.params![p.Name]
+        await expect(Methods.callEndpoint(sm, args, stubUrl), ep.ID).rejects.toThrow(p.Name)
+      }
+      const args = sampleArgs(ep)
+      args.params!.__unknown__ = 'x'
+      await expect(Methods.callEndpoint(sm, args, stubUrl), ep.ID).rejects.toThrow('__unknown__')
+      if (ep.RequestBody?.Required) {
+        await expect(Methods.callEndpoint(sm, { ...sampleArgs(ep), body: undefined }, stubUrl), ep.ID).rejects.toThrow('body')
+      }
+    }
+    await expect(Methods.callEndpoint(sm, sampleArgs(endpoints[0]), '/relative')).rejects.toThrow(BASE_URL_ENV)
+    expect(received).toHaveLength(0)
+  })
+
+  it('applies credentials', async () => {
+    for (const ep of endpoints) {
+      const alt = (ep.Security || []).find(a => a.length > 0 && a.every(scheme => scheme in Methods.CREDENTIALS))
+      if (!alt) continue
+      received.length = 0
+      await Methods.callEndpoint(sm, sampleArgs(ep), stubUrl)
+      const got = received[0]
+      const url = new URL(got.url, stubUrl)
+      for (const scheme of alt) {
+        const c = Methods.CREDENTIALS[scheme]
+        // A parameter of the same name, given explicitly, wins.
+        if ((ep.Parameters || []).some(p => p.In === c.in && p.Name.toLowerCase() === c.name.toLowerCase())) continue
+        let want = 'secret-' + c.envVar
+        if (c.kind === 'bearer') want = 'Bearer ' + want
+        else if (c.kind === 'basic') want = 'Basic ' + Buffer.from(want).toString('base64')
+        if (c.in === 'query') expect(url.searchParams.get(c.name), ep.ID).toBe(want)
+        else if (c.in === 'cookie') expect(got.headers.cookie, ep.ID).toContain(c.name + '=' + encodeURIComponent(want))
+        else expect(got.headers[c.name.toLowerCase()], ep.ID).toBe(want)
+      }
+    }
+  })
+
+  it('fails without credentials', async () => {
+    for (const c of credentials) delete process.env[c.envVar]
+    try {
+      received.length = 0
+      for (const ep of endpoints) {
+        const security = ep.Security || []
+        if (security.length === 0 || security.some(a => a.length === 0)) continue
+        await expect(Methods.callEndpoint(sm, sampleArgs(ep), stubUrl), ep.ID).rejects.toThrow('missing credentials')
+      }
+      expect(received).toHaveLength(0)
+    } finally {
+      setCredentials()
+    }
+  })
+
+  it('truncates large bodies', async () => {
+    const ep = endpoints.find(e => e.Method !== 'head')
+    if (!ep) return
+    const res = await Methods.callEndpoint(sm, sampleArgs(ep), stubUrl + '/big')
+    expect(res.truncated).toBe(true)
+    expect(res.body.length).toBe(64 * 1024)
+    expect(Methods.formatCallResult(res)).toContain('truncated')
+  })
+
+  it('times out and retries', async () => {
+    process.env[Methods.CALL_TIMEOUT_ENV] = '50ms'
+    process.env[Methods.CALL_RETRIES_ENV] = '1'
+    try {
+      const start = Date.now()
+      await expect(Methods.callEndpoint(sm, sampleArgs(endpoints[0]), stubUrl + '/slow')).rejects.toThrow('timed out')
+      expect(Date.now() - start).toBeLessThan(2_000)
+      // The stub may see the aborted retry after the call has failed.
+      for (let i = 0; i < 50 && slowAttempts < 2; i++) await new Promise(resolve => setTimeout(resolve, 10))
+      expect(slowAttempts).toBe(2)
+    } finally {
+      delete process.env[Methods.CALL_TIMEOUT_ENV]
+      delete process.env[Methods.CALL_RETRIES_ENV]
+    }
+  })
+
+  it('retries 5xx responses with API_RETRIES', async () => {
+    const args = sampleArgs(endpoints[0])
+    expect((await Methods.callEndpoint(sm, args, stubUrl + '/flaky')).status).toBe(503)
+    expect(flakyAttempts).toBe(1)
+    process.env[Methods.CALL_RETRIES_ENV] = '2'
+    try {
+      expect((await Methods.callEndpoint(sm, args, stubUrl + '/flaky')).status).toBe(418)
+      expect(flakyAttempts).toBe(3)
+    } finally {
+      delete process.env[Methods.CALL_RETRIES_ENV]
+    }
+  })
 })
 `) + "\n"
 }
 
-func renderMethodsIndexTs() string {
-	return normalize(`export { listEndpoints, formatEndpointsOverview } from './listEndpoints.js'
+func renderMethodsIndexTs(data templateData) string {
+	src := `export { listEndpoints, formatEndpointsOverview } from './listEndpoints.js'
 export { searchEndpoints } from './searchEndpoints.js'
 export { getEndpointDetails } from './getEndpointDetails.js'
 export { listSchemas } from './listSchemas.js'
 export { getSchemaDetails } from './getSchemaDetails.js'
-`) + "\n"
+export { explainParameter, formatParameterExplanation } from './explainParameter.js'
+`
+	if data.invoke {
+		src += "export { callEndpoint, buildRequest, formatCallResult, CREDENTIALS, CALL_TIMEOUT_ENV, CALL_RETRIES_ENV, type CallArgs, type CallResult, type Credential } from './callEndpoint.js'\n"
+	}
+	return normalize(src) + "\n"
 }
 
 // sampleSpecYAML is a small sample used for testdata in the generated project.
@@ -990,20 +3022,34 @@ const sampleSpecYAML = "" +
 	"                  type: string\n"
 
 func renderMCPBManifest(data templateData) string {
-	author := map[string]string{"name": "Generated by swagger2mcp"}
+	author := map[string]string{"name": data.author}
+	if data.authorEmail != "" {
+		author["email"] = data.authorEmail
+	}
 	title := data.title()
 	manifest := map[string]any{
 		"manifest_version": "0.2",
-		"name":             data.PackageName,
-		"version":          "0.1.0",
+		"name":             data.BundleName,
+		"version":          data.version,
 		"description":      fmt.Sprintf("Generated MCP tool for %s", title),
 		"author":           author,
 		"server": map[string]any{
 			"type":        "node",
-			"entry_point": "dist/index.js",
+			"entry_point": data.distDir() + "/index.js",
 			"mcp_config": map[string]any{
 				"command": "/usr/bin/env",
-				"args":    []string{"node", "${__dirname}/dist/index.js"},
+				"args":    []string{"node", "${__dirname}/" + data.distDir() + "/index.js", "--transport", "${user_config.transport}"},
+			},
+		},
+		// The entry point also serves streamable HTTP; hosts that launch it
+		// over stdio keep the default.
+		"user_config": map[string]any{
+			"transport": map[string]any{
+				"type":        "string",
+				"title":       "Transport",
+				"description": "stdio (default) or http (streamable HTTP on MCP_HTTP_ADDR or PORT)",
+				"default":     "stdio",
+				"required":    false,
 			},
 		},
 		"tools": []map[string]any{
@@ -1012,9 +3058,17 @@ func renderMCPBManifest(data templateData) string {
 			{"name": "getEndpointDetails", "description": "Get endpoint details"},
 			{"name": "listSchemas", "description": "List schemas"},
 			{"name": "getSchemaDetails", "description": "Get schema details"},
+			{"name": "explainParameter", "description": "Explain one endpoint parameter"},
 		},
 		"tools_generated": false,
 	}
+	if data.invoke {
+		tools := manifest["tools"].([]map[string]any)
+		manifest["tools"] = append(tools, map[string]any{"name": "callEndpoint", "description": "Call an API endpoint and return its response"})
+	}
+	if data.license != "" {
+		manifest["license"] = data.license
+	}
 	b, _ := json.MarshalIndent(manifest, "", "  ")
 	return string(b) + "\n"
 }
@@ -1065,7 +3119,14 @@ func renderESLintRC() string {
 	return string(b) + "\n"
 }
 
-func renderMCPBIgnore() string {
+func renderMCPBIgnore(data templateData) string {
+	jestConfig, docker := "", ""
+	if data.testRunner == TestRunnerJest {
+		jestConfig = "jest.config.js\n"
+	}
+	if data.docker {
+		docker = "Dockerfile\n.dockerignore\ndocker-compose.yml\n"
+	}
 	return normalize(`# Exclude development and source files from the bundle
 node_modules/
 src/
@@ -1083,19 +3144,44 @@ pnpm-lock.yaml
 *.ts
 .eslintrc.json
 .prettierrc.json
-.editorconfig
+`+jestConfig+`.editorconfig
 Makefile
-README.md
+`+docker+`README.md
 `) + "\n"
 }
 
-func renderMakefileNpm() string {
-	return normalize(`# Simple Makefile for npm/TypeScript MCP tool
-
-.PHONY: help install build test format lint bundle
+func renderMakefileNpm(data templateData) string {
+	targets, test, lint := "install build", "\ntest:\n\tnpm test\n", "\nlint:\n\tnpm run lint\n"
+	if data.skip.Tests {
+		test = ""
+	} else {
+		targets += " test"
+	}
+	targets += " format"
+	if data.skip.Lint {
+		lint = ""
+	} else {
+		targets += " lint"
+	}
+	targets += " bundle"
+	image, docker := "", ""
+	if data.docker {
+		targets += " docker-build docker-run"
+		image = "\nIMAGE ?= " + data.ToolName + "\n"
+		docker = `
+docker-build:
+	docker build -t $(IMAGE) .
+
+docker-run:
+	docker run -i --rm $(IMAGE)
+`
+	}
+	return normalize(strings.NewReplacer("{{TARGETS}}", targets, "{{TEST}}", test, "{{LINT}}", lint, "{{IMAGE}}", image, "{{DOCKER}}", docker).Replace(`# Simple Makefile for npm/TypeScript MCP tool
+{{IMAGE}}
+.PHONY: help {{TARGETS}}
 
 help:
-	@echo "Targets: install build test format lint bundle"
+	@echo "Targets: {{TARGETS}}"
 
 install:
 	npm install
@@ -1106,14 +3192,120 @@ build:
 bundle:
 	# Requires 'mcpb' CLI globally: npm i -g @anthropic-ai/mcpb
 	npm run bundle
-
-test:
-	npm test
-
+{{TEST}}
 format:
 	npm run format
+{{LINT}}{{DOCKER}}`)) + "\n"
+}
 
-lint:
-	npm run lint
-`) + "\n"
+// ciScripts are the package.json scripts the CI workflow runs, in order.
+func ciScripts(data templateData) []string {
+	scripts := []string{"`npm run build`"}
+	if !data.skip.Lint {
+		scripts = append(scripts, "`npm run lint`")
+	}
+	if !data.skip.Tests {
+		scripts = append(scripts, "`npm test`")
+	}
+	return scripts
+}
+
+// renderCIWorkflow returns the GitHub Actions workflow of GenerateCI: one
+// job on the current Node.js LTS that installs the dependencies, builds
+// (which type-checks) and, unless their files are skipped, lints and tests.
+// Like the Dockerfile it falls back to npm install without a lock file.
+func renderCIWorkflow(data templateData) string {
+	steps := `      - name: Build
+        run: npm run build
+`
+	if !data.skip.Lint {
+		steps += `      - name: Lint (eslint)
+        run: npm run lint
+`
+	}
+	if !data.skip.Tests {
+		steps += `      - name: Test (` + data.testRunner + `)
+        run: npm test
+`
+	}
+	return normalize(strings.NewReplacer("{{TOOL_NAME}}", data.ToolName, "{{STEPS}}", steps).Replace(`# CI for the {{TOOL_NAME}} npm MCP tool, generated by swagger2mcp.
+name: CI
+
+on:
+  push:
+  pull_request:
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-node@v4
+        with:
+          node-version: lts/*
+      - name: Install dependencies
+        # npm ci needs a lock file; commit the one npm install writes.
+        run: if [ -f package-lock.json ]; then npm ci; else npm install; fi
+{{STEPS}}`))
+}
+
+// renderDockerfile returns a multi-stage Dockerfile: npm ci and tsc in a
+// node:lts build stage, then the compiled server and its production
+// dependencies in a slim image whose entrypoint serves MCP over stdio.
+func renderDockerfile(data templateData) string {
+	return normalize(strings.NewReplacer("{{TOOL_NAME}}", data.ToolName, "{{DIST}}", data.distDir()).Replace(`# Dockerfile for the {{TOOL_NAME}} npm MCP tool
+FROM node:lts AS build
+WORKDIR /app
+COPY package.json package-lock.json* ./
+# npm ci needs a lock file; commit the one npm install writes.
+RUN if [ -f package-lock.json ]; then npm ci; else npm install; fi
+COPY tsconfig.json ./
+COPY src ./src
+RUN npm run build && npm prune --omit=dev
+
+FROM node:lts-slim
+WORKDIR /app
+ENV NODE_ENV=production
+COPY --from=build /app/package.json ./
+COPY --from=build /app/node_modules ./node_modules
+COPY --from=build /app/{{DIST}} ./{{DIST}}
+USER node
+# The server speaks MCP over stdio; keep stdin open: docker run -i --rm <image>
+ENTRYPOINT ["node", "{{DIST}}/index.js"]
+`))
+}
+
+// renderDockerignore keeps dependencies, build output and tests out of the
+// build context.
+func renderDockerignore() string {
+	return normalize(`.git
+.vscode
+node_modules
+dist
+server
+*.mcpb
+__tests__
+testdata
+Dockerfile
+.dockerignore
+docker-compose.yml
+*.md
+`)
+}
+
+// renderDockerCompose runs the image as a service on the HTTP transport,
+// listening on PORT 3000 of all interfaces inside the container.
+func renderDockerCompose(data templateData) string {
+	return normalize(strings.ReplaceAll(`# docker compose up builds the {{TOOL_NAME}} image and serves MCP over
+# streamable HTTP at http://localhost:3000/mcp.
+services:
+  server:
+    build: .
+    image: {{TOOL_NAME}}
+    command: ["--transport", "http"]
+    environment:
+      PORT: "3000"
+    ports:
+      - "3000:3000"
+`, "{{TOOL_NAME}}", data.ToolName))
 }
@@ -0,0 +1,282 @@
+package npmemitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// zodGenerator renders ServiceModel.Schemas as Zod schemas, one exported
+// <Name>Schema constant per entry. References become z.lazy() calls so the
+// constants can appear in any order and refer to each other.
+type zodGenerator struct {
+	schemas   map[string]genspec.Schema
+	idents    map[string]string // schema name -> exported constant
+	recursive map[string]bool   // schemas that reach themselves through refs
+}
+
+// renderZodSchemasTs returns src/spec/schemas.ts for sm.
+func renderZodSchemasTs(sm *genspec.ServiceModel) string {
+	g := newZodGenerator(sm)
+	names := make([]string, 0, len(g.schemas))
+	for name := range g.schemas {
+		names = append(names, name)
+	}
+	genspec.SortNames(names)
+
+	var b strings.Builder
+	b.WriteString("// Zod schemas for the API's named schemas, generated by swagger2mcp.\n")
+	b.WriteString("import { z } from 'zod'\n")
+	for _, name := range names {
+		s := g.schemas[name]
+		b.WriteString("\n")
+		if s.Description != "" {
+			b.WriteString(tsComment(s.Description))
+		}
+		// A schema that refers back to itself needs an explicit type, or
+		// TypeScript cannot infer one for its initializer.
+		annotation := ""
+		if g.recursive[name] {
+			annotation = ": z.ZodTypeAny"
+		}
+		s.Description = ""
+		fmt.Fprintf(&b, "export const %s%s = %s\n", g.idents[name], annotation, g.schema(&s, ""))
+	}
+	return b.String()
+}
+
+func newZodGenerator(sm *genspec.ServiceModel) *zodGenerator {
+	g := &zodGenerator{schemas: map[string]genspec.Schema{}, idents: map[string]string{}, recursive: map[string]bool{}}
+	if sm != nil && sm.Schemas != nil {
+		g.schemas = sm.Schemas
+	}
+	names := make([]string, 0, len(g.schemas))
+	for name := range g.schemas {
+		names = append(names, name)
+	}
+	genspec.SortNames(names)
+	taken := map[string]bool{}
+	for _, name := range names {
+		ident := tsIdentifier(name) + "Schema"
+		for i := 2; taken[ident]; i++ {
+			ident = fmt.Sprintf("%sSchema%d", tsIdentifier(name), i)
+		}
+		taken[ident] = true
+		g.idents[name] = ident
+	}
+	for _, name := range names {
+		s := g.schemas[name]
+		g.recursive[name] = g.reaches(&s, name, map[string]bool{})
+	}
+	return g
+}
+
+// reaches reports whether s refers to target, directly or through other
+// named schemas.
+func (g *zodGenerator) reaches(s *genspec.Schema, target string, seen map[string]bool) bool {
+	if s == nil {
+		return false
+	}
+	children := []*genspec.SchemaOrRef{s.Items}
+	for _, p := range s.Properties {
+		children = append(children, p)
+	}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, c := range children {
+		if c == nil {
+			continue
+		}
+		if c.Ref != nil {
+			name := refName(c.Ref.Ref)
+			if name == target {
+				return true
+			}
+			if ref, ok := g.schemas[name]; ok && !seen[name] {
+				seen[name] = true
+				if g.reaches(&ref, target, seen) {
+					return true
+				}
+			}
+			continue
+		}
+		if g.reaches(c.Schema, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaOrRef and schema return the Zod expression for a schema; indent is
+// the indentation of the line the expression starts on, used to lay out
+// object properties one per line.
+func (g *zodGenerator) schemaOrRef(sr *genspec.SchemaOrRef, indent string) string {
+	if sr == nil {
+		return "z.unknown()"
+	}
+	if sr.Ref != nil {
+		if ident, ok := g.idents[refName(sr.Ref.Ref)]; ok {
+			return "z.lazy(() => " + ident + ")"
+		}
+		return "z.unknown()"
+	}
+	return g.schema(sr.Schema, indent)
+}
+
+func (g *zodGenerator) schema(s *genspec.Schema, indent string) string {
+	if s == nil || s.Excluded {
+		return "z.unknown()"
+	}
+	var expr string
+	switch {
+	case len(s.AllOf) > 0:
+		expr = g.schemaOrRef(s.AllOf[0], indent)
+		for _, part := range s.AllOf[1:] {
+			expr += ".and(" + g.schemaOrRef(part, indent) + ")"
+		}
+	case len(s.OneOf) > 0:
+		expr = g.union(s.OneOf, indent)
+	case len(s.AnyOf) > 0:
+		expr = g.union(s.AnyOf, indent)
+	case len(s.Enum) > 0:
+		expr = zodEnum(s.Enum)
+	case s.Type == "string":
+		expr = "z.string()"
+	case s.Type == "integer", s.Type == "number":
+		expr = "z.number()"
+	case s.Type == "boolean":
+		expr = "z.boolean()"
+	case s.Type == "array":
+		expr = "z.array(" + g.schemaOrRef(s.Items, indent) + ")"
+	case s.Type == "object" || len(s.Properties) > 0:
+		expr = g.object(s, indent)
+	default:
+		expr = "z.unknown()"
+	}
+	if s.Description != "" {
+		expr += ".describe(" + tsString(s.Description) + ")"
+	}
+	return expr
+}
+
+func (g *zodGenerator) union(variants []*genspec.SchemaOrRef, indent string) string {
+	if len(variants) == 1 {
+		return g.schemaOrRef(variants[0], indent)
+	}
+	parts := make([]string, len(variants))
+	for i, v := range variants {
+		parts[i] = g.schemaOrRef(v, indent)
+	}
+	return "z.union([" + strings.Join(parts, ", ") + "])"
+}
+
+func (g *zodGenerator) object(s *genspec.Schema, indent string) string {
+	if len(s.Properties) == 0 {
+		return "z.record(z.unknown())"
+	}
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	genspec.SortNames(keys)
+	inner := indent + "  "
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for _, k := range keys {
+		field := g.schemaOrRef(s.Properties[k], inner)
+		if !required[k] {
+			field += ".optional()"
+		}
+		b.WriteString(inner + tsPropertyKey(k) + ": " + field + ",\n")
+	}
+	b.WriteString(indent + "})")
+	return b.String()
+}
+
+// zodEnum maps string enums to z.enum and any other values to a union of
+// literals.
+func zodEnum(values []any) string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			break
+		}
+		strs = append(strs, tsString(s))
+	}
+	if len(strs) == len(values) {
+		return "z.enum([" + strings.Join(strs, ", ") + "])"
+	}
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == nil {
+			literals = append(literals, "z.null()")
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		literals = append(literals, "z.literal("+string(raw)+")")
+	}
+	if len(literals) == 1 {
+		return literals[0]
+	}
+	return "z.union([" + strings.Join(literals, ", ") + "])"
+}
+
+// refName returns the schema name a $ref points at, e.g. Pet for
+// #/components/schemas/Pet.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// tsIdentifier turns a schema name into a valid TypeScript identifier.
+func tsIdentifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '$' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	out := b.String()
+	if out == "" || (out[0] >= '0' && out[0] <= '9') {
+		out = "_" + out
+	}
+	return out
+}
+
+// tsPropertyKey quotes a property name unless it is a plain identifier.
+func tsPropertyKey(name string) string {
+	if name != "" && tsIdentifier(name) == name {
+		return name
+	}
+	return tsString(name)
+}
+
+// tsString returns s as a single-quoted TypeScript string literal.
+func tsString(s string) string {
+	raw, _ := json.Marshal(s)
+	inner := string(raw[1 : len(raw)-1])
+	inner = strings.ReplaceAll(inner, `\"`, `"`)
+	inner = strings.ReplaceAll(inner, "'", `\'`)
+	return "'" + inner + "'"
+}
+
+// tsComment renders text as a // comment block.
+func tsComment(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight("// "+strings.TrimSpace(line), " ") + "\n")
+	}
+	return b.String()
+}
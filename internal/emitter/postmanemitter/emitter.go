@@ -0,0 +1,282 @@
+package postmanemitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// CollectionSchemaURL identifies the Postman Collection format version.
+const CollectionSchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// CollectionFile is the only file written by the emitter.
+const CollectionFile = "collection.json"
+
+// Options controls how the Postman emitter renders a collection.
+type Options struct {
+	OutDir            string // required; target directory to write collection.json
+	ToolName          string // collection name fallback when the spec has no title
+	Force             bool   // overwrite existing files
+	OverwriteModified bool   // with Force, also replace files edited since the last run and files it did not generate
+	Prune             bool   // delete files the last run generated that are no longer produced
+	Concurrency       int    // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun            bool   // don't write, only plan
+	Verbose           bool
+}
+
+// PlannedFile describes a file the emitter intends to write.
+type PlannedFile struct {
+	RelPath string
+	Size    int
+	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
+}
+
+// Result returns the planned files and final resolved names.
+type Result struct {
+	ToolName string
+	Planned  []PlannedFile
+	Skipped  bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome  manifest.Outcome // files kept or pruned because of the last run's manifest
+}
+
+// Collection is the subset of the Postman Collection v2.1 format we emit.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type Item struct {
+	Name    string  `json:"name"`
+	Request Request `json:"request"`
+}
+
+type Request struct {
+	Method      string   `json:"method"`
+	Header      []Header `json:"header"`
+	Body        *Body    `json:"body,omitempty"`
+	URL         URL      `json:"url"`
+	Description string   `json:"description,omitempty"`
+}
+
+type Header struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Body struct {
+	Mode    string       `json:"mode"`
+	Raw     string       `json:"raw"`
+	Options *BodyOptions `json:"options,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type URL struct {
+	Raw      string       `json:"raw"`
+	Protocol string       `json:"protocol,omitempty"`
+	Host     []string     `json:"host,omitempty"`
+	Port     string       `json:"port,omitempty"`
+	Path     []string     `json:"path,omitempty"`
+	Query    []QueryParam `json:"query,omitempty"`
+}
+
+type QueryParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Emit renders a Postman Collection v2.1 file from the provided ServiceModel (IM).
+func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result, error) {
+	_ = ctx
+	if sm == nil {
+		return nil, fmt.Errorf("postmanemitter: nil ServiceModel")
+	}
+	if strings.TrimSpace(opts.OutDir) == "" {
+		return nil, fmt.Errorf("postmanemitter: OutDir is required")
+	}
+	toolName := strings.TrimSpace(opts.ToolName)
+	if toolName == "" {
+		toolName = "mcp-tool"
+	}
+
+	data, err := json.MarshalIndent(BuildCollection(sm, toolName), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal collection: %w", err)
+	}
+	files := map[string][]byte{CollectionFile: append(data, '\n')}
+
+	planned := []PlannedFile{{
+		RelPath: CollectionFile,
+		Size:    len(files[CollectionFile]),
+		Mode:    0o644,
+		SHA256:  manifest.HashBytes(files[CollectionFile]),
+		Change:  filewriter.Classify(opts.OutDir, CollectionFile, files[CollectionFile], 0o644),
+	}}
+	res := &Result{ToolName: toolName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "postman", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		w := &filewriter.Writer{Prefix: "postmanemitter", Concurrency: opts.Concurrency, Force: opts.Force}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	}
+	return res, nil
+}
+
+// BuildCollection converts sm into a collection with one item per endpoint.
+// name is used when the spec has no title.
+func BuildCollection(sm *genspec.ServiceModel, name string) *Collection {
+	col := &Collection{
+		Info: Info{
+			Name:        strings.TrimSpace(sm.Title),
+			Description: sm.Description,
+			Schema:      CollectionSchemaURL,
+		},
+		Item: make([]Item, 0, len(sm.Endpoints)),
+	}
+	if col.Info.Name == "" {
+		col.Info.Name = name
+	}
+
+	base := ""
+	if len(sm.Servers) > 0 {
+		base = strings.TrimRight(strings.TrimSpace(sm.Servers[0].URL), "/")
+	}
+	if base == "" {
+		base = "{{baseUrl}}"
+		col.Variable = append(col.Variable, Variable{Key: "baseUrl", Value: ""})
+	}
+
+	seenVars := map[string]bool{}
+	var pathVars []string
+	for i := range sm.Endpoints {
+		ep := &sm.Endpoints[i]
+		col.Item = append(col.Item, buildItem(sm, ep, base))
+		for _, p := range ep.Parameters {
+			if p.In == "path" && !seenVars[p.Name] {
+				seenVars[p.Name] = true
+				pathVars = append(pathVars, p.Name)
+			}
+		}
+	}
+	// Path parameters reference collection variables so they can be set once.
+	genspec.SortNames(pathVars)
+	for _, name := range pathVars {
+		col.Variable = append(col.Variable, Variable{Key: name, Value: ""})
+	}
+	return col
+}
+
+func buildItem(sm *genspec.ServiceModel, ep *genspec.EndpointModel, base string) Item {
+	name := strings.TrimSpace(ep.Summary)
+	if name == "" {
+		name = strings.ToUpper(string(ep.Method)) + " " + ep.Path
+	}
+
+	var desc []string
+	for _, s := range []string{ep.Summary, ep.Description} {
+		if s = strings.TrimSpace(s); s != "" && (len(desc) == 0 || desc[0] != s) {
+			desc = append(desc, s)
+		}
+	}
+
+	req := Request{
+		Method:      strings.ToUpper(string(ep.Method)),
+		Header:      []Header{},
+		Description: strings.Join(desc, "\n\n"),
+	}
+	var query []QueryParam
+	for _, p := range ep.Parameters {
+		switch p.In {
+		case "query":
+			query = append(query, QueryParam{Key: p.Name, Value: "", Disabled: !p.Required})
+		case "header":
+			req.Header = append(req.Header, Header{Key: p.Name, Value: "", Disabled: !p.Required})
+		}
+	}
+	if body := buildBody(sm, ep.RequestBody); body != nil {
+		req.Header = append(req.Header, Header{Key: "Content-Type", Value: "application/json"})
+		req.Body = body
+	}
+	req.URL = buildURL(base, ep.Path, query)
+	return Item{Name: name, Request: req}
+}
+
+// buildURL joins base and path, rewriting {param} segments as {{param}}.
+func buildURL(base, path string, query []QueryParam) URL {
+	rewritten := strings.NewReplacer("{", "{{", "}", "}}").Replace(path)
+	u := URL{Raw: base + rewritten, Query: query}
+	sep := "?"
+	for _, q := range query {
+		if !q.Disabled {
+			u.Raw += sep + q.Key + "="
+			sep = "&"
+		}
+	}
+
+	var basePath string
+	if parsed, err := url.Parse(base); err == nil && parsed.Host != "" {
+		u.Protocol = parsed.Scheme
+		u.Host = strings.Split(parsed.Hostname(), ".")
+		u.Port = parsed.Port()
+		basePath = parsed.Path
+	} else {
+		u.Host = []string{base}
+	}
+	for _, seg := range strings.Split(basePath+rewritten, "/") {
+		if seg != "" {
+			u.Path = append(u.Path, seg)
+		}
+	}
+	return u
+}
+
+// buildBody returns a raw JSON body for the first JSON media type of rb,
+// using its example or one synthesized from the schema.
+func buildBody(sm *genspec.ServiceModel, rb *genspec.RequestBodyModel) *Body {
+	example, ok := sm.JSONRequestExample(rb)
+	if !ok {
+		return nil
+	}
+	raw, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		raw = []byte("{}")
+	}
+	body := &Body{Mode: "raw", Raw: string(raw), Options: &BodyOptions{}}
+	body.Options.Raw.Language = "json"
+	return body
+}
@@ -0,0 +1,153 @@
+package postmanemitter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func sampleModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Title:       "Pet Store",
+		Version:     "1.0.0",
+		Description: "Sample pets API",
+		Servers:     []genspec.Server{{URL: "https://api.example.com/v1/"}},
+		Endpoints: []genspec.EndpointModel{
+			{
+				ID:      "get /pets/{petId}",
+				Method:  genspec.GET,
+				Path:    "/pets/{petId}",
+				Summary: "Get pet",
+				Parameters: []genspec.ParameterModel{
+					{Name: "petId", In: "path", Required: true},
+					{Name: "verbose", In: "query"},
+					{Name: "limit", In: "query", Required: true},
+				},
+			},
+			{
+				ID:          "post /pets",
+				Method:      genspec.POST,
+				Path:        "/pets",
+				Description: "Creates a pet",
+				RequestBody: &genspec.RequestBodyModel{
+					Required: true,
+					Content: []genspec.Media{{
+						Mime:   "application/json",
+						Schema: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Pet"}},
+					}},
+				},
+			},
+		},
+		Schemas: map[string]genspec.Schema{
+			"Pet": {
+				Name: "Pet",
+				Type: "object",
+				Properties: map[string]*genspec.SchemaOrRef{
+					"name": {Schema: &genspec.Schema{Type: "string", Example: "rex"}},
+					"age":  {Schema: &genspec.Schema{Type: "integer"}},
+				},
+			},
+		},
+	}
+}
+
+func TestEmit_WritesValidCollection(t *testing.T) {
+	dir := t.TempDir()
+	sm := sampleModel()
+	res, err := Emit(context.Background(), sm, Options{OutDir: dir, ToolName: "pets"})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	if len(res.Planned) != 1 || res.Planned[0].RelPath != CollectionFile {
+		t.Fatalf("unexpected plan: %+v", res.Planned)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, CollectionFile))
+	if err != nil {
+		t.Fatalf("read collection: %v", err)
+	}
+	var col Collection
+	if err := json.Unmarshal(raw, &col); err != nil {
+		t.Fatalf("collection is not valid JSON: %v", err)
+	}
+	if col.Info.Schema != CollectionSchemaURL || col.Info.Name != "Pet Store" {
+		t.Fatalf("unexpected info: %+v", col.Info)
+	}
+	if len(col.Item) != len(sm.Endpoints) {
+		t.Fatalf("want %d items, got %d", len(sm.Endpoints), len(col.Item))
+	}
+
+	get := col.Item[0].Request
+	if get.Method != "GET" || get.URL.Raw != "https://api.example.com/v1/pets/{{petId}}?limit=" {
+		t.Fatalf("unexpected GET request: %+v", get)
+	}
+	if strings.Join(get.URL.Host, ".") != "api.example.com" || strings.Join(get.URL.Path, "/") != "v1/pets/{{petId}}" {
+		t.Fatalf("unexpected URL parts: %+v", get.URL)
+	}
+	if len(get.URL.Query) != 2 || !get.URL.Query[0].Disabled || get.URL.Query[1].Disabled {
+		t.Fatalf("unexpected query items: %+v", get.URL.Query)
+	}
+	if len(col.Variable) != 1 || col.Variable[0].Key != "petId" {
+		t.Fatalf("expected petId collection variable, got %+v", col.Variable)
+	}
+
+	post := col.Item[1]
+	if post.Name != "POST /pets" || post.Request.Description != "Creates a pet" {
+		t.Fatalf("unexpected POST item: %+v", post)
+	}
+	if post.Request.Body == nil || post.Request.Body.Mode != "raw" {
+		t.Fatalf("expected raw JSON body, got %+v", post.Request.Body)
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(post.Request.Body.Raw), &body); err != nil {
+		t.Fatalf("body is not valid JSON: %v", err)
+	}
+	if body["name"] != "rex" || body["age"] != float64(0) {
+		t.Fatalf("unexpected body example: %v", body)
+	}
+}
+
+func TestEmit_NoServersUsesBaseURLVariable(t *testing.T) {
+	sm := &genspec.ServiceModel{
+		Endpoints: []genspec.EndpointModel{{ID: "get /health", Method: genspec.GET, Path: "/health"}},
+	}
+	col := BuildCollection(sm, "fallback")
+	if col.Info.Name != "fallback" {
+		t.Fatalf("expected fallback name, got %q", col.Info.Name)
+	}
+	if got := col.Item[0].Request.URL.Raw; got != "{{baseUrl}}/health" {
+		t.Fatalf("unexpected raw URL %q", got)
+	}
+	if len(col.Variable) != 1 || col.Variable[0].Key != "baseUrl" {
+		t.Fatalf("expected baseUrl variable, got %+v", col.Variable)
+	}
+}
+
+func TestEmit_DryRunAndErrors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: dir, DryRun: true}); err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("dry-run should not create output, stat err=%v", err)
+	}
+	if _, err := Emit(context.Background(), nil, Options{OutDir: dir}); err == nil {
+		t.Fatalf("expected error for nil model")
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{}); err == nil {
+		t.Fatalf("expected error for empty OutDir")
+	}
+
+	existing := t.TempDir()
+	if err := os.WriteFile(filepath.Join(existing, "keep.txt"), nil, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Emit(context.Background(), sampleModel(), Options{OutDir: existing}); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("expected not-empty error, got %v", err)
+	}
+}
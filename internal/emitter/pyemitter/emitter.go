@@ -6,20 +6,106 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/docs"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/fileexclude"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/filewriter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/license"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/modelshard"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/tmploverride"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
+// Build tools a generated project can be packaged and locked with.
+const (
+	BuildToolSetuptools = "setuptools" // setup.py, requirements*.txt and a [project] pyproject.toml
+	BuildToolUV         = "uv"         // a [project] pyproject.toml with [tool.uv] and a uv.lock placeholder
+	BuildToolPoetry     = "poetry"     // a [tool.poetry] pyproject.toml and a poetry.lock placeholder
+)
+
+// CI providers a generated project can get a workflow for.
+const (
+	CIProviderGitHub = "github" // .github/workflows/ci.yml
+	CIProviderGitLab = "gitlab" // .gitlab-ci.yml
+)
+
+// DefaultPythonVersion is the target Python version used when neither
+// Options.PythonVersion nor Options.PythonRequires is set, and
+// DefaultPythonRequires the matching requires-python. The generated code
+// itself runs on 3.8 or newer.
+const (
+	DefaultPythonVersion  = "3.9"
+	DefaultPythonRequires = ">=" + DefaultPythonVersion
+)
+
+var (
+	pythonClauseRe  = regexp.MustCompile(`^(>=|~=|==|<=|!=|<|>)\s*([0-9]+(?:\.[0-9]+){0,2}(?:\.\*)?)$`)
+	versionClauseRe = regexp.MustCompile(`^(>=|~=|==|<=|!=|<|>)\s*([0-9]+(?:\.[0-9]+)*(?:\.\*|(?:a|b|rc)[0-9]+)?)$`)
+	bareVersionRe   = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+)*(?:(?:a|b|rc)[0-9]+)?$`)
+	pythonVersionRe = regexp.MustCompile(`^3\.(0|[1-9][0-9]*)$`)
+)
+
 // Options controls how the Python emitter renders a project.
 type Options struct {
-	OutDir      string // required; target directory to write the project
-	ToolName    string // tool binary name; used for project and package naming
-	PackageName string // Python package name; defaults to normalized ToolName when empty
-	Force       bool   // overwrite existing files
-	DryRun      bool   // don't write, only plan
-	Verbose     bool
+	OutDir            string // required; target directory to write the project
+	ToolName          string // tool binary name; used for project and package naming
+	PackageName       string // Python package name; defaults to normalized ToolName when empty
+	License           string // SPDX identifier (MIT, Apache-2.0, ...); when set, writes LICENSE and declares it in the packaging metadata
+	Author            string // package author in setup.py/pyproject.toml and the LICENSE copyright holder; defaults to "Generated by swagger2mcp"
+	AuthorEmail       string // author's e-mail address in the packaging metadata
+	ProjectVersion    string // semantic version of the package and its __version__; defaults to the spec's version when that is one, else 0.1.0
+	Library           bool   // emit only the spec subpackage (model, loader, model.json) with packaging; no server, methods or tests
+	BuildTool         string // BuildToolSetuptools (default when empty), BuildToolUV or BuildToolPoetry
+	PythonVersion     string // target Python version (3.x) of mypy, black, ruff, pyupgrade and vermin; also the requires-python lower bound when PythonRequires is empty; defaults to DefaultPythonVersion
+	PythonRequires    string // requires-python specifier, e.g. ">=3.10" (a bare 3.10 means >=3.10); defaults to ">=" + PythonVersion
+	UseRuff           bool   // lint with ruff (ruff.toml) instead of pylint (.pylintrc)
+	GenerateCI        bool   // emit a CI configuration running lint, type-check and test jobs; server layout only
+	GenerateFastAPI   bool   // add a FastAPI router with one route per endpoint and an api_server.py entry point; server layout only
+	EnableInvoke      bool   // add the callEndpoint tool (mcp/methods/call_endpoint.py), which sends requests to the upstream API; server layout only
+	EmitDocs          bool   // add docs/API.md, the Markdown reference of the endpoints and schemas that all emitters render alike (see package docs)
+	EmitDockerfile    bool   // emit a multi-stage Dockerfile, a .dockerignore and Makefile docker-build/docker-run targets; server layout only
+	WithOTel          bool   // trace each tool call with opentelemetry-api, declared as the optional "otel" extra; server layout only
+	ShardModelByTag   bool   // ship the model as an index and per-tag shards under spec/model/, read lazily by load_shard and load_tag, instead of one model.json
+	CIProvider        string // CIProviderGitHub (default when empty) or CIProviderGitLab
+	PinDependencies   bool   // declare every dependency at an exact version (==x.y.z) instead of its default specifier
+	MCPSDKVersion     string // when set, adds an mcp dependency: a bare 1.9.4 pins ==1.9.4, a specifier such as ">=1.9,<2" is used as is
+	Force             bool   // overwrite existing files
+	OverwriteModified bool   // with Force, also replace files edited since the last run and files it did not generate
+	Prune             bool   // delete files the last run generated that are no longer produced
+	Concurrency       int    // parallel file writers; <= 0 uses runtime.NumCPU()
+	DryRun            bool   // don't write, only plan
+	Verbose           bool
+
+	// TemplateOverrideDir holds files that replace the generated file at the
+	// same relative path (e.g. README.md, src/<package>/server.py). They are
+	// rendered as text/template with an emitter.TemplateContext; files
+	// without an override keep the built-in.
+	// Under python/ it holds named overrides instead: python/<name>.tmpl, where
+	// <name> is one of TemplateNames, replaces that file after the same-path
+	// overrides, and any other file under python/ is an error listing the valid
+	// names. Other languages' directories are ignored.
+	TemplateOverrideDir string
+	// Provenance records how the model was built; template overrides see it
+	// as .Provenance.
+	Provenance *manifest.Provenance
+	// ExcludeFiles are globs (path.Match syntax, against the slash-separated
+	// relative path, or the file name when a pattern has no "/") of generated
+	// files to leave out, e.g. .pylintrc or mypy.ini. Files the project needs to
+	// build cannot be excluded.
+	ExcludeFiles []string
+	// Skip names categories of generated files to leave out (see
+	// emitter.SkipCategories): tests drops tests/, lint the flake8, pylint,
+	// ruff and mypy configs and .pre-commit-config.yaml, makefile the
+	// Makefile, readme README.md and editor .editorconfig and .vscode/. The
+	// Makefile targets, CI jobs, packaging metadata and README sections of
+	// skipped files are left out too. The CI jobs run Makefile targets, so
+	// GenerateCI needs the Makefile and the lint or test jobs.
+	Skip []string
 }
 
 // PlannedFile describes a file the emitter intends to write.
@@ -27,6 +113,8 @@ type PlannedFile struct {
 	RelPath string
 	Size    int
 	Mode    os.FileMode
+	SHA256  string            // hex digest of the content
+	Change  filewriter.Change // relative to what is already in OutDir
 }
 
 // Result returns the planned files and final resolved names.
@@ -34,6 +122,8 @@ type Result struct {
 	ToolName    string
 	PackageName string
 	Planned     []PlannedFile
+	Skipped     bool             // the output on disk already matched what this run renders; nothing was written
+	Outcome     manifest.Outcome // files kept or pruned because of the last run's manifest
 }
 
 // Emit renders a Python MCP tool project using the provided ServiceModel.
@@ -45,6 +135,55 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 	if strings.TrimSpace(opts.OutDir) == "" {
 		return nil, fmt.Errorf("pyemitter: OutDir is required")
 	}
+	if v := strings.TrimSpace(opts.ProjectVersion); v != "" && !genspec.IsSemver(v) {
+		return nil, fmt.Errorf("pyemitter: invalid ProjectVersion %q (expected a semantic version, e.g. 1.2.3)", opts.ProjectVersion)
+	}
+	licenseID := ""
+	if opts.License != "" {
+		id, err := license.Normalize(opts.License)
+		if err != nil {
+			return nil, fmt.Errorf("pyemitter: %w", err)
+		}
+		licenseID = id
+	}
+	buildTool := strings.TrimSpace(opts.BuildTool)
+	switch buildTool {
+	case "":
+		buildTool = BuildToolSetuptools
+	case BuildToolSetuptools, BuildToolUV, BuildToolPoetry:
+	default:
+		return nil, fmt.Errorf("pyemitter: unsupported BuildTool %q (allowed: %s, %s, %s)", opts.BuildTool, BuildToolSetuptools, BuildToolUV, BuildToolPoetry)
+	}
+
+	ciProvider := strings.TrimSpace(opts.CIProvider)
+	switch ciProvider {
+	case "":
+		ciProvider = CIProviderGitHub
+	case CIProviderGitHub, CIProviderGitLab:
+	default:
+		return nil, fmt.Errorf("pyemitter: unsupported CIProvider %q (allowed: %s, %s)", opts.CIProvider, CIProviderGitHub, CIProviderGitLab)
+	}
+
+	pythonVersion, pythonRequires, err := ResolvePythonVersion(opts.PythonVersion, opts.PythonRequires)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := emitter.ParseSkip(opts.Skip)
+	if err != nil {
+		return nil, fmt.Errorf("pyemitter: %w", err)
+	}
+	if opts.GenerateCI && !opts.Library {
+		if skip.Makefile {
+			return nil, fmt.Errorf("pyemitter: GenerateCI runs the Makefile targets; it cannot be combined with skipping %s", emitter.SkipMakefile)
+		}
+		if skip.Lint && skip.Tests {
+			return nil, fmt.Errorf("pyemitter: GenerateCI has no jobs left when skipping both %s and %s", emitter.SkipLint, emitter.SkipTests)
+		}
+	}
+	_, mcpSpec, err := ResolveVersions(pythonRequires, opts.MCPSDKVersion)
+	if err != nil {
+		return nil, err
+	}
 
 	toolName := sanitizeToolName(opts.ToolName)
 	if toolName == "" {
@@ -60,289 +199,540 @@ func Emit(ctx context.Context, sm *genspec.ServiceModel, opts Options) (*Result,
 		packageName = sanitizePackageName(toolName)
 	}
 
-	// Build file map
+	// Templates render from a copy with blank Title/Version/Description
+	// filled in; the spec hash below is still computed from sm.
+	model, notes := genspec.FillDefaults(sm, toolName)
+	if opts.Verbose {
+		for _, n := range notes {
+			fmt.Fprintf(os.Stderr, "[INFO] pyemitter: %s\n", n)
+		}
+	}
+
+	templateData := NewTemplateData(toolName, packageName, model)
+	templateData.BuildTool = buildTool
+	templateData.setPythonVersions(pythonVersion, pythonRequires)
+	templateData.MCPVersionSpec = mcpSpec
+	templateData.UseRuff = opts.UseRuff
+	templateData.PinDependencies = opts.PinDependencies
+	templateData.Invoke = opts.EnableInvoke && !opts.Library
+	if templateData.Invoke {
+		templateData.Credentials = emitter.Credentials(toolName, model)
+	}
+	templateData.Docker = opts.EmitDockerfile && !opts.Library
+	templateData.OTel = opts.WithOTel && !opts.Library
+	templateData.ShardModel = opts.ShardModelByTag
+	templateData.Skip = skip
+	if opts.GenerateFastAPI && !opts.Library {
+		templateData.FastAPI = true
+		templateData.APIRoutes = apiRoutes(model.Endpoints)
+	}
+	templateData.Version = genspec.ProjectVersion(opts.ProjectVersion, sm.Version)
+	if licenseID != "" {
+		templateData.License = licenseID
+		templateData.LicenseClassifier = license.Classifier(licenseID)
+	}
+	if author := strings.TrimSpace(opts.Author); author != "" {
+		templateData.Author = author
+	}
+	templateData.AuthorEmail = strings.TrimSpace(opts.AuthorEmail)
+	var files map[string][]byte
+	if opts.Library {
+		files, err = libraryFiles(templateData, model)
+	} else {
+		files, err = projectFiles(templateData, model)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The CI jobs run the Makefile targets, which only the server layout has.
+	if opts.GenerateCI && !opts.Library {
+		switch ciProvider {
+		case CIProviderGitLab:
+			files[".gitlab-ci.yml"] = []byte(renderTemplate(GitLabCITemplate, templateData))
+		default:
+			files[filepath.Join(".github", "workflows", "ci.yml")] = []byte(renderTemplate(GitHubActionsCITemplate, templateData))
+		}
+	}
+	if licenseID != "" {
+		text, err := license.Render(licenseID, templateData.Year, templateData.Author)
+		if err != nil {
+			return nil, err
+		}
+		files[license.FileName] = []byte(text)
+	}
+	if opts.EmitDocs {
+		files[filepath.FromSlash(docs.File)] = []byte(docs.Render(model, model.Title))
+	}
+	render := templateContext(templateData, opts).Render
+	if err := tmploverride.Apply(opts.TemplateOverrideDir, files, render); err != nil {
+		return nil, fmt.Errorf("pyemitter: %w", err)
+	}
+	err = tmploverride.ApplyNamed(opts.TemplateOverrideDir, "python", templateNames, func(name string) string {
+		return strings.Replace(name, "src/PACKAGE/", "src/"+templateData.PackageName+"/", 1)
+	}, files, render)
+	if err != nil {
+		return nil, fmt.Errorf("pyemitter: %w", err)
+	}
+	// Without a README the packaging declares none, so it is not essential.
+	essential := essentialFile
+	if skip.Readme {
+		essential = func(rel string) bool { return rel != "README.md" && essentialFile(rel) }
+	}
+	if err := fileexclude.Apply(skip.Patterns(skipFiles), files, essential); err != nil {
+		return nil, fmt.Errorf("pyemitter: %w", err)
+	}
+	if err := fileexclude.Apply(opts.ExcludeFiles, files, essential); err != nil {
+		return nil, fmt.Errorf("pyemitter: %w", err)
+	}
+
+	// Plan in deterministic order
+	rels := make([]string, 0, len(files))
+	for p := range files {
+		rels = append(rels, filepath.ToSlash(p))
+	}
+	genspec.SortNames(rels)
+
+	planned := make([]PlannedFile, 0, len(rels))
+	for _, rel := range rels {
+		content, mode := files[rel], fileModeFor(rel)
+		planned = append(planned, PlannedFile{
+			RelPath: rel,
+			Size:    len(content),
+			Mode:    mode,
+			SHA256:  manifest.HashBytes(content),
+			Change:  filewriter.Classify(opts.OutDir, rel, content, mode),
+		})
+	}
+
+	// Write files if not in dry-run mode; a dry-run still validates the output directory
+	w := &filewriter.Writer{Prefix: "pyemitter", Concurrency: opts.Concurrency, Force: opts.Force, Mode: fileModeFor}
+	res := &Result{ToolName: toolName, PackageName: packageName, Planned: planned}
+	if !opts.DryRun {
+		record := manifest.New(toolName, "python", manifest.SpecHash(sm), files)
+		if !opts.Force && manifest.Unchanged(opts.OutDir, record) {
+			res.Skipped = true
+			return res, nil
+		}
+		policy := manifest.Policy{OverwriteModified: opts.OverwriteModified, Prune: opts.Prune}
+		outcome, err := manifest.Apply(w, opts.OutDir, record, files, policy)
+		if err != nil {
+			return nil, err
+		}
+		res.Outcome = *outcome
+	} else if _, err := w.Check(opts.OutDir); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// skipFiles maps each Skip category to the files it leaves out.
+var skipFiles = map[string][]string{
+	emitter.SkipTests:    {"tests/*"},
+	emitter.SkipLint:     {".flake8", ".pylintrc", "ruff.toml", "mypy.ini", ".pre-commit-config.yaml"},
+	emitter.SkipMakefile: {"Makefile"},
+	emitter.SkipReadme:   {"README.md"},
+	emitter.SkipEditor:   {".editorconfig", ".vscode/*"},
+}
+
+// templateNames registers the built-in templates TemplateOverrideDir can
+// override by name, keyed by the slash-separated path of the file each
+// renders; PACKAGE stands for the package directory under src/. The model
+// data and docs/API.md are not templates.
+var templateNames = []string{
+	".dockerignore",
+	".editorconfig",
+	".flake8",
+	".github/workflows/ci.yml",
+	".gitignore",
+	".gitlab-ci.yml",
+	".pre-commit-config.yaml",
+	".pylintrc",
+	".vscode/launch.json",
+	"Dockerfile",
+	"LICENSE",
+	"Makefile",
+	"README.md",
+	"mypy.ini",
+	"poetry.lock",
+	"pyproject.toml",
+	"requirements-dev.txt",
+	"requirements.txt",
+	"ruff.toml",
+	"setup.py",
+	"src/PACKAGE/__init__.py",
+	"src/PACKAGE/__main__.py",
+	"src/PACKAGE/api/__init__.py",
+	"src/PACKAGE/api/router.py",
+	"src/PACKAGE/api_server.py",
+	"src/PACKAGE/main.py",
+	"src/PACKAGE/mcp/__init__.py",
+	"src/PACKAGE/mcp/methods/__init__.py",
+	"src/PACKAGE/mcp/methods/call_endpoint.py",
+	"src/PACKAGE/mcp/methods/explain_parameter.py",
+	"src/PACKAGE/mcp/methods/formatting.py",
+	"src/PACKAGE/mcp/methods/get_endpoint_details.py",
+	"src/PACKAGE/mcp/methods/get_schema_details.py",
+	"src/PACKAGE/mcp/methods/list_endpoints.py",
+	"src/PACKAGE/mcp/methods/list_schemas.py",
+	"src/PACKAGE/mcp/methods/search_endpoints.py",
+	"src/PACKAGE/server.py",
+	"src/PACKAGE/spec/__init__.py",
+	"src/PACKAGE/spec/loader.py",
+	"src/PACKAGE/spec/model.py",
+	"src/PACKAGE/telemetry.py",
+	"src/PACKAGE/transport.py",
+	"tests/__init__.py",
+	"tests/test_call_endpoint.py",
+	"tests/test_describe.py",
+	"tests/test_mcp_methods.py",
+	"tests/test_telemetry.py",
+	"tests/test_transport.py",
+	"uv.lock",
+}
+
+// TemplateNames returns the names of the templates TemplateOverrideDir can
+// override by name, each given there as python/<name>.tmpl.
+func TemplateNames() []string {
+	return append([]string(nil), templateNames...)
+}
+
+// essentialFile reports whether the generated package does not build without
+// rel: the build configuration, the README it declares and everything under
+// src/.
+func essentialFile(rel string) bool {
+	switch rel {
+	case "pyproject.toml", "setup.py", "README.md":
+		return true
+	}
+	return strings.HasPrefix(rel, "src/")
+}
+
+// projectFiles renders the full MCP server project.
+func projectFiles(templateData TemplateData, sm *genspec.ServiceModel) (map[string][]byte, error) {
 	files := map[string][]byte{}
 
 	// Project configuration files
-	templateData := NewTemplateData(toolName, packageName, sm)
 	files[".editorconfig"] = []byte(renderTemplate(EditorconfigTemplate, templateData))
 	files[".gitignore"] = []byte(renderTemplate(GitignoreTemplate, templateData))
-	files["setup.py"] = []byte(renderTemplate(SetupPyTemplate, templateData))
-	files["requirements.txt"] = []byte(renderTemplate(RequirementsTxtTemplate, templateData))
-	files["requirements-dev.txt"] = []byte(renderTemplate(RequirementsDevTxtTemplate, templateData))
-	files["pyproject.toml"] = []byte(renderTemplate(PyprojectTomlTemplate, templateData))
+	// uv and Poetry declare everything in pyproject.toml. Their lock files
+	// start as placeholders that the first lock run replaces.
+	switch templateData.BuildTool {
+	case BuildToolPoetry:
+		files["pyproject.toml"] = []byte(renderTemplate(PoetryPyprojectTomlTemplate, templateData))
+		files["poetry.lock"] = []byte(renderTemplate(LockStubTemplate, templateData))
+	case BuildToolUV:
+		files["pyproject.toml"] = []byte(renderTemplate(PyprojectTomlTemplate, templateData))
+		files["uv.lock"] = []byte(renderTemplate(LockStubTemplate, templateData))
+	default:
+		files["setup.py"] = []byte(renderTemplate(SetupPyTemplate, templateData))
+		files["requirements.txt"] = []byte(renderTemplate(RequirementsTxtTemplate, templateData))
+		files["requirements-dev.txt"] = []byte(renderTemplate(RequirementsDevTxtTemplate, templateData))
+		files["pyproject.toml"] = []byte(renderTemplate(PyprojectTomlTemplate, templateData))
+	}
 	files["Makefile"] = []byte(renderTemplate(MakefileTemplate, templateData))
+	if templateData.Docker {
+		files["Dockerfile"] = []byte(renderTemplate(DockerfileTemplate, templateData))
+		files[".dockerignore"] = []byte(renderTemplate(DockerignoreTemplate, templateData))
+	}
 	files["README.md"] = []byte(renderTemplate(ReadmeMdTemplate, templateData))
 
 	// Code quality and development configuration files
 	files[".pre-commit-config.yaml"] = []byte(renderTemplate(PreCommitConfigTemplate, templateData))
 	files["mypy.ini"] = []byte(renderTemplate(MyPyConfigTemplate, templateData))
-	files[".pylintrc"] = []byte(renderTemplate(PylintRcTemplate, templateData))
+	if templateData.UseRuff {
+		files["ruff.toml"] = []byte(renderTemplate(RuffTomlTemplate, templateData))
+	} else {
+		files[".pylintrc"] = []byte(renderTemplate(PylintRcTemplate, templateData))
+	}
+	files[".flake8"] = []byte(renderTemplate(Flake8Template, templateData))
+	files[filepath.Join(".vscode", "launch.json")] = []byte(renderTemplate(VSCodeLaunchTemplate, templateData))
 
 	// Source code structure
-	srcPath := filepath.Join("src", packageName)
-	files[filepath.Join(srcPath, "__init__.py")] = []byte(`"""Generated MCP tool package."""
-__version__ = "0.1.0"
-`)
+	srcPath := filepath.Join("src", templateData.PackageName)
+	files[filepath.Join(srcPath, "__init__.py")] = []byte(fmt.Sprintf(`"""Generated MCP tool package."""
+
+__version__ = %q
+`, templateData.Version))
+	files[filepath.Join(srcPath, "__main__.py")] = []byte(renderTemplate(DunderMainPyTemplate, templateData))
 	files[filepath.Join(srcPath, "main.py")] = []byte(renderTemplate(MainPyTemplate, templateData))
 	files[filepath.Join(srcPath, "server.py")] = []byte(renderTemplate(ServerPyTemplate, templateData))
+	files[filepath.Join(srcPath, "transport.py")] = []byte(renderTemplate(TransportPyTemplate, templateData))
+	if templateData.OTel {
+		files[filepath.Join(srcPath, "telemetry.py")] = []byte(renderTemplate(TelemetryPyTemplate, templateData))
+	}
 
 	// Spec package
 	specPath := filepath.Join(srcPath, "spec")
 	files[filepath.Join(specPath, "__init__.py")] = []byte("")
 	files[filepath.Join(specPath, "model.py")] = []byte(renderModelPy())
 
-	// model.json
-	modelJSON, err := json.MarshalIndent(sm, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("marshal model.json: %w", err)
+	// model.json, or its shards, and the loader
+	if err := addSpecData(files, specPath, templateData, sm); err != nil {
+		return nil, err
 	}
-	files[filepath.Join(specPath, "model.json")] = append(modelJSON, '\n')
-	files[filepath.Join(specPath, "loader.py")] = []byte(renderLoaderPy())
 
 	// MCP methods
 	mcpPath := filepath.Join(srcPath, "mcp")
 	files[filepath.Join(mcpPath, "__init__.py")] = []byte("")
 	methodsPath := filepath.Join(mcpPath, "methods")
 	files[filepath.Join(methodsPath, "__init__.py")] = []byte(renderTemplate(MethodsInitPyTemplate, templateData))
+	files[filepath.Join(methodsPath, "formatting.py")] = []byte(renderTemplate(FormattingPyTemplate, templateData))
 	files[filepath.Join(methodsPath, "list_endpoints.py")] = []byte(renderTemplate(ListEndpointsPyTemplate, templateData))
 	files[filepath.Join(methodsPath, "search_endpoints.py")] = []byte(renderTemplate(SearchEndpointsPyTemplate, templateData))
 	files[filepath.Join(methodsPath, "get_endpoint_details.py")] = []byte(renderTemplate(GetEndpointDetailsPyTemplate, templateData))
 	files[filepath.Join(methodsPath, "list_schemas.py")] = []byte(renderTemplate(ListSchemasPyTemplate, templateData))
 	files[filepath.Join(methodsPath, "get_schema_details.py")] = []byte(renderTemplate(GetSchemaDetailsPyTemplate, templateData))
+	files[filepath.Join(methodsPath, "explain_parameter.py")] = []byte(renderTemplate(ExplainParameterPyTemplate, templateData))
+	if templateData.Invoke {
+		files[filepath.Join(methodsPath, "call_endpoint.py")] = []byte(renderTemplate(CallEndpointPyTemplate, templateData))
+	}
+
+	// HTTP API
+	if templateData.FastAPI {
+		apiPath := filepath.Join(srcPath, "api")
+		files[filepath.Join(apiPath, "__init__.py")] = []byte(renderTemplate(APIInitPyTemplate, templateData))
+		files[filepath.Join(apiPath, "router.py")] = []byte(renderTemplate(APIRouterPyTemplate, templateData))
+		files[filepath.Join(srcPath, "api_server.py")] = []byte(renderTemplate(APIServerPyTemplate, templateData))
+	}
 
 	// Tests
 	testsPath := "tests"
 	files[filepath.Join(testsPath, "__init__.py")] = []byte(renderTemplate(TestsInitPyTemplate, templateData))
+	files[filepath.Join(testsPath, "test_describe.py")] = []byte(renderTemplate(TestDescribePyTemplate, templateData))
 	files[filepath.Join(testsPath, "test_mcp_methods.py")] = []byte(renderTemplate(TestMCPMethodsPyTemplate, templateData))
+	files[filepath.Join(testsPath, "test_transport.py")] = []byte(renderTemplate(TestTransportPyTemplate, templateData))
+	if templateData.Invoke {
+		files[filepath.Join(testsPath, "test_call_endpoint.py")] = []byte(renderTemplate(TestCallEndpointPyTemplate, templateData))
+	}
+	if templateData.OTel {
+		files[filepath.Join(testsPath, "test_telemetry.py")] = []byte(renderTemplate(TestTelemetryPyTemplate, templateData))
+	}
+	return files, nil
+}
 
-	// Plan in deterministic order
-	rels := make([]string, 0, len(files))
-	for p := range files {
-		rels = append(rels, filepath.ToSlash(p))
+// libraryFiles renders only the spec subpackage, with packaging that ships
+// model.json, for building a custom server on the typed model.
+func libraryFiles(templateData TemplateData, sm *genspec.ServiceModel) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	files[".editorconfig"] = []byte(renderTemplate(EditorconfigTemplate, templateData))
+	files[".gitignore"] = []byte(renderTemplate(GitignoreTemplate, templateData))
+	if templateData.BuildTool == BuildToolPoetry {
+		files["pyproject.toml"] = []byte(renderTemplate(LibraryPoetryPyprojectTomlTemplate, templateData))
+	} else {
+		files["pyproject.toml"] = []byte(renderTemplate(LibraryPyprojectTomlTemplate, templateData))
 	}
-	sort.Strings(rels)
+	files["README.md"] = []byte(renderTemplate(LibraryReadmeMdTemplate, templateData))
 
-	planned := make([]PlannedFile, 0, len(rels))
-	for _, rel := range rels {
-		// Determine appropriate file mode
-		var fileMode os.FileMode = 0o644
-		if isExecutable(rel) {
-			fileMode = 0o755
-		}
-		planned = append(planned, PlannedFile{
-			RelPath: rel,
-			Size:    len(files[rel]),
-			Mode:    fileMode,
-		})
+	srcPath := filepath.Join("src", templateData.PackageName)
+	files[filepath.Join(srcPath, "__init__.py")] = []byte(fmt.Sprintf(`"""Generated service model package."""
+
+__version__ = %q
+`, templateData.Version))
+	specPath := filepath.Join(srcPath, "spec")
+	files[filepath.Join(specPath, "__init__.py")] = []byte("")
+	files[filepath.Join(specPath, "model.py")] = []byte(renderModelPy())
+	if err := addSpecData(files, specPath, templateData, sm); err != nil {
+		return nil, err
 	}
+	return files, nil
+}
 
-	// Write files if not in dry-run mode
-	if !opts.DryRun {
-		if err := writeFiles(opts.OutDir, files, opts.Force); err != nil {
-			return nil, err
+// addSpecData writes the model data of the spec subpackage in specPath and
+// its loader.py: model.json, or with ShardModelByTag the index and per-tag
+// shards under model/.
+func addSpecData(files map[string][]byte, specPath string, templateData TemplateData, sm *genspec.ServiceModel) error {
+	if templateData.ShardModel {
+		shards, err := modelshard.Files(sm)
+		if err != nil {
+			return err
+		}
+		for rel, b := range shards {
+			files[filepath.Join(specPath, filepath.FromSlash(rel))] = b
 		}
 	} else {
-		// In dry-run mode, validate output directory without writing
-		abs, err := filepath.Abs(opts.OutDir)
+		modelJSON, err := json.MarshalIndent(sm, "", "  ")
 		if err != nil {
-			return nil, fmt.Errorf("pyemitter: resolve output directory: %w", err)
-		}
-		if err := validateOutputDirectory(abs, opts.Force); err != nil {
-			return nil, err
+			return fmt.Errorf("marshal model.json: %w", err)
 		}
+		files[filepath.Join(specPath, "model.json")] = append(modelJSON, '\n')
 	}
+	files[filepath.Join(specPath, "loader.py")] = []byte(renderLoaderPy(templateData.ShardModel))
+	return nil
+}
 
-	return &Result{ToolName: toolName, PackageName: packageName, Planned: planned}, nil
+// templateContext returns the data template overrides are rendered with.
+func templateContext(d TemplateData, opts Options) *emitter.TemplateContext {
+	c := emitter.NewTemplateContext("python", d.ToolName, d.PackageName, d.ServiceModel)
+	c.Version, c.Author, c.AuthorEmail = d.Version, d.Author, d.AuthorEmail
+	c.License, c.Year = d.License, d.Year
+	c.Library, c.EnableInvoke, c.PinDependencies = opts.Library, d.Invoke, d.PinDependencies
+	c.Dockerfile = d.Docker
+	c.CI = opts.GenerateCI && !opts.Library
+	c.OTel = d.OTel
+	c.ShardModel = d.ShardModel
+	c.Skip = d.Skip
+	c.Provenance = opts.Provenance
+	c.Python = &emitter.PythonContext{
+		BuildTool:      d.BuildTool,
+		Version:        d.PythonVersion,
+		Requires:       d.PythonRequires,
+		MCPVersionSpec: d.MCPVersionSpec,
+		UseRuff:        d.UseRuff,
+		FastAPI:        d.FastAPI,
+	}
+	return c
 }
 
-func writeFiles(outDir string, files map[string][]byte, force bool) error {
-	abs, err := filepath.Abs(outDir)
-	if err != nil {
-		return fmt.Errorf("pyemitter: resolve output directory: %w", err)
+// fileModeFor returns the permissions a generated file is written with.
+func fileModeFor(relPath string) os.FileMode {
+	if isExecutable(relPath) {
+		return 0o755
 	}
+	return 0o644
+}
+
+// isExecutable determines if a file should have executable permissions
+func isExecutable(relPath string) bool {
+	// Python entry point scripts and Makefiles should be executable
+	fileName := filepath.Base(relPath)
+	ext := filepath.Ext(relPath)
 
-	// Pre-flight: validate output directory and force flag
-	if err := validateOutputDirectory(abs, force); err != nil {
-		return err
+	// Makefiles should be executable
+	if fileName == "Makefile" || fileName == "makefile" {
+		return true
 	}
 
-	// Create directory structure first (all parent directories)
-	if err := createDirectoryStructure(abs, files); err != nil {
-		return fmt.Errorf("pyemitter: create directory structure: %w", err)
+	// Python main entry scripts should be executable
+	if strings.HasSuffix(relPath, "/main.py") || strings.HasSuffix(relPath, "\\main.py") {
+		return true
 	}
 
-	// Write files atomically with proper permissions
-	for rel, content := range files {
-		if err := writeFileAtomic(abs, rel, content); err != nil {
-			return fmt.Errorf("pyemitter: write file %s: %w", rel, err)
-		}
+	// Shell scripts should be executable
+	if ext == ".sh" || ext == ".bash" {
+		return true
 	}
 
-	return nil
+	return false
 }
 
-// validateOutputDirectory checks if the output directory is valid for writing
-func validateOutputDirectory(absPath string, force bool) error {
-	stat, err := os.Stat(absPath)
-	if os.IsNotExist(err) {
-		// Directory doesn't exist, will be created - this is fine
-		return nil
+// ResolveVersions validates the requires-python specifier and the mcp SDK
+// version, filling in DefaultPythonRequires for an empty requirement. A bare
+// Python version becomes a lower bound (3.10 -> >=3.10) and a bare SDK version
+// an exact pin (1.9.4 -> ==1.9.4); an empty SDK version yields no dependency.
+func ResolveVersions(pythonRequires, mcpSDKVersion string) (string, string, error) {
+	pythonRequires = strings.TrimSpace(pythonRequires)
+	if pythonRequires == "" {
+		pythonRequires = DefaultPythonRequires
+	}
+	if bareVersionRe.MatchString(pythonRequires) {
+		pythonRequires = ">=" + pythonRequires
 	}
+	clauses, err := specifierClauses(pythonRequires, pythonClauseRe)
 	if err != nil {
-		return fmt.Errorf("cannot access output directory %q: %w", absPath, err)
+		return "", "", fmt.Errorf("pyemitter: invalid Python requirement %q (expected e.g. >=3.10 or >=3.10,<4)", pythonRequires)
 	}
-
-	if !stat.IsDir() {
-		return fmt.Errorf("output path %q is not a directory", absPath)
+	pythonRequires = strings.Join(clauses, ",")
+	floor := pythonFloor(pythonRequires)
+	if floor == "" {
+		return "", "", fmt.Errorf("pyemitter: Python requirement %q needs a lower bound (>=, ~= or ==)", pythonRequires)
 	}
-
-	// If force flag is set, we can proceed regardless of content
-	if force {
-		return nil
+	if !strings.HasPrefix(floor, "3.") || pythonMinor(floor) < 8 {
+		return "", "", fmt.Errorf("pyemitter: Python requirement %q allows Python %s; the generated code needs 3.8 or newer", pythonRequires, floor)
 	}
 
-	// Check if directory is empty
-	entries, err := os.ReadDir(absPath)
-	if err != nil {
-		return fmt.Errorf("cannot read output directory %q: %w", absPath, err)
+	mcpSDKVersion = strings.TrimSpace(mcpSDKVersion)
+	if mcpSDKVersion == "" {
+		return pythonRequires, "", nil
 	}
-
-	if len(entries) > 0 {
-		return fmt.Errorf("output directory %q is not empty (use --force to overwrite)", absPath)
+	if bareVersionRe.MatchString(mcpSDKVersion) {
+		mcpSDKVersion = "==" + mcpSDKVersion
 	}
-
-	return nil
+	clauses, err = specifierClauses(mcpSDKVersion, versionClauseRe)
+	if err != nil {
+		return "", "", fmt.Errorf("pyemitter: invalid mcp SDK version %q (expected e.g. 1.9.4 or >=1.9,<2)", mcpSDKVersion)
+	}
+	return pythonRequires, strings.Join(clauses, ","), nil
 }
 
-// createDirectoryStructure creates all necessary directories for the file structure
-func createDirectoryStructure(baseDir string, files map[string][]byte) error {
-	dirsToCreate := make(map[string]bool)
-
-	// Collect all unique directory paths
-	for relPath := range files {
-		dir := filepath.Dir(relPath)
-		if dir != "." {
-			// Normalize path separators and collect all parent directories
-			normalizedDir := filepath.ToSlash(dir)
-			parts := strings.Split(normalizedDir, "/")
-			currentPath := ""
-			for _, part := range parts {
-				if currentPath == "" {
-					currentPath = part
-				} else {
-					currentPath = currentPath + "/" + part
-				}
-				dirsToCreate[currentPath] = true
-			}
+// ResolvePythonVersion validates the target Python version and the
+// requires-python specifier and fills in whichever is missing: the
+// requirement defaults to ">=" + version, the version to the requirement's
+// lower bound, and both to DefaultPythonVersion. When both are given the
+// version must be that lower bound, so the tools never target a Python the
+// package does not support (or the other way round).
+func ResolvePythonVersion(pythonVersion, pythonRequires string) (string, string, error) {
+	pythonVersion = strings.TrimSpace(pythonVersion)
+	if pythonVersion != "" {
+		if !pythonVersionRe.MatchString(pythonVersion) {
+			return "", "", fmt.Errorf("pyemitter: invalid Python version %q (expected major.minor, e.g. 3.11)", pythonVersion)
+		}
+		if pythonMinor(pythonVersion) < 8 {
+			return "", "", fmt.Errorf("pyemitter: Python version %s is too old; the generated code needs 3.8 or newer", pythonVersion)
 		}
 	}
-
-	// Create directories with proper permissions
-	for dirPath := range dirsToCreate {
-		fullPath := filepath.Join(baseDir, dirPath)
-		if err := os.MkdirAll(fullPath, 0o755); err != nil {
-			return fmt.Errorf("create directory %s: %w", dirPath, err)
+	if strings.TrimSpace(pythonRequires) == "" {
+		if pythonVersion == "" {
+			pythonVersion = DefaultPythonVersion
 		}
+		pythonRequires = ">=" + pythonVersion
 	}
-
-	return nil
-}
-
-// writeFileAtomic writes a file atomically using temporary file + rename
-func writeFileAtomic(baseDir, relPath string, content []byte) error {
-	fullPath := filepath.Join(baseDir, relPath)
-
-	// Determine file mode based on file type
-	var fileMode os.FileMode = 0o644
-	if isExecutable(relPath) {
-		fileMode = 0o755
+	pythonRequires, _, err := ResolveVersions(pythonRequires, "")
+	if err != nil {
+		return "", "", err
 	}
-
-	// Ensure target directory exists
-	dir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("ensure target directory %s: %w", dir, err)
+	floor := pythonFloor(pythonRequires)
+	if pythonVersion == "" {
+		pythonVersion = floor
+	} else if pythonVersion != floor {
+		return "", "", fmt.Errorf("pyemitter: Python version %s does not match the lower bound %s of requirement %q", pythonVersion, floor, pythonRequires)
 	}
+	return pythonVersion, pythonRequires, nil
+}
 
-	// Create temporary file in the same directory as the target
-	tmpFile, err := os.CreateTemp(dir, ".tmp-pyemitter-*")
-	if err != nil {
-		return fmt.Errorf("create temp file for %s: %w", relPath, err)
+// specifierClauses splits a comma-separated version specifier and checks each
+// clause against re, returning the clauses without inner whitespace.
+func specifierClauses(specifier string, re *regexp.Regexp) ([]string, error) {
+	var clauses []string
+	for _, c := range strings.Split(specifier, ",") {
+		m := re.FindStringSubmatch(strings.TrimSpace(c))
+		if m == nil {
+			return nil, fmt.Errorf("invalid clause %q", c)
+		}
+		clauses = append(clauses, m[1]+m[2])
 	}
+	return clauses, nil
+}
 
-	tmpPath := tmpFile.Name()
-	success := false
-
-	// Ensure cleanup on error
-	defer func() {
-		if tmpFile != nil {
-			tmpFile.Close()
+// pythonFloor returns the lowest major.minor a validated requires-python
+// specifier admits, or "" when it has no lower bound.
+func pythonFloor(requires string) string {
+	bestMajor, bestMinor := -1, -1
+	for _, c := range strings.Split(requires, ",") {
+		m := pythonClauseRe.FindStringSubmatch(c)
+		if m == nil || (m[1] != ">=" && m[1] != "~=" && m[1] != "==") {
+			continue
 		}
-		if !success {
-			os.Remove(tmpPath)
+		parts := strings.Split(strings.TrimSuffix(m[2], ".*"), ".")
+		major, _ := strconv.Atoi(parts[0])
+		minor := 0
+		if len(parts) > 1 {
+			minor, _ = strconv.Atoi(parts[1])
 		}
-	}()
-
-	// Write content to temporary file with buffered I/O
-	if len(content) > 0 {
-		n, err := tmpFile.Write(content)
-		if err != nil {
-			return fmt.Errorf("write content to temp file: %w", err)
+		if major > bestMajor || (major == bestMajor && minor > bestMinor) {
+			bestMajor, bestMinor = major, minor
 		}
-		if n != len(content) {
-			return fmt.Errorf("incomplete write: expected %d bytes, wrote %d", len(content), n)
-		}
-	}
-
-	// Sync to ensure data is written to disk
-	if err := tmpFile.Sync(); err != nil {
-		return fmt.Errorf("sync temp file: %w", err)
-	}
-
-	// Set file permissions before closing
-	if err := tmpFile.Chmod(fileMode); err != nil {
-		return fmt.Errorf("set file permissions: %w", err)
 	}
-
-	// Close temp file to ensure all data is written
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("close temp file: %w", err)
-	}
-	tmpFile = nil
-
-	// Atomically move temp file to final location
-	if err := os.Rename(tmpPath, fullPath); err != nil {
-		return fmt.Errorf("atomic rename %s to %s: %w", tmpPath, fullPath, err)
+	if bestMajor < 0 {
+		return ""
 	}
-
-	// Mark as successfully processed
-	success = true
-	return nil
+	return fmt.Sprintf("%d.%d", bestMajor, bestMinor)
 }
 
-// isExecutable determines if a file should have executable permissions
-func isExecutable(relPath string) bool {
-	// Python entry point scripts and Makefiles should be executable
-	fileName := filepath.Base(relPath)
-	ext := filepath.Ext(relPath)
-
-	// Makefiles should be executable
-	if fileName == "Makefile" || fileName == "makefile" {
-		return true
-	}
-
-	// Python main entry scripts should be executable
-	if strings.HasSuffix(relPath, "/main.py") || strings.HasSuffix(relPath, "\\main.py") {
-		return true
-	}
-
-	// Shell scripts should be executable
-	if ext == ".sh" || ext == ".bash" {
-		return true
-	}
-
-	return false
+// pythonMinor returns the minor version of a pythonFloor result.
+func pythonMinor(floor string) int {
+	minor, _ := strconv.Atoi(floor[strings.Index(floor, ".")+1:])
+	return minor
 }
 
 func sanitizeToolName(name string) string {
@@ -425,24 +815,22 @@ func renderModelPy() string {
 	}
 
 	// Use a basic model template since the full one is complex
-	template := `"""Data model definitions for MCP server.
+	template := `"""Data model definitions for the MCP server.
 
-This module defines the core data structures used by the MCP server
-to represent API documentation and schema information. These structures
-are compatible with the Go ServiceModel definitions.
+These dataclasses mirror the Go ServiceModel that swagger2mcp embeds as
+model.json, including its SchemaOrRef wrappers.
 
 Generated by swagger2mcp - DO NOT MODIFY MANUALLY
 """
 
-from __future__ import annotations
 from dataclasses import dataclass, field
-from typing import List, Dict, Optional, Any, Union
 from enum import Enum
-import json
+from typing import Any, Dict, List, Optional, Tuple
 
 
 class HttpMethod(str, Enum):
-    """HTTP method enumeration matching OpenAPI specification."""
+    """HTTP method enumeration matching the OpenAPI specification."""
+
     GET = "get"
     POST = "post"
     PUT = "put"
@@ -453,82 +841,128 @@ class HttpMethod(str, Enum):
     TRACE = "trace"
 
     def __str__(self) -> str:
-        return self.value
+        """Return the lower-case method name used in model.json."""
+        return str(self.value)
 
 
 @dataclass
 class Server:
-    """Server information from OpenAPI specification."""
+    """Server information from the OpenAPI specification."""
+
     url: str = ""
     description: str = ""
+    development: bool = False  # localhost, private-network, .local or x-internal
 
 
 @dataclass
 class SchemaRef:
-    """Schema reference containing a $ref pointer."""
-    ref: str = ""
+    """Schema reference containing a JSON pointer such as #/components/schemas/Pet."""
 
+    ref: str = ""
 
-@dataclass
-class SchemaOrRef:
-    """Container for either a Schema or a SchemaRef."""
-    schema: Optional["Schema"] = None
-    ref: Optional[SchemaRef] = None
+    @property
+    def name(self) -> str:
+        """Return the referenced schema name without its JSON pointer prefix."""
+        return self.ref.rsplit("/", 1)[-1]
 
 
+# Schema mirrors every field of the Go struct, so it needs more attributes
+# than pylint's default limit.
 @dataclass
-class Schema:
-    """Schema definition from OpenAPI specification."""
+class Schema:  # pylint: disable=too-many-instance-attributes
+    """Schema definition from the OpenAPI specification."""
+
     name: str = ""
     type: str = ""
-    properties: Optional[Dict[str, SchemaOrRef]] = None
-    required: Optional[List[str]] = None
-    items: Optional[SchemaOrRef] = None
-    all_of: Optional[List[SchemaOrRef]] = None
-    any_of: Optional[List[SchemaOrRef]] = None
-    one_of: Optional[List[SchemaOrRef]] = None
+    properties: Dict[str, "SchemaOrRef"] = field(default_factory=dict)
+    required: List[str] = field(default_factory=list)
+    items: Optional["SchemaOrRef"] = None
+    all_of: List["SchemaOrRef"] = field(default_factory=list)
+    any_of: List["SchemaOrRef"] = field(default_factory=list)
+    one_of: List["SchemaOrRef"] = field(default_factory=list)
     description: str = ""
-    enum: Optional[List[Any]] = None
-    format: Optional[str] = None
+    enum: List[Any] = field(default_factory=list)
+    format: str = ""
     example: Any = None
+    extensions: Dict[str, Any] = field(default_factory=dict)
+    # Array and numeric constraints; exclusive_* qualify minimum/maximum.
+    min_items: Optional[int] = None
+    max_items: Optional[int] = None
+    unique_items: bool = False
+    minimum: Optional[float] = None
+    maximum: Optional[float] = None
+    exclusive_minimum: bool = False
+    exclusive_maximum: bool = False
+
+
+@dataclass
+class SchemaOrRef:
+    """Container for an inline Schema, a SchemaRef, or both.
+
+    Parameters that reference a schema also carry it inline.
+    """
+
+    schema: Optional[Schema] = None
+    ref: Optional[SchemaRef] = None
 
 
 @dataclass
 class Media:
     """Media type definition for request/response content."""
+
     mime: str = ""
     schema: Optional[SchemaOrRef] = None
     example: Any = None
+    is_binary: bool = False
 
 
 @dataclass
 class ParameterModel:
-    """Parameter definition for API endpoints."""
+    """API parameter definition."""
+
     name: str = ""
-    in_: str = ""  # path|query|header|cookie
+    in_: str = ""
     required: bool = False
-    schema: Optional[SchemaOrRef] = None
+    description: str = ""
+    schema: Optional[SchemaOrRef] = None  # a referenced schema is also inlined
+    example: Any = None
+    style: str = ""
+    explode: Optional[bool] = None
 
 
 @dataclass
 class RequestBodyModel:
-    """Request body definition for API endpoints."""
+    """Request body definition."""
+
     content: List[Media] = field(default_factory=list)
     required: bool = False
 
 
 @dataclass
 class ResponseModel:
-    """Response definition for API endpoints."""
-    status: str = ""  # 200, 4xx, default
+    """API response definition."""
+
+    status: str = ""
     description: str = ""
     content: List[Media] = field(default_factory=list)
 
 
 @dataclass
-class EndpointModel:
-    """API endpoint definition from OpenAPI specification."""
-    id: str = ""  # method+path
+class PaginationHint:
+    """Pagination pattern of a list endpoint."""
+
+    style: str = ""  # cursor|offset|unknown
+    params: List[str] = field(default_factory=list)
+
+
+# EndpointModel mirrors every field of the Go struct, so it needs more
+# attributes than pylint's default limit.
+@dataclass
+class EndpointModel:  # pylint: disable=too-many-instance-attributes
+    """API endpoint definition."""
+
+    id: str = ""
+    operation_id: str = ""
     method: HttpMethod = HttpMethod.GET
     path: str = ""
     summary: str = ""
@@ -537,188 +971,241 @@ class EndpointModel:
     parameters: List[ParameterModel] = field(default_factory=list)
     request_body: Optional[RequestBodyModel] = None
     responses: List[ResponseModel] = field(default_factory=list)
+    deprecated: bool = False
+    featured: bool = False
+    pagination: Optional[PaginationHint] = None
+    extensions: Dict[str, Any] = field(default_factory=dict)
+    # Alternative security requirements by scheme name; [] allows anonymous access.
+    security: List[List[str]] = field(default_factory=list)
 
 
+# ServiceModel mirrors every field of the Go struct, so it needs more
+# attributes than pylint's default limit.
 @dataclass
-class ServiceModel:
+class ServiceModel:  # pylint: disable=too-many-instance-attributes
     """Root service model containing all API documentation."""
+
     title: str = ""
     version: str = ""
     description: str = ""
     servers: List[Server] = field(default_factory=list)
     tags: List[str] = field(default_factory=list)
+    tag_descriptions: Dict[str, str] = field(default_factory=dict)
     endpoints: List[EndpointModel] = field(default_factory=list)
     schemas: Dict[str, Schema] = field(default_factory=dict)
+    extensions: Dict[str, Any] = field(default_factory=dict)
 
     @classmethod
     def from_dict(cls, data: Dict[str, Any]) -> "ServiceModel":
-        """Create ServiceModel from dictionary with complete parsing."""
-        # Parse servers
-        servers = []
-        servers_data = data.get("Servers", data.get("servers", []))
-        if servers_data:
-            for server_data in servers_data:
-                if server_data:  # Check for None
-                    servers.append(Server(
-                        url=server_data.get("URL", server_data.get("url", "")),
-                        description=server_data.get("Description", server_data.get("description", ""))
-                    ))
-        
-        # Parse endpoints
-        endpoints = []
-        endpoints_data = data.get("Endpoints", data.get("endpoints", []))
-        if endpoints_data:
-            for endpoint_data in endpoints_data:
-                if not endpoint_data:  # Skip None endpoints
-                    continue
-                    
-                # Parse parameters
-                parameters = []
-                parameters_data = endpoint_data.get("Parameters", endpoint_data.get("parameters", []))
-                if parameters_data:
-                    for param_data in parameters_data:
-                        if not param_data:  # Skip None parameters
-                            continue
-                            
-                        schema = None
-                        if "Schema" in param_data or "schema" in param_data:
-                            schema_data = param_data.get("Schema", param_data.get("schema"))
-                            if schema_data and "$ref" in schema_data:
-                                schema = SchemaOrRef(ref=SchemaRef(ref=schema_data["$ref"]))
-                            elif schema_data:
-                                schema = SchemaOrRef(schema=Schema(
-                                    type=schema_data.get("Type", schema_data.get("type", "")),
-                                    format=schema_data.get("Format", schema_data.get("format"))
-                                ))
-                        
-                        parameters.append(ParameterModel(
-                            name=param_data.get("Name", param_data.get("name", "")),
-                            in_=param_data.get("In", param_data.get("in", "")),
-                            required=param_data.get("Required", param_data.get("required", False)),
-                            schema=schema
-                        ))
-            
-                # Parse request body
-                request_body = None
-                if "RequestBody" in endpoint_data or "requestBody" in endpoint_data:
-                    rb_data = endpoint_data.get("RequestBody", endpoint_data.get("requestBody"))
-                    if rb_data:
-                        content = []
-                        content_data = rb_data.get("Content", rb_data.get("content", []))
-                        if content_data:
-                            for media_data in content_data:
-                                if not media_data:  # Skip None media
-                                    continue
-                                    
-                                schema = None
-                                if "Schema" in media_data or "schema" in media_data:
-                                    schema_data = media_data.get("Schema", media_data.get("schema"))
-                                    if schema_data and "$ref" in schema_data:
-                                        schema = SchemaOrRef(ref=SchemaRef(ref=schema_data["$ref"]))
-                                    elif schema_data:
-                                        schema = SchemaOrRef(schema=Schema(
-                                            type=schema_data.get("Type", schema_data.get("type", "")),
-                                            format=schema_data.get("Format", schema_data.get("format"))
-                                        ))
-                                
-                                content.append(Media(
-                                    mime=media_data.get("Mime", media_data.get("mime", "")),
-                                    schema=schema
-                                ))
-                        
-                        request_body = RequestBodyModel(
-                            content=content,
-                            required=rb_data.get("Required", rb_data.get("required", False))
-                        )
-            
-                # Parse responses
-                responses = []
-                responses_data = endpoint_data.get("Responses", endpoint_data.get("responses", []))
-                if responses_data:
-                    for resp_data in responses_data:
-                        if not resp_data:  # Skip None responses
-                            continue
-                            
-                        content = []
-                        content_data = resp_data.get("Content", resp_data.get("content", []))
-                        if content_data:
-                            for media_data in content_data:
-                                if not media_data:  # Skip None media
-                                    continue
-                                    
-                                schema = None
-                                if "Schema" in media_data or "schema" in media_data:
-                                    schema_data = media_data.get("Schema", media_data.get("schema"))
-                                    if schema_data and "$ref" in schema_data:
-                                        schema = SchemaOrRef(ref=SchemaRef(ref=schema_data["$ref"]))
-                                    elif schema_data:
-                                        schema = SchemaOrRef(schema=Schema(
-                                            type=schema_data.get("Type", schema_data.get("type", "")),
-                                            format=schema_data.get("Format", schema_data.get("format"))
-                                        ))
-                                
-                                content.append(Media(
-                                    mime=media_data.get("Mime", media_data.get("mime", "")),
-                                    schema=schema
-                                ))
-                        
-                        responses.append(ResponseModel(
-                            status=resp_data.get("Status", resp_data.get("status", "")),
-                            description=resp_data.get("Description", resp_data.get("description", "")),
-                            content=content
-                        ))
-            
-                endpoints.append(EndpointModel(
-                    id=endpoint_data.get("ID", endpoint_data.get("id", "")),
-                    method=HttpMethod(endpoint_data.get("Method", endpoint_data.get("method", "get"))),
-                    path=endpoint_data.get("Path", endpoint_data.get("path", "")),
-                    summary=endpoint_data.get("Summary", endpoint_data.get("summary", "")),
-                    description=endpoint_data.get("Description", endpoint_data.get("description", "")),
-                    tags=endpoint_data.get("Tags", endpoint_data.get("tags", [])),
-                    parameters=parameters,
-                    request_body=request_body,
-                    responses=responses
-                ))
-        
-        # Parse schemas
-        schemas = {}
-        schemas_data = data.get("Schemas", data.get("schemas", {}))
-        if schemas_data:
-            for schema_name, schema_data in schemas_data.items():
-                if not schema_data:  # Skip None schemas
-                    continue
-                    
-                # Parse properties
-                properties = {}
-                if "Properties" in schema_data or "properties" in schema_data:
-                    props_data = schema_data.get("Properties", schema_data.get("properties", {}))
-                    if props_data:
-                        for prop_name, prop_data in props_data.items():
-                            if prop_data and "$ref" in prop_data:
-                                properties[prop_name] = SchemaOrRef(ref=SchemaRef(ref=prop_data["$ref"]))
-                            elif prop_data:
-                                properties[prop_name] = SchemaOrRef(schema=Schema(
-                                    type=prop_data.get("Type", prop_data.get("type", "")),
-                                    format=prop_data.get("Format", prop_data.get("format"))
-                                ))
-                
-                schemas[schema_name] = Schema(
-                    name=schema_name,
-                    type=schema_data.get("Type", schema_data.get("type", "")),
-                    properties=properties,
-                    required=schema_data.get("Required", schema_data.get("required", [])),
-                    description=schema_data.get("Description", schema_data.get("description", ""))
-                )
-        
+        """Create a ServiceModel from the decoded model.json content.
+
+        Field names follow the Go ServiceModel (Title, Endpoints, ...);
+        lower-case keys are accepted as well.
+        """
         return cls(
-            title=data.get("title", data.get("Title", "")),
-            version=data.get("version", data.get("Version", "")),
-            description=data.get("description", data.get("Description", "")),
-            servers=servers,
-            tags=data.get("tags", data.get("Tags", [])),
-            endpoints=endpoints,
-            schemas=schemas
+            title=_text(data, "Title"),
+            version=_text(data, "Version"),
+            description=_text(data, "Description"),
+            servers=[
+                Server(
+                    url=_text(item, "URL"),
+                    description=_text(item, "Description"),
+                    development=bool(_field(item, "Development", False)),
+                )
+                for item in _dicts(data, "Servers")
+            ],
+            tags=[str(tag) for tag in _list(data, "Tags")],
+            tag_descriptions={
+                str(tag): str(desc)
+                for tag, desc in _dict(data, "TagDescriptions").items()
+            },
+            endpoints=[_endpoint(item) for item in _dicts(data, "Endpoints")],
+            schemas={
+                str(name): _schema(item, str(name))
+                for name, item in _dict(data, "Schemas").items()
+                if isinstance(item, dict)
+            },
+            extensions=_dict(data, "Extensions"),
         )
+
+
+def _field(data: Dict[str, Any], name: str, default: Any = None) -> Any:
+    """Read a field by its Go name, falling back to the lower-case spelling."""
+    value = data.get(name)
+    if value is None:
+        value = data.get(name.lower())
+    return default if value is None else value
+
+
+def _text(data: Dict[str, Any], name: str) -> str:
+    return str(_field(data, name, ""))
+
+
+def _list(data: Dict[str, Any], name: str) -> List[Any]:
+    value = _field(data, name)
+    return list(value) if isinstance(value, list) else []
+
+
+def _dicts(data: Dict[str, Any], name: str) -> List[Dict[str, Any]]:
+    return [item for item in _list(data, name) if isinstance(item, dict)]
+
+
+def _dict(data: Dict[str, Any], name: str) -> Dict[str, Any]:
+    value = _field(data, name)
+    return dict(value) if isinstance(value, dict) else {}
+
+
+def _optional_int(data: Dict[str, Any], name: str) -> Optional[int]:
+    value = _field(data, name)
+    return int(value) if isinstance(value, (int, float)) else None
+
+
+def _optional_number(data: Dict[str, Any], name: str) -> Optional[float]:
+    value = _field(data, name)
+    return value if isinstance(value, (int, float)) else None
+
+
+def _optional_bool(data: Dict[str, Any], name: str) -> Optional[bool]:
+    value = _field(data, name)
+    return value if isinstance(value, bool) else None
+
+
+def _schema_or_ref(data: Any) -> Optional[SchemaOrRef]:
+    """Parse a Go SchemaOrRef wrapper holding a "Schema", a "Ref" or both."""
+    if not isinstance(data, dict):
+        return None
+    ref = _field(data, "Ref")
+    schema = _field(data, "Schema")
+    if not isinstance(ref, dict) and not isinstance(schema, dict):
+        return None
+    return SchemaOrRef(
+        schema=_schema(schema) if isinstance(schema, dict) else None,
+        ref=SchemaRef(ref=_text(ref, "Ref")) if isinstance(ref, dict) else None,
+    )
+
+
+def _schema_or_ref_list(data: Dict[str, Any], name: str) -> List[SchemaOrRef]:
+    parsed = [_schema_or_ref(item) for item in _list(data, name)]
+    return [item for item in parsed if item is not None]
+
+
+def _schema(data: Dict[str, Any], name: str = "") -> Schema:
+    properties: Dict[str, SchemaOrRef] = {}
+    for prop_name, prop_data in _dict(data, "Properties").items():
+        prop = _schema_or_ref(prop_data)
+        if prop is not None:
+            properties[str(prop_name)] = prop
+    return Schema(
+        name=_text(data, "Name") or name,
+        type=_text(data, "Type"),
+        properties=properties,
+        required=[str(item) for item in _list(data, "Required")],
+        items=_schema_or_ref(_field(data, "Items")),
+        all_of=_schema_or_ref_list(data, "AllOf"),
+        any_of=_schema_or_ref_list(data, "AnyOf"),
+        one_of=_schema_or_ref_list(data, "OneOf"),
+        description=_text(data, "Description"),
+        enum=_list(data, "Enum"),
+        format=_text(data, "Format"),
+        example=_field(data, "Example"),
+        extensions=_dict(data, "Extensions"),
+        min_items=_optional_int(data, "MinItems"),
+        max_items=_optional_int(data, "MaxItems"),
+        unique_items=bool(_field(data, "UniqueItems", False)),
+        minimum=_optional_number(data, "Minimum"),
+        maximum=_optional_number(data, "Maximum"),
+        exclusive_minimum=bool(_field(data, "ExclusiveMinimum", False)),
+        exclusive_maximum=bool(_field(data, "ExclusiveMaximum", False)),
+    )
+
+
+def _media_list(data: Dict[str, Any]) -> List[Media]:
+    return [
+        Media(
+            mime=_text(item, "Mime"),
+            schema=_schema_or_ref(_field(item, "Schema")),
+            example=_field(item, "Example"),
+            is_binary=bool(_field(item, "IsBinary", False)),
+        )
+        for item in _dicts(data, "Content")
+    ]
+
+
+def _parameter(data: Dict[str, Any]) -> ParameterModel:
+    return ParameterModel(
+        name=_text(data, "Name"),
+        in_=_text(data, "In"),
+        required=bool(_field(data, "Required", False)),
+        description=_text(data, "Description"),
+        schema=_schema_or_ref(_field(data, "Schema")),
+        example=_field(data, "Example"),
+        style=_text(data, "Style"),
+        explode=_optional_bool(data, "Explode"),
+    )
+
+
+def _request_body(data: Any) -> Optional[RequestBodyModel]:
+    if not isinstance(data, dict):
+        return None
+    return RequestBodyModel(
+        content=_media_list(data),
+        required=bool(_field(data, "Required", False)),
+    )
+
+
+def _response(data: Dict[str, Any]) -> ResponseModel:
+    return ResponseModel(
+        status=_text(data, "Status"),
+        description=_text(data, "Description"),
+        content=_media_list(data),
+    )
+
+
+def _pagination(data: Any) -> Optional[PaginationHint]:
+    if not isinstance(data, dict):
+        return None
+    return PaginationHint(
+        style=_text(data, "Style"),
+        params=[str(name) for name in _list(data, "Params")],
+    )
+
+
+def _endpoint(data: Dict[str, Any]) -> EndpointModel:
+    return EndpointModel(
+        id=_text(data, "ID"),
+        operation_id=_text(data, "OperationID"),
+        method=HttpMethod(_text(data, "Method").lower() or HttpMethod.GET.value),
+        path=_text(data, "Path"),
+        summary=_text(data, "Summary"),
+        description=_text(data, "Description"),
+        tags=[str(tag) for tag in _list(data, "Tags")],
+        parameters=[_parameter(item) for item in _dicts(data, "Parameters")],
+        request_body=_request_body(_field(data, "RequestBody")),
+        responses=[_response(item) for item in _dicts(data, "Responses")],
+        deprecated=bool(_field(data, "Deprecated", False)),
+        featured=bool(_field(data, "Featured", False)),
+        pagination=_pagination(_field(data, "Pagination")),
+        extensions=_dict(data, "Extensions"),
+        security=[
+            [str(name) for name in alternative]
+            for alternative in _list(data, "Security")
+            if isinstance(alternative, list)
+        ],
+    )
+
+
+_ASCII_FOLD = {code: code + 32 for code in range(ord("A"), ord("Z") + 1)}
+
+
+def name_key(name: str) -> Tuple[str, str]:
+    """Sort key of the names the tools list: schemas, properties, tags, paths and headers.
+
+    Names compare case-insensitively first, folding only the ASCII letters,
+    then by code point, so "apple" < "Pet" < "pet" < "Zebra"; non-ASCII
+    characters sort by code point after ASCII. It is the order of the
+    generator's model, which str.lower and locale collation are not.
+    """
+    return name.translate(_ASCII_FOLD), name
 `
 
 	result, err := RenderTemplateWithErrorHandling("model.py", template, templateData)
@@ -728,78 +1215,275 @@ class ServiceModel:
 	return result
 }
 
-// renderLoaderPy renders the loader.py file (specific implementation)
-func renderLoaderPy() string {
-	template := `"""Service model loader for MCP server.
+// renderLoaderPy renders the loader.py file (specific implementation); with
+// shardModel it reads the index and per-tag shards under model/ instead of
+// model.json.
+func renderLoaderPy(shardModel bool) string {
+	if shardModel {
+		return withShardedModelPy(loaderPy)
+	}
+	return loaderPy
+}
+
+// withShardedModelPy swaps the model.json reader of loaderPy for one of the
+// modelshard files, adding load_index, shards, load_shard and load_tag.
+func withShardedModelPy(src string) string {
+	return strings.NewReplacer(
+		"Loads the embedded model.json into ServiceModel instances.",
+		"Loads the embedded model, split by tag into model/_index.json and one\nshard per tag, into ServiceModel instances. Shards are read on demand.",
+		"from pathlib import Path\nfrom typing import Optional\n\nfrom .model import Server, ServiceModel\n",
+		"from dataclasses import dataclass\nfrom functools import lru_cache\nfrom pathlib import Path\nfrom typing import Any, Dict, List, Optional, Tuple\n\nfrom .model import EndpointModel, Server, ServiceModel\n",
+		`MODEL_PATH = Path(__file__).with_name("model.json")`,
+		`MODEL_DIR = Path(__file__).with_name("model")`,
+		loaderPyLoad, shardedLoaderPyLoad,
+		`    "load_service_model",
+    "load_service_model_safe",
+    "model_hash",
+]`, `    "Shard",
+    "load_index",
+    "load_service_model",
+    "load_service_model_safe",
+    "load_shard",
+    "load_tag",
+    "model_hash",
+    "read_model_data",
+    "shards",
+]`,
+	).Replace(src)
+}
+
+const loaderPyLoad = `def load_service_model() -> ServiceModel:
+    """Load the embedded service model from model.json.
+
+    Returns:
+        The loaded service model.
+
+    Raises:
+        ServiceModelLoadError: If model.json is missing or malformed.
+    """
+    try:
+        with MODEL_PATH.open(encoding="utf-8") as handle:
+            data = json.load(handle)
+        if not isinstance(data, dict):
+            raise ServiceModelLoadError("model.json must contain a JSON object")
+        model = ServiceModel.from_dict(data)
+    except (OSError, ValueError, TypeError) as exc:
+        raise ServiceModelLoadError(f"Failed to load service model: {exc}") from exc
+    logger.info("Loaded service model: %s v%s", model.title, model.version)
+    return model
+
+
+def model_hash() -> str:
+    """Return the hex SHA-256 of model.json, which identifies the model.
+
+    Raises:
+        ServiceModelLoadError: If model.json cannot be read.
+    """
+    try:
+        return hashlib.sha256(MODEL_PATH.read_bytes()).hexdigest()
+    except OSError as exc:
+        raise ServiceModelLoadError(f"Failed to read model.json: {exc}") from exc
+`
+
+const shardedLoaderPyLoad = `@dataclass
+class Shard:
+    """One endpoint shard of the embedded model.
+
+    model/<name>.json holds the endpoints whose first tag is tag ("" for the
+    endpoints without tags).
+    """
+
+    name: str
+    tag: str
+    endpoints: int
+
+
+def _read(name: str) -> Any:
+    path = MODEL_DIR / f"{name}.json"
+    try:
+        with path.open(encoding="utf-8") as handle:
+            return json.load(handle)
+    except (OSError, ValueError) as exc:
+        raise ServiceModelLoadError(f"Failed to read model/{path.name}: {exc}") from exc
+
+
+def _read_index() -> Dict[str, Any]:
+    data = _read("_index")
+    if not isinstance(data, dict):
+        raise ServiceModelLoadError("model/_index.json must contain a JSON object")
+    return data
+
+
+def _read_shard(name: str) -> List[Dict[str, Any]]:
+    data = _read(name)
+    if not isinstance(data, list):
+        raise ServiceModelLoadError(f"model/{name}.json must contain a JSON array")
+    return [item for item in data if isinstance(item, dict)]
+
+
+def read_model_data() -> Dict[str, Any]:
+    """Return the decoded model, assembled from the index and every shard.
+
+    Raises:
+        ServiceModelLoadError: If a model file is missing or malformed.
+    """
+    data = _read_index()
+    by_shard = {
+        str(item.get("Name")): _read_shard(str(item.get("Name")))
+        for item in data.pop("Shards", None) or []
+    }
+    endpoints = []
+    for item in data.pop("EndpointShards", None) or []:
+        remaining = by_shard.get(str(item.get("Shard"))) or []
+        if not remaining:
+            raise ServiceModelLoadError(
+                f"model shard {item.get('Shard')} is missing endpoint {item.get('ID')}"
+            )
+        endpoints.append(remaining.pop(0))
+    data["Endpoints"] = endpoints
+    return data
+
+
+def load_service_model() -> ServiceModel:
+    """Load the embedded service model from the index and every shard.
+
+    Returns:
+        The loaded service model.
+
+    Raises:
+        ServiceModelLoadError: If a model file is missing or malformed.
+    """
+    try:
+        model = ServiceModel.from_dict(read_model_data())
+    except (AttributeError, ValueError, TypeError) as exc:
+        raise ServiceModelLoadError(f"Failed to load service model: {exc}") from exc
+    logger.info("Loaded service model: %s v%s", model.title, model.version)
+    return model
+
+
+def load_index() -> ServiceModel:
+    """Load the service model without its endpoints, reading no shard."""
+    data = _read_index()
+    data["Endpoints"] = []
+    return ServiceModel.from_dict(data)
+
+
+@lru_cache(maxsize=None)
+def shards() -> Tuple[Shard, ...]:
+    """List the shards in the order their first endpoint appears."""
+    return tuple(
+        Shard(
+            name=str(item.get("Name") or ""),
+            tag=str(item.get("Tag") or ""),
+            endpoints=int(item.get("Endpoints") or 0),
+        )
+        for item in _read_index().get("Shards") or []
+        if isinstance(item, dict)
+    )
+
+
+@lru_cache(maxsize=None)
+def load_shard(name: str) -> Tuple[EndpointModel, ...]:
+    """Load the endpoints of the named shard, reading its file once."""
+    return tuple(ServiceModel.from_dict({"Endpoints": _read_shard(name)}).endpoints)
+
+
+def load_tag(tag: str) -> Tuple[EndpointModel, ...]:
+    """Load the endpoints whose first tag is tag, or those without tags for ""."""
+    for shard in shards():
+        if shard.tag == tag:
+            return load_shard(shard.name)
+    return ()
+
 
-This module handles loading the embedded service model from the JSON file
-and creating Python data structure instances.
+def model_hash() -> str:
+    """Return the hex SHA-256 of the model files concatenated in name order.
+
+    Raises:
+        ServiceModelLoadError: If a model file cannot be read.
+    """
+    digest = hashlib.sha256()
+    try:
+        for path in sorted(MODEL_DIR.glob("*.json")):
+            digest.update(path.read_bytes())
+    except OSError as exc:
+        raise ServiceModelLoadError(f"Failed to read the model files: {exc}") from exc
+    return digest.hexdigest()
+`
+
+// loaderPy is the loader.py of the spec subpackage.
+const loaderPy = `"""Service model loader for the MCP server.
+
+Loads the embedded model.json into ServiceModel instances.
 
 Generated by swagger2mcp - DO NOT MODIFY MANUALLY
 """
 
+import hashlib
 import json
-import os
 import logging
+import os
 from pathlib import Path
 from typing import Optional
 
-from .model import ServiceModel
+from .model import Server, ServiceModel
 
 logger = logging.getLogger(__name__)
 
+MODEL_PATH = Path(__file__).with_name("model.json")
+
+# Environment variable that, when set, replaces the spec's server selection so
+# one build can target staging and production.
+BASE_URL_ENV = "API_BASE_URL"
+
 
 class ServiceModelLoadError(Exception):
-    """Exception raised when service model loading fails."""
-    pass
+    """Raised when the embedded service model cannot be loaded."""
 
 
-def load_service_model() -> ServiceModel:
-    """Load the embedded service model from model.json.
-    
-    Returns:
-        ServiceModel: The loaded and validated service model instance.
-        
-    Raises:
-        ServiceModelLoadError: If the model cannot be loaded or is invalid.
+` + loaderPyLoad + `
+
+def _base_url_from_env() -> str:
+    return os.environ.get(BASE_URL_ENV, "").strip()
+
+
+def apply_base_url_override(model: ServiceModel) -> ServiceModel:
+    """Put the URL from API_BASE_URL, when set, first in model.servers.
+
+    A server with the same URL is dropped, so the override wins over the
+    spec's servers.
     """
-    try:
-        # Get the path to model.json
-        current_dir = Path(__file__).parent
-        model_json_path = current_dir / "model.json"
-        
-        if not model_json_path.exists():
-            raise ServiceModelLoadError(f"Model file not found: {model_json_path}")
-        
-        # Load and parse JSON
-        with open(model_json_path, 'r', encoding='utf-8') as f:
-            json_data = json.load(f)
-        
-        # Create ServiceModel instance
-        service_model = ServiceModel.from_dict(json_data)
-        
-        logger.info(f"Successfully loaded service model: {service_model.title} v{service_model.version}")
-        return service_model
-        
-    except Exception as e:
-        raise ServiceModelLoadError(f"Failed to load service model: {e}") from e
+    url = _base_url_from_env()
+    if url:
+        others = [server for server in model.servers if server.url != url]
+        model.servers = [Server(url=url, description=f"from {BASE_URL_ENV}")] + others
+    return model
+
+
+def base_url(model: ServiceModel) -> str:
+    """Return API_BASE_URL when set, else the first server URL, else ""."""
+    env_url = _base_url_from_env()
+    if env_url:
+        return env_url
+    return model.servers[0].url if model.servers else ""
 
 
 def load_service_model_safe() -> Optional[ServiceModel]:
-    """Load the service model with exception handling.
-    
-    Returns:
-        ServiceModel: The loaded service model, or None if loading failed.
-    """
+    """Load the service model, returning None instead of raising on failure."""
     try:
         return load_service_model()
-    except ServiceModelLoadError as e:
-        logger.error(f"Failed to load service model: {e}")
+    except ServiceModelLoadError as exc:
+        logger.error("Failed to load service model: %s", exc)
         return None
 
 
-__all__ = ["ServiceModelLoadError", "load_service_model", "load_service_model_safe"]
+__all__ = [
+    "BASE_URL_ENV",
+    "ServiceModelLoadError",
+    "apply_base_url_override",
+    "base_url",
+    "load_service_model",
+    "load_service_model_safe",
+    "model_hash",
+]
 `
-
-	return template
-}
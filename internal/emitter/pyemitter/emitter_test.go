@@ -2,12 +2,19 @@ package pyemitter
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter/manifest"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"gopkg.in/yaml.v3"
 )
 
 func TestEmit_BasicFunctionality(t *testing.T) {
@@ -71,6 +78,7 @@ func TestEmit_BasicFunctionality(t *testing.T) {
 		"src/test_package/spec/model.py",
 		"src/test_package/spec/loader.py",
 		"src/test_package/spec/model.json",
+		".vscode/launch.json",
 	}
 
 	for _, expectedFile := range expectedFiles {
@@ -79,6 +87,21 @@ func TestEmit_BasicFunctionality(t *testing.T) {
 			t.Errorf("expected file %s does not exist", expectedFile)
 		}
 	}
+
+	// The debug configuration must be valid JSON pointing at the package module.
+	launchData, err := os.ReadFile(filepath.Join(tmpDir, ".vscode", "launch.json"))
+	if err != nil {
+		t.Fatalf("read launch.json: %v", err)
+	}
+	var launch struct {
+		Configurations []map[string]any `json:"configurations"`
+	}
+	if err := json.Unmarshal(launchData, &launch); err != nil {
+		t.Fatalf("launch.json invalid: %v", err)
+	}
+	if len(launch.Configurations) != 2 || launch.Configurations[0]["module"] != "test_package.main" {
+		t.Errorf("unexpected launch.json: %s", launchData)
+	}
 }
 
 func TestEmit_DryRun(t *testing.T) {
@@ -307,6 +330,369 @@ func TestEmit_PythonSyntaxValidation(t *testing.T) {
 	verifyPythonSyntax(t, tmpDir)
 }
 
+// TestEmit_GeneratedProjectPassesLint 在生成的项目中安装固定版本的开发依赖，
+// 并运行 make lint typecheck，确保生成代码与生成的检查配置一致。
+// 需要网络安装依赖，仅在 SWAGGER2MCP_E2E_ONLINE=1 时运行。
+func TestEmit_GeneratedProjectPassesLint(t *testing.T) {
+	if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+		t.Skip("set SWAGGER2MCP_E2E_ONLINE=1 to lint the generated project")
+	}
+	for _, name := range []string{"python3", "make"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%s not available", name)
+		}
+	}
+
+	tmpDir := t.TempDir()
+	opts := Options{
+		OutDir:      tmpDir,
+		ToolName:    "complex-api-tool",
+		PackageName: "complex_api",
+		Force:       true,
+	}
+	if _, err := Emit(context.Background(), createComplexServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+
+	venvBin := filepath.Join(tmpDir, ".venv", "bin")
+	if out, err := runInDir(tmpDir, 2*time.Minute, nil, "python3", "-m", "venv", ".venv"); err != nil {
+		t.Skipf("python venv unavailable: %v\n%s", err, out)
+	}
+	pip := filepath.Join(venvBin, "pip")
+	if out, err := runInDir(tmpDir, 10*time.Minute, nil, pip, "install", "-r", "requirements-dev.txt", "-e", "."); err != nil {
+		t.Skipf("pip install skipped (likely offline): %v\n%s", err, out)
+	}
+
+	env := []string{"PATH=" + venvBin + string(os.PathListSeparator) + os.Getenv("PATH")}
+	if out, err := runInDir(tmpDir, 5*time.Minute, env, "make", "lint", "typecheck"); err != nil {
+		t.Fatalf("make lint typecheck failed: %v\n%s", err, out)
+	}
+}
+
+func runInDir(dir string, timeout time.Duration, env []string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// TestEmit_BaseURLOverride 验证 API_BASE_URL 覆盖规格中的服务器地址。
+func TestEmit_BaseURLOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), createComplexServiceModel(), Options{OutDir: tmpDir, ToolName: "base-url", PackageName: "base_url"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	server, _ := os.ReadFile(filepath.Join(tmpDir, "src", "base_url", "server.py"))
+	if !strings.Contains(string(server), "apply_base_url_override(load_service_model())") {
+		t.Fatalf("server.py should apply API_BASE_URL:\n%s", server)
+	}
+	readme, _ := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	if !strings.Contains(string(readme), "API_BASE_URL") {
+		t.Fatalf("README should document API_BASE_URL")
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `from base_url.spec.loader import apply_base_url_override, base_url, load_service_model
+model = load_service_model()
+print(base_url(model))
+print(",".join(server.url for server in apply_base_url_override(model).servers))
+`
+	src := filepath.Join(tmpDir, "src")
+	out, err := runInDir(src, time.Minute, nil, "python3", "-c", script)
+	if err != nil || out != "https://api.petstore.com/v2\nhttps://api.petstore.com/v2,https://staging.petstore.com/v2\n" {
+		t.Fatalf("without API_BASE_URL: %v\n%s", err, out)
+	}
+	out, err = runInDir(src, time.Minute, []string{"API_BASE_URL=https://staging.petstore.com/v2"}, "python3", "-c", script)
+	if err != nil || out != "https://staging.petstore.com/v2\nhttps://staging.petstore.com/v2,https://api.petstore.com/v2\n" {
+		t.Fatalf("with API_BASE_URL: %v\n%s", err, out)
+	}
+}
+
+// TestEmit_TagDescriptions 验证 README 的标签列表与概览中的标签描述。
+func TestEmit_TagDescriptions(t *testing.T) {
+	sm := createSimpleServiceModel()
+	sm.Endpoints[0].Tags = []string{"read", "write"}
+	sm.Tags = []string{"read", "write"}
+	sm.TagDescriptions = map[string]string{"read": "Read-only\n  operations", "write": ""}
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), sm, Options{OutDir: tmpDir, ToolName: "tag-tool", PackageName: "tag_tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	readme, _ := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	if !strings.Contains(string(readme), "## 标签\n\n- read — Read-only operations\n- write\n\n## 快速开始") {
+		t.Fatalf("README should list the tags with their descriptions:\n%s", readme)
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `from tag_tool.spec.loader import load_service_model
+from tag_tool.mcp.methods.list_endpoints import format_endpoints_overview
+model = load_service_model()
+print(model.tag_descriptions)
+print("\n".join(line for line in format_endpoints_overview(model).splitlines() if line.startswith("- 🏷️")))
+`
+	out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, nil, "python3", "-c", script)
+	want := "{'read': 'Read-only\\n  operations', 'write': ''}\n- 🏷️ read: 1 个 — Read-only\n- 🏷️ write: 1 个\n"
+	if err != nil || out != want {
+		t.Fatalf("overview: %v\n%q", err, out)
+	}
+
+	untagged := createSimpleServiceModel()
+	untagged.Tags = nil
+	tmpDir = t.TempDir()
+	if _, err := Emit(context.Background(), untagged, Options{OutDir: tmpDir, ToolName: "tag-tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	readme, _ = os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	if strings.Contains(string(readme), "## 标签") {
+		t.Fatalf("README of an untagged model should have no 标签 section:\n%s", readme)
+	}
+}
+
+// TestEmit_ExplainParameter 验证 explainParameter 工具的注册与参数说明输出。
+func TestEmit_ExplainParameter(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), createComplexServiceModel(), Options{OutDir: tmpDir, ToolName: "explain", PackageName: "explain"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pkg := filepath.Join(tmpDir, "src", "explain")
+	if _, err := os.Stat(filepath.Join(pkg, "mcp", "methods", "explain_parameter.py")); err != nil {
+		t.Fatalf("explain_parameter.py not emitted: %v", err)
+	}
+	server, _ := os.ReadFile(filepath.Join(pkg, "server.py"))
+	if !strings.Contains(string(server), `"explainParameter"`) {
+		t.Fatalf("server.py should register explainParameter:\n%s", server)
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `from explain.spec.loader import load_service_model
+from explain.mcp.methods.explain_parameter import ParameterLookupError, explain_parameter
+model = load_service_model()
+status = explain_parameter(model, "GET /pets", "status")
+print(status.in_, status.style, status.explode, ",".join(status.enum))
+pet_id = explain_parameter(model, "get /pets/{petId}", "petId")
+print(pet_id.in_, pet_id.required, pet_id.style)
+try:
+    explain_parameter(model, "get /pets", "statu")
+except ParameterLookupError as exc:
+    print(exc)
+`
+	out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, nil, "python3", "-c", script)
+	if err != nil {
+		t.Fatalf("python3 failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"query form True available,pending,sold", "path True simple", `did you mean: status`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// TestEmit_LibraryLayout 验证库布局只输出 spec 子包及其打包配置。
+func TestEmit_LibraryLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "pets-model", PackageName: "pets_model", Library: true, DryRun: true}
+	res, err := Emit(context.Background(), createComplexServiceModel(), opts)
+	if err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	var got []string
+	for _, pf := range res.Planned {
+		got = append(got, pf.RelPath)
+	}
+	want := []string{
+		".editorconfig", ".gitignore", "pyproject.toml", "README.md",
+		"src/pets_model/__init__.py",
+		"src/pets_model/spec/__init__.py", "src/pets_model/spec/loader.py", "src/pets_model/spec/model.json", "src/pets_model/spec/model.py",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("library plan = %v, want %v", got, want)
+	}
+
+	opts.DryRun = false
+	if _, err := Emit(context.Background(), createComplexServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pyproject, _ := os.ReadFile(filepath.Join(tmpDir, "pyproject.toml"))
+	for _, want := range []string{`name = "pets_model"`, "dependencies = []", `"pets_model.spec" = ["model.json"]`} {
+		if !strings.Contains(string(pyproject), want) {
+			t.Errorf("pyproject.toml missing %q", want)
+		}
+	}
+	if strings.Contains(string(pyproject), "project.scripts") {
+		t.Errorf("library pyproject.toml should not declare console scripts")
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `from pets_model.spec.loader import load_service_model
+model = load_service_model()
+print(model.title, len(model.endpoints))
+`
+	out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, nil, "python3", "-c", script)
+	if err != nil || out != "Complex Pet Store API 3\n" {
+		t.Fatalf("load library model: %v\n%s", err, out)
+	}
+}
+
+// TestEmit_BuildToolFiles 验证每种构建工具生成(或不生成)的打包文件。
+func TestEmit_BuildToolFiles(t *testing.T) {
+	packaging := []string{"setup.py", "requirements.txt", "requirements-dev.txt", "pyproject.toml", "uv.lock", "poetry.lock"}
+	cases := []struct {
+		tool      string
+		want      []string
+		pyproject string
+	}{
+		{"", []string{"setup.py", "requirements.txt", "requirements-dev.txt", "pyproject.toml"}, "[project.optional-dependencies]"},
+		{BuildToolSetuptools, []string{"setup.py", "requirements.txt", "requirements-dev.txt", "pyproject.toml"}, "[project.optional-dependencies]"},
+		{BuildToolUV, []string{"pyproject.toml", "uv.lock"}, "[tool.uv]"},
+		{BuildToolPoetry, []string{"pyproject.toml", "poetry.lock"}, "[tool.poetry.dependencies]"},
+	}
+	for _, tc := range cases {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", PackageName: "complex_api", BuildTool: tc.tool}
+		res, err := Emit(context.Background(), createComplexServiceModel(), opts)
+		if err != nil {
+			t.Fatalf("%q: Emit failed: %v", tc.tool, err)
+		}
+		planned := map[string]bool{}
+		for _, pf := range res.Planned {
+			planned[pf.RelPath] = true
+		}
+		var got []string
+		for _, rel := range packaging {
+			if planned[rel] {
+				got = append(got, rel)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%q: packaging files = %v, want %v", tc.tool, got, tc.want)
+		}
+		pyproject, err := os.ReadFile(filepath.Join(tmpDir, "pyproject.toml"))
+		if err != nil {
+			t.Fatalf("%q: read pyproject.toml: %v", tc.tool, err)
+		}
+		if !strings.Contains(string(pyproject), tc.pyproject) {
+			t.Errorf("%q: pyproject.toml missing %s", tc.tool, tc.pyproject)
+		}
+		for _, lock := range []string{"uv.lock", "poetry.lock"} {
+			if !planned[lock] {
+				continue
+			}
+			data, _ := os.ReadFile(filepath.Join(tmpDir, lock))
+			if !strings.Contains(string(data), "提交到版本库") {
+				t.Errorf("%q: %s placeholder should say it must be committed:\n%s", tc.tool, lock, data)
+			}
+		}
+	}
+
+	// uv 项目不再有 setup.py，pyproject.toml 需自行声明 src 布局与 model.json。
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), createComplexServiceModel(), Options{OutDir: tmpDir, PackageName: "complex_api", BuildTool: BuildToolUV}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pyproject, _ := os.ReadFile(filepath.Join(tmpDir, "pyproject.toml"))
+	for _, want := range []string{"[dependency-groups]", `"complex_api.spec" = ["model.json"]`, "[project.scripts]"} {
+		if !strings.Contains(string(pyproject), want) {
+			t.Errorf("uv pyproject.toml missing %q", want)
+		}
+	}
+	makefile, _ := os.ReadFile(filepath.Join(tmpDir, "Makefile"))
+	for _, want := range []string{"\tuv sync --no-dev\n", "\tuv run pytest tests/", "\tuv lock\n"} {
+		if !strings.Contains(string(makefile), want) {
+			t.Errorf("uv Makefile missing %q", want)
+		}
+	}
+}
+
+// TestEmit_PoetryBuildSystem 验证 poetry 构建工具只输出 [tool.poetry] 形式的
+// pyproject.toml，不生成 setup.py 与 requirements*.txt。
+func TestEmit_PoetryBuildSystem(t *testing.T) {
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", PackageName: "complex_api", BuildTool: BuildToolPoetry}
+	res, err := Emit(context.Background(), createComplexServiceModel(), opts)
+	if err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	planned := map[string]bool{}
+	for _, pf := range res.Planned {
+		planned[pf.RelPath] = true
+	}
+	for _, rel := range []string{"setup.py", "requirements.txt", "requirements-dev.txt"} {
+		if planned[rel] {
+			t.Errorf("poetry mode should not generate %s", rel)
+		}
+	}
+	if !planned["pyproject.toml"] || !planned["Makefile"] {
+		t.Fatalf("poetry mode should still generate pyproject.toml and Makefile: %v", planned)
+	}
+
+	read := func(rel string) string {
+		data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+	pyproject := read("pyproject.toml")
+	for _, want := range []string{
+		`build-backend = "poetry.core.masonry.api"`,
+		"[tool.poetry]",
+		`packages = [{ include = "complex_api", from = "src" }]`,
+		"[tool.poetry.dependencies]",
+		"[tool.poetry.group.dev.dependencies]",
+		"[tool.poetry.scripts]\n\"complex-api-tool\" = \"complex_api.main:main\"",
+		"[tool.black]",
+	} {
+		if !strings.Contains(pyproject, want) {
+			t.Errorf("pyproject.toml missing %q", want)
+		}
+	}
+	if strings.Contains(pyproject, "[project]") {
+		t.Errorf("poetry pyproject.toml should not declare a PEP 621 [project] table")
+	}
+	makefile := read("Makefile")
+	for _, want := range []string{"\tpoetry install --only main\n", "\tpoetry run pytest tests/", "\tpoetry build\n"} {
+		if !strings.Contains(makefile, want) {
+			t.Errorf("Makefile missing %q", want)
+		}
+	}
+	if strings.Contains(makefile, "pip install") || strings.Contains(makefile, "requirements") {
+		t.Errorf("poetry Makefile should not use pip or requirements files:\n%s", makefile)
+	}
+	if readme := read("README.md"); !strings.Contains(readme, "poetry run complex-api-tool") || strings.Contains(readme, "requirements.txt") {
+		t.Errorf("README should document the poetry workflow:\n%s", readme)
+	}
+	if strings.Contains(read(".gitignore"), "poetry.lock") {
+		t.Errorf(".gitignore should not ignore poetry.lock in poetry mode")
+	}
+
+	if _, err := Emit(context.Background(), createComplexServiceModel(), Options{OutDir: t.TempDir(), BuildTool: "hatch"}); err == nil {
+		t.Errorf("expected an error for an unknown build system")
+	}
+
+	// Building the wheel needs poetry-core from the package index.
+	if os.Getenv("SWAGGER2MCP_E2E_ONLINE") != "1" {
+		return
+	}
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	wheelDir := t.TempDir()
+	if out, err := runInDir(tmpDir, 5*time.Minute, nil, "python3", "-m", "pip", "wheel", "--no-deps", "-w", wheelDir, "."); err != nil {
+		t.Fatalf("build poetry wheel: %v\n%s", err, out)
+	}
+}
+
 // TestEmit_TemplateRendering 测试模板渲染正确性
 func TestEmit_TemplateRendering(t *testing.T) {
 	sm := createComplexServiceModel()
@@ -718,6 +1104,7 @@ func verifyAllRequiredFiles(t *testing.T, baseDir string) {
 
 		// Code quality configuration
 		".pre-commit-config.yaml",
+		".flake8",
 		"mypy.ini",
 		".pylintrc",
 
@@ -735,6 +1122,7 @@ func verifyAllRequiredFiles(t *testing.T, baseDir string) {
 		// MCP methods
 		"src/complex_api/mcp/__init__.py",
 		"src/complex_api/mcp/methods/__init__.py",
+		"src/complex_api/mcp/methods/formatting.py",
 		"src/complex_api/mcp/methods/list_endpoints.py",
 		"src/complex_api/mcp/methods/search_endpoints.py",
 		"src/complex_api/mcp/methods/get_endpoint_details.py",
@@ -963,3 +1351,1310 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestEmit_ForceProtectsModifiedFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	opts := Options{OutDir: dir, ToolName: "test-tool"}
+	if _, err := Emit(ctx, createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("first emit: %v", err)
+	}
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := createSimpleServiceModel()
+	changed.Version = "9.9.9"
+	opts.Force = true
+	res, err := Emit(ctx, changed, opts)
+	if err != nil {
+		t.Fatalf("forced emit: %v", err)
+	}
+	if b, _ := os.ReadFile(readme); string(b) != "edited" {
+		t.Fatalf("edited README.md was overwritten")
+	}
+	if len(res.Outcome.Modified) != 1 || res.Outcome.Modified[0] != "README.md" {
+		t.Fatalf("expected README.md reported as modified, got %+v", res.Outcome)
+	}
+
+	opts.OverwriteModified = true
+	if _, err := Emit(ctx, changed, opts); err != nil {
+		t.Fatalf("overwrite emit: %v", err)
+	}
+	if b, _ := os.ReadFile(readme); string(b) == "edited" {
+		t.Fatalf("OverwriteModified should replace README.md")
+	}
+}
+
+// TestEmit_License 验证 License 选项生成填好年份与作者的 LICENSE 文件。
+func TestEmit_License(t *testing.T) {
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", License: "BSD-3-Clause", DryRun: true}
+	res, err := Emit(context.Background(), createSimpleServiceModel(), opts)
+	if err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	planned := false
+	for _, pf := range res.Planned {
+		planned = planned || pf.RelPath == "LICENSE"
+	}
+	if !planned {
+		t.Fatalf("dry-run plan should list LICENSE")
+	}
+	opts.DryRun = false
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "LICENSE"))
+	if err != nil {
+		t.Fatalf("read LICENSE: %v", err)
+	}
+	want := fmt.Sprintf("Copyright (c) %d, Generated by swagger2mcp\n", time.Now().Year())
+	if !strings.HasPrefix(string(data), "BSD 3-Clause License\n\n"+want) {
+		t.Fatalf("unexpected LICENSE:\n%s", data)
+	}
+
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), License: "GPL-3.0"}); err == nil {
+		t.Fatalf("expected an error for an unknown license")
+	}
+}
+
+// TestEmit_AuthorMetadata 验证作者、邮箱与许可证写入各构建工具的包元数据。
+func TestEmit_AuthorMetadata(t *testing.T) {
+	cases := map[string][]string{
+		BuildToolSetuptools: {
+			`author="Jane Doe"`,
+			`author_email="jane@example.com"`,
+			`license="Apache-2.0"`,
+			`"License :: OSI Approved :: Apache Software License"`,
+		},
+		BuildToolUV: {
+			`{name = "Jane Doe", email = "jane@example.com"}`,
+			`license = {text = "Apache-2.0"}`,
+		},
+		BuildToolPoetry: {
+			`authors = ["Jane Doe <jane@example.com>"]`,
+			`license = "Apache-2.0"`,
+		},
+	}
+	for tool, wants := range cases {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", BuildTool: tool, License: "apache-2.0", Author: "Jane Doe", AuthorEmail: "jane@example.com"}
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+			t.Fatalf("%s: Emit failed: %v", tool, err)
+		}
+		var content strings.Builder
+		for _, name := range []string{"setup.py", "pyproject.toml"} {
+			data, err := os.ReadFile(filepath.Join(tmpDir, name))
+			if err == nil {
+				content.Write(data)
+			}
+		}
+		for _, want := range wants {
+			if !strings.Contains(content.String(), want) {
+				t.Errorf("%s: package metadata missing %s:\n%s", tool, want, content.String())
+			}
+		}
+		licenseText, err := os.ReadFile(filepath.Join(tmpDir, "LICENSE"))
+		if err != nil || !strings.Contains(string(licenseText), "Jane Doe") {
+			t.Errorf("%s: LICENSE should name the author (err=%v)", tool, err)
+		}
+	}
+}
+
+// TestEmit_GenerateCI 验证 GitHub Actions 与 GitLab CI 配置是合法的 YAML 且包含测试任务。
+func TestEmit_GenerateCI(t *testing.T) {
+	for _, tool := range []string{BuildToolSetuptools, BuildToolUV, BuildToolPoetry} {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", BuildTool: tool, UseRuff: true, GenerateCI: true}
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+			t.Fatalf("%s: Emit failed: %v", tool, err)
+		}
+		data, err := os.ReadFile(filepath.Join(tmpDir, ".github", "workflows", "ci.yml"))
+		if err != nil {
+			t.Fatalf("%s: read ci.yml: %v", tool, err)
+		}
+		var workflow struct {
+			On   map[string]any `yaml:"on"`
+			Jobs map[string]struct {
+				RunsOn   string `yaml:"runs-on"`
+				Strategy struct {
+					Matrix map[string][]string `yaml:"matrix"`
+				} `yaml:"strategy"`
+				Steps []map[string]any `yaml:"steps"`
+			} `yaml:"jobs"`
+		}
+		if err := yaml.Unmarshal(data, &workflow); err != nil {
+			t.Fatalf("%s: ci.yml is not valid YAML: %v\n%s", tool, err, data)
+		}
+		if _, ok := workflow.On["push"]; !ok {
+			t.Errorf("%s: ci.yml should run on push", tool)
+		}
+		if _, ok := workflow.On["pull_request"]; !ok {
+			t.Errorf("%s: ci.yml should run on pull_request", tool)
+		}
+		for _, job := range []string{"lint", "type-check", "test"} {
+			if workflow.Jobs[job].RunsOn != "ubuntu-latest" {
+				t.Errorf("%s: job %s missing or not on ubuntu-latest", tool, job)
+			}
+		}
+		test := workflow.Jobs["test"]
+		if got := strings.Join(test.Strategy.Matrix["python-version"], ","); got != "3.10,3.11,3.12" {
+			t.Errorf("%s: test matrix = %s", tool, got)
+		}
+		for _, want := range []string{"path: .venv", "run: make test", "Lint (ruff)", "${{ matrix.python-version }}"} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s: ci.yml missing %q:\n%s", tool, want, data)
+			}
+		}
+
+		tmpDir = t.TempDir()
+		opts = Options{OutDir: tmpDir, ToolName: "complex-api-tool", BuildTool: tool, GenerateCI: true, CIProvider: CIProviderGitLab}
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+			t.Fatalf("%s: Emit failed: %v", tool, err)
+		}
+		data, err = os.ReadFile(filepath.Join(tmpDir, ".gitlab-ci.yml"))
+		if err != nil {
+			t.Fatalf("%s: read .gitlab-ci.yml: %v", tool, err)
+		}
+		var pipeline map[string]any
+		if err := yaml.Unmarshal(data, &pipeline); err != nil {
+			t.Fatalf("%s: .gitlab-ci.yml is not valid YAML: %v\n%s", tool, err, data)
+		}
+		stages, _ := pipeline["stages"].([]any)
+		if len(stages) != 3 || stages[2] != "test" {
+			t.Errorf("%s: stages = %v", tool, pipeline["stages"])
+		}
+		job, _ := pipeline["test"].(map[string]any)
+		if job["stage"] != "test" {
+			t.Errorf("%s: .gitlab-ci.yml has no test job:\n%s", tool, data)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, ".github")); !os.IsNotExist(err) {
+			t.Errorf("%s: gitlab provider should not emit .github", tool)
+		}
+	}
+
+	// 默认不生成 CI 配置; Python 下限较高时矩阵随之收窄
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: tmpDir, ToolName: "complex-api-tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".github")); !os.IsNotExist(err) {
+		t.Errorf(".github should not be generated by default")
+	}
+	tmpDir = t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: tmpDir, ToolName: "complex-api-tool", PythonRequires: "3.11", GenerateCI: true}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, ".github", "workflows", "ci.yml"))
+	if !strings.Contains(string(data), `python-version: ["3.11", "3.12"]`) {
+		t.Errorf("matrix should start at the Python floor:\n%s", data)
+	}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), GenerateCI: true, CIProvider: "jenkins"}); err == nil {
+		t.Fatalf("expected an error for an unsupported CIProvider")
+	}
+}
+
+// TestEmit_GenerateFastAPI 验证每个端点生成一条 FastAPI 路由, 并随之添加依赖、入口与 Makefile 目标。
+func TestEmit_GenerateFastAPI(t *testing.T) {
+	sm := createSimpleServiceModel()
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), sm, Options{OutDir: tmpDir, ToolName: "complex-api-tool", GenerateFastAPI: true}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pkgDir := filepath.Join(tmpDir, "src", "complex_api_tool")
+	router, err := os.ReadFile(filepath.Join(pkgDir, "api", "router.py"))
+	if err != nil {
+		t.Fatalf("read router.py: %v", err)
+	}
+	if got := strings.Count(string(router), "\n@router.get("); got != len(sm.Endpoints) {
+		t.Errorf("router.py has %d routes, want one per endpoint (%d):\n%s", got, len(sm.Endpoints), router)
+	}
+	for _, ep := range sm.Endpoints {
+		if !strings.Contains(string(router), fmt.Sprintf("endpoint_details(%q)", ep.ID)) {
+			t.Errorf("router.py has no route for %s", ep.ID)
+		}
+	}
+	wants := map[string][]string{
+		"requirements.txt": {"fastapi>=", "uvicorn>="},
+		"pyproject.toml":   {`"fastapi>=`, `"uvicorn>=`},
+		"Makefile":         {"run-api:", "uvicorn --app-dir src complex_api_tool.api_server:app"},
+		filepath.Join(pkgDir, "api", "__init__.py"): {"from .router import router"},
+		filepath.Join(pkgDir, "api_server.py"):      {"app.include_router(router)", "uvicorn.run("},
+	}
+	for rel, ws := range wants {
+		if !filepath.IsAbs(rel) {
+			rel = filepath.Join(tmpDir, rel)
+		}
+		data, err := os.ReadFile(rel)
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		for _, want := range ws {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s missing %q", rel, want)
+			}
+		}
+	}
+	if main, _ := os.ReadFile(filepath.Join(pkgDir, "main.py")); strings.Contains(string(main), "fastapi") {
+		t.Errorf("main.py should stay the stdio MCP entry point")
+	}
+
+	// 默认与 library 布局都不生成
+	for _, opts := range []Options{{ToolName: "complex-api-tool"}, {ToolName: "complex-api-tool", Library: true, GenerateFastAPI: true}} {
+		opts.OutDir = t.TempDir()
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(opts.OutDir, "src", "complex_api_tool", "api")); !os.IsNotExist(err) {
+			t.Errorf("library=%v: api package should not be generated", opts.Library)
+		}
+		if data, _ := os.ReadFile(filepath.Join(opts.OutDir, "pyproject.toml")); strings.Contains(string(data), "fastapi") {
+			t.Errorf("library=%v: pyproject.toml should not depend on fastapi", opts.Library)
+		}
+	}
+}
+
+func TestAPIRoutes(t *testing.T) {
+	long := "/" + strings.Repeat("segment/", 12) + "{id}"
+	routes := apiRoutes([]genspec.EndpointModel{
+		{ID: "get /pets/{petId}", Method: "get", Path: "/pets/{petId}"},
+		{ID: "get /pets/petId", Method: "get", Path: "/pets/petId"},
+		{ID: "get /", Method: "get", Path: "/"},
+		{ID: "post " + long, Method: "post", Path: long},
+	})
+	want := []APIRoute{
+		{Path: "/get-pets-petid", Function: "get_pets_petid", EndpointID: "get /pets/{petId}"},
+		{Path: "/get-pets-petid-2", Function: "get_pets_petid_2", EndpointID: "get /pets/petId"},
+		{Path: "/get", Function: "get", EndpointID: "get /"},
+	}
+	for i, w := range want {
+		if routes[i] != w {
+			t.Errorf("route %d = %+v, want %+v", i, routes[i], w)
+		}
+	}
+	if got := routes[3].Function; len(got) > maxRouteSlug || !strings.HasPrefix(got, "post_segment_") {
+		t.Errorf("long path function = %q, want at most %d characters", got, maxRouteSlug)
+	}
+}
+
+// TestAPIRoutes_LongPaths 验证 300 字符以上的路径: 路由与函数名有长度上限、互不相同且每次生成结果一致。
+func TestAPIRoutes_LongPaths(t *testing.T) {
+	long := "/" + strings.Repeat("accounts/{accountId}/", 15)
+	endpoints := []genspec.EndpointModel{
+		{ID: "get " + long + "a", Method: "get", Path: long + "a"},
+		{ID: "get " + long + "b", Method: "get", Path: long + "b"},
+		{ID: "get " + long + "a/", Method: "get", Path: long + "a/"},
+	}
+	routes := apiRoutes(endpoints)
+	seen := map[string]bool{}
+	for i, r := range routes {
+		if len(r.Function) > maxRouteSlug || len(r.Path) > maxRouteSlug+1 {
+			t.Errorf("route %d 超出长度上限: %+v", i, r)
+		}
+		if seen[r.Function] {
+			t.Errorf("route %d 函数名重复: %s", i, r.Function)
+		}
+		seen[r.Function] = true
+		if r.EndpointID != endpoints[i].ID {
+			t.Errorf("route %d 应保留完整的端点 ID, got %q", i, r.EndpointID)
+		}
+	}
+	if again := apiRoutes(endpoints); !reflect.DeepEqual(again, routes) {
+		t.Errorf("两次生成的路由不一致:\n%+v\n%+v", routes, again)
+	}
+}
+
+// TestEmit_ProjectVersion 验证包版本: 显式指定、取自规格版本以及非语义化版本时的回退。
+func TestEmit_ProjectVersion(t *testing.T) {
+	undated := createSimpleServiceModel()
+	undated.Version = "2024.01"
+	cases := []struct {
+		sm      *genspec.ServiceModel
+		version string
+		want    string
+	}{
+		{createSimpleServiceModel(), "3.1.0", "3.1.0"},
+		{createSimpleServiceModel(), "", "1.0.0"},
+		{undated, "", genspec.DefaultProjectVersion},
+	}
+	for _, tc := range cases {
+		for _, library := range []bool{false, true} {
+			tmpDir := t.TempDir()
+			opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", ProjectVersion: tc.version, Library: library}
+			if _, err := Emit(context.Background(), tc.sm, opts); err != nil {
+				t.Fatalf("Emit failed: %v", err)
+			}
+			checks := map[string]string{
+				"pyproject.toml": `version = "` + tc.want + `"`,
+				filepath.Join("src", "complex_api_tool", "__init__.py"): `__version__ = "` + tc.want + `"`,
+			}
+			if !library {
+				checks["setup.py"] = `version="` + tc.want + `"`
+			}
+			for name, want := range checks {
+				data, err := os.ReadFile(filepath.Join(tmpDir, name))
+				if err != nil || !strings.Contains(string(data), want) {
+					t.Errorf("library=%v: %s missing %s (err=%v)", library, name, want, err)
+				}
+			}
+		}
+	}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), ProjectVersion: "1.0"}); err == nil {
+		t.Fatalf("expected an error for a non-semver ProjectVersion")
+	}
+}
+
+// TestResolveVersions 验证 Python 版本约束与 mcp SDK 版本的规范化和校验。
+func TestResolveVersions(t *testing.T) {
+	cases := []struct {
+		requires, mcp         string
+		wantRequires, wantMCP string
+	}{
+		{"", "", DefaultPythonRequires, ""},
+		{"3.10", "1.9.4", ">=3.10", "==1.9.4"},
+		{" >= 3.9 , < 4 ", ">=1.9, <2", ">=3.9,<4", ">=1.9,<2"},
+		{"~=3.11", "~=1.9", "~=3.11", "~=1.9"},
+	}
+	for _, tc := range cases {
+		requires, mcp, err := ResolveVersions(tc.requires, tc.mcp)
+		if err != nil || requires != tc.wantRequires || mcp != tc.wantMCP {
+			t.Errorf("ResolveVersions(%q, %q) = %q, %q, %v; want %q, %q", tc.requires, tc.mcp, requires, mcp, err, tc.wantRequires, tc.wantMCP)
+		}
+	}
+	for _, bad := range [][2]string{{"3.7", ""}, {"<4", ""}, {"python3", ""}, {">=2.7", ""}, {"", "latest"}, {"", ">=1.9;<2"}} {
+		if _, _, err := ResolveVersions(bad[0], bad[1]); err == nil {
+			t.Errorf("ResolveVersions(%q, %q): expected an error", bad[0], bad[1])
+		}
+	}
+}
+
+// TestEmit_PythonVersions 验证 PythonRequires 与 MCPSDKVersion 一致地写入各打包文件。
+func TestEmit_PythonVersions(t *testing.T) {
+	read := func(dir, rel string) string {
+		data, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", PythonRequires: "3.10", MCPSDKVersion: "1.9.4"}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	wants := map[string][]string{
+		"setup.py":         {`python_requires=">=3.10"`, `"mcp==1.9.4"`, `"Programming Language :: Python :: 3.10"`},
+		"pyproject.toml":   {`requires-python = ">=3.10"`, `"mcp==1.9.4"`, `"Programming Language :: Python :: 3.12"`},
+		"requirements.txt": {"\nmcp==1.9.4\n", "Python 3.10+"},
+		"mypy.ini":         {"python_version = 3.10\n"},
+		"README.md":        {"Python 3.10 或更高版本\n"},
+	}
+	for rel, ws := range wants {
+		content := read(tmpDir, rel)
+		for _, want := range ws {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q", rel, want)
+			}
+		}
+		if strings.Contains(content, "Python :: 3.9") || strings.Contains(content, ">=3.8") {
+			t.Errorf("%s still lists an older Python version:\n%s", rel, content)
+		}
+	}
+
+	// Poetry 只有下限时写成 ^3.x，带上限的约束原样使用；未设置 MCPSDKVersion 时不添加 mcp 依赖。
+	opts = Options{OutDir: t.TempDir(), ToolName: "complex-api-tool", BuildTool: BuildToolPoetry, PythonRequires: ">=3.10", MCPSDKVersion: ">=1.9,<2"}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pyproject := read(opts.OutDir, "pyproject.toml")
+	for _, want := range []string{`python = "^3.10"`, `mcp = ">=1.9,<2"`} {
+		if !strings.Contains(pyproject, want) {
+			t.Errorf("poetry pyproject.toml missing %q", want)
+		}
+	}
+	opts = Options{OutDir: t.TempDir(), ToolName: "complex-api-tool", BuildTool: BuildToolPoetry, PythonRequires: ">=3.10,<3.13"}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pyproject = read(opts.OutDir, "pyproject.toml")
+	if !strings.Contains(pyproject, `python = ">=3.10,<3.13"`) || strings.Contains(pyproject, "mcp =") {
+		t.Errorf("unexpected poetry dependencies:\n%s", pyproject)
+	}
+	if readme := read(opts.OutDir, "README.md"); !strings.Contains(readme, "Python 3.10 或更高版本（`>=3.10,<3.13`）") {
+		t.Errorf("README should show a requirement with an upper bound")
+	}
+
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), PythonRequires: "3.7"}); err == nil {
+		t.Fatalf("expected an error for Python 3.7")
+	}
+}
+
+func TestResolvePythonVersion(t *testing.T) {
+	cases := []struct {
+		version, requires         string
+		wantVersion, wantRequires string
+	}{
+		{"", "", DefaultPythonVersion, DefaultPythonRequires},
+		{"3.11", "", "3.11", ">=3.11"},
+		{" 3.12 ", "", "3.12", ">=3.12"},
+		{"", "3.10", "3.10", ">=3.10"},
+		{"", ">=3.10,<4", "3.10", ">=3.10,<4"},
+		{"3.10", "~=3.10", "3.10", "~=3.10"},
+	}
+	for _, tc := range cases {
+		version, requires, err := ResolvePythonVersion(tc.version, tc.requires)
+		if err != nil || version != tc.wantVersion || requires != tc.wantRequires {
+			t.Errorf("ResolvePythonVersion(%q, %q) = %q, %q, %v; want %q, %q", tc.version, tc.requires, version, requires, err, tc.wantVersion, tc.wantRequires)
+		}
+	}
+	for _, bad := range [][2]string{{"3", ""}, {"3.11.2", ""}, {"py311", ""}, {"2.7", ""}, {"3.7", ""}, {"3.11", ">=3.10"}} {
+		if _, _, err := ResolvePythonVersion(bad[0], bad[1]); err == nil {
+			t.Errorf("ResolvePythonVersion(%q, %q): expected an error", bad[0], bad[1])
+		}
+	}
+}
+
+// TestEmit_PythonVersion 验证 PythonVersion 同时决定 requires-python 与 mypy、black、ruff、
+// pyupgrade、vermin (含 pre-commit 钩子) 的目标版本。
+func TestEmit_PythonVersion(t *testing.T) {
+	for _, useRuff := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", PythonVersion: "3.11", UseRuff: useRuff}
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		wants := map[string][]string{
+			"pyproject.toml":          {`requires-python = ">=3.11"`, "target-version = ['py311']", "py311-plus = true", `targets = ["3.11-"]`},
+			"mypy.ini":                {"python_version = 3.11\n"},
+			"Makefile":                {"pyupgrade --py311-plus", "vermin -t=3.11- "},
+			".pre-commit-config.yaml": {"args: [--py311-plus]", `"-t=3.11-"`},
+		}
+		if useRuff {
+			wants["ruff.toml"] = []string{`target-version = "py311"`}
+		}
+		for rel, ws := range wants {
+			data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+			if err != nil {
+				t.Fatalf("read %s: %v", rel, err)
+			}
+			for _, want := range ws {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%s (ruff=%v) missing %q", rel, useRuff, want)
+				}
+			}
+			if strings.Contains(string(data), "py39") || strings.Contains(string(data), "3.9-") {
+				t.Errorf("%s (ruff=%v) still targets the default Python version", rel, useRuff)
+			}
+		}
+	}
+
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), PythonVersion: "3.11", PythonRequires: ">=3.10"}); err == nil {
+		t.Fatalf("expected an error for a PythonVersion below the PythonRequires lower bound")
+	}
+}
+
+// TestEmit_UseRuff 验证 UseRuff 时以 ruff.toml 取代 .pylintrc，并同步更新依赖、Makefile 与 pre-commit。
+func TestEmit_UseRuff(t *testing.T) {
+	for _, useRuff := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "complex-api-tool", UseRuff: useRuff}
+		res, err := Emit(context.Background(), createSimpleServiceModel(), opts)
+		if err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		planned := map[string]bool{}
+		for _, pf := range res.Planned {
+			planned[pf.RelPath] = true
+		}
+		if planned["ruff.toml"] != useRuff || planned[".pylintrc"] == useRuff {
+			t.Fatalf("UseRuff=%v: ruff.toml planned=%v, .pylintrc planned=%v", useRuff, planned["ruff.toml"], planned[".pylintrc"])
+		}
+		linter := map[bool]string{false: "pylint", true: "ruff"}[useRuff]
+		other := map[bool]string{false: "ruff", true: "pylint"}[useRuff]
+		for _, rel := range []string{"requirements-dev.txt", "Makefile", ".pre-commit-config.yaml"} {
+			data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+			if err != nil {
+				t.Fatalf("read %s: %v", rel, err)
+			}
+			if !strings.Contains(string(data), linter) || strings.Contains(string(data), other) {
+				t.Errorf("UseRuff=%v: %s should use %s only:\n%s", useRuff, rel, linter, data)
+			}
+		}
+		if !useRuff {
+			continue
+		}
+		makefile, _ := os.ReadFile(filepath.Join(tmpDir, "Makefile"))
+		if !strings.Contains(string(makefile), "\truff check src/ tests/\n") {
+			t.Errorf("Makefile lint target should run ruff check:\n%s", makefile)
+		}
+		ruff, _ := os.ReadFile(filepath.Join(tmpDir, "ruff.toml"))
+		for _, want := range []string{`select = ["E", "F", "I", "N", "UP", "ANN"]`, `"ANN101", "ANN102"`, `known-first-party = ["complex_api_tool"]`} {
+			if !strings.Contains(string(ruff), want) {
+				t.Errorf("ruff.toml missing %q:\n%s", want, ruff)
+			}
+		}
+	}
+}
+
+// TestEmit_HTTPTransport 验证 main.py 的 --transport 参数与 transport.py 的 HTTP 传输。
+func TestEmit_HTTPTransport(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: tmpDir, ToolName: "transport", PackageName: "transport_tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pkg := filepath.Join(tmpDir, "src", "transport_tool")
+	wants := map[string][]string{
+		filepath.Join(pkg, "main.py"):                       {`"--transport"`, "choices=TRANSPORTS", "serve_http(server, host, port)", "server.run_stdio()"},
+		filepath.Join(pkg, "transport.py"):                  {`TRANSPORTS = ("stdio", "http")`, `env.get("MCP_HTTP_ADDR", "")`, `env.get("PORT", "")`, "ThreadingHTTPServer"},
+		filepath.Join(tmpDir, "tests", "test_transport.py"): {"def test_http_round_trip("},
+		filepath.Join(tmpDir, "README.md"):                  {"--transport http", "MCP_HTTP_ADDR"},
+	}
+	for path, ws := range wants {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		for _, want := range ws {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s missing %q", path, want)
+			}
+		}
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `import json, threading, urllib.request
+from transport_tool.main import parse_args
+from transport_tool.server import MCPServer
+from transport_tool.transport import create_http_server, http_address
+print(parse_args([]).transport, parse_args(["--transport", "http"]).transport)
+print(http_address({}), http_address({"PORT": "9000"}), http_address({"PORT": "9000", "MCP_HTTP_ADDR": "localhost:7000"}))
+httpd = create_http_server(MCPServer(tool_name="transport"), "127.0.0.1", 0)
+threading.Thread(target=httpd.serve_forever, daemon=True).start()
+body = json.dumps({"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {}}).encode()
+url = "http://127.0.0.1:%d/mcp" % httpd.server_address[1]
+with urllib.request.urlopen(urllib.request.Request(url, data=body), timeout=5) as resp:
+    print(resp.status, json.loads(resp.read())["result"]["serverInfo"]["name"])
+httpd.shutdown()
+`
+	out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, nil, "python3", "-c", script)
+	if err != nil {
+		t.Fatalf("python3 failed: %v\n%s", err, out)
+	}
+	want := "stdio http\n('127.0.0.1', 8080) ('0.0.0.0', 9000) ('localhost', 7000)\n200 transport\n"
+	if out != want {
+		t.Fatalf("output = %q, want %q", out, want)
+	}
+}
+
+// TestEmit_Describe 验证 --describe 与 python -m 包名 的入口, 以及模型哈希在分片时同样覆盖全部模型文件。
+func TestEmit_Describe(t *testing.T) {
+	for _, shard := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "describe", PackageName: "describe_tool", ShardModelByTag: shard}
+		if _, err := Emit(context.Background(), createComplexServiceModel(), opts); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		pkg := filepath.Join(tmpDir, "src", "describe_tool")
+		wants := map[string][]string{
+			filepath.Join(pkg, "__main__.py"):                  {"from .main import main"},
+			filepath.Join(pkg, "main.py"):                      {`"--describe"`, "server.describe()"},
+			filepath.Join(pkg, "server.py"):                    {"def describe(self)", "model_hash()"},
+			filepath.Join(pkg, "spec", "loader.py"):            {"def model_hash() -> str:", `"model_hash",`},
+			filepath.Join(tmpDir, "tests", "test_describe.py"): {"def test_describe(", `main(["--describe"])`},
+			filepath.Join(tmpDir, "README.md"):                 {"python -m describe_tool --describe"},
+		}
+		for path, ws := range wants {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read %s: %v", path, err)
+			}
+			for _, want := range ws {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("shard=%v: %s missing %q", shard, path, want)
+				}
+			}
+		}
+
+		// 模型哈希是模型文件按名称顺序拼接后的 SHA-256
+		var model []byte
+		if shard {
+			entries, err := os.ReadDir(filepath.Join(pkg, "spec", "model"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range entries {
+				data, err := os.ReadFile(filepath.Join(pkg, "spec", "model", e.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				model = append(model, data...)
+			}
+		} else {
+			data, err := os.ReadFile(filepath.Join(pkg, "spec", "model.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			model = data
+		}
+
+		if _, err := exec.LookPath("python3"); err != nil {
+			t.Skip("python3 not available")
+		}
+		out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, nil, "python3", "-m", "describe_tool", "--describe")
+		if err != nil {
+			t.Fatalf("shard=%v: python3 -m describe_tool --describe failed: %v\n%s", shard, err, out)
+		}
+		var desc struct {
+			Title     string `json:"title"`
+			Endpoints int    `json:"endpoints"`
+			ModelHash string `json:"modelHash"`
+			Tools     []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		}
+		if err := json.Unmarshal([]byte(out), &desc); err != nil {
+			t.Fatalf("shard=%v: 输出不是 JSON: %v\n%s", shard, err, out)
+		}
+		sm := createComplexServiceModel()
+		if desc.Title != sm.Title || desc.Endpoints != len(sm.Endpoints) {
+			t.Errorf("shard=%v: title=%q endpoints=%d, 应为 %q 与 %d", shard, desc.Title, desc.Endpoints, sm.Title, len(sm.Endpoints))
+		}
+		if want := manifest.HashBytes(model); desc.ModelHash != want {
+			t.Errorf("shard=%v: modelHash = %s, 应为 %s", shard, desc.ModelHash, want)
+		}
+		if len(desc.Tools) != 6 || desc.Tools[0].Name != "explainParameter" {
+			t.Errorf("shard=%v: tools = %+v, 应为按名称排序的 6 个工具", shard, desc.Tools)
+		}
+	}
+}
+
+// TestEmit_PinDependencies 验证 PinDependencies 为每个依赖写入精确版本。
+func TestEmit_PinDependencies(t *testing.T) {
+	for _, buildTool := range []string{BuildToolSetuptools, BuildToolUV, BuildToolPoetry} {
+		for _, pinned := range []bool{false, true} {
+			tmpDir := t.TempDir()
+			opts := Options{OutDir: tmpDir, ToolName: "pinned", BuildTool: buildTool, PinDependencies: pinned, GenerateFastAPI: true}
+			if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+				t.Fatalf("Emit failed: %v", err)
+			}
+			var wants []string
+			switch {
+			case buildTool == BuildToolPoetry && pinned:
+				wants = []string{`dataclasses-json = "0.6.7"`, `pytest = "7.4.4"`, `fastapi = "0.115.0"`}
+			case buildTool == BuildToolPoetry:
+				wants = []string{`dataclasses-json = ">=0.6.0"`, `pytest = ">=7.4.0"`}
+			case pinned:
+				wants = []string{"dataclasses-json==0.6.7", "pytest==7.4.4", "fastapi==0.115.0"}
+			default:
+				wants = []string{"dataclasses-json>=0.6.0", "pytest>=7.4.0"}
+			}
+			var all string
+			for _, rel := range []string{"pyproject.toml", "setup.py", "requirements.txt", "requirements-dev.txt"} {
+				data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+				if err == nil {
+					all += string(data)
+				}
+			}
+			if strings.Contains(all, "Error rendering template") {
+				t.Fatalf("%s pinned=%v: template error:\n%s", buildTool, pinned, all)
+			}
+			for _, want := range wants {
+				if !strings.Contains(all, want) {
+					t.Errorf("%s pinned=%v: missing %q", buildTool, pinned, want)
+				}
+			}
+			if !pinned {
+				continue
+			}
+			for name := range pythonDependencies {
+				for _, bad := range []string{name + ">=", name + " = \">=", name + "~="} {
+					if strings.Contains(all, bad) {
+						t.Errorf("%s: %s is not pinned (found %q)", buildTool, name, bad)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestEmit_EnableInvoke 验证 EnableInvoke 生成 callEndpoint 工具及其测试, 并在本地桩服务器上调用。
+func TestEmit_EnableInvoke(t *testing.T) {
+	sm := createSimpleServiceModel()
+	sm.Endpoints = append(sm.Endpoints, genspec.EndpointModel{
+		ID: "get /pets/{petId}", Method: genspec.GET, Path: "/pets/{petId}",
+		Parameters: []genspec.ParameterModel{
+			{Name: "petId", In: "path", Required: true},
+			{Name: "fields", In: "query"},
+		},
+	})
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), sm, Options{OutDir: tmpDir, ToolName: "invoke", PackageName: "invoke_tool", EnableInvoke: true}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pkg := filepath.Join(tmpDir, "src", "invoke_tool")
+	wants := map[string][]string{
+		filepath.Join(pkg, "mcp", "methods", "call_endpoint.py"): {"def call_endpoint(", "def build_request(", `CALL_TIMEOUT_ENV = "API_TIMEOUT"`, `CALL_RETRIES_ENV = "API_RETRIES"`, "from .explain_parameter import find_endpoint, not_found"},
+		filepath.Join(pkg, "server.py"):                          {`"callEndpoint": self._handle_call_endpoint`, "    call_endpoint,\n    explain_parameter,"},
+		filepath.Join(tmpDir, "tests", "test_call_endpoint.py"):  {"def test_parameter_substitution(", "def test_validation_before_sending("},
+		filepath.Join(tmpDir, "README.md"):                       {"**callEndpoint**", "API_TIMEOUT", "API_RETRIES"},
+	}
+	for path, ws := range wants {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		for _, want := range ws {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s missing %q", path, want)
+			}
+		}
+	}
+
+	plainDir := t.TempDir()
+	if _, err := Emit(context.Background(), sm, Options{OutDir: plainDir, ToolName: "invoke", PackageName: "invoke_tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(plainDir, "src", "invoke_tool", "mcp", "methods", "call_endpoint.py")); !os.IsNotExist(err) {
+		t.Errorf("call_endpoint.py written without EnableInvoke (err=%v)", err)
+	}
+	server, _ := os.ReadFile(filepath.Join(plainDir, "src", "invoke_tool", "server.py"))
+	if strings.Contains(string(server), "callEndpoint") {
+		t.Errorf("server.py registers callEndpoint without EnableInvoke")
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `import threading
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+from invoke_tool.mcp.methods import call_endpoint
+from invoke_tool.spec.loader import load_service_model
+
+class Stub(BaseHTTPRequestHandler):
+    def do_GET(self):
+        self.send_response(418)
+        self.end_headers()
+        self.wfile.write(self.path.encode())
+    def log_message(self, *args):
+        pass
+
+httpd = ThreadingHTTPServer(("127.0.0.1", 0), Stub)
+threading.Thread(target=httpd.serve_forever, daemon=True).start()
+base = "http://127.0.0.1:%d/v1" % httpd.server_address[1]
+model = load_service_model()
+args = call_endpoint.CallArgs("get /pets/{petId}", {"petId": "a b", "fields": ["x", "y"]})
+result = call_endpoint.call_endpoint(model, args, base)
+print(result.status, result.body)
+for bad in (call_endpoint.CallArgs("get /pets/{petId}"), call_endpoint.CallArgs("get /pets/{petId}", {"petId": 1, "nope": 2})):
+    try:
+        call_endpoint.call_endpoint(model, bad, base)
+    except call_endpoint.CallError as exc:
+        print(exc)
+httpd.shutdown()
+`
+	out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, nil, "python3", "-c", script)
+	if err != nil {
+		t.Fatalf("python3 failed: %v\n%s", err, out)
+	}
+	want := "418 /v1/pets/a%20b?fields=x&fields=y\n" +
+		"get /pets/{petId}: missing required parameters: petId (path)\n" +
+		"parameter \"nope\" not found on get /pets/{petId}; did you mean: petId, fields\n"
+	if out != want {
+		t.Fatalf("output = %q, want %q", out, want)
+	}
+}
+
+// TestEmit_InvokeCredentials 验证 callEndpoint 按端点的安全要求从环境变量读取凭据。
+func TestEmit_InvokeCredentials(t *testing.T) {
+	sm := createSimpleServiceModel()
+	sm.SecuritySchemes = map[string]genspec.SecurityScheme{
+		"key":    {Name: "key", Type: "apiKey", In: "query", ParamName: "api_key"},
+		"bearer": {Name: "bearer", Type: "http", Scheme: "bearer"},
+	}
+	sm.Endpoints[0].Security = [][]string{{"key"}, {"bearer"}}
+	tmpDir := t.TempDir()
+	if _, err := Emit(context.Background(), sm, Options{OutDir: tmpDir, ToolName: "invoke", PackageName: "invoke_tool", EnableInvoke: true}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pkg := filepath.Join(tmpDir, "src", "invoke_tool")
+	wants := map[string][]string{
+		filepath.Join(pkg, "mcp", "methods", "call_endpoint.py"): {`    "bearer": Credential("bearer", "header", "Authorization", "INVOKE_BEARER_TOKEN"),`, `    "key": Credential("apiKey", "query", "api_key", "INVOKE_API_KEY"),`},
+		filepath.Join(pkg, "spec", "model.py"):                   {"security: List[List[str]]"},
+		filepath.Join(tmpDir, "tests", "test_call_endpoint.py"):  {"def test_credentials(", "def test_missing_credentials("},
+		filepath.Join(tmpDir, "README.md"):                       {"## 认证", "- `INVOKE_API_KEY`: 安全方案 `key`，作为查询参数 `api_key`", "- `INVOKE_BEARER_TOKEN`: 安全方案 `bearer`，作为 `Authorization: Bearer <token>` 请求头"},
+	}
+	for path, ws := range wants {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		for _, want := range ws {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s missing %q", path, want)
+			}
+		}
+	}
+
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	script := `import os
+from invoke_tool.mcp.methods import call_endpoint
+from invoke_tool.spec.loader import load_service_model
+
+model = load_service_model()
+args = call_endpoint.CallArgs(model.endpoints[0].id)
+try:
+    call_endpoint.build_request(model, args, "http://stub")
+except call_endpoint.CallError as exc:
+    print(exc)
+os.environ["INVOKE_BEARER_TOKEN"] = "token"
+print(call_endpoint.build_request(model, args, "http://stub").headers["Authorization"])
+os.environ["INVOKE_API_KEY"] = "k y"
+request = call_endpoint.build_request(model, args, "http://stub")
+print(request.url, "Authorization" in request.headers)
+`
+	out, err := runInDir(filepath.Join(tmpDir, "src"), time.Minute, []string{"INVOKE_API_KEY=", "INVOKE_BEARER_TOKEN="}, "python3", "-c", script)
+	if err != nil {
+		t.Fatalf("python3 failed: %v\n%s", err, out)
+	}
+	id := sm.Endpoints[0].ID
+	want := id + ": missing credentials: set INVOKE_API_KEY or INVOKE_BEARER_TOKEN\n" +
+		"Bearer token\n" +
+		"http://stub" + sm.Endpoints[0].Path + "?api_key=k+y False\n"
+	if out != want {
+		t.Fatalf("output = %q, want %q", out, want)
+	}
+}
+
+func TestEmit_TemplateOverrideDir(t *testing.T) {
+	tmplDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmplDir, "README.md"), []byte("# {{.ToolName}} ({{.PackageName}}) for {{.ServiceTitle}}\n"), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	pkgDir := filepath.Join(tmplDir, "src", "override_tool")
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(pkgDir, "server.py"), []byte("# 自定义 {{.ToolName}} 服务\n"), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "override-tool", PackageName: "override_tool", TemplateOverrideDir: tmplDir}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	readme, _ := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	if string(readme) != "# override-tool (override_tool) for Simple API\n" {
+		t.Fatalf("README 应来自覆盖模板:\n%s", readme)
+	}
+	server, _ := os.ReadFile(filepath.Join(tmpDir, "src", "override_tool", "server.py"))
+	if string(server) != "# 自定义 override-tool 服务\n" {
+		t.Fatalf("server.py 应来自覆盖模板:\n%s", server)
+	}
+	// 没有覆盖的文件仍使用内置模板
+	mainPy, _ := os.ReadFile(filepath.Join(tmpDir, "src", "override_tool", "main.py"))
+	if len(mainPy) == 0 {
+		t.Fatalf("main.py 应使用内置模板")
+	}
+
+	if err := os.WriteFile(filepath.Join(tmplDir, "README.md"), []byte("{{.Missing"), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	opts.OutDir = t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err == nil || !strings.Contains(err.Error(), "README.md") {
+		t.Fatalf("覆盖模板语法错误时应报错, got %v", err)
+	}
+	opts.TemplateOverrideDir = filepath.Join(tmplDir, "missing")
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err == nil || !strings.Contains(err.Error(), "template override") {
+		t.Fatalf("覆盖目录不存在时应报错, got %v", err)
+	}
+}
+
+func TestEmit_TemplateContextProbe(t *testing.T) {
+	probe, err := os.ReadFile(filepath.Join("..", "testdata", "template_context.tmpl"))
+	if err != nil {
+		t.Fatalf("read probe: %v", err)
+	}
+	tmplDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmplDir, "README.md"), probe, 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "probe-tool", PackageName: "probe_tool", BuildTool: BuildToolUV, UseRuff: true, TemplateOverrideDir: tmplDir,
+		Provenance: &manifest.Provenance{Generator: "1.2.3", Input: "spec.yaml"}}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	readme, _ := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	for _, want := range []string{
+		"schema 1 lang python\n",
+		"tool probe-tool package probe_tool title Simple API",
+		"input spec.yaml generator 1.2.3\n",
+		"python " + DefaultPythonVersion + " requires >=" + DefaultPythonVersion + " build uv mcp  ruff true fastapi false\n",
+	} {
+		if !strings.Contains(string(readme), want) {
+			t.Errorf("渲染后的探针缺少 %q:\n%s", want, readme)
+		}
+	}
+}
+
+func TestEmit_ExcludeFiles(t *testing.T) {
+	opts := Options{OutDir: t.TempDir(), ToolName: "exclude-tool", PackageName: "exclude_tool", DryRun: true, ExcludeFiles: []string{".pylintrc", "mypy.ini", ".vscode/*"}}
+	res, err := Emit(context.Background(), createSimpleServiceModel(), opts)
+	if err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if len(res.Planned) == 0 {
+		t.Fatalf("计划中没有文件")
+	}
+	for _, p := range res.Planned {
+		if p.RelPath == ".pylintrc" || p.RelPath == "mypy.ini" || strings.HasPrefix(p.RelPath, ".vscode/") {
+			t.Fatalf("被排除的文件 %s 仍在计划中", p.RelPath)
+		}
+	}
+
+	for _, pattern := range []string{"pyproject.toml", "README.md", "*.py"} {
+		opts := Options{OutDir: t.TempDir(), ToolName: "exclude-tool", PackageName: "exclude_tool", ExcludeFiles: []string{pattern}}
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err == nil || !strings.Contains(err.Error(), "which the project needs to build") {
+			t.Fatalf("排除 %q 时应报错, got %v", pattern, err)
+		}
+	}
+}
+
+// TestEmit_Skip 验证每个类别从计划中去掉对应的文件, 且其余文件不再引用它们。
+func TestEmit_Skip(t *testing.T) {
+	planned := func(skip ...string) map[string]bool {
+		t.Helper()
+		opts := Options{OutDir: t.TempDir(), ToolName: "skip-tool", PackageName: "skip_tool", DryRun: true, Skip: skip}
+		res, err := Emit(context.Background(), createSimpleServiceModel(), opts)
+		if err != nil {
+			t.Fatalf("Emit %v failed: %v", skip, err)
+		}
+		out := map[string]bool{}
+		for _, p := range res.Planned {
+			out[p.RelPath] = true
+		}
+		return out
+	}
+	all := planned()
+	for category, want := range map[string][]string{
+		"tests":    {"tests/__init__.py", "tests/test_describe.py", "tests/test_mcp_methods.py", "tests/test_transport.py"},
+		"lint":     {".flake8", ".pylintrc", "mypy.ini", ".pre-commit-config.yaml"},
+		"makefile": {"Makefile"},
+		"readme":   {"README.md"},
+		"editor":   {".editorconfig", ".vscode/launch.json"},
+	} {
+		got := planned(category)
+		for _, rel := range want {
+			if !all[rel] || got[rel] {
+				t.Fatalf("跳过 %s: %s 在默认计划中为 %v, 跳过后为 %v", category, rel, all[rel], got[rel])
+			}
+		}
+		if len(got) != len(all)-len(want) {
+			t.Fatalf("跳过 %s: 计划了 %d 个文件, 应为 %d: %v", category, len(got), len(all)-len(want), got)
+		}
+	}
+
+	read := func(dir, rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("读取 %s 失败: %v", rel, err)
+		}
+		return string(data)
+	}
+	dir := t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: dir, ToolName: "skip-tool", PackageName: "skip_tool", Skip: []string{"tests", "lint"}}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	mk := read(dir, "Makefile")
+	for _, unwanted := range []string{"pytest ", "flake8", "pylint", "mypy src", "\ntest:", "\nlint:", "\ntypecheck:"} {
+		if strings.Contains(mk, unwanted) {
+			t.Fatalf("Makefile 仍引用被省略的文件 (%q):\n%s", unwanted, mk)
+		}
+	}
+	if launch := read(dir, ".vscode/launch.json"); strings.Contains(launch, "pytest") {
+		t.Fatalf("launch.json 仍包含 pytest 配置:\n%s", launch)
+	}
+
+	for _, tool := range []string{BuildToolSetuptools, BuildToolUV, BuildToolPoetry} {
+		dir := t.TempDir()
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: dir, ToolName: "skip-tool", PackageName: "skip_tool", BuildTool: tool, Skip: []string{"readme", "makefile"}}); err != nil {
+			t.Fatalf("%s: Emit failed: %v", tool, err)
+		}
+		if pyproject := read(dir, "pyproject.toml"); strings.Contains(pyproject, "README") {
+			t.Errorf("%s: pyproject.toml 仍引用 README.md:\n%s", tool, pyproject)
+		}
+		if tool == BuildToolSetuptools {
+			if setup := read(dir, "setup.py"); strings.Contains(setup, "README") {
+				t.Errorf("setup.py 仍读取 README.md:\n%s", setup)
+			}
+		}
+	}
+
+	// 跳过 lint 时 CI 只剩 test 任务; CI 依赖 Makefile, 且至少需要一个任务
+	dir = t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: dir, ToolName: "skip-tool", GenerateCI: true, Skip: []string{"lint"}}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	var workflow struct {
+		Jobs map[string]any `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal([]byte(read(dir, ".github/workflows/ci.yml")), &workflow); err != nil {
+		t.Fatalf("ci.yml 不是合法的 YAML: %v", err)
+	}
+	if len(workflow.Jobs) != 1 || workflow.Jobs["test"] == nil {
+		t.Fatalf("跳过 lint 时 CI 应只有 test 任务, got %v", workflow.Jobs)
+	}
+	for skip, want := range map[string][]string{
+		"cannot be combined with skipping makefile": {"makefile"},
+		"no jobs left": {"lint", "tests"},
+	} {
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), ToolName: "skip-tool", GenerateCI: true, Skip: want}); err == nil || !strings.Contains(err.Error(), skip) {
+			t.Fatalf("GenerateCI 与跳过 %v 应报错 %q, got %v", want, skip, err)
+		}
+	}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), ToolName: "skip-tool", Skip: []string{"docs"}}); err == nil || !strings.Contains(err.Error(), "unknown skip category") {
+		t.Fatalf("未知类别应报错, got %v", err)
+	}
+}
+
+func TestEmit_Dockerfile(t *testing.T) {
+	planned := func(opts Options) map[string]bool {
+		t.Helper()
+		opts.OutDir, opts.ToolName, opts.PackageName, opts.DryRun = t.TempDir(), "docker-tool", "docker_tool", true
+		res, err := Emit(context.Background(), createSimpleServiceModel(), opts)
+		if err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		out := map[string]bool{}
+		for _, p := range res.Planned {
+			out[p.RelPath] = true
+		}
+		return out
+	}
+	if got := planned(Options{}); got["Dockerfile"] || got[".dockerignore"] {
+		t.Fatalf("未设置 EmitDockerfile 时不应生成 Docker 文件: %v", got)
+	}
+	if got := planned(Options{EmitDockerfile: true}); !got["Dockerfile"] || !got[".dockerignore"] {
+		t.Fatalf("设置 EmitDockerfile 时应生成 Docker 文件: %v", got)
+	}
+	if got := planned(Options{EmitDockerfile: true, Library: true}); got["Dockerfile"] {
+		t.Fatalf("库布局不应生成 Dockerfile: %v", got)
+	}
+
+	tmpDir := t.TempDir()
+	opts := Options{OutDir: tmpDir, ToolName: "docker-tool", PackageName: "docker_tool", PythonVersion: "3.11", BuildTool: BuildToolPoetry, EmitDockerfile: true}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	for rel, wants := range map[string][]string{
+		"Dockerfile":    {"FROM python:3.11-slim AS build", "pip wheel --no-cache-dir --wheel-dir /wheels .", `ENTRYPOINT ["python", "-m", "docker_tool.main"]`},
+		".dockerignore": {".venv\n", "tests\n"},
+		"Makefile":      {"IMAGE ?= docker-tool", "docker-build:\n\tdocker build -t $(IMAGE) .", "docker-run:\n\tdocker run -i --rm $(IMAGE)", "pre-commit docker-build docker-run\n"},
+		"README.md":     {"### Docker", "make docker-run"},
+	} {
+		data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+		if err != nil {
+			t.Fatalf("读取 %s 失败: %v", rel, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(data), want) {
+				t.Fatalf("%s 缺少 %q:\n%s", rel, want, data)
+			}
+		}
+	}
+}
+
+func TestEmit_WithOTel(t *testing.T) {
+	plain := t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: plain, ToolName: "otel-tool", PackageName: "otel_tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	for _, rel := range []string{"setup.py", "pyproject.toml", "mypy.ini", filepath.Join("src", "otel_tool", "server.py")} {
+		data, err := os.ReadFile(filepath.Join(plain, rel))
+		if err != nil {
+			t.Fatalf("读取 %s 失败: %v", rel, err)
+		}
+		if strings.Contains(string(data), "opentelemetry") || strings.Contains(string(data), "trace_tool") {
+			t.Fatalf("未设置 WithOTel 时 %s 不应提及追踪:\n%s", rel, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(plain, "src", "otel_tool", "telemetry.py")); !os.IsNotExist(err) {
+		t.Fatalf("未设置 WithOTel 时不应生成 telemetry.py: %v", err)
+	}
+
+	for _, tool := range []string{BuildToolSetuptools, BuildToolUV, BuildToolPoetry} {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "otel-tool", PackageName: "otel_tool", BuildTool: tool, WithOTel: true}
+		if _, err := Emit(context.Background(), createSimpleServiceModel(), opts); err != nil {
+			t.Fatalf("Emit failed (%s): %v", tool, err)
+		}
+		wants := map[string][]string{
+			"pyproject.toml": {`otel = ["opentelemetry-api>=1.20.0"]`},
+			"mypy.ini":       {"[mypy-opentelemetry.*]\nignore_missing_imports = True"},
+			filepath.Join("src", "otel_tool", "server.py"):    {"from .telemetry import trace_tool", "name: trace_tool(name, handler) for name, handler in self.tools.items()"},
+			filepath.Join("src", "otel_tool", "telemetry.py"): {"from opentelemetry import trace", "OTEL_AVAILABLE = False", `f"tools/call {name}"`},
+			filepath.Join("tests", "test_telemetry.py"):       {"def test_tools_without_exporter() -> None:"},
+		}
+		switch tool {
+		case BuildToolSetuptools:
+			wants["setup.py"] = []string{"\"otel\": [\n            \"opentelemetry-api>=1.20.0\",\n        ],"}
+		case BuildToolPoetry:
+			wants["pyproject.toml"] = []string{`opentelemetry-api = { version = ">=1.20.0", optional = true }`, "[tool.poetry.extras]\notel = [\"opentelemetry-api\"]"}
+		}
+		for rel, list := range wants {
+			data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+			if err != nil {
+				t.Fatalf("读取 %s 失败: %v", rel, err)
+			}
+			for _, want := range list {
+				if !strings.Contains(string(data), want) {
+					t.Fatalf("%s (%s) 缺少 %q:\n%s", rel, tool, want, data)
+				}
+			}
+		}
+		for _, rel := range []string{"requirements.txt", "requirements-dev.txt"} {
+			if data, err := os.ReadFile(filepath.Join(tmpDir, rel)); err == nil && strings.Contains(string(data), "opentelemetry") {
+				t.Fatalf("opentelemetry-api 是可选依赖, 不应写入 %s:\n%s", rel, data)
+			}
+		}
+	}
+}
+
+func TestEmit_ShardModelByTag(t *testing.T) {
+	for _, tool := range []string{BuildToolSetuptools, BuildToolUV, BuildToolPoetry} {
+		tmpDir := t.TempDir()
+		opts := Options{OutDir: tmpDir, ToolName: "shard-tool", PackageName: "shard_tool", BuildTool: tool, ShardModelByTag: true}
+		if _, err := Emit(context.Background(), createComplexServiceModel(), opts); err != nil {
+			t.Fatalf("Emit failed (%s): %v", tool, err)
+		}
+		specDir := filepath.Join(tmpDir, "src", "shard_tool", "spec")
+		if _, err := os.Stat(filepath.Join(specDir, "model.json")); !os.IsNotExist(err) {
+			t.Fatalf("设置 ShardModelByTag 时不应生成 model.json: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(specDir, "model", "_index.json")); err != nil {
+			t.Fatalf("缺少分片索引: %v", err)
+		}
+		packaging := "pyproject.toml"
+		want := `"shard_tool.spec" = ["model/*.json"]`
+		switch tool {
+		case BuildToolSetuptools:
+			packaging, want = "setup.py", `"shard_tool.spec": ["model/*.json"],`
+		case BuildToolPoetry:
+			want = `path = "src/shard_tool/spec/model/*.json"`
+		}
+		wants := map[string][]string{
+			packaging: {want},
+			filepath.Join("src", "shard_tool", "spec", "loader.py"): {`MODEL_DIR = Path(__file__).with_name("model")`, "def load_shard(name: str) -> Tuple[EndpointModel, ...]:", "def load_tag(tag: str) -> Tuple[EndpointModel, ...]:"},
+			filepath.Join("tests", "test_mcp_methods.py"):           {"raw = read_model_data()", "def test_shards_cover_model("},
+			"README.md": {"### 模型分片"},
+		}
+		for rel, list := range wants {
+			data, err := os.ReadFile(filepath.Join(tmpDir, rel))
+			if err != nil {
+				t.Fatalf("读取 %s 失败: %v", rel, err)
+			}
+			for _, w := range list {
+				if !strings.Contains(string(data), w) {
+					t.Errorf("%s (%s) 缺少 %q", rel, tool, w)
+				}
+			}
+		}
+	}
+
+	lib := t.TempDir()
+	if _, err := Emit(context.Background(), createComplexServiceModel(), Options{OutDir: lib, ToolName: "shard-tool", PackageName: "shard_tool", Library: true, ShardModelByTag: true}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	pyproject, _ := os.ReadFile(filepath.Join(lib, "pyproject.toml"))
+	if !strings.Contains(string(pyproject), `"shard_tool.spec" = ["model/*.json"]`) {
+		t.Fatalf("库布局应打包模型分片:\n%s", pyproject)
+	}
+	if readme, _ := os.ReadFile(filepath.Join(lib, "README.md")); !strings.Contains(string(readme), "load_tag()") {
+		t.Fatalf("库布局 README 应说明模型分片:\n%s", readme)
+	}
+}
+
+func TestEmit_TemplateOverrideDirNamed(t *testing.T) {
+	// 每个由模板渲染的文件都已注册, 每个注册的名字也都由某组选项生成
+	registered := map[string]bool{}
+	for _, name := range TemplateNames() {
+		registered[name] = false
+	}
+	for _, opts := range []Options{
+		{},
+		{BuildTool: BuildToolUV, UseRuff: true, GenerateCI: true, GenerateFastAPI: true, EnableInvoke: true, EmitDockerfile: true, WithOTel: true, License: "MIT"},
+		{BuildTool: BuildToolPoetry, GenerateCI: true, CIProvider: CIProviderGitLab},
+	} {
+		opts.OutDir, opts.ToolName, opts.PackageName, opts.DryRun = t.TempDir(), "tmpl-tool", "tmpl_tool", true
+		res, err := Emit(context.Background(), createSimpleServiceModel(), opts)
+		if err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		for _, f := range res.Planned {
+			name := strings.Replace(filepath.ToSlash(f.RelPath), "src/tmpl_tool/", "src/PACKAGE/", 1)
+			if strings.HasSuffix(name, "/model.json") {
+				continue
+			}
+			if _, ok := registered[name]; !ok {
+				t.Errorf("%s 未在 templateNames 中注册", name)
+			}
+			registered[name] = true
+		}
+	}
+	for name, seen := range registered {
+		if !seen {
+			t.Errorf("templateNames 中的 %s 未被任何测试的选项生成", name)
+		}
+	}
+
+	tmpl := t.TempDir()
+	for rel, text := range map[string]string{
+		"python/README.md.tmpl":               "# {{.ServiceTitle}}\n",
+		"python/src/PACKAGE/__init__.py.tmpl": "\"\"\"Corporate header.\"\"\"\n\n__version__ = \"{{.Version}}\"\n",
+		"npm/README.md.tmpl":                  "# not for python\n",
+	} {
+		p := filepath.Join(tmpl, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatalf("写入覆盖模板失败: %v", err)
+		}
+	}
+	plain, dir := t.TempDir(), t.TempDir()
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: plain, ToolName: "tmpl-tool", PackageName: "tmpl_tool"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if _, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: dir, ToolName: "tmpl-tool", PackageName: "tmpl_tool", TemplateOverrideDir: tmpl}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	for rel, want := range map[string]string{
+		"README.md": "# Simple API\n",
+		filepath.Join("src", "tmpl_tool", "__init__.py"): "\"\"\"Corporate header.\"\"\"\n\n__version__ = \"1.0.0\"\n",
+	} {
+		if got, _ := os.ReadFile(filepath.Join(dir, rel)); string(got) != want {
+			t.Fatalf("%s 应来自覆盖模板:\n%s", rel, got)
+		}
+	}
+	for _, rel := range []string{"Makefile", filepath.Join("src", "tmpl_tool", "server.py")} {
+		want, _ := os.ReadFile(filepath.Join(plain, rel))
+		if got, _ := os.ReadFile(filepath.Join(dir, rel)); string(got) != string(want) {
+			t.Fatalf("没有覆盖模板的 %s 应保持内置内容", rel)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpl, "python", "server.py.tmpl"), nil, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := Emit(context.Background(), createSimpleServiceModel(), Options{OutDir: t.TempDir(), ToolName: "tmpl-tool", PackageName: "tmpl_tool", TemplateOverrideDir: tmpl, DryRun: true})
+	if err == nil || !strings.Contains(err.Error(), "unknown template override python/server.py.tmpl") || !strings.Contains(err.Error(), "src/PACKAGE/server.py.tmpl") {
+		t.Fatalf("应报错并列出可用的模板名, got %v", err)
+	}
+}
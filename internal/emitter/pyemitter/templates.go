@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/mark3labs/swagger2mcp/internal/emitter"
+	"github.com/mark3labs/swagger2mcp/internal/emitter/license"
 	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
 )
 
-// TemplateData 包含所有模板需要的变量
+// TemplateData 包含所有内置模板需要的变量; 用户的覆盖模板 (TemplateOverrideDir)
+// 不使用它, 而以 emitter.TemplateContext 渲染
 type TemplateData struct {
 	ToolName     string                `json:"tool_name"`     // 工具名称
 	PackageName  string                `json:"package_name"`  // Python包名
@@ -17,6 +21,187 @@ type TemplateData struct {
 	ServiceModel *genspec.ServiceModel `json:"service_model"` // 服务模型
 	Version      string                `json:"version"`       // 版本号
 	Author       string                `json:"author"`        // 作者信息
+	AuthorEmail  string                `json:"author_email"`  // 作者邮箱, 可为空
+	License      string                `json:"license"`       // LICENSE 的 SPDX 标识符, 未生成 LICENSE 时为空
+	BuildTool    string                `json:"build_tool"`    // 构建工具: setuptools、uv 或 poetry
+	Year         int                   `json:"year"`          // LICENSE 中的版权年份
+
+	PythonRequires string   `json:"python_requires"`  // requires-python 版本约束, 如 ">=3.9"
+	PythonVersion  string   `json:"python_version"`   // 目标 (亦即支持的最低) Python 版本, 如 "3.9"
+	PythonTarget   string   `json:"python_target"`    // black、ruff 与 pyupgrade 的目标版本写法, 如 "py39"
+	PythonVersions []string `json:"python_versions"`  // 分类器中列出的 Python 版本
+	PoetryPython   string   `json:"poetry_python"`    // Poetry 依赖中的 python 约束
+	MCPVersionSpec string   `json:"mcp_version_spec"` // mcp 依赖的版本约束, 为空时不添加该依赖
+
+	LicenseClassifier string `json:"license_classifier"` // PyPI 许可证分类器
+
+	PinDependencies bool `json:"pin_dependencies"` // 依赖写为固定版本 (==x.y.z) 而非默认的版本约束
+
+	UseRuff     bool   `json:"use_ruff"`     // 使用 ruff 取代 pylint
+	RuffVersion string `json:"ruff_version"` // 开发依赖与 pre-commit 中固定的 ruff 版本
+
+	CIPython         string   `json:"ci_python"`          // CI 中 lint 与类型检查使用的 Python 版本
+	CIPythonVersions []string `json:"ci_python_versions"` // CI 测试矩阵中的 Python 版本
+
+	FastAPI   bool       `json:"fastapi"`    // 生成 FastAPI 路由与 api_server.py 入口
+	APIRoutes []APIRoute `json:"api_routes"` // FastAPI 路由, 每个端点一个
+
+	Invoke bool `json:"invoke"` // 生成 callEndpoint 工具, 向上游 API 发送请求
+	Docker bool `json:"docker"` // 生成 Dockerfile、.dockerignore 与 Makefile 的 docker 目标
+	OTel   bool `json:"otel"`   // 用 OpenTelemetry 追踪工具调用, opentelemetry-api 为可选依赖 otel
+
+	ShardModel bool `json:"shard_model"` // 模型按标签拆分为 spec/model/ 下的索引与分片, 由加载器按需读取
+
+	Skip emitter.Skip `json:"skip"` // 省略的文件类别; 其余文件不再引用被省略的文件
+
+	// Credentials 是 callEndpoint 可使用的安全方案凭据, 按方案名排序; 仅在 Invoke 时设置
+	Credentials []emitter.Credential `json:"credentials"`
+
+	Tags []TagInfo `json:"tags"` // README 中列出的标签及其描述, 按名称排序
+}
+
+// TagInfo 是 README 标签列表中的一项
+type TagInfo struct {
+	Name        string `json:"name"`        // 标签名
+	Description string `json:"description"` // 规格根 tags 中的描述 (空白已合并为单个空格), 无描述时为空
+}
+
+// APIRoute 是 FastAPI 路由器中对应一个端点的路由
+type APIRoute struct {
+	Path       string `json:"path"`        // 路由路径 (相对于路由器前缀), 如 "/get-pets-petid"
+	Function   string `json:"function"`    // 处理函数名, 如 "get_pets_petid"
+	EndpointID string `json:"endpoint_id"` // 端点 ID, 如 "get /pets/{petId}"
+}
+
+// maxRouteSlug 是路由路径与函数名中取自端点的部分的最大长度, 使生成的代码不超过行宽;
+// 更长的部分由 emitter.ShortName 截断并追加完整名称的哈希
+const maxRouteSlug = 60
+
+// ruffVersion 是 UseRuff 时固定的 ruff 版本
+const ruffVersion = "0.6.9"
+
+// pythonDependency 是生成项目可能声明的依赖: 默认的版本约束与 PinDependencies 时固定的版本
+type pythonDependency struct {
+	Spec   string // 默认版本约束, 如 ">=0.6.0"
+	Pinned string // 固定版本, 满足 Spec
+}
+
+// pythonDependencies 是各打包文件 (requirements*.txt、setup.py、pyproject.toml) 中
+// 依赖版本的唯一来源, 键为依赖名 (含 extras)
+var pythonDependencies = map[string]pythonDependency{
+	"dataclasses-json":  {">=0.6.0", "0.6.7"},
+	"typing-extensions": {">=4.5.0", "4.12.2"},
+	"fastapi":           {">=0.110.0", "0.115.0"},
+	"uvicorn":           {">=0.29.0", "0.30.6"},
+	"opentelemetry-api": {">=1.20.0", "1.27.0"},
+
+	"black":            {"==23.9.1", "23.9.1"},
+	"isort":            {"==5.12.0", "5.12.0"},
+	"flake8":           {"==6.1.0", "6.1.0"},
+	"pylint":           {"==3.0.3", "3.0.3"},
+	"ruff":             {"==" + ruffVersion, ruffVersion},
+	"mypy":             {"==1.7.1", "1.7.1"},
+	"types-setuptools": {">=68.0.0", "68.2.0.0"},
+	"pytest":           {">=7.4.0", "7.4.4"},
+	"pytest-cov":       {">=4.1.0", "4.1.0"},
+	"pytest-asyncio":   {">=0.21.0", "0.21.2"},
+	"bandit[toml]":     {">=1.7.5", "1.7.10"},
+	"safety":           {">=2.3.0", "2.3.5"},
+	"radon":            {">=6.0.1", "6.0.1"},
+	"xenon":            {">=0.9.0", "0.9.1"},
+	"pydocstyle":       {">=6.3.0", "6.3.0"},
+	"pre-commit":       {">=3.3.0", "3.5.0"},
+	"pyupgrade":        {">=3.10.0", "3.10.1"},
+	"vermin":           {">=1.5.2", "1.5.2"},
+	"build":            {">=0.10.0", "1.2.2"},
+	"twine":            {">=4.0.0", "4.0.2"},
+}
+
+// Require 返回依赖 name 的 PEP 508 写法, 如 "pytest>=7.4.0"; PinDependencies 时为 "pytest==7.4.4"
+func (d TemplateData) Require(name string) (string, error) {
+	spec, err := d.versionSpec(name)
+	if err != nil {
+		return "", err
+	}
+	return name + spec, nil
+}
+
+// PoetryRequire 返回依赖 name 在 Poetry 中的版本约束; 精确版本不带 "=="
+func (d TemplateData) PoetryRequire(name string) (string, error) {
+	spec, err := d.versionSpec(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(spec, "=="), nil
+}
+
+func (d TemplateData) versionSpec(name string) (string, error) {
+	dep, ok := pythonDependencies[name]
+	if !ok {
+		return "", fmt.Errorf("未知依赖 %q", name)
+	}
+	if d.PinDependencies {
+		return "==" + dep.Pinned, nil
+	}
+	return dep.Spec, nil
+}
+
+// maxPythonLine 是生成代码的行宽上限, 与 black、flake8 和 pylint 的配置一致
+const maxPythonLine = 88
+
+// CredentialsTable 返回 call_endpoint.py 中 CREDENTIALS 字典的 Python 源码,
+// 每项按 black 的方式在超出行宽时换行
+func (d TemplateData) CredentialsTable() string {
+	if len(d.Credentials) == 0 {
+		return "CREDENTIALS: Dict[str, Credential] = {}"
+	}
+	lines := []string{"CREDENTIALS: Dict[str, Credential] = {"}
+	for _, c := range d.Credentials {
+		key := "    " + quoteString(c.Scheme) + ": Credential("
+		args := []string{quoteString(c.Kind), quoteString(c.In), quoteString(c.Name), quoteString(c.EnvVar)}
+		joined := strings.Join(args, ", ")
+		switch {
+		case len(key)+len(joined)+2 <= maxPythonLine:
+			lines = append(lines, key+joined+"),")
+		case len(joined)+8 <= maxPythonLine:
+			lines = append(lines, key, "        "+joined, "    ),")
+		default:
+			lines = append(lines, key)
+			for _, arg := range args {
+				lines = append(lines, "        "+arg+",")
+			}
+			lines = append(lines, "    ),")
+		}
+	}
+	return strings.Join(append(lines, "}"), "\n")
+}
+
+// newestClassifiedPython 是分类器列出的最高 Python 次版本
+const newestClassifiedPython = 12
+
+// ciMatrixSize 是 CI 测试矩阵最多包含的 Python 版本数, 取分类器中最新的几个
+const ciMatrixSize = 3
+
+// setPythonVersions 根据已校验的目标版本与 requires-python 约束设置工具目标版本、
+// 分类器版本与 Poetry 约束; 仅有下限时 Poetry 使用 "^3.x" 写法
+func (d *TemplateData) setPythonVersions(version, requires string) {
+	d.PythonRequires = requires
+	d.PythonVersion = version
+	d.PythonTarget = "py" + strings.Replace(version, ".", "", 1)
+	minor := pythonMinor(d.PythonVersion)
+	d.PythonVersions = nil
+	for v := minor; v <= newestClassifiedPython || v == minor; v++ {
+		d.PythonVersions = append(d.PythonVersions, fmt.Sprintf("3.%d", v))
+	}
+	d.CIPythonVersions = d.PythonVersions
+	if len(d.CIPythonVersions) > ciMatrixSize {
+		d.CIPythonVersions = d.CIPythonVersions[len(d.CIPythonVersions)-ciMatrixSize:]
+	}
+	d.CIPython = d.CIPythonVersions[len(d.CIPythonVersions)-1]
+	d.PoetryPython = requires
+	if requires == ">="+d.PythonVersion {
+		d.PoetryPython = "^" + d.PythonVersion
+	}
 }
 
 // RenderTemplate 渲染模板内容，支持动态内容替换
@@ -38,6 +223,7 @@ func RenderTemplate(templateContent string, data TemplateData) (string, error) {
 		"DocString":  formatDocString,
 		"PythonName": toPythonName,
 		"SafeString": toSafeString,
+		"GHExpr":     githubExpression,
 	}
 
 	// 解析模板
@@ -69,6 +255,11 @@ func indentLines(indent string, text string) string {
 	return strings.Join(result, "\n")
 }
 
+// githubExpression 返回 GitHub Actions 表达式 ${{ expr }}, 其定界符与模板语法冲突, 不能直接写在模板中
+func githubExpression(expr string) string {
+	return "${{ " + expr + " }}"
+}
+
 // quoteString 为字符串添加引号并转义特殊字符
 func quoteString(s string) string {
 	return fmt.Sprintf("%q", s)
@@ -159,14 +350,54 @@ func toSafeString(s string) string {
 
 // NewTemplateData 创建新的模板数据实例
 func NewTemplateData(toolName, packageName string, sm *genspec.ServiceModel) TemplateData {
-	return TemplateData{
+	data := TemplateData{
 		ToolName:     toolName,
 		PackageName:  packageName,
 		ServiceTitle: sm.Title,
 		ServiceModel: sm,
-		Version:      "0.1.0",
+		Version:      genspec.DefaultProjectVersion,
 		Author:       "Generated by swagger2mcp",
+		BuildTool:    BuildToolSetuptools,
+		Year:         time.Now().Year(),
+		RuffVersion:  ruffVersion,
+		// 未指定许可证时沿用一直以来的 MIT 分类器
+		LicenseClassifier: license.Classifier("MIT"),
+	}
+	data.setPythonVersions(DefaultPythonVersion, DefaultPythonRequires)
+	for _, tag := range sm.Tags {
+		desc := strings.Join(strings.Fields(sm.TagDescriptions[tag]), " ")
+		data.Tags = append(data.Tags, TagInfo{Name: tag, Description: desc})
 	}
+	return data
+}
+
+// apiRoutes 为每个端点生成一个路由: 路径与函数名由方法和路径中的字母数字组成,
+// 过长时截断并追加哈希, 重名时追加序号; 完整的端点 ID 保留在 EndpointID 中
+func apiRoutes(endpoints []genspec.EndpointModel) []APIRoute {
+	routes := make([]APIRoute, 0, len(endpoints))
+	seen := map[string]bool{}
+	for _, ep := range endpoints {
+		var b strings.Builder
+		for _, r := range strings.ToLower(string(ep.Method) + " " + ep.Path) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				b.WriteRune(r)
+			} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+		}
+		base := strings.Trim(b.String(), "-")
+		slug := emitter.ShortName(base, maxRouteSlug)
+		for n := 2; seen[slug]; n++ {
+			slug = emitter.ShortName(fmt.Sprintf("%s-%d", base, n), maxRouteSlug)
+		}
+		seen[slug] = true
+		routes = append(routes, APIRoute{
+			Path:       "/" + slug,
+			Function:   strings.ReplaceAll(slug, "-", "_"),
+			EndpointID: ep.ID,
+		})
+	}
+	return routes
 }
 
 // 模板渲染错误类型
@@ -221,6 +452,24 @@ func GetTemplateVariableNames() []string {
 		"ServiceModel",
 		"Version",
 		"Author",
+		"AuthorEmail",
+		"License",
+		"LicenseClassifier",
+		"BuildTool",
+		"Year",
+		"PythonRequires",
+		"PythonVersion",
+		"PythonTarget",
+		"PythonVersions",
+		"PoetryPython",
+		"MCPVersionSpec",
+		"PinDependencies",
+		"UseRuff",
+		"RuffVersion",
+		"CIPython",
+		"CIPythonVersions",
+		"FastAPI",
+		"APIRoutes",
 	}
 }
 
@@ -242,55 +491,79 @@ func GetTemplateFunctionNames() []string {
 		"DocString",
 		"PythonName",
 		"SafeString",
+		"GHExpr",
 	}
 }
 
 // MainPyTemplate main.py程序入口点模板
 const MainPyTemplate = `#!/usr/bin/env python3
-"""
-{{.ServiceTitle}} MCP 服务器
-通过标准输入输出提供 MCP (Model Context Protocol) 服务
+"""{{.ServiceTitle}} MCP 服务器入口.
+
+默认通过标准输入输出提供 MCP (Model Context Protocol) 服务;
+--transport http 改为在 HTTP 上提供 (见 transport.py);
+--describe 以 JSON 输出工具列表与模型摘要后退出。
 
 Generated by swagger2mcp
 """
 
-import sys
+import argparse
 import json
 import logging
-from typing import Dict, Any, Optional
+import sys
+from typing import List, Optional
 
-from {{.PackageName}}.server import MCPServer
+from .server import MCPServer
+from .transport import TRANSPORTS, http_address, serve_http
+
+logger = logging.getLogger(__name__)
 
 
 def setup_logging() -> None:
-    """配置日志系统"""
+    """配置日志系统, 输出到 stderr 以免干扰 stdio 上的 MCP 通信."""
     logging.basicConfig(
-        level=logging.WARNING,  # 只显示警告和错误，避免干扰MCP通信
-        format='%(asctime)s - %(name)s - %(levelname)s - %(message)s',
-        handlers=[logging.StreamHandler(sys.stderr)]  # 使用stderr，不干扰stdin/stdout
+        level=logging.WARNING,
+        format="%(asctime)s - %(name)s - %(levelname)s - %(message)s",
+        handlers=[logging.StreamHandler(sys.stderr)],
     )
 
 
-def main() -> None:
-    """MCP服务器主入口点"""
+def parse_args(argv: Optional[List[str]] = None) -> argparse.Namespace:
+    """解析命令行参数."""
+    parser = argparse.ArgumentParser(prog="{{.ToolName}}", description="MCP 服务器")
+    parser.add_argument(
+        "--transport",
+        choices=TRANSPORTS,
+        default="stdio",
+        help="MCP 传输方式 (默认 stdio)",
+    )
+    parser.add_argument(
+        "--describe",
+        action="store_true",
+        help="以 JSON 输出工具列表与模型摘要后退出",
+    )
+    return parser.parse_args(argv)
+
+
+def main(argv: Optional[List[str]] = None) -> None:
+    """MCP 服务器主入口点."""
+    args = parse_args(argv)
+    setup_logging()
     try:
-        # 配置日志
-        setup_logging()
-        logger = logging.getLogger(__name__)
-        
-        # 创建MCP服务器实例
         server = MCPServer(tool_name="{{.ToolName}}")
-        
-        # 启动标准输入输出通信
-        logger.info("启动 {{.ServiceTitle}} MCP 服务器")
-        server.run_stdio()
-        
+        if args.describe:
+            print(json.dumps(server.describe(), ensure_ascii=False, indent=2))
+            return
+        logger.info("启动 MCP 服务器: %s", server.tool_name)
+        if args.transport == "http":
+            host, port = http_address()
+            serve_http(server, host, port)
+        else:
+            server.run_stdio()
     except KeyboardInterrupt:
-        # 处理Ctrl+C优雅退出
         sys.exit(0)
-    except Exception as e:
-        logger = logging.getLogger(__name__)
-        logger.error(f"MCP服务器启动失败: {e}")
+    except Exception:  # pylint: disable=broad-exception-caught
+        # 入口点兜底: 任何启动失败都记录日志并以非零状态退出
+        logger.exception("MCP 服务器运行失败")
         sys.exit(1)
 
 
@@ -298,909 +571,973 @@ if __name__ == "__main__":
     main()
 `
 
-// ServerPyTemplate server.py MCP服务器核心实现模板
-const ServerPyTemplate = `"""
-MCP 服务器核心实现
-处理 JSON-RPC 2.0 协议消息，支持标准 MCP 方法
+// DunderMainPyTemplate __main__.py 模板, 使 python -m 包名 可直接运行服务器
+const DunderMainPyTemplate = `"""python -m {{.PackageName}} 的入口, 等同于 main.py.
+
+Generated by swagger2mcp
+"""
+
+from .main import main
+
+main()
+`
+
+// TransportPyTemplate transport.py HTTP 传输模板
+const TransportPyTemplate = `"""{{.ServiceTitle}} MCP 服务器的 HTTP 传输.
+
+以 Streamable HTTP 提供 MCP 服务: 客户端向 /mcp POST 一条 JSON-RPC 消息,
+响应以 JSON 返回, 通知返回 202。服务器不会主动发送消息, 因此没有 GET 上的 SSE 流。
 
 Generated by swagger2mcp
 """
 
-import sys
 import json
 import logging
-from typing import Dict, Any, Optional, List, Union, Callable
-from dataclasses import dataclass, asdict
+import os
+import sys
+from http import HTTPStatus
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+from typing import Any, Dict, Mapping, Optional, Tuple, Type
 
-from {{.PackageName}}.spec.loader import load_service_model
-from {{.PackageName}}.mcp.methods import (
-    list_endpoints,
-    search_endpoints, 
+from .server import MCPServer
+
+logger = logging.getLogger(__name__)
+
+# 支持的 --transport 取值
+TRANSPORTS = ("stdio", "http")
+
+# 既未设置 MCP_HTTP_ADDR 也未设置 PORT 时的监听地址
+DEFAULT_HTTP_ADDR = "127.0.0.1:8080"
+
+# Streamable HTTP 端点路径
+MCP_PATH = "/mcp"
+
+
+def http_address(env: Optional[Mapping[str, str]] = None) -> Tuple[str, int]:
+    """返回 HTTP 传输的监听地址.
+
+    优先使用 MCP_HTTP_ADDR (host:port); 其次按容器平台的惯例在所有网卡上监听 PORT;
+    都未设置时为 DEFAULT_HTTP_ADDR。
+
+    Args:
+        env: 环境变量, 默认 os.environ
+
+    Returns:
+        (host, port)
+
+    Raises:
+        ValueError: 地址不是 host:port 形式或端口无效
+    """
+    env = os.environ if env is None else env
+    addr = env.get("MCP_HTTP_ADDR", "").strip()
+    if not addr and env.get("PORT", "").strip():
+        addr = ":" + env.get("PORT", "").strip()
+    if not addr:
+        addr = DEFAULT_HTTP_ADDR
+    host, sep, port = addr.rpartition(":")
+    if not sep or not port.isdigit() or int(port) > 65535:
+        raise ValueError(f"invalid listen address {addr!r} (want host:port)")
+    if host.startswith("[") and host.endswith("]"):
+        host = host[1:-1]
+    return host or "0.0.0.0", int(port)
+
+
+def make_handler(server: MCPServer) -> Type[BaseHTTPRequestHandler]:
+    """返回把 /mcp 上的请求交给 server 处理的请求处理器类."""
+
+    class MCPRequestHandler(BaseHTTPRequestHandler):
+        """Streamable HTTP 请求处理器."""
+
+        def do_POST(self) -> None:  # pylint: disable=invalid-name
+            """处理一条 JSON-RPC 消息."""
+            if not self._on_mcp_path():
+                return
+            length = int(self.headers.get("Content-Length") or 0)
+            body = self.rfile.read(length).decode("utf-8")
+            response = server.handle_message(body)
+            if response is None:
+                self.send_response(HTTPStatus.ACCEPTED)
+                self.send_header("Content-Length", "0")
+                self.end_headers()
+                return
+            error = response.error
+            status = HTTPStatus.OK
+            if error is not None and error.code == MCPServer.PARSE_ERROR:
+                status = HTTPStatus.BAD_REQUEST
+            self._send_json(status, response.to_dict())
+
+        def do_GET(self) -> None:  # pylint: disable=invalid-name
+            """服务器不会主动发送消息, 没有可供 GET 的 SSE 流."""
+            if not self._on_mcp_path():
+                return
+            self.send_response(HTTPStatus.METHOD_NOT_ALLOWED)
+            self.send_header("Allow", "POST")
+            self.send_header("Content-Length", "0")
+            self.end_headers()
+
+        def _on_mcp_path(self) -> bool:
+            path = self.path.split("?", 1)[0]
+            if path == MCP_PATH:
+                return True
+            self._send_json(HTTPStatus.NOT_FOUND, {"error": f"not found: {path}"})
+            return False
+
+        def _send_json(self, status: HTTPStatus, payload: Dict[str, Any]) -> None:
+            data = json.dumps(payload, ensure_ascii=False).encode("utf-8")
+            self.send_response(status)
+            self.send_header("Content-Type", "application/json")
+            self.send_header("Content-Length", str(len(data)))
+            self.end_headers()
+            self.wfile.write(data)
+
+        # pylint: disable-next=redefined-builtin
+        def log_message(self, format: str, *args: Any) -> None:
+            """访问日志写到 logging, 而不是直接写 stderr."""
+            logger.debug(format, *args)
+
+    return MCPRequestHandler
+
+
+def create_http_server(server: MCPServer, host: str, port: int) -> ThreadingHTTPServer:
+    """创建在 host:port 上提供 MCP_PATH 的 HTTP 服务器 (尚未开始服务)."""
+    return ThreadingHTTPServer((host, port), make_handler(server))
+
+
+def serve_http(server: MCPServer, host: str, port: int) -> None:
+    """在 host:port 上提供 MCP 服务直到进程被中断."""
+    httpd = create_http_server(server, host, port)
+    sys.stderr.write(f"MCP 服务器监听 http://{host}:{httpd.server_address[1]}{MCP_PATH}\n")
+    with httpd:
+        httpd.serve_forever()
+`
+
+// ServerPyTemplate server.py MCP服务器核心实现模板
+const ServerPyTemplate = `"""MCP 服务器核心实现.
+
+处理 JSON-RPC 2.0 协议消息, 支持标准 MCP 方法。
+
+Generated by swagger2mcp
+"""
+
+import json
+import logging
+import sys
+from dataclasses import dataclass
+from typing import Any, Callable, Dict, List, Optional, Union
+
+from .mcp.methods import (
+{{- if .Invoke}}
+    call_endpoint,
+{{- end}}
+    explain_parameter,
     get_endpoint_details,
+    get_schema_details,
+    list_endpoints,
     list_schemas,
-    get_schema_details
+    search_endpoints,
 )
+from .spec.loader import apply_base_url_override, load_service_model, model_hash
+from .spec.model import name_key
+{{- if .OTel}}
+from .telemetry import trace_tool
+{{- end}}
+
+JsonRpcId = Union[str, int]
+ToolHandler = Callable[[Dict[str, Any]], str]
+
+PROTOCOL_VERSION = "2024-11-05"
+SERVER_VERSION = "1.0.0"
+
+
+def _string_param(description: str) -> Dict[str, str]:
+    """构造字符串类型的工具参数定义."""
+    return {"type": "string", "description": description}
+
+
+TOOL_SPECS: Dict[str, Dict[str, Any]] = {
+    "listEndpoints": {
+        "description": "列出所有可用的API端点，提供端点概览和统计信息",
+        "properties": {},
+        "required": [],
+    },
+    "searchEndpoints": {
+        "description": "根据关键字、标签、方法或路径模式搜索API端点",
+        "properties": {
+            "keyword": _string_param("搜索关键字，在端点路径、描述和摘要中查找"),
+            "tag": _string_param("按标签过滤端点"),
+            "method": _string_param("按HTTP方法过滤端点 (GET, POST, PUT, DELETE等)"),
+            "path_pattern": _string_param("路径正则表达式模式"),
+        },
+        "required": [],
+    },
+    "getEndpointDetails": {
+        "description": "获取指定API端点的详细信息，包括参数、请求体和响应格式",
+        "properties": {
+            "endpoint_id": _string_param("端点ID (method+path格式，如 'GET /users')"),
+            "method": _string_param("HTTP方法，与path一起使用"),
+            "path": _string_param("API路径，与method一起使用"),
+        },
+        "required": [],
+    },
+    "listSchemas": {
+        "description": "列出所有可用的数据模式(Schema)定义",
+        "properties": {},
+        "required": [],
+    },
+    "getSchemaDetails": {
+        "description": "获取指定数据模式(Schema)的详细定义信息",
+        "properties": {
+            "schema_name": _string_param("要查询的Schema名称"),
+        },
+        "required": ["schema_name"],
+    },
+    "explainParameter": {
+        "description": "说明端点的单个参数：位置、Schema、序列化方式、枚举值及其在请求中的写法",
+        "properties": {
+            "endpoint_id": _string_param("端点ID (如 'GET /users/{id}') 或 operationId"),
+            "parameter_name": _string_param("参数名，也可以是请求体的顶层属性"),
+        },
+        "required": ["endpoint_id", "parameter_name"],
+    },
+{{- if .Invoke}}
+    "callEndpoint": {
+        "description": (
+            "调用API端点：传入端点ID、按名称给出的参数 (path、query、header 或 cookie) "
+            "与可选的JSON请求体，返回状态码、响应头与 (截断的) 响应体"
+        ),
+        "properties": {
+            "endpoint_id": _string_param("端点ID (如 'GET /users/{id}') 或 operationId"),
+            "params": {"type": "object", "description": "按参数名给出的参数值"},
+            "body": {"description": "JSON请求体"},
+        },
+        "required": ["endpoint_id"],
+    },
+{{- end}}
+}
 
 
 @dataclass
 class JsonRpcError:
-    """JSON-RPC 2.0 错误对象"""
+    """JSON-RPC 2.0 错误对象."""
+
     code: int
     message: str
-    data: Optional[Any] = None
+    data: Any = None
 
 
 @dataclass
 class JsonRpcResponse:
-    """JSON-RPC 2.0 响应对象"""
-    jsonrpc: str = "2.0"
-    id: Optional[Union[str, int]] = None
-    result: Optional[Any] = None
+    """JSON-RPC 2.0 响应对象."""
+
+    id: Optional[JsonRpcId] = None
+    result: Any = None
     error: Optional[JsonRpcError] = None
 
+    def to_dict(self) -> Dict[str, Any]:
+        """转换为可序列化的字典, 只包含 result 或 error 之一."""
+        payload: Dict[str, Any] = {"jsonrpc": "2.0", "id": self.id}
+        if self.error is None:
+            payload["result"] = {} if self.result is None else self.result
+            return payload
+        error: Dict[str, Any] = {
+            "code": self.error.code,
+            "message": self.error.message,
+        }
+        if self.error.data is not None:
+            error["data"] = self.error.data
+        payload["error"] = error
+        return payload
+
 
 class MCPServer:
-    """MCP 服务器主类，实现 JSON-RPC 2.0 协议处理"""
-    
-    # JSON-RPC 2.0 错误代码
+    """MCP 服务器主类, 实现 JSON-RPC 2.0 协议处理."""
+
     PARSE_ERROR = -32700
     INVALID_REQUEST = -32600
     METHOD_NOT_FOUND = -32601
     INVALID_PARAMS = -32602
     INTERNAL_ERROR = -32603
-    
-    def __init__(self, tool_name: str):
-        """
-        初始化 MCP 服务器
-        
+
+    def __init__(self, tool_name: str) -> None:
+        """初始化 MCP 服务器并加载内嵌的服务模型.
+
         Args:
             tool_name: 工具名称
         """
         self.tool_name = tool_name
         self.logger = logging.getLogger(f"{__name__}.MCPServer")
-        
-        # 初始化状态
         self.initialized = False
-        
-        try:
-            # 加载服务模型
-            self.service_model = load_service_model()
-            self.logger.info(f"成功加载服务模型: {self.service_model.title}")
-            
-            # 注册工具方法
-            self.tools = self._register_tools()
-            self.logger.info(f"注册了 {len(self.tools)} 个工具方法")
-            
-        except Exception as e:
-            self.logger.error(f"初始化失败: {e}")
-            raise
-    
-    def _register_tools(self) -> Dict[str, Callable[..., Any]]:
-        """注册所有可用的工具方法"""
-        return {
+        self.service_model = apply_base_url_override(load_service_model())
+        self.tools: Dict[str, ToolHandler] = {
             "listEndpoints": self._handle_list_endpoints,
             "searchEndpoints": self._handle_search_endpoints,
             "getEndpointDetails": self._handle_get_endpoint_details,
             "listSchemas": self._handle_list_schemas,
             "getSchemaDetails": self._handle_get_schema_details,
+            "explainParameter": self._handle_explain_parameter,
+{{- if .Invoke}}
+            "callEndpoint": self._handle_call_endpoint,
+{{- end}}
         }
-    
+{{- if .OTel}}
+        # 每次工具调用记录一个 span, 见 telemetry.py
+        self.tools = {
+            name: trace_tool(name, handler) for name, handler in self.tools.items()
+        }
+{{- end}}
+
     def run_stdio(self) -> None:
-        """启动标准输入输出协议监听"""
+        """逐行读取标准输入中的 JSON-RPC 消息并把响应写到标准输出."""
         self.logger.info("开始监听标准输入")
-        
-        try:
-            for line in sys.stdin:
-                line = line.strip()
-                if not line:
-                    continue
-                
-                response = self._process_message(line)
-                if response:
-                    # 只有请求才需要响应，通知消息不需要响应
-                    self._send_response(response)
-                    
-        except EOFError:
-            # 标准输入关闭，正常退出
-            self.logger.info("标准输入已关闭，服务器退出")
-        except Exception as e:
-            self.logger.error(f"处理标准输入时发生错误: {e}")
-            raise
-    
-    def _process_message(self, message: str) -> Optional[JsonRpcResponse]:
+        for line in sys.stdin:
+            message = line.strip()
+            if not message:
+                continue
+            response = self.handle_message(message)
+            if response is not None:
+                self._send_response(response)
+        self.logger.info("标准输入已关闭, 服务器退出")
+
+    def describe(self) -> Dict[str, Any]:
+        """返回 --describe 输出的摘要: API 标题与版本、端点与模式数量、模型哈希及工具列表.
+
+        工具按名称排序, 取自 self.tools 注册表; 模型哈希是内嵌模型文件的 SHA-256。
         """
-        处理接收到的 JSON-RPC 消息
-        
+        return {
+            "title": self.service_model.title,
+            "version": self.service_model.version,
+            "endpoints": len(self.service_model.endpoints),
+            "schemas": len(self.service_model.schemas),
+            "modelHash": model_hash(),
+            "tools": [
+                {"name": name, "description": TOOL_SPECS[name]["description"]}
+                for name in sorted(self.tools, key=name_key)
+            ],
+        }
+
+    def handle_message(self, message: str) -> Optional[JsonRpcResponse]:
+        """处理一条 JSON-RPC 消息.
+
         Args:
             message: JSON-RPC 消息字符串
-            
+
         Returns:
-            响应对象，如果是通知消息则返回None
+            响应对象; 通知消息没有响应, 返回 None
         """
         try:
-            # 解析JSON消息
-            try:
-                data = json.loads(message)
-            except json.JSONDecodeError as e:
-                self.logger.warning(f"JSON解析失败: {e}")
-                return JsonRpcResponse(
-                    error=JsonRpcError(
-                        code=self.PARSE_ERROR,
-                        message="Parse error"
-                    )
-                )
-            
-            # 验证JSON-RPC 2.0格式
-            if not isinstance(data, dict) or data.get("jsonrpc") != "2.0":
-                return JsonRpcResponse(
-                    id=data.get("id") if isinstance(data, dict) else None,
-                    error=JsonRpcError(
-                        code=self.INVALID_REQUEST,
-                        message="Invalid Request"
-                    )
-                )
-            
-            method = data.get("method")
-            params = data.get("params", {})
-            msg_id = data.get("id")
-            
-            # 如果没有id字段，这是一个通知消息，不需要响应
-            if msg_id is None:
-                self._handle_notification(method, params)
-                return None
-            
-            # 处理请求消息
-            return self._handle_request(method, params, msg_id)
-            
-        except Exception as e:
-            self.logger.error(f"处理消息时发生内部错误: {e}")
-            return JsonRpcResponse(
-                id=data.get("id") if "data" in locals() and isinstance(data, dict) else None,
-                error=JsonRpcError(
-                    code=self.INTERNAL_ERROR,
-                    message="Internal error",
-                    data=str(e)
-                )
-            )
-    
-    def _handle_notification(self, method: str, params: Dict[str, Any]) -> None:
-        """处理通知消息（无需响应）.
-        
-        Args:
-            method: 通知方法名
-            params: 通知参数
-        """
-        self.logger.debug(f"收到通知: {method}")
-        # 通知消息通常用于客户端向服务器发送状态更新等信息
-        # 这里可以根据需要处理特定的通知消息
-    
-    def _handle_request(self, method: str, params: Dict[str, Any], msg_id: Union[str, int]) -> JsonRpcResponse:
-        """
-        处理请求消息
-        
-        Args:
-            method: 方法名
-            params: 参数
-            msg_id: 消息ID
-            
-        Returns:
-            响应对象
-        """
+            data = json.loads(message)
+        except json.JSONDecodeError as exc:
+            self.logger.warning("JSON 解析失败: %s", exc)
+            return self._error(None, self.PARSE_ERROR, "Parse error")
+        if not isinstance(data, dict) or data.get("jsonrpc") != "2.0":
+            return self._error(None, self.INVALID_REQUEST, "Invalid Request")
+
+        msg_id = data.get("id")
+        method = data.get("method")
+        params = data.get("params") or {}
+        if msg_id is None:
+            self.logger.debug("收到通知: %s", method)
+            return None
+        if not isinstance(method, str) or not isinstance(params, dict):
+            return self._error(msg_id, self.INVALID_REQUEST, "Invalid Request")
         try:
-            # 处理标准MCP方法
-            if method == "initialize":
-                return self._handle_initialize(params, msg_id)
-            elif method == "ping":
-                return self._handle_ping(params, msg_id)
-            elif method == "tools/list":
-                return self._handle_tools_list(params, msg_id)
-            elif method == "tools/call":
-                return self._handle_tools_call(params, msg_id)
-            else:
-                return JsonRpcResponse(
-                    id=msg_id,
-                    error=JsonRpcError(
-                        code=self.METHOD_NOT_FOUND,
-                        message=f"Method not found: {method}"
-                    )
-                )
-        except Exception as e:
-            self.logger.error(f"处理请求 {method} 时发生错误: {e}")
-            return JsonRpcResponse(
-                id=msg_id,
-                error=JsonRpcError(
-                    code=self.INTERNAL_ERROR,
-                    message="Internal error",
-                    data=str(e)
-                )
-            )
-    
-    def _handle_initialize(self, params: Dict[str, Any], msg_id: Union[str, int]) -> JsonRpcResponse:
-        """处理初始化请求.
-        
-        Args:
-            params: 初始化参数
-            msg_id: 消息ID
-            
-        Returns:
-            包含服务器能力信息的响应对象
-        """
-        self.initialized = True
-        self.logger.info("MCP服务器已初始化")
-        
-        return JsonRpcResponse(
-            id=msg_id,
-            result={
-                "protocolVersion": "2024-11-05",
-                "capabilities": {
-                    "tools": {}
-                },
-                "serverInfo": {
-                    "name": self.tool_name,
-                    "version": "1.0.0"
-                }
-            }
-        )
-    
-    def _handle_ping(self, params: Dict[str, Any], msg_id: Union[str, int]) -> JsonRpcResponse:
-        """处理ping请求.
-        
-        Args:
-            params: ping参数（通常为空）
-            msg_id: 消息ID
-            
-        Returns:
-            空结果的响应对象
-        """
-        return JsonRpcResponse(
-            id=msg_id,
-            result={}
-        )
-    
-    def _handle_tools_list(self, params: Dict[str, Any], msg_id: Union[str, int]) -> JsonRpcResponse:
-        """处理工具列表请求.
-        
-        Args:
-            params: 请求参数（通常为空）
-            msg_id: 消息ID
-            
-        Returns:
-            包含所有可用工具列表的响应对象
-        """
-        tools_list = []
-        
-        for tool_name in self.tools.keys():
-            tool_info = {
-                "name": tool_name,
-                "description": self._get_tool_description(tool_name),
+            return self._handle_request(method, params, msg_id)
+        except Exception as exc:  # pylint: disable=broad-exception-caught
+            # 单个请求出错不能让服务器退出, 以 JSON-RPC 内部错误返回给客户端
+            self.logger.exception("处理请求 %s 时发生错误", method)
+            return self._error(msg_id, self.INTERNAL_ERROR, "Internal error", str(exc))
+
+    def _handle_request(
+        self,
+        method: str,
+        params: Dict[str, Any],
+        msg_id: JsonRpcId,
+    ) -> JsonRpcResponse:
+        """把请求分发到对应的 MCP 方法."""
+        if method == "initialize":
+            self.initialized = True
+            return JsonRpcResponse(id=msg_id, result=self._server_info())
+        if method == "ping":
+            return JsonRpcResponse(id=msg_id, result={})
+        if method == "tools/list":
+            return JsonRpcResponse(id=msg_id, result={"tools": self._list_tools()})
+        if method == "tools/call":
+            return self._handle_tools_call(params, msg_id)
+        message = f"Method not found: {method}"
+        return self._error(msg_id, self.METHOD_NOT_FOUND, message)
+
+    def _server_info(self) -> Dict[str, Any]:
+        """返回 initialize 请求的结果."""
+        return {
+            "protocolVersion": PROTOCOL_VERSION,
+            "capabilities": {
+                "tools": {},
+            },
+            "serverInfo": {
+                "name": self.tool_name,
+                "version": SERVER_VERSION,
+            },
+        }
+
+    @staticmethod
+    def _list_tools() -> List[Dict[str, Any]]:
+        """返回 tools/list 请求的工具定义."""
+        return [
+            {
+                "name": name,
+                "description": spec["description"],
                 "inputSchema": {
                     "type": "object",
-                    "properties": self._get_tool_input_schema(tool_name),
-                    "required": self._get_tool_required_params(tool_name)
-                }
+                    "properties": spec["properties"],
+                    "required": spec["required"],
+                },
             }
-            tools_list.append(tool_info)
-        
+            for name, spec in TOOL_SPECS.items()
+        ]
+
+    def _handle_tools_call(
+        self,
+        params: Dict[str, Any],
+        msg_id: JsonRpcId,
+    ) -> JsonRpcResponse:
+        """执行 tools/call 请求中指定的工具."""
+        if not self.initialized:
+            return self._error(msg_id, self.INVALID_REQUEST, "Server not initialized")
+        tool_name = params.get("name")
+        handler = self.tools.get(tool_name) if isinstance(tool_name, str) else None
+        if handler is None:
+            message = f"Tool not found: {tool_name}"
+            return self._error(msg_id, self.METHOD_NOT_FOUND, message)
+        arguments = params.get("arguments") or {}
+        if not isinstance(arguments, dict):
+            return self._error(msg_id, self.INVALID_PARAMS, "Invalid params")
+        text = handler(arguments)
         return JsonRpcResponse(
             id=msg_id,
-            result={
-                "tools": tools_list
-            }
+            result={"content": [{"type": "text", "text": text}]},
         )
-    
-    def _handle_tools_call(self, params: Dict[str, Any], msg_id: Union[str, int]) -> JsonRpcResponse:
-        """处理工具调用请求.
-        
-        Args:
-            params: 工具调用参数，包含name和arguments
-            msg_id: 消息ID
-            
-        Returns:
-            包含工具执行结果的响应对象
-        """
-        if not self.initialized:
-            return JsonRpcResponse(
-                id=msg_id,
-                error=JsonRpcError(
-                    code=self.INVALID_REQUEST,
-                    message="Server not initialized"
-                )
-            )
-        
-        tool_name = params.get("name")
-        arguments = params.get("arguments", {})
-        
-        if tool_name not in self.tools:
-            return JsonRpcResponse(
-                id=msg_id,
-                error=JsonRpcError(
-                    code=self.METHOD_NOT_FOUND,
-                    message=f"Tool not found: {tool_name}"
-                )
-            )
-        
-        try:
-            # 调用具体的工具实现
-            result = self.tools[tool_name](arguments)
-            
-            return JsonRpcResponse(
-                id=msg_id,
-                result={
-                    "content": [
-                        {
-                            "type": "text",
-                            "text": result
-                        }
-                    ]
-                }
-            )
-            
-        except Exception as e:
-            self.logger.error(f"工具 {tool_name} 执行失败: {e}")
-            return JsonRpcResponse(
-                id=msg_id,
-                error=JsonRpcError(
-                    code=self.INTERNAL_ERROR,
-                    message=f"Tool execution failed: {str(e)}"
-                )
-            )
-    
-    def _send_response(self, response: JsonRpcResponse) -> None:
-        """发送响应到标准输出.
-        
-        Args:
-            response: 要发送的JSON-RPC响应对象
-        """
-        try:
-            # 将响应对象转换为字典，过滤None值
-            response_dict = asdict(response)
-            response_dict = {k: v for k, v in response_dict.items() if v is not None}
-            
-            # 处理嵌套的dataclass对象
-            if response.error:
-                response_dict["error"] = asdict(response.error)
-                response_dict["error"] = {k: v for k, v in response_dict["error"].items() if v is not None}
-            
-            # 发送JSON响应
-            json_response = json.dumps(response_dict, ensure_ascii=False)
-            print(json_response, flush=True)
-            
-        except Exception as e:
-            self.logger.error(f"发送响应失败: {e}")
-    
-    def _get_tool_description(self, tool_name: str) -> str:
-        """获取工具描述.
-        
-        Args:
-            tool_name: 工具名称
-            
-        Returns:
-            工具描述文本
-        """
-        descriptions = {
-            "listEndpoints": "列出所有可用的API端点，提供端点概览和统计信息",
-            "searchEndpoints": "根据关键字、标签、方法或路径模式搜索API端点",
-            "getEndpointDetails": "获取指定API端点的详细信息，包括参数、请求体和响应格式",
-            "listSchemas": "列出所有可用的数据模式(Schema)定义",
-            "getSchemaDetails": "获取指定数据模式(Schema)的详细定义信息"
-        }
-        return descriptions.get(tool_name, "")
-    
-    def _get_tool_input_schema(self, tool_name: str) -> Dict[str, Any]:
-        """获取工具输入参数模式.
-        
-        Args:
-            tool_name: 工具名称
-            
-        Returns:
-            工具输入参数的JSON Schema定义
-        """
-        schemas = {
-            "listEndpoints": {},
-            "searchEndpoints": {
-                "keyword": {
-                    "type": "string",
-                    "description": "搜索关键字，在端点路径、描述和摘要中查找"
-                },
-                "tag": {
-                    "type": "string", 
-                    "description": "按标签过滤端点"
-                },
-                "method": {
-                    "type": "string",
-                    "description": "按HTTP方法过滤端点 (GET, POST, PUT, DELETE等)"
-                },
-                "path_pattern": {
-                    "type": "string",
-                    "description": "路径正则表达式模式"
-                }
-            },
-            "getEndpointDetails": {
-                "endpoint_id": {
-                    "type": "string",
-                    "description": "端点ID (method+path格式，如 'GET /users') 或者分别提供method和path参数"
-                },
-                "method": {
-                    "type": "string",
-                    "description": "HTTP方法，与path一起使用"
-                },
-                "path": {
-                    "type": "string", 
-                    "description": "API路径，与method一起使用"
-                }
-            },
-            "listSchemas": {},
-            "getSchemaDetails": {
-                "schema_name": {
-                    "type": "string",
-                    "description": "要查询的Schema名称"
-                }
-            }
-        }
-        return schemas.get(tool_name, {})
-    
-    def _get_tool_required_params(self, tool_name: str) -> List[str]:
-        """获取工具必需参数列表.
-        
-        Args:
-            tool_name: 工具名称
-            
-        Returns:
-            必需参数名称列表
-        """
-        required = {
-            "listEndpoints": [],
-            "searchEndpoints": [],
-            "getEndpointDetails": [],  # endpoint_id 或 (method + path) 至少需要一个
-            "listSchemas": [],
-            "getSchemaDetails": ["schema_name"]
-        }
-        return required.get(tool_name, [])
-    
-    # 工具方法实现
-    def _handle_list_endpoints(self, arguments: Dict[str, Any]) -> str:
-        """处理listEndpoints工具调用.
-        
-        Args:
-            arguments: 工具参数（此工具无需参数）
-            
-        Returns:
-            格式化的端点概览文本
-        """
+
+    def _handle_list_endpoints(self, _arguments: Dict[str, Any]) -> str:
         return list_endpoints.format_endpoints_overview(self.service_model)
-    
+
     def _handle_search_endpoints(self, arguments: Dict[str, Any]) -> str:
-        """处理searchEndpoints工具调用.
-        
-        Args:
-            arguments: 搜索参数，包含keyword、tag、method、path_pattern等
-            
-        Returns:
-            格式化的搜索结果文本
-        """
-        keyword = arguments.get("keyword", "")
-        tag = arguments.get("tag", "")
-        method = arguments.get("method", "")
-        path_pattern = arguments.get("path_pattern", "")
-        
         search_params = {
-            "keyword": keyword,
-            "tag": tag,
-            "method": method,
-            "path_pattern": path_pattern
+            key: str(arguments.get(key) or "")
+            for key in ("keyword", "tag", "method", "path_pattern")
         }
-        
         results = search_endpoints.search_endpoints(self.service_model, search_params)
         return search_endpoints.format_search_results(results, search_params)
-    
+
     def _handle_get_endpoint_details(self, arguments: Dict[str, Any]) -> str:
-        """处理getEndpointDetails工具调用.
-        
-        Args:
-            arguments: 端点查询参数，支持endpoint_id或method+path组合
-            
-        Returns:
-            格式化的端点详细信息文本
-        """
-        endpoint_id = arguments.get("endpoint_id", "")
-        method = arguments.get("method", "")
-        path = arguments.get("path", "")
-        
+        endpoint_id = str(arguments.get("endpoint_id") or "")
+        method = str(arguments.get("method") or "")
+        path = str(arguments.get("path") or "")
         if endpoint_id:
-            # 使用endpoint_id查找
-            endpoint, found = get_endpoint_details.get_endpoint_details(
+            endpoint = get_endpoint_details.get_endpoint_details(
                 self.service_model, endpoint_id
             )
+            missing = f"未找到端点: {endpoint_id}"
         elif method and path:
-            # 使用method和path查找
-            endpoint, found = get_endpoint_details.get_endpoint_details(
+            endpoint = get_endpoint_details.get_endpoint_details(
                 self.service_model, method, path
             )
+            missing = f"未找到端点: {method} {path}"
         else:
             return "错误：必须提供 endpoint_id 或 (method + path) 参数"
-        
-        if not found:
-            return f"未找到端点: {endpoint_id or f'{method} {path}'}"
-        
-        return get_endpoint_details.format_endpoint_details(endpoint, self.service_model)
-    
-    def _handle_list_schemas(self, arguments: Dict[str, Any]) -> str:
-        """处理listSchemas工具调用.
-        
-        Args:
-            arguments: 工具参数（此工具无需参数）
-            
-        Returns:
-            格式化的Schema列表文本
-        """
+        if endpoint is None:
+            return missing
+        return get_endpoint_details.format_endpoint_details(
+            endpoint, self.service_model
+        )
+
+    def _handle_list_schemas(self, _arguments: Dict[str, Any]) -> str:
         schemas = list_schemas.list_schemas(self.service_model)
         return list_schemas.format_schemas_list(schemas)
-    
+
     def _handle_get_schema_details(self, arguments: Dict[str, Any]) -> str:
-        """处理getSchemaDetails工具调用.
-        
-        Args:
-            arguments: Schema查询参数，必须包含schema_name
-            
-        Returns:
-            格式化的Schema详细信息文本
-        """
-        schema_name = arguments.get("schema_name", "")
-        
+        schema_name = str(arguments.get("schema_name") or "")
         if not schema_name:
             return "错误：必须提供 schema_name 参数"
-        
-        schema, found = get_schema_details.get_schema_details(
-            self.service_model, schema_name
-        )
-        
-        if not found:
+        schema = get_schema_details.get_schema_details(self.service_model, schema_name)
+        if schema is None:
             return f"未找到Schema: {schema_name}"
-        
         return get_schema_details.format_schema_details(schema, self.service_model)
+
+    def _handle_explain_parameter(self, arguments: Dict[str, Any]) -> str:
+        endpoint_id = str(arguments.get("endpoint_id") or "")
+        parameter_name = str(arguments.get("parameter_name") or "")
+        if not endpoint_id or not parameter_name:
+            return "错误：必须提供 endpoint_id 与 parameter_name 参数"
+        try:
+            explanation = explain_parameter.explain_parameter(
+                self.service_model, endpoint_id, parameter_name
+            )
+        except explain_parameter.ParameterLookupError as exc:
+            return str(exc)
+        return explain_parameter.format_parameter_explanation(
+            explanation, self.service_model
+        )
+{{- if .Invoke}}
+
+    def _handle_call_endpoint(self, arguments: Dict[str, Any]) -> str:
+        endpoint_id = str(arguments.get("endpoint_id") or "")
+        params = arguments.get("params") or {}
+        if not endpoint_id:
+            return "错误：必须提供 endpoint_id 参数"
+        if not isinstance(params, dict):
+            return "错误：params 必须是对象"
+        args = call_endpoint.CallArgs(endpoint_id, params, arguments.get("body"))
+        try:
+            result = call_endpoint.call_endpoint(self.service_model, args)
+        except call_endpoint.CallError as exc:
+            return str(exc)
+        return call_endpoint.format_call_result(result)
+{{- end}}
+
+    @staticmethod
+    def _error(
+        msg_id: Optional[JsonRpcId],
+        code: int,
+        message: str,
+        data: Any = None,
+    ) -> JsonRpcResponse:
+        """构造错误响应."""
+        error = JsonRpcError(code=code, message=message, data=data)
+        return JsonRpcResponse(id=msg_id, error=error)
+
+    @staticmethod
+    def _send_response(response: JsonRpcResponse) -> None:
+        """以单行 JSON 写出响应."""
+        sys.stdout.write(json.dumps(response.to_dict(), ensure_ascii=False) + "\n")
+        sys.stdout.flush()
 `
 
-// MethodsInitPyTemplate methods/__init__.py方法导出模板
-const MethodsInitPyTemplate = `"""
-MCP 工具方法实现包
-提供所有 API 查询和文档功能
+// TelemetryPyTemplate telemetry.py 工具调用追踪模板, opentelemetry-api 为可选依赖
+const TelemetryPyTemplate = `"""工具调用的 OpenTelemetry 追踪.
 
-Generated by swagger2mcp  
+安装可选依赖 otel (opentelemetry-api) 后, 每次工具调用记录一个 span; 未安装, 或未配置
+SDK 与导出器时为空操作。
+
+Generated by swagger2mcp
 """
 
-from .list_endpoints import format_endpoints_overview
-from .search_endpoints import search_endpoints, format_search_results
-from .get_endpoint_details import get_endpoint_details, format_endpoint_details
-from .list_schemas import list_schemas, format_schemas_list
-from .get_schema_details import get_schema_details, format_schema_details
-
-__all__ = [
-    'format_endpoints_overview',
-    'search_endpoints',
-    'format_search_results',
-    'get_endpoint_details', 
-    'format_endpoint_details',
-    'list_schemas',
-    'format_schemas_list',
-    'get_schema_details',
-    'format_schema_details'
-]
+import time
+from typing import Any, Callable, Dict
+
+try:
+    from opentelemetry import trace  # pylint: disable=import-error
+except ImportError:  # 未安装可选依赖 otel
+    OTEL_AVAILABLE = False
+else:
+    OTEL_AVAILABLE = True
+
+Handler = Callable[[Dict[str, Any]], str]
+
+
+def trace_tool(name: str, handler: Handler) -> Handler:
+    """返回在名为 "tools/call <name>" 的 span 中运行 handler 的处理函数.
+
+    span 带属性 mcp.tool.name 与 mcp.tool.duration_ms, handler 抛出异常时记录异常并标记为
+    错误。未安装 opentelemetry-api 时原样返回 handler。
+
+    Args:
+        name: 工具名称
+        handler: 工具处理函数
+
+    Returns:
+        记录 span 的处理函数
+    """
+    if not OTEL_AVAILABLE:
+        return handler
+    tracer = trace.get_tracer(__name__)
+
+    def traced(arguments: Dict[str, Any]) -> str:
+        start = time.perf_counter()
+        with tracer.start_as_current_span(
+            f"tools/call {name}",
+            kind=trace.SpanKind.SERVER,
+            attributes={"mcp.tool.name": name},
+        ) as span:
+            try:
+                return handler(arguments)
+            finally:
+                elapsed = (time.perf_counter() - start) * 1000
+                span.set_attribute("mcp.tool.duration_ms", elapsed)
+
+    return traced
+`
+
+// MethodsInitPyTemplate methods/__init__.py方法导出模板
+const MethodsInitPyTemplate = `"""MCP 工具方法实现.
+
+每个子模块实现一个 MCP 工具:
+
+- list_endpoints: 端点概览 (listEndpoints)
+- search_endpoints: 端点搜索 (searchEndpoints)
+- get_endpoint_details: 端点详情 (getEndpointDetails)
+- list_schemas: 数据模型列表 (listSchemas)
+- get_schema_details: 数据模型详情 (getSchemaDetails)
+- explain_parameter: 单个参数说明 (explainParameter)
+{{- if .Invoke}}
+- call_endpoint: 调用上游 API (callEndpoint)
+{{- end}}
+
+子模块按名称导入, 例如 ` + "`" + `from {{.PackageName}}.mcp.methods import list_endpoints` + "`" + `。
+
+Generated by swagger2mcp
+"""
+`
+
+// FormattingPyTemplate mcp/methods/formatting.py共用格式化函数模板
+const FormattingPyTemplate = `"""MCP 工具方法共用的格式化辅助函数.
+
+Generated by swagger2mcp
+"""
+
+import json
+from typing import Any, Dict, List
+
+from ...spec.model import Schema
+
+METHOD_EMOJIS = {
+    "GET": "🔍",
+    "POST": "➕",
+    "PUT": "✏️",
+    "PATCH": "🔧",
+    "DELETE": "🗑️",
+    "HEAD": "👁️",
+    "OPTIONS": "⚙️",
+    "TRACE": "🔄",
+}
+
+TYPE_EMOJIS = {
+    "object": "📦",
+    "array": "📋",
+    "string": "📝",
+    "number": "🔢",
+    "integer": "🔢",
+    "boolean": "☑️",
+    "null": "⚫",
+}
+
+STATUS_EMOJIS = {
+    "2": "✅",
+    "3": "🔄",
+    "4": "❌",
+    "5": "💥",
+}
+
+
+def method_emoji(method: str) -> str:
+    """获取HTTP方法对应的emoji."""
+    return METHOD_EMOJIS.get(method.upper(), "📡")
+
+
+def type_emoji(schema_type: str) -> str:
+    """获取Schema类型对应的emoji."""
+    return TYPE_EMOJIS.get(schema_type.lower(), "📄")
+
+
+def status_emoji(status: str) -> str:
+    """获取HTTP状态码对应的emoji."""
+    return STATUS_EMOJIS.get(status[:1], "📡")
+
+
+def truncate(text: str, limit: int) -> str:
+    """把文本截断到 limit 个字符以内, 截断时以省略号结尾."""
+    if len(text) <= limit:
+        return text
+    cut = limit - 3
+    return text[:cut] + "..."
+
+
+def format_example(example: Any) -> str:
+    """格式化示例值."""
+    if example is None:
+        return "null"
+    if isinstance(example, str):
+        return f'"{truncate(example, 49)}"'
+    if isinstance(example, (dict, list)):
+        return truncate(str(example), 99)
+    return str(example)
+
+
+def format_extensions(extensions: Dict[str, Any]) -> List[str]:
+    """格式化 x-* 扩展字段, 按键名排序."""
+    lines: List[str] = []
+    for key in sorted(extensions):
+        value = json.dumps(extensions[key], ensure_ascii=False, sort_keys=True)
+        lines.append(f"- **{key}**: ` + "`" + `{value}` + "`" + `")
+    return lines
+
+
+def format_constraints(schema: Schema) -> str:
+    """格式化数组与数值约束, 例如 "minItems=2, uniqueItems"."""
+    parts: List[str] = []
+    if schema.min_items is not None:
+        parts.append(f"minItems={schema.min_items}")
+    if schema.max_items is not None:
+        parts.append(f"maxItems={schema.max_items}")
+    if schema.unique_items:
+        parts.append("uniqueItems")
+    if schema.minimum is not None:
+        comparator = ">" if schema.exclusive_minimum else ">="
+        parts.append(f"minimum{comparator}{schema.minimum}")
+    if schema.maximum is not None:
+        comparator = "<" if schema.exclusive_maximum else "<="
+        parts.append(f"maximum{comparator}{schema.maximum}")
+    return ", ".join(parts)
 `
 
 // ListEndpointsPyTemplate list_endpoints.py模板
-const ListEndpointsPyTemplate = `"""
-列出所有API端点的实现
-提供端点概览、统计信息和格式化显示
+const ListEndpointsPyTemplate = `"""listEndpoints 工具实现.
+
+提供端点概览、统计信息和格式化显示。
 
 Generated by swagger2mcp
 """
 
-from typing import List, Dict, Any, Counter
-from {{.PackageName}}.spec.model import ServiceModel, EndpointModel
+from collections import Counter
+from typing import Dict, List
+
+from ...spec.model import EndpointModel, ServiceModel, name_key
+from .formatting import method_emoji, truncate
+
+TIP = "💡 **提示**: 使用 ` + "`" + `searchEndpoints` + "`" + ` 搜索特定接口，使用 ` + "`" + `getEndpointDetails` + "`" + ` 查看接口详情"
 
 
 def format_endpoints_overview(service_model: ServiceModel) -> str:
-    """
-    格式化API端点概览信息
-    
+    """格式化 API 端点概览信息.
+
     Args:
         service_model: 服务模型
-        
+
     Returns:
-        格式化的概览文本
+        Markdown 格式的概览文本
     """
-    if not service_model or not service_model.endpoints:
+    if not service_model.endpoints:
         return "## 📋 API 接口概览\n\n暂无可用的API端点。"
-    
-    # 统计信息
-    total_endpoints = len(service_model.endpoints)
-    method_stats = {}
-    tag_stats = {}
-    
-    for endpoint in service_model.endpoints:
-        # 统计HTTP方法
-        method = endpoint.method.upper()
-        method_stats[method] = method_stats.get(method, 0) + 1
-        
-        # 统计标签
-        for tag in endpoint.tags:
-            tag_stats[tag] = tag_stats.get(tag, 0) + 1
-    
-    # 构建概览文本
-    overview = []
-    overview.append("## 📋 API 接口概览")
-    overview.append("")
-    overview.append(f"**服务名称**: {service_model.title}")
-    overview.append(f"**版本**: {service_model.version}")
-    
+
+    lines = ["## 📋 API 接口概览", ""]
+    lines.extend(_format_service_info(service_model))
+    lines.extend(
+        _format_statistics(service_model.endpoints, service_model.tag_descriptions)
+    )
+    lines.extend(_format_endpoint_groups(service_model.endpoints))
+    lines.extend(_format_schema_names(service_model))
+    lines.extend(["---", TIP])
+    return "\n".join(lines)
+
+
+def _format_service_info(service_model: ServiceModel) -> List[str]:
+    lines = [
+        f"**服务名称**: {service_model.title}",
+        f"**版本**: {service_model.version}",
+    ]
     if service_model.description:
-        overview.append(f"**描述**: {service_model.description}")
-    
-    # 服务器信息
+        lines.append(f"**描述**: {service_model.description}")
     if service_model.servers:
-        overview.append("")
-        overview.append("### 🌐 服务器")
-        for i, server in enumerate(service_model.servers):
-            overview.append(f"{i+1}. **{server.url}**")
+        lines.extend(["", "### 🌐 服务器"])
+        for i, server in enumerate(service_model.servers, start=1):
+            marker = " *(开发环境)*" if server.development else ""
+            lines.append(f"{i}. **{server.url}**{marker}")
             if server.description:
-                overview.append(f"   - {server.description}")
-    
-    # 统计信息
-    overview.append("")
-    overview.append("### 📊 接口统计")
-    overview.append(f"**总计**: {total_endpoints} 个接口")
-    
-    # HTTP方法统计
-    if method_stats:
-        overview.append("")
-        overview.append("**按方法分类**:")
-        for method, count in sorted(method_stats.items()):
-            emoji = _get_method_emoji(method)
-            overview.append(f"- {emoji} {method}: {count} 个")
-    
-    # 标签统计
+                lines.append(f"   - {server.description}")
+    return lines
+
+
+def _format_statistics(
+    endpoints: List[EndpointModel], tag_descriptions: Dict[str, str]
+) -> List[str]:
+    method_stats = Counter(endpoint.method.upper() for endpoint in endpoints)
+    tag_stats = Counter(tag for endpoint in endpoints for tag in endpoint.tags)
+    lines = ["", "### 📊 接口统计", f"**总计**: {len(endpoints)} 个接口"]
+    lines.extend(["", "**按方法分类**:"])
+    for method, count in sorted(method_stats.items()):
+        lines.append(f"- {method_emoji(method)} {method}: {count} 个")
     if tag_stats:
-        overview.append("")
-        overview.append("**按标签分类**:")
-        for tag, count in sorted(tag_stats.items(), key=lambda x: x[1], reverse=True):
-            overview.append(f"- 🏷️ {tag}: {count} 个")
-    
-    # 端点列表
-    overview.append("")
-    overview.append("### 📝 接口列表")
-    overview.append("")
-    
-    # 按标签分组显示端点
-    endpoints_by_tag = {}
-    untagged_endpoints = []
-    
-    for endpoint in service_model.endpoints:
-        if endpoint.tags:
-            for tag in endpoint.tags:
-                if tag not in endpoints_by_tag:
-                    endpoints_by_tag[tag] = []
-                endpoints_by_tag[tag].append(endpoint)
-        else:
-            untagged_endpoints.append(endpoint)
-    
-    # 显示有标签的端点
-    for tag in sorted(endpoints_by_tag.keys()):
-        overview.append(f"#### 🏷️ {tag}")
-        overview.append("")
-        endpoints = endpoints_by_tag[tag]
-        for endpoint in sorted(endpoints, key=lambda e: (e.method, e.path)):
-            overview.append(_format_endpoint_line(endpoint))
-        overview.append("")
-    
-    # 显示无标签的端点
-    if untagged_endpoints:
-        overview.append("#### 📂 其他接口")
-        overview.append("")
-        for endpoint in sorted(untagged_endpoints, key=lambda e: (e.method, e.path)):
-            overview.append(_format_endpoint_line(endpoint))
-        overview.append("")
-    
-    # Schema信息
-    if service_model.schemas:
-        overview.append("### 📋 数据模型")
-        overview.append(f"**可用Schema**: {len(service_model.schemas)} 个")
-        schema_names = sorted(service_model.schemas.keys())[:10]  # 只显示前10个
-        for name in schema_names:
-            overview.append(f"- 📄 {name}")
-        if len(service_model.schemas) > 10:
-            overview.append(f"- ... 还有 {len(service_model.schemas) - 10} 个")
-    
-    overview.append("")
-    overview.append("---")
-    overview.append("💡 **提示**: 使用 ` + "`" + `searchEndpoints` + "`" + ` 搜索特定接口，使用 ` + "`" + `getEndpointDetails` + "`" + ` 查看接口详情")
-    
-    return "\n".join(overview)
-
-
-def _get_method_emoji(method: str) -> str:
-    """获取HTTP方法对应的emoji"""
-    emojis = {
-        "GET": "🔍",
-        "POST": "➕", 
-        "PUT": "✏️",
-        "PATCH": "🔧",
-        "DELETE": "🗑️",
-        "HEAD": "👁️",
-        "OPTIONS": "⚙️",
-        "TRACE": "🔄"
-    }
-    return emojis.get(method.upper(), "📡")
+        lines.extend(["", "**按标签分类**:"])
+        for tag, count in tag_stats.most_common():
+            desc = tag_descriptions.get(tag)
+            lines.append(f"- 🏷️ {tag}: {count} 个" + (f" — {desc}" if desc else ""))
+    return lines
 
 
-def _format_endpoint_line(endpoint: EndpointModel) -> str:
-    """格式化单个端点行"""
-    emoji = _get_method_emoji(endpoint.method)
-    method = endpoint.method.upper()
-    path = endpoint.path
-    summary = endpoint.summary or "无描述"
-    
-    # 限制摘要长度
-    if len(summary) > 50:
-        summary = summary[:47] + "..."
-    
-    return f"- {emoji} **{method}** ` + "`" + `{path}` + "`" + ` - {summary}"
+def _format_endpoint_groups(endpoints: List[EndpointModel]) -> List[str]:
+    groups: Dict[str, List[EndpointModel]] = {}
+    untagged: List[EndpointModel] = []
+    for endpoint in endpoints:
+        for tag in endpoint.tags:
+            groups.setdefault(tag, []).append(endpoint)
+        if not endpoint.tags:
+            untagged.append(endpoint)
+
+    lines = ["", "### 📝 接口列表", ""]
+    for tag, tagged in sorted(groups.items(), key=lambda item: name_key(item[0])):
+        lines.extend(_format_group(f"#### 🏷️ {tag}", tagged))
+    if untagged:
+        lines.extend(_format_group("#### 📂 其他接口", untagged))
+    return lines
+
+
+def _format_group(title: str, endpoints: List[EndpointModel]) -> List[str]:
+    lines = [title, ""]
+    ordered = sorted(endpoints, key=lambda item: (item.method, name_key(item.path)))
+    for endpoint in ordered:
+        method = endpoint.method.upper()
+        summary = truncate(endpoint.summary or "无描述", 50)
+        line = f"- {method_emoji(method)} **{method}** ` + "`" + `{endpoint.path}` + "`" + ` - {summary}"
+        lines.append(line)
+    lines.append("")
+    return lines
+
+
+def _format_schema_names(service_model: ServiceModel) -> List[str]:
+    if not service_model.schemas:
+        return []
+    names = sorted(service_model.schemas, key=name_key)
+    lines = ["### 📋 数据模型", f"**可用Schema**: {len(names)} 个"]
+    lines.extend(f"- 📄 {name}" for name in names[:10])
+    if len(names) > 10:
+        lines.append(f"- ... 还有 {len(names) - 10} 个")
+    lines.append("")
+    return lines
 `
 
 // SearchEndpointsPyTemplate search_endpoints.py模板
-const SearchEndpointsPyTemplate = `"""
-搜索API端点的实现
-支持关键字、标签、方法、路径模式搜索
+const SearchEndpointsPyTemplate = `"""searchEndpoints 工具实现.
+
+支持关键字、标签、方法、路径模式搜索。
 
 Generated by swagger2mcp
 """
 
 import re
-from typing import List, Dict, Any, Optional
-from {{.PackageName}}.spec.model import ServiceModel, EndpointModel
+from typing import Any, Dict, List, Optional, Pattern
 
+from ...spec.model import EndpointModel, ServiceModel, name_key
+from .formatting import method_emoji, truncate
+
+FEATURE_LABELS = [
+    ("has_parameters", "📝参数"),
+    ("has_request_body", "📤请求体"),
+    ("has_responses", "📥响应"),
+]
+
+NO_RESULTS = [
+    "❌ **未找到匹配的接口**",
+    "",
+    "**建议**:",
+    "- 检查搜索条件是否正确",
+    "- 尝试使用更宽泛的关键字",
+    "- 使用 ` + "`" + `listEndpoints` + "`" + ` 查看所有可用接口",
+]
+
+
+def search_endpoints(
+    service_model: ServiceModel,
+    search_params: Dict[str, str],
+) -> List[Dict[str, Any]]:
+    """搜索API端点.
 
-def search_endpoints(service_model: ServiceModel, search_params: Dict[str, str]) -> List[Dict[str, Any]]:
-    """
-    搜索API端点
-    
     Args:
         service_model: 服务模型
-        search_params: 搜索参数，包含keyword, tag, method, path_pattern
-        
+        search_params: 搜索参数, 包含 keyword、tag、method、path_pattern
+
     Returns:
-        匹配的端点列表，每个端点包含基本信息
+        匹配的端点列表, 每个端点包含基本信息
     """
-    if not service_model or not service_model.endpoints:
-        return []
-    
     keyword = search_params.get("keyword", "").strip().lower()
     tag_filter = search_params.get("tag", "").strip().lower()
     method_filter = search_params.get("method", "").strip().lower()
     path_pattern = search_params.get("path_pattern", "").strip()
-    
-    results = []
-    
-    # 编译正则表达式（如果提供了路径模式）
-    path_regex = None
+
+    path_regex: Optional[Pattern[str]] = None
     if path_pattern:
         try:
             path_regex = re.compile(path_pattern, re.IGNORECASE)
         except re.error:
-            # 如果正则表达式无效，将其作为普通字符串处理
-            path_regex = None
+            # 无效的正则表达式按普通关键字处理
             keyword = path_pattern.lower()
-    
-    for endpoint in service_model.endpoints:
-        if _matches_filters(endpoint, keyword, tag_filter, method_filter, path_regex):
-            results.append(_endpoint_to_dict(endpoint))
-    
-    return results
-
 
-def _matches_filters(endpoint: EndpointModel, keyword: str, tag_filter: str, 
-                    method_filter: str, path_regex: Optional[re.Pattern]) -> bool:
-    """检查端点是否匹配搜索条件"""
-    
-    # 方法过滤
+    return [
+        _endpoint_to_dict(endpoint)
+        for endpoint in service_model.endpoints
+        if _matches(endpoint, keyword, tag_filter, method_filter, path_regex)
+    ]
+
+
+def _matches(
+    endpoint: EndpointModel,
+    keyword: str,
+    tag_filter: str,
+    method_filter: str,
+    path_regex: Optional[Pattern[str]],
+) -> bool:
+    """检查端点是否匹配所有搜索条件."""
     if method_filter and endpoint.method.lower() != method_filter:
         return False
-    
-    # 标签过滤
-    if tag_filter:
-        tag_matches = any(tag_filter in tag.lower() for tag in endpoint.tags)
-        if not tag_matches:
-            return False
-    
-    # 路径正则表达式匹配
-    if path_regex:
-        if not path_regex.search(endpoint.path):
-            return False
-    
-    # 关键字搜索（在路径、摘要、描述中查找）
+    if tag_filter and not any(tag_filter in tag.lower() for tag in endpoint.tags):
+        return False
+    if path_regex is not None and not path_regex.search(endpoint.path):
+        return False
     if keyword:
-        search_text = " ".join([
-            endpoint.path.lower(),
-            endpoint.summary.lower() if endpoint.summary else "",
-            endpoint.description.lower() if endpoint.description else "",
-            " ".join(endpoint.tags).lower()
-        ])
-        
-        if keyword not in search_text:
-            return False
-    
+        fields = [endpoint.path, endpoint.summary, endpoint.description]
+        return keyword in " ".join(fields + endpoint.tags).lower()
     return True
 
 
 def _endpoint_to_dict(endpoint: EndpointModel) -> Dict[str, Any]:
-    """将端点模型转换为字典格式"""
+    """将端点模型转换为字典格式."""
     return {
         "id": endpoint.id,
         "method": endpoint.method.upper(),
         "path": endpoint.path,
-        "summary": endpoint.summary or "",
-        "description": endpoint.description or "",
-        "tags": endpoint.tags or [],
-        "has_parameters": len(endpoint.parameters) > 0,
+        "summary": endpoint.summary,
+        "description": endpoint.description,
+        "tags": list(endpoint.tags),
+        "has_parameters": bool(endpoint.parameters),
         "has_request_body": endpoint.request_body is not None,
-        "has_responses": len(endpoint.responses) > 0
+        "has_responses": bool(endpoint.responses),
     }
 
 
-def format_search_results(results: List[Dict[str, Any]], search_params: Dict[str, str]) -> str:
-    """
-    格式化搜索结果
-    
+def format_search_results(
+    results: List[Dict[str, Any]],
+    search_params: Dict[str, str],
+) -> str:
+    """格式化搜索结果.
+
     Args:
         results: 搜索结果列表
         search_params: 搜索参数
-        
+
     Returns:
-        格式化的搜索结果文本
+        Markdown 格式的搜索结果文本
     """
-    if not results:
-        return _format_no_results(search_params)
-    
-    output = []
-    output.append("## 🔍 接口搜索结果")
-    output.append("")
-    
-    # 显示搜索条件
+    lines = ["## 🔍 接口搜索结果", ""]
     conditions = _format_search_conditions(search_params)
     if conditions:
-        output.append(f"**搜索条件**: {conditions}")
-        output.append("")
-    
-    output.append(f"**找到 {len(results)} 个匹配的接口**")
-    output.append("")
-    
-    # 按方法分组显示结果
-    results_by_method = {}
+        lines.extend([f"**搜索条件**: {conditions}", ""])
+    if not results:
+        return "\n".join(lines + NO_RESULTS)
+
+    lines.extend([f"**找到 {len(results)} 个匹配的接口**", ""])
+    by_method: Dict[str, List[Dict[str, Any]]] = {}
     for result in results:
-        method = result["method"]
-        if method not in results_by_method:
-            results_by_method[method] = []
-        results_by_method[method].append(result)
-    
-    for method in sorted(results_by_method.keys()):
-        endpoints = results_by_method[method]
-        emoji = _get_method_emoji(method)
-        output.append(f"### {emoji} {method} ({len(endpoints)} 个)")
-        output.append("")
-        
-        for endpoint in sorted(endpoints, key=lambda e: e["path"]):
-            output.append(_format_result_item(endpoint))
-        output.append("")
-    
-    output.append("---")
-    output.append("💡 **提示**: 使用 ` + "`" + `getEndpointDetails` + "`" + ` 查看接口详情")
-    
-    return "\n".join(output)
+        by_method.setdefault(result["method"], []).append(result)
+    for method, endpoints in sorted(by_method.items()):
+        lines.extend([f"### {method_emoji(method)} {method} ({len(endpoints)} 个)", ""])
+        for endpoint in sorted(endpoints, key=lambda item: name_key(str(item["path"]))):
+            lines.append(_format_result_item(endpoint))
+        lines.append("")
+    lines.extend(["---", "💡 **提示**: 使用 ` + "`" + `getEndpointDetails` + "`" + ` 查看接口详情"])
+    return "\n".join(lines)
 
 
 def _format_search_conditions(search_params: Dict[str, str]) -> str:
-    """格式化搜索条件"""
-    conditions = []
-    
+    """格式化搜索条件."""
+    conditions: List[str] = []
     if search_params.get("keyword"):
         conditions.append(f"关键字='{search_params['keyword']}'")
     if search_params.get("tag"):
@@ -1209,1207 +1546,2157 @@ def _format_search_conditions(search_params: Dict[str, str]) -> str:
         conditions.append(f"方法={search_params['method'].upper()}")
     if search_params.get("path_pattern"):
         conditions.append(f"路径模式='{search_params['path_pattern']}'")
-    
     return ", ".join(conditions)
 
 
-def _format_no_results(search_params: Dict[str, str]) -> str:
-    """格式化无结果消息"""
-    conditions = _format_search_conditions(search_params)
-    output = []
-    output.append("## 🔍 接口搜索结果")
-    output.append("")
-    
-    if conditions:
-        output.append(f"**搜索条件**: {conditions}")
-        output.append("")
-    
-    output.append("❌ **未找到匹配的接口**")
-    output.append("")
-    output.append("**建议**:")
-    output.append("- 检查搜索条件是否正确")
-    output.append("- 尝试使用更宽泛的关键字")
-    output.append("- 使用 ` + "`" + `listEndpoints` + "`" + ` 查看所有可用接口")
-    
-    return "\n".join(output)
-
-
 def _format_result_item(endpoint: Dict[str, Any]) -> str:
-    """格式化单个搜索结果项"""
-    path = endpoint["path"]
-    summary = endpoint["summary"] or "无描述"
-    tags = endpoint["tags"]
-    
-    # 限制摘要长度
-    if len(summary) > 60:
-        summary = summary[:57] + "..."
-    
-    result = f"- ` + "`" + `{path}` + "`" + ` - {summary}"
-    
-    # 添加标签信息
+    """格式化单个搜索结果项."""
+    summary = truncate(str(endpoint["summary"] or "无描述"), 60)
+    result = f"- ` + "`" + `{endpoint['path']}` + "`" + ` - {summary}"
+    tags: List[str] = endpoint["tags"]
     if tags:
-        tag_str = ", ".join(tags[:3])  # 最多显示3个标签
+        tag_text = ", ".join(tags[:3])
         if len(tags) > 3:
-            tag_str += f" (+{len(tags)-3})"
-        result += f" [🏷️ {tag_str}]"
-    
-    # 添加功能标识
-    features = []
-    if endpoint["has_parameters"]:
-        features.append("📝参数")
-    if endpoint["has_request_body"]:  
-        features.append("📤请求体")
-    if endpoint["has_responses"]:
-        features.append("📥响应")
-    
+            tag_text += f" (+{len(tags) - 3})"
+        result += f" [🏷️ {tag_text}]"
+    features = [label for key, label in FEATURE_LABELS if endpoint[key]]
     if features:
         result += f" ({', '.join(features)})"
-    
     return result
-
-
-def _get_method_emoji(method: str) -> str:
-    """获取HTTP方法对应的emoji"""
-    emojis = {
-        "GET": "🔍",
-        "POST": "➕",
-        "PUT": "✏️", 
-        "PATCH": "🔧",
-        "DELETE": "🗑️",
-        "HEAD": "👁️",
-        "OPTIONS": "⚙️",
-        "TRACE": "🔄"
-    }
-    return emojis.get(method.upper(), "📡")
 `
 
 // GetEndpointDetailsPyTemplate get_endpoint_details.py模板
-const GetEndpointDetailsPyTemplate = `"""
-获取API端点详细信息的实现
-支持通过ID或method+path查找端点详情
+const GetEndpointDetailsPyTemplate = `"""getEndpointDetails 工具实现.
+
+支持通过 ID 或 method + path 查找端点详情。
 
 Generated by swagger2mcp
 """
 
-from typing import Tuple, Optional, List, Dict, Any
-from {{.PackageName}}.spec.model import ServiceModel, EndpointModel, SchemaOrRef, ParameterModel, ResponseModel
+from typing import List, Optional
 
+from ...spec.model import (
+    EndpointModel,
+    Media,
+    ParameterModel,
+    RequestBodyModel,
+    ResponseModel,
+    SchemaOrRef,
+    ServiceModel,
+)
+from .formatting import (
+    format_constraints,
+    format_example,
+    format_extensions,
+    method_emoji,
+    status_emoji,
+    truncate,
+)
+
+PARAMETER_LOCATIONS = [
+    ("path", "🛤️ 路径参数"),
+    ("query", "❓ 查询参数"),
+    ("header", "📋 请求头参数"),
+    ("cookie", "🍪 Cookie参数"),
+]
+
+
+def get_endpoint_details(
+    service_model: ServiceModel,
+    id_or_method: str,
+    path: Optional[str] = None,
+) -> Optional[EndpointModel]:
+    """查找端点.
 
-def get_endpoint_details(service_model: ServiceModel, *args) -> Tuple[Optional[EndpointModel], bool]:
-    """
-    获取端点详细信息
-    
     Args:
         service_model: 服务模型
-        *args: 可以是 (endpoint_id,) 或 (method, path)
-        
+        id_or_method: 端点 ID (如 "get /users"); 提供 path 时为 HTTP 方法
+        path: API 路径, 与 HTTP 方法一起使用
+
     Returns:
-        (端点模型或None, 是否找到)
+        找到的端点模型, 未找到时返回 None
     """
-    if not service_model or not service_model.endpoints:
-        return None, False
-    
-    if len(args) == 1:
-        # 通过endpoint_id查找
-        endpoint_id = args[0].strip()
-        for endpoint in service_model.endpoints:
-            if endpoint.id == endpoint_id:
-                return endpoint, True
-    elif len(args) == 2:
-        # 通过method和path查找
-        method = args[0].strip().lower()
-        path = args[1].strip()
-        for endpoint in service_model.endpoints:
-            if endpoint.method.lower() == method and endpoint.path == path:
-                return endpoint, True
-    
-    return None, False
+    if path is None:
+        endpoint_id = id_or_method.strip().lower()
+        matches = [ep for ep in service_model.endpoints if ep.id.lower() == endpoint_id]
+    else:
+        method = id_or_method.strip().lower()
+        matches = [
+            ep
+            for ep in service_model.endpoints
+            if ep.method.lower() == method and ep.path == path.strip()
+        ]
+    return matches[0] if matches else None
 
 
-def format_endpoint_details(endpoint: EndpointModel, service_model: ServiceModel) -> str:
-    """
-    格式化端点详细信息
-    
+def format_endpoint_details(
+    endpoint: EndpointModel,
+    service_model: ServiceModel,
+) -> str:
+    """格式化端点详细信息.
+
     Args:
         endpoint: 端点模型
-        service_model: 服务模型（用于解析Schema引用）
-        
+        service_model: 服务模型, 用于解析 Schema 引用
+
     Returns:
-        格式化的详细信息文本
+        Markdown 格式的详细信息文本
     """
-    output = []
-    
-    # 标题和基本信息
-    emoji = _get_method_emoji(endpoint.method)
     method = endpoint.method.upper()
-    output.append(f"## {emoji} {method} {endpoint.path}")
-    output.append("")
-    
+    lines = [f"## {method_emoji(method)} {method} {endpoint.path}", ""]
     if endpoint.summary:
-        output.append(f"**摘要**: {endpoint.summary}")
-    
+        lines.append(f"**摘要**: {endpoint.summary}")
     if endpoint.description:
-        output.append(f"**描述**: {endpoint.description}")
-        
-    # 标签
+        lines.append(f"**描述**: {endpoint.description}")
     if endpoint.tags:
-        tags_str = " ".join([f"` + "`" + `{tag}` + "`" + `" for tag in endpoint.tags])
-        output.append(f"**标签**: {tags_str}")
-    
-    output.append(f"**端点ID**: ` + "`" + `{endpoint.id}` + "`" + `")
-    output.append("")
-    
-    # 参数
+        tags = " ".join(f"` + "`" + `{tag}` + "`" + `" for tag in endpoint.tags)
+        lines.append(f"**标签**: {tags}")
+    lines.extend([f"**端点ID**: ` + "`" + `{endpoint.id}` + "`" + `", ""])
+    if endpoint.extensions:
+        lines.extend(["### 🧩 扩展字段", ""])
+        lines.extend(format_extensions(endpoint.extensions))
+        lines.append("")
+
     if endpoint.parameters:
-        output.append("### 📝 请求参数")
-        output.append("")
-        output.extend(_format_parameters(endpoint.parameters, service_model))
-        output.append("")
-    
-    # 请求体
-    if endpoint.request_body:
-        output.append("### 📤 请求体")
-        output.append("")
-        output.extend(_format_request_body(endpoint.request_body, service_model))
-        output.append("")
-    
-    # 响应
+        lines.extend(["### 📝 请求参数", ""])
+        lines.extend(_format_parameters(endpoint.parameters, service_model))
+        lines.append("")
+    if endpoint.request_body is not None:
+        lines.extend(["### 📤 请求体", ""])
+        lines.extend(_format_request_body(endpoint.request_body, service_model))
+        lines.append("")
     if endpoint.responses:
-        output.append("### 📥 响应")
-        output.append("")
-        output.extend(_format_responses(endpoint.responses, service_model))
-        output.append("")
-    
-    output.append("---")
-    output.append("💡 **提示**: 使用 ` + "`" + `listSchemas` + "`" + ` 查看可用的数据模型")
-    
-    return "\n".join(output)
-
-
-def _format_parameters(parameters: List[ParameterModel], service_model: ServiceModel) -> List[str]:
-    """格式化参数列表"""
-    output = []
-    
-    # 按位置分组
-    params_by_location = {}
-    for param in parameters:
-        location = param.in_
-        if location not in params_by_location:
-            params_by_location[location] = []
-        params_by_location[location].append(param)
-    
-    # 按位置顺序显示
-    location_order = ["path", "query", "header", "cookie"]
-    location_names = {
-        "path": "🛤️ 路径参数",
-        "query": "❓ 查询参数", 
-        "header": "📋 请求头参数",
-        "cookie": "🍪 Cookie参数"
-    }
-    
-    for location in location_order:
-        if location in params_by_location:
-            params = params_by_location[location]
-            output.append(f"#### {location_names[location]}")
-            output.append("")
-            
-            for param in params:
-                required_indicator = " *(必需)*" if param.required else " *(可选)*"
-                output.append(f"- **{param.name}**{required_indicator}")
-                
-                if param.schema:
-                    schema_info = _format_schema_info(param.schema, service_model)
-                    if schema_info:
-                        output.append(f"  - {schema_info}")
-                
-                output.append("")
-    
-    return output
-
-
-def _format_request_body(request_body, service_model: ServiceModel) -> List[str]:
-    """格式化请求体信息"""
-    output = []
-    
-    required_text = "**必需**" if request_body.required else "**可选**"
-    output.append(f"**是否必需**: {required_text}")
-    output.append("")
-    
+        lines.extend(["### 📥 响应", ""])
+        lines.extend(_format_responses(endpoint.responses, service_model))
+        lines.append("")
+
+    lines.extend(["---", "💡 **提示**: 使用 ` + "`" + `listSchemas` + "`" + ` 查看可用的数据模型"])
+    return "\n".join(lines)
+
+
+def _format_parameters(
+    parameters: List[ParameterModel],
+    service_model: ServiceModel,
+) -> List[str]:
+    """按位置分组格式化参数列表."""
+    lines: List[str] = []
+    for location, title in PARAMETER_LOCATIONS:
+        located = [param for param in parameters if param.in_ == location]
+        if not located:
+            continue
+        lines.extend([f"#### {title}", ""])
+        for param in located:
+            required = " *(必需)*" if param.required else " *(可选)*"
+            lines.append(f"- **{param.name}**{required}")
+            if param.description:
+                lines.append(f"  - 说明: {' '.join(param.description.split())}")
+            if param.schema is not None:
+                lines.append(f"  - {_format_schema_info(param.schema, service_model)}")
+            if param.example is not None:
+                lines.append(f"  - 示例: ` + "`" + `{format_example(param.example)}` + "`" + `")
+            lines.append("")
+    return lines
+
+
+def _format_request_body(
+    request_body: RequestBodyModel,
+    service_model: ServiceModel,
+) -> List[str]:
+    """格式化请求体信息."""
+    required = "**必需**" if request_body.required else "**可选**"
+    lines = [f"**是否必需**: {required}", ""]
     if request_body.content:
-        output.append("**支持的内容类型**:")
-        output.append("")
-        
-        for i, media in enumerate(request_body.content):
-            output.append(f"{i+1}. **{media.mime}**")
-            
-            if media.schema:
-                schema_info = _format_schema_info(media.schema, service_model)
-                if schema_info:
-                    output.append(f"   - {schema_info}")
-            
-            if media.example is not None:
-                output.append(f"   - 示例: ` + "`" + `{_format_example(media.example)}` + "`" + `")
-            
-            output.append("")
-    
-    return output
-
-
-def _format_responses(responses: List[ResponseModel], service_model: ServiceModel) -> List[str]:
-    """格式化响应信息"""
-    output = []
-    
-    for response in sorted(responses, key=lambda r: r.status):
-        status_emoji = _get_status_emoji(response.status)
-        output.append(f"#### {status_emoji} {response.status}")
-        output.append("")
-        
+        lines.extend(["**支持的内容类型**:", ""])
+        lines.extend(_format_media(request_body.content, service_model))
+    return lines
+
+
+def _format_responses(
+    responses: List[ResponseModel],
+    service_model: ServiceModel,
+) -> List[str]:
+    """按状态码顺序格式化响应信息."""
+    lines: List[str] = []
+    for response in sorted(responses, key=lambda item: item.status):
+        lines.extend([f"#### {status_emoji(response.status)} {response.status}", ""])
         if response.description:
-            output.append(f"**描述**: {response.description}")
-            output.append("")
-        
+            lines.extend([f"**描述**: {response.description}", ""])
         if response.content:
-            output.append("**响应内容**:")
-            output.append("")
-            
-            for i, media in enumerate(response.content):
-                output.append(f"{i+1}. **{media.mime}**")
-                
-                if media.schema:
-                    schema_info = _format_schema_info(media.schema, service_model)
-                    if schema_info:
-                        output.append(f"   - {schema_info}")
-                
-                if media.example is not None:
-                    output.append(f"   - 示例: ` + "`" + `{_format_example(media.example)}` + "`" + `")
-                
-                output.append("")
-        
-        output.append("")
-    
-    return output
-
-
-def _format_schema_info(schema_or_ref: SchemaOrRef, service_model: ServiceModel) -> str:
-    """格式化Schema信息"""
-    if schema_or_ref.ref:
-        # 这是一个引用
-        ref_name = schema_or_ref.ref.ref.replace("#/components/schemas/", "")
-        if ref_name in service_model.schemas:
-            schema = service_model.schemas[ref_name]
-            return f"类型: ` + "`" + `{schema.type or 'object'}` + "`" + ` (引用: {ref_name})"
-        else:
+            lines.extend(["**响应内容**:", ""])
+            lines.extend(_format_media(response.content, service_model))
+        lines.append("")
+    return lines
+
+
+def _format_media(media_list: List[Media], service_model: ServiceModel) -> List[str]:
+    """格式化请求体或响应的内容类型列表."""
+    lines: List[str] = []
+    for i, media in enumerate(media_list, start=1):
+        lines.append(f"{i}. **{media.mime}**")
+        if media.schema is not None:
+            lines.append(f"   - {_format_schema_info(media.schema, service_model)}")
+        if media.example is not None:
+            lines.append(f"   - 示例: ` + "`" + `{format_example(media.example)}` + "`" + `")
+        lines.append("")
+    return lines
+
+
+def _format_schema_info(
+    schema_or_ref: SchemaOrRef,
+    service_model: ServiceModel,
+) -> str:
+    """格式化 Schema 的类型摘要."""
+    if schema_or_ref.ref is not None:
+        ref_name = schema_or_ref.ref.name
+        referenced = service_model.schemas.get(ref_name)
+        if referenced is None:
             return f"类型: 引用 {ref_name} (未找到定义)"
-    elif schema_or_ref.schema:
-        # 直接的Schema定义
-        schema = schema_or_ref.schema
-        schema_type = schema.type or "unknown"
-        
-        info_parts = [f"类型: ` + "`" + `{schema_type}` + "`" + `"]
-        
-        if schema.format:
-            info_parts.append(f"格式: ` + "`" + `{schema.format}` + "`" + `")
-        
-        if schema.enum:
-            enum_values = ", ".join([str(v) for v in schema.enum[:3]])
-            if len(schema.enum) > 3:
-                enum_values += f" (+{len(schema.enum)-3})"
-            info_parts.append(f"枚举: {enum_values}")
-        
-        if schema.description:
-            desc = schema.description
-            if len(desc) > 50:
-                desc = desc[:47] + "..."
-            info_parts.append(f"说明: {desc}")
-        
-        return " | ".join(info_parts)
-    
-    return "类型: 未知"
-
-
-def _format_example(example: Any) -> str:
-    """格式化示例值"""
-    if example is None:
-        return "null"
-    elif isinstance(example, str):
-        return f'"{example}"' if len(str(example)) < 50 else f'"{str(example)[:47]}..."'
-    elif isinstance(example, (dict, list)):
-        example_str = str(example)
-        return example_str if len(example_str) < 100 else f"{example_str[:97]}..."
-    else:
-        return str(example)
-
-
-def _get_method_emoji(method: str) -> str:
-    """获取HTTP方法对应的emoji"""
-    emojis = {
-        "GET": "🔍",
-        "POST": "➕",
-        "PUT": "✏️",
-        "PATCH": "🔧", 
-        "DELETE": "🗑️",
-        "HEAD": "👁️",
-        "OPTIONS": "⚙️",
-        "TRACE": "🔄"
-    }
-    return emojis.get(method.upper(), "📡")
-
-
-def _get_status_emoji(status: str) -> str:
-    """获取HTTP状态码对应的emoji"""
-    if status.startswith("2"):
-        return "✅"
-    elif status.startswith("3"):
-        return "🔄"
-    elif status.startswith("4"):
-        return "❌"
-    elif status.startswith("5"):
-        return "💥"
-    else:
-        return "📡"
+        return f"类型: ` + "`" + `{referenced.type or 'object'}` + "`" + ` (引用: {ref_name})"
+    schema = schema_or_ref.schema
+    if schema is None:
+        return "类型: 未知"
+
+    parts = [f"类型: ` + "`" + `{schema.type or 'unknown'}` + "`" + `"]
+    if schema.format:
+        parts.append(f"格式: ` + "`" + `{schema.format}` + "`" + `")
+    if schema.enum:
+        values = ", ".join(str(value) for value in schema.enum[:3])
+        if len(schema.enum) > 3:
+            values += f" (+{len(schema.enum) - 3})"
+        parts.append(f"枚举: {values}")
+    constraints = format_constraints(schema)
+    if constraints:
+        parts.append(f"约束: {constraints}")
+    if schema.description:
+        parts.append(f"说明: {truncate(schema.description, 50)}")
+    return " | ".join(parts)
 `
 
 // ListSchemasPyTemplate list_schemas.py模板
-const ListSchemasPyTemplate = `"""
-列出数据模式(Schema)的实现
-提供Schema摘要信息和列表显示
+const ListSchemasPyTemplate = `"""listSchemas 工具实现.
+
+提供 Schema 摘要信息和列表显示。
 
 Generated by swagger2mcp
 """
 
-from typing import List, Dict, Any
-from {{.PackageName}}.spec.model import ServiceModel, Schema
+from typing import Any, Dict, List
+
+from ...spec.model import Schema, ServiceModel, name_key
+from .formatting import truncate, type_emoji
 
 
 def list_schemas(service_model: ServiceModel) -> List[Dict[str, Any]]:
-    """
-    获取所有Schema的摘要信息
-    
+    """获取所有 Schema 的摘要信息.
+
     Args:
         service_model: 服务模型
-        
+
     Returns:
-        Schema信息列表
+        按名称排序的 Schema 信息列表
     """
-    if not service_model or not service_model.schemas:
-        return []
-    
-    schemas = []
-    for name, schema in service_model.schemas.items():
-        schema_info = {
-            "name": name,
-            "type": schema.type or "object",
-            "description": schema.description or "",
-            "has_properties": bool(schema.properties),
-            "property_count": len(schema.properties) if schema.properties else 0,
-            "has_enum": bool(schema.enum),
-            "enum_count": len(schema.enum) if schema.enum else 0,
-            "has_example": schema.example is not None,
-            "format": schema.format or "",
-            "required_fields": len(schema.required) if schema.required else 0
-        }
-        schemas.append(schema_info)
-    
-    # 按名称排序
-    return sorted(schemas, key=lambda s: s["name"])
+    return [
+        _schema_summary(name, service_model.schemas[name])
+        for name in sorted(service_model.schemas, key=name_key)
+    ]
+
+
+def _schema_summary(name: str, schema: Schema) -> Dict[str, Any]:
+    """生成单个 Schema 的摘要字典."""
+    return {
+        "name": name,
+        "type": schema.type or "object",
+        "description": schema.description,
+        "has_properties": bool(schema.properties),
+        "property_count": len(schema.properties),
+        "has_enum": bool(schema.enum),
+        "enum_count": len(schema.enum),
+        "has_example": schema.example is not None,
+        "format": schema.format,
+        "required_fields": len(schema.required),
+    }
 
 
 def format_schemas_list(schemas: List[Dict[str, Any]]) -> str:
-    """
-    格式化Schema列表显示
-    
+    """格式化 Schema 列表.
+
     Args:
-        schemas: Schema信息列表
-        
+        schemas: Schema 信息列表
+
     Returns:
-        格式化的Schema列表文本
+        Markdown 格式的 Schema 列表文本
     """
     if not schemas:
         return "## 📋 数据模型列表\n\n❌ **暂无可用的数据模型定义**"
-    
-    output = []
-    output.append("## 📋 数据模型 (Schema) 列表")
-    output.append("")
-    output.append(f"**总计**: {len(schemas)} 个数据模型")
-    output.append("")
-    
-    # 按类型分组统计
-    type_stats = {}
-    for schema in schemas:
-        schema_type = schema["type"]
-        type_stats[schema_type] = type_stats.get(schema_type, 0) + 1
-    
-    if len(type_stats) > 1:
-        output.append("**按类型分类**:")
-        for schema_type, count in sorted(type_stats.items()):
-            emoji = _get_type_emoji(schema_type)
-            output.append(f"- {emoji} {schema_type}: {count} 个")
-        output.append("")
-    
-    # 详细列表
-    output.append("### 📝 详细列表")
-    output.append("")
-    
-    # 按类型分组显示
-    schemas_by_type = {}
+
+    by_type: Dict[str, List[Dict[str, Any]]] = {}
     for schema in schemas:
-        schema_type = schema["type"]
-        if schema_type not in schemas_by_type:
-            schemas_by_type[schema_type] = []
-        schemas_by_type[schema_type].append(schema)
-    
-    for schema_type in sorted(schemas_by_type.keys()):
-        type_schemas = schemas_by_type[schema_type]
-        emoji = _get_type_emoji(schema_type)
-        
-        output.append(f"#### {emoji} {schema_type.title()} 类型 ({len(type_schemas)} 个)")
-        output.append("")
-        
-        for schema in sorted(type_schemas, key=lambda s: s["name"]):
-            output.append(_format_schema_item(schema))
-        output.append("")
-    
-    output.append("---")
-    output.append("💡 **提示**: 使用 ` + "`" + `getSchemaDetails` + "`" + ` 查看具体Schema的详细定义")
-    
-    return "\n".join(output)
+        by_type.setdefault(schema["type"], []).append(schema)
+
+    lines = [
+        "## 📋 数据模型 (Schema) 列表",
+        "",
+        f"**总计**: {len(schemas)} 个数据模型",
+        "",
+    ]
+    if len(by_type) > 1:
+        lines.append("**按类型分类**:")
+        for schema_type, grouped in sorted(by_type.items()):
+            emoji = type_emoji(schema_type)
+            lines.append(f"- {emoji} {schema_type}: {len(grouped)} 个")
+        lines.append("")
+
+    lines.extend(["### 📝 详细列表", ""])
+    for schema_type, grouped in sorted(by_type.items()):
+        emoji = type_emoji(schema_type)
+        lines.append(f"#### {emoji} {schema_type.title()} 类型 ({len(grouped)} 个)")
+        lines.append("")
+        for schema in sorted(grouped, key=lambda item: name_key(str(item["name"]))):
+            lines.append(_format_schema_item(schema))
+        lines.append("")
+
+    lines.extend(["---", "💡 **提示**: 使用 ` + "`" + `getSchemaDetails` + "`" + ` 查看具体Schema的详细定义"])
+    return "\n".join(lines)
 
 
 def _format_schema_item(schema: Dict[str, Any]) -> str:
-    """格式化单个Schema项目"""
-    name = schema["name"]
-    description = schema["description"]
-    
-    # 基本信息
-    result = f"- **{name}**"
-    
-    # 描述
-    if description:
-        desc = description
-        if len(desc) > 60:
-            desc = desc[:57] + "..."
-        result += f" - {desc}"
-    
-    # 属性统计
-    details = []
-    
-    if schema["has_properties"] and schema["property_count"] > 0:
+    """格式化单个 Schema 项目."""
+    result = f"- **{schema['name']}**"
+    if schema["description"]:
+        result += f" - {truncate(str(schema['description']), 60)}"
+
+    details: List[str] = []
+    if schema["property_count"]:
         details.append(f"{schema['property_count']} 属性")
-    
-    if schema["required_fields"] > 0:
+    if schema["required_fields"]:
         details.append(f"{schema['required_fields']} 必需")
-    
     if schema["has_enum"]:
         details.append(f"{schema['enum_count']} 枚举值")
-    
     if schema["format"]:
         details.append(f"格式: {schema['format']}")
-    
     if schema["has_example"]:
         details.append("有示例")
-    
     if details:
         result += f" [{', '.join(details)}]"
-    
     return result
-
-
-def _get_type_emoji(schema_type: str) -> str:
-    """获取Schema类型对应的emoji"""
-    emojis = {
-        "object": "📦",
-        "array": "📋",
-        "string": "📝",
-        "number": "🔢",
-        "integer": "🔢",
-        "boolean": "☑️",
-        "null": "⚫"
-    }
-    return emojis.get(schema_type.lower(), "📄")
 `
 
 // GetSchemaDetailsPyTemplate get_schema_details.py模板
-const GetSchemaDetailsPyTemplate = `"""
-获取数据模式(Schema)详细信息的实现  
-支持递归解析Schema引用和复杂结构
+const GetSchemaDetailsPyTemplate = `"""getSchemaDetails 工具实现.
+
+展开 Schema 的属性、数组项目和组合结构, 引用只展示摘要以避免无限递归。
 
 Generated by swagger2mcp
 """
 
-from typing import Tuple, Optional, List, Dict, Any, Set
-from {{.PackageName}}.spec.model import ServiceModel, Schema, SchemaOrRef
+from typing import List, Optional
 
+from ...spec.model import Schema, SchemaOrRef, ServiceModel, name_key
+from .formatting import (
+    format_constraints,
+    format_example,
+    format_extensions,
+    truncate,
+    type_emoji,
+)
+
+
+def get_schema_details(
+    service_model: ServiceModel,
+    schema_name: str,
+) -> Optional[Schema]:
+    """查找指定名称的 Schema.
 
-def get_schema_details(service_model: ServiceModel, schema_name: str) -> Tuple[Optional[Schema], bool]:
-    """
-    获取指定Schema的详细信息
-    
     Args:
         service_model: 服务模型
-        schema_name: Schema名称
-        
+        schema_name: Schema 名称
+
     Returns:
-        (Schema对象或None, 是否找到)
+        找到的 Schema, 未找到时返回 None
     """
-    if not service_model or not service_model.schemas:
-        return None, False
-    
-    schema_name = schema_name.strip()
-    if schema_name in service_model.schemas:
-        return service_model.schemas[schema_name], True
-    
-    return None, False
+    return service_model.schemas.get(schema_name.strip())
 
 
 def format_schema_details(schema: Schema, service_model: ServiceModel) -> str:
-    """
-    格式化Schema详细信息
-    
+    """格式化 Schema 详细信息.
+
     Args:
-        schema: Schema对象
-        service_model: 服务模型（用于解析引用）
-        
+        schema: Schema 对象
+        service_model: 服务模型, 用于解析引用
+
     Returns:
-        格式化的详细信息文本
+        Markdown 格式的详细信息文本
     """
-    output = []
-    visited_refs = set()  # 避免循环引用
-    
-    # 标题和基本信息
-    emoji = _get_type_emoji(schema.type or "object")
-    output.append(f"## {emoji} {schema.name}")
-    output.append("")
-    
+    schema_type = schema.type or "object"
+    lines = [f"## {type_emoji(schema_type)} {schema.name}", ""]
     if schema.description:
-        output.append(f"**描述**: {schema.description}")
-        output.append("")
-    
-    # 基本类型信息
-    output.append("### ℹ️ 基本信息")
-    output.append("")
-    output.append(f"- **类型**: ` + "`" + `{schema.type or 'object'}` + "`" + `")
-    
+        lines.extend([f"**描述**: {schema.description}", ""])
+    lines.extend(["### ℹ️ 基本信息", "", f"- **类型**: ` + "`" + `{schema_type}` + "`" + `"])
     if schema.format:
-        output.append(f"- **格式**: ` + "`" + `{schema.format}` + "`" + `")
-    
+        lines.append(f"- **格式**: ` + "`" + `{schema.format}` + "`" + `")
     if schema.example is not None:
-        output.append(f"- **示例**: ` + "`" + `{_format_example(schema.example)}` + "`" + `")
-    
-    output.append("")
-    
-    # 枚举值
+        lines.append(f"- **示例**: ` + "`" + `{format_example(schema.example)}` + "`" + `")
+    constraints = format_constraints(schema)
+    if constraints:
+        lines.append(f"- **约束**: {constraints}")
+    lines.append("")
+    if schema.extensions:
+        lines.extend(["### 🧩 扩展字段", ""])
+        lines.extend(format_extensions(schema.extensions))
+        lines.append("")
+
     if schema.enum:
-        output.append("### 📝 枚举值")
-        output.append("")
-        for i, value in enumerate(schema.enum):
-            output.append(f"{i+1}. ` + "`" + `{value}` + "`" + `")
-        output.append("")
-    
-    # 属性
+        lines.extend(["### 📝 枚举值", ""])
+        lines.extend(f"{i}. ` + "`" + `{value}` + "`" + `" for i, value in enumerate(schema.enum, start=1))
+        lines.append("")
     if schema.properties:
-        output.append("### 📦 属性")
-        output.append("")
-        output.extend(_format_properties(schema, service_model, visited_refs, level=0))
-        output.append("")
-    
-    # 数组项目
-    if schema.items:
-        output.append("### 📋 数组项目类型")
-        output.append("")
-        output.extend(_format_schema_or_ref(schema.items, service_model, visited_refs, level=0))
-        output.append("")
-    
-    # 组合Schema (allOf, anyOf, oneOf)
-    if schema.all_of:
-        output.append("### 🔗 全部匹配 (allOf)")
-        output.append("")
-        output.extend(_format_schema_list(schema.all_of, service_model, visited_refs, level=0))
-        output.append("")
-    
-    if schema.any_of:
-        output.append("### 🔀 任一匹配 (anyOf)")
-        output.append("")
-        output.extend(_format_schema_list(schema.any_of, service_model, visited_refs, level=0))
-        output.append("")
-    
-    if schema.one_of:
-        output.append("### ⚡ 单一匹配 (oneOf)")
-        output.append("")
-        output.extend(_format_schema_list(schema.one_of, service_model, visited_refs, level=0))
-        output.append("")
-    
-    output.append("---")
-    output.append("💡 **提示**: 使用 ` + "`" + `listSchemas` + "`" + ` 查看所有可用的数据模型")
-    
-    return "\n".join(output)
-
-
-def _format_properties(schema: Schema, service_model: ServiceModel, 
-                      visited_refs: Set[str], level: int) -> List[str]:
-    """格式化属性列表"""
-    output = []
-    
-    if not schema.properties:
-        return output
-    
-    required_fields = set(schema.required or [])
-    
-    for prop_name in sorted(schema.properties.keys()):
-        prop_schema = schema.properties[prop_name]
-        required_indicator = " *(必需)*" if prop_name in required_fields else " *(可选)*"
-        
-        indent = "  " * level
-        output.append(f"{indent}- **{prop_name}**{required_indicator}")
-        
-        # 递归处理属性Schema
-        prop_details = _format_schema_or_ref(prop_schema, service_model, visited_refs, level + 1)
-        output.extend([f"  {line}" for line in prop_details])
-        
-        output.append("")
-    
-    return output
-
-
-def _format_schema_or_ref(schema_or_ref: SchemaOrRef, service_model: ServiceModel,
-                         visited_refs: Set[str], level: int) -> List[str]:
-    """格式化SchemaOrRef对象"""
-    output = []
+        lines.extend(["### 📦 属性", ""])
+        lines.extend(_format_properties(schema, service_model, 0))
+        lines.append("")
+    if schema.items is not None:
+        lines.extend(["### 📋 数组项目类型", ""])
+        lines.extend(_format_schema_or_ref(schema.items, service_model, 0))
+        lines.append("")
+
+    compositions = [
+        ("### 🔗 全部匹配 (allOf)", schema.all_of),
+        ("### 🔀 任一匹配 (anyOf)", schema.any_of),
+        ("### ⚡ 单一匹配 (oneOf)", schema.one_of),
+    ]
+    for title, members in compositions:
+        if members:
+            lines.extend([title, ""])
+            lines.extend(_format_schema_list(members, service_model))
+            lines.append("")
+
+    lines.extend(["---", "💡 **提示**: 使用 ` + "`" + `listSchemas` + "`" + ` 查看所有可用的数据模型"])
+    return "\n".join(lines)
+
+
+def _format_properties(
+    schema: Schema,
+    service_model: ServiceModel,
+    level: int,
+) -> List[str]:
+    """按名称顺序格式化属性列表."""
+    lines: List[str] = []
+    required = set(schema.required)
     indent = "  " * level
-    
-    if schema_or_ref.ref:
-        # 处理引用
-        ref_name = schema_or_ref.ref.ref.replace("#/components/schemas/", "")
-        
-        if ref_name in visited_refs:
-            output.append(f"{indent}📎 引用: ` + "`" + `{ref_name}` + "`" + ` *(循环引用，已访问)*")
-            return output
-        
-        if ref_name in service_model.schemas:
-            visited_refs.add(ref_name)
-            referenced_schema = service_model.schemas[ref_name]
-            
-            output.append(f"{indent}📎 引用: ` + "`" + `{ref_name}` + "`" + ` ({referenced_schema.type or 'object'})")
-            
-            if referenced_schema.description:
-                desc = referenced_schema.description
-                if len(desc) > 80:
-                    desc = desc[:77] + "..."
-                output.append(f"{indent}  - {desc}")
-            
-            # 如果引用的Schema有属性且层级不太深，展示一些基本信息
-            if level < 2 and referenced_schema.properties:
-                output.append(f"{indent}  - 属性: {len(referenced_schema.properties)} 个")
-                if referenced_schema.required:
-                    output.append(f"{indent}  - 必需: {len(referenced_schema.required)} 个")
-            
-            visited_refs.discard(ref_name)
-        else:
-            output.append(f"{indent}📎 引用: ` + "`" + `{ref_name}` + "`" + ` *(未找到定义)*")
-            
-    elif schema_or_ref.schema:
-        # 处理直接Schema定义
-        schema = schema_or_ref.schema
-        schema_type = schema.type or "object"
-        
-        type_info = f"{indent}📄 类型: ` + "`" + `{schema_type}` + "`" + `"
-        if schema.format:
-            type_info += f" (格式: ` + "`" + `{schema.format}` + "`" + `)"
-        output.append(type_info)
-        
-        if schema.description:
-            desc = schema.description
-            if len(desc) > 80:
-                desc = desc[:77] + "..."
-            output.append(f"{indent}  - {desc}")
-        
-        if schema.example is not None:
-            output.append(f"{indent}  - 示例: ` + "`" + `{_format_example(schema.example)}` + "`" + `")
-        
-        # 递归处理嵌套属性（仅在层级不太深时）
-        if level < 3 and schema.properties:
-            output.append(f"{indent}  - 属性:")
-            nested_props = _format_properties(schema, service_model, visited_refs, level + 1)
-            output.extend([f"  {line}" for line in nested_props])
-        elif schema.properties:
-            output.append(f"{indent}  - 属性: {len(schema.properties)} 个")
-        
-        # 处理数组项目
-        if schema.items:
-            output.append(f"{indent}  - 数组项目:")
-            item_details = _format_schema_or_ref(schema.items, service_model, visited_refs, level + 1)
-            output.extend([f"  {line}" for line in item_details])
-        
-        # 处理枚举
-        if schema.enum:
-            enum_values = ", ".join([str(v) for v in schema.enum[:5]])
-            if len(schema.enum) > 5:
-                enum_values += f" (+{len(schema.enum)-5})"
-            output.append(f"{indent}  - 枚举值: {enum_values}")
-    
-    return output
-
-
-def _format_schema_list(schema_list: List[SchemaOrRef], service_model: ServiceModel,
-                       visited_refs: Set[str], level: int) -> List[str]:
-    """格式化Schema列表"""
-    output = []
-    
-    for i, schema_or_ref in enumerate(schema_list):
-        output.append(f"{i+1}.")
-        schema_details = _format_schema_or_ref(schema_or_ref, service_model, visited_refs, level)
-        output.extend([f"   {line}" for line in schema_details])
-        output.append("")
-    
-    return output
-
-
-def _format_example(example: Any) -> str:
-    """格式化示例值"""
-    if example is None:
-        return "null"
-    elif isinstance(example, str):
-        return f'"{example}"' if len(str(example)) < 50 else f'"{str(example)[:47]}..."'
-    elif isinstance(example, (dict, list)):
-        example_str = str(example)
-        return example_str if len(example_str) < 100 else f"{example_str[:97]}..."
+    for name in sorted(schema.properties, key=name_key):
+        prop = schema.properties[name]
+        marker = " *(必需)*" if name in required else " *(可选)*"
+        lines.append(f"{indent}- **{name}**{marker}")
+        details = _format_schema_or_ref(prop, service_model, level + 1)
+        lines.extend(f"  {line}" for line in details)
+        lines.append("")
+    return lines
+
+
+def _format_schema_or_ref(
+    schema_or_ref: SchemaOrRef,
+    service_model: ServiceModel,
+    level: int,
+) -> List[str]:
+    """格式化引用或内联 Schema."""
+    if schema_or_ref.ref is not None:
+        return _format_ref(schema_or_ref.ref.name, service_model, level)
+    if schema_or_ref.schema is not None:
+        return _format_inline(schema_or_ref.schema, service_model, level)
+    return []
+
+
+def _format_ref(ref_name: str, service_model: ServiceModel, level: int) -> List[str]:
+    """格式化 Schema 引用的摘要."""
+    indent = "  " * level
+    referenced = service_model.schemas.get(ref_name)
+    if referenced is None:
+        return [f"{indent}📎 引用: ` + "`" + `{ref_name}` + "`" + ` *(未找到定义)*"]
+
+    lines = [f"{indent}📎 引用: ` + "`" + `{ref_name}` + "`" + ` ({referenced.type or 'object'})"]
+    if referenced.description:
+        lines.append(f"{indent}  - {truncate(referenced.description, 80)}")
+    if level < 2 and referenced.properties:
+        lines.append(f"{indent}  - 属性: {len(referenced.properties)} 个")
+        if referenced.required:
+            lines.append(f"{indent}  - 必需: {len(referenced.required)} 个")
+    return lines
+
+
+def _format_inline(
+    schema: Schema,
+    service_model: ServiceModel,
+    level: int,
+) -> List[str]:
+    """格式化内联 Schema, 嵌套属性最多展开三层."""
+    indent = "  " * level
+    type_info = f"{indent}📄 类型: ` + "`" + `{schema.type or 'object'}` + "`" + `"
+    if schema.format:
+        type_info += f" (格式: ` + "`" + `{schema.format}` + "`" + `)"
+    lines = [type_info]
+    if schema.description:
+        lines.append(f"{indent}  - {truncate(schema.description, 80)}")
+    if schema.example is not None:
+        lines.append(f"{indent}  - 示例: ` + "`" + `{format_example(schema.example)}` + "`" + `")
+
+    if schema.properties and level < 3:
+        lines.append(f"{indent}  - 属性:")
+        nested = _format_properties(schema, service_model, level + 1)
+        lines.extend(f"  {line}" for line in nested)
+    elif schema.properties:
+        lines.append(f"{indent}  - 属性: {len(schema.properties)} 个")
+    if schema.items is not None:
+        lines.append(f"{indent}  - 数组项目:")
+        nested = _format_schema_or_ref(schema.items, service_model, level + 1)
+        lines.extend(f"  {line}" for line in nested)
+    if schema.enum:
+        values = ", ".join(str(value) for value in schema.enum[:5])
+        if len(schema.enum) > 5:
+            values += f" (+{len(schema.enum) - 5})"
+        lines.append(f"{indent}  - 枚举值: {values}")
+    constraints = format_constraints(schema)
+    if constraints:
+        lines.append(f"{indent}  - 约束: {constraints}")
+    return lines
+
+
+def _format_schema_list(
+    members: List[SchemaOrRef],
+    service_model: ServiceModel,
+) -> List[str]:
+    """格式化 allOf/anyOf/oneOf 成员列表."""
+    lines: List[str] = []
+    for i, member in enumerate(members, start=1):
+        lines.append(f"{i}.")
+        details = _format_schema_or_ref(member, service_model, 0)
+        lines.extend(f"   {line}" for line in details)
+        lines.append("")
+    return lines
+`
+
+// ExplainParameterPyTemplate explain_parameter.py模板
+const ExplainParameterPyTemplate = `"""explainParameter 工具实现.
+
+汇总单个参数的位置、Schema (已解析引用)、序列化方式、枚举值、示例与约束,
+并生成该参数出现在 URL、请求头或请求体中的用法示例。
+
+Generated by swagger2mcp
+"""
+
+import json
+from dataclasses import dataclass, field
+from typing import Any, Callable, Dict, List, Optional, Tuple
+from urllib.parse import quote
+
+from ...spec.model import (
+    EndpointModel,
+    ParameterModel,
+    Schema,
+    SchemaOrRef,
+    ServiceModel,
+    name_key,
+)
+from .formatting import format_constraints, format_example, truncate
+
+
+class ParameterLookupError(LookupError):
+    """端点或参数不存在; 消息中附带最接近的名称建议."""
+
+
+# ParameterExplanation mirrors the explanation of the Go and npm tools.
+@dataclass
+class ParameterExplanation:  # pylint: disable=too-many-instance-attributes
+    """单个参数的完整说明."""
+
+    endpoint_id: str
+    name: str
+    in_: str  # path|query|header|cookie, 请求体属性为 body
+    required: bool = False
+    style: str = ""
+    explode: bool = False
+    content_type: str = ""  # 仅请求体属性
+    ref: str = ""
+    schema: Optional[Schema] = None
+    enum: List[Any] = field(default_factory=list)
+    example: Any = None
+    constraints: str = ""
+    usage: str = ""  # 参数在请求中的写法
+
+
+def explain_parameter(
+    service_model: ServiceModel,
+    endpoint_id: str,
+    parameter_name: str,
+) -> ParameterExplanation:
+    """说明端点的一个参数.
+
+    Args:
+        service_model: 服务模型
+        endpoint_id: 端点 ID (如 "get /users/{id}") 或 operationId
+        parameter_name: 参数名; 请求体的顶层属性也可作为参数查询
+
+    Returns:
+        参数说明
+
+    Raises:
+        ParameterLookupError: 端点或参数不存在, 消息中列出最接近的名称
+    """
+    endpoint = find_endpoint(service_model, endpoint_id)
+    if endpoint is None:
+        ids = [ep.id for ep in service_model.endpoints]
+        message = f'endpoint "{endpoint_id}" not found'
+        raise ParameterLookupError(not_found(message, endpoint_id, ids, False))
+    name = parameter_name.strip()
+    names: List[str] = []
+    for param in endpoint.parameters:
+        if param.name == name:
+            return _explain_model_parameter(service_model, endpoint, param)
+        names.append(param.name)
+    body, mime = _request_body_schema(service_model, endpoint)
+    if body is not None:
+        for prop in sorted(body.properties, key=name_key):
+            if prop == name:
+                return _explain_body_property(service_model, endpoint, body, prop, mime)
+            names.append(prop)
+    message = f'parameter "{name}" not found on {endpoint.id}'
+    raise ParameterLookupError(not_found(message, name, names, True))
+
+
+def format_parameter_explanation(
+    explanation: ParameterExplanation,
+    service_model: ServiceModel,
+) -> str:
+    """格式化参数说明.
+
+    Args:
+        explanation: 参数说明
+        service_model: 服务模型, 用于解析数组项目的引用
+
+    Returns:
+        Markdown 格式的说明文本
+    """
+    required = " *(必需)*" if explanation.required else " *(可选)*"
+    lines = [
+        f"## 🧩 参数 {explanation.name}",
+        "",
+        f"**端点**: ` + "`" + `{explanation.endpoint_id}` + "`" + `",
+        f"**位置**: ` + "`" + `{explanation.in_}` + "`" + `{required}",
+    ]
+    if explanation.in_ == "body":
+        lines.append(f"**Content-Type**: ` + "`" + `{explanation.content_type or 'unknown'}` + "`" + `")
+    else:
+        explode = "true" if explanation.explode else "false"
+        lines.append(f"**序列化**: style=` + "`" + `{explanation.style}` + "`" + `, explode=` + "`" + `{explode}` + "`" + `")
+    lines.extend(["", "### 📄 Schema", ""])
+    lines.extend(_format_schema(explanation, service_model))
+    lines.extend(["", "### 🧪 用法", "", "` + "`" + `` + "`" + `` + "`" + `http", explanation.usage, "` + "`" + `` + "`" + `` + "`" + `"])
+    return "\n".join(lines)
+
+
+def _format_schema(
+    explanation: ParameterExplanation,
+    service_model: ServiceModel,
+) -> List[str]:
+    """列出参数 Schema 的类型、枚举值、约束与示例."""
+    schema = explanation.schema
+    lines: List[str] = []
+    if explanation.ref:
+        lines.append(f"- 引用: ` + "`" + `{explanation.ref}` + "`" + `")
+    if schema is None:
+        lines.append("- 类型: 未知")
+        return lines
+    lines.append(f"- 类型: ` + "`" + `{schema.type or 'unknown'}` + "`" + `")
+    if schema.format:
+        lines.append(f"- 格式: ` + "`" + `{schema.format}` + "`" + `")
+    if schema.description:
+        lines.append(f"- 说明: {truncate(schema.description, 80)}")
+    if schema.items is not None:
+        _, items = _resolve_schema(service_model, schema.items)
+        item_type = items.type if items is not None and items.type else "unknown"
+        lines.append(f"- 数组项目类型: ` + "`" + `{item_type}` + "`" + `")
+    if schema.properties:
+        required = set(schema.required)
+        for name in sorted(schema.properties, key=name_key):
+            _, prop = _resolve_schema(service_model, schema.properties[name])
+            prop_type = prop.type if prop is not None and prop.type else "unknown"
+            marker = " *(必需)*" if name in required else ""
+            lines.append(f"- 属性 **{name}**: ` + "`" + `{prop_type}` + "`" + `{marker}")
+    if explanation.enum:
+        values = ", ".join(f"` + "`" + `{value}` + "`" + `" for value in explanation.enum)
+        lines.append(f"- 允许值: {values}")
+    if explanation.constraints:
+        lines.append(f"- 约束: {explanation.constraints}")
+    if explanation.example is not None:
+        lines.append(f"- 示例: ` + "`" + `{format_example(explanation.example)}` + "`" + `")
+    return lines
+
+
+def find_endpoint(service_model: ServiceModel, key: str) -> Optional[EndpointModel]:
+    """按端点 ID (方法不区分大小写) 或 operationId 查找端点."""
+    key = key.strip()
+    method, _, path = key.partition(" ")
+    if path:
+        endpoint_id = f"{method.lower()} {path.strip()}"
+        for endpoint in service_model.endpoints:
+            if endpoint.id == endpoint_id:
+                return endpoint
+    for endpoint in service_model.endpoints:
+        if endpoint.operation_id and endpoint.operation_id == key:
+            return endpoint
+    return None
+
+
+def _explain_model_parameter(
+    service_model: ServiceModel,
+    endpoint: EndpointModel,
+    param: ParameterModel,
+) -> ParameterExplanation:
+    """说明 path/query/header/cookie 参数."""
+    style = param.style or ("form" if param.in_ in ("query", "cookie") else "simple")
+    explode = param.explode if param.explode is not None else style == "form"
+    ref, schema = _resolve_schema(service_model, param.schema)
+    explanation = ParameterExplanation(
+        endpoint_id=endpoint.id,
+        name=param.name,
+        in_=param.in_,
+        required=param.required or param.in_ == "path",
+        style=style,
+        explode=explode,
+        ref=ref,
+        schema=schema,
+    )
+    _describe_schema(explanation, service_model)
+    value = _sample_value(service_model, schema, 0)
+    explanation.usage = _parameter_usage(endpoint, explanation, value)
+    return explanation
+
+
+def _explain_body_property(
+    service_model: ServiceModel,
+    endpoint: EndpointModel,
+    body: Schema,
+    name: str,
+    mime: str,
+) -> ParameterExplanation:
+    """说明请求体的顶层属性."""
+    ref, schema = _resolve_schema(service_model, body.properties[name])
+    explanation = ParameterExplanation(
+        endpoint_id=endpoint.id,
+        name=name,
+        in_="body",
+        required=name in body.required,
+        content_type=mime,
+        ref=ref,
+        schema=schema,
+    )
+    _describe_schema(explanation, service_model)
+    value = _sample_value(service_model, schema, 0)
+    if not mime or "json" in mime:
+        payload = json.dumps({name: value}, indent=2, ensure_ascii=False)
     else:
-        return str(example)
-
-
-def _get_type_emoji(schema_type: str) -> str:
-    """获取Schema类型对应的emoji"""
-    emojis = {
-        "object": "📦",
-        "array": "📋", 
-        "string": "📝",
-        "number": "🔢",
-        "integer": "🔢",
-        "boolean": "☑️",
-        "null": "⚫"
+        payload = f"{quote(name, safe='')}={quote(_scalar(value), safe='')}"
+    request_line = f"{endpoint.method.upper()} {endpoint.path}"
+    content_type = mime or "application/json"
+    explanation.usage = f"{request_line}\nContent-Type: {content_type}\n\n{payload}"
+    return explanation
+
+
+def _describe_schema(
+    explanation: ParameterExplanation,
+    service_model: ServiceModel,
+) -> None:
+    """从 Schema 中复制枚举值、示例与约束; 数组取项目的枚举值."""
+    schema = explanation.schema
+    if schema is None:
+        return
+    explanation.enum = list(schema.enum)
+    explanation.example = schema.example
+    explanation.constraints = format_constraints(schema)
+    if schema.type == "array" and not explanation.enum:
+        _, items = _resolve_schema(service_model, schema.items)
+        if items is not None:
+            explanation.enum = list(items.enum)
+
+
+def _request_body_schema(
+    service_model: ServiceModel,
+    endpoint: EndpointModel,
+) -> Tuple[Optional[Schema], str]:
+    """返回第一个带属性的请求体内容类型的对象 Schema."""
+    if endpoint.request_body is None:
+        return None, ""
+    for media in endpoint.request_body.content:
+        _, schema = _resolve_schema(service_model, media.schema)
+        if schema is not None and schema.properties:
+            return schema, media.mime
+    return None, ""
+
+
+def _resolve_schema(
+    service_model: ServiceModel,
+    schema_or_ref: Optional[SchemaOrRef],
+) -> Tuple[str, Optional[Schema]]:
+    """返回引用的 Schema 名称 (没有引用时为空) 与解析后的 Schema."""
+    if schema_or_ref is None:
+        return "", None
+    if schema_or_ref.ref is None:
+        return "", schema_or_ref.schema
+    name = schema_or_ref.ref.name
+    return name, service_model.schemas.get(name) or schema_or_ref.schema
+
+
+_FORMAT_SAMPLES = {
+    "date": "2024-01-01",
+    "date-time": "2024-01-01T00:00:00Z",
+    "uuid": "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+}
+
+
+def _sample_value(
+    service_model: ServiceModel,
+    schema: Optional[Schema],
+    depth: int,
+) -> Any:
+    """选择用法示例中的取值: Schema 示例、第一个枚举值或对应类型的占位值."""
+    if schema is None:
+        return "value"
+    if schema.example is not None:
+        return schema.example
+    if schema.enum:
+        return schema.enum[0]
+    if schema.type in ("array", "object"):
+        return _sample_container(service_model, schema, depth)
+    minimum = schema.minimum
+    samples: Dict[str, Any] = {
+        "integer": int(minimum) if minimum is not None else 1,
+        "number": minimum if minimum is not None else 1.5,
+        "boolean": True,
     }
-    return emojis.get(schema_type.lower(), "📄")
+    return samples.get(schema.type, _FORMAT_SAMPLES.get(schema.format, "value"))
+
+
+def _sample_container(service_model: ServiceModel, schema: Schema, depth: int) -> Any:
+    """数组取两个枚举值或一个项目示例, 对象取前两个属性; 嵌套最多三层."""
+    if schema.type == "array":
+        _, items = _resolve_schema(service_model, schema.items)
+        if items is not None and len(items.enum) > 1:
+            return [items.enum[0], items.enum[1]]
+        return [] if depth > 2 else [_sample_value(service_model, items, depth + 1)]
+    sample: Dict[str, Any] = {}
+    if depth > 2:
+        return sample
+    for name in sorted(schema.properties, key=name_key)[:2]:
+        _, prop = _resolve_schema(service_model, schema.properties[name])
+        sample[name] = _sample_value(service_model, prop, depth + 1)
+    return sample or {"key": "value"}
+
+
+def _parameter_usage(
+    endpoint: EndpointModel,
+    explanation: ParameterExplanation,
+    value: Any,
+) -> str:
+    """按参数的 style 与 explode 序列化取值, 放入请求行或请求头."""
+    method = endpoint.method.upper()
+    name = explanation.name
+    explode = explanation.explode
+    if explanation.in_ == "path":
+        if explanation.style == "label":
+            serialized = "." + _join_value(value, explode, ".", _path_escape)
+        elif explanation.style == "matrix":
+            serialized = _matrix_value(name, value, explode)
+        else:
+            serialized = _join_value(value, explode, ",", _path_escape)
+        return f"{method} {endpoint.path.replace('{' + name + '}', serialized)}"
+    if explanation.in_ == "header":
+        header = _join_value(value, explode, ",", str)
+        return f"{method} {endpoint.path}\n{name}: {header}"
+    if explanation.in_ == "cookie":
+        cookie = _query_value(explanation, value)
+        return f"{method} {endpoint.path}\nCookie: {cookie}"
+    return f"{method} {endpoint.path}?{_query_value(explanation, value)}"
+
+
+def _path_escape(text: str) -> str:
+    return quote(text, safe="")
+
+
+def _query_value(explanation: ParameterExplanation, value: Any) -> str:
+    """按 form、spaceDelimited、pipeDelimited 或 deepObject 写出查询参数."""
+    name = quote(explanation.name, safe="")
+    if isinstance(value, list):
+        parts = [quote(_scalar(item), safe="") for item in value]
+        separators = {"spaceDelimited": "%20", "pipeDelimited": "|"}
+        if explanation.style in separators:
+            return f"{name}={separators[explanation.style].join(parts)}"
+        if explanation.explode:
+            return "&".join(f"{name}={part}" for part in parts)
+        return f"{name}={','.join(parts)}"
+    if isinstance(value, dict):
+        return _query_object(name, explanation, value)
+    return f"{name}={quote(_scalar(value), safe='')}"
+
+
+def _query_object(
+    name: str,
+    explanation: ParameterExplanation,
+    value: Dict[str, Any],
+) -> str:
+    """写出对象取值: deepObject 为 name[key]=v, explode 为 key=v, 否则逗号连接."""
+    members = [
+        (quote(str(key), safe=""), quote(_scalar(value[key]), safe=""))
+        for key in sorted(value)
+    ]
+    if explanation.style == "deepObject":
+        return "&".join(f"{name}[{key}]={item}" for key, item in members)
+    if explanation.explode:
+        return "&".join(f"{key}={item}" for key, item in members)
+    return f"{name}={','.join(f'{key},{item}' for key, item in members)}"
+
+
+def _join_value(
+    value: Any,
+    explode: bool,
+    sep: str,
+    escape: Callable[[str], str],
+) -> str:
+    """写出 simple 或 label 风格的取值, 数组项目与对象成员以 sep 连接."""
+    if isinstance(value, list):
+        return sep.join(escape(_scalar(item)) for item in value)
+    if isinstance(value, dict):
+        parts: List[str] = []
+        for key in sorted(value):
+            if explode:
+                parts.append(f"{escape(str(key))}={escape(_scalar(value[key]))}")
+            else:
+                parts.extend([escape(str(key)), escape(_scalar(value[key]))])
+        return sep.join(parts)
+    return escape(_scalar(value))
+
+
+def _matrix_value(name: str, value: Any, explode: bool) -> str:
+    if isinstance(value, list) and explode:
+        return "".join(f";{name}={_path_escape(_scalar(item))}" for item in value)
+    return f";{name}={_join_value(value, explode, ',', _path_escape)}"
+
+
+def _scalar(value: Any) -> str:
+    if isinstance(value, str):
+        return value
+    return json.dumps(value, ensure_ascii=False)
+
+
+def not_found(message: str, name: str, candidates: List[str], list_all: bool) -> str:
+    """生成未找到的提示; 没有接近的名称且 list_all 为真时列出全部候选."""
+    suggestions = _closest_names(name, candidates)
+    if not suggestions and list_all:
+        suggestions = candidates
+    if not suggestions:
+        return f"{message}; use searchEndpoints to find it"
+    return f"{message}; did you mean: {', '.join(suggestions)}"
+
+
+def _closest_names(name: str, candidates: List[str]) -> List[str]:
+    """返回最多五个包含 name、被 name 包含或编辑距离很小的候选, 由近到远."""
+    want = name.strip().lower()
+    if not want:
+        return []
+    limit = max(2, len(want) // 3)
+    scored: List[Tuple[int, str]] = []
+    for candidate in candidates:
+        lowered = candidate.lower()
+        distance = _edit_distance(want, lowered)
+        if distance <= limit or want in lowered or lowered in want:
+            scored.append((distance, candidate))
+    scored.sort(key=lambda item: item[0])
+    return [candidate for _, candidate in scored[:5]]
+
+
+def _edit_distance(left: str, right: str) -> int:
+    """Levenshtein 编辑距离."""
+    previous = list(range(len(right) + 1))
+    for i, left_char in enumerate(left, start=1):
+        current = [i]
+        for j, right_char in enumerate(right, start=1):
+            cost = 0 if left_char == right_char else 1
+            best = min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost)
+            current.append(best)
+        previous = current
+    return previous[len(right)]
 `
 
 // TestsInitPyTemplate tests/__init__.py测试包初始化模板
-const TestsInitPyTemplate = `"""
-测试包初始化文件
-为{{.ServiceTitle}} MCP服务器提供测试功能
+const TestsInitPyTemplate = `"""{{.ServiceTitle}} MCP 服务器测试包.
+
+pytest 通过 pyproject.toml 中的 pythonpath 配置导入 src 下的源码。
 
 Generated by swagger2mcp
 """
+`
 
-# 导入常用测试工具和配置
-import sys
+// CallEndpointPyTemplate call_endpoint.py模板, 仅在 --enable-invoke 时生成
+const CallEndpointPyTemplate = `"""callEndpoint 工具实现.
+
+按内嵌的服务模型校验参数与请求体, 再向上游 API 发送请求,
+返回状态码、响应头与截断后的响应体。校验失败时不会发送任何请求。
+
+Generated by swagger2mcp
+"""
+
+import base64
+import json
 import os
-from pathlib import Path
+import re
+import time
+from dataclasses import dataclass, field
+from http import HTTPStatus
+from typing import Any, Dict, List, Mapping, Optional, Tuple
+from urllib.error import HTTPError
+from urllib.parse import quote, urlencode, urlsplit
+from urllib.request import Request, urlopen
+
+from ...spec.loader import BASE_URL_ENV, base_url
+from ...spec.model import EndpointModel, ParameterModel, ServiceModel, name_key
+from .explain_parameter import find_endpoint, not_found
+
+# 单次请求的超时时间, 如 10s、500ms, 或秒数
+CALL_TIMEOUT_ENV = "API_TIMEOUT"
+
+# 未设置或无法解析 API_TIMEOUT 时的超时秒数
+DEFAULT_CALL_TIMEOUT = 30.0
+
+# 请求失败、超时或响应为 429、5xx 时重新发送的次数
+CALL_RETRIES_ENV = "API_RETRIES"
+
+# 未设置或无法解析 API_RETRIES 时的重试次数
+DEFAULT_CALL_RETRIES = 0
+
+# 首次重试前等待的秒数, 之后每次加倍, 最多 MAX_CALL_RETRY_DELAY
+CALL_RETRY_DELAY = 0.1
+MAX_CALL_RETRY_DELAY = 2.0
+
+# 返回的响应体上限, 超出部分丢弃并标记 truncated
+MAX_RESPONSE_BYTES = 64 * 1024
+
+_DURATION = re.compile(r"^(\d+(?:\.\d+)?)(ms|s|m|h)?$")
+_DURATION_UNITS = {"ms": 0.001, "s": 1.0, "m": 60.0, "h": 3600.0}
+
+
+class CallError(Exception):
+    """请求未能发送: 端点、参数、凭据或基础地址无效, 或连接失败."""
+
+
+@dataclass(frozen=True)
+class Credential:
+    """callEndpoint 对一个安全方案的认证方式.
+
+    从环境变量 env_var 读取密钥, 作为 in_ 位置上名为 name 的参数发送。
+    """
+
+    kind: str  # apiKey、bearer 或 basic (env_var 的值为 user:password)
+    in_: str  # header、query 或 cookie
+    name: str
+    env_var: str
+
+
+# callEndpoint 可使用的安全方案凭据, 按方案名索引
+{{.CredentialsTable}}
+
+
+@dataclass
+class CallArgs:
+    """callEndpoint 工具的参数.
+
+    params 按名称给出 path、query、header 与 cookie 参数, 每个参数按端点声明的位置发送。
+    """
+
+    endpoint_id: str
+    params: Dict[str, Any] = field(default_factory=dict)
+    body: Any = None
+
+
+@dataclass
+class CallResult:
+    """上游 API 对 callEndpoint 请求的响应."""
+
+    method: str
+    url: str
+    status: int
+    headers: Dict[str, str]
+    body: str
+    truncated: bool = False  # body 只包含前 MAX_RESPONSE_BYTES 字节
+
+
+@dataclass
+class PreparedRequest:
+    """校验通过、可以发送的请求."""
+
+    method: str
+    url: str
+    headers: Dict[str, str]
+    body: Optional[bytes] = None
+
+
+def call_timeout(env: Optional[Mapping[str, str]] = None) -> float:
+    """返回 API_TIMEOUT 给出的超时秒数, 未设置或无效时为 DEFAULT_CALL_TIMEOUT."""
+    value = (os.environ if env is None else env).get(CALL_TIMEOUT_ENV, "").strip()
+    match = _DURATION.match(value)
+    if match:
+        seconds = float(match.group(1)) * _DURATION_UNITS[match.group(2) or "s"]
+        if seconds > 0:
+            return seconds
+    return DEFAULT_CALL_TIMEOUT
+
+
+def call_retries(env: Optional[Mapping[str, str]] = None) -> int:
+    """返回 API_RETRIES 给出的重试次数, 未设置或无效时为 DEFAULT_CALL_RETRIES."""
+    value = (os.environ if env is None else env).get(CALL_RETRIES_ENV, "").strip()
+    return int(value) if value.isdigit() else DEFAULT_CALL_RETRIES
+
+
+def call_endpoint(
+    service_model: ServiceModel,
+    args: CallArgs,
+    base: str = "",
+) -> CallResult:
+    """校验参数后发送请求并返回响应; 非 2xx 响应同样作为结果返回.
+
+    每次发送受 call_timeout 限制; 请求失败、超时或响应为 429、5xx 时最多重新发送
+    call_retries 次, 返回最后一次的结果。
+
+    Args:
+        service_model: 服务模型
+        args: 端点 ID、参数与请求体
+        base: 基础地址; 为空时使用 API_BASE_URL 或规格中的第一个服务器
+
+    Returns:
+        上游响应
 
-# 确保能够导入源代码包
-src_path = Path(__file__).parent.parent / "src"
-if str(src_path) not in sys.path:
-    sys.path.insert(0, str(src_path))
+    Raises:
+        CallError: 校验失败 (此时不发送请求), 或最后一次发送连接失败、超时
+    """
+    prepared = build_request(service_model, args, base)
+    timeout, retries = call_timeout(), call_retries()
+    delay = CALL_RETRY_DELAY
+    attempt = 0
+    while True:
+        try:
+            result = _send(prepared, timeout)
+        except CallError:
+            if attempt >= retries:
+                raise
+        else:
+            if attempt >= retries or (result.status != 429 and result.status < 500):
+                return result
+        attempt += 1
+        time.sleep(delay)
+        delay = min(delay * 2, MAX_CALL_RETRY_DELAY)
+
+
+def _send(prepared: PreparedRequest, timeout: float) -> CallResult:
+    """发送一次请求, 连接与每次读取最多等待 timeout 秒."""
+    request = Request(
+        prepared.url,
+        data=prepared.body,
+        headers=prepared.headers,
+        method=prepared.method,
+    )
+    try:
+        with urlopen(request, timeout=timeout) as response:
+            return _call_result(prepared, response.status, response.headers, response)
+    except HTTPError as exc:
+        return _call_result(prepared, exc.code, exc.headers, exc)
+    except OSError as exc:
+        raise CallError(f"{prepared.method} {prepared.url}: {exc}") from exc
+
+
+def build_request(
+    service_model: ServiceModel,
+    args: CallArgs,
+    base: str = "",
+) -> PreparedRequest:
+    """按端点的参数与请求体校验 args, 构造指向 base 的请求.
+
+    Raises:
+        CallError: 端点或参数不存在, 缺少必需参数、请求体或凭据, 或基础地址不是绝对 URL
+    """
+    endpoint = find_endpoint(service_model, args.endpoint_id)
+    if endpoint is None:
+        ids = [ep.id for ep in service_model.endpoints]
+        message = f'endpoint "{args.endpoint_id}" not found'
+        raise CallError(not_found(message, args.endpoint_id, ids, False))
+    known = _check_arguments(endpoint, args)
+
+    root = (base or base_url(service_model)).strip()
+    parts = urlsplit(root)
+    if not parts.scheme or not parts.netloc:
+        raise CallError(f'base URL "{root}" is not an absolute URL; set {BASE_URL_ENV}')
+
+    path, query, headers = _encode_parameters(endpoint.path, known, args.params)
+    _apply_credentials(endpoint, query, headers)
+    url = root.rstrip("/") + path
+    if query:
+        url += "?" + urlencode(query)
+    body = None
+    if args.body is not None:
+        body = json.dumps(args.body, ensure_ascii=False).encode("utf-8")
+        if not _has_header(headers, "content-type"):
+            headers["Content-Type"] = "application/json"
+    if not _has_header(headers, "accept"):
+        headers["Accept"] = "application/json, */*;q=0.8"
+    return PreparedRequest(str(endpoint.method).upper(), url, headers, body)
+
+
+def format_call_result(result: CallResult) -> str:
+    """格式化 callEndpoint 工具的响应文本."""
+    try:
+        reason = HTTPStatus(result.status).phrase
+    except ValueError:
+        reason = ""
+    lines = [f"{result.method} {result.url}", f"HTTP {result.status} {reason}".rstrip()]
+    headers = sorted(result.headers, key=name_key)
+    lines.extend(f"{name}: {result.headers[name]}" for name in headers)
+    lines.extend(["", result.body])
+    if result.truncated:
+        lines.append(f"... (truncated to {MAX_RESPONSE_BYTES} bytes)")
+    return "\n".join(lines)
+
+
+def _check_arguments(
+    endpoint: EndpointModel,
+    args: CallArgs,
+) -> Dict[str, ParameterModel]:
+    """确认参数都已声明、必需参数与请求体都已给出, 返回按名称索引的参数."""
+    known: Dict[str, ParameterModel] = {}
+    missing: List[str] = []
+    for param in endpoint.parameters:
+        if param.name in known:
+            continue
+        known[param.name] = param
+        if param.name not in args.params and (param.required or param.in_ == "path"):
+            missing.append(f"{param.name} ({param.in_})")
+    for name in sorted(args.params):
+        if name not in known:
+            message = f'parameter "{name}" not found on {endpoint.id}'
+            raise CallError(not_found(message, name, list(known), True))
+    if missing:
+        raise CallError(f"{endpoint.id}: missing required parameters: {', '.join(missing)}")
+    body = endpoint.request_body
+    if body is not None and body.required and args.body is None:
+        raise CallError(f"{endpoint.id}: a request body is required")
+    return known
+
+
+def _encode_parameters(
+    path: str,
+    known: Dict[str, ParameterModel],
+    params: Dict[str, Any],
+) -> Tuple[str, List[Tuple[str, str]], Dict[str, str]]:
+    """把参数写入路径、查询字符串、请求头与 Cookie."""
+    query: List[Tuple[str, str]] = []
+    headers: Dict[str, str] = {}
+    cookies: List[str] = []
+    for name in sorted(params):
+        param = known[name]
+        values = _param_values(params[name])
+        if param.in_ == "path":
+            escaped = ",".join(quote(value, safe="") for value in values)
+            path = path.replace("{" + name + "}", escaped)
+        elif param.in_ == "query":
+            explode = param.style in ("", "form")
+            if param.explode is not None:
+                explode = param.explode
+            if explode:
+                query.extend((name, value) for value in values)
+            else:
+                query.append((name, _query_delimiter(param.style).join(values)))
+        elif param.in_ == "header":
+            headers[name] = ",".join(values)
+        elif param.in_ == "cookie":
+            cookies.append(f"{name}={quote(','.join(values), safe='')}")
+    if cookies:
+        headers["Cookie"] = "; ".join(cookies)
+    return path, query, headers
+
+
+def _apply_credentials(
+    endpoint: EndpointModel,
+    query: List[Tuple[str, str]],
+    headers: Dict[str, str],
+) -> None:
+    """加入第一个环境变量都已设置的安全要求所需的凭据; 显式给出的参数保持不变.
+
+    Raises:
+        CallError: 端点需要凭据, 但没有一个安全要求能够满足
+    """
+    anonymous = not endpoint.security
+    options: List[str] = []
+    for alternative in endpoint.security:
+        if not alternative:
+            anonymous = True
+            continue
+        if not all(scheme in CREDENTIALS for scheme in alternative):
+            continue
+        credentials = [CREDENTIALS[scheme] for scheme in alternative]
+        unset = [cred.env_var for cred in credentials if not os.environ.get(cred.env_var)]
+        if unset:
+            options.append(" and ".join(unset))
+            continue
+        for cred in credentials:
+            _apply_credential(cred, os.environ[cred.env_var], query, headers)
+        return
+    if anonymous:
+        return
+    if not options:
+        raise CallError(
+            f"{endpoint.id}: missing credentials: "
+            f"no supported security scheme in {endpoint.security}"
+        )
+    raise CallError(f"{endpoint.id}: missing credentials: set {' or '.join(options)}")
+
+
+def _apply_credential(
+    credential: Credential,
+    value: str,
+    query: List[Tuple[str, str]],
+    headers: Dict[str, str],
+) -> None:
+    """按凭据的位置写入查询字符串、请求头或 Cookie."""
+    if credential.kind == "bearer":
+        value = "Bearer " + value
+    elif credential.kind == "basic":
+        value = "Basic " + base64.b64encode(value.encode("utf-8")).decode("ascii")
+    name = credential.name
+    if credential.in_ == "query":
+        if all(key != name for key, _ in query):
+            query.append((name, value))
+    elif credential.in_ == "cookie":
+        cookies = [cookie for cookie in headers.get("Cookie", "").split("; ") if cookie]
+        if not any(cookie.startswith(name + "=") for cookie in cookies):
+            cookies.append(f"{name}={quote(value, safe='')}")
+            headers["Cookie"] = "; ".join(cookies)
+    elif not _has_header(headers, name.lower()):
+        headers[name] = value
+
+
+def _call_result(
+    prepared: PreparedRequest,
+    status: int,
+    headers: Any,
+    stream: Any,
+) -> CallResult:
+    """读取至多 MAX_RESPONSE_BYTES 字节的响应体."""
+    data = stream.read(MAX_RESPONSE_BYTES + 1)
+    return CallResult(
+        method=prepared.method,
+        url=prepared.url,
+        status=status,
+        headers={name: ", ".join(headers.get_all(name)) for name in headers.keys()},
+        body=data[:MAX_RESPONSE_BYTES].decode("utf-8", errors="replace"),
+        truncated=len(data) > MAX_RESPONSE_BYTES,
+    )
+
+
+def _param_values(value: Any) -> List[str]:
+    """把参数值展开为字符串: 数组每个元素一个, 对象写为 JSON."""
+    if value is None:
+        return [""]
+    if isinstance(value, list):
+        return [item for element in value for item in _param_values(element)]
+    if isinstance(value, bool):
+        return ["true" if value else "false"]
+    if isinstance(value, dict):
+        return [json.dumps(value, ensure_ascii=False)]
+    if isinstance(value, float) and value.is_integer():
+        return [str(int(value))]
+    return [str(value)]
+
+
+def _query_delimiter(style: str) -> str:
+    """非 explode 查询参数的多个值之间的分隔符."""
+    if style == "spaceDelimited":
+        return " "
+    if style == "pipeDelimited":
+        return "|"
+    return ","
+
+
+def _has_header(headers: Dict[str, str], name: str) -> bool:
+    return any(key.lower() == name for key in headers)
+`
 
-# 测试配置
-TEST_DATA_DIR = Path(__file__).parent / "data"
-TEMP_DIR = Path(__file__).parent / "temp"
+// TestCallEndpointPyTemplate tests/test_call_endpoint.py 模板, 以本地桩服务器测试 callEndpoint
+const TestCallEndpointPyTemplate = `"""callEndpoint 的单元测试.
 
-# 创建测试所需的目录
-TEST_DATA_DIR.mkdir(exist_ok=True)
-TEMP_DIR.mkdir(exist_ok=True)
+对本地桩服务器调用服务模型中的每个端点, 检查参数替换、凭据与发送前的校验。
 
-__version__ = "1.0.0"
-__author__ = "Generated by swagger2mcp"
+Generated by swagger2mcp
+"""
+
+import base64
+import json
+import re
+import threading
+import time
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+from typing import Any, Dict, Iterator, List
+from urllib.parse import parse_qs, quote, urlsplit
+
+import pytest
+
+from {{.PackageName}}.mcp.methods import call_endpoint
+from {{.PackageName}}.server import MCPServer
+from {{.PackageName}}.spec.loader import BASE_URL_ENV, load_service_model
+from {{.PackageName}}.spec.model import EndpointModel, ServiceModel
+
+RECEIVED: List[Dict[str, Any]] = []
+SLOW: List[str] = []
+FLAKY: List[str] = []
+
+
+class _StubHandler(BaseHTTPRequestHandler):
+    """对每个请求返回 418 与简短的 JSON 响应体, 并记录收到的请求.
+
+    /big 下返回超长响应体, /slow 下 1 秒内不响应, /flaky 下每第三个请求之外都返回 503。
+    """
+
+    def _answer(self) -> None:
+        length = int(self.headers.get("Content-Length") or 0)
+        body = self.rfile.read(length).decode("utf-8") if length else ""
+        if self.path.startswith("/slow"):
+            SLOW.append(self.path)
+            time.sleep(1)
+            self.close_connection = True
+            return
+        if self.path.startswith("/flaky"):
+            FLAKY.append(self.path)
+            self.send_response(418 if len(FLAKY) % 3 == 0 else 503)
+            self.send_header("Content-Length", "0")
+            self.end_headers()
+            return
+        if self.path.startswith("/big"):
+            payload = b"x" * (call_endpoint.MAX_RESPONSE_BYTES + 100)
+            self.send_response(200)
+        else:
+            RECEIVED.append(
+                {
+                    "method": self.command,
+                    "path": self.path,
+                    "headers": dict(self.headers),
+                    "body": body,
+                }
+            )
+            payload = b'{"ok":true}'
+            self.send_response(418)
+        self.send_header("Content-Type", "application/json")
+        self.send_header("Content-Length", str(len(payload)))
+        self.end_headers()
+        if self.command != "HEAD":
+            self.wfile.write(payload)
+
+    do_GET = do_POST = do_PUT = do_PATCH = do_DELETE = _answer
+    do_HEAD = do_OPTIONS = do_TRACE = _answer
+
+    # pylint: disable-next=redefined-builtin
+    def log_message(self, format: str, *args: Any) -> None:
+        """不输出访问日志."""
+
+
+@pytest.fixture(name="stub_url", scope="module")
+def fixture_stub_url() -> Iterator[str]:
+    """在随机端口上启动桩服务器, 返回其地址."""
+    httpd = ThreadingHTTPServer(("127.0.0.1", 0), _StubHandler)
+    thread = threading.Thread(target=httpd.serve_forever, daemon=True)
+    thread.start()
+    yield f"http://127.0.0.1:{httpd.server_address[1]}"
+    httpd.shutdown()
+    httpd.server_close()
+
+
+@pytest.fixture(name="service_model", scope="module")
+def fixture_service_model() -> ServiceModel:
+    """加载生成项目内嵌的服务模型, 没有端点时跳过."""
+    model = load_service_model()
+    if not model.endpoints:
+        pytest.skip("服务模型中没有端点")
+    return model
+
+
+@pytest.fixture(name="credentials", autouse=True)
+def fixture_credentials(monkeypatch: pytest.MonkeyPatch) -> None:
+    """设置所有凭据, 使每次调用都能认证."""
+    for credential in call_endpoint.CREDENTIALS.values():
+        monkeypatch.setenv(credential.env_var, f"secret-{credential.env_var}")
+
+
+def _sample_args(endpoint: EndpointModel) -> call_endpoint.CallArgs:
+    """为每个参数填入占位值, 端点接受请求体时附带请求体."""
+    params = {param.name: f"v-{param.name}" for param in endpoint.parameters}
+    body = {"sample": True} if endpoint.request_body is not None else None
+    return call_endpoint.CallArgs(endpoint.id, params, body)
+
+
+def test_parameter_substitution(service_model: ServiceModel, stub_url: str) -> None:
+    """参数按声明的位置发送, 路径占位符全部替换."""
+    for endpoint in service_model.endpoints:
+        RECEIVED.clear()
+        args = _sample_args(endpoint)
+        result = call_endpoint.call_endpoint(service_model, args, stub_url + "/base")
+        assert result.status == 418, endpoint.id
+        assert len(RECEIVED) == 1, endpoint.id
+        got = RECEIVED[0]
+        url = urlsplit(got["path"])
+        query = parse_qs(url.query)
+        headers = {name.lower(): value for name, value in got["headers"].items()}
+        assert got["method"] == str(endpoint.method).upper()
+        assert url.path.startswith("/base"), endpoint.id
+        for param in endpoint.parameters:
+            want = f"v-{param.name}"
+            if param.in_ == "path":
+                assert "{" + param.name + "}" not in url.path, endpoint.id
+                assert quote(want, safe="") in url.path, endpoint.id
+            elif param.in_ == "query":
+                assert query.get(param.name) == [want], endpoint.id
+            elif param.in_ == "header" and param.name.lower() != "host":
+                assert headers.get(param.name.lower()) == want, endpoint.id
+        if args.body is not None:
+            assert '"sample": true' in got["body"], endpoint.id
+
+
+def test_validation_before_sending(service_model: ServiceModel, stub_url: str) -> None:
+    """未知端点或参数、缺少必需参数或请求体、相对基础地址都在发送前报错."""
+    RECEIVED.clear()
+    with pytest.raises(call_endpoint.CallError, match="not found"):
+        call_endpoint.call_endpoint(
+            service_model, call_endpoint.CallArgs("get /__missing__"), stub_url
+        )
+    for endpoint in service_model.endpoints:
+        for param in endpoint.parameters:
+            if not param.required and param.in_ != "path":
+                continue
+            args = _sample_args(endpoint)
+            del args.params[param.name]
+            with pytest.raises(call_endpoint.CallError, match=re.escape(param.name)):
+                call_endpoint.call_endpoint(service_model, args, stub_url)
+        args = _sample_args(endpoint)
+        args.params["__unknown__"] = "x"
+        with pytest.raises(call_endpoint.CallError, match="__unknown__"):
+            call_endpoint.call_endpoint(service_model, args, stub_url)
+        body = endpoint.request_body
+        if body is not None and body.required:
+            args = _sample_args(endpoint)
+            args.body = None
+            with pytest.raises(call_endpoint.CallError, match="body"):
+                call_endpoint.call_endpoint(service_model, args, stub_url)
+    args = _sample_args(service_model.endpoints[0])
+    with pytest.raises(call_endpoint.CallError, match=BASE_URL_ENV):
+        call_endpoint.call_endpoint(service_model, args, "/relative")
+    assert not RECEIVED
+
+
+def _applied_alternative(endpoint: EndpointModel) -> List[str]:
+    """设置了所有凭据时采用的安全要求: 第一个方案都受支持的要求."""
+    for alternative in endpoint.security:
+        if alternative and all(s in call_endpoint.CREDENTIALS for s in alternative):
+            return alternative
+    return []
+
+
+def _declares(endpoint: EndpointModel, credential: call_endpoint.Credential) -> bool:
+    """端点是否声明了与凭据同名同位置的参数; 显式给出的参数优先于凭据."""
+    return any(
+        param.in_ == credential.in_ and param.name.lower() == credential.name.lower()
+        for param in endpoint.parameters
+    )
+
+
+def _credential_value(credential: call_endpoint.Credential) -> str:
+    """fixture_credentials 设置的凭据在请求中的形式."""
+    value = f"secret-{credential.env_var}"
+    if credential.kind == "bearer":
+        return "Bearer " + value
+    if credential.kind == "basic":
+        return "Basic " + base64.b64encode(value.encode("utf-8")).decode("ascii")
+    return value
+
+
+def test_credentials(service_model: ServiceModel, stub_url: str) -> None:
+    """凭据按安全方案写入请求头、查询字符串或 Cookie."""
+    for endpoint in service_model.endpoints:
+        alternative = _applied_alternative(endpoint)
+        if not alternative:
+            continue
+        RECEIVED.clear()
+        call_endpoint.call_endpoint(service_model, _sample_args(endpoint), stub_url)
+        got = RECEIVED[0]
+        query = parse_qs(urlsplit(got["path"]).query)
+        headers = {name.lower(): value for name, value in got["headers"].items()}
+        for scheme in alternative:
+            credential = call_endpoint.CREDENTIALS[scheme]
+            if _declares(endpoint, credential):
+                continue
+            want = _credential_value(credential)
+            if credential.in_ == "query":
+                assert query.get(credential.name) == [want], endpoint.id
+            elif credential.in_ == "cookie":
+                cookie = f"{credential.name}={quote(want, safe='')}"
+                assert cookie in headers.get("cookie", "").split("; "), endpoint.id
+            else:
+                assert headers.get(credential.name.lower()) == want, endpoint.id
+
+
+def test_missing_credentials(
+    service_model: ServiceModel, stub_url: str, monkeypatch: pytest.MonkeyPatch
+) -> None:
+    """端点需要的凭据都未设置时在发送前报错."""
+    for credential in call_endpoint.CREDENTIALS.values():
+        monkeypatch.delenv(credential.env_var)
+    RECEIVED.clear()
+    for endpoint in service_model.endpoints:
+        if not endpoint.security or not all(endpoint.security):
+            continue
+        args = _sample_args(endpoint)
+        with pytest.raises(call_endpoint.CallError, match="missing credentials"):
+            call_endpoint.call_endpoint(service_model, args, stub_url)
+    assert not RECEIVED
+
+
+def test_truncated_body(service_model: ServiceModel, stub_url: str) -> None:
+    """超过 MAX_RESPONSE_BYTES 的响应体被截断."""
+    endpoint = next(
+        (ep for ep in service_model.endpoints if str(ep.method) != "head"), None
+    )
+    if endpoint is None:
+        pytest.skip("服务模型中只有 HEAD 端点")
+    result = call_endpoint.call_endpoint(
+        service_model, _sample_args(endpoint), stub_url + "/big"
+    )
+    assert result.truncated
+    assert len(result.body) == call_endpoint.MAX_RESPONSE_BYTES
+    assert "truncated" in call_endpoint.format_call_result(result)
+
+
+def test_timeout(
+    service_model: ServiceModel, stub_url: str, monkeypatch: pytest.MonkeyPatch
+) -> None:
+    """API_TIMEOUT 限制每次发送, API_RETRIES 次重试之后报错."""
+    monkeypatch.setenv(call_endpoint.CALL_TIMEOUT_ENV, "50ms")
+    monkeypatch.setenv(call_endpoint.CALL_RETRIES_ENV, "1")
+    SLOW.clear()
+    args = _sample_args(service_model.endpoints[0])
+    start = time.monotonic()
+    with pytest.raises(call_endpoint.CallError, match="timed out"):
+        call_endpoint.call_endpoint(service_model, args, stub_url + "/slow")
+    assert time.monotonic() - start < 1
+    assert len(SLOW) == 2
+
+
+def test_retries(
+    service_model: ServiceModel, stub_url: str, monkeypatch: pytest.MonkeyPatch
+) -> None:
+    """未设置 API_RETRIES 时不重试, 设置后重新发送返回 5xx 的请求."""
+    FLAKY.clear()
+    args = _sample_args(service_model.endpoints[0])
+    result = call_endpoint.call_endpoint(service_model, args, stub_url + "/flaky")
+    assert result.status == 503
+    assert len(FLAKY) == 1
+    monkeypatch.setenv(call_endpoint.CALL_RETRIES_ENV, "2")
+    result = call_endpoint.call_endpoint(service_model, args, stub_url + "/flaky")
+    assert result.status == 418
+    assert len(FLAKY) == 3
+
+
+def test_tool(
+    service_model: ServiceModel, stub_url: str, monkeypatch: pytest.MonkeyPatch
+) -> None:
+    """callEndpoint 工具通过 API_BASE_URL 调用桩服务器."""
+    monkeypatch.setenv(BASE_URL_ENV, stub_url)
+    server = MCPServer(tool_name="{{.ToolName}}")
+    server.initialized = True
+    args = _sample_args(service_model.endpoints[0])
+    arguments = {"endpoint_id": args.endpoint_id, "params": args.params, "body": args.body}
+    response = server.handle_message(
+        json.dumps(
+            {
+                "jsonrpc": "2.0",
+                "id": 1,
+                "method": "tools/call",
+                "params": {"name": "callEndpoint", "arguments": arguments},
+            }
+        )
+    )
+    assert response is not None and response.error is None
+    assert "HTTP 418" in response.result["content"][0]["text"]
+    RECEIVED.clear()
 `
 
 // TestMCPMethodsPyTemplate tests/test_mcp_methods.py MCP方法单元测试模板
-const TestMCPMethodsPyTemplate = `"""
-MCP工具方法的单元测试
-测试所有MCP方法的基本功能和正确性
+const TestMCPMethodsPyTemplate = `"""MCP 工具方法的单元测试.
+
+测试所有 MCP 方法的基本功能和 JSON-RPC 服务器的请求处理。
 
 Generated by swagger2mcp
 """
 
-import pytest
 import json
-from typing import Dict, List, Any, Optional
-from unittest.mock import Mock, patch
+from typing import Any, Callable, Dict, Optional, Tuple
+
+import pytest
 
-# 导入被测试的模块
-from {{.PackageName}}.spec.loader import load_service_model
-from {{.PackageName}}.spec.model import ServiceModel
 from {{.PackageName}}.mcp.methods import (
-    list_endpoints, 
-    search_endpoints, 
-    get_endpoint_details, 
-    list_schemas, 
-    get_schema_details
+    explain_parameter,
+    get_endpoint_details,
+    get_schema_details,
+    list_endpoints,
+    list_schemas,
+    search_endpoints,
 )
+from {{.PackageName}}.server import MCPServer
+from {{.PackageName}}.spec.loader import {{if .ShardModel}}(
+    load_service_model,
+    load_shard,
+    load_tag,
+    read_model_data,
+    shards,
+){{else}}MODEL_PATH, load_service_model{{end}}
+from {{.PackageName}}.spec.model import ParameterModel, ServiceModel, name_key
+
+
+@pytest.fixture(name="service_model", scope="module")
+def fixture_service_model() -> ServiceModel:
+    """加载生成项目内嵌的服务模型."""
+    return load_service_model()
+
+
+@pytest.fixture(name="empty_model")
+def fixture_empty_model() -> ServiceModel:
+    """提供空的服务模型用于边界测试."""
+    return ServiceModel(title="Empty Test API", version="0.0.1")
+
+
+class TestServiceModel:
+    """model.json 加载测试."""
+
+    def test_load_matches_model_json(self, service_model: ServiceModel) -> None:
+        """加载结果与 model.json 中的端点和 Schema 一致."""
+        raw = {{if .ShardModel}}read_model_data(){{else}}json.loads(MODEL_PATH.read_text(encoding="utf-8")){{end}}
+        assert service_model.title == (raw.get("Title") or "")
+        assert len(service_model.endpoints) == len(raw.get("Endpoints") or [])
+        assert set(service_model.schemas) == set(raw.get("Schemas") or {})
+
+    def test_parameter_types_preserved(self, service_model: ServiceModel) -> None:
+        """参数 Schema 的类型和引用从 SchemaOrRef 包装中正确解析."""
+        raw = {{if .ShardModel}}read_model_data(){{else}}json.loads(MODEL_PATH.read_text(encoding="utf-8")){{end}}
+        for raw_endpoint, endpoint in zip(
+            raw.get("Endpoints") or [],
+            service_model.endpoints,
+        ):
+            for raw_param, param in zip(
+                raw_endpoint.get("Parameters") or [],
+                endpoint.parameters,
+            ):
+                wrapper = raw_param.get("Schema") or {}
+                inline = wrapper.get("Schema")
+                if inline:
+                    assert param.schema is not None and param.schema.schema
+                    assert param.schema.schema.type == (inline.get("Type") or "")
+                if wrapper.get("Ref"):
+                    assert param.schema is not None and param.schema.ref
+
+    def test_name_key(self) -> None:
+        """name_key 与生成器的名称排序一致."""
+        names = [
+            "Zebra", "apple", "pet", "Pet", "_id", "éclair", "Eclair", "b", "B2",
+            "a10", "a2", "Ünits", "units", "zoo", "😀", "文件", "ﬁ",
+        ]
+        assert sorted(names, key=name_key) == [
+            "_id", "a10", "a2", "apple", "b", "B2", "Eclair", "Pet", "pet",
+            "units", "Zebra", "zoo", "Ünits", "éclair", "文件", "ﬁ", "😀",
+        ]
+{{- if .ShardModel}}
 
+    def test_shards_cover_model(self, service_model: ServiceModel) -> None:
+        """各分片合起来恰好是模型中的全部端点, 按标签取到的端点数与索引一致."""
+        ids = [endpoint.id for shard in shards() for endpoint in load_shard(shard.name)]
+        assert sorted(ids) == sorted(endpoint.id for endpoint in service_model.endpoints)
+        for shard in shards():
+            assert len(load_tag(shard.tag)) == shard.endpoints
+{{- end}}
 
-class TestMCPMethods:
-    """MCP方法测试类"""
-    
-    @pytest.fixture(scope="class")
-    def service_model(self) -> ServiceModel:
-        """
-        提供测试用的ServiceModel实例
-        
-        Returns:
-            加载的ServiceModel对象
-        """
-        try:
-            model = load_service_model()
-            assert model is not None, "ServiceModel不能为空"
-            return model
-        except Exception as e:
-            pytest.fail(f"加载ServiceModel失败: {e}")
-    
-    @pytest.fixture
-    def mock_empty_service_model(self) -> ServiceModel:
-        """
-        提供空的ServiceModel用于边界测试
-        
-        Returns:
-            空的ServiceModel对象
-        """
-        from {{.PackageName}}.spec.model import ServiceModel
-        return ServiceModel(
-            title="Empty Test API",
-            version="0.0.1", 
-            description="用于测试的空API",
-            servers=[],
-            tags=[],
-            endpoints=[],
-            schemas={}
-        )
-    
-    def test_load_service_model_success(self, service_model: ServiceModel):
-        """测试ServiceModel加载成功"""
-        assert service_model is not None
-        assert hasattr(service_model, 'title')
-        assert hasattr(service_model, 'version')
-        assert hasattr(service_model, 'endpoints')
-        assert hasattr(service_model, 'schemas')
-        print(f"✅ 成功加载ServiceModel: {service_model.title} v{service_model.version}")
-    
-    def test_list_endpoints_overview(self, service_model: ServiceModel):
-        """测试端点概览功能"""
+
+class TestListEndpoints:
+    """listEndpoints 测试."""
+
+    def test_overview(self, service_model: ServiceModel) -> None:
+        """概览包含标题和统计信息."""
         overview = list_endpoints.format_endpoints_overview(service_model)
-        
-        # 基本验证
-        assert isinstance(overview, str), "返回结果应该是字符串"
-        assert len(overview) > 0, "概览内容不能为空"
-        assert "API 接口概览" in overview, "应包含标题"
-        
-        # 如果有端点，应该包含统计信息
+        assert "API 接口概览" in overview
         if service_model.endpoints:
-            assert "总计" in overview, "应包含端点总数统计"
-            assert "按方法分类" in overview or "按标签分类" in overview, "应包含分类统计"
-        
-        print(f"✅ 端点概览生成成功，内容长度: {len(overview)}")
-    
-    def test_list_endpoints_empty_model(self, mock_empty_service_model: ServiceModel):
-        """测试空模型的端点概览"""
-        overview = list_endpoints.format_endpoints_overview(mock_empty_service_model)
-        
-        assert isinstance(overview, str)
+            assert "总计" in overview
+            assert "按方法分类" in overview
+
+    def test_empty_model(self, empty_model: ServiceModel) -> None:
+        """没有端点时给出提示."""
+        overview = list_endpoints.format_endpoints_overview(empty_model)
         assert "暂无可用的API端点" in overview
-        print("✅ 空模型端点概览处理正确")
-    
-    def test_search_endpoints_keyword(self, service_model: ServiceModel):
-        """测试关键字搜索功能"""
-        if not service_model.endpoints:
-            pytest.skip("没有端点数据，跳过搜索测试")
-        
-        # 测试关键字搜索
-        search_params = {
-            "keyword": "api", 
-            "tag": "", 
-            "method": "", 
-            "path_pattern": ""
-        }
-        results = search_endpoints.search_endpoints(service_model, search_params)
-        
-        assert isinstance(results, list), "搜索结果应该是列表"
-        
-        # 如果有结果，验证结果结构
-        if results:
-            result = results[0]
-            required_keys = ["id", "method", "path", "summary", "tags"]
-            for key in required_keys:
-                assert key in result, f"搜索结果应包含字段: {key}"
-        
-        # 测试搜索结果格式化
-        formatted = search_endpoints.format_search_results(results, search_params)
-        assert isinstance(formatted, str)
-        assert "接口搜索结果" in formatted
-        
-        print(f"✅ 关键字搜索测试通过，找到 {len(results)} 个结果")
-    
-    def test_search_endpoints_by_method(self, service_model: ServiceModel):
-        """测试按HTTP方法搜索"""
-        if not service_model.endpoints:
-            pytest.skip("没有端点数据，跳过方法搜索测试")
-        
-        # 获取第一个端点的方法进行搜索
-        first_method = service_model.endpoints[0].method.lower()
-        search_params = {
-            "keyword": "", 
-            "tag": "", 
-            "method": first_method, 
-            "path_pattern": ""
-        }
-        
-        results = search_endpoints.search_endpoints(service_model, search_params)
-        assert isinstance(results, list)
-        
-        # 验证所有结果都是指定的HTTP方法
-        for result in results:
-            assert result["method"].lower() == first_method.upper(), "搜索结果方法应匹配"
-        
-        print(f"✅ HTTP方法搜索测试通过，方法: {first_method.upper()}")
-    
-    def test_search_endpoints_no_results(self, service_model: ServiceModel):
-        """测试搜索无结果的情况"""
-        search_params = {
-            "keyword": "不存在的关键字xyz123", 
-            "tag": "", 
-            "method": "", 
-            "path_pattern": ""
-        }
-        
-        results = search_endpoints.search_endpoints(service_model, search_params)
-        assert isinstance(results, list)
-        assert len(results) == 0, "应该没有搜索结果"
-        
-        formatted = search_endpoints.format_search_results(results, search_params)
-        assert "未找到匹配的接口" in formatted
-        print("✅ 无结果搜索处理正确")
-    
-    def test_get_endpoint_details_by_id(self, service_model: ServiceModel):
-        """测试通过ID获取端点详情"""
+
+
+class TestSearchEndpoints:
+    """searchEndpoints 测试."""
+
+    def test_search_by_method(self, service_model: ServiceModel) -> None:
+        """按方法过滤只返回该方法的端点."""
         if not service_model.endpoints:
-            pytest.skip("没有端点数据，跳过详情查询测试")
-        
-        # 使用第一个端点进行测试
-        endpoint_id = service_model.endpoints[0].id
-        endpoint, found = get_endpoint_details.get_endpoint_details(service_model, endpoint_id)
-        
-        assert found, f"应该找到端点: {endpoint_id}"
-        assert endpoint is not None, "端点对象不应为空"
-        assert endpoint.id == endpoint_id, "返回的端点ID应该匹配"
-        
-        # 测试格式化详情
-        formatted = get_endpoint_details.format_endpoint_details(endpoint, service_model)
-        assert isinstance(formatted, str)
-        assert len(formatted) > 0
-        assert endpoint.method.upper() in formatted
-        assert endpoint.path in formatted
-        
-        print(f"✅ 端点详情查询测试通过，ID: {endpoint_id}")
-    
-    def test_get_endpoint_details_by_method_path(self, service_model: ServiceModel):
-        """测试通过方法和路径获取端点详情"""
+            pytest.skip("服务模型中没有端点")
+        method = service_model.endpoints[0].method.upper()
+        results = search_endpoints.search_endpoints(service_model, {"method": method})
+        assert results
+        assert all(result["method"] == method for result in results)
+
+    def test_search_no_results(self, service_model: ServiceModel) -> None:
+        """没有匹配时输出建议."""
+        params = {"keyword": "no-such-endpoint-keyword"}
+        results = search_endpoints.search_endpoints(service_model, params)
+        assert not results
+        text = search_endpoints.format_search_results(results, params)
+        assert "未找到匹配的接口" in text
+
+
+class TestGetEndpointDetails:
+    """getEndpointDetails 测试."""
+
+    def test_by_id_and_by_method_path(self, service_model: ServiceModel) -> None:
+        """ID 与 method + path 两种方式查找到同一端点."""
         if not service_model.endpoints:
-            pytest.skip("没有端点数据，跳过详情查询测试")
-        
-        # 使用第一个端点进行测试
-        first_endpoint = service_model.endpoints[0]
-        method = first_endpoint.method
-        path = first_endpoint.path
-        
-        endpoint, found = get_endpoint_details.get_endpoint_details(service_model, method, path)
-        
-        assert found, f"应该找到端点: {method} {path}"
-        assert endpoint is not None, "端点对象不应为空"
-        assert endpoint.method == method, "返回的端点方法应该匹配"
-        assert endpoint.path == path, "返回的端点路径应该匹配"
-        
-        print(f"✅ 方法路径查询测试通过，{method} {path}")
-    
-    def test_get_endpoint_details_not_found(self, service_model: ServiceModel):
-        """测试查询不存在的端点"""
-        # 测试不存在的端点ID
-        endpoint, found = get_endpoint_details.get_endpoint_details(service_model, "不存在的ID")
-        assert not found, "应该找不到不存在的端点"
-        assert endpoint is None, "不存在的端点应返回None"
-        
-        # 测试不存在的方法和路径
-        endpoint, found = get_endpoint_details.get_endpoint_details(service_model, "INVALID", "/nonexistent")
-        assert not found, "应该找不到不存在的端点"
-        assert endpoint is None, "不存在的端点应返回None"
-        
-        print("✅ 端点不存在情况处理正确")
-    
-    def test_list_schemas_overview(self, service_model: ServiceModel):
-        """测试Schema列表功能"""
+            pytest.skip("服务模型中没有端点")
+        first = service_model.endpoints[0]
+        by_id = get_endpoint_details.get_endpoint_details(service_model, first.id)
+        by_path = get_endpoint_details.get_endpoint_details(
+            service_model, first.method, first.path
+        )
+        assert by_id is first
+        assert by_path is first
+        details = get_endpoint_details.format_endpoint_details(first, service_model)
+        assert first.path in details
+
+    def test_not_found(self, service_model: ServiceModel) -> None:
+        """未知端点返回 None."""
+        found = get_endpoint_details.get_endpoint_details(
+            service_model, "INVALID", "/nonexistent"
+        )
+        assert found is None
+
+
+class TestSchemas:
+    """listSchemas 与 getSchemaDetails 测试."""
+
+    def test_list_schemas(self, service_model: ServiceModel) -> None:
+        """列表包含所有 Schema 名称."""
         schemas = list_schemas.list_schemas(service_model)
-        assert isinstance(schemas, list), "Schema列表应该是list"
-        
-        # 如果有Schema，验证数据结构
-        if schemas:
-            schema_info = schemas[0]
-            required_keys = ["name", "type", "description", "has_properties", "property_count"]
-            for key in required_keys:
-                assert key in schema_info, f"Schema信息应包含字段: {key}"
-        
-        # 测试格式化显示
-        formatted = list_schemas.format_schemas_list(schemas)
-        assert isinstance(formatted, str)
-        assert "数据模型" in formatted
-        
-        print(f"✅ Schema列表测试通过，共 {len(schemas)} 个Schema")
-    
-    def test_list_schemas_empty_model(self, mock_empty_service_model: ServiceModel):
-        """测试空模型的Schema列表"""
-        schemas = list_schemas.list_schemas(mock_empty_service_model)
-        assert isinstance(schemas, list)
-        assert len(schemas) == 0, "空模型应该没有Schema"
-        
-        formatted = list_schemas.format_schemas_list(schemas)
-        assert "暂无可用的数据模型定义" in formatted
-        print("✅ 空Schema列表处理正确")
-    
-    def test_get_schema_details_success(self, service_model: ServiceModel):
-        """测试Schema详情查询成功情况"""
-        if not service_model.schemas:
-            pytest.skip("没有Schema数据，跳过详情查询测试")
-        
-        # 使用第一个Schema进行测试
-        schema_name = list(service_model.schemas.keys())[0]
-        schema, found = get_schema_details.get_schema_details(service_model, schema_name)
-        
-        assert found, f"应该找到Schema: {schema_name}"
-        assert schema is not None, "Schema对象不应为空"
-        assert schema.name == schema_name, "返回的Schema名称应该匹配"
-        
-        # 测试格式化详情
-        formatted = get_schema_details.format_schema_details(schema, service_model)
-        assert isinstance(formatted, str)
-        assert len(formatted) > 0
-        assert schema_name in formatted
-        
-        print(f"✅ Schema详情查询测试通过，名称: {schema_name}")
-    
-    def test_get_schema_details_not_found(self, service_model: ServiceModel):
-        """测试查询不存在的Schema"""
-        schema, found = get_schema_details.get_schema_details(service_model, "不存在的Schema")
-        assert not found, "应该找不到不存在的Schema"
-        assert schema is None, "不存在的Schema应返回None"
-        print("✅ Schema不存在情况处理正确")
-    
-    def test_all_methods_with_empty_model(self, mock_empty_service_model: ServiceModel):
-        """测试所有方法对空模型的处理"""
-        # 测试所有主要方法都能正确处理空模型
-        
-        # list_endpoints
-        overview = list_endpoints.format_endpoints_overview(mock_empty_service_model)
-        assert "暂无可用的API端点" in overview
-        
-        # search_endpoints
-        results = search_endpoints.search_endpoints(mock_empty_service_model, {"keyword": "test"})
-        assert len(results) == 0
-        
-        # get_endpoint_details
-        endpoint, found = get_endpoint_details.get_endpoint_details(mock_empty_service_model, "test")
-        assert not found
-        
-        # list_schemas
-        schemas = list_schemas.list_schemas(mock_empty_service_model)
-        assert len(schemas) == 0
-        
-        # get_schema_details
-        schema, found = get_schema_details.get_schema_details(mock_empty_service_model, "test")
-        assert not found
-        
-        print("✅ 所有方法的空模型处理测试通过")
-    
-    @pytest.mark.parametrize("method_name,args", [
-        ("list_endpoints", []),
-        ("search_endpoints", [{"keyword": "test"}]),
-        ("get_endpoint_details", ["test_id"]),
-        ("list_schemas", []),
-        ("get_schema_details", ["test_schema"]),
-    ])
-    def test_method_error_handling(self, method_name: str, args: List[Any], service_model: ServiceModel):
-        """测试方法的错误处理"""
-        # 这个测试确保所有方法都能处理各种输入而不崩溃
-        try:
-            if method_name == "list_endpoints":
-                list_endpoints.format_endpoints_overview(service_model)
-            elif method_name == "search_endpoints":
-                search_endpoints.search_endpoints(service_model, args[0])
-            elif method_name == "get_endpoint_details":
-                get_endpoint_details.get_endpoint_details(service_model, args[0])
-            elif method_name == "list_schemas":
-                list_schemas.list_schemas(service_model)
-            elif method_name == "get_schema_details":
-                get_schema_details.get_schema_details(service_model, args[0])
-        except Exception as e:
-            pytest.fail(f"方法 {method_name} 应该能处理输入而不抛出异常: {e}")
-        
-        print(f"✅ 方法 {method_name} 错误处理测试通过")
-    
-    def test_search_performance(self, service_model: ServiceModel):
-        """测试搜索性能"""
-        if not service_model.endpoints:
-            pytest.skip("没有端点数据，跳过性能测试")
-        
-        import time
-        
-        # 测试搜索性能 - 应该能在合理时间内完成
-        start_time = time.time()
-        
-        for _ in range(10):  # 执行10次搜索
-            search_endpoints.search_endpoints(service_model, {"keyword": "api"})
-        
-        end_time = time.time()
-        avg_time = (end_time - start_time) / 10
-        
-        # 平均搜索时间应该小于100ms（相对宽松的限制）
-        assert avg_time < 0.1, f"搜索性能过慢: {avg_time:.4f}s per search"
-        print(f"✅ 搜索性能测试通过，平均耗时: {avg_time:.4f}s")
-    
-    def test_output_format_consistency(self, service_model: ServiceModel):
-        """测试输出格式的一致性"""
-        # 确保所有方法的输出都是字符串且包含预期的格式标记
-        
-        if service_model.endpoints:
-            # 端点概览格式
-            overview = list_endpoints.format_endpoints_overview(service_model)
-            assert "##" in overview, "应包含Markdown标题格式"
-            assert "**" in overview, "应包含Markdown粗体格式"
-            
-            # 搜索结果格式
-            results = search_endpoints.search_endpoints(service_model, {"keyword": ""})
-            formatted = search_endpoints.format_search_results(results, {"keyword": ""})
-            assert "##" in formatted, "搜索结果应包含标题"
-            
-            # 端点详情格式
-            endpoint, found = get_endpoint_details.get_endpoint_details(service_model, service_model.endpoints[0].id)
-            if found:
-                formatted = get_endpoint_details.format_endpoint_details(endpoint, service_model)
-                assert "##" in formatted, "端点详情应包含标题"
-        
-        if service_model.schemas:
-            # Schema列表格式
-            schemas = list_schemas.list_schemas(service_model)
-            formatted = list_schemas.format_schemas_list(schemas)
-            assert "##" in formatted, "Schema列表应包含标题"
-            
-            # Schema详情格式
-            schema_name = list(service_model.schemas.keys())[0]
-            schema, found = get_schema_details.get_schema_details(service_model, schema_name)
-            if found:
-                formatted = get_schema_details.format_schema_details(schema, service_model)
-                assert "##" in formatted, "Schema详情应包含标题"
-        
-        print("✅ 输出格式一致性测试通过")
-
-
-# 测试运行配置
-if __name__ == "__main__":
-    # 可以直接运行此文件进行测试
-    pytest.main([__file__, "-v", "--tb=short"])
+        names = sorted(service_model.schemas, key=name_key)
+        assert [item["name"] for item in schemas] == names
+        text = list_schemas.format_schemas_list(schemas)
+        for name in service_model.schemas:
+            assert name in text
+
+    def test_schema_details(self, service_model: ServiceModel) -> None:
+        """每个 Schema 都能格式化详情."""
+        for name in service_model.schemas:
+            schema = get_schema_details.get_schema_details(service_model, name)
+            assert schema is not None
+            text = get_schema_details.format_schema_details(schema, service_model)
+            assert name in text
+        missing = get_schema_details.get_schema_details(service_model, "NoSuchSchema")
+        assert missing is None
+
+
+def _find_parameter(
+    service_model: ServiceModel,
+    match: Callable[[ParameterModel], bool],
+) -> Optional[Tuple[str, str]]:
+    """返回第一个满足 match 的参数所在端点 ID 与参数名."""
+    for endpoint in service_model.endpoints:
+        for param in endpoint.parameters:
+            if match(param):
+                return endpoint.id, param.name
+    return None
+
+
+class TestExplainParameter:
+    """explainParameter 测试."""
+
+    def test_query_enum(self, service_model: ServiceModel) -> None:
+        """带枚举的查询参数给出允许值和查询字符串写法."""
+        def has_enum(param: ParameterModel) -> bool:
+            inline = param.schema.schema if param.schema is not None else None
+            return param.in_ == "query" and inline is not None and bool(inline.enum)
+
+        found = _find_parameter(service_model, has_enum)
+        if found is None:
+            pytest.skip("服务模型中没有带枚举的查询参数")
+        endpoint_id, name = found
+        explanation = explain_parameter.explain_parameter(
+            service_model, endpoint_id, name
+        )
+        assert explanation.in_ == "query"
+        assert explanation.enum
+        assert f"?{name}=" in explanation.usage
+        text = explain_parameter.format_parameter_explanation(
+            explanation, service_model
+        )
+        assert "允许值" in text
+
+    def test_path(self, service_model: ServiceModel) -> None:
+        """路径参数必需, 用法中已替换占位符; 拼错的名称给出建议."""
+        found = _find_parameter(service_model, lambda p: p.in_ == "path")
+        if found is None:
+            pytest.skip("服务模型中没有路径参数")
+        endpoint_id, name = found
+        explanation = explain_parameter.explain_parameter(
+            service_model, endpoint_id, name
+        )
+        assert explanation.required
+        assert "{" + name + "}" not in explanation.usage
+        with pytest.raises(explain_parameter.ParameterLookupError, match=name):
+            explain_parameter.explain_parameter(service_model, endpoint_id, name + "x")
+
+    def test_unknown_endpoint(self, service_model: ServiceModel) -> None:
+        """未知端点抛出 ParameterLookupError."""
+        with pytest.raises(explain_parameter.ParameterLookupError):
+            explain_parameter.explain_parameter(
+                service_model, "get /no-such-endpoint", "id"
+            )
+
+
+class TestServer:
+    """JSON-RPC 服务器测试."""
+
+    @staticmethod
+    def _call(
+        server: MCPServer,
+        method: str,
+        params: Optional[Dict[str, Any]] = None,
+    ) -> Dict[str, Any]:
+        message = {"jsonrpc": "2.0", "id": 1, "method": method, "params": params}
+        response = server.handle_message(json.dumps(message))
+        assert response is not None
+        return response.to_dict()
+
+    def test_tools_round_trip(self) -> None:
+        """initialize 之后可以列出并调用所有工具."""
+        server = MCPServer(tool_name="{{.ToolName}}")
+        init = self._call(server, "initialize", {})
+        assert init["result"]["serverInfo"]["name"] == "{{.ToolName}}"
+
+        tools = self._call(server, "tools/list")["result"]["tools"]
+        assert {tool["name"] for tool in tools} == set(server.tools)
+
+        result = self._call(server, "tools/call", {"name": "listEndpoints"})
+        assert "API 接口概览" in result["result"]["content"][0]["text"]
+
+    def test_errors(self) -> None:
+        """错误请求返回 JSON-RPC 错误码."""
+        server = MCPServer(tool_name="{{.ToolName}}")
+        parse_error = server.handle_message("{not json")
+        assert parse_error is not None and parse_error.error is not None
+        assert parse_error.error.code == MCPServer.PARSE_ERROR
+        unknown = self._call(server, "no/such/method")
+        assert unknown["error"]["code"] == MCPServer.METHOD_NOT_FOUND
+        notification = {"jsonrpc": "2.0", "method": "notifications/initialized"}
+        assert server.handle_message(json.dumps(notification)) is None
+`
+
+// TestTelemetryPyTemplate tests/test_telemetry.py 模板, 测试追踪不改变工具结果
+const TestTelemetryPyTemplate = `"""工具调用追踪的单元测试.
+
+未配置 SDK 与导出器 (或未安装 opentelemetry-api) 时, 工具经追踪包装后结果不变。
+
+Generated by swagger2mcp
+"""
+
+import json
+from typing import Any, Dict
+
+import pytest
+
+from {{.PackageName}}.mcp.methods import get_schema_details, list_endpoints
+from {{.PackageName}}.server import MCPServer
+from {{.PackageName}}.telemetry import trace_tool
+
+
+def _call_tool(server: MCPServer, name: str, arguments: Dict[str, Any]) -> str:
+    """经 tools/call 调用工具, 返回结果文本."""
+    message = {
+        "jsonrpc": "2.0",
+        "id": 1,
+        "method": "tools/call",
+        "params": {"name": name, "arguments": arguments},
+    }
+    response = server.handle_message(json.dumps(message))
+    assert response is not None and response.error is None
+    text: str = response.result["content"][0]["text"]
+    return text
+
+
+def test_tools_without_exporter() -> None:
+    """工具经 tools/call 返回与直接调用相同的结果."""
+    server = MCPServer(tool_name="{{.ToolName}}")
+    initialize = {"jsonrpc": "2.0", "id": 0, "method": "initialize"}
+    server.handle_message(json.dumps(initialize))
+    sm = server.service_model
+    overview = list_endpoints.format_endpoints_overview(sm)
+    assert _call_tool(server, "listEndpoints", {}) == overview
+    for name in sorted(sm.schemas)[:1]:
+        schema = get_schema_details.get_schema_details(sm, name)
+        assert schema is not None
+        want = get_schema_details.format_schema_details(schema, sm)
+        assert _call_tool(server, "getSchemaDetails", {"schema_name": name}) == want
+
+
+def test_trace_tool_reraises() -> None:
+    """处理函数抛出的异常原样传出."""
+
+    def failing(_arguments: Dict[str, Any]) -> str:
+        raise ValueError("boom")
+
+    with pytest.raises(ValueError, match="boom"):
+        trace_tool("failing", failing)({})
+`
+
+// TestTransportPyTemplate tests/test_transport.py HTTP 传输测试模板
+const TestTransportPyTemplate = `"""传输方式的单元测试.
+
+测试 --transport 参数解析、监听地址以及 /mcp 上的 Streamable HTTP 请求处理。
+
+Generated by swagger2mcp
+"""
+
+import json
+import threading
+import urllib.error
+import urllib.request
+from typing import Any, Dict, Iterator, Optional, Tuple
+
+import pytest
+
+from {{.PackageName}}.main import parse_args
+from {{.PackageName}}.server import MCPServer
+from {{.PackageName}}.transport import MCP_PATH, create_http_server, http_address
+
+
+def test_parse_args() -> None:
+    """默认 stdio, 只接受 stdio 和 http."""
+    assert parse_args([]).transport == "stdio"
+    assert parse_args(["--transport", "http"]).transport == "http"
+    with pytest.raises(SystemExit):
+        parse_args(["--transport", "sse"])
+
+
+@pytest.mark.parametrize(
+    ("env", "want"),
+    [
+        ({}, ("127.0.0.1", 8080)),
+        ({"PORT": "9000"}, ("0.0.0.0", 9000)),
+        ({"PORT": "9000", "MCP_HTTP_ADDR": "localhost:7000"}, ("localhost", 7000)),
+        ({"MCP_HTTP_ADDR": "[::1]:7000"}, ("::1", 7000)),
+        ({"MCP_HTTP_ADDR": ":7000"}, ("0.0.0.0", 7000)),
+    ],
+)
+def test_http_address(env: Dict[str, str], want: Tuple[str, int]) -> None:
+    """MCP_HTTP_ADDR 优先于 PORT, 都未设置时使用默认地址."""
+    assert http_address(env) == want
+
+
+@pytest.mark.parametrize(
+    "addr", ["localhost", "localhost:", "localhost:http", "localhost:70000"]
+)
+def test_http_address_invalid(addr: str) -> None:
+    """无效地址报错."""
+    with pytest.raises(ValueError):
+        http_address({"MCP_HTTP_ADDR": addr})
+
+
+@pytest.fixture(name="mcp_url")
+def fixture_mcp_url() -> Iterator[str]:
+    """在随机端口上启动 HTTP 传输, 返回 /mcp 的地址."""
+    httpd = create_http_server(MCPServer(tool_name="{{.ToolName}}"), "127.0.0.1", 0)
+    thread = threading.Thread(target=httpd.serve_forever, daemon=True)
+    thread.start()
+    yield f"http://127.0.0.1:{httpd.server_address[1]}{MCP_PATH}"
+    httpd.shutdown()
+    httpd.server_close()
+
+
+def _request(url: str, body: Optional[bytes] = None) -> Tuple[int, Any]:
+    headers = {"Content-Type": "application/json"}
+    request = urllib.request.Request(url, data=body, headers=headers)
+    try:
+        with urllib.request.urlopen(request, timeout=5) as response:
+            data = response.read()
+            return response.status, json.loads(data) if data else None
+    except urllib.error.HTTPError as exc:
+        return exc.code, None
+
+
+def test_http_round_trip(mcp_url: str) -> None:
+    """请求返回 JSON 响应, 通知返回 202."""
+    message = {"jsonrpc": "2.0", "id": 1, "method": "tools/list"}
+    status, payload = _request(mcp_url, json.dumps(message).encode("utf-8"))
+    assert status == 200
+    assert payload["id"] == 1 and payload["result"]["tools"]
+
+    notification = {"jsonrpc": "2.0", "method": "notifications/initialized"}
+    assert _request(mcp_url, json.dumps(notification).encode("utf-8"))[0] == 202
+
+
+def test_http_errors(mcp_url: str) -> None:
+    """解析错误返回 400, GET 返回 405, 其他路径返回 404."""
+    assert _request(mcp_url, b"{not json")[0] == 400
+    assert _request(mcp_url)[0] == 405
+    assert _request(mcp_url + "/other", b"{}")[0] == 404
+`
+
+// TestDescribePyTemplate tests/test_describe.py --describe 输出测试模板
+const TestDescribePyTemplate = `"""--describe 的单元测试.
+
+在进程内运行 main(["--describe"]), 校验输出的 JSON 摘要。
+
+Generated by swagger2mcp
+"""
+
+import json
+import re
+
+import pytest
+
+from {{.PackageName}}.main import main, parse_args
+from {{.PackageName}}.server import TOOL_SPECS
+from {{.PackageName}}.spec.loader import load_service_model, model_hash
+from {{.PackageName}}.spec.model import name_key
+
+
+def test_parse_args_describe() -> None:
+    """--describe 默认关闭."""
+    assert parse_args([]).describe is False
+    assert parse_args(["--describe"]).describe is True
+
+
+def test_describe(capsys: pytest.CaptureFixture[str]) -> None:
+    """输出 API 标题与版本、端点与模式数量、模型哈希及排序后的工具列表."""
+    main(["--describe"])
+    data = json.loads(capsys.readouterr().out)
+
+    model = load_service_model()
+    assert data["title"] == model.title
+    assert data["version"] == model.version
+    assert data["endpoints"] == len(model.endpoints)
+    assert data["schemas"] == len(model.schemas)
+    assert re.fullmatch(r"[0-9a-f]{64}", data["modelHash"])
+    assert data["modelHash"] == model_hash()
+
+    names = [tool["name"] for tool in data["tools"]]
+    assert names == sorted(TOOL_SPECS, key=name_key)
+    for tool in data["tools"]:
+        assert tool["description"] == TOOL_SPECS[tool["name"]]["description"]
 `
 
 // ReadmeMdTemplate README.md项目文档模板
@@ -2426,12 +3713,68 @@ const ReadmeMdTemplate = `# {{.ServiceTitle}} MCP 工具
 - **详细信息**: 获取端点参数、请求体、响应的详细信息
 - **数据模型**: 浏览和查看Schema定义
 - **MCP协议**: 遵循MCP协议标准，与各种AI客户端兼容
+{{- if .Tags}}
+
+## 标签
+{{range .Tags}}
+- {{.Name}}{{if .Description}} — {{.Description}}{{end}}
+{{- end}}
+{{- end}}
 
 ## 快速开始
 
 ### 系统要求
 
-- Python 3.8 或更高版本
+- Python {{.PythonVersion}} 或更高版本{{if ne .PythonRequires (printf ">=%s" .PythonVersion)}}（` + "`" + `{{.PythonRequires}}` + "`" + `）{{end}}
+{{- if eq .BuildTool "poetry"}}
+- Poetry 1.2 或更高版本（依赖与虚拟环境由 Poetry 管理）
+
+### 安装
+
+1. 生成锁文件（首次）:
+{{- if .Skip.Makefile}}
+   rm poetry.lock && poetry lock
+   生成的 poetry.lock 只是占位文件，需先删除，再由 poetry lock 解析依赖重新生成，之后请将其提交到版本库。
+{{- else}}
+   make lock
+   生成的 poetry.lock 只是占位文件，make lock 会用 poetry 解析依赖并替换它，之后请将其提交到版本库。
+{{- end}}
+
+2. 安装依赖:
+   poetry install --only main
+
+3. 安装开发依赖（可选）:
+   poetry install
+
+### 使用方法
+
+作为MCP服务器运行:
+poetry run {{.ToolName}}
+{{- else if eq .BuildTool "uv"}}
+- uv 0.5 或更高版本（依赖与虚拟环境由 uv 管理）
+
+### 安装
+
+1. 生成锁文件（首次）:
+{{- if .Skip.Makefile}}
+   rm uv.lock && uv lock
+   生成的 uv.lock 只是占位文件，需先删除，再由 uv lock 解析依赖重新生成，之后请将其提交到版本库。
+{{- else}}
+   make lock
+   生成的 uv.lock 只是占位文件，make lock 会用 uv 解析依赖并替换它，之后请将其提交到版本库。
+{{- end}}
+
+2. 安装依赖:
+   uv sync --no-dev
+
+3. 安装开发依赖（可选）:
+   uv sync
+
+### 使用方法
+
+作为MCP服务器运行:
+uv run {{.ToolName}}
+{{- else}}
 - pip 包管理器
 
 ### 安装
@@ -2446,14 +3789,94 @@ const ReadmeMdTemplate = `# {{.ServiceTitle}} MCP 工具
 
 作为MCP服务器运行:
 python -m {{.PackageName}}.main
+{{- end}}
+
+### 切换环境
+
+请求默认指向规格中的第一个服务器地址。设置环境变量 API_BASE_URL 可让同一份构建指向其他环境（如预发布环境），其优先级高于规格中的服务器列表:
+API_BASE_URL=https://staging.example.com {{if eq .BuildTool "poetry"}}poetry run {{.ToolName}}{{else if eq .BuildTool "uv"}}uv run {{.ToolName}}{{else}}python -m {{.PackageName}}.main{{end}}
+
+### HTTP 传输
+
+默认通过标准输入输出通信；加上 --transport http 则以 Streamable HTTP 提供 MCP 服务，远程客户端向 http://<监听地址>/mcp 发送 POST 请求:
+{{if eq .BuildTool "poetry"}}poetry run {{.ToolName}}{{else if eq .BuildTool "uv"}}uv run {{.ToolName}}{{else}}python -m {{.PackageName}}.main{{end}} --transport http
+
+监听地址取自 MCP_HTTP_ADDR（host:port）；未设置时在所有网卡上监听 PORT（容器平台的惯例）；两者都未设置时为 127.0.0.1:8080。
+
+### 查看工具与模型
+
+--describe 以 JSON 输出注册的工具、API 标题与版本、端点与模式数量以及模型哈希（内嵌模型文件的 SHA-256），然后退出，不启动服务:
+{{if eq .BuildTool "poetry"}}poetry run {{else if eq .BuildTool "uv"}}uv run {{end}}python -m {{.PackageName}} --describe
+
+{{if .FastAPI}}### HTTP API（FastAPI）
+
+src/{{.PackageName}}/api/router.py 为每个 API 端点提供一个 GET 路由（/endpoints/...），返回 getEndpointDetails 工具对该端点的结果；api_server.py 是独立于 main.py 的 HTTP 入口:
+{{if .Skip.Makefile}}{{if eq .BuildTool "poetry"}}poetry run {{else if eq .BuildTool "uv"}}uv run {{end}}python -m {{.PackageName}}.api_server
+
+监听地址由 API_HOST 与 API_PORT 环境变量设置（默认 127.0.0.1:8000）。
+{{else}}make run-api
+
+不使用 make 时可运行 {{if eq .BuildTool "poetry"}}poetry run {{else if eq .BuildTool "uv"}}uv run {{end}}python -m {{.PackageName}}.api_server，监听地址由 API_HOST 与 API_PORT 环境变量设置（默认 127.0.0.1:8000）。
+{{end}}
+{{end}}{{if .Docker}}### Docker
 
-## 可用工具
+构建镜像并以标准输入输出运行（需保持 stdin 打开，即 docker run -i）:
+{{- if .Skip.Makefile}}
+docker build -t {{.ToolName}} .
+docker run -i --rm {{.ToolName}}
+
+API_BASE_URL 等环境变量可通过 docker run -e 传入。
+{{- else}}
+make docker-build
+make docker-run
+
+镜像名由 IMAGE 设置（默认 {{.ToolName}}），API_BASE_URL 等环境变量可通过 docker run -e 传入。
+{{- end}}
+
+{{end}}{{if .OTel}}### 追踪（OpenTelemetry）
+
+每次工具调用记录一个名为 tools/call <工具名> 的 span，带工具名与耗时，工具抛出异常时标记为错误。追踪依赖可选的 opentelemetry-api，安装方式:
+{{if eq .BuildTool "poetry"}}poetry install -E otel{{else if eq .BuildTool "uv"}}uv sync --extra otel{{else}}pip install -e ".[otel]"{{end}}
+
+未安装 opentelemetry-api，或未配置 SDK 与导出器时追踪为空操作。导出到 OTLP 端点可借助 opentelemetry-distro，由标准 OTEL_* 环境变量配置:
+pip install opentelemetry-distro opentelemetry-exporter-otlp
+OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318 opentelemetry-instrument python -m {{.PackageName}}.main
+
+{{end}}{{if .ShardModel}}### 模型分片
+
+模型按标签拆分：src/{{.PackageName}}/spec/model/_index.json 保存除端点外的全部内容，model/<标签>.json 保存首个标签为该标签的端点，无标签的端点在 _untagged.json 中。load_service_model() 仍返回完整模型；load_index()、shards()、load_tag() 与 load_shard() 只读取所需的文件，每个分片只读一次。
+
+{{end}}{{if not .Skip.Editor}}### 调试（VS Code）
+
+生成的 .vscode/launch.json 提供{{if .Skip.Tests}}一个{{else}}两个{{end}}调试配置（需安装 Python 扩展）：
+
+- **Debug MCP server (stdio)**: 以模块方式运行 {{.PackageName}}.main，可在集成终端中输入 JSON-RPC 请求
+{{- if not .Skip.Tests}}
+- **Debug tests (pytest)**: 在调试器下运行 tests 目录中的测试
+{{- end}}
+
+{{end}}## 可用工具
 
 - **listEndpoints**: 列出所有可用的API端点
 - **searchEndpoints**: 根据条件搜索API端点
 - **getEndpointDetails**: 获取指定API端点的详细信息
 - **listSchemas**: 列出所有可用的数据模型定义
 - **getSchemaDetails**: 获取指定数据模型的详细信息
+- **explainParameter**: 说明端点的单个参数（位置、Schema、序列化方式、枚举值与用法示例）
+{{- if .Invoke}}
+- **callEndpoint**: 调用API端点并返回状态码、响应头与响应体（发送前按模型校验必需参数与请求体；API_TIMEOUT 设置每次发送的超时，默认 30s；API_RETRIES 设置请求失败、超时或响应为 429、5xx 时的重试次数，默认 0，重试前等待 100ms 并逐次加倍，最多 2s；响应体最多返回 64 KiB）
+{{- end}}
+{{- if .Credentials}}
+
+## 认证
+
+callEndpoint 从环境变量读取凭据，按各端点的安全要求发送:
+{{range .Credentials}}
+- ` + "`{{.EnvVar}}`" + `: 安全方案 ` + "`{{.Scheme}}`" + `，{{if eq .Kind "bearer"}}作为 ` + "`Authorization: Bearer <token>`" + ` 请求头{{else if eq .Kind "basic"}}作为 ` + "`Authorization: Basic`" + ` 请求头，值为 user:password{{else if eq .In "query"}}作为查询参数 ` + "`{{.Name}}`" + `{{else if eq .In "cookie"}}作为 Cookie ` + "`{{.Name}}`" + `{{else}}作为请求头 ` + "`{{.Name}}`" + `{{end}}
+{{- end}}
+
+不满足端点安全要求的调用在发送前报错。
+{{- end}}
 
 ## API信息
 
@@ -2465,6 +3888,39 @@ python -m {{.PackageName}}.main
 > 注意: 这是一个自动生成的项目。如需更新，请修改原始API规范并重新生成。
 `
 
+// VSCodeLaunchTemplate .vscode/launch.json调试配置模板
+const VSCodeLaunchTemplate = `{
+  "version": "0.2.0",
+  "configurations": [
+    {
+      "type": "debugpy",
+      "request": "launch",
+      "name": "Debug MCP server (stdio)",
+      "module": "{{.PackageName}}.main",
+      "cwd": "${workspaceFolder}",
+      "env": {
+        "PYTHONPATH": "${workspaceFolder}/src"
+      },
+      "console": "integratedTerminal",
+      "justMyCode": true
+    }{{if not .Skip.Tests}},
+    {
+      "type": "debugpy",
+      "request": "launch",
+      "name": "Debug tests (pytest)",
+      "module": "pytest",
+      "args": ["-q", "tests"],
+      "cwd": "${workspaceFolder}",
+      "env": {
+        "PYTHONPATH": "${workspaceFolder}/src"
+      },
+      "console": "integratedTerminal",
+      "justMyCode": false
+    }{{end}}
+  ]
+}
+`
+
 // SetupPyTemplate setup.py项目安装配置模板
 const SetupPyTemplate = `"""
 {{.ServiceTitle}} MCP 工具安装配置
@@ -2472,27 +3928,38 @@ const SetupPyTemplate = `"""
 
 Generated by swagger2mcp
 """
+{{- if .Skip.Readme}}
+
+from setuptools import find_packages, setup
+{{- else}}
 
-from setuptools import setup, find_packages
 from pathlib import Path
 
+from setuptools import find_packages, setup
+
 # 读取README文件作为长描述
 this_directory = Path(__file__).parent
-long_description = (this_directory / "README.md").read_text(encoding='utf-8')
+long_description = (this_directory / "README.md").read_text(encoding="utf-8")
+{{- end}}
 
 setup(
     name="{{.PackageName}}",
     version="{{.Version}}",
     author="{{.Author}}",
-    author_email="noreply@example.com",
+    author_email="{{if .AuthorEmail}}{{.AuthorEmail}}{{else}}noreply@example.com{{end}}",
+{{- if .License}}
+    license="{{.License}}",
+{{- end}}
     description="{{.ServiceTitle}}的MCP服务器 - 提供API文档查询功能",
+{{- if not .Skip.Readme}}
     long_description=long_description,
     long_description_content_type="text/markdown",
+{{- end}}
     url="https://github.com/mark3labs/swagger2mcp",
     packages=find_packages(where="src"),
     package_dir={"": "src"},
     package_data={
-        "{{.PackageName}}.spec": ["model.json"],
+        "{{.PackageName}}.spec": [{{if .ShardModel}}"model/*.json"{{else}}"model.json"{{end}}],
     },
     include_package_data=True,
     classifiers=[
@@ -2500,29 +3967,40 @@ setup(
         "Intended Audience :: Developers",
         "Topic :: Software Development :: Documentation",
         "Topic :: Internet :: WWW/HTTP :: Dynamic Content",
-        "License :: OSI Approved :: MIT License",
+        "{{.LicenseClassifier}}",
         "Programming Language :: Python :: 3",
-        "Programming Language :: Python :: 3.8",
-        "Programming Language :: Python :: 3.9",
-        "Programming Language :: Python :: 3.10",
-        "Programming Language :: Python :: 3.11",
-        "Programming Language :: Python :: 3.12",
+{{- range .PythonVersions}}
+        "Programming Language :: Python :: {{.}}",
+{{- end}}
         "Operating System :: OS Independent",
     ],
-    python_requires=">=3.8",
+    python_requires="{{.PythonRequires}}",
     install_requires=[
-        "dataclasses-json>=0.6.0",
-        "typing-extensions>=4.5.0",
+        "{{.Require "dataclasses-json"}}",
+        "{{.Require "typing-extensions"}}",
+{{- if .MCPVersionSpec}}
+        "mcp{{.MCPVersionSpec}}",
+{{- end}}
+{{- if .FastAPI}}
+        "{{.Require "fastapi"}}",
+        "{{.Require "uvicorn"}}",
+{{- end}}
     ],
     extras_require={
         "dev": [
-            "black>=23.0.0",
-            "mypy>=1.5.0", 
-            "pytest>=7.4.0",
-            "pytest-cov>=4.1.0",
-            "flake8>=6.0.0",
-            "isort>=5.12.0",
-        ]
+            "{{.Require "black"}}",
+            "{{.Require "isort"}}",
+            "{{.Require "flake8"}}",
+            "{{if .UseRuff}}{{.Require "ruff"}}{{else}}{{.Require "pylint"}}{{end}}",
+            "{{.Require "mypy"}}",
+            "{{.Require "pytest"}}",
+            "{{.Require "pytest-cov"}}",
+        ],
+{{- if .OTel}}
+        "otel": [
+            "{{.Require "opentelemetry-api"}}",
+        ],
+{{- end}}
     },
     entry_points={
         "console_scripts": [
@@ -2535,18 +4013,28 @@ setup(
         "Source": "https://github.com/mark3labs/swagger2mcp",
         "Tracker": "https://github.com/mark3labs/swagger2mcp/issues",
     },
-)`
+)
+`
 
 // RequirementsTxtTemplate requirements.txt运行时依赖模板
 const RequirementsTxtTemplate = `# {{.ServiceTitle}} MCP 工具运行时依赖
 # Generated by swagger2mcp
 
 # MCP协议相关依赖
-dataclasses-json>=0.6.0
-typing-extensions>=4.5.0
+{{.Require "dataclasses-json"}}
+{{.Require "typing-extensions"}}
+{{- if .MCPVersionSpec}}
+mcp{{.MCPVersionSpec}}
+{{- end}}
+{{- if .FastAPI}}
+
+# HTTP API (FastAPI)
+{{.Require "fastapi"}}
+{{.Require "uvicorn"}}
+{{- end}}
 
 # JSON处理和数据验证
-# 注意：Python 3.8+ 内置了json模块，无需额外依赖
+# 注意：Python {{.PythonVersion}}+ 内置了json模块，无需额外依赖
 `
 
 // RequirementsDevTxtTemplate requirements-dev.txt开发依赖模板
@@ -2556,45 +4044,42 @@ const RequirementsDevTxtTemplate = `# {{.ServiceTitle}} MCP 工具开发依赖
 # 包含运行时依赖
 -r requirements.txt
 
-# 代码格式化
-black>=23.0.0
-isort>=5.12.0
-
-# 类型检查
-mypy>=1.5.0
-types-setuptools>=68.0.0
+{{if or .Skip.Lint .Skip.Makefile}}# 代码格式化与检查（固定版本，保证检查结果可复现）
+{{else}}# 代码格式化与检查（固定版本，与 .pre-commit-config.yaml 一致，
+# 保证 make lint / make typecheck 的结果可复现）
+{{end}}{{.Require "black"}}
+{{.Require "isort"}}
+{{.Require "flake8"}}
+{{if .UseRuff}}{{.Require "ruff"}}{{else}}{{.Require "pylint"}}{{end}}
+{{.Require "mypy"}}
+{{.Require "types-setuptools"}}
 
 # 测试框架
-pytest>=7.4.0
-pytest-cov>=4.1.0
-pytest-asyncio>=0.21.0
+{{.Require "pytest"}}
+{{.Require "pytest-cov"}}
+{{.Require "pytest-asyncio"}}
 
-# 代码质量检查
-flake8>=6.0.0
-pylint>=2.17.0
-bandit>=1.7.5  # 安全漏洞检查
-safety>=2.3.0  # 依赖安全检查
+# 安全检查
+{{.Require "bandit[toml]"}}  # 安全漏洞检查
+{{.Require "safety"}}  # 依赖安全检查
 
 # 代码复杂度检查
-radon>=6.0.1
-xenon>=0.9.0
+{{.Require "radon"}}
+{{.Require "xenon"}}
 
 # 文档字符串检查
-pydocstyle>=6.3.0
-
-# 导入排序检查
-isort[colors]>=5.12.0
+{{.Require "pydocstyle"}}
 
 # 预提交钩子
-pre-commit>=3.3.0
+{{.Require "pre-commit"}}
 
-# Python 3.8+ 兼容性检查
-pyupgrade>=3.10.0
-vermin>=1.5.2
+# Python {{.PythonVersion}}+ 兼容性检查
+{{.Require "pyupgrade"}}
+{{.Require "vermin"}}
 
 # 构建工具
-build>=0.10.0
-twine>=4.0.0
+{{.Require "build"}}
+{{.Require "twine"}}
 `
 
 // PyprojectTomlTemplate pyproject.toml现代Python项目配置模板
@@ -2610,27 +4095,37 @@ name = "{{.PackageName}}"
 version = "{{.Version}}"
 description = "{{.ServiceTitle}}的MCP服务器 - 提供API文档查询功能"
 authors = [
-    {name = "{{.Author}}"},
+    {name = "{{.Author}}"{{if .AuthorEmail}}, email = "{{.AuthorEmail}}"{{end}}},
 ]
+{{- if not .Skip.Readme}}
 readme = "README.md"
-requires-python = ">=3.8"
+{{- end}}
+{{- if .License}}
+license = {text = "{{.License}}"}
+{{- end}}
+requires-python = "{{.PythonRequires}}"
 classifiers = [
     "Development Status :: 4 - Beta",
     "Intended Audience :: Developers",
     "Topic :: Software Development :: Documentation",
     "Topic :: Internet :: WWW/HTTP :: Dynamic Content",
-    "License :: OSI Approved :: MIT License",
+    "{{.LicenseClassifier}}",
     "Programming Language :: Python :: 3",
-    "Programming Language :: Python :: 3.8",
-    "Programming Language :: Python :: 3.9",
-    "Programming Language :: Python :: 3.10",
-    "Programming Language :: Python :: 3.11",
-    "Programming Language :: Python :: 3.12",
+{{- range .PythonVersions}}
+    "Programming Language :: Python :: {{.}}",
+{{- end}}
     "Operating System :: OS Independent",
 ]
 dependencies = [
-    "dataclasses-json>=0.6.0",
-    "typing-extensions>=4.5.0",
+    "{{.Require "dataclasses-json"}}",
+    "{{.Require "typing-extensions"}}",
+{{- if .MCPVersionSpec}}
+    "mcp{{.MCPVersionSpec}}",
+{{- end}}
+{{- if .FastAPI}}
+    "{{.Require "fastapi"}}",
+    "{{.Require "uvicorn"}}",
+{{- end}}
 ]
 keywords = ["mcp", "api", "documentation", "openapi", "swagger"]
 
@@ -2642,21 +4137,153 @@ Tracker = "https://github.com/mark3labs/swagger2mcp/issues"
 [project.scripts]
 "{{.ToolName}}" = "{{.PackageName}}.main:main"
 
+{{if eq .BuildTool "uv" -}}
+[tool.setuptools.packages.find]
+where = ["src"]
+
+[tool.setuptools.package-data]
+"{{.PackageName}}.spec" = [{{if .ShardModel}}"model/*.json"{{else}}"model.json"{{end}}]
+
+{{if or .Skip.Lint .Skip.Makefile}}# 开发依赖（固定版本，保证检查结果可复现）
+{{else}}# 开发依赖（固定版本，与 .pre-commit-config.yaml 一致，
+# 保证 make lint / make typecheck 的结果可复现）
+{{end}}[dependency-groups]
+dev = [
+    "{{.Require "black"}}",
+    "{{.Require "isort"}}",
+    "{{.Require "flake8"}}",
+    "{{if .UseRuff}}{{.Require "ruff"}}{{else}}{{.Require "pylint"}}{{end}}",
+    "{{.Require "mypy"}}",
+    "{{.Require "types-setuptools"}}",
+    "{{.Require "pytest"}}",
+    "{{.Require "pytest-cov"}}",
+    "{{.Require "pytest-asyncio"}}",
+    "{{.Require "bandit[toml]"}}",
+    "{{.Require "safety"}}",
+    "{{.Require "radon"}}",
+    "{{.Require "xenon"}}",
+    "{{.Require "pydocstyle"}}",
+    "{{.Require "pre-commit"}}",
+    "{{.Require "pyupgrade"}}",
+    "{{.Require "vermin"}}",
+]
+
+[tool.uv]
+# 以可编辑方式安装本项目，uv run 可直接使用 {{.ToolName}} 命令
+package = true
+required-version = ">=0.5.0"
+{{- if .OTel}}
+
+# 可选的工具调用追踪: uv sync --extra otel
+[project.optional-dependencies]
+otel = ["{{.Require "opentelemetry-api"}}"]
+{{- end}}
+{{- else -}}
 [project.optional-dependencies]
 dev = [
-    "black>=23.0.0",
-    "isort>=5.12.0", 
-    "mypy>=1.5.0",
-    "pytest>=7.4.0",
-    "pytest-cov>=4.1.0",
-    "flake8>=6.0.0",
-    "pylint>=2.17.0",
+    "{{.Require "black"}}",
+    "{{.Require "isort"}}",
+    "{{.Require "flake8"}}",
+    "{{if .UseRuff}}{{.Require "ruff"}}{{else}}{{.Require "pylint"}}{{end}}",
+    "{{.Require "mypy"}}",
+    "{{.Require "pytest"}}",
+    "{{.Require "pytest-cov"}}",
+]
+{{- if .OTel}}
+# 可选的工具调用追踪: pip install -e ".[otel]"
+otel = ["{{.Require "opentelemetry-api"}}"]
+{{- end}}
+{{- end}}
+
+` + pyprojectToolsToml
+
+// PoetryPyprojectTomlTemplate poetry 构建系统的 pyproject.toml：依赖、开发依赖组与命令行入口均由 Poetry 管理
+const PoetryPyprojectTomlTemplate = `# {{.ServiceTitle}} MCP 工具项目配置 (Poetry)
+# Generated by swagger2mcp
+
+[build-system]
+requires = ["poetry-core>=1.0.0"]
+build-backend = "poetry.core.masonry.api"
+
+[tool.poetry]
+name = "{{.PackageName}}"
+version = "{{.Version}}"
+description = "{{.ServiceTitle}}的MCP服务器 - 提供API文档查询功能"
+authors = ["{{.Author}}{{if .AuthorEmail}} <{{.AuthorEmail}}>{{end}}"]
+{{- if .License}}
+license = "{{.License}}"
+{{- end}}
+{{- if not .Skip.Readme}}
+readme = "README.md"
+{{- end}}
+homepage = "https://github.com/mark3labs/swagger2mcp"
+repository = "https://github.com/mark3labs/swagger2mcp"
+documentation = "https://github.com/mark3labs/swagger2mcp"
+keywords = ["mcp", "api", "documentation", "openapi", "swagger"]
+classifiers = [
+    "Development Status :: 4 - Beta",
+    "Intended Audience :: Developers",
+    "Topic :: Software Development :: Documentation",
+    "Topic :: Internet :: WWW/HTTP :: Dynamic Content",
+    "{{.LicenseClassifier}}",
+    "Operating System :: OS Independent",
 ]
+# src 布局：包位于 src/ 下，model.json 随 spec 子包一起打包
+packages = [{ include = "{{.PackageName}}", from = "src" }]
+include = [{ path = "src/{{.PackageName}}/spec/{{if .ShardModel}}model/*.json{{else}}model.json{{end}}", format = ["sdist", "wheel"] }]
+
+[tool.poetry.dependencies]
+python = "{{.PoetryPython}}"
+dataclasses-json = "{{.PoetryRequire "dataclasses-json"}}"
+typing-extensions = "{{.PoetryRequire "typing-extensions"}}"
+{{- if .MCPVersionSpec}}
+mcp = "{{.MCPVersionSpec}}"
+{{- end}}
+{{- if .FastAPI}}
+fastapi = "{{.PoetryRequire "fastapi"}}"
+uvicorn = "{{.PoetryRequire "uvicorn"}}"
+{{- end}}
+{{- if .OTel}}
+opentelemetry-api = { version = "{{.PoetryRequire "opentelemetry-api"}}", optional = true }
+
+# 可选的工具调用追踪: poetry install -E otel
+[tool.poetry.extras]
+otel = ["opentelemetry-api"]
+{{- end}}
+
+{{if or .Skip.Lint .Skip.Makefile}}# 开发依赖（固定版本，保证检查结果可复现）
+{{else}}# 开发依赖（固定版本，与 .pre-commit-config.yaml 一致，
+# 保证 make lint / make typecheck 的结果可复现）
+{{end}}[tool.poetry.group.dev.dependencies]
+black = "{{.PoetryRequire "black"}}"
+isort = "{{.PoetryRequire "isort"}}"
+flake8 = "{{.PoetryRequire "flake8"}}"
+{{if .UseRuff}}ruff = "{{.PoetryRequire "ruff"}}"{{else}}pylint = "{{.PoetryRequire "pylint"}}"{{end}}
+mypy = "{{.PoetryRequire "mypy"}}"
+types-setuptools = "{{.PoetryRequire "types-setuptools"}}"
+pytest = "{{.PoetryRequire "pytest"}}"
+pytest-cov = "{{.PoetryRequire "pytest-cov"}}"
+pytest-asyncio = "{{.PoetryRequire "pytest-asyncio"}}"
+bandit = { version = "{{.PoetryRequire "bandit[toml]"}}", extras = ["toml"] }
+safety = "{{.PoetryRequire "safety"}}"
+radon = "{{.PoetryRequire "radon"}}"
+xenon = "{{.PoetryRequire "xenon"}}"
+pydocstyle = "{{.PoetryRequire "pydocstyle"}}"
+pre-commit = "{{.PoetryRequire "pre-commit"}}"
+pyupgrade = "{{.PoetryRequire "pyupgrade"}}"
+vermin = "{{.PoetryRequire "vermin"}}"
+
+[tool.poetry.scripts]
+"{{.ToolName}}" = "{{.PackageName}}.main:main"
 
-# 工具配置
+` + pyprojectToolsToml
+
+// pyprojectToolsToml pyproject.toml 中与构建系统无关的工具配置
+const pyprojectToolsToml = `# 工具配置
+# flake8 使用 .flake8，mypy 使用 mypy.ini，{{if .UseRuff}}ruff 使用 ruff.toml{{else}}pylint 使用 .pylintrc{{end}}
 [tool.black]
 line-length = 88
-target-version = ['py38']
+target-version = ['{{.PythonTarget}}']
 include = '\.pyi?$'
 extend-exclude = '''
 # A regex preceded by ^/ will apply only to files and directories
@@ -2672,29 +4299,12 @@ include_trailing_comma = true
 force_grid_wrap = 0
 use_parentheses = true
 ensure_newline_before_comments = true
-
-[tool.mypy]
-python_version = "3.8"
-strict = true
-warn_return_any = true
-warn_unused_configs = true
-disallow_untyped_defs = true
-disallow_incomplete_defs = true
-check_untyped_defs = true
-disallow_untyped_decorators = true
-no_implicit_optional = true
-warn_redundant_casts = true
-warn_unused_ignores = true
-warn_no_return = true
-warn_unreachable = true
-strict_equality = true
-
-[[tool.mypy.overrides]]
-module = ["tests.*"]
-disallow_untyped_defs = false
+known_first_party = ["{{.PackageName}}"]
+src_paths = ["src", "tests"]
 
 [tool.pytest.ini_options]
 testpaths = ["tests"]
+pythonpath = ["src"]
 python_files = ["test_*.py", "*_test.py"]
 python_classes = ["Test*"]
 python_functions = ["test_*"]
@@ -2729,25 +4339,6 @@ exclude_lines = [
     "@(abc\\.)?abstractmethod",
 ]
 
-[tool.flake8]
-max-line-length = 88
-select = ["E", "F", "W", "C90"]
-ignore = [
-    "E203",  # whitespace before ':'
-    "E501",  # line too long (handled by black)
-    "W503",  # line break before binary operator
-]
-exclude = [
-    ".git",
-    "__pycache__",
-    "build",
-    "dist",
-    ".eggs",
-    "*.egg-info",
-    ".venv",
-    "venv",
-]
-
 [tool.bandit]
 targets = ["src/{{.PackageName}}"]
 exclude_dirs = ["tests"]
@@ -2758,86 +4349,254 @@ convention = "google"
 add_ignore = "D100,D104"
 
 [tool.pyupgrade]
-py38-plus = true
+{{.PythonTarget}}-plus = true
 
 [tool.vermin]
-targets = ["3.8-"]
+targets = ["{{.PythonVersion}}-"]
 backport = ["typing"]
 no-tips = true
 `
 
+// LibraryPyprojectTomlTemplate 库布局的 pyproject.toml：只打包 spec 子包与 model.json
+const LibraryPyprojectTomlTemplate = `# {{.ServiceTitle}} 服务模型库项目配置
+# Generated by swagger2mcp
+
+[build-system]
+requires = ["setuptools>=61.0", "wheel"]
+build-backend = "setuptools.build_meta"
+
+[project]
+name = "{{.PackageName}}"
+version = "{{.Version}}"
+description = "{{.ServiceTitle}}的服务模型库 - 类型化模型与内嵌的 model.json"
+authors = [
+    {name = "{{.Author}}"{{if .AuthorEmail}}, email = "{{.AuthorEmail}}"{{end}}},
+]
+{{- if not .Skip.Readme}}
+readme = "README.md"
+{{- end}}
+{{- if .License}}
+license = {text = "{{.License}}"}
+{{- end}}
+requires-python = "{{.PythonRequires}}"
+classifiers = [
+    "Development Status :: 4 - Beta",
+    "Intended Audience :: Developers",
+    "Programming Language :: Python :: 3",
+    "Operating System :: OS Independent",
+]
+dependencies = []
+keywords = ["api", "openapi", "swagger", "model"]
+
+[tool.setuptools.packages.find]
+where = ["src"]
+
+[tool.setuptools.package-data]
+"{{.PackageName}}.spec" = [{{if .ShardModel}}"model/*.json"{{else}}"model.json"{{end}}]
+`
+
+// LibraryPoetryPyprojectTomlTemplate 库布局在 poetry 构建系统下的 pyproject.toml
+const LibraryPoetryPyprojectTomlTemplate = `# {{.ServiceTitle}} 服务模型库项目配置 (Poetry)
+# Generated by swagger2mcp
+
+[build-system]
+requires = ["poetry-core>=1.0.0"]
+build-backend = "poetry.core.masonry.api"
+
+[tool.poetry]
+name = "{{.PackageName}}"
+version = "{{.Version}}"
+description = "{{.ServiceTitle}}的服务模型库 - 类型化模型与内嵌的 model.json"
+authors = ["{{.Author}}{{if .AuthorEmail}} <{{.AuthorEmail}}>{{end}}"]
+{{- if .License}}
+license = "{{.License}}"
+{{- end}}
+{{- if not .Skip.Readme}}
+readme = "README.md"
+{{- end}}
+keywords = ["api", "openapi", "swagger", "model"]
+classifiers = [
+    "Development Status :: 4 - Beta",
+    "Intended Audience :: Developers",
+    "Operating System :: OS Independent",
+]
+packages = [{ include = "{{.PackageName}}", from = "src" }]
+include = [{ path = "src/{{.PackageName}}/spec/{{if .ShardModel}}model/*.json{{else}}model.json{{end}}", format = ["sdist", "wheel"] }]
+
+[tool.poetry.dependencies]
+python = "{{.PoetryPython}}"
+`
+
+// LibraryReadmeMdTemplate 库布局的 README：说明如何安装并在代码中加载模型
+const LibraryReadmeMdTemplate = `# {{.ServiceTitle}} 服务模型库
+
+{{.ServiceTitle}}的类型化服务模型，以及内嵌的 model.json。
+
+> 🤖 此项目由 swagger2mcp 工具自动生成
+
+本包只包含 spec 子包（模型类型、加载器与数据），不含 MCP 服务器，适合在其之上构建自定义服务器或工具。仅依赖 Python 标准库。
+
+## 安装
+
+{{if eq .BuildTool "poetry"}}poetry install{{else if eq .BuildTool "uv"}}uv pip install .{{else}}pip install .{{end}}
+
+## 使用方法
+
+` + "```python" + `
+from {{.PackageName}}.spec.loader import apply_base_url_override, base_url, load_service_model
+
+model = apply_base_url_override(load_service_model())  # 支持 API_BASE_URL 覆盖
+for endpoint in model.endpoints:
+    print(endpoint.method.value, endpoint.path, endpoint.summary)
+print("base URL:", base_url(model))
+` + "```" + `
+{{- if .ShardModel}}
+
+模型按标签拆分为 spec/model/ 下的索引 _index.json 与各标签的分片（无标签的端点在 _untagged.json 中）。load_service_model() 仍返回完整模型；load_index()、shards()、load_tag() 与 load_shard() 只读取所需的文件，每个分片只读一次。
+{{- end}}
+`
+
+// LockStubTemplate uv.lock / poetry.lock 占位模板：首次 lock 时被真正的锁文件替换
+const LockStubTemplate = `# {{.ServiceTitle}} MCP 工具依赖锁文件（占位）
+# Generated by swagger2mcp
+#
+{{- if .Skip.Makefile}}
+# 此文件只是占位符，尚未包含任何已解析的依赖。请删除它后运行
+# {{.BuildTool}} lock 生成真正的锁文件，并将其提交到版本库，以保证所有
+# 环境安装完全相同的依赖版本。
+{{- else}}
+# 此文件只是占位符，尚未包含任何已解析的依赖。请运行 make lock
+# 用 {{.BuildTool}} 生成真正的锁文件，并将其提交到版本库，以保证所有
+# 环境安装完全相同的依赖版本。
+{{- end}}
+`
+
 // MakefileTemplate Makefile开发任务管理模板
-const MakefileTemplate = `# {{.ServiceTitle}} MCP 工具开发任务
+const MakefileTemplate = `{{$run := ""}}{{if eq .BuildTool "poetry"}}{{$run = "poetry run "}}{{else if eq .BuildTool "uv"}}{{$run = "uv run "}}{{end}}{{$dirs := "src/ tests/"}}{{$globs := "src/**/*.py tests/**/*.py"}}{{if .Skip.Tests}}{{$dirs = "src/"}}{{$globs = "src/**/*.py"}}{{end}}# {{.ServiceTitle}} MCP 工具开发任务
 # Generated by swagger2mcp
 
-.PHONY: help install install-dev test format lint clean build upload check security quality compat upgrade
+.PHONY: help install install-dev{{if ne .BuildTool "setuptools"}} lock{{end}}{{if .FastAPI}} run-api{{end}}{{if not .Skip.Tests}} test{{end}} format{{if not .Skip.Lint}} lint typecheck{{end}} clean build upload check security quality compat upgrade ci-check pre-commit{{if .Docker}} docker-build docker-run{{end}}
 
 # 默认目标：显示帮助信息
 help:
 	@echo "{{.ServiceTitle}} MCP 工具开发命令:"
 	@echo ""
 	@echo "  install     安装项目依赖"
-	@echo "  install-dev 安装开发依赖" 
+	@echo "  install-dev 安装开发依赖"
+{{- if ne .BuildTool "setuptools"}}
+	@echo "  lock        解析依赖并更新锁文件（需提交到版本库）"
+{{- end}}
+{{- if .FastAPI}}
+	@echo "  run-api     以 uvicorn 启动 HTTP API"
+{{- end}}
+{{- if not .Skip.Tests}}
 	@echo "  test        运行测试"
+{{- end}}
 	@echo "  format      格式化代码"
-	@echo "  lint        检查代码质量"
+{{- if not .Skip.Lint}}
+	@echo "  lint        检查代码风格与质量"
+	@echo "  typecheck   mypy 严格类型检查"
+{{- end}}
 	@echo "  security    安全漏洞检查"
 	@echo "  quality     全面代码质量检查"
-	@echo "  compat      Python 3.8+ 兼容性检查"
+	@echo "  compat      Python {{.PythonVersion}}+ 兼容性检查"
 	@echo "  upgrade     升级代码到现代Python语法"
 	@echo "  clean       清理构建文件"
 	@echo "  build       构建项目"
 	@echo "  upload      上传到PyPI"
 	@echo "  check       运行所有检查"
+{{- if .Docker}}
+	@echo "  docker-build 构建 Docker 镜像 (IMAGE, 默认 {{.ToolName}})"
+	@echo "  docker-run  以标准输入输出运行 Docker 镜像"
+{{- end}}
 	@echo ""
 
 # 安装项目依赖
 install:
+{{- if eq .BuildTool "poetry"}}
+	poetry install --only main
+{{- else if eq .BuildTool "uv"}}
+	uv sync --no-dev
+{{- else}}
 	pip install -e .
+{{- end}}
 
 # 安装开发依赖
 install-dev:
+{{- if eq .BuildTool "poetry"}}
+	poetry install
+{{- else if eq .BuildTool "uv"}}
+	uv sync
+{{- else}}
 	pip install -e ".[dev]"
 	pip install -r requirements-dev.txt
+{{- end}}
+{{- if ne .BuildTool "setuptools"}}
+
+# 解析依赖并更新锁文件; 首次运行时先删除 swagger2mcp 生成的占位文件
+lock:
+	@if grep -qs "Generated by swagger2mcp" {{.BuildTool}}.lock; then rm -f {{.BuildTool}}.lock; fi
+	{{.BuildTool}} lock
+{{- end}}
+{{- if .FastAPI}}
+
+# 以 uvicorn 启动 HTTP API (开发模式, 代码变更时自动重载)
+run-api:
+	{{$run}}uvicorn --app-dir src {{.PackageName}}.api_server:app --reload
+{{- end}}
+
+{{- if not .Skip.Tests}}
 
 # 运行测试
 test:
-	pytest tests/ -v --cov={{.PackageName}} --cov-report=term-missing --cov-report=html
+	{{$run}}pytest tests/ -v --cov={{.PackageName}} --cov-report=term-missing --cov-report=html
+{{- end}}
 
 # 格式化代码
 format:
-	pyupgrade --py38-plus src/**/*.py tests/**/*.py
-	black src/ tests/
-	isort src/ tests/
+	{{$run}}pyupgrade --{{.PythonTarget}}-plus {{$globs}}
+	{{$run}}black {{$dirs}}
+	{{$run}}isort {{$dirs}}
+{{- if not .Skip.Lint}}
 
-# 检查代码质量 (基础检查)
+# 检查代码风格与质量 (flake8/black/isort/{{if .UseRuff}}ruff{{else}}pylint{{end}})
 lint:
-	flake8 src/ tests/
-	mypy src/
-	black --check src/ tests/
-	isort --check-only src/ tests/
+	{{$run}}flake8 {{$dirs}}
+	{{$run}}black --check {{$dirs}}
+	{{$run}}isort --check-only {{$dirs}}
+{{- if .UseRuff}}
+	{{$run}}ruff check {{$dirs}}
+{{- else}}
+	{{$run}}pylint src/{{.PackageName}}/
+{{- end}}
+
+# 严格类型检查 (配置见 mypy.ini)
+typecheck:
+	{{$run}}mypy src/
+{{- end}}
 
 # 安全漏洞检查
 security:
-	bandit -r src/ -f json -o bandit-report.json || bandit -r src/
-	safety check --json --output safety-report.json || safety check
+	{{$run}}bandit -r src/ -f json -o bandit-report.json || {{$run}}bandit -r src/
+	{{$run}}safety check --json --output safety-report.json || {{$run}}safety check
 
 # 全面代码质量检查
-quality: lint security
-	pylint src/{{.PackageName}}/ --output-format=json --output=pylint-report.json || pylint src/{{.PackageName}}/
-	pydocstyle src/{{.PackageName}}/ || echo "文档字符串检查完成"
-	radon cc src/{{.PackageName}}/ -a -nb
-	radon mi src/{{.PackageName}}/ -nb
-	xenon --max-absolute A --max-modules A --max-average A src/{{.PackageName}}/
-
-# Python 3.8+ 兼容性检查
+quality:{{if not .Skip.Lint}} lint typecheck{{end}} security
+	{{$run}}pydocstyle src/{{.PackageName}}/ || echo "文档字符串检查完成"
+	{{$run}}radon cc src/{{.PackageName}}/ -a -nb
+	{{$run}}radon mi src/{{.PackageName}}/ -nb
+	{{$run}}xenon --max-absolute A --max-modules A --max-average A src/{{.PackageName}}/
+
+# Python {{.PythonVersion}}+ 兼容性检查
 compat:
-	vermin -t=3.8- src/{{.PackageName}}/
-	vermin -t=3.8- tests/
+	{{$run}}vermin -t={{.PythonVersion}}- src/{{.PackageName}}/
+{{- if not .Skip.Tests}}
+	{{$run}}vermin -t={{.PythonVersion}}- tests/
+{{- end}}
 
 # 升级代码到现代Python语法
 upgrade:
-	pyupgrade --py38-plus src/**/*.py tests/**/*.py
+	{{$run}}pyupgrade --{{.PythonTarget}}-plus {{$globs}}
 
 # 清理构建文件和报告
 clean:
@@ -2853,32 +4612,96 @@ clean:
 	rm -rf htmlcov/
 	rm -rf .pytest_cache/
 	rm -rf .mypy_cache/
-	rm -f bandit-report.json safety-report.json pylint-report.json
+{{- if .UseRuff}}
+	rm -rf .ruff_cache/
+{{- end}}
+	rm -f bandit-report.json safety-report.json
 
 # 构建项目
 build: clean
+{{- if eq .BuildTool "poetry"}}
+	poetry build
+{{- else if eq .BuildTool "uv"}}
+	uv build
+{{- else}}
 	python -m build
+{{- end}}
 
 # 上传到PyPI (需要先配置API token)
 upload: build
+{{- if eq .BuildTool "poetry"}}
+	poetry publish
+{{- else if eq .BuildTool "uv"}}
+	uv publish
+{{- else}}
 	python -m twine upload dist/*
+{{- end}}
 
 # 运行所有检查
-check: quality compat test
+check: quality compat{{if not .Skip.Tests}} test{{end}}
 	@echo "所有检查完成!"
 
 # 快速检查（用于CI/CD）
-ci-check:
-	pytest tests/ --tb=short -q
-	flake8 src/
-	mypy src/
-	black --check src/ tests/
-	bandit -r src/ -q
-	vermin -t=3.8- src/{{.PackageName}}/ -q
+ci-check:{{if not .Skip.Lint}} lint typecheck{{end}}
+{{- if not .Skip.Tests}}
+	{{$run}}pytest tests/ --tb=short -q
+{{- end}}
+	{{$run}}bandit -r src/ -q
+	{{$run}}vermin -t={{.PythonVersion}}- src/{{.PackageName}}/ -q
 
 # 预提交检查
 pre-commit: format quality
 	@echo "预提交检查通过!"
+{{- if .Docker}}
+
+IMAGE ?= {{.ToolName}}
+
+# 构建 Docker 镜像
+docker-build:
+	docker build -t $(IMAGE) .
+
+# 运行 Docker 镜像; MCP 经标准输入输出通信, 需保持 stdin 打开
+docker-run:
+	docker run -i --rm $(IMAGE)
+{{- end}}
+`
+
+// DockerfileTemplate 多阶段构建的 Dockerfile 模板: 先构建项目及其依赖的 wheel, 再安装到 slim 镜像
+const DockerfileTemplate = `# {{.ServiceTitle}} MCP 工具镜像
+# Generated by swagger2mcp
+FROM python:{{.PythonVersion}}-slim AS build
+WORKDIR /src
+COPY . .
+# 构建项目及其依赖的 wheel
+RUN pip wheel --no-cache-dir --wheel-dir /wheels .
+
+FROM python:{{.PythonVersion}}-slim
+ENV PYTHONDONTWRITEBYTECODE=1 PYTHONUNBUFFERED=1
+COPY --from=build /wheels /wheels
+RUN pip install --no-cache-dir --no-index /wheels/*.whl && rm -rf /wheels
+USER nobody
+# 通过标准输入输出提供 MCP 服务, 运行时需保持 stdin 打开: docker run -i --rm <镜像>
+ENTRYPOINT ["python", "-m", "{{.PackageName}}.main"]
+`
+
+// DockerignoreTemplate .dockerignore 模板: 构建上下文中不包含虚拟环境、缓存、构建产物与测试
+const DockerignoreTemplate = `.git
+.venv
+venv
+.vscode
+__pycache__
+*.py[cod]
+.mypy_cache
+.pytest_cache
+.ruff_cache
+.coverage
+htmlcov
+build
+dist
+*.egg-info
+tests
+Dockerfile
+.dockerignore
 `
 
 // GitignoreTemplate .gitignore文件模板
@@ -2973,10 +4796,10 @@ ipython_config.py
 # pipenv
 Pipfile.lock
 
-# poetry
+{{if ne .BuildTool "poetry"}}# poetry
 poetry.lock
 
-# pdm
+{{end}}# pdm
 .pdm.toml
 
 # PEP 582
@@ -3012,6 +4835,11 @@ venv.bak/
 .mypy_cache/
 .dmypy.json
 dmypy.json
+{{- if .UseRuff}}
+
+# ruff
+.ruff_cache/
+{{- end}}
 
 # Pyre type checker
 .pyre/
@@ -3025,8 +4853,9 @@ cython_debug/
 # PyCharm
 .idea/
 
-# VSCode
-.vscode/
+# VSCode (keep the generated debug configuration)
+.vscode/*
+!.vscode/launch.json
 
 # macOS
 .DS_Store
@@ -3068,6 +4897,8 @@ indent_style = tab
 // PreCommitConfigTemplate pre-commit配置模板
 const PreCommitConfigTemplate = `# {{.ServiceTitle}} MCP 工具预提交钩子配置
 # Generated by swagger2mcp
+#
+# 工具版本与{{if ne .BuildTool "setuptools"}} pyproject.toml 的 dev 依赖组{{else}} requirements-dev.txt {{end}}保持一致{{if not .Skip.Makefile}}，检查内容与 make lint / make typecheck 相同{{end}}。
 
 repos:
   - repo: https://github.com/pre-commit/pre-commit-hooks
@@ -3085,7 +4916,7 @@ repos:
     rev: v3.10.1
     hooks:
       - id: pyupgrade
-        args: [--py38-plus]
+        args: [--{{.PythonTarget}}-plus]
 
   - repo: https://github.com/psf/black
     rev: 23.9.1
@@ -3097,144 +4928,140 @@ repos:
     rev: 5.12.0
     hooks:
       - id: isort
-        args: ["--profile", "black"]
 
   - repo: https://github.com/pycqa/flake8
     rev: 6.1.0
     hooks:
       - id: flake8
-        additional_dependencies: [flake8-docstrings]
 
   - repo: https://github.com/pre-commit/mirrors-mypy
-    rev: v1.5.1
+    rev: v1.7.1
     hooks:
       - id: mypy
-        additional_dependencies: [types-setuptools]
+        args: [--config-file=mypy.ini]
+        pass_filenames: false
+
+{{- if .UseRuff}}
+
+  - repo: https://github.com/astral-sh/ruff-pre-commit
+    rev: v{{.RuffVersion}}
+    hooks:
+      - id: ruff
+{{- else}}
+
+  - repo: local
+    hooks:
+      # pylint 需要导入项目代码，因此使用已安装开发依赖的本地环境运行
+      - id: pylint
+        name: pylint
+        entry: pylint
+        language: system
+        types: [python]
+        files: ^src/
+{{- end}}
 
   - repo: https://github.com/PyCQA/bandit
     rev: 1.7.5
     hooks:
       - id: bandit
         args: ["-c", "pyproject.toml"]
-
-  - repo: https://github.com/pycqa/pydocstyle
-    rev: 6.3.0
-    hooks:
-      - id: pydocstyle
-        args: [--convention=google]
+        additional_dependencies: ["bandit[toml]"]
 
   - repo: https://github.com/netromdk/vermin
     rev: v1.5.2
     hooks:
       - id: vermin
-        args: ["-t=3.8-", "--no-tips"]
+        args: ["-t={{.PythonVersion}}-", "--no-tips"]
 `
 
 // MyPyConfigTemplate mypy.ini配置模板
 const MyPyConfigTemplate = `# {{.ServiceTitle}} MCP 工具 MyPy 配置
 # Generated by swagger2mcp
+#
+# 这是 mypy 的唯一配置来源（pyproject.toml 中不再重复），
+# {{if .Skip.Makefile}}mypy{{else}}make typecheck{{end}} 与 pre-commit 都使用它。生成的源码只依赖标准库，
+# 无需为第三方库添加 ignore_missing_imports。
 
 [mypy]
-python_version = 3.8
-warn_return_any = True
-warn_unused_configs = True
-disallow_untyped_defs = True
-disallow_incomplete_defs = True
-check_untyped_defs = True
-disallow_untyped_decorators = True
-no_implicit_optional = True
-warn_redundant_casts = True
-warn_unused_ignores = True
-warn_no_return = True
+python_version = {{.PythonVersion}}
+files = src
+strict = True
 warn_unreachable = True
-strict_equality = True
 show_error_codes = True
+{{- if .OTel}}
 
-# 第三方库配置
-[mypy-pytest.*]
+# opentelemetry-api 是可选依赖 (otel), 未安装时 telemetry.py 也要通过检查
+[mypy-opentelemetry.*]
 ignore_missing_imports = True
+{{- end}}
+`
 
-[mypy-setuptools.*]
-ignore_missing_imports = True
+// Flake8Template .flake8配置模板
+const Flake8Template = `# {{.ServiceTitle}} MCP 工具 flake8 配置
+# Generated by swagger2mcp
+#
+# flake8 不读取 pyproject.toml，因此单独放在此文件中。
+# E203/W503 与 black 的格式化结果冲突，按 black 官方建议忽略。
+
+[flake8]
+max-line-length = 88
+extend-ignore = E203, W503
+exclude =
+    .git,
+    __pycache__,
+    build,
+    dist,
+    .eggs,
+    *.egg-info,
+    .venv,
+    venv
+`
+
+// RuffTomlTemplate ruff.toml配置模板, UseRuff 时取代 .pylintrc
+const RuffTomlTemplate = `# {{.ServiceTitle}} MCP 工具 Ruff 配置
+# Generated by swagger2mcp
+#
+# Ruff 取代 pylint 做静态检查；行宽与目标版本与 black、.flake8 一致。
+
+line-length = 88
+target-version = "{{.PythonTarget}}"
+src = ["src", "tests"]
+
+[lint]
+select = ["E", "F", "I", "N", "UP", "ANN"]
+# ANN101/ANN102: self 与 cls 无需注解
+# ANN401: 规格中的 JSON 值（示例、枚举、扩展字段）本身就是 Any
+ignore = ["ANN101", "ANN102", "ANN401"]
+
+[lint.isort]
+known-first-party = ["{{.PackageName}}"]
 `
 
 // PylintRcTemplate .pylintrc配置模板
-const PylintRcTemplate = `# {{.ServiceTitle}} MCP 工具 Pylint 配置  
+const PylintRcTemplate = `# {{.ServiceTitle}} MCP 工具 Pylint 配置
 # Generated by swagger2mcp
+#
+# 生成的源码在此配置下应没有任何告警。个别有意为之的写法
+# （镜像 Go 模型字段的数据类、JSON-RPC 服务器兜底捕获异常）
+# 在代码中就地注释并禁用，而不是在这里全局关闭。
 
-[MASTER]
-extension-pkg-whitelist=
+[MAIN]
 jobs=1
-limit-inference-results=100
 persistent=yes
-suggestion-mode=yes
-unsafe-load-any-extension=no
 
 [MESSAGES CONTROL]
-confidence=
-disable=raw-checker-failed,
-        bad-inline-option,
-        locally-disabled,
-        file-ignored,
-        suppressed-message,
-        useless-suppression,
-        deprecated-pragma,
-        use-symbolic-message-instead,
-        missing-module-docstring,
+disable=missing-module-docstring,
         too-few-public-methods
 
 [REPORTS]
-evaluation=10.0 - ((float(5 * error + warning + refactor + convention) / statement) * 10)
 output-format=text
 reports=no
 score=yes
 
-[REFACTORING]
-max-nested-blocks=5
-never-returning-functions=sys.exit
-
-[SIMILARITIES]
-ignore-comments=yes
-ignore-docstrings=yes
-ignore-imports=no
-min-similarity-lines=4
-
-[SPELLING]
-spelling-dict=
-spelling-ignore-words=
-spelling-private-dict-file=
-
-[TYPECHECK]
-contextmanager-decorators=contextlib.contextmanager
-generated-members=
-ignore-mixin-members=yes
-ignore-none=yes
-ignore-on-opaque-inference=yes
-ignored-classes=optparse.Values,thread._local,_thread._local
-ignored-modules=
-missing-member-hint=yes
-missing-member-hint-distance=1
-missing-member-max-choices=1
-
-[VARIABLES]
-additional-builtins=
-allow-global-unused-variables=yes
-callbacks=cb_,_cb
-dummy-variables-rgx=_+$|(_[a-zA-Z0-9_]*[a-zA-Z0-9]+?$)|dummy|^ignored_|^unused_
-ignored-argument-names=_.*|^ignored_|^unused_
-init-import=no
-redefining-builtins-modules=six.moves,past.builtins,future.builtins,builtins,io
-
 [FORMAT]
-expected-line-ending-format=
-ignore-long-lines=^\s*(# )?<?https?://\S+>?$
-indent-after-paren=4
-indent-string='    '
 max-line-length=88
 max-module-lines=1000
-no-space-check=trailing-comma,dict-separator
-single-line-class-stmt=no
-single-line-if-stmt=no
+indent-string='    '
 
 [LOGGING]
 logging-format-style=old
@@ -3244,50 +5071,274 @@ logging-modules=logging
 notes=FIXME,XXX,TODO
 
 [BASIC]
-argument-naming-style=snake_case
-attr-naming-style=snake_case
-bad-names=foo,bar,baz,toto,tutu,tata
-class-attribute-naming-style=any
-class-naming-style=PascalCase
-const-naming-style=UPPER_CASE
-docstring-min-length=-1
-function-naming-style=snake_case
 good-names=i,j,k,ex,Run,_
-include-naming-hint=no
-inlinevar-naming-style=any
-method-naming-style=snake_case
-module-naming-style=snake_case
-name-group=
 no-docstring-rgx=^_
-property-classes=abc.abstractproperty
-variable-naming-style=snake_case
-
-[STRING]
-check-str-concat-over-line-jumps=no
-
-[IMPORTS]
-allow-wildcard-with-all=no
-analyse-fallback-blocks=no
-deprecated-modules=optparse,tkinter.tix
+class-attribute-naming-style=any
+inlinevar-naming-style=any
 
-[CLASSES]
-defining-attr-methods=__init__,__new__,setUp,__post_init__
-exclude-protected=_asdict,_fields,_replace,_source,_make
-valid-classmethod-first-arg=cls
-valid-metaclass-classmethod-first-arg=cls
+[SIMILARITIES]
+ignore-comments=yes
+ignore-docstrings=yes
+ignore-imports=yes
+min-similarity-lines=4
 
 [DESIGN]
 max-args=5
 max-attributes=7
-max-bool-expr=5
 max-branches=12
 max-locals=15
-max-parents=7
-max-public-methods=20
 max-returns=6
 max-statements=50
-min-public-methods=2
 
 [EXCEPTIONS]
-overgeneral-exceptions=BaseException,Exception
+overgeneral-exceptions=builtins.BaseException,builtins.Exception
+`
+
+// GitHubActionsCITemplate .github/workflows/ci.yml 模板, GenerateCI 且 CIProvider 为 github 时生成
+const GitHubActionsCITemplate = `# {{.ServiceTitle}} MCP 工具 CI
+# Generated by swagger2mcp
+#
+# 检查内容与 {{if not .Skip.Lint}}make lint / make typecheck{{if not .Skip.Tests}} / {{end}}{{end}}{{if not .Skip.Tests}}make test{{end}} 相同；依赖安装在 .venv 中并按锁文件缓存。
+{{define "setup"}}
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-python@v5
+        with:
+          python-version: {{GHExpr "matrix.python-version"}}
+{{- if eq .BuildTool "uv"}}
+      - uses: astral-sh/setup-uv@v3
+{{- else if eq .BuildTool "poetry"}}
+      - name: Install Poetry
+        run: |
+          pipx install poetry
+          poetry config virtualenvs.in-project true
+{{- end}}
+      - name: Cache .venv
+        uses: actions/cache@v4
+        with:
+          path: .venv
+          key: venv-{{GHExpr "runner.os"}}-py{{GHExpr "matrix.python-version"}}-{{if eq .BuildTool "setuptools"}}{{GHExpr "hashFiles('setup.py', 'pyproject.toml', 'requirements*.txt')"}}{{else}}{{GHExpr (printf "hashFiles('pyproject.toml', '%s.lock')" .BuildTool)}}{{end}}
+      - name: Install dependencies
+{{- if eq .BuildTool "uv"}}
+        env:
+          UV_PYTHON: {{GHExpr "matrix.python-version"}}
+        run: make install-dev
+{{- else if eq .BuildTool "poetry"}}
+        run: make install-dev
+{{- else}}
+        run: |
+          python -m venv .venv
+          echo "$PWD/.venv/bin" >> "$GITHUB_PATH"
+          . .venv/bin/activate
+          make install-dev
+{{- end}}
+{{- end}}
+name: CI
+
+on:
+  push:
+  pull_request:
+
+jobs:
+{{- if not .Skip.Lint}}
+  lint:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        python-version: ["{{.CIPython}}"]
+{{- template "setup" .}}
+      - name: Lint ({{if .UseRuff}}ruff{{else}}pylint{{end}})
+        run: make lint
+
+  type-check:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        python-version: ["{{.CIPython}}"]
+{{- template "setup" .}}
+      - name: Type check (mypy)
+        run: make typecheck
+{{- end}}
+{{- if not .Skip.Tests}}
+{{if not .Skip.Lint}}
+{{end}}  test:
+    runs-on: ubuntu-latest
+    strategy:
+      fail-fast: false
+      matrix:
+        python-version: [{{range $i, $v := .CIPythonVersions}}{{if $i}}, {{end}}"{{$v}}"{{end}}]
+{{- template "setup" .}}
+      - name: Test (pytest + coverage)
+        run: make test
+{{- end}}
+`
+
+// GitLabCITemplate .gitlab-ci.yml 模板, GenerateCI 且 CIProvider 为 gitlab 时生成
+const GitLabCITemplate = `# {{.ServiceTitle}} MCP 工具 CI
+# Generated by swagger2mcp
+#
+# 检查内容与 {{if not .Skip.Lint}}make lint / make typecheck{{if not .Skip.Tests}} / {{end}}{{end}}{{if not .Skip.Tests}}make test{{end}} 相同；依赖安装在 .venv 中并按锁文件缓存。
+
+stages:
+{{- if not .Skip.Lint}}
+  - lint
+  - type-check
+{{- end}}
+{{- if not .Skip.Tests}}
+  - test
+{{- end}}
+
+variables:
+  PYTHON_VERSION: "{{.CIPython}}"
+
+default:
+  image: python:${PYTHON_VERSION}
+  cache:
+    key:
+      prefix: venv-py${PYTHON_VERSION}
+      files:
+{{- if eq .BuildTool "setuptools"}}
+        - setup.py
+        - requirements-dev.txt
+{{- else}}
+        - pyproject.toml
+        - {{.BuildTool}}.lock
+{{- end}}
+    paths:
+      - .venv/
+  before_script:
+{{- if eq .BuildTool "uv"}}
+    - pip install uv
+{{- else if eq .BuildTool "poetry"}}
+    - pip install poetry
+    - poetry config virtualenvs.in-project true
+{{- else}}
+    - python -m venv .venv
+    - source .venv/bin/activate
+{{- end}}
+    - make install-dev
+{{- if not .Skip.Lint}}
+
+lint:
+  stage: lint
+  script:
+    - make lint
+
+type-check:
+  stage: type-check
+  script:
+    - make typecheck
+{{- end}}
+{{- if not .Skip.Tests}}
+
+test:
+  stage: test
+  parallel:
+    matrix:
+      - PYTHON_VERSION: [{{range $i, $v := .CIPythonVersions}}{{if $i}}, {{end}}"{{$v}}"{{end}}]
+  script:
+    - make test
+  coverage: '/^TOTAL.+?(\d+%)$/'
+{{- end}}
+`
+
+// APIInitPyTemplate api/__init__.py 模板
+const APIInitPyTemplate = `"""{{.ServiceTitle}} HTTP API (FastAPI).
+
+Generated by swagger2mcp
+"""
+
+from .router import router
+
+__all__ = ["router"]
+`
+
+// APIRouterPyTemplate api/router.py 模板, 每个端点一个路由
+const APIRouterPyTemplate = `"""{{.ServiceTitle}} HTTP API 路由.
+
+每个 API 端点对应一个 GET 路由, 返回 getEndpointDetails 工具对该端点的结果,
+其 JSON 结构与 MCP tools/call 的 result 相同。
+
+Generated by swagger2mcp
+"""
+
+from functools import lru_cache
+from typing import Any, Dict
+
+from fastapi import APIRouter
+
+from ..server import MCPServer
+
+router = APIRouter(prefix="/endpoints", tags=["endpoints"])
+
+
+@lru_cache(maxsize=None)
+def mcp_server() -> MCPServer:
+    """返回路由共用的 MCP 服务器, 首次调用时加载服务模型."""
+    return MCPServer(tool_name="{{.ToolName}}")
+
+
+def call_tool(name: str, arguments: Dict[str, Any]) -> Dict[str, Any]:
+    """调用 MCP 工具, 以 tools/call 的 result 结构返回其输出.
+
+    Args:
+        name: 工具名称, 如 "getEndpointDetails"
+        arguments: 工具参数
+
+    Returns:
+        包含文本内容的结果字典
+    """
+    text = mcp_server().tools[name](arguments)
+    return {"content": [{"type": "text", "text": text}]}
+
+
+def endpoint_details(endpoint_id: str) -> Dict[str, Any]:
+    """返回 getEndpointDetails 工具对指定端点的结果."""
+    return call_tool("getEndpointDetails", {"endpoint_id": endpoint_id})
+{{- range .APIRoutes}}
+
+
+@router.get({{Quote .Path}})
+def {{.Function}}() -> Dict[str, Any]:
+    """返回该端点的 getEndpointDetails 结果."""
+{{- if gt (len (Quote .EndpointID)) 57}}
+    return endpoint_details(
+        {{Quote .EndpointID}}
+    )
+{{- else}}
+    return endpoint_details({{Quote .EndpointID}})
+{{- end}}
+{{- end}}
+`
+
+// APIServerPyTemplate api_server.py 模板, 以 uvicorn 运行 FastAPI 应用
+const APIServerPyTemplate = `"""{{.ServiceTitle}} HTTP API 入口.
+
+以 FastAPI 在 HTTP 上提供端点详情, 与 main.py 的 stdio MCP 服务器相互独立。
+也可直接运行 uvicorn {{.PackageName}}.api_server:app。
+
+Generated by swagger2mcp
+"""
+
+import os
+
+import uvicorn
+from fastapi import FastAPI
+
+from . import __version__
+from .api import router
+
+app = FastAPI(title="{{.ToolName}}", version=__version__)
+app.include_router(router)
+
+
+def main() -> None:
+    """以 uvicorn 运行 HTTP API, 监听地址取自 API_HOST 与 API_PORT 环境变量."""
+    host = os.environ.get("API_HOST", "127.0.0.1")
+    port = int(os.environ.get("API_PORT", "8000"))
+    uvicorn.run(app, host=host, port=port)
+
+
+if __name__ == "__main__":
+    main()
 `
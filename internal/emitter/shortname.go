@@ -0,0 +1,30 @@
+package emitter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// hashSuffixLen is the length of the "-" and 8 hex digits ShortName appends.
+const hashSuffixLen = 9
+
+// ShortName returns name when it has at most max bytes. A longer name is cut
+// to max-9 bytes, trimmed of trailing "-" and "_", and suffixed with "-" plus
+// the first 8 hex digits of the SHA-256 of the whole name, so the result is
+// deterministic and names sharing a long prefix stay apart. Callers that
+// need unique names keep their numeric-suffix loop and shorten each
+// candidate, which bounds the result without letting two inputs meet. name is
+// expected to be ASCII, as the slugs derived from endpoint IDs, paths and tags
+// are; a max below 16 is raised to 16.
+func ShortName(name string, max int) string {
+	if max < 16 {
+		max = 16
+	}
+	if len(name) <= max {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	head := strings.TrimRight(name[:max-hashSuffixLen], "-_")
+	return head + "-" + hex.EncodeToString(sum[:4])
+}
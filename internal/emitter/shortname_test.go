@@ -0,0 +1,37 @@
+package emitter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShortName(t *testing.T) {
+	if got := ShortName("get-pets-id", 64); got != "get-pets-id" {
+		t.Fatalf("short name changed: %q", got)
+	}
+	long := "get-" + strings.Repeat("segment-", 40) + "id"
+	got := ShortName(long, 64)
+	if len(got) > 64 || !strings.HasPrefix(got, "get-segment-") {
+		t.Fatalf("ShortName = %q (%d bytes), want a prefix of the name within 64 bytes", got, len(got))
+	}
+	if again := ShortName(long, 64); again != got {
+		t.Fatalf("ShortName is not deterministic: %q, then %q", got, again)
+	}
+
+	// Names that only differ past the cut stay apart.
+	seen := map[string]string{}
+	for _, tail := range []string{"a", "b", "a-2", "segment-"} {
+		name := long + "-" + tail
+		short := ShortName(name, 64)
+		if len(short) > 64 {
+			t.Fatalf("ShortName(%q) = %q exceeds 64 bytes", name, short)
+		}
+		if prev, dup := seen[short]; dup {
+			t.Fatalf("%q and %q both shorten to %q", prev, name, short)
+		}
+		seen[short] = name
+	}
+	if got := ShortName(strings.Repeat("x", 100), 4); len(got) != 16 {
+		t.Fatalf("a max below 16 should be raised to 16, got %q", got)
+	}
+}
@@ -0,0 +1,75 @@
+package emitter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Categories of generated files the emitters' Skip option leaves out.
+const (
+	SkipTests    = "tests"    // generated tests and their fixtures
+	SkipLint     = "lint"     // linter configs, pre-commit hooks and lint targets
+	SkipMakefile = "makefile" // the Makefile
+	SkipReadme   = "readme"   // README.md
+	SkipEditor   = "editor"   // .editorconfig and .vscode/
+)
+
+// SkipCategories lists the valid categories of ParseSkip.
+var SkipCategories = []string{SkipTests, SkipLint, SkipMakefile, SkipReadme, SkipEditor}
+
+// Skip records the categories of files left out of a project. Each emitter
+// drops the files of a set category and adapts the rest, so that, say, the
+// Makefile has no test target when Tests is set.
+type Skip struct {
+	Tests    bool
+	Lint     bool
+	Makefile bool
+	Readme   bool
+	Editor   bool
+}
+
+// ParseSkip returns the Skip of categories, which are matched without regard
+// to case or surrounding space. An unknown category is an error listing the
+// valid ones.
+func ParseSkip(categories []string) (Skip, error) {
+	var s Skip
+	for _, c := range categories {
+		switch strings.ToLower(strings.TrimSpace(c)) {
+		case SkipTests:
+			s.Tests = true
+		case SkipLint:
+			s.Lint = true
+		case SkipMakefile:
+			s.Makefile = true
+		case SkipReadme:
+			s.Readme = true
+		case SkipEditor:
+			s.Editor = true
+		case "":
+		default:
+			return Skip{}, fmt.Errorf("unknown skip category %q (valid: %s)", c, strings.Join(SkipCategories, ", "))
+		}
+	}
+	return s, nil
+}
+
+// Categories returns the set categories in the order of SkipCategories.
+func (s Skip) Categories() []string {
+	var out []string
+	for i, set := range []bool{s.Tests, s.Lint, s.Makefile, s.Readme, s.Editor} {
+		if set {
+			out = append(out, SkipCategories[i])
+		}
+	}
+	return out
+}
+
+// Patterns returns the globs byCategory maps the set categories to, an
+// emitter's table of the files each category covers (fileexclude syntax).
+func (s Skip) Patterns(byCategory map[string][]string) []string {
+	var out []string
+	for _, c := range s.Categories() {
+		out = append(out, byCategory[c]...)
+	}
+	return out
+}
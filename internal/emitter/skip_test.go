@@ -0,0 +1,30 @@
+package emitter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSkip(t *testing.T) {
+	s, err := ParseSkip([]string{" Tests", "makefile", "", "EDITOR"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if want := (Skip{Tests: true, Makefile: true, Editor: true}); s != want {
+		t.Fatalf("ParseSkip = %+v, want %+v", s, want)
+	}
+	if got := s.Categories(); !reflect.DeepEqual(got, []string{"tests", "makefile", "editor"}) {
+		t.Fatalf("Categories = %v", got)
+	}
+	if got := s.Patterns(map[string][]string{SkipTests: {"tests/*"}, SkipLint: {".pylintrc"}, SkipEditor: {".vscode/*"}}); !reflect.DeepEqual(got, []string{"tests/*", ".vscode/*"}) {
+		t.Fatalf("Patterns = %v", got)
+	}
+	all, err := ParseSkip(SkipCategories)
+	if err != nil || !reflect.DeepEqual(all.Categories(), SkipCategories) {
+		t.Fatalf("every category must round-trip: %+v, %v", all, err)
+	}
+	if _, err := ParseSkip([]string{"docs"}); err == nil || !strings.Contains(err.Error(), "valid: tests, lint, makefile, readme, editor") {
+		t.Fatalf("expected an error listing the categories, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package tmploverride
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Ext is the file extension of a named template override.
+const Ext = ".tmpl"
+
+// ApplyNamed replaces generated files with the named overrides of lang under
+// dir: dir/<lang>/<name>.tmpl is rendered by render in place of the file
+// expand(name), where name is one of names, the emitter's template registry.
+// A file under dir/<lang> that is not <name>.tmpl for a registered name is an
+// error listing the valid names. An override whose file this run does not
+// generate (a Dockerfile without EmitDockerfile, say) is skipped. An empty dir
+// leaves files unchanged, as does a dir without a <lang> directory.
+func ApplyNamed(dir, lang string, names []string, expand func(name string) string, files map[string][]byte, render RenderFunc) error {
+	if dir == "" {
+		return nil
+	}
+	overrides, err := namedOverrides(dir, lang, names)
+	if err != nil {
+		return err
+	}
+	for _, name := range overrides {
+		rel := filepath.FromSlash(expand(name))
+		if _, ok := files[rel]; !ok {
+			continue
+		}
+		text, err := os.ReadFile(filepath.Join(dir, lang, filepath.FromSlash(name)+Ext))
+		if err != nil {
+			return fmt.Errorf("read template override %s/%s%s: %w", lang, name, Ext, err)
+		}
+		out, err := render(rel, string(text))
+		if err != nil {
+			return fmt.Errorf("render template override %s/%s%s: %w", lang, name, Ext, err)
+		}
+		files[rel] = []byte(out)
+	}
+	return nil
+}
+
+// CheckNamed reports an error unless every file under dir/<lang> is
+// <name>.tmpl for one of names. A missing dir/<lang> is fine.
+func CheckNamed(dir, lang string, names []string) error {
+	_, err := namedOverrides(dir, lang, names)
+	return err
+}
+
+// namedOverrides returns the sorted template names overridden under
+// dir/<lang>.
+func namedOverrides(dir, lang string, names []string) ([]string, error) {
+	st, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("template override dir: %w", err)
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("template override dir %s is not a directory", dir)
+	}
+	root := filepath.Join(dir, lang)
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+	var found, unknown []string
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if name, ok := strings.CutSuffix(rel, Ext); ok && known[name] {
+			found = append(found, name)
+		} else {
+			unknown = append(unknown, path.Join(lang, rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("template override dir: %w", err)
+	}
+	if len(unknown) > 0 {
+		valid := append([]string(nil), names...)
+		sort.Strings(valid)
+		return nil, fmt.Errorf("unknown template override %s; valid templates for %s: %s%s", strings.Join(unknown, ", "), lang, strings.Join(valid, Ext+", "), Ext)
+	}
+	sort.Strings(found)
+	return found, nil
+}
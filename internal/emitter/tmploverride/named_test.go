@@ -0,0 +1,76 @@
+package tmploverride
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyNamed(t *testing.T) {
+	dir := t.TempDir()
+	for rel, text := range map[string]string{
+		"go/README.md.tmpl":         "# custom\n",
+		"go/cmd/TOOL/main.go.tmpl":  "package main // {{NAME}}\n",
+		"go/Dockerfile.tmpl":        "FROM scratch\n",
+		"python/anything.txt":       "other languages are not checked here",
+		"npm/src/index.ts.tmpl":     "ignored for go\n",
+		"go/internal/spec/x.go.bak": "",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := []string{"README.md", "cmd/TOOL/main.go", "Dockerfile", "go.mod"}
+	expand := func(name string) string { return strings.Replace(name, "TOOL", "mytool", 1) }
+	render := func(rel, text string) (string, error) {
+		return strings.ReplaceAll(text, "{{NAME}}", filepath.ToSlash(rel)), nil
+	}
+	files := map[string][]byte{
+		"README.md": []byte("# built-in\n"),
+		filepath.Join("cmd", "mytool", "main.go"): []byte("built-in\n"),
+		"go.mod": []byte("module x\n"),
+	}
+
+	err := ApplyNamed(dir, "go", names, expand, files, render)
+	if err == nil || !strings.Contains(err.Error(), "unknown template override go/internal/spec/x.go.bak; valid templates for go: Dockerfile.tmpl, README.md.tmpl, cmd/TOOL/main.go.tmpl, go.mod.tmpl") {
+		t.Fatalf("expected an error listing the valid names, got %v", err)
+	}
+	if string(files["README.md"]) != "# built-in\n" {
+		t.Fatal("a refused override dir must change nothing")
+	}
+	if err := os.Remove(filepath.Join(dir, "go", "internal", "spec", "x.go.bak")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ApplyNamed(dir, "go", names, expand, files, render); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	want := map[string]string{
+		"README.md": "# custom\n",
+		filepath.Join("cmd", "mytool", "main.go"): "package main // cmd/mytool/main.go\n",
+		"go.mod": "module x\n",
+	}
+	for rel, content := range want {
+		if string(files[rel]) != content {
+			t.Errorf("%s = %q, want %q", rel, files[rel], content)
+		}
+	}
+	if len(files) != len(want) {
+		t.Errorf("an override of a file this run does not generate must not add it: %v", files)
+	}
+
+	if err := ApplyNamed(dir, "bruno", names, expand, files, render); err != nil {
+		t.Fatalf("a missing language directory should be a no-op: %v", err)
+	}
+	if err := CheckNamed(dir, "python", []string{"README.md"}); err == nil || !strings.Contains(err.Error(), "python/anything.txt") {
+		t.Fatalf("expected CheckNamed to reject python/anything.txt, got %v", err)
+	}
+	if err := ApplyNamed(filepath.Join(dir, "missing"), "go", names, expand, files, render); err == nil {
+		t.Fatal("expected an error for a missing template override dir")
+	}
+}
@@ -0,0 +1,84 @@
+// Package tmploverride lets users replace generated files with their own
+// templates without forking swagger2mcp: a file in the override directory at
+// the same relative path as a generated file is rendered in its place (Apply),
+// and <lang>/<name>.tmpl in the same directory overrides a built-in template
+// by its registered name (ApplyNamed).
+package tmploverride
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// RenderFunc renders the override text for the generated file rel.
+type RenderFunc func(rel, text string) (string, error)
+
+// Apply replaces each entry of files (keyed by relative path) that has an
+// override at the same relative path under dir with the override rendered by
+// render. Files without an override keep their built-in content; an empty dir
+// leaves files unchanged.
+func Apply(dir string, files map[string][]byte, render RenderFunc) error {
+	if dir == "" {
+		return nil
+	}
+	st, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("template override dir: %w", err)
+	}
+	if !st.IsDir() {
+		return fmt.Errorf("template override dir %s is not a directory", dir)
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		text, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read template override %s: %w", filepath.ToSlash(rel), err)
+		}
+		out, err := render(rel, string(text))
+		if err != nil {
+			return fmt.Errorf("render template override %s: %w", filepath.ToSlash(rel), err)
+		}
+		files[rel] = []byte(out)
+	}
+	return nil
+}
+
+// Hash returns a SHA-256 over the slash-separated path and content of every
+// file under dir, so a run can tell whether the overrides changed since the
+// last one.
+func Hash(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("template override dir: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,64 @@
+package tmploverride
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for rel, text := range map[string]string{"README.md": "# custom {{NAME}}\n", "src/index.ts": "// header\n", "unused.txt": "x"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string][]byte{
+		"README.md":                       []byte("# built-in\n"),
+		filepath.Join("src", "index.ts"):  []byte("built-in\n"),
+		filepath.Join("src", "server.ts"): []byte("untouched\n"),
+	}
+	var rendered []string
+	err := Apply(dir, files, func(rel, text string) (string, error) {
+		rendered = append(rendered, filepath.ToSlash(rel))
+		return strings.ReplaceAll(text, "{{NAME}}", "tool"), nil
+	})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := strings.Join(rendered, ","); got != "README.md,src/index.ts" {
+		t.Fatalf("rendered %s", got)
+	}
+	want := map[string]string{"README.md": "# custom tool\n", filepath.Join("src", "index.ts"): "// header\n", filepath.Join("src", "server.ts"): "untouched\n"}
+	for rel, content := range want {
+		if string(files[rel]) != content {
+			t.Errorf("%s = %q, want %q", rel, files[rel], content)
+		}
+	}
+	if len(files) != len(want) {
+		t.Errorf("overrides must not add files: %v", files)
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	files := map[string][]byte{"README.md": []byte("x")}
+	if err := Apply("", files, nil); err != nil {
+		t.Fatalf("empty dir should be a no-op: %v", err)
+	}
+	if err := Apply(filepath.Join(t.TempDir(), "missing"), files, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist for a missing dir, got %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("{{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := Apply(dir, files, func(rel, text string) (string, error) { return "", errors.New("bad template") })
+	if err == nil || !strings.Contains(err.Error(), "render template override README.md: bad template") {
+		t.Fatalf("expected a render error naming the file, got %v", err)
+	}
+}
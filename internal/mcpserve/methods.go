@@ -0,0 +1,469 @@
+package mcpserve
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// EndpointSummary is the listEndpoints/searchEndpoints view of an endpoint.
+type EndpointSummary struct {
+	ID      string   `json:"id"`
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Summary string   `json:"summary"`
+	Tags    []string `json:"tags"`
+}
+
+// SearchQuery mirrors the searchEndpoints tool arguments.
+type SearchQuery struct {
+	Keyword     string
+	Tag         string
+	Method      string // http verb, case-insensitive
+	PathPattern string // regex
+}
+
+// SchemaSummary is the listSchemas view of a schema.
+type SchemaSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ListEndpoints returns all endpoints sorted by path then method.
+func ListEndpoints(sm *genspec.ServiceModel) []EndpointSummary {
+	return SearchEndpoints(sm, SearchQuery{})
+}
+
+// SearchEndpoints filters endpoints by keyword, tag, method, and path regex.
+// An invalid path pattern yields no matches.
+func SearchEndpoints(sm *genspec.ServiceModel, q SearchQuery) []EndpointSummary {
+	var re *regexp.Regexp
+	if strings.TrimSpace(q.PathPattern) != "" {
+		var err error
+		re, err = regexp.Compile(q.PathPattern)
+		if err != nil {
+			return []EndpointSummary{}
+		}
+	}
+	kw := strings.ToLower(strings.TrimSpace(q.Keyword))
+	tag := strings.TrimSpace(q.Tag)
+	method := strings.ToLower(strings.TrimSpace(q.Method))
+
+	out := make([]EndpointSummary, 0, len(sm.Endpoints))
+	for _, ep := range sm.Endpoints {
+		if method != "" && string(ep.Method) != method {
+			continue
+		}
+		if tag != "" && !containsTag(ep.Tags, tag) {
+			continue
+		}
+		if re != nil && !re.MatchString(ep.Path) {
+			continue
+		}
+		if kw != "" {
+			text := strings.ToLower(ep.Summary + "\n" + ep.Description + "\n" + ep.Path)
+			if !strings.Contains(text, kw) {
+				continue
+			}
+		}
+		out = append(out, EndpointSummary{
+			ID:      ep.ID,
+			Method:  string(ep.Method),
+			Path:    ep.Path,
+			Summary: ep.Summary,
+			Tags:    append([]string(nil), ep.Tags...),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path == out[j].Path {
+			return out[i].Method < out[j].Method
+		}
+		return genspec.CompareNames(out[i].Path, out[j].Path) < 0
+	})
+	return out
+}
+
+// GetEndpointDetails looks up an endpoint by ID, or by method and path when
+// both are provided.
+func GetEndpointDetails(sm *genspec.ServiceModel, id, method, path string) (*genspec.EndpointModel, bool) {
+	id = strings.TrimSpace(id)
+	method = strings.ToLower(strings.TrimSpace(method))
+	for i := range sm.Endpoints {
+		ep := sm.Endpoints[i]
+		if id != "" {
+			if ep.ID == id {
+				return &ep, true
+			}
+			continue
+		}
+		if string(ep.Method) == method && ep.Path == path {
+			return &ep, true
+		}
+	}
+	return nil, false
+}
+
+// ListSchemas returns schema names and descriptions sorted by name.
+func ListSchemas(sm *genspec.ServiceModel) []SchemaSummary {
+	names := make([]string, 0, len(sm.Schemas))
+	for name := range sm.Schemas {
+		names = append(names, name)
+	}
+	genspec.SortNames(names)
+	out := make([]SchemaSummary, 0, len(names))
+	for _, name := range names {
+		out = append(out, SchemaSummary{Name: name, Description: sm.Schemas[name].Description})
+	}
+	return out
+}
+
+// GetSchemaDetails returns a copy of the named schema.
+func GetSchemaDetails(sm *genspec.ServiceModel, name string) (*genspec.Schema, bool) {
+	s, ok := sm.Schemas[name]
+	if !ok {
+		return nil, false
+	}
+	sc := s
+	return &sc, true
+}
+
+// The Format* functions below produce the same text as the methods package
+// of a goemitter-generated project; parity_test.go keeps them in step.
+
+// BaseURLEnv names the environment variable generated tools read to override
+// the spec's server URL; serve honours it too so both report the same base URL.
+const BaseURLEnv = "API_BASE_URL"
+
+// BaseURL returns API_BASE_URL when set, otherwise the first server's URL, or
+// "" when the spec lists none.
+func BaseURL(sm *genspec.ServiceModel) string {
+	if url := strings.TrimSpace(os.Getenv(BaseURLEnv)); url != "" {
+		return url
+	}
+	if sm == nil || len(sm.Servers) == 0 {
+		return ""
+	}
+	return sm.Servers[0].URL
+}
+
+// FormatEndpointsOverview renders the listEndpoints text: the base URL, then
+// method, tag, and path-prefix distribution followed by every endpoint.
+func FormatEndpointsOverview(sm *genspec.ServiceModel) string {
+	endpoints := ListEndpoints(sm)
+	if len(endpoints) == 0 {
+		return "无可用接口"
+	}
+	lines := []string{fmt.Sprintf("API 接口概览 (%d 个接口)", len(endpoints))}
+	if base := BaseURL(sm); base != "" {
+		lines = append(lines, "基础 URL: "+base)
+	}
+	lines = append(lines, "")
+
+	methodStats := map[string]int{}
+	tagStats := map[string]int{}
+	for _, ep := range endpoints {
+		methodStats[ep.Method]++
+		for _, t := range ep.Tags {
+			tagStats[t]++
+		}
+	}
+	lines = append(lines, "HTTP 方法分布:")
+	for _, m := range sortedKeys(methodStats) {
+		lines = append(lines, fmt.Sprintf("  %s: %d 个接口", strings.ToUpper(m), methodStats[m]))
+	}
+	lines = append(lines, "")
+
+	if len(tagStats) > 0 {
+		lines = append(lines, "服务模块分布:")
+		lines = append(lines, topCounts(tagStats, "  ... 还有 %d 个其他服务模块")...)
+		lines = append(lines, "")
+	}
+
+	prefixes := map[string]int{}
+	for _, ep := range endpoints {
+		parts := strings.Split(strings.Trim(ep.Path, "/"), "/")
+		if len(parts) >= 2 {
+			prefixes["/"+parts[0]+"/"+parts[1]]++
+		} else if len(parts) == 1 && parts[0] != "" {
+			prefixes["/"+parts[0]]++
+		}
+	}
+	lines = append(lines, "主要路由路径:")
+	lines = append(lines, topCounts(prefixes, "  ... 还有 %d 个其他路径")...)
+	lines = append(lines, "")
+
+	lines = append(lines, "所有接口端点:")
+	for _, ep := range endpoints {
+		lines = append(lines, fmt.Sprintf("  %s %s - %s", strings.ToUpper(ep.Method), ep.Path, orDefault(ep.Summary, "无描述")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parameterLocations orders the parameter sections of FormatEndpointDetails.
+var parameterLocations = []struct{ in, title string }{
+	{"path", "路径参数"},
+	{"query", "查询参数"},
+	{"header", "请求头参数"},
+	{"cookie", "Cookie参数"},
+}
+
+// FormatEndpointDetails renders an endpoint's parameters, request body, and
+// responses, expanding referenced schemas.
+func FormatEndpointDetails(ep *genspec.EndpointModel, sm *genspec.ServiceModel) string {
+	lines := []string{
+		fmt.Sprintf("%s %s", strings.ToUpper(string(ep.Method)), ep.Path),
+		fmt.Sprintf("摘要: %s", orDefault(ep.Summary, "无")),
+		fmt.Sprintf("描述: %s", orDefault(ep.Description, "无")),
+		fmt.Sprintf("标签: %s", orDefault(strings.Join(ep.Tags, ", "), "无")),
+	}
+	if len(ep.Extensions) > 0 {
+		lines = append(lines, fmt.Sprintf("扩展: %s", mustJSON(ep.Extensions)))
+	}
+	for _, loc := range parameterLocations {
+		var located []genspec.ParameterModel
+		for _, p := range ep.Parameters {
+			if p.In == loc.in {
+				located = append(located, p)
+			}
+		}
+		if len(located) == 0 {
+			continue
+		}
+		lines = append(lines, "", loc.title+":")
+		for _, p := range located {
+			paramType := "unknown"
+			enum := ""
+			if p.Schema != nil && p.Schema.Schema != nil {
+				paramType = orDefault(p.Schema.Schema.Type, "unknown")
+				if p.Schema.Schema.Format != "" {
+					paramType += " (" + p.Schema.Schema.Format + ")"
+				}
+				if len(p.Schema.Schema.Enum) > 0 {
+					enum = fmt.Sprintf(" (允许值: %s)", mustJSON(p.Schema.Schema.Enum))
+				}
+			}
+			lines = append(lines, fmt.Sprintf("  • %s - %s%s %s", p.Name, paramType, enum, requiredLabel(p.Required)))
+			if desc := strings.Join(strings.Fields(p.Description), " "); desc != "" {
+				lines = append(lines, "    说明: "+desc)
+			}
+			if p.Example != nil {
+				lines = append(lines, "    示例: "+mustJSON(p.Example))
+			}
+		}
+	}
+	if ep.RequestBody != nil {
+		lines = append(lines, "", "请求体:")
+		lines = append(lines, fmt.Sprintf("  Content-Type: %s %s", orDefault(mimeList(ep.RequestBody.Content), "unknown"), requiredLabel(ep.RequestBody.Required)))
+		lines = append(lines, formatMedia(ep.RequestBody.Content, sm, "  ")...)
+	}
+	if len(ep.Responses) > 0 {
+		lines = append(lines, "", "响应:")
+		for _, r := range ep.Responses {
+			lines = append(lines, fmt.Sprintf("  • %s: %s", r.Status, r.Description))
+			lines = append(lines, formatMedia(r.Content, sm, "    ")...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatSchemaDetails renders a schema's type, properties, items, and enum
+// values, expanding referenced schemas.
+func FormatSchemaDetails(schema *genspec.Schema, sm *genspec.ServiceModel) string {
+	lines := []string{fmt.Sprintf("Schema: %s", schema.Name)}
+	if len(schema.Extensions) > 0 {
+		lines = append(lines, fmt.Sprintf("扩展: %s", mustJSON(schema.Extensions)))
+	}
+	lines = append(lines, formatSchema(schema, sm, "")...)
+	return strings.Join(lines, "\n")
+}
+
+func formatMedia(content []genspec.Media, sm *genspec.ServiceModel, indent string) []string {
+	var lines []string
+	for _, m := range content {
+		if m.Schema != nil {
+			lines = append(lines, fmt.Sprintf("%sSchema (%s):", indent, m.Mime))
+			if m.Schema.Schema != nil {
+				lines = append(lines, formatSchema(m.Schema.Schema, sm, indent+"  ")...)
+			}
+			if m.Schema.Ref != nil {
+				name := strings.Replace(m.Schema.Ref.Ref, "#/components/schemas/", "", 1)
+				lines = append(lines, fmt.Sprintf("%s  引用: %s", indent, name))
+				if ref, ok := sm.Schemas[name]; ok {
+					lines = append(lines, fmt.Sprintf("%s  └─ %s 详情:", indent, name))
+					lines = append(lines, formatSchema(&ref, sm, indent+"    ")...)
+				}
+			}
+		}
+		if m.Example != nil {
+			lines = append(lines, fmt.Sprintf("%s示例: %s", indent, mustJSON(m.Example)))
+		}
+	}
+	return lines
+}
+
+func formatSchema(schema *genspec.Schema, sm *genspec.ServiceModel, indent string) []string {
+	var lines []string
+	if schema == nil {
+		return lines
+	}
+	if schema.Type != "" {
+		lines = append(lines, fmt.Sprintf("%s类型: %s", indent, schema.Type))
+	}
+	if len(schema.Properties) > 0 {
+		lines = append(lines, fmt.Sprintf("%s属性:", indent))
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		genspec.SortNames(names)
+		for _, name := range names {
+			lines = append(lines, formatProperty(name, schema.Properties[name], containsTag(schema.Required, name), sm, indent)...)
+		}
+	}
+	if schema.Items != nil {
+		if schema.Items.Schema != nil {
+			lines = append(lines, fmt.Sprintf("%s数组元素类型: %s", indent, orDefault(schema.Items.Schema.Type, "unknown")))
+			lines = append(lines, formatSchema(schema.Items.Schema, sm, indent+"  ")...)
+		} else if schema.Items.Ref != nil {
+			name := refName(schema.Items.Ref.Ref)
+			lines = append(lines, fmt.Sprintf("%s数组元素类型: %s (引用)", indent, name))
+			if ref, ok := sm.Schemas[name]; ok {
+				lines = append(lines, fmt.Sprintf("%s└─ %s 详情:", indent, name))
+				lines = append(lines, formatSchema(&ref, sm, indent+"  ")...)
+			}
+		}
+	}
+	if len(schema.AllOf) > 0 {
+		lines = append(lines, fmt.Sprintf("%s组合类型 (AllOf):", indent))
+		for i, sub := range schema.AllOf {
+			lines = append(lines, fmt.Sprintf("%s  %d. ", indent, i+1))
+			if sub.Schema != nil {
+				lines = append(lines, formatSchema(sub.Schema, sm, indent+"    ")...)
+			} else if sub.Ref != nil {
+				name := refName(sub.Ref.Ref)
+				lines = append(lines, fmt.Sprintf("%s    引用: %s", indent, name))
+				if ref, ok := sm.Schemas[name]; ok {
+					lines = append(lines, formatSchema(&ref, sm, indent+"      ")...)
+				}
+			}
+		}
+	}
+	if len(schema.Enum) > 0 {
+		lines = append(lines, fmt.Sprintf("%s允许值: %s", indent, mustJSON(schema.Enum)))
+	}
+	if c := schema.Constraints(); len(c) > 0 {
+		lines = append(lines, fmt.Sprintf("%s约束: %s", indent, strings.Join(c, ", ")))
+	}
+	return lines
+}
+
+func formatProperty(name string, prop *genspec.SchemaOrRef, required bool, sm *genspec.ServiceModel, indent string) []string {
+	req := requiredLabel(required)
+	switch {
+	case prop != nil && prop.Schema != nil:
+		var lines []string
+		propType := orDefault(prop.Schema.Type, "unknown")
+		items := prop.Schema.Items
+		switch {
+		case propType == "array" && items != nil && items.Ref != nil:
+			ref := refName(items.Ref.Ref)
+			lines = append(lines, fmt.Sprintf("%s  • %s: %s<%s> %s", indent, name, propType, ref, req))
+			if refSchema, ok := sm.Schemas[ref]; ok {
+				lines = append(lines, fmt.Sprintf("%s    └─ %s 详情:", indent, ref))
+				lines = append(lines, formatSchema(&refSchema, sm, indent+"      ")...)
+			}
+		case propType == "array" && items != nil && items.Schema != nil:
+			lines = append(lines, fmt.Sprintf("%s  • %s: %s<%s> %s", indent, name, propType, orDefault(items.Schema.Type, "unknown"), req))
+		default:
+			lines = append(lines, fmt.Sprintf("%s  • %s: %s %s", indent, name, propType, req))
+		}
+		if len(prop.Schema.Enum) > 0 {
+			lines = append(lines, fmt.Sprintf("%s    允许值: %s", indent, mustJSON(prop.Schema.Enum)))
+		}
+		if c := prop.Schema.Constraints(); len(c) > 0 {
+			lines = append(lines, fmt.Sprintf("%s    约束: %s", indent, strings.Join(c, ", ")))
+		}
+		return lines
+	case prop != nil && prop.Ref != nil:
+		ref := refName(prop.Ref.Ref)
+		lines := []string{fmt.Sprintf("%s  • %s: %s (引用) %s", indent, name, ref, req)}
+		if refSchema, ok := sm.Schemas[ref]; ok {
+			lines = append(lines, fmt.Sprintf("%s    └─ %s 详情:", indent, ref))
+			lines = append(lines, formatSchema(&refSchema, sm, indent+"      ")...)
+		}
+		return lines
+	}
+	return []string{fmt.Sprintf("%s  • %s: unknown %s", indent, name, req)}
+}
+
+// topCounts lists up to ten entries of stats by descending count, then name,
+// followed by moreFormat when entries were left out.
+func topCounts(stats map[string]int, moreFormat string) []string {
+	keys := sortedKeys(stats)
+	sort.SliceStable(keys, func(i, j int) bool { return stats[keys[i]] > stats[keys[j]] })
+	const maxShow = 10
+	var lines []string
+	for i, k := range keys {
+		if i == maxShow {
+			lines = append(lines, fmt.Sprintf(moreFormat, len(keys)-maxShow))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %d 个接口", k, stats[k]))
+	}
+	return lines
+}
+
+func refName(ref string) string {
+	ref = strings.Replace(ref, "#/components/schemas/", "", 1)
+	return strings.Replace(ref, "#/definitions/", "", 1)
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func mimeList(content []genspec.Media) string {
+	mimes := make([]string, 0, len(content))
+	for _, m := range content {
+		mimes = append(mimes, m.Mime)
+	}
+	return strings.Join(mimes, ", ")
+}
+
+func requiredLabel(required bool) string {
+	if required {
+		return "[必需]"
+	}
+	return "[可选]"
+}
+
+func orDefault(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+func containsTag(tags []string, want string) bool {
+	for _, t := range tags {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	genspec.SortNames(keys)
+	return keys
+}
@@ -0,0 +1,229 @@
+package mcpserve
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/swagger2mcp/internal/emitter/goemitter"
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+const paritySpecYAML = `openapi: 3.0.0
+info:
+  title: Parity API
+  version: '1.0.0'
+  description: Checks serve output against generated projects
+servers:
+  - url: https://api.example.com
+    description: production
+paths:
+  /pets/{id}:
+    get:
+      summary: Get pet
+      tags: [pets]
+      x-internal: true
+      x-rate-limit: {rps: 10}
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: {type: integer, format: int64}
+        - name: verbose
+          in: query
+          schema: {type: boolean}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              example: {name: rex}
+        '404':
+          description: missing
+    put:
+      summary: Replace pet
+      tags: [pets, admin]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: {type: integer}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '204': {description: done}
+  /health:
+    get:
+      summary: Health check
+      responses:
+        '200':
+          description: ok
+          content:
+            text/plain:
+              schema: {type: string}
+components:
+  schemas:
+    Pet:
+      type: object
+      description: A pet
+      x-owner: pets-team
+      required: [name]
+      properties:
+        name: {type: string, example: rex}
+        tags:
+          type: array
+          minItems: 1
+          uniqueItems: true
+          items: {type: string}
+        age:
+          type: integer
+          minimum: 0
+          exclusiveMinimum: true
+          maximum: 40
+        owner:
+          $ref: '#/components/schemas/Owner'
+        kind:
+          type: string
+          enum: [dog, cat]
+    Owner:
+      type: object
+      properties:
+        id: {type: integer, format: int64}
+`
+
+// parityHarness prints every formatted tool output of a generated Go
+// project as a JSON object keyed like formatAll.
+const parityHarness = `package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"parity/internal/mcp/methods"
+	"parity/internal/spec"
+)
+
+func main() {
+	sm, err := spec.Load()
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]string{"overview": methods.FormatEndpointsOverview(sm)}
+	for i := range sm.Endpoints {
+		ep := &sm.Endpoints[i]
+		out["endpoint "+ep.ID] = methods.FormatEndpointDetails(ep, sm)
+	}
+	for name := range sm.Schemas {
+		sc := sm.Schemas[name]
+		out["schema "+name] = methods.FormatSchemaDetails(&sc, sm)
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(out)
+}
+`
+
+func formatAll(sm *genspec.ServiceModel) map[string]string {
+	out := map[string]string{"overview": FormatEndpointsOverview(sm)}
+	for i := range sm.Endpoints {
+		ep := &sm.Endpoints[i]
+		out["endpoint "+ep.ID] = FormatEndpointDetails(ep, sm)
+	}
+	for name := range sm.Schemas {
+		sc := sm.Schemas[name]
+		out["schema "+name] = FormatSchemaDetails(&sc, sm)
+	}
+	return out
+}
+
+// TestFormatParityWithGeneratedGoProject keeps serve's tool output identical
+// to the tools of a project generated by goemitter.
+func TestFormatParityWithGeneratedGoProject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go run of generated project in -short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(paritySpecYAML), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	ctx := context.Background()
+	doc, serr := genspec.Load(ctx, specPath)
+	if serr != nil {
+		t.Fatalf("load: %v", serr)
+	}
+	sm, err := genspec.BuildServiceModel(ctx, doc, nil)
+	if err != nil {
+		t.Fatalf("build model: %v", err)
+	}
+
+	outDir := filepath.Join(dir, "gen")
+	if _, err := goemitter.Emit(ctx, sm, goemitter.Options{OutDir: outDir, ToolName: "parity", ModuleName: "parity"}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	harnessDir := filepath.Join(outDir, "cmd", "parity-harness")
+	if err := os.MkdirAll(harnessDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(harnessDir, "main.go"), []byte(parityHarness), 0o600); err != nil {
+		t.Fatalf("write harness: %v", err)
+	}
+
+	// The methods and spec packages only use the standard library, so the
+	// harness builds without fetching the generated project's dependencies.
+	cmd := exec.Command(goBin, "run", "./cmd/parity-harness")
+	cmd.Dir = outDir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	raw, err := cmd.Output()
+	if err != nil {
+		var stderr []byte
+		if ee, ok := err.(*exec.ExitError); ok {
+			stderr = ee.Stderr
+		}
+		t.Fatalf("go run harness: %v\n%s", err, stderr)
+	}
+	var generated map[string]string
+	if err := json.Unmarshal(raw, &generated); err != nil {
+		t.Fatalf("decode harness output: %v", err)
+	}
+
+	// The generated project reads its model back from model.json; mirror that
+	// so example values have the same dynamic types on both sides.
+	var roundTripped genspec.ServiceModel
+	encoded, _ := json.Marshal(sm)
+	if err := json.Unmarshal(encoded, &roundTripped); err != nil {
+		t.Fatalf("round-trip model: %v", err)
+	}
+	served := formatAll(&roundTripped)
+
+	if len(served) != len(generated) {
+		t.Fatalf("output keys differ: serve=%d generated=%d", len(served), len(generated))
+	}
+	for key, want := range generated {
+		if got := served[key]; got != want {
+			t.Errorf("%s differs from generated project\n--- generated\n%s\n--- serve\n%s", key, want, got)
+		}
+	}
+	if got := served["endpoint get /pets/{id}"]; !strings.Contains(got, `扩展: {"x-internal":true,"x-rate-limit":{"rps":10}}`) {
+		t.Errorf("endpoint details missing extensions:\n%s", got)
+	}
+	if got := served["schema Pet"]; !strings.Contains(got, `扩展: {"x-owner":"pets-team"}`) {
+		t.Errorf("schema details missing extensions:\n%s", got)
+	}
+	if got := served["schema Pet"]; !strings.Contains(got, "约束: minItems=1, uniqueItems") || !strings.Contains(got, "约束: minimum>0, maximum<=40") {
+		t.Errorf("schema details missing constraints:\n%s", got)
+	}
+}
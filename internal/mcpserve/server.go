@@ -0,0 +1,142 @@
+// Package mcpserve exposes the swagger2mcp documentation tools over MCP
+// straight from an in-memory ServiceModel, without generating a project.
+package mcpserve
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	goserver "github.com/mark3labs/mcp-go/server"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// Server wraps an MCP server whose tools read the current ServiceModel on
+// every call, so the model can be swapped while serving.
+type Server struct {
+	mu  sync.RWMutex
+	sm  *genspec.ServiceModel
+	srv *goserver.MCPServer
+}
+
+// New creates a Server registering listEndpoints, searchEndpoints,
+// getEndpointDetails, listSchemas, and getSchemaDetails.
+func New(sm *genspec.ServiceModel) *Server {
+	if sm == nil {
+		sm = &genspec.ServiceModel{}
+	}
+	name := sm.Title
+	if name == "" {
+		name = "mcp-tool"
+	}
+	s := &Server{sm: sm}
+	s.srv = goserver.NewMCPServer(name, sm.Version,
+		goserver.WithToolCapabilities(true),
+		goserver.WithInstructions("This server exposes tools to query your API documentation."),
+		goserver.WithRecovery(),
+	)
+	s.registerTools()
+	return s
+}
+
+// MCPServer returns the underlying mcp-go server, e.g. for in-process clients.
+func (s *Server) MCPServer() *goserver.MCPServer { return s.srv }
+
+// Model returns the ServiceModel currently being served.
+func (s *Server) Model() *genspec.ServiceModel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sm
+}
+
+// SetModel replaces the served ServiceModel. Nil is ignored.
+func (s *Server) SetModel(sm *genspec.ServiceModel) {
+	if sm == nil {
+		return
+	}
+	s.mu.Lock()
+	s.sm = sm
+	s.mu.Unlock()
+}
+
+// ServeStdio serves newline-delimited JSON-RPC on in/out until ctx is done or
+// in is closed.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	return goserver.NewStdioServer(s.srv).Listen(ctx, in, out)
+}
+
+func (s *Server) registerTools() {
+	s.srv.AddTool(mcp.NewTool("listEndpoints",
+		mcp.WithDescription("Show API overview and routing summary"),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sm := s.Model()
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewTextContent(FormatEndpointsOverview(sm))},
+			StructuredContent: ListEndpoints(sm),
+		}, nil
+	})
+
+	s.srv.AddTool(mcp.NewTool("searchEndpoints",
+		mcp.WithDescription("Search endpoints by keyword, tag, method, or path regex"),
+		mcp.WithString("keyword", mcp.Description("Matched against summary, description, and path")),
+		mcp.WithString("tag", mcp.Description("Exact tag to filter by")),
+		mcp.WithString("method", mcp.Description("HTTP method to filter by")),
+		mcp.WithString("pathPattern", mcp.Description("Regular expression matched against the path")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		out := SearchEndpoints(s.Model(), SearchQuery{
+			Keyword:     req.GetString("keyword", ""),
+			Tag:         req.GetString("tag", ""),
+			Method:      req.GetString("method", ""),
+			PathPattern: req.GetString("pathPattern", ""),
+		})
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewTextContent(fmt.Sprintf("%d matches", len(out)))},
+			StructuredContent: out,
+		}, nil
+	})
+
+	s.srv.AddTool(mcp.NewTool("getEndpointDetails",
+		mcp.WithDescription("Get endpoint details by id or method+path"),
+		mcp.WithString("id", mcp.Description("Endpoint ID, e.g. \"get /pets\"")),
+		mcp.WithString("method", mcp.Description("HTTP method, used with path")),
+		mcp.WithString("path", mcp.Description("Endpoint path, used with method")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sm := s.Model()
+		ep, ok := GetEndpointDetails(sm, req.GetString("id", ""), req.GetString("method", ""), req.GetString("path", ""))
+		if !ok {
+			return mcp.NewToolResultError("endpoint not found"), nil
+		}
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewTextContent(FormatEndpointDetails(ep, sm))},
+			StructuredContent: ep,
+		}, nil
+	})
+
+	s.srv.AddTool(mcp.NewTool("listSchemas",
+		mcp.WithDescription("List schema names"),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		out := ListSchemas(s.Model())
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewTextContent(fmt.Sprintf("%d schemas", len(out)))},
+			StructuredContent: out,
+		}, nil
+	})
+
+	s.srv.AddTool(mcp.NewTool("getSchemaDetails",
+		mcp.WithDescription("Get details for a schema by name"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Schema name")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sm := s.Model()
+		sc, ok := GetSchemaDetails(sm, req.GetString("name", ""))
+		if !ok {
+			return mcp.NewToolResultError("schema not found"), nil
+		}
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewTextContent(FormatSchemaDetails(sc, sm))},
+			StructuredContent: sc,
+		}, nil
+	})
+}
@@ -0,0 +1,156 @@
+package mcpserve
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func sampleModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Title:   "Sample API",
+		Version: "1.0.0",
+		Endpoints: []genspec.EndpointModel{
+			{ID: "get /pets", Method: genspec.GET, Path: "/pets", Summary: "List pets", Tags: []string{"pets"}},
+			{ID: "post /pets", Method: genspec.POST, Path: "/pets", Summary: "Create pet", Tags: []string{"pets"},
+				RequestBody: &genspec.RequestBodyModel{Required: true, Content: []genspec.Media{
+					{Mime: "application/json", Schema: &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/Pet"}}},
+				}}},
+			{ID: "get /users", Method: genspec.GET, Path: "/users", Summary: "List users", Tags: []string{"users"}},
+		},
+		Schemas: map[string]genspec.Schema{
+			"Pet": {Name: "Pet", Type: "object", Description: "A pet", Required: []string{"name"},
+				Properties: map[string]*genspec.SchemaOrRef{"name": {Schema: &genspec.Schema{Type: "string"}}}},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, s *Server) *client.Client {
+	t.Helper()
+	c, err := client.NewInProcessClient(s.MCPServer())
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	return c
+}
+
+func callText(t *testing.T, c *client.Client, name string, args map[string]any) (string, bool) {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := c.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("call %s: %v", name, err)
+	}
+	var parts []string
+	for _, content := range res.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, tc.Text)
+		}
+	}
+	return strings.Join(parts, "\n"), res.IsError
+}
+
+func TestServer_ListsFiveTools(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, New(sampleModel()))
+	res, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	have := map[string]bool{}
+	for _, tool := range res.Tools {
+		have[tool.Name] = true
+	}
+	for _, want := range []string{"listEndpoints", "searchEndpoints", "getEndpointDetails", "listSchemas", "getSchemaDetails"} {
+		if !have[want] {
+			t.Fatalf("missing tool %s in %v", want, have)
+		}
+	}
+}
+
+func TestServer_ToolCalls(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, New(sampleModel()))
+
+	if text, _ := callText(t, c, "listEndpoints", nil); !strings.Contains(text, "GET /users - List users") {
+		t.Fatalf("overview missing endpoint: %s", text)
+	}
+	if text, _ := callText(t, c, "searchEndpoints", map[string]any{"tag": "pets", "method": "POST"}); text != "1 matches" {
+		t.Fatalf("search: got %q", text)
+	}
+	text, isErr := callText(t, c, "getEndpointDetails", map[string]any{"id": "post /pets"})
+	if isErr || !strings.Contains(text, "引用: Pet") || !strings.Contains(text, "name: string [必需]") {
+		t.Fatalf("details: got %q", text)
+	}
+	if text, _ := callText(t, c, "getEndpointDetails", map[string]any{"method": "get", "path": "/pets"}); !strings.HasPrefix(text, "GET /pets") {
+		t.Fatalf("details by method+path: got %q", text)
+	}
+	if _, isErr := callText(t, c, "getEndpointDetails", map[string]any{"id": "get /missing"}); !isErr {
+		t.Fatalf("expected error result for unknown endpoint")
+	}
+	if text, _ := callText(t, c, "listSchemas", nil); text != "1 schemas" {
+		t.Fatalf("list schemas: got %q", text)
+	}
+	if text, isErr := callText(t, c, "getSchemaDetails", map[string]any{"name": "Pet"}); isErr || !strings.Contains(text, "Schema: Pet") {
+		t.Fatalf("schema details: got %q", text)
+	}
+}
+
+func TestServer_SetModelSwapsServedModel(t *testing.T) {
+	t.Parallel()
+	s := New(sampleModel())
+	c := newTestClient(t, s)
+	s.SetModel(&genspec.ServiceModel{Title: "Other"})
+	if text, _ := callText(t, c, "listEndpoints", nil); text != "无可用接口" {
+		t.Fatalf("expected swapped model, got %q", text)
+	}
+}
+
+func TestWatch_ReportsChanges(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte("a"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go func() {
+		_ = Watch(ctx, path, 10*time.Millisecond, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+	}()
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("changed"), 0o600); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatalf("watch did not report change")
+	}
+}
@@ -0,0 +1,38 @@
+package mcpserve
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// DefaultWatchInterval is how often Watch polls the spec file.
+const DefaultWatchInterval = time.Second
+
+// Watch polls path every interval and calls onChange whenever its size or
+// modification time differs from the previous poll. It blocks until ctx is
+// done. A file that temporarily disappears (e.g. editors replacing it) is not
+// reported until it reappears.
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	last, _ := os.Stat(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		st, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if last == nil || st.Size() != last.Size() || !st.ModTime().Equal(last.ModTime()) {
+			last = st
+			onChange()
+		}
+	}
+}
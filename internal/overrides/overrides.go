@@ -0,0 +1,139 @@
+// Package overrides applies a small YAML file of per-endpoint edits on top of
+// a built ServiceModel, so descriptions written for human API docs can be
+// reworded for agents without touching the spec itself:
+//
+//	get /pets/{id}:
+//	  summary: Fetch one pet by its id
+//	listPets:            # operationIds work as keys too
+//	  description: Lists pets, newest first.
+//	  featured: true
+//	delete /pets/{id}:
+//	  hidden: true
+//
+// Keys are endpoint IDs ("<method> <path>", method case-insensitive) or
+// operationIds. summary and description replace the spec's text when set,
+// hidden drops the endpoint from the model, and featured sets
+// EndpointModel.Featured.
+package overrides
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// Override is the set of edits for one endpoint. Nil text fields keep the
+// spec's value; an empty string clears it.
+type Override struct {
+	Summary     *string `yaml:"summary"`
+	Description *string `yaml:"description"`
+	Hidden      bool    `yaml:"hidden"`
+	Featured    bool    `yaml:"featured"`
+}
+
+// File maps endpoint IDs or operationIds to their overrides.
+type File map[string]Override
+
+// Load reads and parses an overrides file. Unknown fields are rejected so a
+// misspelled key does not silently do nothing.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read overrides %q: %w", path, err)
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse overrides %q: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse decodes overrides from YAML (or JSON). An empty document yields an
+// empty File.
+func Parse(data []byte) (File, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	f := File{}
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Apply edits sm in place, in sorted key order, and appends a note to
+// sm.Warnings for every key that matches no endpoint. An override that is
+// both hidden and featured hides the endpoint, with a warning.
+func Apply(sm *genspec.ServiceModel, f File) {
+	if sm == nil || len(f) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hidden := map[string]bool{}
+	for _, key := range keys {
+		o := f[key]
+		i := find(sm.Endpoints, key)
+		if i < 0 {
+			sm.Warnings = append(sm.Warnings, genspec.NewWarning(genspec.WarnOverrideUnmatched, "override %q matches no endpoint", key))
+			continue
+		}
+		ep := &sm.Endpoints[i]
+		if o.Hidden {
+			if o.Featured {
+				sm.Warnings = append(sm.Warnings, genspec.NewWarning(genspec.WarnOverrideHiddenFeatured, "override %q is both hidden and featured; hiding %s", key, ep.ID))
+			}
+			hidden[ep.ID] = true
+			continue
+		}
+		if o.Summary != nil {
+			ep.Summary = strings.TrimSpace(*o.Summary)
+		}
+		if o.Description != nil {
+			ep.Description = strings.TrimSpace(*o.Description)
+		}
+		if o.Featured {
+			ep.Featured = true
+		}
+	}
+	if len(hidden) == 0 {
+		return
+	}
+	kept := sm.Endpoints[:0]
+	for _, ep := range sm.Endpoints {
+		if !hidden[ep.ID] {
+			kept = append(kept, ep)
+		}
+	}
+	sm.Endpoints = kept
+}
+
+// find returns the index of the endpoint key refers to, trying the
+// "<method> <path>" ID first and the operationId second, or -1.
+func find(endpoints []genspec.EndpointModel, key string) int {
+	key = strings.TrimSpace(key)
+	if method, path, ok := strings.Cut(key, " "); ok {
+		id := strings.ToLower(method) + " " + strings.TrimSpace(path)
+		for i := range endpoints {
+			if endpoints[i].ID == id {
+				return i
+			}
+		}
+	}
+	for i := range endpoints {
+		if endpoints[i].OperationID != "" && endpoints[i].OperationID == key {
+			return i
+		}
+	}
+	return -1
+}
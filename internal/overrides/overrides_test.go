@@ -0,0 +1,116 @@
+package overrides
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func petsModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Title: "Pets",
+		Endpoints: []genspec.EndpointModel{
+			{ID: "get /pets", OperationID: "listPets", Method: genspec.GET, Path: "/pets", Summary: "List pets", Description: "Returns all pets."},
+			{ID: "get /pets/{id}", OperationID: "getPet", Method: genspec.GET, Path: "/pets/{id}", Summary: "Get a pet"},
+			{ID: "delete /pets/{id}", OperationID: "deletePet", Method: genspec.DELETE, Path: "/pets/{id}", Summary: "Delete a pet"},
+		},
+	}
+}
+
+func endpoint(t *testing.T, sm *genspec.ServiceModel, id string) *genspec.EndpointModel {
+	t.Helper()
+	for i := range sm.Endpoints {
+		if sm.Endpoints[i].ID == id {
+			return &sm.Endpoints[i]
+		}
+	}
+	return nil
+}
+
+func TestApply_OverridesByIDAndOperationID(t *testing.T) {
+	t.Parallel()
+	f, err := Parse([]byte(`
+GET /pets/{id}:
+  summary: "  Fetch one pet by its id "
+listPets:
+  description: Lists pets, newest first.
+  featured: true
+`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	sm := petsModel()
+	Apply(sm, f)
+
+	if len(sm.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", sm.Warnings)
+	}
+	byID := endpoint(t, sm, "get /pets/{id}")
+	if byID.Summary != "Fetch one pet by its id" || byID.Featured {
+		t.Errorf("get /pets/{id} = %+v", byID)
+	}
+	byOp := endpoint(t, sm, "get /pets")
+	if byOp.Summary != "List pets" || byOp.Description != "Lists pets, newest first." || !byOp.Featured {
+		t.Errorf("listPets = %+v", byOp)
+	}
+	if len(sm.Endpoints) != 3 {
+		t.Errorf("endpoints = %d, want 3", len(sm.Endpoints))
+	}
+}
+
+func TestApply_UnknownKeysWarn(t *testing.T) {
+	t.Parallel()
+	sm := petsModel()
+	Apply(sm, File{"post /pets": {Hidden: true}, "createPet": {Featured: true}})
+
+	want := []string{`override "createPet" matches no endpoint`, `override "post /pets" matches no endpoint`}
+	if got := genspec.WarningMessages(sm.Warnings); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("warnings = %q, want %q", got, want)
+	}
+	if len(sm.Endpoints) != 3 {
+		t.Errorf("endpoints = %d, want 3", len(sm.Endpoints))
+	}
+}
+
+func TestApply_HiddenWinsOverFeatured(t *testing.T) {
+	t.Parallel()
+	sm := petsModel()
+	Apply(sm, File{
+		"deletePet": {Hidden: true, Featured: true},
+		"getPet":    {Featured: true},
+	})
+
+	if endpoint(t, sm, "delete /pets/{id}") != nil {
+		t.Fatalf("hidden endpoint kept: %+v", sm.Endpoints)
+	}
+	if ep := endpoint(t, sm, "get /pets/{id}"); ep == nil || !ep.Featured {
+		t.Fatalf("featured endpoint = %+v", ep)
+	}
+	var ids []string
+	for _, ep := range sm.Endpoints {
+		ids = append(ids, ep.ID)
+	}
+	if got := strings.Join(ids, ","); got != "get /pets,get /pets/{id}" {
+		t.Errorf("endpoints = %s (order must be kept)", got)
+	}
+	if len(sm.Warnings) != 1 || !strings.Contains(sm.Warnings[0].Message, "both hidden and featured") || sm.Warnings[0].ID != genspec.WarnOverrideHiddenFeatured {
+		t.Errorf("warnings = %q", sm.Warnings)
+	}
+}
+
+func TestLoad_RejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	if err := os.WriteFile(path, []byte("listPets:\n  hiden: true\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "hiden") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+	if f, err := Parse(nil); err != nil || len(f) != 0 {
+		t.Fatalf("empty file: %v, %v", f, err)
+	}
+}
@@ -0,0 +1,120 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flattenAllOf merges the allOf members of every schema in sm.Schemas into
+// the schema itself: their properties join Properties (the schema's own
+// properties, then later members, win on a name clash), their Required
+// lists are unioned, and AllOf is cleared. A member given by $ref is
+// flattened first and followed within sm.Schemas; a reference outside it is
+// dropped. A chain of allOf references leading back to a schema being
+// flattened is an error.
+func flattenAllOf(sm *ServiceModel) error {
+	names := make([]string, 0, len(sm.Schemas))
+	for name := range sm.Schemas {
+		names = append(names, name)
+	}
+	SortNames(names)
+
+	done := map[string]bool{}
+	var stack []string
+	var flattenNamed func(name string) error
+	var flatten func(sc *Schema) error
+	flattenNamed = func(name string) error {
+		if done[name] {
+			return nil
+		}
+		for i, n := range stack {
+			if n == name {
+				return fmt.Errorf("circular allOf: %s", strings.Join(append(stack[i:], name), " -> "))
+			}
+		}
+		sc, ok := sm.Schemas[name]
+		if !ok {
+			return nil
+		}
+		stack = append(stack, name)
+		if err := flatten(&sc); err != nil {
+			return err
+		}
+		stack = stack[:len(stack)-1]
+		sm.Schemas[name] = sc
+		done[name] = true
+		return nil
+	}
+	flatten = func(sc *Schema) error {
+		if len(sc.AllOf) == 0 {
+			return nil
+		}
+		props := map[string]*SchemaOrRef{}
+		var required []string
+		for _, member := range sc.AllOf {
+			var from *Schema
+			switch {
+			case member == nil:
+				continue
+			case member.Ref != nil:
+				name := strings.TrimPrefix(member.Ref.Ref, schemaRefPrefix)
+				if err := flattenNamed(name); err != nil {
+					return err
+				}
+				resolved, ok := sm.Schemas[name]
+				if !ok {
+					continue
+				}
+				from = &resolved
+			case member.Schema != nil:
+				inline := *member.Schema
+				if err := flatten(&inline); err != nil {
+					return err
+				}
+				from = &inline
+			default:
+				continue
+			}
+			for prop, s := range from.Properties {
+				props[prop] = s
+			}
+			required = append(required, from.Required...)
+			if sc.Type == "" {
+				sc.Type = from.Type
+			}
+		}
+		for prop, s := range sc.Properties {
+			props[prop] = s
+		}
+		if len(props) > 0 {
+			sc.Properties = props
+		}
+		sc.Required = unionStrings(append(required, sc.Required...))
+		sc.AllOf = nil
+		return nil
+	}
+
+	for _, name := range names {
+		if err := flattenNamed(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unionStrings returns list without duplicates, in first-seen order; nil
+// when list is empty.
+func unionStrings(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(list))
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,360 @@
+package spec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ModelCache memoizes BuildServiceModel by document content and build
+// options, for callers that build the same model repeatedly in one process
+// (watch iterations, one run per target language). Every call returns its
+// own deep copy, so a caller that edits its model (overrides, slimming,
+// redaction) cannot affect what the next caller gets. Only the
+// modelCacheSize most recently used models are kept, so a long watch session
+// does not hold a model for every edit of the spec. The zero value is not
+// usable; call NewModelCache.
+type ModelCache struct {
+	mu     sync.Mutex
+	models map[string]*ServiceModel
+	recent []string // keys of models, least recently used first
+	builds int
+}
+
+// modelCacheSize bounds a ModelCache: enough for the specs of a merged run
+// with a few option sets, while each watch edit replaces the oldest entry.
+const modelCacheSize = 8
+
+// NewModelCache returns an empty cache.
+func NewModelCache() *ModelCache {
+	return &ModelCache{models: map[string]*ServiceModel{}}
+}
+
+// Build returns a copy of the model BuildServiceModel produces for doc, v2Raw
+// and opts, building it only on the first request for that combination.
+// Errors are not cached.
+func (c *ModelCache) Build(ctx context.Context, doc *openapi3.T, v2Raw []byte, opts ...BuildOption) (*ServiceModel, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("nil document")
+	}
+	key, err := modelCacheKey(doc, v2Raw, opts)
+	if err != nil {
+		// An unhashable document is still buildable, just not cacheable.
+		return BuildServiceModel(ctx, doc, v2Raw, opts...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sm, ok := c.models[key]; ok {
+		c.touch(key)
+		return sm.Clone(), nil
+	}
+	sm, err := BuildServiceModel(ctx, doc, v2Raw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.builds++
+	c.models[key] = sm
+	c.touch(key)
+	if len(c.recent) > modelCacheSize {
+		delete(c.models, c.recent[0])
+		c.recent = c.recent[1:]
+	}
+	return sm.Clone(), nil
+}
+
+// touch marks key as the most recently used.
+func (c *ModelCache) touch(key string) {
+	if i := slices.Index(c.recent, key); i >= 0 {
+		c.recent = slices.Delete(c.recent, i, i+1)
+	}
+	c.recent = append(c.recent, key)
+}
+
+// Builds reports how many times the cache has called BuildServiceModel.
+func (c *ModelCache) Builds() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.builds
+}
+
+// modelCacheKey hashes the document content and the resolved build options.
+// A document converted from Swagger 2.0 is identified by the v2 source it was
+// converted from, which also feeds the model; its converted form does not
+// always marshal.
+func modelCacheKey(doc *openapi3.T, v2Raw []byte, opts []BuildOption) (string, error) {
+	cfg := &buildConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfgJSON, err := json.Marshal(cfg.canonical())
+	if err != nil {
+		return "", err
+	}
+	v2SchemaStorage.mu.RLock()
+	content := v2SchemaStorage.store[doc].rawBytes
+	v2SchemaStorage.mu.RUnlock()
+	if content == nil {
+		if content, err = marshalDocument(doc); err != nil {
+			return "", err
+		}
+	}
+
+	h := sha256.New()
+	for _, part := range [][]byte{content, v2Raw, cfgJSON} {
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// marshalDocument is doc.MarshalJSON, with a panic on a malformed document
+// (a nil schema reference, say) turned into an error.
+func marshalDocument(doc *openapi3.T) (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("marshal document: %v", r)
+		}
+	}()
+	return doc.MarshalJSON()
+}
+
+// canonical returns the options in a form encoding/json serializes
+// deterministically (maps are written with sorted keys).
+func (c *buildConfig) canonical() any {
+	paths := make([]string, len(c.pathRes))
+	for i, re := range c.pathRes {
+		paths[i] = re.String()
+	}
+	extensions := make([][2]any, len(c.dropExtensions))
+	for i, f := range c.dropExtensions {
+		extensions[i] = [2]any{f.key, f.want}
+	}
+	return map[string]any{
+		"includeTags":    c.includeTags,
+		"excludeTags":    c.excludeTags,
+		"methods":        c.methods,
+		"paths":          paths,
+		"includeSchemas": c.includeSchemas,
+		"excludeSchemas": c.excludeSchemas,
+		"dropExtensions": extensions,
+		"keepAllServers": c.keepAllServers,
+		"dropDevServers": c.dropDevServers,
+		"flattenAllOf":   c.flattenAllOf,
+	}
+}
+
+// Clone returns a deep copy of sm: no slice, map, pointer or decoded JSON
+// value is shared with the original.
+func (sm *ServiceModel) Clone() *ServiceModel {
+	if sm == nil {
+		return nil
+	}
+	out := *sm
+	out.Servers = cloneSlice(sm.Servers)
+	out.Tags = cloneSlice(sm.Tags)
+	if sm.TagDescriptions != nil {
+		out.TagDescriptions = make(map[string]string, len(sm.TagDescriptions))
+		for tag, desc := range sm.TagDescriptions {
+			out.TagDescriptions[tag] = desc
+		}
+	}
+	out.Warnings = cloneSlice(sm.Warnings)
+	out.Extensions = cloneExtensions(sm.Extensions)
+	if sm.Endpoints != nil {
+		out.Endpoints = make([]EndpointModel, len(sm.Endpoints))
+		for i := range sm.Endpoints {
+			out.Endpoints[i] = cloneEndpoint(sm.Endpoints[i])
+		}
+	}
+	if sm.Schemas != nil {
+		out.Schemas = make(map[string]Schema, len(sm.Schemas))
+		for name, s := range sm.Schemas {
+			out.Schemas[name] = *cloneSchema(&s)
+		}
+	}
+	if sm.SecuritySchemes != nil {
+		out.SecuritySchemes = make(map[string]SecurityScheme, len(sm.SecuritySchemes))
+		for name, s := range sm.SecuritySchemes {
+			s.Extensions = cloneExtensions(s.Extensions)
+			if s.Flows != nil {
+				flows := make([]OAuthFlow, len(s.Flows))
+				for i, f := range s.Flows {
+					if f.Scopes != nil {
+						scopes := make(map[string]string, len(f.Scopes))
+						for k, v := range f.Scopes {
+							scopes[k] = v
+						}
+						f.Scopes = scopes
+					}
+					flows[i] = f
+				}
+				s.Flows = flows
+			}
+			out.SecuritySchemes[name] = s
+		}
+	}
+	return &out
+}
+
+func cloneEndpoint(ep EndpointModel) EndpointModel {
+	ep.Tags = cloneSlice(ep.Tags)
+	ep.Extensions = cloneExtensions(ep.Extensions)
+	if ep.Parameters != nil {
+		params := make([]ParameterModel, len(ep.Parameters))
+		for i, p := range ep.Parameters {
+			p.Schema = cloneSchemaOrRef(p.Schema)
+			p.Example = cloneValue(p.Example)
+			if p.Explode != nil {
+				explode := *p.Explode
+				p.Explode = &explode
+			}
+			params[i] = p
+		}
+		ep.Parameters = params
+	}
+	if ep.Security != nil {
+		security := make([][]string, len(ep.Security))
+		for i, alt := range ep.Security {
+			security[i] = cloneSlice(alt)
+		}
+		ep.Security = security
+	}
+	if ep.Pagination != nil {
+		hint := *ep.Pagination
+		hint.Params = cloneSlice(hint.Params)
+		ep.Pagination = &hint
+	}
+	if ep.RequestBody != nil {
+		body := *ep.RequestBody
+		body.Content = cloneMedia(body.Content)
+		ep.RequestBody = &body
+	}
+	if ep.Responses != nil {
+		responses := make([]ResponseModel, len(ep.Responses))
+		for i, r := range ep.Responses {
+			r.Content = cloneMedia(r.Content)
+			responses[i] = r
+		}
+		ep.Responses = responses
+	}
+	return ep
+}
+
+func cloneMedia(media []Media) []Media {
+	if media == nil {
+		return nil
+	}
+	out := make([]Media, len(media))
+	for i, m := range media {
+		m.Schema = cloneSchemaOrRef(m.Schema)
+		m.Example = cloneValue(m.Example)
+		out[i] = m
+	}
+	return out
+}
+
+func cloneSchemaOrRef(sr *SchemaOrRef) *SchemaOrRef {
+	if sr == nil {
+		return nil
+	}
+	out := &SchemaOrRef{Schema: cloneSchema(sr.Schema)}
+	if sr.Ref != nil {
+		ref := *sr.Ref
+		out.Ref = &ref
+	}
+	return out
+}
+
+func cloneSchemaOrRefs(list []*SchemaOrRef) []*SchemaOrRef {
+	if list == nil {
+		return nil
+	}
+	out := make([]*SchemaOrRef, len(list))
+	for i, sr := range list {
+		out[i] = cloneSchemaOrRef(sr)
+	}
+	return out
+}
+
+func cloneSchema(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	if s.Properties != nil {
+		out.Properties = make(map[string]*SchemaOrRef, len(s.Properties))
+		for name, p := range s.Properties {
+			out.Properties[name] = cloneSchemaOrRef(p)
+		}
+	}
+	out.Required = cloneSlice(s.Required)
+	out.Items = cloneSchemaOrRef(s.Items)
+	out.AllOf = cloneSchemaOrRefs(s.AllOf)
+	out.AnyOf = cloneSchemaOrRefs(s.AnyOf)
+	out.OneOf = cloneSchemaOrRefs(s.OneOf)
+	if s.Enum != nil {
+		out.Enum = make([]any, len(s.Enum))
+		for i, v := range s.Enum {
+			out.Enum[i] = cloneValue(v)
+		}
+	}
+	out.Example = cloneValue(s.Example)
+	out.Extensions = cloneExtensions(s.Extensions)
+	out.MinItems = copyInt(s.MinItems)
+	out.MaxItems = copyInt(s.MaxItems)
+	out.Minimum = copyFloat(s.Minimum)
+	out.Maximum = copyFloat(s.Maximum)
+	return &out
+}
+
+func cloneExtensions(ext map[string]any) map[string]any {
+	if ext == nil {
+		return nil
+	}
+	out := make(map[string]any, len(ext))
+	for k, v := range ext {
+		out[k] = cloneValue(v)
+	}
+	return out
+}
+
+// cloneValue deep-copies a decoded JSON/YAML value; scalars are returned
+// as they are.
+func cloneValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return cloneExtensions(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = cloneValue(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// cloneSlice copies a slice of values, keeping nil and empty distinct:
+// model.json writes them as null and [].
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+func copyInt(n *int) *int {
+	if n == nil {
+		return nil
+	}
+	v := *n
+	return &v
+}
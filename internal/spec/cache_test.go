@@ -0,0 +1,313 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestModelCache_BuildsOncePerDocumentAndOptions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cache := NewModelCache()
+
+	// Two loads of the same content are equal documents, as in a watch
+	// iteration that re-reads an unchanged file.
+	for _, doc := range []*openapi3.T{loadDoc(t, sampleSpec), loadDoc(t, sampleSpec)} {
+		if _, err := cache.Build(ctx, doc, nil, WithIncludeTags([]string{"animal"})); err != nil {
+			t.Fatalf("build: %v", err)
+		}
+	}
+	if got := cache.Builds(); got != 1 {
+		t.Fatalf("builds = %d, want 1", got)
+	}
+
+	doc := loadDoc(t, sampleSpec)
+	if _, err := cache.Build(ctx, doc, nil, WithIncludeTags([]string{"admin"})); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if _, err := cache.Build(ctx, loadDoc(t, strings.Replace(sampleSpec, "Sample API", "Other API", 1)), nil, WithIncludeTags([]string{"animal"})); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if got := cache.Builds(); got != 3 {
+		t.Fatalf("builds = %d, want 3 (new options, new content)", got)
+	}
+
+	want, err := BuildServiceModel(ctx, doc, nil, WithIncludeTags([]string{"admin"}))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	got, _ := cache.Build(ctx, doc, nil, WithIncludeTags([]string{"admin"}))
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("cached model differs from a fresh build:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestModelCache_CopiesAreIsolated(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cache := NewModelCache()
+	doc := loadDoc(t, sampleSpec)
+
+	first, err := cache.Build(ctx, doc, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	// Edit the first copy the way per-target post-processing might.
+	first.Title = "changed"
+	first.Warnings = append(first.Warnings, Warning{Message: "changed"})
+	first.TagDescriptions["read"] = "changed"
+	for _, ep := range first.Endpoints {
+		ep.Tags[0] = "changed"
+		for _, p := range ep.Parameters {
+			p.Schema.Schema.Type = "changed"
+		}
+		if ep.RequestBody != nil {
+			ep.RequestBody.Content[0].Example.(map[string]any)["name"] = "changed"
+		}
+	}
+	pet := first.Schemas["Pet"]
+	pet.Properties["name"].Schema.Type = "changed"
+	pet.Required[0] = "changed"
+
+	second, err := cache.Build(ctx, doc, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	want, err := BuildServiceModel(ctx, doc, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if !reflect.DeepEqual(second, want) {
+		t.Fatalf("edits to one copy leaked into the next:\n got %+v\nwant %+v", second, want)
+	}
+}
+
+// Empty and nil slices serialize differently in model.json ([] and null),
+// so copies keep them apart.
+func TestModelCache_KeepsEmptySlices(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Untagged, version: "1.0.0" }
+paths:
+  /ping:
+    get:
+      responses:
+        "200": { description: ok }
+`)
+	want, err := BuildServiceModel(ctx, doc, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	cache := NewModelCache()
+	for i := 0; i < 2; i++ {
+		got, err := cache.Build(ctx, doc, nil)
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Fatalf("copy %d serializes differently:\n got %s\nwant %s", i, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestModelCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cache := NewModelCache()
+	build := func(i int) {
+		t.Helper()
+		doc := loadDoc(t, strings.Replace(sampleSpec, "Sample API", fmt.Sprintf("Edit %d", i), 1))
+		if _, err := cache.Build(ctx, doc, nil); err != nil {
+			t.Fatalf("build %d: %v", i, err)
+		}
+	}
+	// One model per edit, as in a watch session.
+	for i := 0; i < modelCacheSize; i++ {
+		build(i)
+	}
+	build(0) // used again, so edit 1 is now the oldest
+	build(modelCacheSize)
+	if got := len(cache.models); got != modelCacheSize {
+		t.Fatalf("cache holds %d models, want %d", got, modelCacheSize)
+	}
+	builds := cache.Builds()
+	build(0)
+	if cache.Builds() != builds {
+		t.Fatalf("edit 0 was used recently and should still be cached")
+	}
+	build(1)
+	if cache.Builds() != builds+1 {
+		t.Fatalf("edit 1 was the least recently used and should have been evicted")
+	}
+}
+
+// fill sets every field reachable from v to a non-zero value, creating
+// pointers, slices and maps of one element down to depth levels, so that
+// TestClone_DeepCopiesEveryField covers fields added to the model later.
+func fill(v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if depth > 0 {
+			v.Set(reflect.New(v.Type().Elem()))
+			fill(v.Elem(), depth-1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				fill(v.Field(i), depth)
+			}
+		}
+	case reflect.Slice:
+		if depth > 0 {
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			fill(v.Index(0), depth-1)
+		}
+	case reflect.Map:
+		if depth > 0 {
+			v.Set(reflect.MakeMap(v.Type()))
+			key := reflect.New(v.Type().Key()).Elem()
+			fill(key, depth-1)
+			elem := reflect.New(v.Type().Elem()).Elem()
+			fill(elem, depth-1)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Interface:
+		// as decoded from JSON or YAML
+		v.Set(reflect.ValueOf(map[string]any{"k": []any{"v"}}))
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Float64:
+		v.SetFloat(1)
+	default:
+		panic("fill: unhandled kind " + v.Kind().String() + " of " + v.Type().String())
+	}
+}
+
+// assertDisjoint fails when a and b share a pointer, slice backing array or
+// map anywhere below path.
+func assertDisjoint(t *testing.T, a, b reflect.Value, path string) {
+	t.Helper()
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s: pointer is shared with the original", path)
+			return
+		}
+		assertDisjoint(t, a.Elem(), b.Elem(), path)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			assertDisjoint(t, a.Field(i), b.Field(i), path+"."+a.Type().Field(i).Name)
+		}
+	case reflect.Slice:
+		if a.Len() > 0 && b.Len() > 0 && a.Pointer() == b.Pointer() {
+			t.Errorf("%s: slice is shared with the original", path)
+			return
+		}
+		for i := 0; i < a.Len() && i < b.Len(); i++ {
+			assertDisjoint(t, a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		if a.IsNil() || b.IsNil() {
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s: map is shared with the original", path)
+			return
+		}
+		for _, k := range a.MapKeys() {
+			if bv := b.MapIndex(k); bv.IsValid() {
+				assertDisjoint(t, a.MapIndex(k), bv, fmt.Sprintf("%s[%v]", path, k))
+			}
+		}
+	case reflect.Interface:
+		if !a.IsNil() && !b.IsNil() {
+			assertDisjoint(t, a.Elem(), b.Elem(), path)
+		}
+	}
+}
+
+func TestClone_DeepCopiesEveryField(t *testing.T) {
+	t.Parallel()
+	sm := &ServiceModel{}
+	fill(reflect.ValueOf(sm).Elem(), 6)
+	clone := sm.Clone()
+	if !reflect.DeepEqual(clone, sm) {
+		t.Fatalf("clone differs from the original:\n got %+v\nwant %+v", clone, sm)
+	}
+	assertDisjoint(t, reflect.ValueOf(sm).Elem(), reflect.ValueOf(clone).Elem(), "ServiceModel")
+}
+
+func TestModelCache_ErrorsAreNotCached(t *testing.T) {
+	t.Parallel()
+	cache := NewModelCache()
+	if _, err := cache.Build(context.Background(), nil, nil); err == nil {
+		t.Fatalf("expected an error for a nil document")
+	}
+	if got := cache.Builds(); got != 0 {
+		t.Fatalf("builds = %d, want 0", got)
+	}
+}
+
+// BenchmarkBuildServiceModel_PerTarget builds the model once per target, as
+// a multi-language run did; BenchmarkModelCache_PerTarget builds it once and
+// copies it for each target.
+func BenchmarkBuildServiceModel_PerTarget(b *testing.B) {
+	doc := benchmarkDoc(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for target := 0; target < 3; target++ {
+			if _, err := BuildServiceModel(ctx, doc, nil); err != nil {
+				b.Fatalf("build: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkModelCache_PerTarget(b *testing.B) {
+	doc := benchmarkDoc(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache := NewModelCache()
+		for target := 0; target < 3; target++ {
+			if _, err := cache.Build(ctx, doc, nil); err != nil {
+				b.Fatalf("build: %v", err)
+			}
+		}
+		if cache.Builds() != 1 {
+			b.Fatalf("builds = %d, want 1", cache.Builds())
+		}
+	}
+}
+
+func benchmarkDoc(b *testing.B) *openapi3.T {
+	b.Helper()
+	p := filepath.Join(b.TempDir(), "v2.yaml")
+	if err := os.WriteFile(p, largeV2Spec(300), 0o644); err != nil {
+		b.Fatalf("write: %v", err)
+	}
+	doc, err := Load(context.Background(), p)
+	if err != nil {
+		b.Fatalf("load: %v", err)
+	}
+	return doc
+}
@@ -0,0 +1,43 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Constraints lists the array and numeric constraints of s in a compact,
+// JSON Schema-like notation, e.g. "minItems=2", "uniqueItems", "minimum>0".
+// It returns nil when s has none.
+func (s *Schema) Constraints() []string {
+	if s == nil {
+		return nil
+	}
+	var out []string
+	if s.MinItems != nil {
+		out = append(out, fmt.Sprintf("minItems=%d", *s.MinItems))
+	}
+	if s.MaxItems != nil {
+		out = append(out, fmt.Sprintf("maxItems=%d", *s.MaxItems))
+	}
+	if s.UniqueItems {
+		out = append(out, "uniqueItems")
+	}
+	if s.Minimum != nil {
+		out = append(out, "minimum"+bound(">=", ">", s.ExclusiveMinimum)+formatNumber(*s.Minimum))
+	}
+	if s.Maximum != nil {
+		out = append(out, "maximum"+bound("<=", "<", s.ExclusiveMaximum)+formatNumber(*s.Maximum))
+	}
+	return out
+}
+
+func bound(inclusive, exclusive string, isExclusive bool) string {
+	if isExclusive {
+		return exclusive
+	}
+	return inclusive
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
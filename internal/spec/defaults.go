@@ -0,0 +1,67 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultVersion is the version FillDefaults assigns to a model without one.
+const DefaultVersion = "0.0.0"
+
+// FillDefaults returns a shallow copy of sm whose blank Title, Version and
+// Description are replaced, so emitter templates never interpolate an empty
+// required field (a "# " README header, an empty npm description). Title
+// falls back to toolName. A nil Schemas map becomes an empty one, so every
+// model.json writes "Schemas": {} rather than null. The returned notes
+// describe each substitution, for verbose output. sm itself is not modified,
+// so spec hashes computed from it stay stable.
+func FillDefaults(sm *ServiceModel, toolName string) (*ServiceModel, []string) {
+	out := *sm
+	if out.Schemas == nil {
+		out.Schemas = map[string]Schema{}
+	}
+	var notes []string
+	if strings.TrimSpace(out.Title) == "" {
+		out.Title = toolName
+		notes = append(notes, fmt.Sprintf("spec has no title; using %q", out.Title))
+	}
+	if strings.TrimSpace(out.Version) == "" {
+		out.Version = DefaultVersion
+		notes = append(notes, fmt.Sprintf("spec has no version; using %q", out.Version))
+	}
+	if strings.TrimSpace(out.Description) == "" {
+		out.Description = fmt.Sprintf("MCP tool for the %s API.", strings.TrimSpace(out.Title))
+		notes = append(notes, fmt.Sprintf("spec has no description; using %q", out.Description))
+	}
+	return &out, notes
+}
+
+// DefaultProjectVersion is the version of a generated package when none is
+// given and the spec's version is not a semantic version.
+const DefaultProjectVersion = "0.1.0"
+
+// semverRe matches a SemVer 2.0.0 version: MAJOR.MINOR.PATCH with optional
+// pre-release and build parts, no leading zeros and no "v" prefix.
+var semverRe = regexp.MustCompile(`^(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+	`(-(0|[1-9][0-9]*|[0-9]*[A-Za-z-][0-9A-Za-z-]*)(\.(0|[1-9][0-9]*|[0-9]*[A-Za-z-][0-9A-Za-z-]*))*)?` +
+	`(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// IsSemver reports whether v is a semantic version such as 1.2.3 or
+// 2.0.0-rc.1.
+func IsSemver(v string) bool {
+	return semverRe.MatchString(v)
+}
+
+// ProjectVersion returns the version of a generated package: explicit when
+// set (callers validate it with IsSemver), else specVersion (the spec's
+// info.version) when it is a semantic version, else DefaultProjectVersion.
+func ProjectVersion(explicit, specVersion string) string {
+	if v := strings.TrimSpace(explicit); v != "" {
+		return v
+	}
+	if v := strings.TrimSpace(specVersion); IsSemver(v) {
+		return v
+	}
+	return DefaultProjectVersion
+}
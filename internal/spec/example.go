@@ -0,0 +1,100 @@
+package spec
+
+import "strings"
+
+// maxExampleDepth bounds example synthesis for recursive schemas.
+const maxExampleDepth = 6
+
+// JSONRequestExample returns an example payload for the first JSON media type
+// of rb: the media example when present, otherwise one synthesized from its
+// schema. ok is false when rb has no JSON content.
+func (sm *ServiceModel) JSONRequestExample(rb *RequestBodyModel) (example any, ok bool) {
+	if rb == nil {
+		return nil, false
+	}
+	for i := range rb.Content {
+		media := &rb.Content[i]
+		mime := strings.ToLower(media.Mime)
+		if mime != "application/json" && !strings.HasSuffix(mime, "+json") {
+			continue
+		}
+		if media.Example != nil {
+			return media.Example, true
+		}
+		return sm.SynthesizeExample(media.Schema), true
+	}
+	return nil, false
+}
+
+// SynthesizeExample builds a placeholder value shaped like sor, resolving
+// component references against sm.Schemas. Schema examples and the first
+// enum value are preferred over type-based placeholders.
+func (sm *ServiceModel) SynthesizeExample(sor *SchemaOrRef) any {
+	return sm.synthesizeExample(sor, 0)
+}
+
+func (sm *ServiceModel) synthesizeExample(sor *SchemaOrRef, depth int) any {
+	if sor == nil || depth > maxExampleDepth {
+		return nil
+	}
+	sc := sor.Schema
+	if sc == nil && sor.Ref != nil {
+		name := sor.Ref.Ref[strings.LastIndex(sor.Ref.Ref, "/")+1:]
+		if resolved, ok := sm.Schemas[name]; ok {
+			sc = &resolved
+		}
+	}
+	if sc == nil {
+		return nil
+	}
+	if sc.Example != nil {
+		return sc.Example
+	}
+	if len(sc.Enum) > 0 {
+		return sc.Enum[0]
+	}
+	if len(sc.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, part := range sc.AllOf {
+			if obj, ok := sm.synthesizeExample(part, depth+1).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, alts := range [][]*SchemaOrRef{sc.OneOf, sc.AnyOf} {
+		if len(alts) > 0 {
+			return sm.synthesizeExample(alts[0], depth+1)
+		}
+	}
+	switch sc.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		item := sm.synthesizeExample(sc.Items, depth+1)
+		if item == nil {
+			return []any{}
+		}
+		// Emit enough items to satisfy minItems.
+		n := 1
+		if sc.MinItems != nil && *sc.MinItems > n {
+			n = *sc.MinItems
+		}
+		out := make([]any, n)
+		for i := range out {
+			out[i] = item
+		}
+		return out
+	}
+	obj := map[string]any{}
+	for name, prop := range sc.Properties {
+		obj[name] = sm.synthesizeExample(prop, depth+1)
+	}
+	return obj
+}
@@ -1,6 +1,10 @@
 package spec
 
 import (
+    "bytes"
+    "compress/flate"
+    "compress/gzip"
+    "compress/zlib"
     "context"
     "errors"
     "fmt"
@@ -31,14 +35,24 @@ const (
 )
 
 // SpecError is a structured error with optional location and JSON Pointer.
+// Message and JSONPointer describe the first problem; Errors lists every
+// member when the underlying error is an openapi3.MultiError.
 type SpecError struct {
     Code        ErrorCode
     Message     string
     Location    string // file path or URL
     JSONPointer string // e.g. "#/paths/~1pets/get"
+    Errors      []SpecErrorDetail
     Cause       error
 }
 
+// SpecErrorDetail describes a single validation or parse problem.
+type SpecErrorDetail struct {
+    Code        ErrorCode
+    Message     string
+    JSONPointer string
+}
+
 func (e *SpecError) Error() string { return e.Message }
 func (e *SpecError) Unwrap() error { return e.Cause }
 
@@ -54,6 +68,59 @@ type Settings struct {
     // Default false, but automatically allowed when the root input is a local file
     // to enable typical multi-file specs.
     AllowFileRefs bool
+    // WarningWriter, when set, also receives each warning as a "[WARN] ..."
+    // line, matching the loader's historical stdout output.
+    WarningWriter io.Writer
+    // MaxSpecBytes caps the size of the root document read from a file or URL
+    // (after decompression). Zero or negative disables the limit.
+    MaxSpecBytes int64
+}
+
+// DefaultMaxSpecBytes is the default Settings.MaxSpecBytes.
+const DefaultMaxSpecBytes = 32 << 20
+
+// ErrSpecTooLarge is wrapped by the InputError returned when a spec exceeds
+// Settings.MaxSpecBytes.
+var ErrSpecTooLarge = errors.New("spec exceeds the maximum size")
+
+// WarningCode classifies non-fatal loader problems.
+type WarningCode string
+
+const (
+    RefResolutionWarning WarningCode = "RefResolution"
+    ValidationWarning    WarningCode = "Validation"
+)
+
+// Warning is a non-fatal problem found while loading a spec or building and
+// emitting its model. ID and Category come from the warning registry (see
+// NewWarning); Code is only set on loader warnings.
+type Warning struct {
+    ID       WarningID
+    Category string
+    Code     WarningCode
+    Message  string
+    Location string // file path or URL
+    Pointer  string // JSON pointer into the document, e.g. "#/paths/~1pets/get"; empty when unknown
+}
+
+// LoadResult is the document returned by LoadDetailed along with any warnings.
+type LoadResult struct {
+    Doc      *openapi3.T
+    Warnings []Warning
+    // Files lists the absolute paths of the local files read: the root
+    // document first, then the files its external $refs pulled in.
+    Files []string
+}
+
+// addFile records a local file read, once.
+func (r *LoadResult) addFile(path string) {
+    path = filepath.Clean(path)
+    for _, f := range r.Files {
+        if f == path {
+            return
+        }
+    }
+    r.Files = append(r.Files, path)
 }
 
 // DefaultSettings returns recommended defaults.
@@ -63,6 +130,7 @@ func DefaultSettings() Settings {
         MaxRetries:  3,
         BackoffBase: 200 * time.Millisecond,
         AllowFileRefs: false,
+        MaxSpecBytes:  DefaultMaxSpecBytes,
     }
 }
 
@@ -73,14 +141,27 @@ func WithHTTPTimeout(d time.Duration) Option    { return func(s *Settings) { s.H
 func WithMaxRetries(n int) Option              { return func(s *Settings) { s.MaxRetries = n } }
 func WithBackoffBase(d time.Duration) Option   { return func(s *Settings) { s.BackoffBase = d } }
 func WithAllowFileRefs(allow bool) Option      { return func(s *Settings) { s.AllowFileRefs = allow } }
+func WithWarningWriter(w io.Writer) Option     { return func(s *Settings) { s.WarningWriter = w } }
+func WithMaxSpecBytes(n int64) Option          { return func(s *Settings) { s.MaxSpecBytes = n } }
 
 // Load reads, validates, and returns an OpenAPI v3 document. If the input
 // is Swagger v2.0, it converts it to v3 via kin-openapi openapi2conv.
 //
 // input may be a filesystem path or an http/https URL. file:// URLs are blocked
 // by default (use WithAllowFileRefs(true) when loading from local files and you
-// want to permit file-based external refs).
+// want to permit file-based external refs). Warnings are dropped; use
+// LoadDetailed to receive them.
 func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error) {
+    res, err := LoadDetailed(ctx, input, opts...)
+    if err != nil {
+        return nil, err
+    }
+    return res.Doc, nil
+}
+
+// LoadDetailed behaves like Load but also returns the non-fatal warnings
+// collected while loading, such as ignored validation errors.
+func LoadDetailed(ctx context.Context, input string, opts ...Option) (*LoadResult, error) {
     if strings.TrimSpace(input) == "" {
         return nil, &SpecError{Code: InputError, Message: "spec: input is empty"}
     }
@@ -89,6 +170,14 @@ func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error
     for _, opt := range opts {
         opt(&settings)
     }
+    res := &LoadResult{}
+    warn := func(w Warning, code WarningCode) {
+        w.Code = code
+        res.Warnings = append(res.Warnings, w)
+        if settings.WarningWriter != nil {
+            fmt.Fprintf(settings.WarningWriter, "[WARN] %s\n", w.Message)
+        }
+    }
 
     // Classify input as URL or file path.
     u, uerr := url.Parse(input)
@@ -105,11 +194,14 @@ func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error
 
         // Fetch head bytes to detect version reliably.
         raw, fetchErr := fetchWithRetry(ctx, input, settings)
+        if errors.Is(fetchErr, ErrSpecTooLarge) {
+            return nil, &SpecError{Code: InputError, Message: fmt.Sprintf("fetch %s: %v", input, fetchErr), Location: input, Cause: fetchErr}
+        }
         if fetchErr != nil {
             return nil, &SpecError{Code: NetworkError, Message: fmt.Sprintf("fetch %s: %v", input, fetchErr), Location: input, Cause: fetchErr}
         }
 
-        version, derr := detectSpecVersion(raw)
+        version, root, derr := detectSpecVersion(raw)
         if derr != nil {
             return nil, &SpecError{Code: ParseError, Message: derr.Error(), Location: input, Cause: derr}
         }
@@ -117,8 +209,10 @@ func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error
         switch version {
         case 3:
             // Use loader with proper base URL support and external refs policy.
-            loader := newLoader(settings, false /*rootIsFile*/)
-            doc, err := loader.LoadFromURI(u)
+            // Parse the bytes already fetched (and decoded) rather than
+            // fetching the root again.
+            loader := newLoader(settings, false /*rootIsFile*/, res.addFile)
+            doc, err := loader.LoadFromDataWithPath(raw, u)
             if err != nil {
                 return nil, mapValidateOrParseErr(err, input)
             }
@@ -127,32 +221,30 @@ func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error
                     return nil, mapValidateOrParseErr(err, input)
                 }
                 // proceed in permissive mode
+                warn(NewWarning(WarnUnresolvedRef, "ignoring validation error: %v", err).At(input, extractJSONPointer(err)), ValidationWarning)
             }
-            return doc, nil
+            res.Doc = doc
+            return res, nil
         case 2:
-            // Preprocess incompatible v2 constructs to improve conversion success.
-            if fixed, changed, _ := preprocessV2ForCompatibility(raw); changed {
-                raw = fixed
-            }
-            // Convert v2 bytes to v3, then validate.
-            v3doc, err := convertV2ToV3(raw)
+            // Preprocess incompatible v2 constructs, convert to v3, then validate.
+            v3doc, err := loadV2Document(raw, root)
             if err != nil {
                 return nil, &SpecError{Code: ConversionError, Message: fmt.Sprintf("convert v2→v3: %v", err), Location: input, Cause: err}
             }
-            // Store original v2 schema definitions for enhanced parsing
-            SetV2SchemaDefinitions(v3doc, raw)
             // Resolve all refs immediately after conversion
-            loader := newLoader(settings, false)
+            loader := newLoader(settings, false, res.addFile)
             if err := loader.ResolveRefsIn(v3doc, nil); err != nil {
-                fmt.Printf("[WARN] Failed to resolve refs after conversion: %v\n", err)
+                warn(NewWarning(WarnRefResolutionFailed, "Failed to resolve refs after conversion: %v", err).At(input, extractJSONPointer(err)), RefResolutionWarning)
             }
             if err := v3doc.Validate(ctx); err != nil {
                 if !canProceedDespiteValidation(err) {
                     return nil, mapValidateOrParseErr(err, input)
                 }
                 // proceed in permissive mode
+                warn(NewWarning(WarnUnresolvedRef, "ignoring validation error: %v", err).At(input, extractJSONPointer(err)), ValidationWarning)
             }
-            return v3doc, nil
+            res.Doc = v3doc
+            return res, nil
         default:
             return nil, &SpecError{Code: ParseError, Message: "spec: unknown or unsupported OpenAPI/Swagger version", Location: input}
         }
@@ -165,19 +257,23 @@ func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error
     }
 
     // Read file to detect version.
-    raw, rerr := os.ReadFile(abs)
+    res.addFile(abs)
+    raw, rerr := readFileLimited(abs, settings.MaxSpecBytes)
+    if errors.Is(rerr, ErrSpecTooLarge) {
+        return nil, &SpecError{Code: InputError, Message: fmt.Sprintf("read file %s: %v", abs, rerr), Location: abs, Cause: rerr}
+    }
     if rerr != nil {
         return nil, &SpecError{Code: InputError, Message: fmt.Sprintf("read file %s: %v", abs, rerr), Location: abs, Cause: rerr}
     }
 
-    version, derr := detectSpecVersion(raw)
+    version, root, derr := detectSpecVersion(raw)
     if derr != nil {
         return nil, &SpecError{Code: ParseError, Message: derr.Error(), Location: abs, Cause: derr}
     }
 
     switch version {
     case 3:
-        loader := newLoader(settings, true /*rootIsFile*/)
+        loader := newLoader(settings, true /*rootIsFile*/, res.addFile)
         doc, err := loader.LoadFromFile(abs)
         if err != nil {
             return nil, mapValidateOrParseErr(err, abs)
@@ -187,32 +283,33 @@ func Load(ctx context.Context, input string, opts ...Option) (*openapi3.T, error
                 return nil, mapValidateOrParseErr(err, abs)
             }
             // proceed in permissive mode
+            warn(NewWarning(WarnUnresolvedRef, "ignoring validation error: %v", err).At(abs, extractJSONPointer(err)), ValidationWarning)
         }
-        return doc, nil
+        res.Doc = doc
+        return res, nil
     case 2:
-        // Preprocess incompatible v2 constructs to improve conversion success.
-        if fixed, changed, _ := preprocessV2ForCompatibility(raw); changed {
-            raw = fixed
-        }
-        v3doc, err := convertV2ToV3(raw)
+        // Preprocess incompatible v2 constructs, convert to v3, then validate.
+        v3doc, err := loadV2Document(raw, root)
         if err != nil {
             return nil, &SpecError{Code: ConversionError, Message: fmt.Sprintf("convert v2→v3: %v", err), Location: abs, Cause: err}
         }
-        // Store original v2 schema definitions for enhanced parsing
-        SetV2SchemaDefinitions(v3doc, raw)
         if err := v3doc.Validate(ctx); err != nil {
             if !canProceedDespiteValidation(err) {
                 return nil, mapValidateOrParseErr(err, abs)
             }
             // proceed in permissive mode
+            warn(NewWarning(WarnUnresolvedRef, "ignoring validation error: %v", err).At(abs, extractJSONPointer(err)), ValidationWarning)
         }
-        return v3doc, nil
+        res.Doc = v3doc
+        return res, nil
     default:
         return nil, &SpecError{Code: ParseError, Message: "spec: unknown or unsupported OpenAPI/Swagger version", Location: abs}
     }
 }
 
-func newLoader(settings Settings, rootIsFile bool) *openapi3.Loader {
+// newLoader returns a loader that reads refs under the settings' policy and
+// reports each local file it reads to onFile.
+func newLoader(settings Settings, rootIsFile bool, onFile func(path string)) *openapi3.Loader {
     loader := openapi3.NewLoader()
     loader.IsExternalRefsAllowed = true
     client := &http.Client{Timeout: settings.HTTPTimeout}
@@ -229,7 +326,11 @@ func newLoader(settings Settings, rootIsFile bool) *openapi3.Loader {
             if path == "" {
                 path = uri.Opaque
             }
-            return os.ReadFile(path)
+            data, err := os.ReadFile(path)
+            if err == nil {
+                onFile(path)
+            }
+            return data, err
         case "http", "https":
             req, err := http.NewRequest("GET", uri.String(), nil)
             if err != nil {
@@ -252,33 +353,44 @@ func newLoader(settings Settings, rootIsFile bool) *openapi3.Loader {
 }
 
 // detectSpecVersion returns 3 for OpenAPI v3, 2 for Swagger v2, else error.
-func detectSpecVersion(data []byte) (int, error) {
+// The decoded document is returned too so the v2 path can reuse it instead of
+// parsing the bytes again.
+func detectSpecVersion(data []byte) (int, map[string]any, error) {
     var root map[string]any
     if err := yaml.Unmarshal(data, &root); err != nil {
-        return 0, fmt.Errorf("parse spec: %w", err)
+        return 0, nil, fmt.Errorf("parse spec: %w", err)
     }
     // Check OpenAPI v3 key
     if v, ok := root["openapi"]; ok {
         if s, _ := v.(string); strings.HasPrefix(strings.TrimSpace(s), "3.") {
-            return 3, nil
+            return 3, root, nil
         }
     }
     // Check Swagger v2 key
     if v, ok := root["swagger"]; ok {
         if s, _ := v.(string); strings.HasPrefix(strings.TrimSpace(s), "2.") {
-            return 2, nil
+            return 2, root, nil
         }
     }
-    return 0, fmt.Errorf("spec: missing or unknown version (expected 'openapi: 3.x' or 'swagger: 2.0')")
+    return 0, nil, fmt.Errorf("spec: missing or unknown version (expected 'openapi: 3.x' or 'swagger: 2.0')")
 }
 
-func convertV2ToV3(data []byte) (*openapi3.T, error) {
+// convertV2ToV3 converts Swagger v2 bytes to v3. root is the generic decoding
+// of data; the typed openapi2 decode still reads data, but extension recovery
+// reuses root.
+func convertV2ToV3(data []byte, root map[string]any) (*openapi3.T, error) {
     // For kin-openapi v0.116.0, convert by unmarshalling to v2 then calling ToV3.
     var v2 openapi2.T
     if err := yaml.Unmarshal(data, &v2); err != nil {
         return nil, err
     }
-    return openapi2conv.ToV3(&v2)
+    v3doc, err := openapi2conv.ToV3(&v2)
+    if err != nil {
+        return nil, err
+    }
+    restoreV2Extensions(v3doc, root)
+    applyV2CollectionFormats(v3doc, root)
+    return v3doc, nil
 }
 
 
@@ -298,10 +410,13 @@ func fetchWithRetry(ctx context.Context, rawURL string, settings Settings) ([]by
         if err != nil {
             return nil, err
         }
+        // Asking explicitly turns off net/http's transparent gzip handling,
+        // so readBody decodes the response itself.
+        req.Header.Set("Accept-Encoding", "gzip, deflate")
         resp, err := client.Do(req)
         if err == nil && resp != nil && resp.StatusCode < 300 {
             defer resp.Body.Close()
-            return io.ReadAll(resp.Body)
+            return readBody(resp, settings.MaxSpecBytes)
         }
         if err != nil {
             lastErr = err
@@ -329,15 +444,118 @@ func fetchWithRetry(ctx context.Context, rawURL string, settings Settings) ([]by
     return nil, lastErr
 }
 
+// readBody returns the response body, decompressing gzip and deflate
+// Content-Encodings. Deflate is accepted both zlib-wrapped (per RFC 9110) and
+// raw, since servers send either. limit applies to the decoded bytes, so a
+// small compressed body cannot expand past it.
+func readBody(resp *http.Response, limit int64) ([]byte, error) {
+    switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+    case "", "identity":
+        return ReadLimited(resp.Body, limit)
+    case "gzip", "x-gzip":
+        zr, err := gzip.NewReader(resp.Body)
+        if err != nil {
+            return nil, fmt.Errorf("decode gzip response: %w", err)
+        }
+        defer zr.Close()
+        data, err := ReadLimited(zr, limit)
+        if err != nil && !errors.Is(err, ErrSpecTooLarge) {
+            return nil, fmt.Errorf("decode gzip response: %w", err)
+        }
+        return data, err
+    case "deflate":
+        raw, err := ReadLimited(resp.Body, limit)
+        if err != nil {
+            return nil, err
+        }
+        var data []byte
+        if zr, zerr := zlib.NewReader(bytes.NewReader(raw)); zerr == nil {
+            data, err = ReadLimited(zr, limit)
+            zr.Close()
+        } else {
+            data, err = ReadLimited(flate.NewReader(bytes.NewReader(raw)), limit)
+        }
+        if errors.Is(err, ErrSpecTooLarge) {
+            return nil, err
+        }
+        if err != nil {
+            return nil, fmt.Errorf("decode deflate response: %w", err)
+        }
+        return data, nil
+    default:
+        return nil, fmt.Errorf("unsupported Content-Encoding %q", resp.Header.Get("Content-Encoding"))
+    }
+}
+
+// ReadLimited reads r to the end, failing with ErrSpecTooLarge once more than
+// limit bytes arrive. A limit <= 0 reads everything.
+func ReadLimited(r io.Reader, limit int64) ([]byte, error) {
+    if limit <= 0 {
+        return io.ReadAll(r)
+    }
+    data, err := io.ReadAll(io.LimitReader(r, limit+1))
+    if err != nil {
+        return nil, err
+    }
+    if int64(len(data)) > limit {
+        return nil, fmt.Errorf("%w of %d bytes", ErrSpecTooLarge, limit)
+    }
+    return data, nil
+}
+
+// readFileLimited reads a local spec, checking its size before reading it.
+func readFileLimited(path string, limit int64) ([]byte, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    if fi, err := f.Stat(); err == nil && limit > 0 && fi.Mode().IsRegular() && fi.Size() > limit {
+        return nil, fmt.Errorf("%w of %d bytes (file is %d bytes)", ErrSpecTooLarge, limit, fi.Size())
+    }
+    return ReadLimited(f, limit)
+}
+
 func mapValidateOrParseErr(err error, location string) error {
-    // Try to extract JSON Pointer where available.
-    pointer := extractJSONPointer(err)
-    code := ValidationError
-    // Heuristics: some loader errors are parse errors.
-    if strings.Contains(strings.ToLower(err.Error()), "parse") || strings.Contains(strings.ToLower(err.Error()), "invalid character") {
-        code = ParseError
-    }
-    return &SpecError{Code: code, Message: err.Error(), Location: location, JSONPointer: pointer, Cause: err}
+    var details []SpecErrorDetail
+    var me openapi3.MultiError
+    if errors.As(err, &me) {
+        for _, member := range flattenMultiError(me) {
+            details = append(details, SpecErrorDetail{Code: classifyErr(member), Message: member.Error(), JSONPointer: extractJSONPointer(member)})
+        }
+    }
+    if len(details) == 0 {
+        details = []SpecErrorDetail{{Code: classifyErr(err), Message: err.Error(), JSONPointer: extractJSONPointer(err)}}
+    }
+    // Keep Message concise: the first problem plus a count of the rest.
+    msg := details[0].Message
+    if len(details) > 1 {
+        msg = fmt.Sprintf("%s (and %d more errors)", msg, len(details)-1)
+    }
+    return &SpecError{Code: details[0].Code, Message: msg, Location: location, JSONPointer: details[0].JSONPointer, Errors: details, Cause: err}
+}
+
+// classifyErr distinguishes parse errors from validation errors by message.
+func classifyErr(err error) ErrorCode {
+    lower := strings.ToLower(err.Error())
+    if strings.Contains(lower, "parse") || strings.Contains(lower, "invalid character") {
+        return ParseError
+    }
+    return ValidationError
+}
+
+// flattenMultiError expands nested MultiErrors into their leaf errors.
+func flattenMultiError(me openapi3.MultiError) []error {
+    var out []error
+    for _, member := range me {
+        var nested openapi3.MultiError
+        if errors.As(member, &nested) {
+            out = append(out, flattenMultiError(nested)...)
+            continue
+        }
+        out = append(out, member)
+    }
+    return out
 }
 
 var jsonPtrRe = regexp.MustCompile(`#/[^\s'\"]+`)
@@ -381,3 +599,25 @@ func canProceedDespiteValidation(err error) bool {
     }
     return false
 }
+
+// loadV2Document applies the compatibility rewrites to a decoded Swagger v2
+// document, converts it to v3 and records the v2 definitions and operations.
+// The decoded root is shared by every step, so the raw bytes are only parsed
+// generically once (by detectSpecVersion).
+func loadV2Document(raw []byte, root map[string]any) (*openapi3.T, error) {
+    if preprocessV2Document(root) {
+        if fixed, err := yaml.Marshal(root); err == nil {
+            raw = fixed
+        } else {
+            // Keep root consistent with the unmodified bytes.
+            root = decodeV2Raw(raw)
+        }
+    }
+    v3doc, err := convertV2ToV3(raw, root)
+    if err != nil {
+        return nil, err
+    }
+    // Store original v2 schema definitions for enhanced parsing
+    setV2Document(v3doc, raw, root)
+    return v3doc, nil
+}
@@ -1,13 +1,22 @@
 package spec
 
 import (
+    "bytes"
+    "compress/gzip"
+    "compress/zlib"
     "context"
     "errors"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
     "os"
     "path/filepath"
+    "reflect"
     "strings"
     "testing"
     "time"
+
+    "github.com/getkin/kin-openapi/openapi3"
 )
 
 func TestLoad_BlocksFileURL(t *testing.T) {
@@ -146,3 +155,268 @@ paths: {}
     }
 }
 
+
+func TestLoad_DecompressesEncodedResponses(t *testing.T) {
+    t.Parallel()
+    spec := []byte(strings.TrimSpace(`openapi: 3.0.0
+info: {title: Zipped, version: "1.0.0"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200": {description: ok}
+`) + "\n")
+    var gz, zl bytes.Buffer
+    gw := gzip.NewWriter(&gz)
+    gw.Write(spec)
+    gw.Close()
+    zw := zlib.NewWriter(&zl)
+    zw.Write(spec)
+    zw.Close()
+
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if got := r.Header.Get("Accept-Encoding"); !strings.Contains(got, "gzip") {
+            http.Error(w, "missing Accept-Encoding: "+got, http.StatusBadRequest)
+            return
+        }
+        switch r.URL.Path {
+        case "/gzip.yaml":
+            w.Header().Set("Content-Encoding", "gzip")
+            w.Write(gz.Bytes())
+        case "/deflate.yaml":
+            w.Header().Set("Content-Encoding", "deflate")
+            w.Write(zl.Bytes())
+        default:
+            w.Write(spec)
+        }
+    }))
+    defer srv.Close()
+
+    for _, name := range []string{"gzip.yaml", "deflate.yaml", "plain.yaml"} {
+        doc, err := Load(context.Background(), srv.URL+"/"+name, WithMaxRetries(1))
+        if err != nil {
+            t.Fatalf("%s: %v", name, err)
+        }
+        if doc.Info == nil || doc.Info.Title != "Zipped" || doc.Paths.Find("/pets") == nil {
+            t.Fatalf("%s: unexpected document %+v", name, doc.Info)
+        }
+    }
+}
+
+func TestLoad_RejectsOversizedSpecs(t *testing.T) {
+    t.Parallel()
+    spec := []byte("openapi: 3.0.0\ninfo: {title: Big, version: \"1\"}\npaths: {}\n# " + strings.Repeat("x", 4096) + "\n")
+    var gz bytes.Buffer
+    gw := gzip.NewWriter(&gz)
+    gw.Write(spec)
+    gw.Close()
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path == "/gzip.yaml" {
+            w.Header().Set("Content-Encoding", "gzip")
+            w.Write(gz.Bytes())
+            return
+        }
+        w.Write(spec)
+    }))
+    defer srv.Close()
+    path := filepath.Join(t.TempDir(), "big.yaml")
+    if err := os.WriteFile(path, spec, 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+
+    for _, input := range []string{srv.URL + "/plain.yaml", srv.URL + "/gzip.yaml", path} {
+        _, err := Load(context.Background(), input, WithMaxSpecBytes(1024), WithMaxRetries(1))
+        var se *SpecError
+        if !errors.As(err, &se) || se.Code != InputError || !errors.Is(err, ErrSpecTooLarge) {
+            t.Fatalf("%s: expected InputError wrapping ErrSpecTooLarge, got %v (%T)", input, err, err)
+        }
+        if !strings.Contains(se.Message, "1024 bytes") {
+            t.Fatalf("%s: message should name the limit: %q", input, se.Message)
+        }
+        // Within the limit the same spec loads.
+        if _, err := Load(context.Background(), input, WithMaxSpecBytes(int64(len(spec))), WithMaxRetries(1)); err != nil {
+            t.Fatalf("%s: load at the limit: %v", input, err)
+        }
+    }
+}
+
+func TestMapValidateOrParseErr_MultiError(t *testing.T) {
+    t.Parallel()
+    me := openapi3.MultiError{
+        errors.New("bad response at #/paths/~1pets/get/responses"),
+        fmt.Errorf("wrapped: %w", openapi3.MultiError{
+            errors.New("missing type at #/components/schemas/Pet"),
+            errors.New("failed to parse example"),
+        }),
+    }
+    err := mapValidateOrParseErr(me, "spec.yaml")
+    var se *SpecError
+    if !errors.As(err, &se) {
+        t.Fatalf("expected SpecError, got %T", err)
+    }
+    if len(se.Errors) != 3 {
+        t.Fatalf("expected 3 details, got %+v", se.Errors)
+    }
+    if se.JSONPointer != "#/paths/~1pets/get/responses" || se.Errors[1].JSONPointer != "#/components/schemas/Pet" {
+        t.Fatalf("unexpected pointers: %+v", se.Errors)
+    }
+    if se.Errors[2].Code != ParseError || se.Code != ValidationError {
+        t.Fatalf("unexpected codes: %v / %+v", se.Code, se.Errors)
+    }
+    if want := "bad response at #/paths/~1pets/get/responses (and 2 more errors)"; se.Error() != want {
+        t.Fatalf("want concise summary %q, got %q", want, se.Error())
+    }
+}
+
+func TestMapValidateOrParseErr_SingleError(t *testing.T) {
+    t.Parallel()
+    err := mapValidateOrParseErr(errors.New("invalid info at #/info"), "spec.yaml")
+    var se *SpecError
+    if !errors.As(err, &se) {
+        t.Fatalf("expected SpecError, got %T", err)
+    }
+    if len(se.Errors) != 1 || se.Errors[0].JSONPointer != "#/info" || se.Message != "invalid info at #/info" {
+        t.Fatalf("unexpected error: %+v", se)
+    }
+}
+
+func TestLoadDetailed_CollectsWarnings(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    path := filepath.Join(dir, "v2.yaml")
+    content := strings.TrimSpace(`swagger: "2.0"
+info: {title: Warn, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          schema:
+            $ref: '#/definitions/Missing'
+`) + "\n"
+    if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+
+    var buf strings.Builder
+    res, err := LoadDetailed(context.Background(), path, WithWarningWriter(&buf))
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    if res.Doc == nil || len(res.Warnings) != 1 {
+        t.Fatalf("expected doc and one warning, got %+v", res)
+    }
+    w := res.Warnings[0]
+    if w.Code != ValidationWarning || w.Location != path || !strings.Contains(w.Message, "unresolved ref") {
+        t.Fatalf("unexpected warning: %+v", w)
+    }
+    if w.ID != WarnUnresolvedRef || w.Category != CategoryReference || w.String() != w.Message+" (SW2MCP-W001)" {
+        t.Fatalf("unexpected warning kind: %+v", w)
+    }
+    if want := "[WARN] " + w.Message + "\n"; buf.String() != want {
+        t.Fatalf("warning writer: want %q got %q", want, buf.String())
+    }
+
+    // Load drops the warnings but still returns the document.
+    if doc, err := Load(context.Background(), path); err != nil || doc == nil {
+        t.Fatalf("Load: doc=%v err=%v", doc, err)
+    }
+}
+
+func TestLoad_V2_SharedDecodeKeepsDefinitionsAndExtensions(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    p := filepath.Join(dir, "v2.yaml")
+    if err := os.WriteFile(p, largeV2Spec(3), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    doc, err := Load(context.Background(), p)
+    if err != nil { t.Fatalf("load: %v", err) }
+    if defs := getV2SchemaDefinitions(doc); len(defs) != 3 { t.Fatalf("expected 3 v2 definitions, got %d", len(defs)) }
+    if ops := getV2Operations(doc); len(ops) != 3 { t.Fatalf("expected 3 cached v2 paths, got %d", len(ops)) }
+    op := doc.Paths["/items1"].Get
+    if op == nil || op.Extensions["x-item"] == nil { t.Fatalf("expected x-item extension restored on GET /items1") }
+}
+
+// largeV2Spec returns a Swagger v2 document with n paths and n definitions.
+func largeV2Spec(n int) []byte {
+    var b strings.Builder
+    b.WriteString("swagger: \"2.0\"\ninfo: { title: bench, version: \"1.0.0\" }\nx-doc: true\npaths:\n")
+    for i := 0; i < n; i++ {
+        fmt.Fprintf(&b, "  /items%d:\n    get:\n      operationId: getItem%d\n      x-item: %d\n", i, i, i)
+        fmt.Fprintf(&b, "      parameters:\n      - { in: query, name: q, type: string }\n")
+        fmt.Fprintf(&b, "      responses:\n        '200':\n          description: ok\n          schema: { $ref: '#/definitions/Item%d' }\n", i)
+    }
+    b.WriteString("definitions:\n")
+    for i := 0; i < n; i++ {
+        fmt.Fprintf(&b, "  Item%d:\n    type: object\n    properties:\n      id: { type: integer }\n      name: { type: string }\n", i)
+    }
+    return []byte(b.String())
+}
+
+// BenchmarkV2Extract_Reparse decodes the raw bytes once per extraction step,
+// as the loader used to; BenchmarkV2Extract_Shared decodes them once.
+func BenchmarkV2Extract_Reparse(b *testing.B) {
+    raw := largeV2Spec(300)
+    for i := 0; i < b.N; i++ {
+        doc := &openapi3.T{}
+        restoreV2Extensions(doc, decodeV2Raw(raw))
+        _ = extractV2Schemas(raw)
+        _ = extractV2Operations(raw)
+    }
+}
+
+func BenchmarkV2Extract_Shared(b *testing.B) {
+    raw := largeV2Spec(300)
+    for i := 0; i < b.N; i++ {
+        doc := &openapi3.T{}
+        root := decodeV2Raw(raw)
+        restoreV2Extensions(doc, root)
+        _ = extractV2SchemasFrom(root)
+        _ = extractV2OperationsFrom(root)
+    }
+}
+
+func BenchmarkLoad_V2(b *testing.B) {
+    p := filepath.Join(b.TempDir(), "v2.yaml")
+    if err := os.WriteFile(p, largeV2Spec(300), 0o644); err != nil { b.Fatalf("write: %v", err) }
+    ctx := context.Background()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        if _, err := Load(ctx, p); err != nil { b.Fatalf("load: %v", err) }
+    }
+}
+
+func TestLoadDetailed_ListsLocalFiles(t *testing.T) {
+    t.Parallel()
+    dir := t.TempDir()
+    root := filepath.Join(dir, "api.yaml")
+    schemas := filepath.Join(dir, "defs", "pets.yaml")
+    if err := os.MkdirAll(filepath.Dir(schemas), 0o755); err != nil {
+        t.Fatalf("mkdir: %v", err)
+    }
+    if err := os.WriteFile(root, []byte(`openapi: 3.0.0
+info: { title: Files, version: "1" }
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: './defs/pets.yaml#/Pet' }
+`), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    if err := os.WriteFile(schemas, []byte("Pet:\n  type: object\n"), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+
+    res, err := LoadDetailed(context.Background(), root)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    if want := []string{root, schemas}; !reflect.DeepEqual(res.Files, want) {
+        t.Fatalf("Files = %q, want %q", res.Files, want)
+    }
+}
@@ -0,0 +1,169 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MergeConflict is something two merged models both define: the same
+// endpoint (method and path), or a schema or security scheme of the same name
+// with different contents.
+type MergeConflict struct {
+	Kind   string // "endpoint", "schema" or "security scheme"
+	Name   string // endpoint ID or component name
+	First  int    // 1-based position of the model that defined it first
+	Second int    // 1-based position of the model that defines it again
+}
+
+func (c MergeConflict) String() string {
+	if c.Kind == "endpoint" {
+		return fmt.Sprintf("endpoint %s is defined by specs %d and %d", c.Name, c.First, c.Second)
+	}
+	return fmt.Sprintf("%s %s differs between specs %d and %d", c.Kind, c.Name, c.First, c.Second)
+}
+
+// MergeError lists every conflict MergeServiceModels found, in the order it
+// found them.
+type MergeError struct {
+	Conflicts []MergeConflict
+}
+
+func (e *MergeError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, c.String())
+	}
+	return fmt.Sprintf("merge: %d conflict(s): %s", len(e.Conflicts), strings.Join(parts, "; "))
+}
+
+// methodOrder is the order BuildServiceModel lists the methods of a path in.
+var methodOrder = []HttpMethod{GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS, TRACE}
+
+// MergeServiceModels combines the models of several specs into one API. The
+// endpoints are concatenated and ordered as BuildServiceModel orders them, by
+// path then method; tags are collected from them again. Schemas and security
+// schemes are united by name, and a name defined twice must have the same
+// contents, except that a schema-filter stub gives way to the full schema.
+// Servers are united by URL, keeping the first model's order. Title, version
+// and description come from the first model, and a tag description or
+// extension from the first model that has one. Every endpoint defined twice
+// and every differing schema or scheme is reported in a *MergeError.
+//
+// The result shares endpoint and schema contents with models.
+func MergeServiceModels(models ...*ServiceModel) (*ServiceModel, error) {
+	if len(models) == 0 {
+		return nil, fmt.Errorf("merge: no models")
+	}
+	first := models[0]
+	out := &ServiceModel{
+		Title:       first.Title,
+		Version:     first.Version,
+		Description: first.Description,
+		Schemas:     map[string]Schema{},
+	}
+
+	var conflicts []MergeConflict
+	endpointOf := map[string]int{}
+	schemaOf := map[string]int{}
+	schemeOf := map[string]int{}
+	serverURLs := map[string]bool{}
+	descriptions := map[string]string{}
+	for i, sm := range models {
+		pos := i + 1
+		if sm == nil {
+			continue
+		}
+		for _, s := range sm.Servers {
+			if !serverURLs[s.URL] {
+				serverURLs[s.URL] = true
+				out.Servers = append(out.Servers, s)
+			}
+		}
+		for _, ep := range sm.Endpoints {
+			id := string(ep.Method) + " " + ep.Path
+			if prev, ok := endpointOf[id]; ok {
+				conflicts = append(conflicts, MergeConflict{Kind: "endpoint", Name: id, First: prev, Second: pos})
+				continue
+			}
+			endpointOf[id] = pos
+			out.Endpoints = append(out.Endpoints, ep)
+		}
+		for _, name := range sortedKeys(sm.Schemas) {
+			sc := sm.Schemas[name]
+			prev, ok := schemaOf[name]
+			if !ok {
+				schemaOf[name] = pos
+				out.Schemas[name] = sc
+				continue
+			}
+			have := out.Schemas[name]
+			switch {
+			case sc.Excluded:
+			case have.Excluded:
+				schemaOf[name] = pos
+				out.Schemas[name] = sc
+			case !reflect.DeepEqual(have, sc):
+				conflicts = append(conflicts, MergeConflict{Kind: "schema", Name: name, First: prev, Second: pos})
+			}
+		}
+		for _, name := range sortedKeys(sm.SecuritySchemes) {
+			scheme := sm.SecuritySchemes[name]
+			if prev, ok := schemeOf[name]; ok {
+				if !reflect.DeepEqual(out.SecuritySchemes[name], scheme) {
+					conflicts = append(conflicts, MergeConflict{Kind: "security scheme", Name: name, First: prev, Second: pos})
+				}
+				continue
+			}
+			if out.SecuritySchemes == nil {
+				out.SecuritySchemes = map[string]SecurityScheme{}
+			}
+			schemeOf[name] = pos
+			out.SecuritySchemes[name] = scheme
+		}
+		for k, v := range sm.Extensions {
+			if _, ok := out.Extensions[k]; ok {
+				continue
+			}
+			if out.Extensions == nil {
+				out.Extensions = map[string]any{}
+			}
+			out.Extensions[k] = v
+		}
+		for tag, d := range sm.TagDescriptions {
+			if descriptions[tag] == "" {
+				descriptions[tag] = d
+			}
+		}
+		out.Warnings = append(out.Warnings, sm.Warnings...)
+	}
+	if len(conflicts) > 0 {
+		return nil, &MergeError{Conflicts: conflicts}
+	}
+
+	slices.SortStableFunc(out.Endpoints, func(a, b EndpointModel) int {
+		if c := CompareNames(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return slices.Index(methodOrder, a.Method) - slices.Index(methodOrder, b.Method)
+	})
+	out.Tags = collectSortedTags(out.Endpoints)
+	if len(out.Tags) > 0 {
+		out.TagDescriptions = make(map[string]string, len(out.Tags))
+		for _, t := range out.Tags {
+			out.TagDescriptions[t] = descriptions[t]
+		}
+	}
+	return out, nil
+}
+
+// sortedKeys returns the keys of m in CompareNames order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	SortNames(keys)
+	return keys
+}
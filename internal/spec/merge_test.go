@@ -0,0 +1,171 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func buildModel(t *testing.T, spec string) *ServiceModel {
+	t.Helper()
+	sm, err := BuildServiceModel(context.Background(), loadDoc(t, spec), nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	return sm
+}
+
+const mergePets = `openapi: 3.0.0
+info: { title: Pets, version: "1.0.0" }
+servers:
+  - url: https://api.example.com
+tags:
+  - { name: pets, description: Pet operations }
+paths:
+  /pets:
+    post:
+      tags: [pets]
+      responses: { "200": { description: ok } }
+    get:
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: '#/components/schemas/Pet' }
+components:
+  schemas:
+    Pet:
+      type: object
+      properties: { id: { type: integer } }
+  securitySchemes:
+    key: { type: apiKey, in: header, name: X-Key }
+`
+
+const mergeStores = `openapi: 3.0.0
+info: { title: Stores, version: "2.0.0" }
+servers:
+  - url: https://stores.example.com
+  - url: https://api.example.com
+tags:
+  - { name: pets, description: Described by the first spec too }
+  - { name: Admin, description: Store administration }
+paths:
+  /stores:
+    get:
+      tags: [Admin]
+      responses: { "200": { description: ok } }
+  /pets/{id}:
+    get:
+      tags: [pets]
+      parameters:
+        - { in: path, name: id, required: true, schema: { type: integer } }
+      responses: { "200": { description: ok } }
+  /Accounts:
+    get:
+      responses: { "200": { description: ok } }
+components:
+  schemas:
+    Pet:
+      type: object
+      properties: { id: { type: integer } }
+    Store:
+      type: object
+  securitySchemes:
+    key: { type: apiKey, in: header, name: X-Key }
+`
+
+func TestMergeServiceModels(t *testing.T) {
+	t.Parallel()
+	sm, err := MergeServiceModels(buildModel(t, mergePets), buildModel(t, mergeStores))
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if sm.Title != "Pets" || sm.Version != "1.0.0" {
+		t.Errorf("title/version should come from the first spec: %q %q", sm.Title, sm.Version)
+	}
+	var ids []string
+	for _, ep := range sm.Endpoints {
+		ids = append(ids, ep.ID)
+	}
+	if want := []string{"get /Accounts", "get /pets", "post /pets", "get /pets/{id}", "get /stores"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("endpoints:\n got %q\nwant %q", ids, want)
+	}
+	if want := []string{"Admin", "pets"}; !reflect.DeepEqual(sm.Tags, want) {
+		t.Errorf("tags: got %q, want %q", sm.Tags, want)
+	}
+	if want := map[string]string{"Admin": "Store administration", "pets": "Pet operations"}; !reflect.DeepEqual(sm.TagDescriptions, want) {
+		t.Errorf("tag descriptions: got %v", sm.TagDescriptions)
+	}
+	var urls []string
+	for _, s := range sm.Servers {
+		urls = append(urls, s.URL)
+	}
+	if want := []string{"https://api.example.com", "https://stores.example.com"}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("servers: got %q, want %q", urls, want)
+	}
+	if len(sm.Schemas) != 2 || sm.Schemas["Store"].Name != "Store" {
+		t.Errorf("schemas: got %v", sm.Schemas)
+	}
+	if len(sm.SecuritySchemes) != 1 {
+		t.Errorf("security schemes: got %v", sm.SecuritySchemes)
+	}
+
+	// the order of the specs only decides what the first spec supplies
+	other, err := MergeServiceModels(buildModel(t, mergeStores), buildModel(t, mergePets))
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if !reflect.DeepEqual(other.Endpoints, sm.Endpoints) || !reflect.DeepEqual(other.Schemas, sm.Schemas) {
+		t.Errorf("endpoints and schemas should not depend on the spec order")
+	}
+}
+
+func TestMergeServiceModels_Conflicts(t *testing.T) {
+	t.Parallel()
+	clash := `openapi: 3.0.0
+info: { title: Clash, version: "1.0.0" }
+paths:
+  /pets:
+    get:
+      responses: { "200": { description: ok } }
+    delete:
+      responses: { "204": { description: gone } }
+components:
+  schemas:
+    Pet:
+      type: string
+`
+	_, err := MergeServiceModels(buildModel(t, mergePets), buildModel(t, mergeStores), buildModel(t, clash))
+	var me *MergeError
+	if !errors.As(err, &me) {
+		t.Fatalf("expected a *MergeError, got %v", err)
+	}
+	want := []MergeConflict{
+		{Kind: "endpoint", Name: "get /pets", First: 1, Second: 3},
+		{Kind: "schema", Name: "Pet", First: 1, Second: 3},
+	}
+	if !reflect.DeepEqual(me.Conflicts, want) {
+		t.Fatalf("conflicts:\n got %+v\nwant %+v", me.Conflicts, want)
+	}
+	if got := err.Error(); got != "merge: 2 conflict(s): endpoint get /pets is defined by specs 1 and 3; schema Pet differs between specs 1 and 3" {
+		t.Errorf("message: %s", got)
+	}
+}
+
+func TestMergeServiceModels_ExcludedStub(t *testing.T) {
+	t.Parallel()
+	a := &ServiceModel{Schemas: map[string]Schema{"Pet": {Name: "Pet", Excluded: true}}}
+	b := &ServiceModel{Schemas: map[string]Schema{"Pet": {Name: "Pet", Type: "object"}}}
+	for _, order := range [][]*ServiceModel{{a, b}, {b, a}} {
+		sm, err := MergeServiceModels(order...)
+		if err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+		if got := sm.Schemas["Pet"]; got.Excluded || got.Type != "object" {
+			t.Errorf("the full schema should win over the stub, got %+v", got)
+		}
+	}
+}
@@ -21,17 +21,55 @@ type ServiceModel struct {
     Description string
     Servers     []Server
     Tags        []string
-    Endpoints   []EndpointModel
-    Schemas     map[string]Schema // by name/ref
+    // TagDescriptions maps each of Tags to its description in the document's
+    // root tags array, or "" when the tag is not described there.
+    TagDescriptions map[string]string `json:",omitempty"`
+    Endpoints       []EndpointModel
+    Schemas         map[string]Schema // by name/ref
+    // SecuritySchemes holds components.securitySchemes (securityDefinitions
+    // for Swagger 2.0 input), by name.
+    SecuritySchemes map[string]SecurityScheme `json:",omitempty"`
+    // Extensions holds the document's x-* vendor extensions.
+    Extensions map[string]any `json:",omitempty"`
+    // Warnings collects non-fatal build notes; not serialized into model.json.
+    Warnings []Warning `json:"-"`
 }
 
 type Server struct {
     URL         string
     Description string
+    // Development marks loopback, private-network, .local, and x-internal
+    // servers, which are not meant as the public API base.
+    Development bool `json:",omitempty"`
+}
+
+// SecurityScheme describes how an API authenticates requests. Swagger 2.0
+// "basic" schemes are normalized to Type "http" with Scheme "basic".
+type SecurityScheme struct {
+    Name             string
+    Type             string         // apiKey|http|oauth2|openIdConnect
+    Description      string         `json:",omitempty"`
+    In               string         `json:",omitempty"` // apiKey: header|query|cookie
+    ParamName        string         `json:",omitempty"` // apiKey: header or query parameter name
+    Scheme           string         `json:",omitempty"` // http: basic|bearer|...
+    BearerFormat     string         `json:",omitempty"`
+    Flows            []OAuthFlow    `json:",omitempty"` // oauth2
+    OpenIDConnectURL string         `json:",omitempty"`
+    Extensions       map[string]any `json:",omitempty"` // x-* vendor extensions
+}
+
+// OAuthFlow is one oauth2 flow of a SecurityScheme.
+type OAuthFlow struct {
+    Type             string            // implicit|password|clientCredentials|authorizationCode
+    AuthorizationURL string            `json:",omitempty"`
+    TokenURL         string            `json:",omitempty"`
+    RefreshURL       string            `json:",omitempty"`
+    Scopes           map[string]string `json:",omitempty"`
 }
 
 type EndpointModel struct {
     ID          string // method+path
+    OperationID string `json:",omitempty"`
     Method      HttpMethod
     Path        string
     Summary     string
@@ -40,13 +78,43 @@ type EndpointModel struct {
     Parameters  []ParameterModel
     RequestBody *RequestBodyModel
     Responses   []ResponseModel
+    Deprecated  bool           `json:",omitempty"`
+    // Featured marks endpoints promoted by an overrides file.
+    Featured   bool            `json:",omitempty"`
+    // Pagination is set on GET endpoints whose query parameters follow a
+    // known pagination pattern.
+    Pagination *PaginationHint `json:",omitempty"`
+    Extensions map[string]any  `json:",omitempty"` // x-* vendor extensions
+
+    // Security lists the alternative security requirements of the operation
+    // (the document's when the operation declares none): each names the
+    // SecuritySchemes that must all be applied, sorted. An empty alternative
+    // allows anonymous access; nil means none are required.
+    Security [][]string `json:",omitempty"`
+}
+
+// PaginationHint describes how a list endpoint pages its results.
+type PaginationHint struct {
+    Style  string   // cursor|offset|unknown
+    Params []string // the pagination query parameters, in the endpoint's parameter order
 }
 
 type ParameterModel struct {
-    Name     string
-    In       string // path|query|header|cookie
-    Required bool
-    Schema   *SchemaOrRef
+    Name        string
+    In          string // path|query|header|cookie
+    Required    bool
+    Description string `json:",omitempty"`
+    // Schema is the parameter's schema. When the spec references a component
+    // the model keeps the Ref and also inlines the resolved Schema, so tools
+    // can show its type, format and enum without a lookup.
+    Schema *SchemaOrRef
+    // Example holds the parameter's example value, or the first of its named
+    // examples by key. It may be nil.
+    Example any `json:",omitempty"`
+    // Style and Explode describe how array/object values are serialized
+    // (OpenAPI 3 semantics); Swagger 2.0 collectionFormat is mapped onto them.
+    Style   string `json:",omitempty"`
+    Explode *bool  `json:",omitempty"`
 }
 
 type RequestBodyModel struct {
@@ -65,6 +133,9 @@ type Media struct {
     Schema *SchemaOrRef
     // Example holds a single example value if available. It may be nil.
     Example any
+    // IsBinary marks raw byte payloads (application/octet-stream or a
+    // string/binary schema) that have no JSON representation.
+    IsBinary bool `json:",omitempty"`
 }
 
 type Schema struct {
@@ -80,6 +151,18 @@ type Schema struct {
     Enum        []any
     Format      string
     Example     any
+    Extensions  map[string]any `json:",omitempty"` // x-* vendor extensions
+    // Array and numeric constraints. Exclusive* qualify Minimum/Maximum.
+    MinItems         *int     `json:",omitempty"`
+    MaxItems         *int     `json:",omitempty"`
+    UniqueItems      bool     `json:",omitempty"`
+    Minimum          *float64 `json:",omitempty"`
+    Maximum          *float64 `json:",omitempty"`
+    ExclusiveMinimum bool     `json:",omitempty"`
+    ExclusiveMaximum bool     `json:",omitempty"`
+    // Excluded marks a stub left for a schema removed by a schema name filter
+    // that is still referenced elsewhere in the model.
+    Excluded bool `json:",omitempty"`
 }
 
 type SchemaRef struct{ Ref string }
@@ -88,4 +171,3 @@ type SchemaOrRef struct {
     Schema *Schema
     Ref    *SchemaRef
 }
-
@@ -5,11 +5,11 @@ import (
     "fmt"
     "regexp"
     "sort"
+    "strconv"
     "strings"
     "sync"
 
     "github.com/getkin/kin-openapi/openapi3"
-    "gopkg.in/yaml.v3"
 )
 
 // v2SchemaStorage holds original v2 schema definitions and cached operations for documents that need enhanced parsing
@@ -28,20 +28,31 @@ var v2SchemaStorage = struct {
     }),
 }
 
-// SetV2SchemaDefinitions stores v2 schema definitions and raw bytes for a document
+// SetV2SchemaDefinitions stores v2 schema definitions and raw bytes for a document.
+// If the document has no servers, they are synthesized from the v2 host, schemes
+// and basePath.
 func SetV2SchemaDefinitions(doc *openapi3.T, v2Raw []byte) {
     if doc == nil || v2Raw == nil {
         return
     }
-    
-    definitions := extractV2Schemas(v2Raw)
-    if definitions == nil {
+    setV2Document(doc, v2Raw, decodeV2Raw(v2Raw))
+}
+
+// setV2Document is SetV2SchemaDefinitions for a v2 document that has already
+// been decoded, so the loader does not parse the same bytes again.
+func setV2Document(doc *openapi3.T, v2Raw []byte, root map[string]any) {
+    if doc == nil || root == nil {
         return
     }
-    
+    synthesizeV2Servers(doc, root)
+
+    definitions := extractV2SchemasFrom(root)
     // Pre-parse operations for better performance
-    operations := extractV2Operations(v2Raw)
-    
+    operations := extractV2OperationsFrom(root)
+    if definitions == nil && operations == nil {
+        return
+    }
+
     v2SchemaStorage.mu.Lock()
     defer v2SchemaStorage.mu.Unlock()
     v2SchemaStorage.store[doc] = struct {
@@ -87,10 +98,21 @@ func getV2Operations(doc *openapi3.T) map[string]map[string]any {
 type BuildOption func(*buildConfig)
 
 type buildConfig struct {
-    includeTags map[string]struct{}
-    excludeTags map[string]struct{}
-    methods     map[HttpMethod]struct{}
-    pathRes     []*regexp.Regexp
+    includeTags    map[string]struct{}
+    excludeTags    map[string]struct{}
+    methods        map[HttpMethod]struct{}
+    pathRes        []*regexp.Regexp
+    includeSchemas []string
+    excludeSchemas []string
+    dropExtensions []extensionFilter
+    keepAllServers bool
+    dropDevServers bool
+    flattenAllOf   bool
+}
+
+type extensionFilter struct {
+    key  string
+    want any
 }
 
 // WithIncludeTags keeps only endpoints that have at least one of the given tags.
@@ -166,6 +188,64 @@ func WithPathPatterns(patterns []string) BuildOption {
     }
 }
 
+// WithIncludeSchemaPatterns keeps only schemas whose name matches at least one
+// of the given globs (path.Match syntax).
+func WithIncludeSchemaPatterns(patterns []string) BuildOption {
+    return func(c *buildConfig) {
+        c.includeSchemas = appendPatterns(c.includeSchemas, patterns)
+    }
+}
+
+// WithExcludeSchemaPatterns removes schemas whose name matches any of the given
+// globs (path.Match syntax). Excluded schemas that are still referenced are
+// replaced by a stub marked Excluded.
+func WithExcludeSchemaPatterns(patterns []string) BuildOption {
+    return func(c *buildConfig) {
+        c.excludeSchemas = appendPatterns(c.excludeSchemas, patterns)
+    }
+}
+
+// WithExtensionFilter drops operations whose x-* extension key equals wantValue,
+// e.g. WithExtensionFilter("x-internal", true). Booleans, strings, and numbers
+// are compared by value; a string wantValue such as "true" or "10" also matches
+// the corresponding boolean or numeric extension.
+func WithExtensionFilter(key string, wantValue any) BuildOption {
+    return func(c *buildConfig) {
+        key = strings.TrimSpace(key)
+        if key == "" {
+            return
+        }
+        c.dropExtensions = append(c.dropExtensions, extensionFilter{key: key, want: wantValue})
+    }
+}
+
+// WithKeepAllServers keeps servers in document order. By default public
+// servers are moved ahead of development ones (see Server.Development).
+func WithKeepAllServers(keep bool) BuildOption {
+    return func(c *buildConfig) {
+        c.keepAllServers = keep
+    }
+}
+
+// WithDropDevServers removes development servers from the model, with a
+// warning for each. It has no effect together with WithKeepAllServers.
+func WithDropDevServers(drop bool) BuildOption {
+    return func(c *buildConfig) {
+        c.dropDevServers = drop
+    }
+}
+
+// WithFlattenAllOf merges the allOf members of every schema in
+// ServiceModel.Schemas into one object: their properties join Properties,
+// their Required lists are unioned, and AllOf is cleared. Members given by
+// $ref are followed within Schemas; circular allOf chains make
+// BuildServiceModel fail. By default allOf is kept as in the spec.
+func WithFlattenAllOf(flatten bool) BuildOption {
+    return func(c *buildConfig) {
+        c.flattenAllOf = flatten
+    }
+}
+
 // BuildServiceModel converts an OpenAPI v3 document into the Internal Model (IM).
 // It applies include/exclude tag filtering and optional method/path filters.
 // If the v2Raw parameter is provided, it will be used to extract detailed schema
@@ -185,6 +265,9 @@ func BuildServiceModel(ctx context.Context, doc *openapi3.T, v2Raw []byte, opts
         Title:       safeStr(doc.Info.Title),
         Version:     safeStr(doc.Info.Version),
         Description: safeStr(doc.Info.Description),
+        Extensions:  vendorExtensions(doc.Extensions),
+        // Never nil: a spec without components still serializes "Schemas": {}.
+        Schemas: map[string]Schema{},
     }
 
     // Servers
@@ -193,7 +276,18 @@ func BuildServiceModel(ctx context.Context, doc *openapi3.T, v2Raw []byte, opts
             if s == nil {
                 continue
             }
-            sm.Servers = append(sm.Servers, Server{URL: safeStr(s.URL), Description: safeStr(s.Description)})
+            sm.Servers = append(sm.Servers, Server{
+                URL:         safeStr(s.URL),
+                Description: safeStr(s.Description),
+                Development: isDevelopmentServer(s),
+            })
+        }
+    }
+    if !cfg.keepAllServers {
+        var dropped []string
+        sm.Servers, dropped = arrangeServers(sm.Servers, cfg.dropDevServers)
+        for _, u := range dropped {
+            sm.Warnings = append(sm.Warnings, NewWarning(WarnDevServerDropped, "dropped development server %s", u))
         }
     }
 
@@ -215,7 +309,7 @@ func BuildServiceModel(ctx context.Context, doc *openapi3.T, v2Raw []byte, opts
         for name := range doc.Components.Schemas {
             keys = append(keys, name)
         }
-        sort.Strings(keys)
+        SortNames(keys)
         for _, name := range keys {
             ref := doc.Components.Schemas[name]
             if ref == nil {
@@ -250,178 +344,247 @@ func BuildServiceModel(ctx context.Context, doc *openapi3.T, v2Raw []byte, opts
         }
     }
 
+    sm.SecuritySchemes = securitySchemes(doc)
+
     // Paths and operations
-    if doc.Paths != nil {
-        // Sort paths for determinism
-        pathKeys := make([]string, 0, len(doc.Paths))
-        for p := range doc.Paths {
-            pathKeys = append(pathKeys, p)
+    for p, item := range iteratePaths(doc) {
+        // Merge parameters: path-level first, overridden by op-level.
+        baseParams := make(map[string]*ParameterModel)
+        for _, pref := range item.Parameters {
+            pm := toParameterModel(pref)
+            if pm == nil {
+                continue
+            }
+            baseParams[paramKey(pm.In, pm.Name)] = pm
+        }
+
+        // Supported HTTP methods in a stable order
+        ops := []struct {
+            m HttpMethod
+            o *openapi3.Operation
+        }{
+            {GET, item.Get},
+            {POST, item.Post},
+            {PUT, item.Put},
+            {DELETE, item.Delete},
+            {PATCH, item.Patch},
+            {HEAD, item.Head},
+            {OPTIONS, item.Options},
+            {TRACE, item.Trace},
         }
-        sort.Strings(pathKeys)
 
-        for _, p := range pathKeys {
-            item := doc.Paths[p]
-            if item == nil {
+        for _, pair := range ops {
+            if pair.o == nil {
                 continue
             }
-            // Merge parameters: path-level first, overridden by op-level.
-            baseParams := make(map[string]*ParameterModel)
-            for _, pref := range item.Parameters {
-                pm := toParameterModel(pref)
-                if pm == nil {
+            // Method filter
+            if len(cfg.methods) > 0 {
+                if _, ok := cfg.methods[pair.m]; !ok {
                     continue
                 }
-                baseParams[paramKey(pm.In, pm.Name)] = pm
-            }
-
-            // Supported HTTP methods in a stable order
-            ops := []struct {
-                m HttpMethod
-                o *openapi3.Operation
-            }{
-                {GET, item.Get},
-                {POST, item.Post},
-                {PUT, item.Put},
-                {DELETE, item.Delete},
-                {PATCH, item.Patch},
-                {HEAD, item.Head},
-                {OPTIONS, item.Options},
-                {TRACE, item.Trace},
-            }
-
-            for _, pair := range ops {
-                if pair.o == nil {
-                    continue
-                }
-                // Method filter
-                if len(cfg.methods) > 0 {
-                    if _, ok := cfg.methods[pair.m]; !ok {
-                        continue
+            }
+            // Path pattern filter
+            if len(cfg.pathRes) > 0 {
+                matched := false
+                for _, re := range cfg.pathRes {
+                    if re.MatchString(p) {
+                        matched = true
+                        break
                     }
                 }
-                // Path pattern filter
-                if len(cfg.pathRes) > 0 {
-                    matched := false
-                    for _, re := range cfg.pathRes {
-                        if re.MatchString(p) {
-                            matched = true
-                            break
-                        }
-                    }
-                    if !matched {
-                        continue
-                    }
+                if !matched {
+                    continue
                 }
+            }
+            // Extension predicate filter
+            if dropByExtensions(pair.o.Extensions, cfg) {
+                continue
+            }
 
-                // Merge parameters with precedence to operation-level ones.
-                mergedParams := make(map[string]*ParameterModel, len(baseParams))
-                for k, v := range baseParams {
-                    mergedParams[k] = v
-                }
-                for _, pref := range pair.o.Parameters {
-                    pm := toParameterModel(pref)
-                    if pm == nil {
-                        continue
-                    }
-                    mergedParams[paramKey(pm.In, pm.Name)] = pm
+            // Merge parameters with precedence to operation-level ones.
+            mergedParams := make(map[string]*ParameterModel, len(baseParams))
+            for k, v := range baseParams {
+                mergedParams[k] = v
+            }
+            for _, pref := range pair.o.Parameters {
+                pm := toParameterModel(pref)
+                if pm == nil {
+                    continue
                 }
-                // Materialize and sort parameters
-                params := make([]ParameterModel, 0, len(mergedParams))
-                for _, v := range mergedParams {
-                    params = append(params, *v)
+                mergedParams[paramKey(pm.In, pm.Name)] = pm
+            }
+            // Materialize and sort parameters
+            params := make([]ParameterModel, 0, len(mergedParams))
+            for _, v := range mergedParams {
+                params = append(params, *v)
+            }
+            sort.Slice(params, func(i, j int) bool {
+                if params[i].In == params[j].In {
+                    return CompareNames(params[i].Name, params[j].Name) < 0
                 }
-                sort.Slice(params, func(i, j int) bool {
-                    if params[i].In == params[j].In {
-                        return params[i].Name < params[j].Name
-                    }
-                    return params[i].In < params[j].In
-                })
+                return params[i].In < params[j].In
+            })
 
-                // Request body
-                var rb *RequestBodyModel
-                if pair.o.RequestBody != nil && pair.o.RequestBody.Value != nil {
-                    rb = &RequestBodyModel{Required: pair.o.RequestBody.Value.Required}
-                    
-                    // Try to enhance with cached v2 operations
-                    v2Ops := getV2Operations(doc)
-                    if v2Ops != nil {
-                        rb.Content = toMediaListWithV2Cache(pair.o.RequestBody.Value.Content, v2Ops, p, string(pair.m))
-                    } else {
-                        rb.Content = toMediaList(pair.o.RequestBody.Value.Content)
-                    }
+            // Request body
+            var rb *RequestBodyModel
+            if pair.o.RequestBody != nil && pair.o.RequestBody.Value != nil {
+                rb = &RequestBodyModel{Required: pair.o.RequestBody.Value.Required}
+                
+                // Try to enhance with cached v2 operations
+                v2Ops := getV2Operations(doc)
+                if v2Ops != nil {
+                    rb.Content = toMediaListWithV2Cache(pair.o.RequestBody.Value.Content, v2Ops, p, string(pair.m))
+                } else {
+                    rb.Content = toMediaList(pair.o.RequestBody.Value.Content)
                 }
+            }
 
-                // Responses
-                var responses []ResponseModel
-                if pair.o.Responses != nil {
-                    // In kin-openapi v0.116, Responses is a map[string]*ResponseRef
-                    keys := make([]string, 0, len(pair.o.Responses))
-                    for k := range pair.o.Responses {
-                        keys = append(keys, k)
-                    }
-                    sort.Strings(keys)
-                    for _, code := range keys {
-                        rref := pair.o.Responses[code]
-                        if rref == nil || rref.Value == nil {
-                            continue
-                        }
-                        desc := ""
-                        if rref.Value.Description != nil {
-                            desc = *rref.Value.Description
-                        }
-                        
-                        // Try to enhance with cached v2 operations  
-                        var content []Media
-                        v2Ops := getV2Operations(doc)
-                        if v2Ops != nil {
-                            content = toMediaListWithV2Cache(rref.Value.Content, v2Ops, p, string(pair.m))
-                        } else {
-                            content = toMediaList(rref.Value.Content)
-                        }
-                        
-                        responses = append(responses, ResponseModel{
-                            Status:      code,
-                            Description: desc,
-                            Content:     content,
-                        })
-                    }
+            // Responses
+            var responses []ResponseModel
+            for code, rref := range iterateResponses(pair.o) {
+                if rref == nil || rref.Value == nil {
+                    continue
                 }
-
-                // Tags and filtering
-                tags := make([]string, 0, len(pair.o.Tags))
-                for _, t := range pair.o.Tags {
-                    t = strings.TrimSpace(t)
-                    if t != "" {
-                        tags = append(tags, t)
-                    }
+                desc := ""
+                if rref.Value.Description != nil {
+                    desc = *rref.Value.Description
                 }
-                if !allowByTags(tags, cfg) {
-                    continue
+                
+                // Try to enhance with cached v2 operations  
+                var content []Media
+                v2Ops := getV2Operations(doc)
+                if v2Ops != nil {
+                    content = toMediaListWithV2Cache(rref.Value.Content, v2Ops, p, string(pair.m))
+                    content = withV2ResponseSchema(content, v2Ops, p, string(pair.m), code)
+                } else {
+                    content = toMediaList(rref.Value.Content)
                 }
+                
+                responses = append(responses, ResponseModel{
+                    Status:      code,
+                    Description: desc,
+                    Content:     content,
+                })
+            }
 
-                ep := EndpointModel{
-                    ID:          string(pair.m) + " " + p,
-                    Method:      pair.m,
-                    Path:        p,
-                    Summary:     safeStr(pair.o.Summary),
-                    Description: safeStr(pair.o.Description),
-                    Tags:        tags,
-                    Parameters:  params,
-                    RequestBody: rb,
-                    Responses:   responses,
+            // Tags and filtering
+            tags := make([]string, 0, len(pair.o.Tags))
+            for _, t := range pair.o.Tags {
+                t = strings.TrimSpace(t)
+                if t != "" {
+                    tags = append(tags, t)
                 }
+            }
+            if !allowByTags(tags, cfg) {
+                continue
+            }
 
-                sm.Endpoints = append(sm.Endpoints, ep)
+            ep := EndpointModel{
+                ID:          string(pair.m) + " " + p,
+                OperationID: strings.TrimSpace(pair.o.OperationID),
+                Method:      pair.m,
+                Path:        p,
+                Summary:     safeStr(pair.o.Summary),
+                Description: safeStr(pair.o.Description),
+                Tags:        tags,
+                Parameters:  params,
+                RequestBody: rb,
+                Responses:   responses,
+                Deprecated:  pair.o.Deprecated,
+                Extensions:  vendorExtensions(pair.o.Extensions),
+                Security:    securityRequirements(pair.o.Security, doc.Security),
+            }
+            if pair.m == GET {
+                ep.Pagination = detectPagination(ep.Parameters)
             }
+
+            sm.Warnings = append(sm.Warnings, reservedHeaderWarnings(ep, ep.Parameters)...)
+            sm.Endpoints = append(sm.Endpoints, ep)
         }
     }
 
     // Collect tags present in included endpoints
     sm.Tags = collectSortedTags(sm.Endpoints)
+    sm.TagDescriptions = tagDescriptions(doc.Tags, sm.Tags)
+
+    // Flatten before the name filters, so every allOf member is still there
+    // to merge.
+    if cfg.flattenAllOf {
+        if err := flattenAllOf(sm); err != nil {
+            return nil, err
+        }
+    }
+
+    // Schema name filters run last so references from kept endpoints are known.
+    applySchemaFilters(sm, cfg)
 
     return sm, nil
 }
 
+func dropByExtensions(exts map[string]any, cfg *buildConfig) bool {
+    for _, f := range cfg.dropExtensions {
+        if got, ok := exts[f.key]; ok && extensionValueEquals(got, f.want) {
+            return true
+        }
+    }
+    return false
+}
+
+// extensionValueEquals compares an extension value decoded from the spec with
+// a wanted value, coercing strings to the extension's boolean or numeric type.
+func extensionValueEquals(got, want any) bool {
+    switch g := got.(type) {
+    case bool:
+        switch w := want.(type) {
+        case bool:
+            return g == w
+        case string:
+            b, err := strconv.ParseBool(strings.TrimSpace(w))
+            return err == nil && g == b
+        }
+        return false
+    case string:
+        switch w := want.(type) {
+        case string:
+            return g == w
+        case bool:
+            return g == strconv.FormatBool(w)
+        }
+        if wf, ok := toFloat(want); ok {
+            gf, err := strconv.ParseFloat(strings.TrimSpace(g), 64)
+            return err == nil && gf == wf
+        }
+        return false
+    }
+    if gf, ok := toFloat(got); ok {
+        if w, isStr := want.(string); isStr {
+            wf, err := strconv.ParseFloat(strings.TrimSpace(w), 64)
+            return err == nil && gf == wf
+        }
+        wf, ok := toFloat(want)
+        return ok && gf == wf
+    }
+    return false
+}
+
+func toFloat(v any) (float64, bool) {
+    switch n := v.(type) {
+    case float64:
+        return n, true
+    case float32:
+        return float64(n), true
+    case int:
+        return float64(n), true
+    case int64:
+        return float64(n), true
+    case int32:
+        return float64(n), true
+    case uint64:
+        return float64(n), true
+    }
+    return 0, false
+}
+
 func allowByTags(tags []string, cfg *buildConfig) bool {
     hasInclude := len(cfg.includeTags) > 0
     if hasInclude {
@@ -450,18 +613,107 @@ func paramKey(in, name string) string { return in + ":" + name }
 
 func safeStr(s string) string { return strings.TrimSpace(s) }
 
+func copyFloat(f *float64) *float64 {
+    if f == nil {
+        return nil
+    }
+    v := *f
+    return &v
+}
+
+// v2Number reads a numeric keyword from a raw decoded schema; YAML and JSON
+// decoders yield different Go number types.
+func v2Number(v any) (float64, bool) {
+    switch n := v.(type) {
+    case int:
+        return float64(n), true
+    case int64:
+        return float64(n), true
+    case uint64:
+        return float64(n), true
+    case float64:
+        return n, true
+    }
+    return 0, false
+}
+
+// vendorExtensions copies the x-* keys of an extensions (or raw schema) map.
+// It returns nil when there are none so model.json stays unchanged.
+func vendorExtensions(in map[string]any) map[string]any {
+    var out map[string]any
+    for k, v := range in {
+        if !strings.HasPrefix(strings.ToLower(k), "x-") {
+            continue
+        }
+        if out == nil {
+            out = make(map[string]any)
+        }
+        out[k] = jsonSafe(v)
+    }
+    return out
+}
+
+// jsonSafe converts map[any]any values produced by the YAML decoder into
+// map[string]any so extensions can be marshalled into model.json.
+func jsonSafe(v any) any {
+    switch t := v.(type) {
+    case map[any]any:
+        out := make(map[string]any, len(t))
+        for k, val := range t {
+            out[fmt.Sprint(k)] = jsonSafe(val)
+        }
+        return out
+    case map[string]any:
+        out := make(map[string]any, len(t))
+        for k, val := range t {
+            out[k] = jsonSafe(val)
+        }
+        return out
+    case []any:
+        out := make([]any, len(t))
+        for i, val := range t {
+            out[i] = jsonSafe(val)
+        }
+        return out
+    default:
+        return v
+    }
+}
+
 func toParameterModel(pref *openapi3.ParameterRef) *ParameterModel {
     if pref == nil || pref.Value == nil {
         return nil
     }
     p := pref.Value
     pm := &ParameterModel{
-        Name:     safeStr(p.Name),
-        In:       safeStr(p.In),
-        Required: p.Required,
+        Name:        safeStr(p.Name),
+        In:          safeStr(p.In),
+        Required:    p.Required,
+        Description: safeStr(p.Description),
+        Style:       safeStr(p.Style),
+    }
+    if p.Explode != nil {
+        v := *p.Explode
+        pm.Explode = &v
     }
     if p.Schema != nil {
         pm.Schema = toSchemaOrRef(p.Schema)
+        if pm.Schema != nil && pm.Schema.Ref != nil && p.Schema.Value != nil {
+            pm.Schema.Schema = toSchemaOrRef(&openapi3.SchemaRef{Value: p.Schema.Value}).Schema
+        }
+    }
+    if p.Example != nil {
+        pm.Example = p.Example
+    } else if len(p.Examples) > 0 {
+        // Pick the first example value deterministically by key
+        enames := make([]string, 0, len(p.Examples))
+        for name := range p.Examples {
+            enames = append(enames, name)
+        }
+        SortNames(enames)
+        if ref := p.Examples[enames[0]]; ref != nil && ref.Value != nil {
+            pm.Example = ref.Value.Value
+        }
     }
     return pm
 }
@@ -474,7 +726,7 @@ func toMediaList(content openapi3.Content) []Media {
     for k := range content {
         keys = append(keys, k)
     }
-    sort.Strings(keys)
+    SortNames(keys)
     out := make([]Media, 0, len(keys))
     for _, mime := range keys {
         mt := content[mime]
@@ -490,15 +742,17 @@ func toMediaList(content openapi3.Content) []Media {
             for name := range mt.Examples {
                 enames = append(enames, name)
             }
-            sort.Strings(enames)
+            SortNames(enames)
             if ref := mt.Examples[enames[0]]; ref != nil && ref.Value != nil {
                 ex = ref.Value.Value
             }
         }
+        schema := toSchemaOrRef(mt.Schema)
         out = append(out, Media{
             Mime:   mime,
-            Schema: toSchemaOrRef(mt.Schema),
+            Schema: schema,
             Example: ex,
+            IsBinary: isBinaryMedia(mime, schema),
         })
     }
     if len(out) == 0 {
@@ -507,6 +761,15 @@ func toMediaList(content openapi3.Content) []Media {
     return out
 }
 
+// isBinaryMedia reports whether a media entry carries raw bytes: an
+// application/octet-stream body or an inline string schema with format binary.
+func isBinaryMedia(mime string, schema *SchemaOrRef) bool {
+    if strings.EqualFold(strings.TrimSpace(mime), "application/octet-stream") {
+        return true
+    }
+    return schema != nil && schema.Schema != nil && schema.Schema.Type == "string" && schema.Schema.Format == "binary"
+}
+
 func toSchemaOrRef(ref *openapi3.SchemaRef) *SchemaOrRef {
     if ref == nil {
         return nil
@@ -535,7 +798,21 @@ func toSchemaOrRef(ref *openapi3.SchemaRef) *SchemaOrRef {
         Format:      safeStr(ref.Value.Format),
         Example:     ref.Value.Example,
         Required:    append([]string(nil), ref.Value.Required...),
+        Extensions:  vendorExtensions(ref.Value.Extensions),
+    }
+    if ref.Value.MinItems > 0 {
+        n := int(ref.Value.MinItems)
+        s.MinItems = &n
     }
+    if ref.Value.MaxItems != nil {
+        n := int(*ref.Value.MaxItems)
+        s.MaxItems = &n
+    }
+    s.UniqueItems = ref.Value.UniqueItems
+    s.Minimum = copyFloat(ref.Value.Min)
+    s.Maximum = copyFloat(ref.Value.Max)
+    s.ExclusiveMinimum = ref.Value.ExclusiveMin
+    s.ExclusiveMaximum = ref.Value.ExclusiveMax
     // Enum values
     if len(ref.Value.Enum) > 0 {
         s.Enum = append([]any(nil), ref.Value.Enum...)
@@ -552,7 +829,7 @@ func toSchemaOrRef(ref *openapi3.SchemaRef) *SchemaOrRef {
         for name := range ref.Value.Properties {
             keys = append(keys, name)
         }
-        sort.Strings(keys)
+        SortNames(keys)
         for _, name := range keys {
             s.Properties[name] = toSchemaOrRef(ref.Value.Properties[name])
         }
@@ -592,17 +869,33 @@ func collectSortedTags(endpoints []EndpointModel) []string {
     for t := range set {
         out = append(out, t)
     }
-    sort.Strings(out)
+    SortNames(out)
     return out
 }
 
-// extractV2Schemas parses the Swagger v2.0 raw YAML/JSON to extract schema definitions
-func extractV2Schemas(v2Raw []byte) map[string]any {
-    var doc map[string]any
-    if err := yaml.Unmarshal(v2Raw, &doc); err != nil {
+// tagDescriptions maps each of tags to its description among declared, the
+// document's root tags array; undescribed tags map to "".
+func tagDescriptions(declared openapi3.Tags, tags []string) map[string]string {
+    if len(tags) == 0 {
         return nil
     }
-    
+    out := make(map[string]string, len(tags))
+    for _, t := range tags {
+        out[t] = ""
+        if tag := declared.Get(t); tag != nil {
+            out[t] = strings.TrimSpace(tag.Description)
+        }
+    }
+    return out
+}
+
+// extractV2Schemas parses the Swagger v2.0 raw YAML/JSON to extract schema definitions
+func extractV2Schemas(v2Raw []byte) map[string]any {
+    return extractV2SchemasFrom(decodeV2Raw(v2Raw))
+}
+
+// extractV2SchemasFrom returns the definitions of an already-decoded v2 document
+func extractV2SchemasFrom(doc map[string]any) map[string]any {
     definitions, ok := doc["definitions"].(map[string]any)
     if !ok {
         return nil
@@ -619,7 +912,8 @@ func toSchemaOrRefFromV2(v2Schema any, name string) *SchemaOrRef {
     }
     
     schema := &Schema{
-        Name: name,
+        Name:       name,
+        Extensions: vendorExtensions(schemaMap),
     }
     
     // Extract basic properties
@@ -634,11 +928,35 @@ func toSchemaOrRefFromV2(v2Schema any, name string) *SchemaOrRef {
     if format, ok := schemaMap["format"].(string); ok {
         schema.Format = format
     }
+    // v2's file type has no v3 counterpart; model it as a binary string.
+    if isV2FileSchema(schemaMap) {
+        schema.Type = "string"
+        schema.Format = "binary"
+    }
     
     if example := schemaMap["example"]; example != nil {
         schema.Example = example
     }
     
+    // Array and numeric constraints
+    if n, ok := v2Number(schemaMap["minItems"]); ok && n > 0 {
+        v := int(n)
+        schema.MinItems = &v
+    }
+    if n, ok := v2Number(schemaMap["maxItems"]); ok {
+        v := int(n)
+        schema.MaxItems = &v
+    }
+    schema.UniqueItems, _ = schemaMap["uniqueItems"].(bool)
+    if n, ok := v2Number(schemaMap["minimum"]); ok {
+        schema.Minimum = &n
+    }
+    if n, ok := v2Number(schemaMap["maximum"]); ok {
+        schema.Maximum = &n
+    }
+    schema.ExclusiveMinimum, _ = schemaMap["exclusiveMinimum"].(bool)
+    schema.ExclusiveMaximum, _ = schemaMap["exclusiveMaximum"].(bool)
+    
     // Handle enum
     if enumVal, ok := schemaMap["enum"].([]any); ok {
         schema.Enum = enumVal
@@ -762,14 +1080,44 @@ func toMediaListWithV2Cache(content openapi3.Content, v2Operations map[string]ma
     return result
 }
 
+// withV2ResponseSchema restores inline v2 response schemas that conversion
+// left as a bare object placeholder, using the cached raw operation. This is
+// how file downloads (rewritten to binary strings by rewriteV2FileResponses)
+// keep their schema; IsBinary is re-evaluated against the restored schema.
+func withV2ResponseSchema(content []Media, v2Operations map[string]map[string]any, path, method, status string) []Media {
+    if len(content) == 0 {
+        return content
+    }
+    op, _ := v2Operations[path][strings.ToLower(method)].(map[string]any)
+    responses, _ := op["responses"].(map[string]any)
+    resp, _ := responses[status].(map[string]any)
+    raw, ok := resp["schema"].(map[string]any)
+    if !ok {
+        return content
+    }
+    if _, isRef := raw["$ref"]; isRef {
+        return content
+    }
+    for i := range content {
+        sc := content[i].Schema
+        if sc == nil || sc.Ref != nil || sc.Schema == nil || sc.Schema.Type != "object" || len(sc.Schema.Properties) > 0 {
+            continue
+        }
+        if restored := toSchemaOrRefFromV2(raw, ""); restored != nil {
+            content[i].Schema = restored
+            content[i].IsBinary = isBinaryMedia(content[i].Mime, restored)
+        }
+    }
+    return content
+}
 
 // extractV2Operations parses v2 YAML to extract operation definitions
 func extractV2Operations(v2Raw []byte) map[string]map[string]any {
-    var doc map[string]any
-    if err := yaml.Unmarshal(v2Raw, &doc); err != nil {
-        return nil
-    }
-    
+    return extractV2OperationsFrom(decodeV2Raw(v2Raw))
+}
+
+// extractV2OperationsFrom indexes the operations of an already-decoded v2 document
+func extractV2OperationsFrom(doc map[string]any) map[string]map[string]any {
     paths, ok := doc["paths"].(map[string]any)
     if !ok {
         return nil
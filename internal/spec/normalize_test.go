@@ -2,6 +2,11 @@ package spec
 
 import (
     "context"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "reflect"
+    "sort"
     "strings"
     "testing"
 
@@ -189,6 +194,244 @@ func TestBuildServiceModel_TagFiltering(t *testing.T) {
     }
 }
 
+func TestBuildServiceModel_TagDescriptions(t *testing.T) {
+    t.Parallel()
+    spec := strings.Replace(sampleSpec, "paths:\n", `tags:
+  - name: read
+    description: "  Read-only operations "
+  - name: animal
+    externalDocs:
+      url: https://example.com/animals
+  - name: unused
+    description: Not used by any endpoint
+paths:
+`, 1)
+    doc := loadDoc(t, spec)
+    sm, err := BuildServiceModel(context.Background(), doc, nil, WithExcludeTags([]string{"admin"}))
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    // Every kept tag has a key; declared-but-undescribed and undeclared tags
+    // map to "", and tags of no kept endpoint are left out.
+    want := map[string]string{"animal": "", "read": "Read-only operations", "write": ""}
+    if !reflect.DeepEqual(sm.TagDescriptions, want) {
+        t.Fatalf("TagDescriptions = %#v, want %#v", sm.TagDescriptions, want)
+    }
+    data, err := json.Marshal(sm)
+    if err != nil {
+        t.Fatalf("marshal: %v", err)
+    }
+    if !strings.Contains(string(data), `"TagDescriptions":{"animal":"","read":"Read-only operations","write":""}`) {
+        t.Fatalf("model.json lacks TagDescriptions: %s", data)
+    }
+}
+
+func TestBuildServiceModel_Security(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Secure, version: "1.0.0" }
+security:
+  - apiKey: []
+paths:
+  /default:
+    get:
+      responses: { "200": { description: ok } }
+  /both:
+    get:
+      security:
+        - { bearer: [], apiKey: [] }
+        - oauth: [read]
+      responses: { "200": { description: ok } }
+  /optional:
+    get:
+      security:
+        - bearer: []
+        - {}
+      responses: { "200": { description: ok } }
+  /public:
+    get:
+      security: []
+      responses: { "200": { description: ok } }
+components:
+  securitySchemes:
+    apiKey: { type: apiKey, in: header, name: X-API-Key }
+    bearer: { type: http, scheme: bearer }
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://example.com/token
+          scopes: { read: Read }
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    want := map[string][][]string{
+        "get /default":  {{"apiKey"}},
+        "get /both":     {{"apiKey", "bearer"}, {"oauth"}},
+        "get /optional": {{"bearer"}, {}},
+        "get /public":   nil,
+    }
+    for _, ep := range sm.Endpoints {
+        if !reflect.DeepEqual(ep.Security, want[ep.ID]) {
+            t.Errorf("%s: Security = %#v, want %#v", ep.ID, ep.Security, want[ep.ID])
+        }
+    }
+    clone := sm.Clone()
+    clone.Endpoints[0].Security[0][0] = "changed"
+    if sm.Endpoints[0].Security[0][0] == "changed" {
+        t.Fatalf("Clone shares Security with the original")
+    }
+}
+
+func TestBuildServiceModel_FlattenAllOf(t *testing.T) {
+    t.Parallel()
+    const spec = `openapi: 3.0.0
+info: { title: Pets, version: "1.0.0" }
+paths: {}
+components:
+  schemas:
+    Named:
+      type: object
+      required: [name]
+      properties:
+        name: { type: string }
+    Base:
+      allOf:
+        - $ref: '#/components/schemas/Named'
+        - type: object
+          required: [id]
+          properties:
+            id: { type: integer }
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          required: [name, tag]
+          properties:
+            tag: { type: string }
+`
+    sm, err := BuildServiceModel(context.Background(), loadDoc(t, spec), nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if len(sm.Schemas["Pet"].AllOf) != 2 {
+        t.Fatalf("allOf should be kept by default: %+v", sm.Schemas["Pet"])
+    }
+
+    sm, err = BuildServiceModel(context.Background(), loadDoc(t, spec), nil, WithFlattenAllOf(true))
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    for name, want := range map[string][]string{"Base": {"id", "name"}, "Pet": {"id", "name", "tag"}} {
+        sc := sm.Schemas[name]
+        if sc.AllOf != nil {
+            t.Errorf("%s: allOf not cleared: %+v", name, sc.AllOf)
+        }
+        var props []string
+        for prop := range sc.Properties {
+            props = append(props, prop)
+        }
+        sort.Strings(props)
+        if !reflect.DeepEqual(props, want) {
+            t.Errorf("%s: properties = %v, want %v", name, props, want)
+        }
+        if sc.Type != "object" {
+            t.Errorf("%s: type = %q, want object", name, sc.Type)
+        }
+    }
+    if got := sm.Schemas["Pet"].Required; !reflect.DeepEqual(got, []string{"name", "id", "tag"}) {
+        t.Errorf("Pet required = %v", got)
+    }
+
+    circular := loadDoc(t, `openapi: 3.0.0
+info: { title: Loop, version: "1.0.0" }
+paths: {}
+components:
+  schemas:
+    A:
+      allOf:
+        - $ref: '#/components/schemas/B'
+    B:
+      allOf:
+        - $ref: '#/components/schemas/A'
+`)
+    if _, err := BuildServiceModel(context.Background(), circular, nil, WithFlattenAllOf(true)); err == nil || !strings.Contains(err.Error(), "circular allOf: A -> B -> A") {
+        t.Fatalf("expected a circular allOf error, got %v", err)
+    }
+}
+
+func TestBuildServiceModel_PathParameterEnrichment(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0.0"
+paths:
+  /pets/{petId}:
+    get:
+      summary: Get pet
+      parameters:
+        - in: path
+          name: petId
+          required: true
+          description: ID of the pet to return
+          example: 42
+          schema:
+            type: integer
+            format: int64
+            minimum: 1
+        - in: query
+          name: status
+          description: Filter by status
+          examples:
+            sold: { value: sold }
+            available: { value: available }
+          schema:
+            $ref: '#/components/schemas/Status'
+      responses:
+        "200": { description: ok }
+components:
+  schemas:
+    Status:
+      type: string
+      description: Pet status
+      enum: [available, sold]
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    params := sm.Endpoints[0].Parameters
+    if len(params) != 2 {
+        t.Fatalf("expected 2 parameters, got %d", len(params))
+    }
+    petID := params[0]
+    if petID.Name != "petId" || petID.In != "path" || petID.Description != "ID of the pet to return" {
+        t.Fatalf("path parameter: %+v", petID)
+    }
+    if petID.Example != 42.0 {
+        t.Fatalf("path parameter example = %#v, want 42", petID.Example)
+    }
+    if s := petID.Schema.Schema; s == nil || s.Type != "integer" || s.Format != "int64" || s.Minimum == nil || *s.Minimum != 1 {
+        t.Fatalf("path parameter schema not populated: %+v", petID.Schema)
+    }
+
+    // A referenced schema keeps its Ref and is inlined as well; the example
+    // is the first named one by key.
+    status := params[1]
+    if status.Description != "Filter by status" || status.Example != "available" {
+        t.Fatalf("query parameter: %+v", status)
+    }
+    if status.Schema.Ref == nil || status.Schema.Ref.Ref != "#/components/schemas/Status" {
+        t.Fatalf("query parameter lost its ref: %+v", status.Schema)
+    }
+    if s := status.Schema.Schema; s == nil || s.Type != "string" || !reflect.DeepEqual(s.Enum, []any{"available", "sold"}) {
+        t.Fatalf("referenced schema not inlined: %+v", status.Schema.Schema)
+    }
+}
+
 func TestBuildServiceModel_MethodAndPathFilters(t *testing.T) {
     t.Parallel()
     doc := loadDoc(t, sampleSpec)
@@ -205,3 +448,544 @@ func TestBuildServiceModel_MethodAndPathFilters(t *testing.T) {
     }
 }
 
+
+const schemaFilterSpec = `openapi: 3.0.0
+info:
+  title: Audit API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      summary: List pets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        audit:
+          $ref: '#/components/schemas/AuditBlob'
+    AuditBlob:
+      type: object
+      properties:
+        raw: {type: string}
+    AuditArchive:
+      type: object
+    Owner:
+      type: object
+`
+
+func TestBuildServiceModel_ExcludeSchemaPatterns(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, schemaFilterSpec)
+
+    sm, err := BuildServiceModel(context.Background(), doc, nil, WithExcludeSchemaPatterns([]string{"Audit*"}))
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    // Referenced through Pet: stays as an excluded stub with a warning.
+    stub, ok := sm.Schemas["AuditBlob"]
+    if !ok || !stub.Excluded || len(stub.Properties) != 0 {
+        t.Fatalf("expected AuditBlob stub, got %+v (present=%v)", stub, ok)
+    }
+    if len(sm.Warnings) != 1 || !strings.Contains(sm.Warnings[0].Message, "AuditBlob") || sm.Warnings[0].Pointer != "#/components/schemas/AuditBlob" {
+        t.Fatalf("expected one AuditBlob warning, got %v", sm.Warnings)
+    }
+    // Unreferenced: removed without a warning.
+    if _, ok := sm.Schemas["AuditArchive"]; ok {
+        t.Fatalf("expected AuditArchive to be removed")
+    }
+    if _, ok := sm.Schemas["Pet"]; !ok || sm.Schemas["Pet"].Excluded {
+        t.Fatalf("expected Pet to be kept")
+    }
+}
+
+func TestBuildServiceModel_IncludeSchemaPatterns(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, schemaFilterSpec)
+
+    sm, err := BuildServiceModel(context.Background(), doc, nil, WithIncludeSchemaPatterns([]string{"Pet", "Own?r"}))
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if len(sm.Schemas) != 3 || !sm.Schemas["AuditBlob"].Excluded || sm.Schemas["Owner"].Excluded {
+        t.Fatalf("unexpected schemas: %+v", sm.Schemas)
+    }
+}
+
+func TestBuildServiceModel_VendorExtensions(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Ext, version: "1.0.0" }
+x-owner: platform
+paths:
+  /pets:
+    get:
+      x-internal: true
+      x-rate-limit: { rps: 10 }
+      responses:
+        "200": { description: ok }
+    post:
+      responses:
+        "201": { description: created }
+components:
+  schemas:
+    Pet:
+      type: object
+      x-rate-limit: 5
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if sm.Extensions["x-owner"] != "platform" {
+        t.Errorf("service extensions: got %v", sm.Extensions)
+    }
+    for _, ep := range sm.Endpoints {
+        switch ep.Method {
+        case GET:
+            if ep.Extensions["x-internal"] != true {
+                t.Errorf("get /pets x-internal: got %v", ep.Extensions)
+            }
+            if rl, ok := ep.Extensions["x-rate-limit"].(map[string]any); !ok || rl["rps"] != float64(10) {
+                t.Errorf("get /pets x-rate-limit: got %#v", ep.Extensions["x-rate-limit"])
+            }
+        case POST:
+            if ep.Extensions != nil {
+                t.Errorf("post /pets: expected no extensions, got %v", ep.Extensions)
+            }
+        }
+    }
+    if got := sm.Schemas["Pet"].Extensions["x-rate-limit"]; got != float64(5) {
+        t.Errorf("schema extensions: got %#v", sm.Schemas["Pet"].Extensions)
+    }
+
+    // Extensions serialize with sorted keys; absent ones are omitted.
+    data, err := json.Marshal(sm.Endpoints)
+    if err != nil {
+        t.Fatalf("marshal: %v", err)
+    }
+    if !strings.Contains(string(data), `"Extensions":{"x-internal":true,"x-rate-limit":{"rps":10}}`) {
+        t.Errorf("unexpected endpoint JSON: %s", data)
+    }
+    if strings.Count(string(data), `"Extensions"`) != 1 {
+        t.Errorf("expected Extensions omitted when empty: %s", data)
+    }
+}
+
+func TestBuildServiceModel_VendorExtensionsV2(t *testing.T) {
+    t.Parallel()
+    path := filepath.Join(t.TempDir(), "swagger.yaml")
+    content := `swagger: "2.0"
+info: { title: Ext, version: "1.0.0" }
+paths:
+  /pets:
+    get:
+      x-internal: true
+      responses:
+        "200":
+          description: ok
+          schema: { $ref: "#/definitions/Pet" }
+definitions:
+  Pet:
+    type: object
+    x-rate-limit: 5
+    properties:
+      id: { type: integer }
+`
+    if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    doc, err := Load(context.Background(), path)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if len(sm.Endpoints) != 1 || sm.Endpoints[0].Extensions["x-internal"] != true {
+        t.Errorf("endpoint extensions: got %+v", sm.Endpoints)
+    }
+    if sm.Schemas["Pet"].Extensions["x-rate-limit"] == nil {
+        t.Errorf("schema extensions: got %#v", sm.Schemas["Pet"])
+    }
+}
+
+func TestBuildServiceModel_Constraints(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Limits, version: "1.0.0" }
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: { $ref: "#/components/schemas/Pet" }
+      responses:
+        "201": { description: created }
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        tags:
+          type: array
+          minItems: 2
+          maxItems: 5
+          uniqueItems: true
+          items: { type: string }
+        age:
+          type: integer
+          minimum: 0
+          maximum: 30
+          exclusiveMaximum: true
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    pet := sm.Schemas["Pet"]
+    tags := pet.Properties["tags"].Schema
+    if got := strings.Join(tags.Constraints(), ","); got != "minItems=2,maxItems=5,uniqueItems" {
+        t.Errorf("tags constraints: got %q", got)
+    }
+    age := pet.Properties["age"].Schema
+    if got := strings.Join(age.Constraints(), ","); got != "minimum>=0,maximum<30" {
+        t.Errorf("age constraints: got %q", got)
+    }
+
+    // minItems drives the synthesized example.
+    example, ok := sm.JSONRequestExample(sm.Endpoints[0].RequestBody)
+    if !ok {
+        t.Fatalf("expected a JSON request example")
+    }
+    items, _ := example.(map[string]any)["tags"].([]any)
+    if len(items) != 2 {
+        t.Errorf("expected 2 synthesized tags, got %#v", example)
+    }
+
+    data, err := json.Marshal(tags)
+    if err != nil {
+        t.Fatalf("marshal: %v", err)
+    }
+    if !strings.Contains(string(data), `"MinItems":2,"MaxItems":5,"UniqueItems":true`) {
+        t.Errorf("unexpected schema JSON: %s", data)
+    }
+    if strings.Contains(string(data), "Minimum") {
+        t.Errorf("expected unset constraints omitted: %s", data)
+    }
+}
+
+func TestBuildServiceModel_ConstraintsV2(t *testing.T) {
+    t.Parallel()
+    path := filepath.Join(t.TempDir(), "swagger.yaml")
+    content := `swagger: "2.0"
+info: { title: Limits, version: "1.0.0" }
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          schema: { $ref: "#/definitions/Pet" }
+definitions:
+  Pet:
+    type: object
+    properties:
+      tags:
+        type: array
+        minItems: 2
+        uniqueItems: true
+        items: { type: string }
+      weight:
+        type: number
+        minimum: 0.5
+        exclusiveMinimum: true
+`
+    if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    doc, err := Load(context.Background(), path)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    pet := sm.Schemas["Pet"]
+    if got := strings.Join(pet.Properties["tags"].Schema.Constraints(), ","); got != "minItems=2,uniqueItems" {
+        t.Errorf("tags constraints: got %q", got)
+    }
+    if got := strings.Join(pet.Properties["weight"].Schema.Constraints(), ","); got != "minimum>0.5" {
+        t.Errorf("weight constraints: got %q", got)
+    }
+}
+
+func TestBuildServiceModel_ExtensionFilter(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Ext, version: "1.0.0" }
+paths:
+  /public:
+    get:
+      x-internal: false
+      responses: { "200": { description: ok } }
+  /internal:
+    get:
+      x-internal: true
+      responses: { "200": { description: ok } }
+  /beta:
+    get:
+      x-stage: beta
+      x-rate-limit: 10
+      responses: { "200": { description: ok } }
+  /plain:
+    get:
+      responses: { "200": { description: ok } }
+`)
+    paths := func(opts ...BuildOption) []string {
+        t.Helper()
+        sm, err := BuildServiceModel(context.Background(), doc, nil, opts...)
+        if err != nil {
+            t.Fatalf("build: %v", err)
+        }
+        var out []string
+        for _, ep := range sm.Endpoints {
+            out = append(out, ep.Path)
+        }
+        return out
+    }
+
+    cases := []struct {
+        name string
+        opts []BuildOption
+        want string
+    }{
+        {"bool", []BuildOption{WithExtensionFilter("x-internal", true)}, "/beta,/plain,/public"},
+        {"bool as string", []BuildOption{WithExtensionFilter("x-internal", "true")}, "/beta,/plain,/public"},
+        {"string", []BuildOption{WithExtensionFilter("x-stage", "beta")}, "/internal,/plain,/public"},
+        {"number as string", []BuildOption{WithExtensionFilter("x-rate-limit", "10")}, "/internal,/plain,/public"},
+        {"number", []BuildOption{WithExtensionFilter("x-rate-limit", 10)}, "/internal,/plain,/public"},
+        {"no match", []BuildOption{WithExtensionFilter("x-rate-limit", 5)}, "/beta,/internal,/plain,/public"},
+        {"combined", []BuildOption{WithExtensionFilter("x-internal", true), WithExtensionFilter("x-stage", "beta")}, "/plain,/public"},
+    }
+    for _, tc := range cases {
+        if got := strings.Join(paths(tc.opts...), ","); got != tc.want {
+            t.Errorf("%s: want %s got %s", tc.name, tc.want, got)
+        }
+    }
+}
+
+func TestExtensionValueEquals(t *testing.T) {
+    t.Parallel()
+    cases := []struct {
+        got, want any
+        eq        bool
+    }{
+        {true, true, true},
+        {true, "TRUE", true},
+        {false, "true", false},
+        {"yes", "yes", true},
+        {"true", true, true},
+        {"1.5", 1.5, true},
+        {float64(3), 3, true},
+        {3, "3.0", true},
+        {3, "three", false},
+        {map[string]any{"a": 1}, "a", false},
+    }
+    for _, tc := range cases {
+        if got := extensionValueEquals(tc.got, tc.want); got != tc.eq {
+            t.Errorf("extensionValueEquals(%#v, %#v): want %v got %v", tc.got, tc.want, tc.eq, got)
+        }
+    }
+}
+
+func TestBuildServiceModel_ReservedHeaderWarnings(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Headers, version: "1.0.0" }
+paths:
+  /me:
+    get:
+      parameters:
+        - { in: header, name: authorization, schema: { type: string } }
+        - { in: header, name: Cookie, schema: { type: string } }
+        - { in: header, name: X-Request-Id, schema: { type: string } }
+        - { in: cookie, name: Authorization, schema: { type: string } }
+      responses: { "200": { description: ok } }
+    post:
+      parameters:
+        - { in: header, name: Set-Cookie, schema: { type: string } }
+      responses: { "200": { description: ok } }
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    want := []string{
+        `get /me: header parameter "authorization" should be a security scheme`,
+        `get /me: header parameter "Cookie" should be modeled as "in: cookie" parameters`,
+        `post /me: header parameter "Set-Cookie" is a response header`,
+    }
+    if len(sm.Warnings) != len(want) {
+        t.Fatalf("expected %d warnings, got %v", len(want), sm.Warnings)
+    }
+    for i, w := range want {
+        if !strings.HasPrefix(sm.Warnings[i].Message, w) {
+            t.Errorf("warning %d: want prefix %q, got %q", i, w, sm.Warnings[i].Message)
+        }
+    }
+    ids := []WarningID{WarnAuthorizationHeader, WarnCookieHeader, WarnSetCookieHeader}
+    pointers := []string{"#/paths/~1me/get", "#/paths/~1me/get", "#/paths/~1me/post"}
+    for i, w := range sm.Warnings {
+        if w.ID != ids[i] || w.Category != CategoryParameter || w.Pointer != pointers[i] {
+            t.Errorf("warning %d: got %s %s %s, want %s %s %s", i, w.ID, w.Category, w.Pointer, ids[i], CategoryParameter, pointers[i])
+        }
+    }
+    if got := len(sm.Endpoints[0].Parameters); got != 4 {
+        t.Errorf("reserved headers should stay in the model, got %d parameters", got)
+    }
+}
+
+func TestProjectVersion(t *testing.T) {
+    t.Parallel()
+    cases := []struct{ explicit, spec, want string }{
+        {"2.0.0-rc.1", "1.0.0", "2.0.0-rc.1"},
+        {"", "1.4.2", "1.4.2"},
+        {"", " 3.0.0+build.7 ", "3.0.0+build.7"},
+        {"", "v1.4.2", DefaultProjectVersion},
+        {"", "1.0", DefaultProjectVersion},
+        {"", "2023-01-01", DefaultProjectVersion},
+        {"", "", DefaultProjectVersion},
+    }
+    for _, tc := range cases {
+        if got := ProjectVersion(tc.explicit, tc.spec); got != tc.want {
+            t.Errorf("ProjectVersion(%q, %q) = %q, want %q", tc.explicit, tc.spec, got, tc.want)
+        }
+    }
+    for _, bad := range []string{"1.2", "01.2.3", "1.2.3-", "1.2.3-01", "latest"} {
+        if IsSemver(bad) {
+            t.Errorf("IsSemver(%q) = true", bad)
+        }
+    }
+}
+
+func TestBuildServiceModel_NoComponentsHasEmptySchemas(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Inline, version: "1.0.0" }
+paths:
+  /ping:
+    get:
+      parameters:
+        - { in: query, name: q, schema: { type: string } }
+      responses:
+        "200": { description: ok }
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if sm.Schemas == nil || len(sm.Schemas) != 0 {
+        t.Fatalf("Schemas = %#v, want an empty map", sm.Schemas)
+    }
+
+    // Hand-built models get the same treatment from FillDefaults, without
+    // the original being modified.
+    bare := &ServiceModel{Title: "T", Version: "1", Description: "d"}
+    filled, _ := FillDefaults(bare, "tool")
+    data, _ := json.Marshal(filled)
+    if !strings.Contains(string(data), `"Schemas":{}`) || bare.Schemas != nil {
+        t.Fatalf("FillDefaults: %s (original Schemas %#v)", data, bare.Schemas)
+    }
+}
+
+func TestBuildServiceModel_Pagination(t *testing.T) {
+    t.Parallel()
+    doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Paged, version: "1.0.0" }
+paths:
+  /events:
+    get:
+      parameters:
+        - { in: query, name: cursor, schema: { type: string } }
+        - { in: query, name: limit, schema: { type: integer } }
+        - { in: query, name: type, schema: { type: string } }
+      responses:
+        "200": { description: ok }
+  /messages:
+    get:
+      parameters:
+        - { in: query, name: before, schema: { type: string } }
+      responses:
+        "200": { description: ok }
+  /pets:
+    get:
+      parameters:
+        - { in: query, name: page, schema: { type: integer } }
+        - { in: query, name: pageSize, schema: { type: integer } }
+      responses:
+        "200": { description: ok }
+    post:
+      parameters:
+        - { in: query, name: offset, schema: { type: integer } }
+      responses:
+        "201": { description: created }
+  /orders:
+    get:
+      parameters:
+        - { in: query, name: offset, schema: { type: integer } }
+        - { in: query, name: limit, schema: { type: integer } }
+        - { in: header, name: cursor, schema: { type: string } }
+      responses:
+        "200": { description: ok }
+  /items:
+    get:
+      parameters:
+        - { in: query, name: page_size, schema: { type: integer } }
+      responses:
+        "200": { description: ok }
+  /health:
+    get:
+      parameters:
+        - { in: query, name: verbose, schema: { type: boolean } }
+      responses:
+        "200": { description: ok }
+`)
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    want := map[string]*PaginationHint{
+        "get /events":   {Style: PaginationCursor, Params: []string{"cursor", "limit"}},
+        "get /messages": {Style: PaginationCursor, Params: []string{"before"}},
+        "get /pets":     {Style: PaginationOffset, Params: []string{"page", "pageSize"}},
+        "post /pets":    nil, // only GET endpoints page
+        "get /orders":   {Style: PaginationOffset, Params: []string{"limit", "offset"}},
+        "get /items":    {Style: PaginationUnknown, Params: []string{"page_size"}},
+        "get /health":   nil,
+    }
+    for _, ep := range sm.Endpoints {
+        w, ok := want[ep.ID]
+        if !ok {
+            t.Fatalf("unexpected endpoint %s", ep.ID)
+        }
+        if !reflect.DeepEqual(ep.Pagination, w) {
+            t.Errorf("%s: Pagination = %+v, want %+v", ep.ID, ep.Pagination, w)
+        }
+    }
+
+    data, _ := json.Marshal(sm)
+    if !strings.Contains(string(data), `"Pagination":{"Style":"cursor","Params":["cursor","limit"]}`) {
+        t.Errorf("model.json should carry the pagination hint: %s", data)
+    }
+}
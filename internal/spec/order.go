@@ -0,0 +1,44 @@
+package spec
+
+import (
+	"slices"
+	"strings"
+)
+
+// CompareNames is the order of every name listing in the pipeline: schema
+// names, tags, paths, parameters and the generated projects' files. Names
+// compare case-insensitively first, folding only the ASCII letters, then
+// bytewise as a tiebreak, so "apple" < "Pet" < "pet" < "Zebra". Bytes of
+// non-ASCII characters are compared as they are, which orders them by code
+// point after ASCII; no locale is involved. The generated runtimes implement
+// the same comparator for their listings.
+func CompareNames(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if ca, cb := foldASCII(a[i]), foldASCII(b[i]); ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// SortNames sorts names in place by CompareNames.
+func SortNames(names []string) {
+	slices.SortFunc(names, CompareNames)
+}
+
+func foldASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
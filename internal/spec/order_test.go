@@ -0,0 +1,78 @@
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSortNames(t *testing.T) {
+	t.Parallel()
+	// The generated runtimes' comparator tests use the same names.
+	names := []string{"Zebra", "apple", "pet", "Pet", "_id", "éclair", "Eclair", "b", "B2", "a10", "a2", "Ünits", "units", "zoo", "😀", "文件", "ﬁ"}
+	SortNames(names)
+	want := []string{"_id", "a10", "a2", "apple", "b", "B2", "Eclair", "Pet", "pet", "units", "Zebra", "zoo", "Ünits", "éclair", "文件", "ﬁ", "😀"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("SortNames:\n got %q\nwant %q", names, want)
+	}
+
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"apple", "Zebra", -1},
+		{"Pet", "pet", -1},
+		{"pet", "pet", 0},
+		{"pets", "Pet", 1},
+		{"Ärger", "zoo", 1},
+	} {
+		if got := CompareNames(c.a, c.b); got != c.want {
+			t.Errorf("CompareNames(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestBuildServiceModel_NameOrder(t *testing.T) {
+	t.Parallel()
+	doc := loadDoc(t, `openapi: 3.0.0
+info: { title: Order, version: "1.0.0" }
+paths:
+  /zoo:
+    get:
+      tags: [zoo, Pets]
+      responses: { "200": { description: ok } }
+  /Users:
+    get:
+      tags: [ünits]
+      parameters:
+        - { in: query, name: b, schema: { type: string } }
+        - { in: query, name: _id, schema: { type: string } }
+        - { in: query, name: Apple, schema: { type: string } }
+      responses: { "200": { description: ok } }
+  /pets:
+    get:
+      tags: [pets]
+      responses: { "200": { description: ok } }
+`)
+	sm, err := BuildServiceModel(context.Background(), doc, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var paths []string
+	for _, ep := range sm.Endpoints {
+		paths = append(paths, ep.Path)
+	}
+	if want := []string{"/pets", "/Users", "/zoo"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("endpoint paths = %q, want %q", paths, want)
+	}
+	if want := []string{"Pets", "pets", "zoo", "ünits"}; !reflect.DeepEqual(sm.Tags, want) {
+		t.Errorf("tags = %q, want %q", sm.Tags, want)
+	}
+	var params []string
+	for _, p := range sm.Endpoints[1].Parameters {
+		params = append(params, p.Name)
+	}
+	if want := []string{"_id", "Apple", "b"}; !reflect.DeepEqual(params, want) {
+		t.Errorf("parameters = %q, want %q", params, want)
+	}
+}
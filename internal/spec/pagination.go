@@ -0,0 +1,50 @@
+package spec
+
+import "strings"
+
+// Pagination styles reported by PaginationHint.
+const (
+	PaginationCursor  = "cursor"
+	PaginationOffset  = "offset"
+	PaginationUnknown = "unknown"
+)
+
+// paginationParams maps the query parameter names that signal pagination,
+// lowercased and without separators (page_size, pageSize and page-size all
+// become pagesize), to the style they indicate. A size parameter alone says
+// the endpoint pages but not how.
+var paginationParams = map[string]string{
+	"cursor":   PaginationCursor,
+	"after":    PaginationCursor,
+	"before":   PaginationCursor,
+	"page":     PaginationOffset,
+	"offset":   PaginationOffset,
+	"pagesize": PaginationUnknown,
+	"limit":    PaginationUnknown,
+}
+
+// detectPagination recognizes the pagination pattern of an endpoint from its
+// query parameter names, or returns nil when none of them is a pagination
+// parameter. Cursor parameters win over offset ones, so limit alongside
+// cursor is a cursor page size.
+func detectPagination(params []ParameterModel) *PaginationHint {
+	var hint *PaginationHint
+	for _, p := range params {
+		if p.In != "query" {
+			continue
+		}
+		key := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(p.Name))
+		style, ok := paginationParams[key]
+		if !ok {
+			continue
+		}
+		if hint == nil {
+			hint = &PaginationHint{Style: PaginationUnknown}
+		}
+		hint.Params = append(hint.Params, p.Name)
+		if style == PaginationCursor || (style == PaginationOffset && hint.Style == PaginationUnknown) {
+			hint.Style = style
+		}
+	}
+	return hint
+}
@@ -0,0 +1,105 @@
+package spec
+
+import (
+	"iter"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// The helpers in this file are the only places that know how kin-openapi
+// represents the paths and responses collections (plain maps in v0.116,
+// wrapper types with accessor methods in later releases). Keep library
+// specifics here so a kin-openapi upgrade stays a change to this file.
+
+// iteratePaths yields the document's path items in CompareNames order.
+// Nil items are skipped.
+func iteratePaths(doc *openapi3.T) iter.Seq2[string, *openapi3.PathItem] {
+	return func(yield func(string, *openapi3.PathItem) bool) {
+		if doc == nil || doc.Paths == nil {
+			return
+		}
+		keys := make([]string, 0, len(doc.Paths))
+		for p := range doc.Paths {
+			keys = append(keys, p)
+		}
+		SortNames(keys)
+		for _, p := range keys {
+			item := doc.Paths[p]
+			if item == nil {
+				continue
+			}
+			if !yield(p, item) {
+				return
+			}
+		}
+	}
+}
+
+// lookupPath returns the path item for an exact path template, or nil.
+func lookupPath(doc *openapi3.T, path string) *openapi3.PathItem {
+	if doc == nil || doc.Paths == nil {
+		return nil
+	}
+	return doc.Paths[path]
+}
+
+// iterateResponses yields an operation's responses in canonical status order:
+// explicit codes ascending, each status class wildcard ("2XX") after the
+// explicit codes of its class, unrecognised keys next and "default" last.
+func iterateResponses(op *openapi3.Operation) iter.Seq2[string, *openapi3.ResponseRef] {
+	return func(yield func(string, *openapi3.ResponseRef) bool) {
+		if op == nil || op.Responses == nil {
+			return
+		}
+		keys := make([]string, 0, len(op.Responses))
+		for k := range op.Responses {
+			keys = append(keys, k)
+		}
+		sortStatusCodes(keys)
+		for _, code := range keys {
+			if !yield(code, op.Responses[code]) {
+				return
+			}
+		}
+	}
+}
+
+// sortStatusCodes orders response keys canonically; see iterateResponses.
+func sortStatusCodes(keys []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		ri, rj := statusRank(keys[i]), statusRank(keys[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// statusRank maps a response key to a sort rank. Explicit codes rank at twice
+// their value so a class wildcard ("4XX") can rank just after "499"; unknown
+// keys rank after every status and "default" ranks last.
+func statusRank(code string) int {
+	c := strings.ToUpper(strings.TrimSpace(code))
+	switch {
+	case c == "DEFAULT":
+		return 3000
+	case len(c) == 3 && c[0] >= '1' && c[0] <= '5' && c[1:] == "XX":
+		return int(c[0]-'0')*200 + 199
+	case len(c) == 3 && isDigits(c):
+		n := int(c[0]-'0')*100 + int(c[1]-'0')*10 + int(c[2]-'0')
+		return n * 2
+	default:
+		return 2000
+	}
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,76 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestIteratePaths_SortedAndSkipsNil(t *testing.T) {
+	t.Parallel()
+	doc := &openapi3.T{Paths: openapi3.Paths{
+		"/pets/{id}": &openapi3.PathItem{},
+		"/b":         &openapi3.PathItem{},
+		"/a":         &openapi3.PathItem{},
+		"/nil":       nil,
+		"/pets":      &openapi3.PathItem{},
+	}}
+	var got []string
+	for p, item := range iteratePaths(doc) {
+		if item == nil {
+			t.Fatalf("nil item yielded for %s", p)
+		}
+		got = append(got, p)
+	}
+	want := []string{"/a", "/b", "/pets", "/pets/{id}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("paths: want %v got %v", want, got)
+	}
+
+	// Early break stops iteration.
+	n := 0
+	for range iteratePaths(doc) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatalf("expected break after one item, got %d", n)
+	}
+
+	for range iteratePaths(&openapi3.T{}) {
+		t.Fatalf("expected no paths for empty doc")
+	}
+	for range iteratePaths(nil) {
+		t.Fatalf("expected no paths for nil doc")
+	}
+	if lookupPath(doc, "/a") == nil || lookupPath(doc, "/missing") != nil || lookupPath(nil, "/a") != nil {
+		t.Fatalf("lookupPath mismatch")
+	}
+}
+
+func TestIterateResponses_CanonicalOrder(t *testing.T) {
+	t.Parallel()
+	op := &openapi3.Operation{Responses: openapi3.Responses{}}
+	for _, code := range []string{"default", "500", "2XX", "404", "201", "200", "4xx", "weird", "5XX"} {
+		op.Responses[code] = &openapi3.ResponseRef{Value: openapi3.NewResponse()}
+	}
+	var got []string
+	for code, ref := range iterateResponses(op) {
+		if ref == nil {
+			t.Fatalf("nil ref for %s", code)
+		}
+		got = append(got, code)
+	}
+	want := []string{"200", "201", "2XX", "404", "4xx", "500", "5XX", "weird", "default"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("responses: want %v got %v", want, got)
+	}
+
+	for range iterateResponses(&openapi3.Operation{}) {
+		t.Fatalf("expected no responses")
+	}
+	for range iterateResponses(nil) {
+		t.Fatalf("expected no responses for nil op")
+	}
+}
@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"path"
+	"strings"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+func appendPatterns(dst []string, patterns []string) []string {
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			dst = append(dst, p)
+		}
+	}
+	return dst
+}
+
+// matchesAnyGlob reports whether name matches one of patterns. Invalid
+// patterns never match.
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(p, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applySchemaFilters drops schemas rejected by the include/exclude name
+// globs. A rejected schema that is still referenced by an endpoint or kept
+// schema is replaced by a stub marked Excluded, and a warning is recorded.
+func applySchemaFilters(sm *ServiceModel, cfg *buildConfig) {
+	if len(sm.Schemas) == 0 || (len(cfg.includeSchemas) == 0 && len(cfg.excludeSchemas) == 0) {
+		return
+	}
+	kept := func(name string) bool {
+		if len(cfg.includeSchemas) > 0 && !matchesAnyGlob(name, cfg.includeSchemas) {
+			return false
+		}
+		return !matchesAnyGlob(name, cfg.excludeSchemas)
+	}
+
+	referenced := map[string]bool{}
+	var queue []string
+	visit := func(sor *SchemaOrRef) {
+		walkSchemaRefs(sor, func(name string) {
+			if !referenced[name] {
+				referenced[name] = true
+				queue = append(queue, name)
+			}
+		})
+	}
+	for i := range sm.Endpoints {
+		ep := &sm.Endpoints[i]
+		for _, p := range ep.Parameters {
+			visit(p.Schema)
+		}
+		if ep.RequestBody != nil {
+			for _, m := range ep.RequestBody.Content {
+				visit(m.Schema)
+			}
+		}
+		for _, r := range ep.Responses {
+			for _, m := range r.Content {
+				visit(m.Schema)
+			}
+		}
+	}
+	for name := range sm.Schemas {
+		if kept(name) {
+			sc := sm.Schemas[name]
+			visit(&SchemaOrRef{Schema: &sc})
+		}
+	}
+	// Follow references through kept schemas only; a stub has no children.
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if sc, ok := sm.Schemas[name]; ok && kept(name) {
+			visit(&SchemaOrRef{Schema: &sc})
+		}
+	}
+
+	names := make([]string, 0, len(sm.Schemas))
+	for name := range sm.Schemas {
+		names = append(names, name)
+	}
+	SortNames(names)
+	for _, name := range names {
+		if kept(name) {
+			continue
+		}
+		if !referenced[name] {
+			delete(sm.Schemas, name)
+			continue
+		}
+		sm.Schemas[name] = Schema{Name: name, Description: "excluded by schema filter", Excluded: true}
+		sm.Warnings = append(sm.Warnings, NewWarning(WarnExcludedSchemaKept, "schema %q is excluded by schema filters but still referenced; keeping an excluded stub", name).At("", schemaRefPrefix+name))
+	}
+}
+
+// walkSchemaRefs calls fn with the component name of every $ref under sor.
+func walkSchemaRefs(sor *SchemaOrRef, fn func(name string)) {
+	if sor == nil {
+		return
+	}
+	if sor.Ref != nil {
+		if strings.HasPrefix(sor.Ref.Ref, schemaRefPrefix) {
+			fn(strings.TrimPrefix(sor.Ref.Ref, schemaRefPrefix))
+		}
+		return
+	}
+	sc := sor.Schema
+	if sc == nil {
+		return
+	}
+	for _, prop := range sc.Properties {
+		walkSchemaRefs(prop, fn)
+	}
+	walkSchemaRefs(sc.Items, fn)
+	for _, group := range [][]*SchemaOrRef{sc.AllOf, sc.AnyOf, sc.OneOf} {
+		for _, member := range group {
+			walkSchemaRefs(member, fn)
+		}
+	}
+}
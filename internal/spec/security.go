@@ -0,0 +1,125 @@
+package spec
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// securitySchemes converts doc.Components.SecuritySchemes. Swagger 2.0
+// securityDefinitions arrive here already converted by openapi2conv, which
+// maps "basic" to http/basic and the v2 oauth2 flow names to their v3 forms.
+// It returns nil when the document defines none.
+func securitySchemes(doc *openapi3.T) map[string]SecurityScheme {
+	if doc.Components == nil || len(doc.Components.SecuritySchemes) == 0 {
+		return nil
+	}
+	out := make(map[string]SecurityScheme, len(doc.Components.SecuritySchemes))
+	for name, ref := range doc.Components.SecuritySchemes {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		v := ref.Value
+		out[name] = SecurityScheme{
+			Name:             name,
+			Type:             safeStr(v.Type),
+			Description:      safeStr(v.Description),
+			In:               safeStr(v.In),
+			ParamName:        safeStr(v.Name),
+			Scheme:           safeStr(v.Scheme),
+			BearerFormat:     safeStr(v.BearerFormat),
+			Flows:            oauthFlows(v.Flows),
+			OpenIDConnectURL: safeStr(v.OpenIdConnectUrl),
+			Extensions:       vendorExtensions(v.Extensions),
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// securityRequirements returns the scheme names of each alternative of op, or
+// of doc when the operation does not declare security. An explicit empty
+// list ("security: []") and requirements that only allow anonymous access
+// yield nil.
+func securityRequirements(op *openapi3.SecurityRequirements, doc openapi3.SecurityRequirements) [][]string {
+	reqs := doc
+	if op != nil {
+		reqs = *op
+	}
+	var out [][]string
+	named := false
+	for _, req := range reqs {
+		names := make([]string, 0, len(req))
+		for name := range req {
+			names = append(names, name)
+		}
+		SortNames(names)
+		named = named || len(names) > 0
+		out = append(out, names)
+	}
+	if !named {
+		return nil
+	}
+	return out
+}
+
+// oauthFlows lists the flows that are set, in a fixed order.
+func oauthFlows(flows *openapi3.OAuthFlows) []OAuthFlow {
+	if flows == nil {
+		return nil
+	}
+	var out []OAuthFlow
+	for _, f := range []struct {
+		typ  string
+		flow *openapi3.OAuthFlow
+	}{
+		{"implicit", flows.Implicit},
+		{"password", flows.Password},
+		{"clientCredentials", flows.ClientCredentials},
+		{"authorizationCode", flows.AuthorizationCode},
+	} {
+		if f.flow == nil {
+			continue
+		}
+		var scopes map[string]string
+		if len(f.flow.Scopes) > 0 {
+			scopes = make(map[string]string, len(f.flow.Scopes))
+			for k, v := range f.flow.Scopes {
+				scopes[k] = v
+			}
+		}
+		out = append(out, OAuthFlow{
+			Type:             f.typ,
+			AuthorizationURL: safeStr(f.flow.AuthorizationURL),
+			TokenURL:         safeStr(f.flow.TokenURL),
+			RefreshURL:       safeStr(f.flow.RefreshURL),
+			Scopes:           scopes,
+		})
+	}
+	return out
+}
+
+// reservedHeaderWarnings flags header parameters the OpenAPI spec says are
+// not described as parameters: Authorization belongs in a security scheme,
+// cookies in "in: cookie" parameters, and Set-Cookie is a response header. Such parameters are kept in the
+// model, but hosts and clients may ignore or strip them.
+func reservedHeaderWarnings(ep EndpointModel, params []ParameterModel) []Warning {
+	var out []Warning
+	pointer := operationPointer(ep.Path, ep.Method)
+	for _, p := range params {
+		if p.In != "header" {
+			continue
+		}
+		switch {
+		case strings.EqualFold(p.Name, "Authorization"):
+			out = append(out, NewWarning(WarnAuthorizationHeader, "%s: header parameter %q should be a security scheme, not a parameter", ep.ID, p.Name).At("", pointer))
+		case strings.EqualFold(p.Name, "Cookie"):
+			out = append(out, NewWarning(WarnCookieHeader, "%s: header parameter %q should be modeled as \"in: cookie\" parameters", ep.ID, p.Name).At("", pointer))
+		case strings.EqualFold(p.Name, "Set-Cookie"):
+			out = append(out, NewWarning(WarnSetCookieHeader, "%s: header parameter %q is a response header; describe it under the response's headers", ep.ID, p.Name).At("", pointer))
+		}
+	}
+	return out
+}
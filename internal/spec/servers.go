@@ -0,0 +1,99 @@
+package spec
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// isDevelopmentServer reports whether s is marked x-internal: true or its URL
+// points at a development host (see isDevelopmentURL).
+func isDevelopmentServer(s *openapi3.Server) bool {
+	if internal, ok := s.Extensions["x-internal"].(bool); ok && internal {
+		return true
+	}
+	return isDevelopmentURL(s.URL)
+}
+
+// isDevelopmentURL reports whether rawURL's host is localhost, a loopback,
+// private (RFC 1918 / RFC 4193), link-local, or unspecified IP address, or an
+// mDNS .local name. Relative and templated URLs are not development URLs.
+func isDevelopmentURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return false
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+}
+
+// arrangeServers stably orders servers as public https first, then other
+// public servers, then development servers. With drop, development servers
+// are removed instead and their URLs returned.
+func arrangeServers(servers []Server, drop bool) (kept []Server, dropped []string) {
+	rank := func(s Server) int {
+		switch {
+		case s.Development:
+			return 2
+		case strings.HasPrefix(strings.ToLower(s.URL), "https://"):
+			return 0
+		}
+		return 1
+	}
+	for _, s := range servers {
+		if drop && s.Development {
+			dropped = append(dropped, s.URL)
+			continue
+		}
+		kept = append(kept, s)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return rank(kept[i]) < rank(kept[j]) })
+	return kept, dropped
+}
+
+// synthesizeV2Servers fills doc.Servers from a Swagger v2 host, schemes and
+// basePath when the document has none, e.g. when it was converted by a caller
+// that dropped them before SetV2SchemaDefinitions. URLs follow openapi2conv:
+// one per scheme in declaration order, https when schemes is empty, and "/"
+// when basePath is empty. Without a host there is nothing to synthesize.
+func synthesizeV2Servers(doc *openapi3.T, root map[string]any) {
+	if len(doc.Servers) > 0 {
+		return
+	}
+	host := strings.TrimSpace(asString(root["host"]))
+	if host == "" || strings.Contains(host, "/") {
+		return
+	}
+	var schemes []string
+	if raw, ok := root["schemes"].([]any); ok {
+		for _, s := range raw {
+			if s := strings.TrimSpace(asString(s)); s != "" {
+				schemes = append(schemes, s)
+			}
+		}
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	basePath := asString(root["basePath"])
+	if basePath == "" {
+		basePath = "/"
+	}
+	for _, scheme := range schemes {
+		u := url.URL{Scheme: scheme, Host: host, Path: basePath}
+		doc.AddServer(&openapi3.Server{URL: u.String()})
+	}
+}
@@ -0,0 +1,108 @@
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestIsDevelopmentURL(t *testing.T) {
+	t.Parallel()
+	for raw, want := range map[string]bool{
+		"http://localhost:8080":         true,
+		"http://LOCALHOST/api":          true,
+		"http://app.localhost":          true,
+		"http://127.0.0.1:3000":         true,
+		"http://[::1]:8080/v1":          true,
+		"http://10.1.2.3":               true,
+		"http://172.16.0.1":             true,
+		"http://172.31.255.255":         true,
+		"http://192.168.1.20:8080":      true,
+		"http://0.0.0.0:8000":           true,
+		"http://169.254.10.1":           true,
+		"http://printer.local":          true,
+		"https://api.example.com":       false,
+		"http://172.32.0.1":             false,
+		"http://8.8.8.8":                false,
+		"https://localhost.example.com": false,
+		"/v1":                           false,
+		"https://{region}.example.com":  false,
+		"":                              false,
+	} {
+		if got := isDevelopmentURL(raw); got != want {
+			t.Errorf("isDevelopmentURL(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+const mixedServersSpec = `openapi: 3.0.0
+info: { title: Servers, version: "1.0.0" }
+servers:
+  - url: http://localhost:8080
+    description: local
+  - url: http://staging.example.com
+  - url: https://internal.example.com
+    x-internal: true
+  - url: https://api.example.com
+  - url: http://192.168.0.10
+paths: {}
+`
+
+func serverURLs(servers []Server) []string {
+	out := make([]string, 0, len(servers))
+	for _, s := range servers {
+		out = append(out, s.URL)
+	}
+	return out
+}
+
+func TestBuildServiceModel_ServerOrdering(t *testing.T) {
+	t.Parallel()
+	doc := loadDoc(t, mixedServersSpec)
+	ctx := context.Background()
+
+	sm, err := BuildServiceModel(ctx, doc, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	want := []string{
+		"https://api.example.com",
+		"http://staging.example.com",
+		"http://localhost:8080",
+		"https://internal.example.com",
+		"http://192.168.0.10",
+	}
+	if got := serverURLs(sm.Servers); !reflect.DeepEqual(got, want) {
+		t.Fatalf("order: want %v got %v", want, got)
+	}
+	var dev []bool
+	for _, s := range sm.Servers {
+		dev = append(dev, s.Development)
+	}
+	if !reflect.DeepEqual(dev, []bool{false, false, true, true, true}) {
+		t.Errorf("development markers: got %v", dev)
+	}
+	if sm.Servers[2].Description != "local" {
+		t.Errorf("description lost: %+v", sm.Servers[2])
+	}
+
+	sm, err = BuildServiceModel(ctx, doc, nil, WithDropDevServers(true))
+	if err != nil {
+		t.Fatalf("build with drop: %v", err)
+	}
+	if got := serverURLs(sm.Servers); !reflect.DeepEqual(got, want[:2]) {
+		t.Fatalf("drop: want %v got %v", want[:2], got)
+	}
+	if len(sm.Warnings) != 3 {
+		t.Errorf("expected a warning per dropped server, got %v", sm.Warnings)
+	}
+
+	sm, err = BuildServiceModel(ctx, doc, nil, WithKeepAllServers(true), WithDropDevServers(true))
+	if err != nil {
+		t.Fatalf("build with keep-all: %v", err)
+	}
+	spec := []string{"http://localhost:8080", "http://staging.example.com", "https://internal.example.com", "https://api.example.com", "http://192.168.0.10"}
+	if got := serverURLs(sm.Servers); !reflect.DeepEqual(got, spec) {
+		t.Fatalf("keep-all: want %v got %v", spec, got)
+	}
+}
@@ -3,6 +3,7 @@ package spec
 import (
     "strings"
 
+    "github.com/getkin/kin-openapi/openapi3"
     "gopkg.in/yaml.v3"
 )
 
@@ -12,6 +13,10 @@ import (
 //   parameter whose schema is an object with properties per original parameter.
 // - If an operation mixes body and formData parameters, convert all body parameters to
 //   formData equivalents and ensure the operation consumes multipart/form-data.
+// - If the document has no host but a non-root basePath, prepend it to every path
+//   (see foldV2BasePath).
+// - Rewrite `type: file` response schemas to `{type: string, format: binary}`
+//   (see rewriteV2FileResponses).
 //
 // It returns possibly-modified YAML bytes, a flag indicating whether modifications were made,
 // and any error encountered during parsing/serialization. On error, the original bytes are
@@ -21,11 +26,27 @@ func preprocessV2ForCompatibility(data []byte) ([]byte, bool, error) {
     if err := yaml.Unmarshal(data, &doc); err != nil {
         return data, false, err
     }
+    if !preprocessV2Document(doc) {
+        return data, false, nil
+    }
+    out, err := yaml.Marshal(doc)
+    if err != nil {
+        return data, false, err
+    }
+    return out, true, nil
+}
+
+// preprocessV2Document applies the rewrites of preprocessV2ForCompatibility to an
+// already-decoded document in place and reports whether anything changed.
+func preprocessV2Document(doc map[string]any) bool {
+    modified := foldV2BasePath(doc)
+    if rewriteV2FileResponses(doc) {
+        modified = true
+    }
     paths, ok := doc["paths"].(map[string]any)
     if !ok || len(paths) == 0 {
-        return data, false, nil
+        return modified
     }
-    modified := false
 
     // Iterate each path + method
     for _, pim := range paths {
@@ -118,14 +139,76 @@ func preprocessV2ForCompatibility(data []byte) ([]byte, bool, error) {
         }
     }
 
-    if !modified {
-        return data, false, nil
+    return modified
+}
+
+// foldV2BasePath makes a Swagger v2 basePath survive conversion. With a host,
+// conversion already puts basePath into the server URL (scheme://host/basePath)
+// and paths stay as written. Without a host there is no server to carry it, so
+// the normalized basePath (leading slash, no trailing slash) is prepended to every
+// path key and removed from the document; "/" and an empty basePath are no-ops.
+// It reports whether the document changed.
+func foldV2BasePath(doc map[string]any) bool {
+    if strings.TrimSpace(asString(doc["host"])) != "" {
+        return false
     }
-    out, err := yaml.Marshal(doc)
-    if err != nil {
-        return data, false, err
+    base := strings.Trim(strings.TrimSpace(asString(doc["basePath"])), "/")
+    if base == "" {
+        return false
     }
-    return out, true, nil
+    base = "/" + base
+    delete(doc, "basePath")
+    paths, _ := doc["paths"].(map[string]any)
+    folded := make(map[string]any, len(paths))
+    for p, item := range paths {
+        if strings.HasPrefix(strings.ToLower(p), "x-") {
+            folded[p] = item
+            continue
+        }
+        if p == "/" {
+            folded[base] = item
+            continue
+        }
+        folded[base+"/"+strings.TrimLeft(p, "/")] = item
+    }
+    doc["paths"] = folded
+    return true
+}
+
+// rewriteV2FileResponses replaces the Swagger v2 `type: file` response schema,
+// which has no v3 equivalent, with a binary string so the converted media keeps
+// the operation's produces mime (e.g. application/octet-stream) and a schema
+// every generated runtime understands. formData file parameters are left to
+// the converter, which already maps them. It reports whether anything changed.
+func rewriteV2FileResponses(doc map[string]any) bool {
+    modified := false
+    rewrite := func(responses any) {
+        rs, _ := responses.(map[string]any)
+        for _, r := range rs {
+            rm, _ := r.(map[string]any)
+            if rm == nil { continue }
+            if sch, ok := rm["schema"].(map[string]any); ok && isV2FileSchema(sch) {
+                sch["type"] = "string"
+                sch["format"] = "binary"
+                modified = true
+            }
+        }
+    }
+    rewrite(doc["responses"])
+    paths, _ := doc["paths"].(map[string]any)
+    for _, pim := range paths {
+        pi, _ := pim.(map[string]any)
+        for _, opm := range pi {
+            if op, ok := opm.(map[string]any); ok {
+                rewrite(op["responses"])
+            }
+        }
+    }
+    return modified
+}
+
+func isV2FileSchema(sch map[string]any) bool {
+    return strings.EqualFold(asString(sch["type"]), "file")
 }
 
 func asString(v any) string {
@@ -195,3 +278,138 @@ func formDataFromBodyParam(pm map[string]any) map[string]any {
     return out
 }
 
+// restoreV2Extensions copies document- and operation-level x-* keys from the
+// decoded Swagger v2 document onto the converted document. The YAML decoder used for v2
+// skips openapi2's Extensions fields, so conversion alone would drop them.
+func restoreV2Extensions(doc *openapi3.T, root map[string]any) {
+    if doc == nil || root == nil {
+        return
+    }
+    doc.Extensions = mergeExtensions(doc.Extensions, root)
+    paths, _ := root["paths"].(map[string]any)
+    for p, rawItem := range paths {
+        item, _ := rawItem.(map[string]any)
+        pathItem := lookupPath(doc, p)
+        if item == nil || pathItem == nil {
+            continue
+        }
+        for method, rawOp := range item {
+            op, _ := rawOp.(map[string]any)
+            if op == nil {
+                continue
+            }
+            if v3op := pathItem.GetOperation(strings.ToUpper(method)); v3op != nil {
+                v3op.Extensions = mergeExtensions(v3op.Extensions, op)
+            }
+        }
+    }
+}
+
+// applyV2CollectionFormats maps the collectionFormat of Swagger v2 array
+// parameters, which conversion drops, onto the style/explode of the converted
+// v3 parameters:
+//   - csv:   form (query) or simple (path, header), explode=false
+//   - ssv:   spaceDelimited, explode=false (query only)
+//   - pipes: pipeDelimited, explode=false (query only)
+//   - multi: form, explode=true (query only)
+// tsv has no OpenAPI 3 style and is left unmapped, as are combinations v3
+// does not allow. Operation, path-level and shared (#/parameters) parameters
+// are covered; formData parameters become request body fields and are skipped.
+func applyV2CollectionFormats(doc *openapi3.T, root map[string]any) {
+    if doc == nil || root == nil {
+        return
+    }
+    apply := func(raw any, v3params openapi3.Parameters) {
+        list, _ := raw.([]any)
+        for _, rp := range list {
+            pm, _ := rp.(map[string]any)
+            if pm == nil {
+                continue
+            }
+            if p := v3params.GetByInAndName(asString(pm["in"]), asString(pm["name"])); p != nil {
+                setV2CollectionFormat(p, asString(pm["collectionFormat"]))
+            }
+        }
+    }
+    if shared, ok := root["parameters"].(map[string]any); ok && doc.Components != nil {
+        for name, rp := range shared {
+            pm, _ := rp.(map[string]any)
+            if ref := doc.Components.Parameters[name]; pm != nil && ref != nil && ref.Value != nil {
+                setV2CollectionFormat(ref.Value, asString(pm["collectionFormat"]))
+            }
+        }
+    }
+    paths, _ := root["paths"].(map[string]any)
+    for p, rawItem := range paths {
+        item, _ := rawItem.(map[string]any)
+        pathItem := lookupPath(doc, p)
+        if item == nil || pathItem == nil {
+            continue
+        }
+        apply(item["parameters"], pathItem.Parameters)
+        for method, rawOp := range item {
+            op, _ := rawOp.(map[string]any)
+            if op == nil {
+                continue
+            }
+            if v3op := pathItem.GetOperation(strings.ToUpper(method)); v3op != nil {
+                apply(op["parameters"], v3op.Parameters)
+            }
+        }
+    }
+}
+
+func setV2CollectionFormat(p *openapi3.Parameter, format string) {
+    query := p.In == openapi3.ParameterInQuery
+    style, explode := "", false
+    switch strings.ToLower(strings.TrimSpace(format)) {
+    case "csv":
+        if query {
+            style = openapi3.SerializationForm
+        } else if p.In == openapi3.ParameterInPath || p.In == openapi3.ParameterInHeader {
+            style = openapi3.SerializationSimple
+        }
+    case "ssv":
+        if query {
+            style = openapi3.SerializationSpaceDelimited
+        }
+    case "pipes":
+        if query {
+            style = openapi3.SerializationPipeDelimited
+        }
+    case "multi":
+        if query {
+            style, explode = openapi3.SerializationForm, true
+        }
+    }
+    if style == "" {
+        return
+    }
+    p.Style = style
+    p.Explode = &explode
+}
+
+func mergeExtensions(dst map[string]any, raw map[string]any) map[string]any {
+    for k, v := range raw {
+        if !strings.HasPrefix(strings.ToLower(k), "x-") {
+            continue
+        }
+        if dst == nil {
+            dst = make(map[string]any)
+        }
+        if _, exists := dst[k]; !exists {
+            dst[k] = v
+        }
+    }
+    return dst
+}
+
+// decodeV2Raw parses Swagger v2 YAML/JSON bytes into a generic document, or
+// returns nil when the bytes do not parse.
+func decodeV2Raw(data []byte) map[string]any {
+    var root map[string]any
+    if err := yaml.Unmarshal(data, &root); err != nil {
+        return nil
+    }
+    return root
+}
@@ -1,8 +1,15 @@
 package spec
 
 import (
+    "context"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
     "strings"
     "testing"
+
+    "github.com/getkin/kin-openapi/openapi3"
 )
 
 func TestV2Compat_MultipleBodyMerged(t *testing.T) {
@@ -61,3 +68,233 @@ paths:
         t.Fatalf("expected consumes multipart/form-data, got:\n%s", s)
     }
 }
+
+func TestV2Compat_SecurityDefinitionsSurviveConversion(t *testing.T) {
+    t.Parallel()
+    path := filepath.Join(t.TempDir(), "swagger.yaml")
+    content := `swagger: "2.0"
+info: { title: t, version: "1.0.0" }
+securityDefinitions:
+  api_key:
+    type: apiKey
+    in: header
+    name: X-API-Key
+    description: Per-tenant key
+  basicAuth:
+    type: basic
+  petstore_auth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://auth.example.com/authorize
+    tokenUrl: https://auth.example.com/token
+    scopes:
+      read:pets: read your pets
+      write:pets: modify pets
+paths:
+  /x:
+    get:
+      security: [ { api_key: [] } ]
+      responses: { '200': { description: ok } }
+`
+    if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    doc, err := Load(context.Background(), path)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+
+    key, ok := sm.SecuritySchemes["api_key"]
+    if !ok {
+        t.Fatalf("api_key scheme missing: %+v", sm.SecuritySchemes)
+    }
+    if key.Type != "apiKey" || key.In != "header" || key.ParamName != "X-API-Key" || key.Description != "Per-tenant key" {
+        t.Errorf("api_key: got %+v", key)
+    }
+    if basic := sm.SecuritySchemes["basicAuth"]; basic.Type != "http" || basic.Scheme != "basic" {
+        t.Errorf("basicAuth: got %+v", basic)
+    }
+    oauth := sm.SecuritySchemes["petstore_auth"]
+    if oauth.Type != "oauth2" || len(oauth.Flows) != 1 {
+        t.Fatalf("petstore_auth: got %+v", oauth)
+    }
+    flow := oauth.Flows[0]
+    if flow.Type != "authorizationCode" || flow.AuthorizationURL != "https://auth.example.com/authorize" ||
+        flow.TokenURL != "https://auth.example.com/token" || flow.Scopes["write:pets"] != "modify pets" || len(flow.Scopes) != 2 {
+        t.Errorf("petstore_auth flow: got %+v", flow)
+    }
+}
+
+func TestV2Compat_BasePathFolding(t *testing.T) {
+    t.Parallel()
+    load := func(t *testing.T, header string) *ServiceModel {
+        t.Helper()
+        path := filepath.Join(t.TempDir(), "swagger.yaml")
+        content := `swagger: "2.0"
+info: { title: t, version: "1.0.0" }
+` + header + `paths:
+  /:
+    get:
+      responses: { '200': { description: ok } }
+  /pets/{id}:
+    get:
+      parameters:
+        - { name: id, in: path, required: true, type: string }
+      responses: { '200': { description: ok } }
+`
+        if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+            t.Fatalf("write: %v", err)
+        }
+        doc, err := Load(context.Background(), path)
+        if err != nil {
+            t.Fatalf("load: %v", err)
+        }
+        sm, err := BuildServiceModel(context.Background(), doc, nil)
+        if err != nil {
+            t.Fatalf("build: %v", err)
+        }
+        return sm
+    }
+    paths := func(sm *ServiceModel) []string {
+        var out []string
+        for _, ep := range sm.Endpoints {
+            out = append(out, ep.Path)
+        }
+        sort.Strings(out)
+        return out
+    }
+
+    t.Run("no host folds into paths", func(t *testing.T) {
+        t.Parallel()
+        sm := load(t, "basePath: /api/v1/\n")
+        got := strings.Join(paths(sm), ",")
+        if got != "/api/v1,/api/v1/pets/{id}" {
+            t.Fatalf("paths = %s", got)
+        }
+        for _, ep := range sm.Endpoints {
+            if ep.Path == "/api/v1/pets/{id}" && len(ep.Parameters) != 1 {
+                t.Fatalf("path parameters lost after folding: %+v", ep.Parameters)
+            }
+        }
+    })
+    t.Run("host keeps basePath in server URL", func(t *testing.T) {
+        t.Parallel()
+        sm := load(t, "host: api.example.com\nschemes: [https]\nbasePath: /api/v1\n")
+        got := strings.Join(paths(sm), ",")
+        if got != "/,/pets/{id}" {
+            t.Fatalf("paths = %s", got)
+        }
+        if len(sm.Servers) != 1 || sm.Servers[0].URL != "https://api.example.com/api/v1" {
+            t.Fatalf("servers = %+v", sm.Servers)
+        }
+    })
+    t.Run("root basePath is a no-op", func(t *testing.T) {
+        t.Parallel()
+        sm := load(t, "basePath: /\n")
+        if got := strings.Join(paths(sm), ","); got != "/,/pets/{id}" {
+            t.Fatalf("paths = %s", got)
+        }
+    })
+}
+
+func TestV2Compat_ServersFromHostAndSchemes(t *testing.T) {
+    t.Parallel()
+    raw := []byte(`swagger: "2.0"
+info: { title: t, version: "1.0.0" }
+host: api.example.com
+schemes: [https, http]
+basePath: /v1
+paths:
+  /pets:
+    get:
+      responses: { '200': { description: ok } }
+`)
+    path := filepath.Join(t.TempDir(), "swagger.yaml")
+    if err := os.WriteFile(path, raw, 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    loaded, err := Load(context.Background(), path)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    // A caller-converted document without servers gets them from the raw v2 bytes.
+    bare := &openapi3.T{OpenAPI: "3.0.0", Info: loaded.Info, Paths: loaded.Paths}
+    SetV2SchemaDefinitions(bare, raw)
+
+    want := "https://api.example.com/v1,http://api.example.com/v1"
+    for name, doc := range map[string]*openapi3.T{"load": loaded, "SetV2SchemaDefinitions": bare} {
+        sm, err := BuildServiceModel(context.Background(), doc, nil)
+        if err != nil {
+            t.Fatalf("%s: build: %v", name, err)
+        }
+        var urls []string
+        for _, s := range sm.Servers {
+            urls = append(urls, s.URL)
+        }
+        if got := strings.Join(urls, ","); got != want {
+            t.Fatalf("%s: servers = %s, want %s", name, got, want)
+        }
+    }
+}
+
+func TestV2Compat_CollectionFormatToStyleExplode(t *testing.T) {
+    t.Parallel()
+    path := filepath.Join(t.TempDir(), "swagger.yaml")
+    content := `swagger: "2.0"
+info: { title: t, version: "1.0.0" }
+parameters:
+  fields:
+    { name: fields, in: query, type: array, items: { type: string }, collectionFormat: pipes }
+paths:
+  /pets/{ids}:
+    parameters:
+      - { name: ids, in: path, required: true, type: array, items: { type: string }, collectionFormat: csv }
+    get:
+      parameters:
+        - { name: tag, in: query, type: array, items: { type: string }, collectionFormat: multi }
+        - { name: sort, in: query, type: array, items: { type: string }, collectionFormat: csv }
+        - { name: tabs, in: query, type: array, items: { type: string }, collectionFormat: tsv }
+        - { name: plain, in: query, type: array, items: { type: string } }
+        - $ref: '#/parameters/fields'
+      responses: { '200': { description: ok } }
+`
+    if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    doc, err := Load(context.Background(), path)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    sm, err := BuildServiceModel(context.Background(), doc, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if len(sm.Endpoints) != 1 {
+        t.Fatalf("endpoints = %d", len(sm.Endpoints))
+    }
+    got := map[string]string{}
+    for _, p := range sm.Endpoints[0].Parameters {
+        explode := "-"
+        if p.Explode != nil {
+            explode = strconv.FormatBool(*p.Explode)
+        }
+        got[p.Name] = p.Style + "/" + explode
+    }
+    want := map[string]string{
+        "tag":    "form/true",
+        "sort":   "form/false",
+        "ids":    "simple/false",
+        "fields": "pipeDelimited/false",
+        "tabs":   "/-",
+        "plain":  "/-",
+    }
+    for name, w := range want {
+        if got[name] != w {
+            t.Errorf("%s: style/explode = %q, want %q", name, got[name], w)
+        }
+    }
+}
@@ -0,0 +1,113 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WarningID is the stable identifier of a kind of warning, e.g.
+// SW2MCP-W001. IDs are never renumbered or reused, so CI configuration can
+// allow or deny warnings by ID across releases.
+type WarningID string
+
+// Warning categories group related warning kinds.
+const (
+	CategoryReference = "reference"
+	CategoryFilter    = "filter"
+	CategoryServer    = "server"
+	CategoryParameter = "parameter"
+	CategoryOverride  = "override"
+	CategoryOutput    = "output"
+)
+
+const (
+	WarnUnresolvedRef          WarningID = "SW2MCP-W001"
+	WarnRefResolutionFailed    WarningID = "SW2MCP-W002"
+	WarnExcludedSchemaKept     WarningID = "SW2MCP-W010"
+	WarnDevServerDropped       WarningID = "SW2MCP-W020"
+	WarnAuthorizationHeader    WarningID = "SW2MCP-W030"
+	WarnCookieHeader           WarningID = "SW2MCP-W031"
+	WarnSetCookieHeader        WarningID = "SW2MCP-W032"
+	WarnOverrideUnmatched      WarningID = "SW2MCP-W040"
+	WarnOverrideHiddenFeatured WarningID = "SW2MCP-W041"
+	WarnModifiedFileKept       WarningID = "SW2MCP-W050"
+	WarnUnmanagedFileKept      WarningID = "SW2MCP-W051"
+	WarnStaleFile              WarningID = "SW2MCP-W052"
+	WarnHookFailed             WarningID = "SW2MCP-W053"
+)
+
+// WarningKind describes one registered kind of warning.
+type WarningKind struct {
+	ID       WarningID
+	Name     string // short kebab-case name, as stable as the ID
+	Category string
+	Summary  string
+}
+
+// warningKinds is the warning taxonomy, in ID order. Every Warning is built
+// by NewWarning from one of these kinds.
+var warningKinds = []WarningKind{
+	{WarnUnresolvedRef, "unresolved-ref", CategoryReference, "a $ref could not be resolved; the document is loaded without it"},
+	{WarnRefResolutionFailed, "ref-resolution-failed", CategoryReference, "resolving the refs of a document converted from Swagger 2.0 failed"},
+	{WarnExcludedSchemaKept, "excluded-schema-referenced", CategoryFilter, "a schema rejected by the schema filters is still referenced and kept as a stub"},
+	{WarnDevServerDropped, "dev-server-dropped", CategoryServer, "a development server was dropped by --drop-dev-servers"},
+	{WarnAuthorizationHeader, "authorization-header", CategoryParameter, "an Authorization header is described as a parameter instead of a security scheme"},
+	{WarnCookieHeader, "cookie-header", CategoryParameter, "a Cookie header is described as a parameter instead of \"in: cookie\" parameters"},
+	{WarnSetCookieHeader, "set-cookie-header", CategoryParameter, "a Set-Cookie response header is described as a request parameter"},
+	{WarnOverrideUnmatched, "override-unmatched", CategoryOverride, "an endpoint override matches no endpoint"},
+	{WarnOverrideHiddenFeatured, "override-hidden-featured", CategoryOverride, "an endpoint override both hides and features an endpoint"},
+	{WarnModifiedFileKept, "modified-file-kept", CategoryOutput, "a generated file edited since the last run was kept"},
+	{WarnUnmanagedFileKept, "unmanaged-file-kept", CategoryOutput, "an existing file swagger2mcp did not generate was kept"},
+	{WarnStaleFile, "stale-file", CategoryOutput, "a file the last run generated is no longer produced"},
+	{WarnHookFailed, "hook-failed", CategoryOutput, "a postGenerate hook failed"},
+}
+
+// WarningKinds returns the registered warning kinds in ID order.
+func WarningKinds() []WarningKind {
+	return append([]WarningKind(nil), warningKinds...)
+}
+
+// LookupWarning finds a registered kind by ID or name, ignoring case.
+func LookupWarning(idOrName string) (WarningKind, bool) {
+	s := strings.TrimSpace(idOrName)
+	for _, k := range warningKinds {
+		if strings.EqualFold(string(k.ID), s) || strings.EqualFold(k.Name, s) {
+			return k, true
+		}
+	}
+	return WarningKind{}, false
+}
+
+// NewWarning returns a warning of the registered kind id, with its category
+// filled in from the registry and a message built from format and args.
+func NewWarning(id WarningID, format string, args ...any) Warning {
+	k, _ := LookupWarning(string(id))
+	return Warning{ID: id, Category: k.Category, Message: fmt.Sprintf(format, args...)}
+}
+
+// At returns w with its location and JSON pointer set.
+func (w Warning) At(location, pointer string) Warning {
+	w.Location = location
+	w.Pointer = pointer
+	return w
+}
+
+// String formats w for text output: its message followed by its ID.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s (%s)", w.Message, w.ID)
+}
+
+// WarningMessages returns the messages of warnings.
+func WarningMessages(warnings []Warning) []string {
+	var out []string
+	for _, w := range warnings {
+		out = append(out, w.Message)
+	}
+	return out
+}
+
+// operationPointer is the JSON pointer of the operation method on path.
+func operationPointer(path string, method HttpMethod) string {
+	escaped := strings.NewReplacer("~", "~0", "/", "~1").Replace(path)
+	return "#/paths/" + escaped + "/" + string(method)
+}
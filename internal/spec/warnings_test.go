@@ -0,0 +1,123 @@
+package spec
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWarningKinds_Unique(t *testing.T) {
+	idRe := regexp.MustCompile(`^SW2MCP-W\d{3}$`)
+	nameRe := regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+	ids, names := map[WarningID]bool{}, map[string]bool{}
+	var prev WarningID
+	for _, k := range WarningKinds() {
+		if !idRe.MatchString(string(k.ID)) || !nameRe.MatchString(k.Name) {
+			t.Errorf("malformed kind %+v", k)
+		}
+		if ids[k.ID] || names[k.Name] {
+			t.Errorf("duplicate kind %+v", k)
+		}
+		ids[k.ID], names[k.Name] = true, true
+		if k.ID <= prev {
+			t.Errorf("%s is out of order after %s", k.ID, prev)
+		}
+		prev = k.ID
+		if k.Category == "" || k.Summary == "" {
+			t.Errorf("%s: missing category or summary", k.ID)
+		}
+		if got, ok := LookupWarning(strings.ToLower(string(k.ID))); !ok || got != k {
+			t.Errorf("LookupWarning(%s) = %+v, %v", k.ID, got, ok)
+		}
+		if got, ok := LookupWarning(k.Name); !ok || got != k {
+			t.Errorf("LookupWarning(%s) = %+v, %v", k.Name, got, ok)
+		}
+	}
+}
+
+// TestWarnings_UseRegistry checks the source tree: every WarningID constant
+// is registered, every NewWarning call names one of those constants, and no
+// code builds a Warning literal instead.
+func TestWarnings_UseRegistry(t *testing.T) {
+	fset := token.NewFileSet()
+	consts := map[string]bool{}
+	f, err := parser.ParseFile(fset, "warnings.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse warnings.go: %v", err)
+	}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if typ, ok := vs.Type.(*ast.Ident); !ok || typ.Name != "WarningID" {
+				continue
+			}
+			for i, name := range vs.Names {
+				id, _ := strconv.Unquote(vs.Values[i].(*ast.BasicLit).Value)
+				if _, ok := LookupWarning(id); !ok {
+					t.Errorf("%s (%s) is not registered", name.Name, id)
+				}
+				consts[name.Name] = true
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	err = filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") || strings.Contains(path, "testdata") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if exprName(n.Fun) != "NewWarning" || len(n.Args) == 0 {
+					return true
+				}
+				id := exprName(n.Args[0])
+				if !consts[id] {
+					t.Errorf("%s: NewWarning with an unregistered ID", fset.Position(n.Pos()))
+				}
+				used[id] = true
+			case *ast.CompositeLit:
+				if exprName(n.Type) == "Warning" && filepath.Base(path) != "warnings.go" {
+					t.Errorf("%s: Warning literal bypasses NewWarning", fset.Position(n.Pos()))
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	for c := range consts {
+		if !used[c] {
+			t.Errorf("%s is registered but never emitted", c)
+		}
+	}
+}
+
+// exprName returns the identifier an expression refers to, without any
+// package qualifier.
+func exprName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,318 @@
+// Package specdiff compares two ServiceModels and reports endpoint, parameter,
+// response, and schema changes, flagging the ones that break existing clients.
+package specdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// Kind says whether an element was added, removed, or changed.
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Target names the kind of element a Change refers to.
+type Target string
+
+const (
+	TargetEndpoint  Target = "endpoint"
+	TargetParameter Target = "parameter"
+	TargetResponse  Target = "response"
+	TargetSchema    Target = "schema"
+	TargetProperty  Target = "property"
+)
+
+// Change is a single difference between the old and new model.
+type Change struct {
+	Kind     Kind   `json:"kind"`
+	Target   Target `json:"target"`
+	Endpoint string `json:"endpoint,omitempty"` // "GET /pets" for endpoint, parameter, and response changes
+	Schema   string `json:"schema,omitempty"`   // schema name for schema and property changes
+	Name     string `json:"name,omitempty"`     // parameter "in:name", response status, or property name
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// Report lists all changes in a deterministic order.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Compare diffs oldSM against newSM. Removals, type changes, and parameters
+// becoming required are breaking; additions are not.
+func Compare(oldSM, newSM *genspec.ServiceModel) *Report {
+	if oldSM == nil {
+		oldSM = &genspec.ServiceModel{}
+	}
+	if newSM == nil {
+		newSM = &genspec.ServiceModel{}
+	}
+	r := &Report{Changes: []Change{}}
+	r.compareEndpoints(oldSM, newSM)
+	r.compareSchemas(oldSM.Schemas, newSM.Schemas)
+	return r
+}
+
+// HasBreaking reports whether any change is breaking.
+func (r *Report) HasBreaking() bool {
+	return r.BreakingCount() > 0
+}
+
+// BreakingCount returns the number of breaking changes.
+func (r *Report) BreakingCount() int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes one line per change followed by a summary.
+func (r *Report) WriteText(w io.Writer) error {
+	if len(r.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "No differences found.")
+		return err
+	}
+	for _, c := range r.Changes {
+		line := "  " + kindSymbol(c.Kind) + " " + c.Describe()
+		if c.Breaking {
+			line += " [breaking]"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d change(s), %d breaking\n", len(r.Changes), r.BreakingCount())
+	return err
+}
+
+// Describe renders the change as a short human-readable sentence.
+func (c Change) Describe() string {
+	var subject string
+	switch c.Target {
+	case TargetEndpoint:
+		subject = "endpoint " + c.Endpoint
+	case TargetParameter:
+		subject = c.Endpoint + " parameter " + c.Name
+	case TargetResponse:
+		subject = c.Endpoint + " response " + c.Name
+	case TargetSchema:
+		subject = "schema " + c.Schema
+	case TargetProperty:
+		subject = "schema " + c.Schema + " property " + c.Name
+	}
+	switch c.Kind {
+	case Changed:
+		return fmt.Sprintf("%s changed: %s -> %s", subject, c.Old, c.New)
+	default:
+		return subject + " " + string(c.Kind)
+	}
+}
+
+func kindSymbol(k Kind) string {
+	switch k {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func endpointKey(ep *genspec.EndpointModel) string {
+	return strings.ToUpper(string(ep.Method)) + " " + ep.Path
+}
+
+func (r *Report) compareEndpoints(oldSM, newSM *genspec.ServiceModel) {
+	oldEps := indexEndpoints(oldSM.Endpoints)
+	newEps := indexEndpoints(newSM.Endpoints)
+	for _, key := range unionKeys(oldEps, newEps) {
+		o, inOld := oldEps[key]
+		n, inNew := newEps[key]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Target: TargetEndpoint, Endpoint: key, Breaking: true})
+		case !inOld:
+			r.add(Change{Kind: Added, Target: TargetEndpoint, Endpoint: key})
+		default:
+			r.compareParameters(key, o.Parameters, n.Parameters)
+			r.compareResponses(key, o.Responses, n.Responses)
+		}
+	}
+}
+
+func (r *Report) compareParameters(endpoint string, oldParams, newParams []genspec.ParameterModel) {
+	index := func(params []genspec.ParameterModel) map[string]genspec.ParameterModel {
+		out := make(map[string]genspec.ParameterModel, len(params))
+		for _, p := range params {
+			out[p.In+":"+p.Name] = p
+		}
+		return out
+	}
+	oldIdx, newIdx := index(oldParams), index(newParams)
+	for _, key := range unionKeys(oldIdx, newIdx) {
+		o, inOld := oldIdx[key]
+		n, inNew := newIdx[key]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Target: TargetParameter, Endpoint: endpoint, Name: key, Breaking: true})
+		case !inOld:
+			// A new required parameter breaks callers that don't send it.
+			r.add(Change{Kind: Added, Target: TargetParameter, Endpoint: endpoint, Name: key, New: requiredLabel(n.Required), Breaking: n.Required})
+		default:
+			if ot, nt := TypeString(o.Schema), TypeString(n.Schema); ot != nt {
+				r.add(Change{Kind: Changed, Target: TargetParameter, Endpoint: endpoint, Name: key, Old: ot, New: nt, Breaking: true})
+			}
+			if o.Required != n.Required {
+				r.add(Change{Kind: Changed, Target: TargetParameter, Endpoint: endpoint, Name: key, Old: requiredLabel(o.Required), New: requiredLabel(n.Required), Breaking: n.Required})
+			}
+		}
+	}
+}
+
+func (r *Report) compareResponses(endpoint string, oldResps, newResps []genspec.ResponseModel) {
+	index := func(resps []genspec.ResponseModel) map[string]bool {
+		out := make(map[string]bool, len(resps))
+		for _, resp := range resps {
+			out[resp.Status] = true
+		}
+		return out
+	}
+	oldIdx, newIdx := index(oldResps), index(newResps)
+	for _, status := range unionKeys(oldIdx, newIdx) {
+		switch {
+		case !newIdx[status]:
+			r.add(Change{Kind: Removed, Target: TargetResponse, Endpoint: endpoint, Name: status, Breaking: true})
+		case !oldIdx[status]:
+			r.add(Change{Kind: Added, Target: TargetResponse, Endpoint: endpoint, Name: status})
+		}
+	}
+}
+
+func (r *Report) compareSchemas(oldSchemas, newSchemas map[string]genspec.Schema) {
+	for _, name := range unionKeys(oldSchemas, newSchemas) {
+		o, inOld := oldSchemas[name]
+		n, inNew := newSchemas[name]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Target: TargetSchema, Schema: name, Breaking: true})
+		case !inOld:
+			r.add(Change{Kind: Added, Target: TargetSchema, Schema: name})
+		default:
+			if ot, nt := TypeString(&genspec.SchemaOrRef{Schema: &o}), TypeString(&genspec.SchemaOrRef{Schema: &n}); ot != nt {
+				r.add(Change{Kind: Changed, Target: TargetSchema, Schema: name, Old: ot, New: nt, Breaking: true})
+			}
+			r.compareProperties(name, o.Properties, n.Properties)
+		}
+	}
+}
+
+func (r *Report) compareProperties(schema string, oldProps, newProps map[string]*genspec.SchemaOrRef) {
+	for _, prop := range unionKeys(oldProps, newProps) {
+		o, inOld := oldProps[prop]
+		n, inNew := newProps[prop]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Target: TargetProperty, Schema: schema, Name: prop, Breaking: true})
+		case !inOld:
+			r.add(Change{Kind: Added, Target: TargetProperty, Schema: schema, Name: prop, New: TypeString(n)})
+		default:
+			if ot, nt := TypeString(o), TypeString(n); ot != nt {
+				r.add(Change{Kind: Changed, Target: TargetProperty, Schema: schema, Name: prop, Old: ot, New: nt, Breaking: true})
+			}
+		}
+	}
+}
+
+func (r *Report) add(c Change) {
+	r.Changes = append(r.Changes, c)
+}
+
+// TypeString summarizes a schema's shape for comparison: a referenced schema
+// name, "array<item>", or the type with its format, e.g. "integer(int64)".
+func TypeString(sor *genspec.SchemaOrRef) string {
+	if sor == nil {
+		return "any"
+	}
+	if sor.Ref != nil {
+		return sor.Ref.Ref[strings.LastIndex(sor.Ref.Ref, "/")+1:]
+	}
+	sc := sor.Schema
+	if sc == nil {
+		return "any"
+	}
+	t := sc.Type
+	if t == "" {
+		switch {
+		case len(sc.AllOf) > 0:
+			t = "allOf"
+		case len(sc.OneOf) > 0:
+			t = "oneOf"
+		case len(sc.AnyOf) > 0:
+			t = "anyOf"
+		case len(sc.Properties) > 0:
+			t = "object"
+		default:
+			t = "any"
+		}
+	}
+	if t == "array" {
+		return "array<" + TypeString(sc.Items) + ">"
+	}
+	if sc.Format != "" {
+		t += "(" + sc.Format + ")"
+	}
+	return t
+}
+
+func requiredLabel(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func indexEndpoints(eps []genspec.EndpointModel) map[string]*genspec.EndpointModel {
+	out := make(map[string]*genspec.EndpointModel, len(eps))
+	for i := range eps {
+		out[endpointKey(&eps[i])] = &eps[i]
+	}
+	return out
+}
+
+// unionKeys returns the sorted union of the keys of a and b.
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		seen[k] = struct{}{}
+	}
+	for k := range b {
+		seen[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	genspec.SortNames(keys)
+	return keys
+}
@@ -0,0 +1,234 @@
+package specdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+func typed(t, format string) *genspec.SchemaOrRef {
+	return &genspec.SchemaOrRef{Schema: &genspec.Schema{Type: t, Format: format}}
+}
+
+func ref(name string) *genspec.SchemaOrRef {
+	return &genspec.SchemaOrRef{Ref: &genspec.SchemaRef{Ref: "#/components/schemas/" + name}}
+}
+
+func baseModel() *genspec.ServiceModel {
+	return &genspec.ServiceModel{
+		Endpoints: []genspec.EndpointModel{
+			{
+				Method: genspec.GET,
+				Path:   "/pets",
+				Parameters: []genspec.ParameterModel{
+					{Name: "limit", In: "query", Schema: typed("integer", "int32")},
+					{Name: "sort", In: "query", Schema: typed("string", "")},
+				},
+				Responses: []genspec.ResponseModel{{Status: "200"}, {Status: "404"}},
+			},
+			{Method: genspec.DELETE, Path: "/pets/{id}"},
+		},
+		Schemas: map[string]genspec.Schema{
+			"Pet": {Name: "Pet", Type: "object", Properties: map[string]*genspec.SchemaOrRef{
+				"id":    typed("integer", "int64"),
+				"name":  typed("string", ""),
+				"owner": ref("Owner"),
+			}},
+			"Owner": {Name: "Owner", Type: "object"},
+		},
+	}
+}
+
+func find(r *Report, target Target, kind Kind, name string) *Change {
+	for i := range r.Changes {
+		c := &r.Changes[i]
+		if c.Target == target && c.Kind == kind && (c.Name == name || c.Endpoint == name || (c.Schema == name && c.Name == "")) {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestCompare_Identical(t *testing.T) {
+	r := Compare(baseModel(), baseModel())
+	if len(r.Changes) != 0 || r.HasBreaking() {
+		t.Fatalf("expected no changes, got %+v", r.Changes)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("write text: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No differences") {
+		t.Fatalf("unexpected text: %q", buf.String())
+	}
+}
+
+func TestCompare_Endpoints(t *testing.T) {
+	newSM := baseModel()
+	newSM.Endpoints = append(newSM.Endpoints[:1], genspec.EndpointModel{Method: genspec.POST, Path: "/pets"})
+	r := Compare(baseModel(), newSM)
+
+	if c := find(r, TargetEndpoint, Removed, "DELETE /pets/{id}"); c == nil || !c.Breaking {
+		t.Fatalf("expected breaking endpoint removal, got %+v", r.Changes)
+	}
+	if c := find(r, TargetEndpoint, Added, "POST /pets"); c == nil || c.Breaking {
+		t.Fatalf("expected non-breaking endpoint addition, got %+v", r.Changes)
+	}
+	if len(r.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", r.Changes)
+	}
+}
+
+func TestCompare_Parameters(t *testing.T) {
+	newSM := baseModel()
+	ep := &newSM.Endpoints[0]
+	ep.Parameters = []genspec.ParameterModel{
+		{Name: "limit", In: "query", Schema: typed("string", "")},
+		{Name: "cursor", In: "query", Required: true, Schema: typed("string", "")},
+		{Name: "verbose", In: "query", Schema: typed("boolean", "")},
+	}
+	r := Compare(baseModel(), newSM)
+
+	if c := find(r, TargetParameter, Changed, "query:limit"); c == nil || !c.Breaking || c.Old != "integer(int32)" || c.New != "string" {
+		t.Fatalf("expected breaking type change, got %+v", r.Changes)
+	}
+	if c := find(r, TargetParameter, Removed, "query:sort"); c == nil || !c.Breaking {
+		t.Fatalf("expected breaking parameter removal, got %+v", r.Changes)
+	}
+	if c := find(r, TargetParameter, Added, "query:cursor"); c == nil || !c.Breaking {
+		t.Fatalf("expected new required parameter to be breaking, got %+v", r.Changes)
+	}
+	if c := find(r, TargetParameter, Added, "query:verbose"); c == nil || c.Breaking {
+		t.Fatalf("expected new optional parameter to be non-breaking, got %+v", r.Changes)
+	}
+}
+
+func TestCompare_ParameterRequiredFlip(t *testing.T) {
+	newSM := baseModel()
+	newSM.Endpoints[0].Parameters[1].Required = true
+	r := Compare(baseModel(), newSM)
+	c := find(r, TargetParameter, Changed, "query:sort")
+	if c == nil || !c.Breaking || c.Old != "optional" || c.New != "required" {
+		t.Fatalf("expected optional->required change, got %+v", r.Changes)
+	}
+
+	// The reverse direction relaxes the contract.
+	r = Compare(newSM, baseModel())
+	if c := find(r, TargetParameter, Changed, "query:sort"); c == nil || c.Breaking {
+		t.Fatalf("expected non-breaking required->optional change, got %+v", r.Changes)
+	}
+}
+
+func TestCompare_Responses(t *testing.T) {
+	newSM := baseModel()
+	newSM.Endpoints[0].Responses = []genspec.ResponseModel{{Status: "200"}, {Status: "429"}}
+	r := Compare(baseModel(), newSM)
+	if c := find(r, TargetResponse, Removed, "404"); c == nil || !c.Breaking {
+		t.Fatalf("expected breaking response removal, got %+v", r.Changes)
+	}
+	if c := find(r, TargetResponse, Added, "429"); c == nil || c.Breaking {
+		t.Fatalf("expected response addition, got %+v", r.Changes)
+	}
+}
+
+func TestCompare_Schemas(t *testing.T) {
+	newSM := baseModel()
+	delete(newSM.Schemas, "Owner")
+	newSM.Schemas["Tag"] = genspec.Schema{Name: "Tag", Type: "string"}
+	newSM.Schemas["Pet"] = genspec.Schema{Name: "Pet", Type: "object", Properties: map[string]*genspec.SchemaOrRef{
+		"id":   typed("string", "uuid"),
+		"name": typed("string", ""),
+		"tags": {Schema: &genspec.Schema{Type: "array", Items: ref("Tag")}},
+	}}
+	r := Compare(baseModel(), newSM)
+
+	if c := find(r, TargetSchema, Removed, "Owner"); c == nil || !c.Breaking {
+		t.Fatalf("expected breaking schema removal, got %+v", r.Changes)
+	}
+	if c := find(r, TargetSchema, Added, "Tag"); c == nil || c.Breaking {
+		t.Fatalf("expected schema addition, got %+v", r.Changes)
+	}
+	if c := find(r, TargetProperty, Changed, "id"); c == nil || !c.Breaking || c.Old != "integer(int64)" || c.New != "string(uuid)" {
+		t.Fatalf("expected breaking property type change, got %+v", r.Changes)
+	}
+	if c := find(r, TargetProperty, Removed, "owner"); c == nil || !c.Breaking {
+		t.Fatalf("expected breaking property removal, got %+v", r.Changes)
+	}
+	if c := find(r, TargetProperty, Added, "tags"); c == nil || c.Breaking || c.New != "array<Tag>" {
+		t.Fatalf("expected property addition, got %+v", r.Changes)
+	}
+	if find(r, TargetProperty, Changed, "name") != nil {
+		t.Fatalf("unchanged property reported: %+v", r.Changes)
+	}
+}
+
+func TestCompare_SchemaTypeChange(t *testing.T) {
+	newSM := baseModel()
+	newSM.Schemas["Owner"] = genspec.Schema{Name: "Owner", Type: "string"}
+	r := Compare(baseModel(), newSM)
+	if c := find(r, TargetSchema, Changed, "Owner"); c == nil || !c.Breaking || c.Old != "object" || c.New != "string" {
+		t.Fatalf("expected schema type change, got %+v", r.Changes)
+	}
+}
+
+func TestCompare_NilModels(t *testing.T) {
+	r := Compare(nil, baseModel())
+	if r.HasBreaking() || len(r.Changes) != 4 {
+		t.Fatalf("expected 4 additions, got %+v", r.Changes)
+	}
+	if r = Compare(baseModel(), nil); r.BreakingCount() != 4 {
+		t.Fatalf("expected 4 breaking removals, got %+v", r.Changes)
+	}
+}
+
+func TestReport_Output(t *testing.T) {
+	newSM := baseModel()
+	newSM.Endpoints = newSM.Endpoints[:1]
+	newSM.Schemas["Tag"] = genspec.Schema{Name: "Tag", Type: "string"}
+	r := Compare(baseModel(), newSM)
+
+	var text bytes.Buffer
+	if err := r.WriteText(&text); err != nil {
+		t.Fatalf("write text: %v", err)
+	}
+	want := "  - endpoint DELETE /pets/{id} removed [breaking]\n" +
+		"  + schema Tag added\n" +
+		"2 change(s), 1 breaking\n"
+	if text.String() != want {
+		t.Fatalf("text output:\n%s\nwant:\n%s", text.String(), want)
+	}
+
+	var js bytes.Buffer
+	if err := r.WriteJSON(&js); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if len(decoded.Changes) != 2 || decoded.Changes[0].Kind != Removed || !decoded.Changes[0].Breaking {
+		t.Fatalf("unexpected decoded report: %+v", decoded)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	cases := []struct {
+		in   *genspec.SchemaOrRef
+		want string
+	}{
+		{nil, "any"},
+		{ref("Pet"), "Pet"},
+		{typed("integer", "int64"), "integer(int64)"},
+		{&genspec.SchemaOrRef{Schema: &genspec.Schema{Type: "array", Items: typed("string", "")}}, "array<string>"},
+		{&genspec.SchemaOrRef{Schema: &genspec.Schema{OneOf: []*genspec.SchemaOrRef{ref("A")}}}, "oneOf"},
+		{&genspec.SchemaOrRef{Schema: &genspec.Schema{}}, "any"},
+	}
+	for _, tc := range cases {
+		if got := TypeString(tc.in); got != tc.want {
+			t.Errorf("TypeString: want %q got %q", tc.want, got)
+		}
+	}
+}
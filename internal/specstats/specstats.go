@@ -0,0 +1,196 @@
+// Package specstats computes complexity metrics for a ServiceModel and renders
+// them as one flat JSON object, so weekly runs can be appended to a time series
+// without reshaping. Every value is a string or a number; there is no nesting.
+//
+// Keys:
+//
+//	title, version                  info.title and info.version
+//	endpoints                       number of operations
+//	endpoints_by_method.<method>    operations per HTTP method; all eight methods are always present
+//	endpoints_by_tag.<tag>          operations per tag in use; an operation counts once for each of its tags
+//	endpoints_untagged              operations without tags
+//	schemas                         named schemas, not counting stubs left by schema filters
+//	parameters                      parameters across all operations
+//	parameters_per_endpoint         parameters / endpoints, rounded to two decimals
+//	deprecated                      operations marked deprecated
+//	deprecated_pct                  share of deprecated operations
+//	responses_<class>_pct           share of operations declaring a 2xx, 4xx, 5xx or default response
+//	described_endpoints_pct         share of operations with a summary or description
+//	described_parameters_pct        share of parameters with a description
+//	described_schemas_pct           share of schemas with a description
+//
+// Percentages range from 0 to 100, are rounded to one decimal, and are 0 when
+// there is nothing to measure.
+package specstats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+// ResponseClasses are the response groups measured by responses_<class>_pct.
+var ResponseClasses = []string{"2xx", "4xx", "5xx", "default"}
+
+var methods = []genspec.HttpMethod{
+	genspec.GET, genspec.POST, genspec.PUT, genspec.DELETE,
+	genspec.PATCH, genspec.HEAD, genspec.OPTIONS, genspec.TRACE,
+}
+
+// Stats holds the raw counts behind the flat metrics.
+type Stats struct {
+	Title               string
+	Version             string
+	Endpoints           int
+	ByMethod            map[genspec.HttpMethod]int
+	ByTag               map[string]int
+	Untagged            int
+	Schemas             int
+	DescribedSchemas    int
+	Parameters          int
+	DescribedParameters int
+	DescribedEndpoints  int
+	Deprecated          int
+	// Responses counts operations declaring at least one response of each class.
+	Responses map[string]int
+}
+
+// Analyze counts the endpoints, parameters, responses, and schemas of sm.
+func Analyze(sm *genspec.ServiceModel) *Stats {
+	s := &Stats{
+		ByMethod:  map[genspec.HttpMethod]int{},
+		ByTag:     map[string]int{},
+		Responses: map[string]int{},
+	}
+	if sm == nil {
+		return s
+	}
+	s.Title, s.Version = sm.Title, sm.Version
+	for _, ep := range sm.Endpoints {
+		s.Endpoints++
+		s.ByMethod[ep.Method]++
+		if len(ep.Tags) == 0 {
+			s.Untagged++
+		}
+		for _, tag := range ep.Tags {
+			s.ByTag[tag]++
+		}
+		if ep.Deprecated {
+			s.Deprecated++
+		}
+		if strings.TrimSpace(ep.Summary) != "" || strings.TrimSpace(ep.Description) != "" {
+			s.DescribedEndpoints++
+		}
+		for _, p := range ep.Parameters {
+			s.Parameters++
+			if strings.TrimSpace(p.Description) != "" {
+				s.DescribedParameters++
+			}
+		}
+		seen := map[string]bool{}
+		for _, r := range ep.Responses {
+			if class := responseClass(r.Status); class != "" && !seen[class] {
+				seen[class] = true
+				s.Responses[class]++
+			}
+		}
+	}
+	for _, sc := range sm.Schemas {
+		if sc.Excluded {
+			continue
+		}
+		s.Schemas++
+		if strings.TrimSpace(sc.Description) != "" {
+			s.DescribedSchemas++
+		}
+	}
+	return s
+}
+
+// responseClass maps "201", "4XX" or "default" to its class, or "" when the
+// status is not one of ResponseClasses.
+func responseClass(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "default" {
+		return status
+	}
+	if len(status) != 3 {
+		return ""
+	}
+	class := status[:1] + "xx"
+	for _, c := range ResponseClasses {
+		if c == class {
+			return class
+		}
+	}
+	return ""
+}
+
+// Flat returns the metrics keyed as documented in the package comment.
+func (s *Stats) Flat() map[string]any {
+	out := map[string]any{
+		"title":                    s.Title,
+		"version":                  s.Version,
+		"endpoints":                s.Endpoints,
+		"endpoints_untagged":       s.Untagged,
+		"schemas":                  s.Schemas,
+		"parameters":               s.Parameters,
+		"parameters_per_endpoint":  ratio(s.Parameters, s.Endpoints, 2),
+		"deprecated":               s.Deprecated,
+		"deprecated_pct":           pct(s.Deprecated, s.Endpoints),
+		"described_endpoints_pct":  pct(s.DescribedEndpoints, s.Endpoints),
+		"described_parameters_pct": pct(s.DescribedParameters, s.Parameters),
+		"described_schemas_pct":    pct(s.DescribedSchemas, s.Schemas),
+	}
+	for _, m := range methods {
+		out["endpoints_by_method."+string(m)] = s.ByMethod[m]
+	}
+	for tag, n := range s.ByTag {
+		out["endpoints_by_tag."+tag] = n
+	}
+	for _, class := range ResponseClasses {
+		out["responses_"+class+"_pct"] = pct(s.Responses[class], s.Endpoints)
+	}
+	return out
+}
+
+// WriteJSON writes Flat as a single JSON object with keys in sorted order.
+func (s *Stats) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s.Flat())
+}
+
+// WriteText writes the same metrics as WriteJSON as an aligned two-column table.
+func (s *Stats) WriteText(w io.Writer) error {
+	flat := s.Flat()
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METRIC\tVALUE")
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%v\n", k, flat[k])
+	}
+	return tw.Flush()
+}
+
+func pct(n, total int) float64 {
+	return ratio(100*n, total, 1)
+}
+
+func ratio(n, total, decimals int) float64 {
+	if total == 0 {
+		return 0
+	}
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(float64(n)/float64(total)*scale) / scale
+}
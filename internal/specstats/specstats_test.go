@@ -0,0 +1,72 @@
+package specstats
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	genspec "github.com/mark3labs/swagger2mcp/internal/spec"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func loadModel(t *testing.T, name string, opts ...genspec.BuildOption) *genspec.ServiceModel {
+	t.Helper()
+	ctx := context.Background()
+	doc, err := genspec.Load(ctx, filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	sm, err := genspec.BuildServiceModel(ctx, doc, nil, opts...)
+	if err != nil {
+		t.Fatalf("build %s: %v", name, err)
+	}
+	return sm
+}
+
+func TestWriteJSON_Golden(t *testing.T) {
+	var got bytes.Buffer
+	if err := Analyze(loadModel(t, "complex.yaml")).WriteJSON(&got); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	golden := filepath.Join("testdata", "complex.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("update golden: %v", err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	if got.String() != string(want) {
+		t.Fatalf("stats json differs from %s (run with -update to accept):\n%s", golden, got.String())
+	}
+}
+
+func TestAnalyze_FilteredSubset(t *testing.T) {
+	s := Analyze(loadModel(t, "complex.yaml", genspec.WithIncludeTags([]string{"store"})))
+	if s.Endpoints != 2 || s.ByTag["store"] != 2 || s.ByTag["users"] != 0 || s.Deprecated != 1 {
+		t.Fatalf("unexpected filtered stats: %+v", s)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var out bytes.Buffer
+	if err := Analyze(&genspec.ServiceModel{Title: "Empty"}).WriteText(&out); err != nil {
+		t.Fatalf("write text: %v", err)
+	}
+	rows := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if f := strings.Fields(line); len(f) == 2 {
+			rows[f[0]] = f[1]
+		}
+	}
+	if rows["METRIC"] != "VALUE" || rows["title"] != "Empty" || rows["deprecated_pct"] != "0" || rows["endpoints_by_method.trace"] != "0" {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+}